@@ -0,0 +1,44 @@
+package application
+
+import (
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// semanticSample folds the points of one telemetry batch that map to the same
+// semantic, following the aggregation mode of the first mapping folded in.
+type semanticSample struct {
+	mode  string
+	value float64
+	sum   float64
+	count int
+	at    time.Time
+}
+
+// add folds value, sampled at at, into the sample.
+func (s *semanticSample) add(mode string, value float64, at time.Time) {
+	if s.count == 0 {
+		s.mode = mode
+	}
+	latest := s.count == 0 || !at.Before(s.at)
+	switch s.mode {
+	case masterdata.AggregationLast:
+		if latest {
+			s.value = value
+		}
+	case masterdata.AggregationMax:
+		if s.count == 0 || value > s.value {
+			s.value = value
+		}
+	case masterdata.AggregationAvg:
+		s.sum += value
+		s.value = s.sum / float64(s.count+1)
+	default:
+		s.value += value
+	}
+	if latest {
+		s.at = at
+	}
+	s.count++
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+func TestSemanticSample_FoldsByAggregationMode(t *testing.T) {
+	base := time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)
+	// Two BMS points report SOC in one batch, out of order: the newest reading
+	// is 62, and summing them (the old behaviour) would report 122%.
+	points := []struct {
+		value float64
+		at    time.Time
+	}{
+		{value: 62, at: base.Add(2 * time.Second)},
+		{value: 60, at: base},
+		{value: 61, at: base.Add(time.Second)},
+	}
+	cases := []struct {
+		mode string
+		want float64
+	}{
+		{mode: masterdata.AggregationLast, want: 62},
+		{mode: masterdata.AggregationSum, want: 183},
+		{mode: masterdata.AggregationMax, want: 62},
+		{mode: masterdata.AggregationAvg, want: 61},
+		{mode: masterdata.PointMapping{}.AggregationMode(), want: 183},
+	}
+	for _, tc := range cases {
+		var sample semanticSample
+		for _, point := range points {
+			sample.add(tc.mode, point.value, point.at)
+		}
+		if sample.value != tc.want {
+			t.Fatalf("%s: value = %v, want %v", tc.mode, sample.value, tc.want)
+		}
+		if !sample.at.Equal(base.Add(2 * time.Second)) {
+			t.Fatalf("%s: at = %s, want latest point", tc.mode, sample.at)
+		}
+	}
+}
+
+func TestPointMappingValidate_RejectsUnknownAggregation(t *testing.T) {
+	mapping := masterdata.PointMapping{ID: "m1", StationID: "s1", PointKey: "soc", Semantic: "soc", Unit: "%", Aggregation: "median"}
+	if err := mapping.Validate(); err == nil {
+		t.Fatal("expected error for unknown aggregation")
+	}
+	mapping.Aggregation = masterdata.AggregationLast
+	if err := mapping.Validate(); err != nil {
+		t.Fatalf("last rejected: %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package application
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EventFlapping is emitted when a flapping alarm is held active instead of
+// being cleared.
+const EventFlapping = "flapping"
+
+// WithFlapDetection counts trigger/clear cycles per rule and originator. An
+// alarm that clears threshold times within window is flapping and is counted
+// in platform_alarm_flaps_total. With suppress, a flapping alarm is held
+// active instead of clearing again, and only clears once its value has stayed
+// on the clear side for a full window. A zero window or threshold disables
+// detection.
+func WithFlapDetection(window time.Duration, threshold int, suppress bool) ServiceOption {
+	return func(s *Service) {
+		if window > 0 && threshold > 0 {
+			s.flaps = newFlapDetector(window, threshold, suppress)
+		}
+	}
+}
+
+type flapEntry struct {
+	clears   []time.Time
+	flapping bool
+	// last is the time of the latest clear, held or not.
+	last time.Time
+	// heldSince is when a held alarm's value first went back to the clear
+	// side; zero while it breaches.
+	heldSince time.Time
+}
+
+// flapDetector tracks recent clears per rule and originator. State is kept in
+// memory, so each replica detects flapping on its own.
+type flapDetector struct {
+	window    time.Duration
+	threshold int
+	suppress  bool
+
+	mu        sync.Mutex
+	entries   map[samplerKey]*flapEntry
+	lastSweep time.Time
+}
+
+func newFlapDetector(window time.Duration, threshold int, suppress bool) *flapDetector {
+	return &flapDetector{window: window, threshold: threshold, suppress: suppress, entries: make(map[samplerKey]*flapEntry)}
+}
+
+// flapDecision is the outcome of an open alarm reaching its clear condition.
+type flapDecision struct {
+	// started is set on the clear that makes the alarm flapping.
+	started bool
+	// hold keeps the alarm active instead of clearing it.
+	hold bool
+	// cycles is the number of clears within the window.
+	cycles int
+}
+
+// clearing records that an open alarm reached its clear condition at at.
+func (fd *flapDetector) clearing(key samplerKey, at time.Time) flapDecision {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	fd.sweep(at)
+	entry, ok := fd.entries[key]
+	if !ok {
+		entry = &flapEntry{}
+		fd.entries[key] = entry
+	}
+	entry.last = at
+
+	if entry.flapping && fd.suppress {
+		if entry.heldSince.IsZero() {
+			entry.heldSince = at
+		}
+		if at.Sub(entry.heldSince) < fd.window {
+			return flapDecision{hold: true, cycles: len(entry.clears)}
+		}
+		// A full window on the clear side: the alarm has settled.
+		delete(fd.entries, key)
+		return flapDecision{}
+	}
+
+	entry.clears = append(pruneBefore(entry.clears, at.Add(-fd.window)), at)
+	cycles := len(entry.clears)
+	if cycles < fd.threshold {
+		entry.flapping = false
+		return flapDecision{cycles: cycles}
+	}
+	if entry.flapping {
+		return flapDecision{cycles: cycles}
+	}
+	entry.flapping = true
+	decision := flapDecision{started: true, cycles: cycles}
+	if fd.suppress {
+		entry.heldSince = at
+		decision.hold = true
+	}
+	return decision
+}
+
+// breaching records that the alarm's value is back on the trigger side, so a
+// held alarm must start its clear window over.
+func (fd *flapDetector) breaching(key samplerKey) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	if entry, ok := fd.entries[key]; ok {
+		entry.heldSince = time.Time{}
+	}
+}
+
+// opened records that a new alarm was created for key. With suppression a
+// flapping key has its alarm held, so a new alarm means the held one was
+// closed some other way and its episode is over.
+func (fd *flapDetector) opened(key samplerKey) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	entry, ok := fd.entries[key]
+	if !ok {
+		return
+	}
+	if fd.suppress && entry.flapping {
+		delete(fd.entries, key)
+		return
+	}
+	entry.heldSince = time.Time{}
+}
+
+// reset forgets key after its alarm was acknowledged or closed outside rule
+// evaluation, so a later alarm starts with a clean history.
+func (fd *flapDetector) reset(key samplerKey) {
+	fd.mu.Lock()
+	defer fd.mu.Unlock()
+	delete(fd.entries, key)
+}
+
+// sweep drops entries whose latest clear is older than the window; they
+// count no cycles any more. Callers hold fd.mu.
+func (fd *flapDetector) sweep(now time.Time) {
+	if now.Sub(fd.lastSweep) < fd.window {
+		return
+	}
+	fd.lastSweep = now
+	cutoff := now.Add(-fd.window)
+	for key, entry := range fd.entries {
+		if !entry.last.After(cutoff) {
+			delete(fd.entries, key)
+		}
+	}
+}
+
+func (fd *flapDetector) note(cycles int) string {
+	return fmt.Sprintf("flapping: %d trigger/clear cycles within %s; held active until the value stays clear for %s", cycles, fd.window, fd.window)
+}
+
+func pruneBefore(times []time.Time, cutoff time.Time) []time.Time {
+	kept := times[:0]
+	for _, t := range times {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,123 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+)
+
+func TestFlapDetector_OscillatingSeries(t *testing.T) {
+	rule := alarms.AlarmRule{ID: "rule-flap", Operator: alarms.OperatorGreater, Threshold: 100, Hysteresis: 5}
+	key := samplerKey{tenantID: "tenant-flap", ruleID: rule.ID, originatorType: alarms.OriginatorDevice, originatorID: "device-flap"}
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	// feed replays a value series one sample per 10s against a single alarm,
+	// as evaluateRule would, and reports the alarm's transitions.
+	feed := func(fd *flapDetector, values []float64) (triggers, clears, flaps, held int) {
+		open := false
+		for i, value := range values {
+			at := start.Add(time.Duration(i) * 10 * time.Second)
+			if !open {
+				if shouldTrigger(rule, value) {
+					fd.opened(key)
+					open = true
+					triggers++
+				}
+				continue
+			}
+			if !shouldClear(rule, value) {
+				fd.breaching(key)
+				continue
+			}
+			decision := fd.clearing(key, at)
+			if decision.started {
+				flaps++
+			}
+			if decision.hold {
+				held++
+				continue
+			}
+			open = false
+			clears++
+		}
+		return triggers, clears, flaps, held
+	}
+
+	// Twenty cycles of 110/90 across the threshold.
+	var oscillating []float64
+	for i := 0; i < 20; i++ {
+		oscillating = append(oscillating, 110, 90)
+	}
+
+	fd := newFlapDetector(5*time.Minute, 3, false)
+	triggers, clears, flaps, held := feed(fd, oscillating)
+	if triggers != 20 || clears != 20 || held != 0 {
+		t.Fatalf("without suppression triggers=%d clears=%d held=%d, want every cycle to pass through", triggers, clears, held)
+	}
+	if flaps != 1 {
+		t.Fatalf("flaps = %d, want one episode for a continuous oscillation", flaps)
+	}
+
+	// With suppression the third clear starts holding the alarm, so the
+	// series yields one sustained alarm instead of twenty.
+	fd = newFlapDetector(5*time.Minute, 3, true)
+	triggers, clears, flaps, held = feed(fd, oscillating)
+	if triggers != 3 || clears != 2 || flaps != 1 || held != 18 {
+		t.Fatalf("with suppression triggers=%d clears=%d flaps=%d held=%d, want 3, 2, 1 and 18", triggers, clears, flaps, held)
+	}
+
+	// The held alarm clears once the value stays clear for a full window.
+	settled := append(append([]float64(nil), oscillating...), make([]float64, 31)...)
+	fd = newFlapDetector(5*time.Minute, 3, true)
+	triggers, clears, _, _ = feed(fd, settled)
+	if triggers != 3 || clears != 3 {
+		t.Fatalf("after settling triggers=%d clears=%d, want the held alarm cleared", triggers, clears)
+	}
+	if len(fd.entries) != 0 {
+		t.Fatalf("settled alarm still tracked: %+v", fd.entries)
+	}
+
+	// Cycles further apart than the window are not flapping.
+	fd = newFlapDetector(time.Minute, 3, true)
+	var slow []float64
+	for i := 0; i < 10; i++ {
+		slow = append(slow, 110, 90, 90, 90, 90)
+	}
+	if _, clears, flaps, _ := feed(fd, slow); clears != 10 || flaps != 0 {
+		t.Fatalf("slow cycles clears=%d flaps=%d, want 10 and 0", clears, flaps)
+	}
+}
+
+func TestFlapDetector_ForgetsClosedAndIdleKeys(t *testing.T) {
+	key := samplerKey{tenantID: "tenant-flap", ruleID: "rule-flap", originatorType: alarms.OriginatorDevice, originatorID: "device-flap"}
+	other := samplerKey{tenantID: "tenant-flap", ruleID: "rule-flap", originatorType: alarms.OriginatorDevice, originatorID: "device-other"}
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	fd := newFlapDetector(5*time.Minute, 2, true)
+	fd.clearing(key, at(10))
+	if decision := fd.clearing(key, at(20)); !decision.started || !decision.hold {
+		t.Fatalf("second clear = %+v, want the alarm held", decision)
+	}
+	// The held alarm went stale and a new alarm opened: its first clear passes.
+	fd.opened(key)
+	if decision := fd.clearing(key, at(30)); decision.hold {
+		t.Fatalf("first clear of a new alarm held: %+v", decision)
+	}
+
+	fd.clearing(key, at(40))
+	fd.reset(key)
+	if decision := fd.clearing(key, at(50)); decision.hold || decision.cycles != 1 {
+		t.Fatalf("clear after reset = %+v, want a fresh history", decision)
+	}
+
+	// Keys without a clear for a window are dropped on the next sweep.
+	fd.clearing(other, at(400))
+	if _, ok := fd.entries[key]; ok {
+		t.Fatalf("idle key still tracked after a window")
+	}
+	if len(fd.entries) != 1 {
+		t.Fatalf("entries = %d, want only the active key", len(fd.entries))
+	}
+}
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/auth"
+)
+
+// Rule test outcomes.
+const (
+	RuleTestTrigger = "trigger"
+	RuleTestClear   = "clear"
+	RuleTestNone    = "none"
+)
+
+// RuleTestResult reports how a rule would react to a sample value. Nothing is persisted.
+type RuleTestResult struct {
+	RuleID          string    `json:"rule_id"`
+	Value           float64   `json:"value"`
+	At              time.Time `json:"at"`
+	WouldTrigger    bool      `json:"would_trigger"`
+	WouldClear      bool      `json:"would_clear"`
+	Outcome         string    `json:"outcome"`
+	DurationSeconds int       `json:"duration_seconds"`
+	Enabled         bool      `json:"enabled"`
+}
+
+// RuleUpdate carries a partial rule edit; nil fields are left unchanged.
+type RuleUpdate struct {
+	Name            *string
+	Operator        *alarms.Operator
+	Threshold       *float64
+	Hysteresis      *float64
+	DurationSeconds *int
+	Severity        *string
+	Enabled         *bool
+	NotifyChannel   *string
+}
+
+// ListRules returns all rules of a station, including disabled ones.
+func (s *Service) ListRules(ctx context.Context, stationID string) ([]alarms.AlarmRule, error) {
+	if s == nil {
+		return nil, errors.New("alarms: nil service")
+	}
+	if stationID == "" {
+		return nil, errors.New("alarms: station id required")
+	}
+	return s.rules.ListByStation(ctx, s.tenantFromContext(ctx), stationID)
+}
+
+// GetRule loads a rule of the caller's tenant.
+func (s *Service) GetRule(ctx context.Context, id string) (*alarms.AlarmRule, error) {
+	if s == nil {
+		return nil, errors.New("alarms: nil service")
+	}
+	if id == "" {
+		return nil, errors.New("alarms: rule id required")
+	}
+	rule, err := s.rules.GetByID(ctx, s.tenantFromContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, alarms.ErrNotFound
+	}
+	return rule, nil
+}
+
+// CreateRule stores a new rule for the caller's tenant. It is picked up by the
+// next telemetry batch of the station.
+func (s *Service) CreateRule(ctx context.Context, rule alarms.AlarmRule) (*alarms.AlarmRule, error) {
+	if s == nil {
+		return nil, errors.New("alarms: nil service")
+	}
+	rule.TenantID = s.tenantFromContext(ctx)
+	if rule.ID == "" {
+		rule.ID = newRuleID()
+	}
+	rule.Severity = strings.ToLower(strings.TrimSpace(rule.Severity))
+	rule.NotifyChannel = strings.TrimSpace(rule.NotifyChannel)
+	if err := s.rules.Create(ctx, &rule); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// UpdateRule applies a partial edit to a rule. Disabling a rule stops new
+// alarms from being raised but leaves alarms it already opened untouched.
+func (s *Service) UpdateRule(ctx context.Context, id string, update RuleUpdate) (*alarms.AlarmRule, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if update.Name != nil {
+		rule.Name = *update.Name
+	}
+	if update.Operator != nil {
+		rule.Operator = *update.Operator
+	}
+	if update.Threshold != nil {
+		rule.Threshold = *update.Threshold
+	}
+	if update.Hysteresis != nil {
+		rule.Hysteresis = *update.Hysteresis
+	}
+	if update.DurationSeconds != nil {
+		rule.DurationSeconds = *update.DurationSeconds
+	}
+	if update.Severity != nil {
+		rule.Severity = strings.ToLower(strings.TrimSpace(*update.Severity))
+	}
+	if update.Enabled != nil {
+		rule.Enabled = *update.Enabled
+	}
+	if update.NotifyChannel != nil {
+		rule.NotifyChannel = strings.TrimSpace(*update.NotifyChannel)
+	}
+	if err := s.rules.Update(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// SetRuleEnabled toggles a rule on or off.
+func (s *Service) SetRuleEnabled(ctx context.Context, id string, enabled bool) (*alarms.AlarmRule, error) {
+	return s.UpdateRule(ctx, id, RuleUpdate{Enabled: &enabled})
+}
+
+// TestRule dry-runs a rule against a sample value using the same predicates as
+// telemetry evaluation. A zero at defaults to now. When the rule has a
+// duration, a trigger only raises an alarm once the condition holds that long.
+func (s *Service) TestRule(ctx context.Context, id string, value float64, at time.Time) (*RuleTestResult, error) {
+	rule, err := s.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	result := previewRule(*rule, value)
+	result.At = atOrNow(at, s.clock)
+	return &result, nil
+}
+
+func previewRule(rule alarms.AlarmRule, value float64) RuleTestResult {
+	result := RuleTestResult{
+		RuleID:          rule.ID,
+		Value:           value,
+		WouldTrigger:    shouldTrigger(rule, value),
+		WouldClear:      shouldClear(rule, value),
+		Outcome:         RuleTestNone,
+		DurationSeconds: rule.DurationSeconds,
+		Enabled:         rule.Enabled,
+	}
+	switch {
+	case result.WouldTrigger:
+		result.Outcome = RuleTestTrigger
+	case result.WouldClear:
+		result.Outcome = RuleTestClear
+	}
+	return result
+}
+
+func (s *Service) tenantFromContext(ctx context.Context) string {
+	if tenantID := auth.TenantIDFromContext(ctx); tenantID != "" {
+		return tenantID
+	}
+	return s.tenantID
+}
+
+func newRuleID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "rule-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,38 @@
+package application
+
+import (
+	"testing"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+)
+
+func TestPreviewRule_Outcomes(t *testing.T) {
+	rule := alarms.AlarmRule{ID: "rule-1", Operator: alarms.OperatorGreater, Threshold: 100, Hysteresis: 5, Enabled: true}
+	cases := []struct {
+		value float64
+		want  string
+	}{
+		{120, RuleTestTrigger},
+		{90, RuleTestClear},
+		{95, RuleTestClear},
+		{98, RuleTestNone},
+		{100, RuleTestNone},
+	}
+	for _, tc := range cases {
+		got := previewRule(rule, tc.value)
+		if got.Outcome != tc.want {
+			t.Fatalf("value=%v outcome=%s want=%s", tc.value, got.Outcome, tc.want)
+		}
+		if got.WouldTrigger && got.WouldClear {
+			t.Fatalf("value=%v both trigger and clear", tc.value)
+		}
+	}
+
+	low := alarms.AlarmRule{ID: "rule-2", Operator: alarms.OperatorLessOrEqual, Threshold: 10, Hysteresis: 2}
+	if got := previewRule(low, 10); got.Outcome != RuleTestTrigger {
+		t.Fatalf("expected <= to trigger at threshold, got %s", got.Outcome)
+	}
+	if got := previewRule(low, 12); got.Outcome != RuleTestClear {
+		t.Fatalf("expected clear above threshold+hysteresis, got %s", got.Outcome)
+	}
+}
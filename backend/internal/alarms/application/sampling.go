@@ -0,0 +1,148 @@
+package application
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+)
+
+// WithEvaluationInterval evaluates each rule at most once per interval per
+// originator. Samples arriving in between are batched and evaluated with the
+// next sample after the interval, or by FlushSampled. Zero evaluates every
+// sample.
+func WithEvaluationInterval(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		if d > 0 {
+			s.sampler = newRuleSampler(d)
+		}
+	}
+}
+
+// ruleSample is the value a rule is evaluated with. since is the time of the
+// earliest triggering sample merged into it, so duration rules count from
+// the first breach rather than from the evaluation.
+type ruleSample struct {
+	value float64
+	at    time.Time
+	since time.Time
+}
+
+type samplerKey struct {
+	tenantID       string
+	ruleID         string
+	originatorType string
+	originatorID   string
+}
+
+type samplerEntry struct {
+	lastEval time.Time
+	pending  bool
+	evt      telemetryevents.TelemetryReceived
+	rule     alarms.AlarmRule
+	sample   ruleSample
+}
+
+// ruleSampler caps how often a rule is evaluated per originator.
+type ruleSampler struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	entries map[samplerKey]*samplerEntry
+}
+
+func newRuleSampler(interval time.Duration) *ruleSampler {
+	return &ruleSampler{interval: interval, entries: make(map[samplerKey]*samplerEntry)}
+}
+
+// offer records a sample and reports whether the rule is due. A due sample
+// carries every sample batched since the previous evaluation.
+func (rs *ruleSampler) offer(key samplerKey, evt telemetryevents.TelemetryReceived, rule alarms.AlarmRule, sample ruleSample, now time.Time) (ruleSample, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	entry, ok := rs.entries[key]
+	if !ok {
+		rs.entries[key] = &samplerEntry{lastEval: now}
+		return sample, true
+	}
+	if entry.pending {
+		sample = mergeSamples(rule, entry.sample, sample)
+	}
+	if now.Sub(entry.lastEval) >= rs.interval {
+		*entry = samplerEntry{lastEval: now}
+		return sample, true
+	}
+	entry.pending = true
+	entry.evt = evt
+	entry.rule = rule
+	entry.sample = sample
+	return ruleSample{}, false
+}
+
+type dueSample struct {
+	key    samplerKey
+	evt    telemetryevents.TelemetryReceived
+	rule   alarms.AlarmRule
+	sample ruleSample
+}
+
+// due takes the batches whose interval has passed without a further sample.
+func (rs *ruleSampler) due(now time.Time) []dueSample {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	var result []dueSample
+	for key, entry := range rs.entries {
+		if now.Sub(entry.lastEval) < rs.interval {
+			continue
+		}
+		if entry.pending {
+			result = append(result, dueSample{key: key, evt: entry.evt, rule: entry.rule, sample: entry.sample})
+		}
+		// Idle entries are dropped; the next sample is evaluated right away.
+		delete(rs.entries, key)
+	}
+	return result
+}
+
+// mergeSamples keeps the value furthest on the triggering side of the rule
+// and the latest timestamp.
+func mergeSamples(rule alarms.AlarmRule, older, newer ruleSample) ruleSample {
+	merged := newer
+	switch rule.Operator {
+	case alarms.OperatorGreater, alarms.OperatorGreaterOrEqual:
+		if older.value > merged.value {
+			merged.value = older.value
+		}
+	case alarms.OperatorLess, alarms.OperatorLessOrEqual:
+		if older.value < merged.value {
+			merged.value = older.value
+		}
+	}
+	if older.at.After(merged.at) {
+		merged.at = older.at
+	}
+	merged.since = older.since
+	if merged.since.IsZero() {
+		merged.since = newer.since
+	}
+	return merged
+}
+
+// FlushSampled evaluates batched samples whose interval has passed without a
+// further sample, so the last samples of a burst are not lost. It returns
+// the number of evaluations.
+func (s *Service) FlushSampled(ctx context.Context) (int, error) {
+	if s == nil || s.sampler == nil {
+		return 0, nil
+	}
+	flushed := 0
+	for _, due := range s.sampler.due(s.clock.Now().UTC()) {
+		if err := s.evaluateRule(ctx, due.evt, due.rule, due.key.originatorType, due.key.originatorID, due.sample); err != nil {
+			return flushed, err
+		}
+		flushed++
+	}
+	return flushed, nil
+}
@@ -0,0 +1,61 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+)
+
+func TestRuleSampler_CapsEvaluationsUnderFlood(t *testing.T) {
+	sampler := newRuleSampler(time.Second)
+	rule := alarms.AlarmRule{ID: "rule-flood", Operator: alarms.OperatorGreater, Threshold: 100}
+	key := samplerKey{tenantID: "tenant-flood", ruleID: rule.ID, originatorType: alarms.OriginatorDevice, originatorID: "device-flood"}
+	evt := telemetryevents.TelemetryReceived{TenantID: "tenant-flood", StationID: "station-flood", DeviceID: "device-flood"}
+
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	evaluations := 0
+	var spikeEvaluated bool
+	// 1000 samples 10ms apart: ten seconds of a 100 Hz device.
+	for i := 0; i < 1000; i++ {
+		now := start.Add(time.Duration(i) * 10 * time.Millisecond)
+		value := 50.0
+		if i == 150 {
+			value = 500
+		}
+		sample := ruleSample{value: value, at: now}
+		if shouldTrigger(rule, value) {
+			sample.since = now
+		}
+		merged, due := sampler.offer(key, evt, rule, sample, now)
+		if !due {
+			continue
+		}
+		evaluations++
+		if merged.value == 500 {
+			spikeEvaluated = true
+			if !merged.since.Equal(start.Add(1500*time.Millisecond)) || !merged.at.Equal(now) {
+				t.Fatalf("spike batch = %+v, want the spike's breach time and the latest timestamp", merged)
+			}
+		}
+	}
+	if evaluations > 10 {
+		t.Fatalf("%d evaluations for ten seconds of samples, want at most one per second", evaluations)
+	}
+	if !spikeEvaluated {
+		t.Fatalf("the batched spike was never evaluated")
+	}
+
+	// The tail of the flood is evaluated once its interval passes.
+	if due := sampler.due(start.Add(9500 * time.Millisecond)); len(due) != 0 {
+		t.Fatalf("due before the interval = %+v", due)
+	}
+	due := sampler.due(start.Add(10 * time.Second))
+	if len(due) != 1 || due[0].sample.value != 50 || due[0].evt.DeviceID != "device-flood" {
+		t.Fatalf("due after the interval = %+v, want the batched tail", due)
+	}
+	if _, first := sampler.offer(key, evt, rule, ruleSample{value: 60, at: start.Add(13 * time.Second)}, start.Add(13*time.Second)); !first {
+		t.Fatalf("first sample after a flush was not evaluated right away")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	alarms "microgrid-cloud/internal/alarms/domain"
 	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
 	"microgrid-cloud/internal/masterdata/domain"
 	"microgrid-cloud/internal/observability/metrics"
 	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
@@ -22,14 +23,19 @@ type AlarmNotifier interface {
 
 // AlarmEvent represents a lifecycle update.
 type AlarmEvent struct {
-	Type  string       `json:"type"`
-	Alarm alarms.Alarm `json:"alarm"`
+	Type     string       `json:"type"`
+	Alarm    alarms.Alarm `json:"alarm"`
+	Severity string       `json:"severity,omitempty"`
+	Note     string       `json:"note,omitempty"`
 }
 
+// EventStale is emitted when an alarm is auto-cleared because its data went stale.
+const EventStale = "stale"
+
+const staleSweepBatch = 500
+
 // Clock provides time.
-type Clock interface {
-	Now() time.Time
-}
+type Clock = clock.Clock
 
 // Service handles alarm evaluation and state transitions.
 type Service struct {
@@ -40,6 +46,12 @@ type Service struct {
 	notifier AlarmNotifier
 	clock    Clock
 	tenantID string
+	stale    time.Duration
+	sampler  *ruleSampler
+	flaps    *flapDetector
+
+	templates *alarmrepo.AlarmRuleTemplateRepository
+	stations  masterdata.StationRepository
 }
 
 // ServiceOption customizes the alarm service.
@@ -59,6 +71,15 @@ func WithClock(clock Clock) ServiceOption {
 	}
 }
 
+// WithStaleAfter enables auto-clearing open alarms that received no sample for d.
+func WithStaleAfter(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		if d > 0 {
+			s.stale = d
+		}
+	}
+}
+
 // NewService constructs an alarm service.
 func NewService(rules *alarmrepo.AlarmRuleRepository, alarmsRepo *alarmrepo.AlarmRepository, states *alarmrepo.AlarmRuleStateRepository, mappings masterdata.PointMappingRepository, tenantID string, opts ...ServiceOption) (*Service, error) {
 	if rules == nil || alarmsRepo == nil || states == nil {
@@ -76,7 +97,7 @@ func NewService(rules *alarmrepo.AlarmRuleRepository, alarmsRepo *alarmrepo.Alar
 		states:   states,
 		mappings: mappings,
 		tenantID: tenantID,
-		clock:    systemClock{},
+		clock:    clock.System{},
 	}
 	for _, opt := range opts {
 		opt(service)
@@ -131,28 +152,23 @@ func (s *Service) HandleTelemetryReceived(ctx context.Context, evt telemetryeven
 		mappingByStation[mapping.PointKey] = mapping
 	}
 
-	type sample struct {
-		value float64
-		at    time.Time
-	}
-	semanticSamples := make(map[string]sample)
+	semanticSamples := make(map[string]*semanticSample)
 
 	for _, point := range evt.Points {
 		mapping, ok := resolveMapping(mappingByDevice, mappingByStation, evt.DeviceID, point.PointKey)
 		if !ok {
 			continue
 		}
-		value := point.Value * mapping.Factor
-		existing := semanticSamples[mapping.Semantic]
 		at := point.TS
 		if at.IsZero() {
 			at = evt.OccurredAt
 		}
-		if existing.at.IsZero() || at.After(existing.at) {
-			existing.at = at
+		existing := semanticSamples[mapping.Semantic]
+		if existing == nil {
+			existing = &semanticSample{}
+			semanticSamples[mapping.Semantic] = existing
 		}
-		existing.value += value
-		semanticSamples[mapping.Semantic] = existing
+		existing.add(mapping.AggregationMode(), point.Value*mapping.Factor, at)
 	}
 
 	originatorType := alarms.OriginatorDevice
@@ -165,7 +181,18 @@ func (s *Service) HandleTelemetryReceived(ctx context.Context, evt telemetryeven
 	for semantic, sample := range semanticSamples {
 		ruleList := rulesBySemantic[semantic]
 		for _, rule := range ruleList {
-			if err := s.evaluateRule(ctx, evt, rule, originatorType, originatorID, sample.value, sample.at); err != nil {
+			current := ruleSample{value: sample.value, at: sample.at}
+			if shouldTrigger(rule, sample.value) {
+				current.since = sample.at
+			}
+			if s.sampler != nil {
+				key := samplerKey{tenantID: evt.TenantID, ruleID: rule.ID, originatorType: originatorType, originatorID: originatorID}
+				var due bool
+				if current, due = s.sampler.offer(key, evt, rule, current, s.clock.Now().UTC()); !due {
+					continue
+				}
+			}
+			if err := s.evaluateRule(ctx, evt, rule, originatorType, originatorID, current); err != nil {
 				return err
 			}
 		}
@@ -206,6 +233,7 @@ func (s *Service) AckAlarm(ctx context.Context, id string) (*alarms.Alarm, error
 		alarm.Status = alarms.StatusAcknowledged
 		alarm.AckedAt = ackedAt
 		alarm.UpdatedAt = ackedAt
+		s.resetFlaps(*alarm)
 		s.notify(ctx, "acknowledged", *alarm)
 	}
 	return alarm, nil
@@ -244,37 +272,88 @@ func (s *Service) ClearAlarm(ctx context.Context, id string) (*alarms.Alarm, err
 	alarm.ClearedAt = clearedAt
 	alarm.EndAt = clearedAt
 	alarm.UpdatedAt = clearedAt
+	s.resetFlaps(*alarm)
 	s.notify(ctx, "cleared", *alarm)
 	return alarm, nil
 }
 
+// SweepStale clears open alarms whose rule semantic has not been sampled within
+// the staleness window, so alarms of offline stations do not stay active forever.
+// It returns the number of alarms cleared.
+func (s *Service) SweepStale(ctx context.Context) (int, error) {
+	if s == nil {
+		return 0, errors.New("alarms: nil service")
+	}
+	if s.stale <= 0 {
+		return 0, nil
+	}
+	now := s.clock.Now().UTC()
+	stale, err := s.alarms.ListStaleOpen(ctx, now.Add(-s.stale), staleSweepBatch)
+	if err != nil {
+		return 0, err
+	}
+	cleared := 0
+	for _, alarm := range stale {
+		if err := s.alarms.MarkCleared(ctx, alarm.ID, alarm.LastValue, now); err != nil {
+			return cleared, err
+		}
+		alarm.Status = alarms.StatusCleared
+		alarm.ClearedAt = now
+		alarm.EndAt = now
+		alarm.UpdatedAt = now
+		s.resetFlaps(alarm)
+		s.notify(ctx, EventStale, alarm)
+		cleared++
+	}
+	return cleared, nil
+}
+
 // ListAlarms returns alarms by station/time/status.
-func (s *Service) ListAlarms(ctx context.Context, stationID, status string, from, to time.Time) ([]alarms.Alarm, error) {
+func (s *Service) ListAlarms(ctx context.Context, stationID, status string, from, to time.Time, limit, offset int) ([]alarms.Alarm, int, error) {
 	if s == nil {
-		return nil, errors.New("alarms: nil service")
+		return nil, 0, errors.New("alarms: nil service")
 	}
 	if stationID == "" {
-		return nil, errors.New("alarms: station id required")
+		return nil, 0, errors.New("alarms: station id required")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
 		tenantID = s.tenantID
 	}
-	return s.alarms.ListByStationStatusAndTime(ctx, tenantID, stationID, status, from.UTC(), to.UTC())
+	return s.alarms.ListByStationStatusAndTime(ctx, tenantID, stationID, status, from.UTC(), to.UTC(), limit, offset)
 }
 
-func (s *Service) evaluateRule(ctx context.Context, evt telemetryevents.TelemetryReceived, rule alarms.AlarmRule, originatorType, originatorID string, value float64, at time.Time) error {
+func (s *Service) evaluateRule(ctx context.Context, evt telemetryevents.TelemetryReceived, rule alarms.AlarmRule, originatorType, originatorID string, sample ruleSample) error {
+	value, at := sample.value, sample.at
 	open, err := s.alarms.FindOpenByRuleOriginator(ctx, evt.TenantID, rule.ID, originatorType, originatorID)
 	if err != nil {
 		return err
 	}
 
+	key := samplerKey{tenantID: evt.TenantID, ruleID: rule.ID, originatorType: originatorType, originatorID: originatorID}
 	if open != nil {
 		if shouldClear(rule, value) {
 			clearedAt := at
 			if clearedAt.IsZero() {
 				clearedAt = s.clock.Now().UTC()
 			}
+			if s.flaps != nil {
+				decision := s.flaps.clearing(key, clearedAt)
+				if decision.started {
+					metrics.IncAlarmFlap()
+				}
+				if decision.hold {
+					if err := s.alarms.UpdateLastValue(ctx, open.ID, value, clearedAt); err != nil {
+						return err
+					}
+					if decision.started {
+						open.LastValue = value
+						open.UpdatedAt = clearedAt
+						s.notifyNote(ctx, EventFlapping, *open, s.flaps.note(decision.cycles))
+					}
+					return nil
+				}
+			}
 			if err := s.alarms.MarkCleared(ctx, open.ID, value, clearedAt); err != nil {
 				return err
 			}
@@ -286,6 +365,9 @@ func (s *Service) evaluateRule(ctx context.Context, evt telemetryevents.Telemetr
 			s.notify(ctx, "cleared", *open)
 			return nil
 		}
+		if s.flaps != nil {
+			s.flaps.breaching(key)
+		}
 		if err := s.alarms.UpdateLastValue(ctx, open.ID, value, atOrNow(at, s.clock)); err != nil {
 			return err
 		}
@@ -309,7 +391,7 @@ func (s *Service) evaluateRule(ctx context.Context, evt telemetryevents.Telemetr
 				RuleID:         rule.ID,
 				OriginatorType: originatorType,
 				OriginatorID:   originatorID,
-				PendingSince:   atOrNow(at, s.clock),
+				PendingSince:   atOrNow(firstBreach(sample), s.clock),
 				LastValue:      value,
 				UpdatedAt:      s.clock.Now().UTC(),
 			}
@@ -354,11 +436,27 @@ func (s *Service) createAlarm(ctx context.Context, evt telemetryevents.Telemetry
 	if err := s.alarms.Create(ctx, alarm); err != nil {
 		return err
 	}
+	if s.flaps != nil {
+		s.flaps.opened(samplerKey{tenantID: alarm.TenantID, ruleID: rule.ID, originatorType: originatorType, originatorID: originatorID})
+	}
 	s.notify(ctx, "active", *alarm)
 	return nil
 }
 
+// resetFlaps forgets the flap history of an alarm closed or acknowledged
+// outside rule evaluation.
+func (s *Service) resetFlaps(alarm alarms.Alarm) {
+	if s.flaps == nil {
+		return
+	}
+	s.flaps.reset(samplerKey{tenantID: alarm.TenantID, ruleID: alarm.RuleID, originatorType: alarm.OriginatorType, originatorID: alarm.OriginatorID})
+}
+
 func (s *Service) notify(ctx context.Context, eventType string, alarm alarms.Alarm) {
+	s.notifyNote(ctx, eventType, alarm, "")
+}
+
+func (s *Service) notifyNote(ctx context.Context, eventType string, alarm alarms.Alarm, note string) {
 	if s == nil {
 		return
 	}
@@ -366,7 +464,19 @@ func (s *Service) notify(ctx context.Context, eventType string, alarm alarms.Ala
 	if s.notifier == nil {
 		return
 	}
-	s.notifier.Notify(ctx, AlarmEvent{Type: eventType, Alarm: alarm})
+	event := AlarmEvent{Type: eventType, Alarm: alarm, Note: note}
+	if rule, err := s.rules.GetByID(ctx, alarm.TenantID, alarm.RuleID); err == nil && rule != nil {
+		event.Severity = rule.Severity
+	}
+	s.notifier.Notify(ctx, event)
+}
+
+// firstBreach is when the sample first met the trigger condition.
+func firstBreach(sample ruleSample) time.Time {
+	if !sample.since.IsZero() {
+		return sample.since
+	}
+	return sample.at
 }
 
 func shouldTrigger(rule alarms.AlarmRule, value float64) bool {
@@ -420,7 +530,3 @@ func atOrNow(value time.Time, clock Clock) time.Time {
 	}
 	return value.UTC()
 }
-
-type systemClock struct{}
-
-func (systemClock) Now() time.Time { return time.Now().UTC() }
@@ -0,0 +1,134 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/auth"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// ErrTemplatesDisabled is returned by the template methods of a service built
+// without WithRuleTemplates.
+var ErrTemplatesDisabled = errors.New("alarms: rule templates not configured")
+
+// WithRuleTemplates enables alarm rule templates. stations resolves the type
+// of a station that templates are applied to by id.
+func WithRuleTemplates(templates *alarmrepo.AlarmRuleTemplateRepository, stations masterdata.StationRepository) ServiceOption {
+	return func(s *Service) {
+		s.templates = templates
+		s.stations = stations
+	}
+}
+
+// CreateTemplate stores a template for the caller's tenant, replacing one with
+// the same id. Stations already instantiated from it are not changed.
+func (s *Service) CreateTemplate(ctx context.Context, template alarms.AlarmRuleTemplate) (*alarms.AlarmRuleTemplate, error) {
+	if s == nil || s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	template.TenantID = s.tenantFromContext(ctx)
+	if template.ID == "" {
+		template.ID = newTemplateID()
+	}
+	template.StationType = strings.TrimSpace(template.StationType)
+	template.Severity = strings.ToLower(strings.TrimSpace(template.Severity))
+	template.NotifyChannel = strings.TrimSpace(template.NotifyChannel)
+	if err := s.templates.Save(ctx, &template); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// ListTemplates returns the caller's templates, of one station type unless
+// stationType is empty.
+func (s *Service) ListTemplates(ctx context.Context, stationType string) ([]alarms.AlarmRuleTemplate, error) {
+	if s == nil || s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	return s.templates.List(ctx, s.tenantFromContext(ctx), stationType)
+}
+
+// ApplyTemplatesToStation instantiates the templates of a station's type onto
+// the station; see ApplyTemplates.
+func (s *Service) ApplyTemplatesToStation(ctx context.Context, stationID string, overrides map[string]float64) ([]alarms.AlarmRule, error) {
+	if s == nil || s.templates == nil || s.stations == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	if stationID == "" {
+		return nil, errors.New("alarms: station id required")
+	}
+	station, err := s.stations.Get(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return nil, alarms.ErrNotFound
+	}
+	tenantID := s.tenantFromContext(ctx)
+	if station.TenantID != tenantID {
+		return nil, auth.ErrTenantMismatch
+	}
+	return s.ApplyTemplates(ctx, tenantID, stationID, station.StationType, overrides)
+}
+
+// ApplyTemplates instantiates every template of stationType onto a station
+// and returns the rules it created. overrides maps template ids to the
+// station's threshold. Rules a previous application created are kept as they
+// are, so applying again only adds rules of templates created since.
+func (s *Service) ApplyTemplates(ctx context.Context, tenantID, stationID, stationType string, overrides map[string]float64) ([]alarms.AlarmRule, error) {
+	if s == nil || s.templates == nil {
+		return nil, ErrTemplatesDisabled
+	}
+	if tenantID == "" || stationID == "" {
+		return nil, errors.New("alarms: tenant and station id required")
+	}
+	if stationType == "" {
+		if len(overrides) > 0 {
+			return nil, errors.New("alarms: station has no type to apply templates of")
+		}
+		return nil, nil
+	}
+	templates, err := s.templates.List(ctx, tenantID, stationType)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(templates))
+	for _, template := range templates {
+		known[template.ID] = true
+	}
+	for id := range overrides {
+		if !known[id] {
+			return nil, fmt.Errorf("alarms: threshold override for unknown template %q of station type %q", id, stationType)
+		}
+	}
+
+	var created []alarms.AlarmRule
+	for _, template := range templates {
+		var threshold *float64
+		if value, ok := overrides[template.ID]; ok {
+			threshold = &value
+		}
+		rule := template.Instantiate(stationID, threshold)
+		inserted, err := s.rules.CreateIfAbsent(ctx, &rule)
+		if err != nil {
+			return created, fmt.Errorf("alarms: apply template %s: %w", template.ID, err)
+		}
+		if inserted {
+			created = append(created, rule)
+		}
+	}
+	return created, nil
+}
+
+func newTemplateID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "tpl-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,199 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/auth"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+func init() {
+	sql.Register("alarm-templates-stub", templateStubDriver{})
+}
+
+func TestApplyTemplatesToStation_InstantiatesTypeTemplates(t *testing.T) {
+	db, err := sql.Open("alarm-templates-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+	resetTemplateStub()
+
+	stations := stationStub{
+		"station-new":   {ID: "station-new", TenantID: "tenant-tpl", StationType: "pv-storage"},
+		"station-other": {ID: "station-other", TenantID: "tenant-other", StationType: "pv-storage"},
+	}
+	service, err := NewService(
+		alarmrepo.NewAlarmRuleRepository(db),
+		alarmrepo.NewAlarmRepository(db),
+		alarmrepo.NewAlarmRuleStateRepository(db),
+		mappingStub{},
+		"tenant-tpl",
+		WithRuleTemplates(alarmrepo.NewAlarmRuleTemplateRepository(db), stations),
+	)
+	if err != nil {
+		t.Fatalf("service: %v", err)
+	}
+	ctx := auth.WithIdentity(context.Background(), "tenant-tpl", auth.RoleOperator, "ops")
+
+	created, err := service.ApplyTemplatesToStation(ctx, "station-new", map[string]float64{"tpl-soc-low": 15})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created = %+v, want 2 rules", created)
+	}
+	soc, temp := created[0], created[1]
+	if soc.ID != templateStubRows[0].RuleID("station-new") || soc.StationID != "station-new" || soc.TenantID != "tenant-tpl" || !soc.Enabled {
+		t.Fatalf("soc rule = %+v", soc)
+	}
+	if soc.Threshold != 15 || temp.Threshold != 55 {
+		t.Fatalf("thresholds = %v, %v; want override 15 and template 55", soc.Threshold, temp.Threshold)
+	}
+	if got := templateStubInserted(); len(got) != 2 || got[0] != soc.ID || got[1] != temp.ID {
+		t.Fatalf("inserted rules = %v", got)
+	}
+
+	// The rules exist now, so applying again creates nothing.
+	again, err := service.ApplyTemplatesToStation(ctx, "station-new", nil)
+	if err != nil || len(again) != 0 {
+		t.Fatalf("second apply = %+v, %v; want no rules", again, err)
+	}
+
+	if _, err := service.ApplyTemplatesToStation(ctx, "station-new", map[string]float64{"tpl-missing": 1}); err == nil {
+		t.Fatalf("expected unknown template override to fail")
+	}
+	if _, err := service.ApplyTemplatesToStation(ctx, "station-other", nil); !errors.Is(err, auth.ErrTenantMismatch) {
+		t.Fatalf("other tenant station err = %v", err)
+	}
+	if _, err := service.ApplyTemplatesToStation(ctx, "station-missing", nil); !errors.Is(err, alarms.ErrNotFound) {
+		t.Fatalf("missing station err = %v", err)
+	}
+}
+
+type stationStub map[string]*masterdata.Station
+
+func (s stationStub) Get(_ context.Context, id string) (*masterdata.Station, error) {
+	return s[id], nil
+}
+
+func (s stationStub) Save(context.Context, *masterdata.Station) error {
+	return errors.New("station stub: read only")
+}
+
+type mappingStub struct{}
+
+func (mappingStub) Save(context.Context, *masterdata.PointMapping) error { return nil }
+
+func (mappingStub) ListByStation(context.Context, string) ([]masterdata.PointMapping, error) {
+	return nil, nil
+}
+
+var templateStubRows = []alarms.AlarmRuleTemplate{
+	{ID: "tpl-soc-low", TenantID: "tenant-tpl", StationType: "pv-storage", Name: "SOC low", Semantic: "soc", Operator: alarms.OperatorLess, Threshold: 10, Severity: "high"},
+	{ID: "tpl-temp-high", TenantID: "tenant-tpl", StationType: "pv-storage", Name: "Battery hot", Semantic: "battery_temp", Operator: alarms.OperatorGreater, Threshold: 55, Hysteresis: 2, Severity: "medium"},
+}
+
+// templateStub records the alarm rule ids inserted so far, so ON CONFLICT DO
+// NOTHING affects no rows for an id inserted before.
+var templateStub struct {
+	sync.Mutex
+	inserted []string
+}
+
+func resetTemplateStub() {
+	templateStub.Lock()
+	defer templateStub.Unlock()
+	templateStub.inserted = nil
+}
+
+func templateStubInserted() []string {
+	templateStub.Lock()
+	defer templateStub.Unlock()
+	return append([]string(nil), templateStub.inserted...)
+}
+
+type templateStubDriver struct{}
+
+func (templateStubDriver) Open(string) (driver.Conn, error) {
+	return templateStubConn{}, nil
+}
+
+type templateStubConn struct{}
+
+func (templateStubConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM alarm_rule_templates") {
+		return nil, errors.New("templates stub: unexpected query")
+	}
+	created := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	rows := &templateStubResult{}
+	for _, tpl := range templateStubRows {
+		if tpl.TenantID != args[0].Value || (args[1].Value != "" && tpl.StationType != args[1].Value) {
+			continue
+		}
+		rows.values = append(rows.values, []driver.Value{
+			tpl.ID, tpl.TenantID, tpl.StationType, tpl.Name, tpl.Semantic, string(tpl.Operator), tpl.Threshold, tpl.Hysteresis,
+			int64(tpl.DurationSeconds), tpl.Severity, tpl.NotifyChannel, created, created,
+		})
+	}
+	return rows, nil
+}
+
+func (templateStubConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch {
+	case strings.Contains(query, "INSERT INTO audit_logs"):
+		return driver.RowsAffected(1), nil
+	case strings.Contains(query, "INSERT INTO alarm_rules"):
+		templateStub.Lock()
+		defer templateStub.Unlock()
+		id := args[0].Value.(string)
+		for _, existing := range templateStub.inserted {
+			if existing == id {
+				return driver.RowsAffected(0), nil
+			}
+		}
+		templateStub.inserted = append(templateStub.inserted, id)
+		return driver.RowsAffected(1), nil
+	}
+	return nil, errors.New("templates stub: unexpected exec")
+}
+
+func (templateStubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("templates stub: prepare not supported")
+}
+
+func (templateStubConn) Close() error { return nil }
+
+func (templateStubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("templates stub: transactions not supported")
+}
+
+type templateStubResult struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *templateStubResult) Columns() []string {
+	return strings.Split("id,tenant_id,station_type,name,semantic,operator,threshold,hysteresis,duration_seconds,severity,notify_channel,created_at,updated_at", ",")
+}
+
+func (r *templateStubResult) Close() error { return nil }
+
+func (r *templateStubResult) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
@@ -25,10 +25,15 @@ type Alarm struct {
 	StartAt        time.Time `json:"start_at"`
 	EndAt          time.Time `json:"end_at,omitempty"`
 	LastValue      float64   `json:"last_value"`
+	MinValue       float64   `json:"min_value"`
+	MaxValue       float64   `json:"max_value"`
+	AvgValue       float64   `json:"avg_value"`
+	SampleCount    int       `json:"sample_count"`
 	AckedAt        time.Time `json:"acked_at,omitempty"`
 	ClearedAt      time.Time `json:"cleared_at,omitempty"`
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
+	LastSampleAt   time.Time `json:"last_sample_at,omitempty"`
 }
 
 // AlarmRuleState tracks pending duration evaluation.
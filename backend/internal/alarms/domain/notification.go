@@ -0,0 +1,25 @@
+package alarms
+
+import "time"
+
+// Notification retry statuses.
+const (
+	RetryStatusPending   = "pending"
+	RetryStatusSent      = "sent"
+	RetryStatusExhausted = "exhausted"
+)
+
+// NotificationRetry is a rendered notification whose delivery failed and is
+// queued for another attempt. Channel names the notifier channel to resend on.
+type NotificationRetry struct {
+	ID            int64
+	AlarmID       string
+	Channel       string
+	Content       string
+	Fields        []byte
+	Attempts      int
+	Status        string
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+}
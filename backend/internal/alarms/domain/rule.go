@@ -2,6 +2,7 @@ package alarms
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -16,19 +17,20 @@ const (
 
 // AlarmRule defines a threshold-based alarm rule.
 type AlarmRule struct {
-	ID              string
-	TenantID        string
-	StationID       string
-	Name            string
-	Semantic        string
-	Operator        Operator
-	Threshold       float64
-	Hysteresis      float64
-	DurationSeconds int
-	Severity        string
-	Enabled         bool
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	StationID       string    `json:"station_id"`
+	Name            string    `json:"name"`
+	Semantic        string    `json:"semantic"`
+	Operator        Operator  `json:"operator"`
+	Threshold       float64   `json:"threshold"`
+	Hysteresis      float64   `json:"hysteresis"`
+	DurationSeconds int       `json:"duration_seconds"`
+	Severity        string    `json:"severity"`
+	Enabled         bool      `json:"enabled"`
+	NotifyChannel   string    `json:"notify_channel,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 // Validate checks rule invariants.
@@ -51,6 +53,15 @@ func (r AlarmRule) Validate() error {
 	if !r.Operator.Valid() {
 		return errors.New("alarm rule: invalid operator")
 	}
+	if r.Severity != "" && SeverityRank(r.Severity) == 0 {
+		return errors.New("alarm rule: invalid severity")
+	}
+	if r.Hysteresis < 0 {
+		return errors.New("alarm rule: negative hysteresis")
+	}
+	if r.DurationSeconds < 0 {
+		return errors.New("alarm rule: negative duration")
+	}
 	return nil
 }
 
@@ -63,3 +74,19 @@ func (o Operator) Valid() bool {
 		return false
 	}
 }
+
+// SeverityRank orders severities from low (1) to critical (4); unknown values rank 0.
+func SeverityRank(value string) int {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "critical":
+		return 4
+	case "high":
+		return 3
+	case "medium":
+		return 2
+	case "low":
+		return 1
+	default:
+		return 0
+	}
+}
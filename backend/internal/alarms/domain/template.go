@@ -0,0 +1,73 @@
+package alarms
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// AlarmRuleTemplate is an alarm rule shared by the stations of one station
+// type. Instantiate copies it onto a station as an ordinary AlarmRule, which
+// can then be edited per station without affecting the template.
+type AlarmRuleTemplate struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	StationType     string    `json:"station_type"`
+	Name            string    `json:"name"`
+	Semantic        string    `json:"semantic"`
+	Operator        Operator  `json:"operator"`
+	Threshold       float64   `json:"threshold"`
+	Hysteresis      float64   `json:"hysteresis"`
+	DurationSeconds int       `json:"duration_seconds"`
+	Severity        string    `json:"severity"`
+	NotifyChannel   string    `json:"notify_channel,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Validate checks template invariants; they match those of the rules it
+// instantiates.
+func (t AlarmRuleTemplate) Validate() error {
+	if t.ID == "" {
+		return errors.New("alarm rule template: empty id")
+	}
+	if t.StationType == "" {
+		return errors.New("alarm rule template: empty station type")
+	}
+	rule := t.Instantiate("template", nil)
+	if err := rule.Validate(); err != nil {
+		return errors.New("alarm rule template: " + err.Error())
+	}
+	return nil
+}
+
+// RuleID is the id of the rule instantiated from t on stationID. It is
+// stable, so applying a template to a station twice yields the same rule.
+func (t AlarmRuleTemplate) RuleID(stationID string) string {
+	sum := sha1.Sum([]byte(t.TenantID + "|" + t.ID + "|" + stationID))
+	return "rule-tpl-" + hex.EncodeToString(sum[:8])
+}
+
+// Instantiate returns the enabled rule t defines for stationID. A non-nil
+// threshold overrides the template threshold for this station.
+func (t AlarmRuleTemplate) Instantiate(stationID string, threshold *float64) AlarmRule {
+	rule := AlarmRule{
+		ID:              t.RuleID(stationID),
+		TenantID:        t.TenantID,
+		StationID:       stationID,
+		Name:            t.Name,
+		Semantic:        t.Semantic,
+		Operator:        t.Operator,
+		Threshold:       t.Threshold,
+		Hysteresis:      t.Hysteresis,
+		DurationSeconds: t.DurationSeconds,
+		Severity:        t.Severity,
+		Enabled:         true,
+		NotifyChannel:   t.NotifyChannel,
+	}
+	if threshold != nil {
+		rule.Threshold = *threshold
+	}
+	return rule
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	alarms "microgrid-cloud/internal/alarms/domain"
@@ -11,6 +12,10 @@ import (
 
 const defaultAlarmsTable = "alarms"
 
+const alarmColumns = `id, tenant_id, station_id, originator_type, originator_id, rule_id, status,
+	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at, last_sample_at,
+	min_value, max_value, value_sum, sample_count`
+
 // AlarmRepository is a Postgres repository for alarms.
 type AlarmRepository struct {
 	db    *sql.DB
@@ -39,13 +44,19 @@ func (r *AlarmRepository) Create(ctx context.Context, alarm *alarms.Alarm) error
 	if alarm.UpdatedAt.IsZero() {
 		alarm.UpdatedAt = alarm.CreatedAt
 	}
+	if alarm.LastSampleAt.IsZero() {
+		alarm.LastSampleAt = alarm.StartAt
+	}
+	alarm.MinValue, alarm.MaxValue, alarm.AvgValue, alarm.SampleCount = alarm.LastValue, alarm.LastValue, alarm.LastValue, 1
 	_, err := r.db.ExecContext(ctx, `
 INSERT INTO alarms (
 	id, tenant_id, station_id, originator_type, originator_id, rule_id, status,
-	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at
+	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at, last_sample_at,
+	min_value, max_value, value_sum, sample_count
 ) VALUES (
 	$1, $2, $3, $4, $5, $6, $7,
-	$8, $9, $10, $11, $12, $13, $14
+	$8, $9, $10, $11, $12, $13, $14, $15,
+	$10, $10, $10, 1
 )`,
 		alarm.ID,
 		alarm.TenantID,
@@ -61,6 +72,7 @@ INSERT INTO alarms (
 		nullableTime(alarm.ClearedAt),
 		alarm.CreatedAt,
 		alarm.UpdatedAt,
+		nullableTime(alarm.LastSampleAt),
 	)
 	return err
 }
@@ -71,8 +83,7 @@ func (r *AlarmRepository) GetByID(ctx context.Context, id string) (*alarms.Alarm
 		return nil, errors.New("alarm repo: nil db")
 	}
 	row := r.db.QueryRowContext(ctx, `
-SELECT id, tenant_id, station_id, originator_type, originator_id, rule_id, status,
-	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at
+SELECT `+alarmColumns+`
 FROM alarms
 WHERE id = $1`, id)
 	return scanAlarm(row)
@@ -87,8 +98,7 @@ func (r *AlarmRepository) FindOpenByRuleOriginator(ctx context.Context, tenantID
 		return nil, errors.New("alarm repo: invalid query")
 	}
 	row := r.db.QueryRowContext(ctx, `
-SELECT id, tenant_id, station_id, originator_type, originator_id, rule_id, status,
-	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at
+SELECT `+alarmColumns+`
 FROM alarms
 WHERE tenant_id = $1 AND rule_id = $2 AND originator_type = $3 AND originator_id = $4
 	AND status IN ('active', 'acknowledged')
@@ -97,18 +107,57 @@ LIMIT 1`, tenantID, ruleID, originatorType, originatorID)
 	return scanAlarm(row)
 }
 
-// UpdateLastValue updates the last value and updated_at.
+// UpdateLastValue records a new sample: last value, lifetime min/max/sum, updated_at and last_sample_at.
 func (r *AlarmRepository) UpdateLastValue(ctx context.Context, id string, value float64, updatedAt time.Time) error {
 	if r == nil || r.db == nil {
 		return errors.New("alarm repo: nil db")
 	}
 	_, err := r.db.ExecContext(ctx, `
 UPDATE alarms
-SET last_value = $1, updated_at = $2
+SET last_value = $1,
+	min_value = LEAST(COALESCE(min_value, $1), $1),
+	max_value = GREATEST(COALESCE(max_value, $1), $1),
+	value_sum = value_sum + $1,
+	sample_count = sample_count + 1,
+	updated_at = $2,
+	last_sample_at = $2
 WHERE id = $3`, value, updatedAt, id)
 	return err
 }
 
+// ListStaleOpen returns active or acknowledged alarms whose last sample is older than cutoff.
+func (r *AlarmRepository) ListStaleOpen(ctx context.Context, cutoff time.Time, limit int) ([]alarms.Alarm, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("alarm repo: nil db")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+alarmColumns+`
+FROM alarms
+WHERE status IN ('active', 'acknowledged') AND COALESCE(last_sample_at, start_at) < $1
+ORDER BY COALESCE(last_sample_at, start_at)
+LIMIT $2`, cutoff, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []alarms.Alarm
+	for rows.Next() {
+		alarm, err := scanAlarm(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *alarm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // MarkAcknowledged marks an alarm as acknowledged.
 func (r *AlarmRepository) MarkAcknowledged(ctx context.Context, id string, ackedAt time.Time) error {
 	if r == nil || r.db == nil {
@@ -133,44 +182,60 @@ WHERE id = $6`, alarms.StatusCleared, value, clearedAt, clearedAt, clearedAt, id
 	return err
 }
 
-// ListByStationStatusAndTime lists alarms for station within time window.
-func (r *AlarmRepository) ListByStationStatusAndTime(ctx context.Context, tenantID, stationID, status string, from, to time.Time) ([]alarms.Alarm, error) {
+// ListByStationStatusAndTime lists alarms for station within time window,
+// newest first. A limit above 0 and an offset page the list in SQL; total is
+// the number of alarms matching the filters.
+func (r *AlarmRepository) ListByStationStatusAndTime(ctx context.Context, tenantID, stationID, status string, from, to time.Time, limit, offset int) ([]alarms.Alarm, int, error) {
 	if r == nil || r.db == nil {
-		return nil, errors.New("alarm repo: nil db")
+		return nil, 0, errors.New("alarm repo: nil db")
 	}
 	if tenantID == "" || stationID == "" {
-		return nil, errors.New("alarm repo: invalid query")
+		return nil, 0, errors.New("alarm repo: invalid query")
 	}
-	query := `
-SELECT id, tenant_id, station_id, originator_type, originator_id, rule_id, status,
-	start_at, end_at, last_value, acked_at, cleared_at, created_at, updated_at
+	where := `
 FROM alarms
 WHERE tenant_id = $1 AND station_id = $2 AND start_at >= $3 AND start_at < $4`
 	args := []any{tenantID, stationID, from, to}
 	if status != "" {
-		query += " AND status = $5"
+		where += " AND status = $5"
 		args = append(args, status)
 	}
-	query += " ORDER BY start_at DESC"
+	query := "SELECT " + alarmColumns + where + " ORDER BY start_at DESC, id DESC"
+	pageArgs := append([]any(nil), args...)
+	if limit > 0 {
+		pageArgs = append(pageArgs, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(pageArgs))
+	}
+	if offset > 0 {
+		pageArgs = append(pageArgs, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(pageArgs))
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
-	var result []alarms.Alarm
+	var list []alarms.Alarm
 	for rows.Next() {
 		alarm, err := scanAlarm(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
-		result = append(result, *alarm)
+		list = append(list, *alarm)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return result, nil
+	if limit <= 0 && offset <= 0 {
+		return list, len(list), nil
+	}
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return list, total, nil
 }
 
 type alarmScanner interface {
@@ -183,6 +248,9 @@ func scanAlarm(row alarmScanner) (*alarms.Alarm, error) {
 	var ackedAt sql.NullTime
 	var clearedAt sql.NullTime
 	var lastValue sql.NullFloat64
+	var lastSampleAt sql.NullTime
+	var minValue, maxValue sql.NullFloat64
+	var valueSum float64
 	if err := row.Scan(
 		&alarm.ID,
 		&alarm.TenantID,
@@ -198,6 +266,11 @@ func scanAlarm(row alarmScanner) (*alarms.Alarm, error) {
 		&clearedAt,
 		&alarm.CreatedAt,
 		&alarm.UpdatedAt,
+		&lastSampleAt,
+		&minValue,
+		&maxValue,
+		&valueSum,
+		&alarm.SampleCount,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -219,6 +292,18 @@ func scanAlarm(row alarmScanner) (*alarms.Alarm, error) {
 	if lastValue.Valid {
 		alarm.LastValue = lastValue.Float64
 	}
+	if lastSampleAt.Valid {
+		alarm.LastSampleAt = lastSampleAt.Time.UTC()
+	}
+	if minValue.Valid {
+		alarm.MinValue = minValue.Float64
+	}
+	if maxValue.Valid {
+		alarm.MaxValue = maxValue.Float64
+	}
+	if alarm.SampleCount > 0 {
+		alarm.AvgValue = valueSum / float64(alarm.SampleCount)
+	}
 	return &alarm, nil
 }
 
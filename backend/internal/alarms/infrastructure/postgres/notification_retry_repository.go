@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+)
+
+// NotificationRetryRepository persists notifications queued for redelivery.
+type NotificationRetryRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRetryRepository constructs a repository.
+func NewNotificationRetryRepository(db *sql.DB) *NotificationRetryRepository {
+	return &NotificationRetryRepository{db: db}
+}
+
+// Enqueue stores a pending retry and sets its id.
+func (r *NotificationRetryRepository) Enqueue(ctx context.Context, retry *alarms.NotificationRetry) error {
+	if r == nil || r.db == nil {
+		return errors.New("notification retry repo: nil db")
+	}
+	if retry == nil || retry.Channel == "" {
+		return errors.New("notification retry repo: invalid retry")
+	}
+	if retry.CreatedAt.IsZero() {
+		retry.CreatedAt = time.Now().UTC()
+	}
+	retry.Status = alarms.RetryStatusPending
+	var fields any
+	if len(retry.Fields) > 0 {
+		fields = retry.Fields
+	}
+	return r.db.QueryRowContext(ctx, `
+INSERT INTO alarm_notification_retries (
+	alarm_id, channel, content, fields, attempts, status, next_attempt_at, last_error, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $9)
+RETURNING id`, retry.AlarmID, retry.Channel, retry.Content, fields, retry.Attempts, retry.Status,
+		retry.NextAttemptAt, retry.LastError, retry.CreatedAt).Scan(&retry.ID)
+}
+
+// retryClaimLease is how long a claimed retry stays hidden from other
+// instances. A claim that is never marked, e.g. after a crash, is due again
+// once it runs out.
+const retryClaimLease = 5 * time.Minute
+
+// ClaimDue claims pending retries due at or before now in queue order. Claimed
+// rows are leased by moving next_attempt_at past now, and rows another
+// instance is claiming are skipped, so every retry is sent by one instance.
+func (r *NotificationRetryRepository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]alarms.NotificationRetry, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("notification retry repo: nil db")
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.db.QueryContext(ctx, `
+WITH claimed AS (
+	SELECT id
+	FROM alarm_notification_retries
+	WHERE status = $1 AND next_attempt_at <= $2
+	ORDER BY next_attempt_at ASC, id ASC
+	FOR UPDATE SKIP LOCKED
+	LIMIT $3
+)
+UPDATE alarm_notification_retries r
+SET next_attempt_at = $4, updated_at = NOW()
+FROM claimed
+WHERE r.id = claimed.id
+RETURNING r.id, r.alarm_id, r.channel, r.content, r.fields, r.attempts, r.status, r.next_attempt_at, r.last_error, r.created_at`,
+		alarms.RetryStatusPending, now.UTC(), limit, now.UTC().Add(retryClaimLease))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []alarms.NotificationRetry
+	for rows.Next() {
+		var retry alarms.NotificationRetry
+		var lastError sql.NullString
+		if err := rows.Scan(
+			&retry.ID,
+			&retry.AlarmID,
+			&retry.Channel,
+			&retry.Content,
+			&retry.Fields,
+			&retry.Attempts,
+			&retry.Status,
+			&retry.NextAttemptAt,
+			&lastError,
+			&retry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		retry.LastError = lastError.String
+		retry.NextAttemptAt = retry.NextAttemptAt.UTC()
+		retry.CreatedAt = retry.CreatedAt.UTC()
+		result = append(result, retry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// MarkSent records a successful redelivery.
+func (r *NotificationRetryRepository) MarkSent(ctx context.Context, id int64, attempts int) error {
+	return r.update(ctx, id, alarms.RetryStatusSent, attempts, time.Time{}, "")
+}
+
+// MarkRetry records a failed attempt and schedules the next one.
+func (r *NotificationRetryRepository) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	return r.update(ctx, id, alarms.RetryStatusPending, attempts, nextAttemptAt, lastError)
+}
+
+// MarkExhausted gives up on a retry after its last failed attempt.
+func (r *NotificationRetryRepository) MarkExhausted(ctx context.Context, id int64, attempts int, lastError string) error {
+	return r.update(ctx, id, alarms.RetryStatusExhausted, attempts, time.Time{}, lastError)
+}
+
+func (r *NotificationRetryRepository) update(ctx context.Context, id int64, status string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	if r == nil || r.db == nil {
+		return errors.New("notification retry repo: nil db")
+	}
+	var next any
+	if !nextAttemptAt.IsZero() {
+		next = nextAttemptAt.UTC()
+	}
+	_, err := r.db.ExecContext(ctx, `
+UPDATE alarm_notification_retries
+SET status = $2,
+	attempts = $3,
+	next_attempt_at = COALESCE($4, next_attempt_at),
+	last_error = COALESCE(NULLIF($5, ''), last_error),
+	updated_at = NOW()
+WHERE id = $1`, id, status, attempts, next, lastError)
+	return err
+}
@@ -10,19 +10,40 @@ import (
 	alarms "microgrid-cloud/internal/alarms/domain"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
 )
 
 const defaultAlarmRulesTable = "alarm_rules"
 
+const alarmRuleColumns = `id, tenant_id, station_id, name, semantic, operator, threshold, hysteresis,
+	duration_seconds, severity, enabled, notify_channel, created_at, updated_at`
+
 // AlarmRuleRepository is a Postgres repository for alarm rules.
 type AlarmRuleRepository struct {
 	db    *sql.DB
 	table string
+	clock clock.Clock
+}
+
+// AlarmRuleOption configures the alarm rule repository.
+type AlarmRuleOption func(*AlarmRuleRepository)
+
+// WithAlarmRuleClock overrides the clock that stamps created_at and updated_at.
+func WithAlarmRuleClock(c clock.Clock) AlarmRuleOption {
+	return func(r *AlarmRuleRepository) {
+		if c != nil {
+			r.clock = c
+		}
+	}
 }
 
 // NewAlarmRuleRepository constructs a repository.
-func NewAlarmRuleRepository(db *sql.DB) *AlarmRuleRepository {
-	return &AlarmRuleRepository{db: db, table: defaultAlarmRulesTable}
+func NewAlarmRuleRepository(db *sql.DB, opts ...AlarmRuleOption) *AlarmRuleRepository {
+	repo := &AlarmRuleRepository{db: db, table: defaultAlarmRulesTable, clock: clock.System{}}
+	for _, opt := range opts {
+		opt(repo)
+	}
+	return repo
 }
 
 // Create inserts an alarm rule.
@@ -40,7 +61,7 @@ func (r *AlarmRuleRepository) Create(ctx context.Context, rule *alarms.AlarmRule
 		rule.Severity = "medium"
 	}
 	if rule.CreatedAt.IsZero() {
-		rule.CreatedAt = time.Now().UTC()
+		rule.CreatedAt = r.clock.Now().UTC()
 	}
 	if rule.UpdatedAt.IsZero() {
 		rule.UpdatedAt = rule.CreatedAt
@@ -48,17 +69,99 @@ func (r *AlarmRuleRepository) Create(ctx context.Context, rule *alarms.AlarmRule
 	_, err := r.db.ExecContext(ctx, `
 INSERT INTO alarm_rules (
 	id, tenant_id, station_id, name, semantic, operator, threshold, hysteresis,
-	duration_seconds, severity, enabled, created_at, updated_at
+	duration_seconds, severity, enabled, notify_channel, created_at, updated_at
 ) VALUES (
 	$1, $2, $3, $4, $5, $6, $7, $8,
-	$9, $10, $11, $12, $13
+	$9, $10, $11, $12, $13, $14
 )`, rule.ID, rule.TenantID, rule.StationID, rule.Name, rule.Semantic, string(rule.Operator),
 		rule.Threshold, rule.Hysteresis, rule.DurationSeconds, rule.Severity, rule.Enabled,
-		rule.CreatedAt, rule.UpdatedAt)
+		rule.NotifyChannel, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	logAlarmRuleAudit(ctx, r.db, "alarm_rule.create", rule)
+	return nil
+}
+
+// CreateIfAbsent inserts an alarm rule unless a rule with its id exists, and
+// reports whether it was inserted. An existing rule, possibly edited since, is
+// left unchanged.
+func (r *AlarmRuleRepository) CreateIfAbsent(ctx context.Context, rule *alarms.AlarmRule) (bool, error) {
+	if r == nil || r.db == nil {
+		return false, errors.New("alarm rule repo: nil db")
+	}
+	if rule == nil {
+		return false, errors.New("alarm rule repo: nil rule")
+	}
+	if err := rule.Validate(); err != nil {
+		return false, err
+	}
+	if rule.Severity == "" {
+		rule.Severity = "medium"
+	}
+	if rule.CreatedAt.IsZero() {
+		rule.CreatedAt = r.clock.Now().UTC()
+	}
+	if rule.UpdatedAt.IsZero() {
+		rule.UpdatedAt = rule.CreatedAt
+	}
+	result, err := r.db.ExecContext(ctx, `
+INSERT INTO alarm_rules (
+	id, tenant_id, station_id, name, semantic, operator, threshold, hysteresis,
+	duration_seconds, severity, enabled, notify_channel, created_at, updated_at
+) VALUES (
+	$1, $2, $3, $4, $5, $6, $7, $8,
+	$9, $10, $11, $12, $13, $14
+)
+ON CONFLICT (id) DO NOTHING`, rule.ID, rule.TenantID, rule.StationID, rule.Name, rule.Semantic, string(rule.Operator),
+		rule.Threshold, rule.Hysteresis, rule.DurationSeconds, rule.Severity, rule.Enabled,
+		rule.NotifyChannel, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+	logAlarmRuleAudit(ctx, r.db, "alarm_rule.create", rule)
+	return true, nil
+}
+
+// Update overwrites the editable fields of an existing rule and bumps updated_at.
+func (r *AlarmRuleRepository) Update(ctx context.Context, rule *alarms.AlarmRule) error {
+	if r == nil || r.db == nil {
+		return errors.New("alarm rule repo: nil db")
+	}
+	if rule == nil {
+		return errors.New("alarm rule repo: nil rule")
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	if rule.Severity == "" {
+		rule.Severity = "medium"
+	}
+	rule.UpdatedAt = r.clock.Now().UTC()
+	result, err := r.db.ExecContext(ctx, `
+UPDATE alarm_rules
+SET name = $3,
+	operator = $4,
+	threshold = $5,
+	hysteresis = $6,
+	duration_seconds = $7,
+	severity = $8,
+	enabled = $9,
+	notify_channel = $10,
+	updated_at = $11
+WHERE tenant_id = $1 AND id = $2`, rule.TenantID, rule.ID, rule.Name, string(rule.Operator),
+		rule.Threshold, rule.Hysteresis, rule.DurationSeconds, rule.Severity, rule.Enabled,
+		rule.NotifyChannel, rule.UpdatedAt)
 	if err != nil {
 		return err
 	}
-	logAlarmRuleAudit(ctx, r.db, rule)
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return alarms.ErrNotFound
+	}
+	logAlarmRuleAudit(ctx, r.db, "alarm_rule.update", rule)
 	return nil
 }
 
@@ -71,41 +174,31 @@ func (r *AlarmRuleRepository) GetByID(ctx context.Context, tenantID, ruleID stri
 		return nil, errors.New("alarm rule repo: invalid query")
 	}
 	row := r.db.QueryRowContext(ctx, `
-SELECT id, tenant_id, station_id, name, semantic, operator, threshold, hysteresis,
-	duration_seconds, severity, enabled, created_at, updated_at
+SELECT `+alarmRuleColumns+`
 FROM alarm_rules
 WHERE tenant_id = $1 AND id = $2
 LIMIT 1`, tenantID, ruleID)
-	var rule alarms.AlarmRule
-	var op string
-	if err := row.Scan(
-		&rule.ID,
-		&rule.TenantID,
-		&rule.StationID,
-		&rule.Name,
-		&rule.Semantic,
-		&op,
-		&rule.Threshold,
-		&rule.Hysteresis,
-		&rule.DurationSeconds,
-		&rule.Severity,
-		&rule.Enabled,
-		&rule.CreatedAt,
-		&rule.UpdatedAt,
-	); err != nil {
+	rule, err := scanAlarmRule(row)
+	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-	rule.Operator = alarms.Operator(op)
-	rule.CreatedAt = rule.CreatedAt.UTC()
-	rule.UpdatedAt = rule.UpdatedAt.UTC()
-	return &rule, nil
+	return rule, nil
 }
 
 // ListEnabledByStation returns enabled rules for a station.
 func (r *AlarmRuleRepository) ListEnabledByStation(ctx context.Context, tenantID, stationID string) ([]alarms.AlarmRule, error) {
+	return r.listByStation(ctx, tenantID, stationID, true)
+}
+
+// ListByStation returns all rules for a station, including disabled ones.
+func (r *AlarmRuleRepository) ListByStation(ctx context.Context, tenantID, stationID string) ([]alarms.AlarmRule, error) {
+	return r.listByStation(ctx, tenantID, stationID, false)
+}
+
+func (r *AlarmRuleRepository) listByStation(ctx context.Context, tenantID, stationID string, enabledOnly bool) ([]alarms.AlarmRule, error) {
 	if r == nil || r.db == nil {
 		return nil, errors.New("alarm rule repo: nil db")
 	}
@@ -113,11 +206,10 @@ func (r *AlarmRuleRepository) ListEnabledByStation(ctx context.Context, tenantID
 		return nil, errors.New("alarm rule repo: invalid query")
 	}
 	rows, err := r.db.QueryContext(ctx, `
-SELECT id, tenant_id, station_id, name, semantic, operator, threshold, hysteresis,
-	duration_seconds, severity, enabled, created_at, updated_at
+SELECT `+alarmRuleColumns+`
 FROM alarm_rules
-WHERE tenant_id = $1 AND station_id = $2 AND enabled = TRUE
-ORDER BY created_at ASC`, tenantID, stationID)
+WHERE tenant_id = $1 AND station_id = $2 AND (enabled = TRUE OR NOT $3)
+ORDER BY created_at ASC`, tenantID, stationID, enabledOnly)
 	if err != nil {
 		return nil, err
 	}
@@ -125,29 +217,11 @@ ORDER BY created_at ASC`, tenantID, stationID)
 
 	var result []alarms.AlarmRule
 	for rows.Next() {
-		var rule alarms.AlarmRule
-		var op string
-		if err := rows.Scan(
-			&rule.ID,
-			&rule.TenantID,
-			&rule.StationID,
-			&rule.Name,
-			&rule.Semantic,
-			&op,
-			&rule.Threshold,
-			&rule.Hysteresis,
-			&rule.DurationSeconds,
-			&rule.Severity,
-			&rule.Enabled,
-			&rule.CreatedAt,
-			&rule.UpdatedAt,
-		); err != nil {
+		rule, err := scanAlarmRule(rows)
+		if err != nil {
 			return nil, err
 		}
-		rule.Operator = alarms.Operator(op)
-		rule.CreatedAt = rule.CreatedAt.UTC()
-		rule.UpdatedAt = rule.UpdatedAt.UTC()
-		result = append(result, rule)
+		result = append(result, *rule)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -155,7 +229,34 @@ ORDER BY created_at ASC`, tenantID, stationID)
 	return result, nil
 }
 
-func logAlarmRuleAudit(ctx context.Context, db *sql.DB, rule *alarms.AlarmRule) {
+func scanAlarmRule(row alarmScanner) (*alarms.AlarmRule, error) {
+	var rule alarms.AlarmRule
+	var op string
+	if err := row.Scan(
+		&rule.ID,
+		&rule.TenantID,
+		&rule.StationID,
+		&rule.Name,
+		&rule.Semantic,
+		&op,
+		&rule.Threshold,
+		&rule.Hysteresis,
+		&rule.DurationSeconds,
+		&rule.Severity,
+		&rule.Enabled,
+		&rule.NotifyChannel,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	rule.Operator = alarms.Operator(op)
+	rule.CreatedAt = rule.CreatedAt.UTC()
+	rule.UpdatedAt = rule.UpdatedAt.UTC()
+	return &rule, nil
+}
+
+func logAlarmRuleAudit(ctx context.Context, db *sql.DB, action string, rule *alarms.AlarmRule) {
 	if db == nil || rule == nil {
 		return
 	}
@@ -176,6 +277,7 @@ func logAlarmRuleAudit(ctx context.Context, db *sql.DB, rule *alarms.AlarmRule)
 		"duration_seconds": rule.DurationSeconds,
 		"severity":         rule.Severity,
 		"enabled":          rule.Enabled,
+		"notify_channel":   rule.NotifyChannel,
 	})
 	repo := audit.NewRepository(db)
 	if repo == nil {
@@ -185,7 +287,7 @@ func logAlarmRuleAudit(ctx context.Context, db *sql.DB, rule *alarms.AlarmRule)
 		TenantID:     tenantID,
 		Actor:        auth.SubjectFromContext(ctx),
 		Role:         string(auth.RoleFromContext(ctx)),
-		Action:       "alarm_rule.create",
+		Action:       action,
 		ResourceType: "alarm_rule",
 		ResourceID:   rule.ID,
 		StationID:    rule.StationID,
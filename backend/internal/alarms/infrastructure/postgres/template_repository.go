@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/audit"
+	"microgrid-cloud/internal/auth"
+)
+
+const alarmRuleTemplateColumns = `id, tenant_id, station_type, name, semantic, operator, threshold, hysteresis,
+	duration_seconds, severity, notify_channel, created_at, updated_at`
+
+// AlarmRuleTemplateRepository is a Postgres repository for alarm rule templates.
+type AlarmRuleTemplateRepository struct {
+	db *sql.DB
+}
+
+// NewAlarmRuleTemplateRepository constructs a repository.
+func NewAlarmRuleTemplateRepository(db *sql.DB) *AlarmRuleTemplateRepository {
+	return &AlarmRuleTemplateRepository{db: db}
+}
+
+// Save upserts a template. Rules already instantiated from it keep their
+// values; only later applications use the new ones.
+func (r *AlarmRuleTemplateRepository) Save(ctx context.Context, template *alarms.AlarmRuleTemplate) error {
+	if r == nil || r.db == nil {
+		return errors.New("alarm rule template repo: nil db")
+	}
+	if template == nil {
+		return errors.New("alarm rule template repo: nil template")
+	}
+	if err := template.Validate(); err != nil {
+		return err
+	}
+	if template.Severity == "" {
+		template.Severity = "medium"
+	}
+	now := time.Now().UTC()
+	if template.CreatedAt.IsZero() {
+		template.CreatedAt = now
+	}
+	template.UpdatedAt = now
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO alarm_rule_templates (
+	id, tenant_id, station_type, name, semantic, operator, threshold, hysteresis,
+	duration_seconds, severity, notify_channel, created_at, updated_at
+) VALUES (
+	$1, $2, $3, $4, $5, $6, $7, $8,
+	$9, $10, $11, $12, $13
+)
+ON CONFLICT (id)
+DO UPDATE SET
+	station_type = EXCLUDED.station_type,
+	name = EXCLUDED.name,
+	semantic = EXCLUDED.semantic,
+	operator = EXCLUDED.operator,
+	threshold = EXCLUDED.threshold,
+	hysteresis = EXCLUDED.hysteresis,
+	duration_seconds = EXCLUDED.duration_seconds,
+	severity = EXCLUDED.severity,
+	notify_channel = EXCLUDED.notify_channel,
+	updated_at = EXCLUDED.updated_at
+WHERE alarm_rule_templates.tenant_id = EXCLUDED.tenant_id`,
+		template.ID, template.TenantID, template.StationType, template.Name, template.Semantic, string(template.Operator),
+		template.Threshold, template.Hysteresis, template.DurationSeconds, template.Severity, template.NotifyChannel,
+		template.CreatedAt, template.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	logAlarmRuleTemplateAudit(ctx, r.db, template)
+	return nil
+}
+
+// List returns the templates of a tenant ordered by station type and creation;
+// a non-empty stationType keeps only that type.
+func (r *AlarmRuleTemplateRepository) List(ctx context.Context, tenantID, stationType string) ([]alarms.AlarmRuleTemplate, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("alarm rule template repo: nil db")
+	}
+	if tenantID == "" {
+		return nil, errors.New("alarm rule template repo: invalid query")
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT `+alarmRuleTemplateColumns+`
+FROM alarm_rule_templates
+WHERE tenant_id = $1 AND ($2 = '' OR station_type = $2)
+ORDER BY station_type ASC, created_at ASC, id ASC`, tenantID, stationType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []alarms.AlarmRuleTemplate
+	for rows.Next() {
+		var template alarms.AlarmRuleTemplate
+		var op string
+		if err := rows.Scan(
+			&template.ID,
+			&template.TenantID,
+			&template.StationType,
+			&template.Name,
+			&template.Semantic,
+			&op,
+			&template.Threshold,
+			&template.Hysteresis,
+			&template.DurationSeconds,
+			&template.Severity,
+			&template.NotifyChannel,
+			&template.CreatedAt,
+			&template.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		template.Operator = alarms.Operator(op)
+		template.CreatedAt = template.CreatedAt.UTC()
+		template.UpdatedAt = template.UpdatedAt.UTC()
+		result = append(result, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func logAlarmRuleTemplateAudit(ctx context.Context, db *sql.DB, template *alarms.AlarmRuleTemplate) {
+	tenantID := auth.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		tenantID = template.TenantID
+	}
+	meta, _ := json.Marshal(map[string]any{
+		"station_type":     template.StationType,
+		"name":             template.Name,
+		"semantic":         template.Semantic,
+		"operator":         template.Operator,
+		"threshold":        template.Threshold,
+		"hysteresis":       template.Hysteresis,
+		"duration_seconds": template.DurationSeconds,
+		"severity":         template.Severity,
+		"notify_channel":   template.NotifyChannel,
+	})
+	repo := audit.NewRepository(db)
+	if repo == nil {
+		return
+	}
+	_ = repo.Log(ctx, audit.Entry{
+		TenantID:     tenantID,
+		Actor:        auth.SubjectFromContext(ctx),
+		Role:         string(auth.RoleFromContext(ctx)),
+		Action:       "alarm_rule_template.save",
+		ResourceType: "alarm_rule_template",
+		ResourceID:   template.ID,
+		Metadata:     meta,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
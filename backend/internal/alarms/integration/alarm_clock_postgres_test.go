@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/clock"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestAlarmService_UsesInjectedClock(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_rules") || !tableExists(db, "alarms") || !tableExists(db, "alarm_rule_states") || !tableExists(db, "point_mappings") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-it-clock"
+	stationID := "station-it-clock"
+	deviceID := "device-it-clock"
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rule_states WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarms WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rules WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM devices WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", stationID)
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO stations (id, tenant_id, name) VALUES ($1, $2, $3)`, stationID, tenantID, "Clock Station"); err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO devices (id, station_id, name) VALUES ($1, $2, $3)`, deviceID, stationID, "Clock Device"); err != nil {
+		t.Fatalf("insert device: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO point_mappings (id, station_id, device_id, point_key, semantic, unit, factor)
+VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		"map-clock-1", stationID, deviceID, "charge_power_kw", "charge_power_kw", "kW", 1.0); err != nil {
+		t.Fatalf("insert mapping: %v", err)
+	}
+
+	ruleRepo := alarmrepo.NewAlarmRuleRepository(db)
+	rule := &alarms.AlarmRule{
+		ID:        "rule-clock-1",
+		TenantID:  tenantID,
+		StationID: stationID,
+		Name:      "Charge High",
+		Semantic:  "charge_power_kw",
+		Operator:  alarms.OperatorGreater,
+		Threshold: 100,
+		Severity:  "high",
+		Enabled:   true,
+	}
+	if err := ruleRepo.Create(ctx, rule); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	frozen := clock.NewFixed(time.Date(2026, time.March, 1, 8, 30, 0, 0, time.UTC))
+	alarmRepo := alarmrepo.NewAlarmRepository(db)
+	service, err := alarmapp.NewService(ruleRepo, alarmRepo, alarmrepo.NewAlarmRuleStateRepository(db), masterdatarepo.NewPointMappingRepository(db), tenantID, alarmapp.WithClock(frozen))
+	if err != nil {
+		t.Fatalf("new alarm service: %v", err)
+	}
+
+	// No timestamps on the event: the service must fall back to the injected clock.
+	err = service.HandleTelemetryReceived(ctx, telemetryevents.TelemetryReceived{
+		TenantID:  tenantID,
+		StationID: stationID,
+		DeviceID:  deviceID,
+		Points:    []telemetryevents.TelemetryPoint{{PointKey: "charge_power_kw", Value: 150}},
+	})
+	if err != nil {
+		t.Fatalf("handle telemetry: %v", err)
+	}
+
+	open, err := alarmRepo.FindOpenByRuleOriginator(ctx, tenantID, rule.ID, alarms.OriginatorDevice, deviceID)
+	if err != nil || open == nil {
+		t.Fatalf("expected open alarm, err=%v", err)
+	}
+	if !open.StartAt.Equal(frozen.Now()) || !open.CreatedAt.Equal(frozen.Now()) {
+		t.Fatalf("expected frozen timestamps, got start=%s created=%s", open.StartAt, open.CreatedAt)
+	}
+
+	frozen.Advance(10 * time.Minute)
+	acked, err := service.AckAlarm(ctx, open.ID)
+	if err != nil {
+		t.Fatalf("ack alarm: %v", err)
+	}
+	if !acked.AckedAt.Equal(frozen.Now()) {
+		t.Fatalf("expected acked_at %s, got %s", frozen.Now(), acked.AckedAt)
+	}
+}
@@ -0,0 +1,78 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestNotificationRetryRepository_ClaimDueClaimsOnce(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_notification_retries") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	alarmID := "alarm-it-retry-claim"
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_notification_retries WHERE alarm_id = $1", alarmID)
+	defer func() {
+		_, _ = db.ExecContext(ctx, "DELETE FROM alarm_notification_retries WHERE alarm_id = $1", alarmID)
+	}()
+
+	repo := alarmrepo.NewNotificationRetryRepository(db)
+	now := time.Now().UTC()
+	retry := &alarms.NotificationRetry{AlarmID: alarmID, Channel: "default", Content: "alarm", Attempts: 1, NextAttemptAt: now.Add(-time.Minute)}
+	if err := repo.Enqueue(ctx, retry); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	claimed, err := repo.ClaimDue(ctx, now, 1000)
+	if err != nil {
+		t.Fatalf("first claim: %v", err)
+	}
+	if !containsRetry(claimed, retry.ID) {
+		t.Fatalf("first claim missed retry %d", retry.ID)
+	}
+	// A second instance polling right after sees the retry leased.
+	again, err := repo.ClaimDue(ctx, now, 1000)
+	if err != nil {
+		t.Fatalf("second claim: %v", err)
+	}
+	if containsRetry(again, retry.ID) {
+		t.Fatalf("retry %d claimed twice", retry.ID)
+	}
+	// An unmarked claim is due again once its lease runs out.
+	later, err := repo.ClaimDue(ctx, now.Add(time.Hour), 1000)
+	if err != nil {
+		t.Fatalf("claim after lease: %v", err)
+	}
+	if !containsRetry(later, retry.ID) {
+		t.Fatalf("retry %d not reclaimed after its lease", retry.ID)
+	}
+}
+
+func containsRetry(retries []alarms.NotificationRetry, id int64) bool {
+	for _, retry := range retries {
+		if retry.ID == id {
+			return true
+		}
+	}
+	return false
+}
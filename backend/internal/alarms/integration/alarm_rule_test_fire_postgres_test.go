@@ -0,0 +1,123 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	alarmhttp "microgrid-cloud/internal/alarms/interfaces/http"
+	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestAlarmHandler_RuleTestFireDoesNotPersist(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_rules") || !tableExists(db, "alarms") || !tableExists(db, "alarm_rule_states") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-it-testfire"
+	stationID := "station-it-testfire"
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarms WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rules WHERE tenant_id = $1", tenantID)
+
+	ruleRepo := alarmrepo.NewAlarmRuleRepository(db)
+	rule := &alarms.AlarmRule{
+		ID:         "rule-testfire-1",
+		TenantID:   tenantID,
+		StationID:  stationID,
+		Name:       "Charge High",
+		Semantic:   "charge_power_kw",
+		Operator:   alarms.OperatorGreater,
+		Threshold:  100,
+		Hysteresis: 5,
+		Severity:   "high",
+		Enabled:    true,
+	}
+	if err := ruleRepo.Create(ctx, rule); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	frozen := clock.NewFixed(time.Date(2026, time.March, 5, 8, 0, 0, 0, time.UTC))
+	service, err := alarmapp.NewService(ruleRepo, alarmrepo.NewAlarmRepository(db), alarmrepo.NewAlarmRuleStateRepository(db), masterdatarepo.NewPointMappingRepository(db), tenantID, alarmapp.WithClock(frozen))
+	if err != nil {
+		t.Fatalf("new alarm service: %v", err)
+	}
+	handler, err := alarmhttp.NewRuleHandler(service, nil)
+	if err != nil {
+		t.Fatalf("alarm handler: %v", err)
+	}
+	identity := auth.WithIdentity(ctx, tenantID, auth.RoleOperator, "it-operator")
+
+	cases := []struct {
+		body    string
+		outcome string
+		at      time.Time
+	}{
+		{`{"value":130}`, alarmapp.RuleTestTrigger, frozen.Now()},
+		{`{"value":90,"ts":"2026-03-05T07:00:00Z"}`, alarmapp.RuleTestClear, time.Date(2026, time.March, 5, 7, 0, 0, 0, time.UTC)},
+		{`{"value":98}`, alarmapp.RuleTestNone, frozen.Now()},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/alarm-rules/"+rule.ID+"/test", strings.NewReader(tc.body)).WithContext(identity)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("body=%s status=%d resp=%s", tc.body, w.Code, w.Body.String())
+		}
+		var result alarmapp.RuleTestResult
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if result.Outcome != tc.outcome || !result.At.Equal(tc.at) || result.RuleID != rule.ID {
+			t.Fatalf("body=%s unexpected result %+v", tc.body, result)
+		}
+	}
+
+	alias := httptest.NewRequest(http.MethodPost, "/api/v1/alarms/rules/"+rule.ID+"/test", strings.NewReader(`{"value":130}`)).WithContext(identity)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, alias)
+	var aliased alarmapp.RuleTestResult
+	if w.Code != http.StatusOK || json.NewDecoder(w.Body).Decode(&aliased) != nil || aliased.Outcome != alarmapp.RuleTestTrigger {
+		t.Fatalf("alias path status=%d result=%+v", w.Code, aliased)
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM alarms WHERE tenant_id = $1", tenantID).Scan(&count); err != nil {
+		t.Fatalf("count alarms: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("test-fire persisted %d alarms", count)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/alarm-rules/rule-missing/test", strings.NewReader(`{"value":1}`)).WithContext(identity)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown rule, got %d", w.Code)
+	}
+}
@@ -0,0 +1,167 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	alarmhttp "microgrid-cloud/internal/alarms/interfaces/http"
+	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestAlarmRuleHandler_DisableStopsNewAlarmsAndKeepsExisting(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_rules") || !tableExists(db, "alarms") || !tableExists(db, "alarm_rule_states") || !tableExists(db, "point_mappings") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-it-rules"
+	stationID := "station-it-rules"
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rule_states WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarms WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rules WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", stationID)
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO stations (id, tenant_id, name) VALUES ($1, $2, $3)`, stationID, tenantID, "Rules Station"); err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO point_mappings (id, station_id, point_key, semantic, unit, factor)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		"map-rules-1", stationID, "charge_power_kw", "charge_power_kw", "kW", 1.0); err != nil {
+		t.Fatalf("insert mapping: %v", err)
+	}
+
+	frozen := clock.NewFixed(time.Date(2026, time.March, 4, 8, 0, 0, 0, time.UTC))
+	alarmRepo := alarmrepo.NewAlarmRepository(db)
+	service, err := alarmapp.NewService(alarmrepo.NewAlarmRuleRepository(db, alarmrepo.WithAlarmRuleClock(frozen)), alarmRepo, alarmrepo.NewAlarmRuleStateRepository(db), masterdatarepo.NewPointMappingRepository(db), tenantID, alarmapp.WithClock(frozen))
+	if err != nil {
+		t.Fatalf("new alarm service: %v", err)
+	}
+	handler, err := alarmhttp.NewRuleHandler(service, nil)
+	if err != nil {
+		t.Fatalf("rule handler: %v", err)
+	}
+	identity := auth.WithIdentity(ctx, tenantID, auth.RoleOperator, "it-operator")
+
+	call := func(method, path, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(identity)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	ingest := func(value float64) {
+		t.Helper()
+		err := service.HandleTelemetryReceived(identity, telemetryevents.TelemetryReceived{
+			TenantID:   tenantID,
+			StationID:  stationID,
+			OccurredAt: frozen.Now(),
+			Points:     []telemetryevents.TelemetryPoint{{PointKey: "charge_power_kw", Value: value, TS: frozen.Now()}},
+		})
+		if err != nil {
+			t.Fatalf("handle telemetry: %v", err)
+		}
+		frozen.Advance(time.Minute)
+	}
+	openAlarm := func(ruleID string) *alarms.Alarm {
+		t.Helper()
+		open, err := alarmRepo.FindOpenByRuleOriginator(ctx, tenantID, ruleID, alarms.OriginatorStation, stationID)
+		if err != nil {
+			t.Fatalf("find open alarm: %v", err)
+		}
+		return open
+	}
+
+	w := call(http.MethodPost, "/api/v1/alarm-rules", `{"id":"rule-it-rules","station_id":"`+stationID+`","name":"Charge High","semantic":"charge_power_kw","operator":">","threshold":100,"hysteresis":5,"severity":"High"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status=%d body=%s", w.Code, w.Body.String())
+	}
+	var rule alarms.AlarmRule
+	if err := json.NewDecoder(w.Body).Decode(&rule); err != nil {
+		t.Fatalf("decode rule: %v", err)
+	}
+	if rule.TenantID != tenantID || !rule.Enabled || rule.Severity != "high" {
+		t.Fatalf("unexpected created rule: %+v", rule)
+	}
+
+	ingest(150)
+	existing := openAlarm(rule.ID)
+	if existing == nil {
+		t.Fatalf("expected alarm from enabled rule")
+	}
+
+	if w := call(http.MethodPost, "/api/v1/alarm-rules/"+rule.ID+"/disable", ""); w.Code != http.StatusOK {
+		t.Fatalf("disable status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	// The disabled rule no longer evaluates, so the open alarm is neither cleared nor duplicated.
+	ingest(50)
+	if open := openAlarm(rule.ID); open == nil || open.ID != existing.ID {
+		t.Fatalf("expected existing alarm to stay open, got %+v", open)
+	}
+	if _, err := service.ClearAlarm(identity, existing.ID); err != nil {
+		t.Fatalf("clear alarm: %v", err)
+	}
+	ingest(150)
+	if open := openAlarm(rule.ID); open != nil {
+		t.Fatalf("disabled rule raised alarm %s", open.ID)
+	}
+
+	w = call(http.MethodGet, "/api/v1/alarm-rules?station_id="+stationID, "")
+	var list []alarms.AlarmRule
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil || len(list) != 1 || list[0].Enabled {
+		t.Fatalf("expected disabled rule in list, status=%d list=%+v err=%v", w.Code, list, err)
+	}
+
+	w = call(http.MethodPatch, "/api/v1/alarm-rules/"+rule.ID, `{"threshold":200,"enabled":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("patch status=%d body=%s", w.Code, w.Body.String())
+	}
+	ingest(150)
+	if open := openAlarm(rule.ID); open != nil {
+		t.Fatalf("edited threshold not applied, alarm %s raised", open.ID)
+	}
+	ingest(250)
+	if open := openAlarm(rule.ID); open == nil {
+		t.Fatalf("expected alarm above edited threshold")
+	}
+
+	if w := call(http.MethodPatch, "/api/v1/alarm-rules/"+rule.ID, `{"severity":"urgent"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid severity, got %d", w.Code)
+	}
+	other := auth.WithIdentity(ctx, "tenant-other", auth.RoleOperator, "it-operator")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/alarm-rules/"+rule.ID, nil).WithContext(other)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for other tenant, got %d", w.Code)
+	}
+}
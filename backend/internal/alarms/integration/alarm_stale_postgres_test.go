@@ -0,0 +1,151 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/clock"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []alarmapp.AlarmEvent
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, event alarmapp.AlarmEvent) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *recordingNotifier) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	for _, evt := range r.events {
+		out = append(out, evt.Type)
+	}
+	return out
+}
+
+func TestAlarmService_SweepStaleClearsAlarmsWithoutSamples(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_rules") || !tableExists(db, "alarms") || !tableExists(db, "alarm_rule_states") || !tableExists(db, "point_mappings") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-it-stale"
+	stationID := "station-it-stale"
+	devices := []string{"device-it-stale-a", "device-it-stale-b"}
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rule_states WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarms WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rules WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM devices WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", stationID)
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO stations (id, tenant_id, name) VALUES ($1, $2, $3)`, stationID, tenantID, "Stale Station"); err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO point_mappings (id, station_id, point_key, semantic, unit, factor)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		"map-stale-1", stationID, "charge_power_kw", "charge_power_kw", "kW", 1.0); err != nil {
+		t.Fatalf("insert mapping: %v", err)
+	}
+	for _, deviceID := range devices {
+		if _, err := db.ExecContext(ctx, `INSERT INTO devices (id, station_id, name) VALUES ($1, $2, $3)`, deviceID, stationID, deviceID); err != nil {
+			t.Fatalf("insert device: %v", err)
+		}
+	}
+
+	ruleRepo := alarmrepo.NewAlarmRuleRepository(db)
+	rule := &alarms.AlarmRule{
+		ID:        "rule-stale-1",
+		TenantID:  tenantID,
+		StationID: stationID,
+		Name:      "Charge High",
+		Semantic:  "charge_power_kw",
+		Operator:  alarms.OperatorGreater,
+		Threshold: 100,
+		Severity:  "high",
+		Enabled:   true,
+	}
+	if err := ruleRepo.Create(ctx, rule); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	frozen := clock.NewFixed(time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC))
+	notifier := &recordingNotifier{}
+	alarmRepo := alarmrepo.NewAlarmRepository(db)
+	service, err := alarmapp.NewService(ruleRepo, alarmRepo, alarmrepo.NewAlarmRuleStateRepository(db), masterdatarepo.NewPointMappingRepository(db), tenantID,
+		alarmapp.WithClock(frozen), alarmapp.WithNotifier(notifier), alarmapp.WithStaleAfter(10*time.Minute))
+	if err != nil {
+		t.Fatalf("new alarm service: %v", err)
+	}
+
+	sample := func(deviceID string, value float64) {
+		t.Helper()
+		err := service.HandleTelemetryReceived(ctx, telemetryevents.TelemetryReceived{
+			TenantID:   tenantID,
+			StationID:  stationID,
+			DeviceID:   deviceID,
+			OccurredAt: frozen.Now(),
+			Points:     []telemetryevents.TelemetryPoint{{PointKey: "charge_power_kw", Value: value, TS: frozen.Now()}},
+		})
+		if err != nil {
+			t.Fatalf("handle telemetry: %v", err)
+		}
+	}
+
+	// Both devices raise an alarm; only device b keeps reporting.
+	sample(devices[0], 150)
+	sample(devices[1], 150)
+	frozen.Advance(8 * time.Minute)
+	sample(devices[1], 140)
+	frozen.Advance(4 * time.Minute)
+
+	cleared, err := service.SweepStale(ctx)
+	if err != nil {
+		t.Fatalf("sweep: %v", err)
+	}
+	if cleared != 1 {
+		t.Fatalf("expected 1 stale alarm cleared, got %d", cleared)
+	}
+
+	staleOpen, err := alarmRepo.FindOpenByRuleOriginator(ctx, tenantID, rule.ID, alarms.OriginatorDevice, devices[0])
+	if err != nil || staleOpen != nil {
+		t.Fatalf("expected stale alarm to be cleared, got %+v err=%v", staleOpen, err)
+	}
+	freshOpen, err := alarmRepo.FindOpenByRuleOriginator(ctx, tenantID, rule.ID, alarms.OriginatorDevice, devices[1])
+	if err != nil || freshOpen == nil {
+		t.Fatalf("expected reporting alarm to stay open, err=%v", err)
+	}
+	types := notifier.types()
+	if len(types) == 0 || types[len(types)-1] != alarmapp.EventStale {
+		t.Fatalf("expected stale notification, got %v", types)
+	}
+}
@@ -0,0 +1,100 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/clock"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestAlarmService_TracksLifetimeValueStats(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "alarm_rules") || !tableExists(db, "alarms") || !tableExists(db, "alarm_rule_states") || !tableExists(db, "point_mappings") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-it-stats"
+	stationID := "station-it-stats"
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rule_states WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarms WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM alarm_rules WHERE tenant_id = $1", tenantID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", stationID)
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO stations (id, tenant_id, name) VALUES ($1, $2, $3)`, stationID, tenantID, "Stats Station"); err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO point_mappings (id, station_id, point_key, semantic, unit, factor)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		"map-stats-1", stationID, "charge_power_kw", "charge_power_kw", "kW", 1.0); err != nil {
+		t.Fatalf("insert mapping: %v", err)
+	}
+
+	ruleRepo := alarmrepo.NewAlarmRuleRepository(db)
+	rule := &alarms.AlarmRule{
+		ID:         "rule-stats-1",
+		TenantID:   tenantID,
+		StationID:  stationID,
+		Name:       "Charge High",
+		Semantic:   "charge_power_kw",
+		Operator:   alarms.OperatorGreater,
+		Threshold:  100,
+		Hysteresis: 5,
+		Severity:   "high",
+		Enabled:    true,
+	}
+	if err := ruleRepo.Create(ctx, rule); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+
+	frozen := clock.NewFixed(time.Date(2026, time.March, 3, 8, 0, 0, 0, time.UTC))
+	alarmRepo := alarmrepo.NewAlarmRepository(db)
+	service, err := alarmapp.NewService(ruleRepo, alarmRepo, alarmrepo.NewAlarmRuleStateRepository(db), masterdatarepo.NewPointMappingRepository(db), tenantID, alarmapp.WithClock(frozen))
+	if err != nil {
+		t.Fatalf("new alarm service: %v", err)
+	}
+
+	for _, value := range []float64{120, 180, 110, 150} {
+		err := service.HandleTelemetryReceived(ctx, telemetryevents.TelemetryReceived{
+			TenantID:   tenantID,
+			StationID:  stationID,
+			OccurredAt: frozen.Now(),
+			Points:     []telemetryevents.TelemetryPoint{{PointKey: "charge_power_kw", Value: value, TS: frozen.Now()}},
+		})
+		if err != nil {
+			t.Fatalf("handle telemetry: %v", err)
+		}
+		frozen.Advance(time.Minute)
+	}
+
+	open, err := alarmRepo.FindOpenByRuleOriginator(ctx, tenantID, rule.ID, alarms.OriginatorStation, stationID)
+	if err != nil || open == nil {
+		t.Fatalf("expected open alarm, err=%v", err)
+	}
+	if open.MaxValue != 180 || open.MinValue != 110 || open.SampleCount != 4 || open.AvgValue != 140 || open.LastValue != 150 {
+		t.Fatalf("unexpected stats: min=%v max=%v avg=%v count=%d last=%v", open.MinValue, open.MaxValue, open.AvgValue, open.SampleCount, open.LastValue)
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	alarmapp "microgrid-cloud/internal/alarms/application"
 	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/api/pagination"
 	"microgrid-cloud/internal/auth"
 )
 
@@ -50,24 +52,29 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 	stationID := r.URL.Query().Get("station_id")
 	if stationID == "" {
-		http.Error(w, "station_id is required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
 		return
 	}
 	from, err := parseTimeQuery(r, "from")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	to, err := parseTimeQuery(r, "to")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be after from")
 		return
 	}
 	status := r.URL.Query().Get("status")
+	page, err := pagination.Parse(r)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
 
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" {
@@ -77,11 +84,12 @@ func (h *Handler) handleList(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	list, err := h.service.ListAlarms(r.Context(), stationID, status, from, to)
+	list, total, err := h.service.ListAlarms(r.Context(), stationID, status, from, to, page.Limit, page.Offset)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
+	pagination.SetHeaders(w, page, total)
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(list)
@@ -120,10 +128,10 @@ func (h *Handler) handleAction(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if errors.Is(err, auth.ErrTenantMismatch) {
-			http.Error(w, "forbidden", http.StatusForbidden)
+			apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 			return
 		}
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -142,14 +150,14 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
 
 func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
@@ -0,0 +1,265 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+// RuleHandler provides alarm rule management endpoints.
+type RuleHandler struct {
+	service        *alarmapp.Service
+	stationChecker auth.StationTenantChecker
+}
+
+// NewRuleHandler constructs a rule handler.
+func NewRuleHandler(service *alarmapp.Service, stationChecker auth.StationTenantChecker) (*RuleHandler, error) {
+	if service == nil {
+		return nil, errors.New("alarm rules handler: nil service")
+	}
+	return &RuleHandler{service: service, stationChecker: stationChecker}, nil
+}
+
+type createRuleRequest struct {
+	ID              string  `json:"id"`
+	StationID       string  `json:"station_id"`
+	Name            string  `json:"name"`
+	Semantic        string  `json:"semantic"`
+	Operator        string  `json:"operator"`
+	Threshold       float64 `json:"threshold"`
+	Hysteresis      float64 `json:"hysteresis"`
+	DurationSeconds int     `json:"duration_seconds"`
+	Severity        string  `json:"severity"`
+	Enabled         *bool   `json:"enabled"`
+	NotifyChannel   string  `json:"notify_channel"`
+}
+
+type updateRuleRequest struct {
+	Name            *string  `json:"name"`
+	Operator        *string  `json:"operator"`
+	Threshold       *float64 `json:"threshold"`
+	Hysteresis      *float64 `json:"hysteresis"`
+	DurationSeconds *int     `json:"duration_seconds"`
+	Severity        *string  `json:"severity"`
+	Enabled         *bool    `json:"enabled"`
+	NotifyChannel   *string  `json:"notify_channel"`
+}
+
+// ServeHTTP handles /api/v1/alarm-rules and subroutes:
+//
+//	GET   /api/v1/alarm-rules?station_id=
+//	POST  /api/v1/alarm-rules
+//	GET   /api/v1/alarm-rules/{id}
+//	PATCH /api/v1/alarm-rules/{id}
+//	POST  /api/v1/alarm-rules/{id}/enable|disable
+//	POST  /api/v1/alarm-rules/{id}/test
+//	POST  /api/v1/alarms/rules/{id}/test (alias)
+func (h *RuleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/api/v1/alarms/rules/"); ok {
+		h.serveTestAlias(w, r, rest)
+		return
+	}
+	if r.URL.Path == "/api/v1/alarm-rules" {
+		switch r.Method {
+		case http.MethodGet:
+			h.handleList(w, r)
+		case http.MethodPost:
+			h.handleCreate(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, "/api/v1/alarm-rules/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/alarm-rules/"), "/")
+	id := parts[0]
+	if id == "" || len(parts) > 2 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(parts) == 2 {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		switch parts[1] {
+		case "enable":
+			respondRule(w, func() (*alarms.AlarmRule, error) { return h.service.SetRuleEnabled(r.Context(), id, true) })
+		case "disable":
+			respondRule(w, func() (*alarms.AlarmRule, error) { return h.service.SetRuleEnabled(r.Context(), id, false) })
+		case "test":
+			h.handleTest(w, r, id)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		respondRule(w, func() (*alarms.AlarmRule, error) { return h.service.GetRule(r.Context(), id) })
+	case http.MethodPatch, http.MethodPut:
+		h.handleUpdate(w, r, id)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// serveTestAlias serves /api/v1/alarms/rules/{id}/test, the path the rule
+// test was first published under; no other rule route lives there.
+func (h *RuleHandler) serveTestAlias(w http.ResponseWriter, r *http.Request, rest string) {
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] != "test" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	h.handleTest(w, r, parts[0])
+}
+
+func (h *RuleHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
+		return
+	}
+	if err := ensureStationTenant(r, h.stationChecker, auth.TenantIDFromContext(r.Context()), stationID); err != nil {
+		respondTenantError(w, err)
+		return
+	}
+	list, err := h.service.ListRules(r.Context(), stationID)
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+	if list == nil {
+		list = []alarms.AlarmRule{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func (h *RuleHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	if req.StationID == "" {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
+		return
+	}
+	if err := ensureStationTenant(r, h.stationChecker, auth.TenantIDFromContext(r.Context()), req.StationID); err != nil {
+		respondTenantError(w, err)
+		return
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	rule, err := h.service.CreateRule(r.Context(), alarms.AlarmRule{
+		ID:              req.ID,
+		StationID:       req.StationID,
+		Name:            req.Name,
+		Semantic:        req.Semantic,
+		Operator:        alarms.Operator(req.Operator),
+		Threshold:       req.Threshold,
+		Hysteresis:      req.Hysteresis,
+		DurationSeconds: req.DurationSeconds,
+		Severity:        req.Severity,
+		Enabled:         enabled,
+		NotifyChannel:   req.NotifyChannel,
+	})
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RuleHandler) handleUpdate(w http.ResponseWriter, r *http.Request, id string) {
+	var req updateRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	update := alarmapp.RuleUpdate{
+		Name:            req.Name,
+		Threshold:       req.Threshold,
+		Hysteresis:      req.Hysteresis,
+		DurationSeconds: req.DurationSeconds,
+		Severity:        req.Severity,
+		Enabled:         req.Enabled,
+		NotifyChannel:   req.NotifyChannel,
+	}
+	if req.Operator != nil {
+		op := alarms.Operator(*req.Operator)
+		update.Operator = &op
+	}
+	respondRule(w, func() (*alarms.AlarmRule, error) { return h.service.UpdateRule(r.Context(), id, update) })
+}
+
+func respondRule(w http.ResponseWriter, fn func() (*alarms.AlarmRule, error)) {
+	rule, err := fn()
+	if err != nil {
+		if errors.Is(err, alarms.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+// handleTest handles POST /api/v1/alarm-rules/{id}/test with body
+// {"value": 120, "ts": "2026-01-26T08:00:00Z"}; ts is optional.
+func (h *RuleHandler) handleTest(w http.ResponseWriter, r *http.Request, id string) {
+	var req struct {
+		Value *float64 `json:"value"`
+		TS    string   `json:"ts"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	if req.Value == nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "value is required")
+		return
+	}
+	var at time.Time
+	if req.TS != "" {
+		parsed, err := time.Parse(timeLayout, req.TS)
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "ts must be RFC3339")
+			return
+		}
+		at = parsed
+	}
+	result, err := h.service.TestRule(r.Context(), id, *req.Value, at)
+	if err != nil {
+		if errors.Is(err, alarms.ErrNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
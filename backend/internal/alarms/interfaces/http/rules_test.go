@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarmrepo "microgrid-cloud/internal/alarms/infrastructure/postgres"
+	"microgrid-cloud/internal/auth"
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+)
+
+type stationOwner map[string]string
+
+func (s stationOwner) EnsureStationTenant(_ context.Context, tenantID, stationID string) error {
+	if s[stationID] != tenantID {
+		return auth.ErrTenantMismatch
+	}
+	return nil
+}
+
+func newTestRuleHandler(t *testing.T) *RuleHandler {
+	t.Helper()
+	service, err := alarmapp.NewService(
+		alarmrepo.NewAlarmRuleRepository(nil),
+		alarmrepo.NewAlarmRepository(nil),
+		alarmrepo.NewAlarmRuleStateRepository(nil),
+		masterdatarepo.NewPointMappingRepository(nil),
+		"tenant-a",
+	)
+	if err != nil {
+		t.Fatalf("new service: %v", err)
+	}
+	handler, err := NewRuleHandler(service, stationOwner{"station-a": "tenant-a"})
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	return handler
+}
+
+func TestRuleHandler_RejectsInvalidRequests(t *testing.T) {
+	handler := newTestRuleHandler(t)
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"list without station", http.MethodGet, "/api/v1/alarm-rules", "", http.StatusBadRequest},
+		{"list foreign station", http.MethodGet, "/api/v1/alarm-rules?station_id=station-b", "", http.StatusForbidden},
+		{"create invalid json", http.MethodPost, "/api/v1/alarm-rules", "{", http.StatusBadRequest},
+		{"create without station", http.MethodPost, "/api/v1/alarm-rules", `{"name":"x"}`, http.StatusBadRequest},
+		{"create foreign station", http.MethodPost, "/api/v1/alarm-rules", `{"station_id":"station-b"}`, http.StatusForbidden},
+		{"create invalid operator", http.MethodPost, "/api/v1/alarm-rules", `{"station_id":"station-a","name":"x","semantic":"soc","operator":"=="}`, http.StatusBadRequest},
+		{"collection delete", http.MethodDelete, "/api/v1/alarm-rules", "", http.StatusMethodNotAllowed},
+		{"update invalid json", http.MethodPatch, "/api/v1/alarm-rules/rule-1", "{", http.StatusBadRequest},
+		{"unknown action", http.MethodPost, "/api/v1/alarm-rules/rule-1/archive", "", http.StatusNotFound},
+		{"action via get", http.MethodGet, "/api/v1/alarm-rules/rule-1/enable", "", http.StatusMethodNotAllowed},
+		{"empty id", http.MethodGet, "/api/v1/alarm-rules/", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := auth.WithIdentity(context.Background(), "tenant-a", auth.RoleOperator, "tester")
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)).WithContext(ctx)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("status=%d want=%d body=%s", w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_RuleTestRejectsInvalidRequests(t *testing.T) {
+	handler := newTestRuleHandler(t)
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"missing value", http.MethodPost, "/api/v1/alarm-rules/rule-1/test", `{}`, http.StatusBadRequest},
+		{"bad timestamp", http.MethodPost, "/api/v1/alarm-rules/rule-1/test", `{"value":1,"ts":"yesterday"}`, http.StatusBadRequest},
+		{"get", http.MethodGet, "/api/v1/alarm-rules/rule-1/test", "", http.StatusMethodNotAllowed},
+		{"unknown subroute", http.MethodPost, "/api/v1/alarm-rules/rule-1/fire", `{"value":1}`, http.StatusNotFound},
+		{"alias missing value", http.MethodPost, "/api/v1/alarms/rules/rule-1/test", `{}`, http.StatusBadRequest},
+		{"alias get", http.MethodGet, "/api/v1/alarms/rules/rule-1/test", "", http.StatusMethodNotAllowed},
+		{"alias other route", http.MethodPost, "/api/v1/alarms/rules/rule-1/enable", "", http.StatusNotFound},
+		{"alias rule", http.MethodGet, "/api/v1/alarms/rules/rule-1", "", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Fatalf("status=%d want=%d body=%s", w.Code, tc.want, w.Body.String())
+			}
+		})
+	}
+}
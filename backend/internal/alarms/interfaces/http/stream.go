@@ -4,20 +4,98 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"sync"
+	"time"
 
 	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/observability/metrics"
 )
 
-// SSEBroker fans out alarm events to connected clients.
+const (
+	defaultReplayBuffer      = 256
+	defaultClientBuffer      = 16
+	defaultHeartbeatInterval = 15 * time.Second
+)
+
+// StreamEvent is a sequenced alarm event payload.
+type StreamEvent struct {
+	ID        uint64
+	TenantID  string
+	StationID string
+	Severity  string
+	Payload   []byte
+}
+
+// StreamFilter selects the events delivered to one subscriber. Empty fields match everything.
+type StreamFilter struct {
+	TenantID    string
+	StationID   string
+	MinSeverity string
+}
+
+// Matches reports whether evt passes the filter.
+func (f StreamFilter) Matches(evt StreamEvent) bool {
+	if f.TenantID != "" && evt.TenantID != f.TenantID {
+		return false
+	}
+	if f.StationID != "" && evt.StationID != f.StationID {
+		return false
+	}
+	if f.MinSeverity != "" && alarms.SeverityRank(evt.Severity) < alarms.SeverityRank(f.MinSeverity) {
+		return false
+	}
+	return true
+}
+
+// SSEBroker fans out alarm events to connected clients and keeps a small
+// buffer of recent events so reconnecting clients can resume. A client whose
+// buffer is full is evicted and its channel closed, so one slow reader never
+// holds back the others; it can reconnect with Last-Event-ID to catch up.
 type SSEBroker struct {
-	mu      sync.Mutex
-	clients map[chan []byte]struct{}
+	mu           sync.Mutex
+	clients      map[chan StreamEvent]StreamFilter
+	seq          uint64
+	recent       []StreamEvent
+	limit        int
+	clientBuffer int
+}
+
+// BrokerOption customizes the SSE broker.
+type BrokerOption func(*SSEBroker)
+
+// WithReplayBuffer sets how many recent events are kept for Last-Event-ID resume.
+func WithReplayBuffer(size int) BrokerOption {
+	return func(b *SSEBroker) {
+		if size >= 0 {
+			b.limit = size
+		}
+	}
+}
+
+// WithClientBuffer sets how many undelivered events a client may queue before it is evicted.
+func WithClientBuffer(size int) BrokerOption {
+	return func(b *SSEBroker) {
+		if size > 0 {
+			b.clientBuffer = size
+		}
+	}
 }
 
 // NewSSEBroker constructs a broker.
-func NewSSEBroker() *SSEBroker {
-	return &SSEBroker{clients: make(map[chan []byte]struct{})}
+func NewSSEBroker(opts ...BrokerOption) *SSEBroker {
+	b := &SSEBroker{
+		clients:      make(map[chan StreamEvent]StreamFilter),
+		limit:        defaultReplayBuffer,
+		clientBuffer: defaultClientBuffer,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
 // Notify implements AlarmNotifier.
@@ -29,71 +107,138 @@ func (b *SSEBroker) Notify(_ context.Context, event alarmapp.AlarmEvent) {
 	if err != nil {
 		return
 	}
-	b.broadcast(payload)
+	b.broadcast(StreamEvent{
+		TenantID:  event.Alarm.TenantID,
+		StationID: event.Alarm.StationID,
+		Severity:  event.Severity,
+		Payload:   payload,
+	})
 }
 
-// Subscribe registers a new client channel.
-func (b *SSEBroker) Subscribe() chan []byte {
+// Subscribe registers a new client channel that only receives events matching
+// filter, and returns matching buffered events newer than lastEventID (0 means
+// none). Registration and replay happen atomically so no event is lost between them.
+func (b *SSEBroker) Subscribe(filter StreamFilter, lastEventID uint64) (chan StreamEvent, []StreamEvent) {
 	if b == nil {
-		return nil
+		return nil, nil
 	}
-	ch := make(chan []byte, 16)
 	b.mu.Lock()
-	b.clients[ch] = struct{}{}
-	b.mu.Unlock()
-	return ch
+	defer b.mu.Unlock()
+	ch := make(chan StreamEvent, b.clientBuffer)
+	b.clients[ch] = filter
+	if lastEventID == 0 || lastEventID > b.seq {
+		return ch, nil
+	}
+	var missed []StreamEvent
+	for _, evt := range b.recent {
+		if evt.ID > lastEventID && filter.Matches(evt) {
+			missed = append(missed, evt)
+		}
+	}
+	return ch, missed
 }
 
-// Unsubscribe removes a client channel.
-func (b *SSEBroker) Unsubscribe(ch chan []byte) {
+// Unsubscribe removes a client channel. Channels already closed by eviction are left alone.
+func (b *SSEBroker) Unsubscribe(ch chan StreamEvent) {
 	if b == nil || ch == nil {
 		return
 	}
 	b.mu.Lock()
-	delete(b.clients, ch)
-	b.mu.Unlock()
-	close(ch)
+	defer b.mu.Unlock()
+	if _, ok := b.clients[ch]; ok {
+		delete(b.clients, ch)
+		close(ch)
+	}
 }
 
-func (b *SSEBroker) broadcast(payload []byte) {
+func (b *SSEBroker) broadcast(evt StreamEvent) {
 	b.mu.Lock()
-	clients := make([]chan []byte, 0, len(b.clients))
-	for ch := range b.clients {
-		clients = append(clients, ch)
+	b.seq++
+	evt.ID = b.seq
+	if b.limit > 0 {
+		if len(b.recent) >= b.limit {
+			b.recent = append(b.recent[:0], b.recent[len(b.recent)-b.limit+1:]...)
+		}
+		b.recent = append(b.recent, evt)
 	}
-	b.mu.Unlock()
-	for _, ch := range clients {
+	// Sends never block, so delivering under the lock is cheap and keeps
+	// eviction from racing with Unsubscribe.
+	for ch, filter := range b.clients {
+		if !filter.Matches(evt) {
+			continue
+		}
 		select {
-		case ch <- payload:
+		case ch <- evt:
 		default:
+			delete(b.clients, ch)
+			close(ch)
+			metrics.IncAlarmStreamEviction()
 		}
 	}
+	b.mu.Unlock()
 }
 
 // StreamHandler serves SSE alarm stream.
 type StreamHandler struct {
-	broker *SSEBroker
+	broker         *SSEBroker
+	stationChecker auth.StationTenantChecker
+	heartbeat      time.Duration
+}
+
+// StreamOption customizes the stream handler.
+type StreamOption func(*StreamHandler)
+
+// WithHeartbeat sets the interval of keep-alive comments; zero disables them.
+func WithHeartbeat(interval time.Duration) StreamOption {
+	return func(h *StreamHandler) {
+		if interval >= 0 {
+			h.heartbeat = interval
+		}
+	}
 }
 
 // NewStreamHandler constructs a stream handler.
-func NewStreamHandler(broker *SSEBroker) *StreamHandler {
-	return &StreamHandler{broker: broker}
+func NewStreamHandler(broker *SSEBroker, stationChecker auth.StationTenantChecker, opts ...StreamOption) *StreamHandler {
+	h := &StreamHandler{broker: broker, stationChecker: stationChecker, heartbeat: defaultHeartbeatInterval}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// ServeHTTP handles GET /api/v1/alarms/stream.
+// ServeHTTP handles GET /api/v1/alarms/stream?station_id=&min_severity=.
 func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 	if h == nil || h.broker == nil {
-		http.Error(w, "stream not ready", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "stream not ready")
 		return
 	}
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
-		http.Error(w, "stream unsupported", http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "stream unsupported")
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid Last-Event-ID")
+		return
+	}
+	filter := StreamFilter{
+		TenantID:    auth.TenantIDFromContext(r.Context()),
+		StationID:   r.URL.Query().Get("station_id"),
+		MinSeverity: r.URL.Query().Get("min_severity"),
+	}
+	if filter.MinSeverity != "" && alarms.SeverityRank(filter.MinSeverity) == 0 {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "min_severity must be one of low, medium, high, critical")
+		return
+	}
+	if err := ensureStationTenant(r, h.stationChecker, filter.TenantID, filter.StationID); err != nil {
+		respondTenantError(w, err)
 		return
 	}
 
@@ -101,30 +246,61 @@ func (h *StreamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
 
-	ch := h.broker.Subscribe()
+	ch, missed := h.broker.Subscribe(filter, lastEventID)
 	if ch == nil {
-		http.Error(w, "stream not ready", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "stream not ready")
 		return
 	}
 	defer h.broker.Unsubscribe(ch)
 
 	_, _ = w.Write([]byte("event: ready\ndata: {}\n\n"))
+	for _, evt := range missed {
+		writeAlarmEvent(w, evt)
+	}
 	flusher.Flush()
 
+	var heartbeat <-chan time.Time
+	if h.heartbeat > 0 {
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
 	notify := r.Context().Done()
 	for {
 		select {
-		case payload, ok := <-ch:
+		case evt, ok := <-ch:
 			if !ok {
 				return
 			}
-			_, _ = w.Write([]byte("event: alarm\n"))
-			_, _ = w.Write([]byte("data: "))
-			_, _ = w.Write(payload)
-			_, _ = w.Write([]byte("\n\n"))
+			writeAlarmEvent(w, evt)
+			flusher.Flush()
+		case <-heartbeat:
+			_, _ = w.Write([]byte(": heartbeat\n\n"))
 			flusher.Flush()
 		case <-notify:
 			return
 		}
 	}
 }
+
+func writeAlarmEvent(w http.ResponseWriter, evt StreamEvent) {
+	_, _ = w.Write([]byte("id: " + strconv.FormatUint(evt.ID, 10) + "\n"))
+	_, _ = w.Write([]byte("event: alarm\n"))
+	_, _ = w.Write([]byte("data: "))
+	_, _ = w.Write(evt.Payload)
+	_, _ = w.Write([]byte("\n\n"))
+}
+
+// parseLastEventID reads the resume position from the Last-Event-ID header,
+// falling back to the last_event_id query param for clients that cannot set headers.
+func parseLastEventID(r *http.Request) (uint64, error) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/auth"
+)
+
+func TestStreamHandler_EmitsHeartbeats(t *testing.T) {
+	broker := NewSSEBroker()
+	server := httptest.NewServer(NewStreamHandler(broker, nil, WithHeartbeat(20*time.Millisecond)))
+	t.Cleanup(server.Close)
+
+	lines := openStream(t, server.URL, "")
+	if !waitForLine(lines, func(line string) bool { return line == ": heartbeat" }) {
+		t.Fatalf("expected heartbeat comment")
+	}
+}
+
+func TestStreamHandler_ResumesFromLastEventID(t *testing.T) {
+	broker := NewSSEBroker(WithReplayBuffer(2))
+	for _, id := range []string{"alarm-1", "alarm-2", "alarm-3"} {
+		broker.Notify(context.Background(), alarmapp.AlarmEvent{Type: "created", Alarm: alarms.Alarm{ID: id}})
+	}
+	server := httptest.NewServer(NewStreamHandler(broker, nil, WithHeartbeat(0)))
+	t.Cleanup(server.Close)
+
+	lines := openStream(t, server.URL, "2")
+	var ids []string
+	waitForLine(lines, func(line string) bool {
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimPrefix(line, "id: "))
+		}
+		return strings.Contains(line, "alarm-3")
+	})
+	if len(ids) != 1 || ids[0] != "3" {
+		t.Fatalf("expected only event 3 to be replayed, got %v", ids)
+	}
+
+	// Resuming from 1 replays everything still buffered after it.
+	lines = openStream(t, server.URL, "1")
+	ids = nil
+	waitForLine(lines, func(line string) bool {
+		if strings.HasPrefix(line, "id: ") {
+			ids = append(ids, strings.TrimPrefix(line, "id: "))
+		}
+		return strings.Contains(line, "alarm-3")
+	})
+	if strings.Join(ids, ",") != "2,3" {
+		t.Fatalf("expected events 2,3 to be replayed, got %v", ids)
+	}
+}
+
+func TestStreamHandler_FiltersPerSubscriber(t *testing.T) {
+	broker := NewSSEBroker()
+	stream := NewStreamHandler(broker, nil, WithHeartbeat(0))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := auth.WithIdentity(r.Context(), "tenant-a", auth.RoleViewer, "tester")
+		stream.ServeHTTP(w, r.WithContext(ctx))
+	}))
+	t.Cleanup(server.Close)
+
+	stationSub := openStream(t, server.URL+"?station_id=station-1", "")
+	severitySub := openStream(t, server.URL+"?min_severity=high", "")
+	for _, lines := range []<-chan string{stationSub, severitySub} {
+		if !waitForLine(lines, func(line string) bool { return line == "event: ready" }) {
+			t.Fatalf("expected ready event")
+		}
+	}
+
+	events := []alarmapp.AlarmEvent{
+		{Type: "active", Severity: "low", Alarm: alarms.Alarm{ID: "a-station1-low", TenantID: "tenant-a", StationID: "station-1"}},
+		{Type: "active", Severity: "critical", Alarm: alarms.Alarm{ID: "a-station2-critical", TenantID: "tenant-a", StationID: "station-2"}},
+		{Type: "active", Severity: "critical", Alarm: alarms.Alarm{ID: "b-station1-critical", TenantID: "tenant-b", StationID: "station-1"}},
+		{Type: "active", Severity: "high", Alarm: alarms.Alarm{ID: "a-station1-high", TenantID: "tenant-a", StationID: "station-1"}},
+	}
+	for _, evt := range events {
+		broker.Notify(context.Background(), evt)
+	}
+
+	cases := []struct {
+		name  string
+		lines <-chan string
+		want  string
+	}{
+		{name: "station", lines: stationSub, want: "a-station1-low,a-station1-high"},
+		{name: "severity", lines: severitySub, want: "a-station2-critical,a-station1-high"},
+	}
+	for _, tc := range cases {
+		var got []string
+		waitForLine(tc.lines, func(line string) bool {
+			for _, evt := range events {
+				if strings.HasPrefix(line, "data: ") && strings.Contains(line, `"`+evt.Alarm.ID+`"`) {
+					got = append(got, evt.Alarm.ID)
+				}
+			}
+			return strings.Contains(line, "a-station1-high")
+		})
+		if strings.Join(got, ",") != tc.want {
+			t.Fatalf("%s subscriber got %v, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestStreamHandler_RejectsUnknownSeverity(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewStreamHandler(NewSSEBroker(), nil).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/alarms/stream?min_severity=urgent", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+}
+
+func openStream(t *testing.T, url, lastEventID string) <-chan string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status=%d", resp.StatusCode)
+	}
+	lines := make(chan string)
+	go func() {
+		defer resp.Body.Close()
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return lines
+}
+
+func waitForLine(lines <-chan string, match func(string) bool) bool {
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return false
+			}
+			if match(line) {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func TestSSEBroker_EvictsSlowClientWithoutStallingOthers(t *testing.T) {
+	broker := NewSSEBroker(WithClientBuffer(4))
+	blocked, _ := broker.Subscribe(StreamFilter{}, 0)
+	healthy, _ := broker.Subscribe(StreamFilter{}, 0)
+	defer broker.Unsubscribe(healthy)
+
+	for i := 0; i < 10; i++ {
+		broker.Notify(context.Background(), alarmapp.AlarmEvent{Type: "created", Alarm: alarms.Alarm{ID: "alarm"}})
+		select {
+		case evt := <-healthy:
+			if evt.ID != uint64(i+1) {
+				t.Fatalf("healthy client got event %d, want %d", evt.ID, i+1)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("healthy client stalled at event %d", i+1)
+		}
+	}
+
+	// The blocked client keeps what fit in its buffer, then sees its stream closed.
+	var received int
+	for range blocked {
+		received++
+	}
+	if received != 4 {
+		t.Fatalf("evicted client received %d buffered events, want 4", received)
+	}
+	broker.Unsubscribe(blocked)
+}
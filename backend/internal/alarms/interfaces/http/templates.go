@@ -0,0 +1,153 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+// TemplateHandler provides alarm rule template endpoints.
+type TemplateHandler struct {
+	service        *alarmapp.Service
+	stationChecker auth.StationTenantChecker
+}
+
+// NewTemplateHandler constructs a template handler.
+func NewTemplateHandler(service *alarmapp.Service, stationChecker auth.StationTenantChecker) (*TemplateHandler, error) {
+	if service == nil {
+		return nil, errors.New("alarm rule templates handler: nil service")
+	}
+	return &TemplateHandler{service: service, stationChecker: stationChecker}, nil
+}
+
+type createTemplateRequest struct {
+	ID              string  `json:"id"`
+	StationType     string  `json:"station_type"`
+	Name            string  `json:"name"`
+	Semantic        string  `json:"semantic"`
+	Operator        string  `json:"operator"`
+	Threshold       float64 `json:"threshold"`
+	Hysteresis      float64 `json:"hysteresis"`
+	DurationSeconds int     `json:"duration_seconds"`
+	Severity        string  `json:"severity"`
+	NotifyChannel   string  `json:"notify_channel"`
+}
+
+type applyTemplatesRequest struct {
+	StationID          string             `json:"station_id"`
+	ThresholdOverrides map[string]float64 `json:"threshold_overrides"`
+}
+
+// ServeHTTP handles:
+//
+//	GET  /api/v1/alarm-rule-templates?station_type=
+//	POST /api/v1/alarm-rule-templates
+//	POST /api/v1/alarm-rule-templates/apply
+func (h *TemplateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/api/v1/alarm-rule-templates":
+		switch r.Method {
+		case http.MethodGet:
+			h.handleList(w, r)
+		case http.MethodPost:
+			h.handleCreate(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	case "/api/v1/alarm-rule-templates/apply":
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		h.handleApply(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (h *TemplateHandler) handleList(w http.ResponseWriter, r *http.Request) {
+	list, err := h.service.ListTemplates(r.Context(), r.URL.Query().Get("station_type"))
+	if err != nil {
+		respondTemplateError(w, err)
+		return
+	}
+	if list == nil {
+		list = []alarms.AlarmRuleTemplate{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+func (h *TemplateHandler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req createTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	template, err := h.service.CreateTemplate(r.Context(), alarms.AlarmRuleTemplate{
+		ID:              req.ID,
+		StationType:     req.StationType,
+		Name:            req.Name,
+		Semantic:        req.Semantic,
+		Operator:        alarms.Operator(req.Operator),
+		Threshold:       req.Threshold,
+		Hysteresis:      req.Hysteresis,
+		DurationSeconds: req.DurationSeconds,
+		Severity:        req.Severity,
+		NotifyChannel:   req.NotifyChannel,
+	})
+	if err != nil {
+		respondTemplateError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(template)
+}
+
+func (h *TemplateHandler) handleApply(w http.ResponseWriter, r *http.Request) {
+	var req applyTemplatesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	if req.StationID == "" {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
+		return
+	}
+	if err := ensureStationTenant(r, h.stationChecker, auth.TenantIDFromContext(r.Context()), req.StationID); err != nil {
+		respondTenantError(w, err)
+		return
+	}
+	created, err := h.service.ApplyTemplatesToStation(r.Context(), req.StationID, req.ThresholdOverrides)
+	if err != nil {
+		respondTemplateError(w, err)
+		return
+	}
+	if created == nil {
+		created = []alarms.AlarmRule{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"station_id": req.StationID,
+		"created":    created,
+	})
+}
+
+func respondTemplateError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, alarmapp.ErrTemplatesDisabled):
+		apierror.Write(w, http.StatusNotImplemented, apierror.CodeNotImplemented, err.Error())
+	case errors.Is(err, alarms.ErrNotFound):
+		w.WriteHeader(http.StatusNotFound)
+	case errors.Is(err, auth.ErrTenantMismatch):
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
+	default:
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+	}
+}
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// digestImmediateSeverity is the lowest severity that bypasses the digest.
+const digestImmediateSeverity = "critical"
+
+// WithDigest buffers events of non-critical alarms and sends them as one
+// summarized notification every interval. Critical alarms and escalations are
+// still sent immediately. Zero disables the digest.
+func WithDigest(interval time.Duration) Option {
+	return func(n *Notifier) {
+		if interval > 0 {
+			n.digestInterval = interval
+		}
+	}
+}
+
+// WithDigestTemplate overrides the digest template.
+func WithDigestTemplate(template *Template) Option {
+	return func(n *Notifier) {
+		if template != nil {
+			n.digestTemplate = template
+		}
+	}
+}
+
+// digested reports whether an event for rule goes into the digest. Events
+// whose rule cannot be loaded are sent immediately.
+func (n *Notifier) digested(rule *alarms.AlarmRule) bool {
+	if n.digestInterval <= 0 || rule == nil {
+		return false
+	}
+	return !severityAtLeast(rule.Severity, digestImmediateSeverity)
+}
+
+// bufferDigest queues an event and arms the flush timer on the first one.
+func (n *Notifier) bufferDigest(ctx context.Context, eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station) {
+	reportURL := ""
+	if n.reportURL != nil {
+		reportURL = n.reportURL(ctx, alarm, rule, station)
+	}
+	item := buildTemplateData(eventType, alarm, rule, station, reportURL, n.clock.Now())
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.digest = append(n.digest, item)
+	if n.digestTimer == nil {
+		n.digestSince = n.clock.Now().UTC()
+		n.digestTimer = n.scheduler().AfterFunc(n.digestInterval, n.flushDigest)
+	}
+}
+
+// flushDigest sends all buffered events as one notification.
+func (n *Notifier) flushDigest() {
+	n.mu.Lock()
+	items := n.digest
+	since := n.digestSince
+	n.digest = nil
+	if n.digestTimer != nil {
+		n.digestTimer.Stop()
+		n.digestTimer = nil
+	}
+	n.mu.Unlock()
+	if len(items) == 0 {
+		return
+	}
+
+	tpl := n.digestTemplate
+	if tpl == nil {
+		var err error
+		if tpl, err = NewDigestTemplate(""); err != nil {
+			return
+		}
+	}
+	content, err := tpl.RenderDigest(DigestData{
+		Count: len(items),
+		From:  since.Format(time.RFC3339),
+		To:    n.clock.Now().UTC().Format(time.RFC3339),
+		Items: items,
+	})
+	if err != nil {
+		return
+	}
+
+	ctx := context.Background()
+	if n.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.requestTimeout)
+		defer cancel()
+	}
+	if err := n.channel.Send(ctx, content); err != nil {
+		n.enqueueRetry(ctx, "", defaultChannelName, content, nil, err)
+	}
+}
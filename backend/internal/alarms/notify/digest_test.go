@@ -0,0 +1,78 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+type ruleMapRepo map[string]*alarms.AlarmRule
+
+func (r ruleMapRepo) GetByID(_ context.Context, _ string, ruleID string) (*alarms.AlarmRule, error) {
+	return r[ruleID], nil
+}
+
+func TestNotifierDigest_BatchesNonCriticalEvents(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 27, 9, 0, 0, 0, time.UTC))
+	channel := &recordingChannel{}
+	rules := ruleMapRepo{
+		"rule-low":  {ID: "rule-low", Name: "SOC Low", Operator: alarms.OperatorLess, Threshold: 20, Severity: "low"},
+		"rule-med":  {ID: "rule-med", Name: "Temp High", Operator: alarms.OperatorGreater, Threshold: 45, Severity: "medium"},
+		"rule-crit": {ID: "rule-crit", Name: "Grid Trip", Operator: alarms.OperatorGreater, Threshold: 1, Severity: "critical"},
+	}
+	notifier, err := NewNotifier(
+		rules,
+		stubStationRepo{station: &masterdata.Station{ID: "station-1", Name: "Station A"}},
+		stubAlarmRepo{},
+		channel,
+		nil,
+		WithClock(clk),
+		WithDigest(15*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	event := func(id, ruleID, eventType string, value float64) alarmapp.AlarmEvent {
+		return alarmapp.AlarmEvent{Type: eventType, Alarm: alarms.Alarm{ID: id, TenantID: "tenant-1", StationID: "station-1", RuleID: ruleID, Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: value}}
+	}
+
+	notifier.Notify(context.Background(), event("alarm-1", "rule-low", "active", 15))
+	clk.Advance(2 * time.Minute)
+	notifier.Notify(context.Background(), event("alarm-2", "rule-med", "active", 50))
+	clk.Advance(3 * time.Minute)
+	notifier.Notify(context.Background(), event("alarm-1", "rule-low", "cleared", 25))
+	if channel.Count() != 0 {
+		t.Fatalf("expected non-critical events to be buffered, sent=%d", channel.Count())
+	}
+
+	notifier.Notify(context.Background(), event("alarm-3", "rule-crit", "active", 2))
+	if channel.Count() != 1 || !strings.Contains(channel.Latest(), "Grid Trip") {
+		t.Fatalf("expected critical alarm sent immediately, sent=%d", channel.Count())
+	}
+
+	clk.Advance(10 * time.Minute)
+	if channel.Count() != 2 {
+		t.Fatalf("expected one digest after interval, sent=%d", channel.Count())
+	}
+	digest := channel.Latest()
+	if !strings.HasPrefix(digest, "[Alarm Digest] 3 events") || !strings.Contains(digest, "SOC Low") || !strings.Contains(digest, "Temp High") || strings.Contains(digest, "Grid Trip") {
+		t.Fatalf("unexpected digest: %s", digest)
+	}
+
+	clk.Advance(30 * time.Minute)
+	if channel.Count() != 2 {
+		t.Fatalf("expected no digest without new events, sent=%d", channel.Count())
+	}
+
+	notifier.Notify(context.Background(), event("alarm-4", "rule-med", "active", 60))
+	notifier.Close()
+	if channel.Count() != 3 || !strings.HasPrefix(channel.Latest(), "[Alarm Digest] 1 events") {
+		t.Fatalf("expected close to flush buffered digest, sent=%d latest=%s", channel.Count(), channel.Latest())
+	}
+}
@@ -0,0 +1,183 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
+)
+
+const defaultEscalationSeverity = "high"
+
+// EscalationStage is one step of an escalation chain, measured from the time
+// the alarm went active.
+type EscalationStage struct {
+	After       time.Duration
+	Channel     Channel // nil uses the notifier channel
+	MinSeverity string  // empty means "high"
+}
+
+// WithEscalationChain configures escalation stages, fired in order of their
+// delay whatever order they are given in. It takes precedence over
+// WithEscalation.
+func WithEscalationChain(stages ...EscalationStage) Option {
+	return func(n *Notifier) {
+		n.stages = nil
+		for _, stage := range stages {
+			if stage.After > 0 {
+				n.stages = append(n.stages, stage)
+			}
+		}
+		sort.SliceStable(n.stages, func(i, j int) bool { return n.stages[i].After < n.stages[j].After })
+	}
+}
+
+// ParseEscalationChain parses "delay[,min_severity[,webhook_url]];..." into
+// stages, e.g. "5m,high;15m,critical,https://hooks.example.com/manager".
+// Stages without a URL use the notifier channel.
+func ParseEscalationChain(spec string, opts ...WebhookOption) ([]EscalationStage, error) {
+	var stages []EscalationStage
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ",", 3)
+		after, err := time.ParseDuration(strings.TrimSpace(parts[0]))
+		if err != nil || after <= 0 {
+			return nil, fmt.Errorf("escalation chain: invalid delay in %q", entry)
+		}
+		stage := EscalationStage{After: after}
+		if len(parts) > 1 {
+			stage.MinSeverity = strings.TrimSpace(parts[1])
+			if stage.MinSeverity != "" && alarms.SeverityRank(stage.MinSeverity) == 0 {
+				return nil, fmt.Errorf("escalation chain: invalid severity in %q", entry)
+			}
+		}
+		if len(parts) > 2 {
+			channel, err := NewWebhookChannel(strings.TrimSpace(parts[2]), opts...)
+			if err != nil {
+				return nil, err
+			}
+			stage.Channel = channel
+		}
+		stages = append(stages, stage)
+	}
+	return stages, nil
+}
+
+func (n *Notifier) escalationStages() []EscalationStage {
+	if len(n.stages) > 0 {
+		return n.stages
+	}
+	if n.escalation > 0 {
+		return []EscalationStage{{After: n.escalation}}
+	}
+	return nil
+}
+
+func (n *Notifier) scheduler() clock.Scheduler {
+	if scheduler, ok := n.clock.(clock.Scheduler); ok {
+		return scheduler
+	}
+	return clock.System{}
+}
+
+func (n *Notifier) scheduleEscalation(alarm alarms.Alarm, rule *alarms.AlarmRule) {
+	if n == nil || alarm.ID == "" || rule == nil {
+		return
+	}
+	stages := n.escalationStages()
+	if len(stages) == 0 {
+		return
+	}
+	n.cancelEscalation(alarm.ID)
+
+	var eligible []int
+	for i, stage := range stages {
+		if severityAtLeast(rule.Severity, stageSeverity(stage)) {
+			eligible = append(eligible, i)
+		}
+	}
+	if len(eligible) == 0 {
+		return
+	}
+
+	scheduler := n.scheduler()
+	timers := make([]clock.Timer, 0, len(eligible))
+	for i, idx := range eligible {
+		idx, final := idx, i == len(eligible)-1
+		timers = append(timers, scheduler.AfterFunc(stages[idx].After, func() {
+			n.runEscalation(alarm.ID, idx, final)
+		}))
+	}
+	n.mu.Lock()
+	n.timers[alarm.ID] = timers
+	n.mu.Unlock()
+}
+
+func (n *Notifier) cancelEscalation(alarmID string) {
+	if n == nil || alarmID == "" {
+		return
+	}
+	n.mu.Lock()
+	timers := n.timers[alarmID]
+	delete(n.timers, alarmID)
+	n.mu.Unlock()
+	for _, timer := range timers {
+		timer.Stop()
+	}
+}
+
+// runEscalation fires one stage; final marks the last stage scheduled for the
+// alarm, after which its timers are dropped.
+func (n *Notifier) runEscalation(alarmID string, stageIdx int, final bool) {
+	if n == nil || alarmID == "" {
+		return
+	}
+	stages := n.escalationStages()
+	if stageIdx >= len(stages) {
+		return
+	}
+	stage := stages[stageIdx]
+	if final {
+		n.mu.Lock()
+		delete(n.timers, alarmID)
+		n.mu.Unlock()
+	}
+
+	ctx := context.Background()
+	if n.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.requestTimeout)
+		defer cancel()
+	}
+
+	alarm, err := n.alarms.GetByID(ctx, alarmID)
+	if err != nil || alarm == nil {
+		return
+	}
+	if alarm.Status == alarms.StatusCleared || alarm.Status == alarms.StatusAcknowledged {
+		return
+	}
+	rule, station := n.lookup(ctx, *alarm)
+	if rule == nil || !severityAtLeast(rule.Severity, stageSeverity(stage)) {
+		return
+	}
+	channelName := defaultChannelName
+	if stage.Channel != nil {
+		channelName = escalationChannelName(stageIdx)
+	}
+	n.dispatch(ctx, channelName, fmt.Sprintf("escalated#%d", stageIdx), "escalated", *alarm, rule, station)
+}
+
+func stageSeverity(stage EscalationStage) string {
+	if stage.MinSeverity == "" {
+		return defaultEscalationSeverity
+	}
+	return stage.MinSeverity
+}
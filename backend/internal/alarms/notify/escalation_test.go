@@ -0,0 +1,169 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+func newChainNotifier(t *testing.T, clk *clock.Fixed, alarm *alarms.Alarm, team, manager *recordingChannel) *Notifier {
+	t.Helper()
+	rule := &alarms.AlarmRule{ID: alarm.RuleID, Name: "Rule", Operator: alarms.OperatorGreater, Threshold: 10, Severity: "critical"}
+	notifier, err := NewNotifier(
+		stubRuleRepo{rule: rule},
+		stubStationRepo{station: &masterdata.Station{ID: alarm.StationID, Name: "Station A"}},
+		stubAlarmRepo{alarm: alarm},
+		team,
+		nil,
+		WithClock(clk),
+		WithEscalationChain(
+			EscalationStage{After: 5 * time.Minute},
+			EscalationStage{After: 15 * time.Minute, Channel: manager, MinSeverity: "critical"},
+		),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	return notifier
+}
+
+func TestNotifierEscalationChain_FiresStagesInOrder(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 26, 12, 0, 0, 0, time.UTC))
+	team, manager := &recordingChannel{}, &recordingChannel{}
+	alarm := &alarms.Alarm{ID: "alarm-chain-1", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-chain", Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 12}
+	notifier := newChainNotifier(t, clk, alarm, team, manager)
+
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	if team.Count() != 1 || manager.Count() != 0 {
+		t.Fatalf("after active: team=%d manager=%d", team.Count(), manager.Count())
+	}
+
+	clk.Advance(5 * time.Minute)
+	if team.Count() != 2 || !strings.Contains(team.Latest(), "Escalated") || manager.Count() != 0 {
+		t.Fatalf("after stage 1: team=%d manager=%d", team.Count(), manager.Count())
+	}
+
+	clk.Advance(10 * time.Minute)
+	if team.Count() != 2 || manager.Count() != 1 || !strings.Contains(manager.Latest(), "Escalated") {
+		t.Fatalf("after stage 2: team=%d manager=%d", team.Count(), manager.Count())
+	}
+}
+
+func TestNotifierEscalationChain_ClearCancelsRemainingStages(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 26, 13, 0, 0, 0, time.UTC))
+	team, manager := &recordingChannel{}, &recordingChannel{}
+	alarm := &alarms.Alarm{ID: "alarm-chain-2", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-chain", Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 12}
+	notifier := newChainNotifier(t, clk, alarm, team, manager)
+
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	clk.Advance(6 * time.Minute)
+	if team.Count() != 2 {
+		t.Fatalf("expected stage 1 escalation, team=%d", team.Count())
+	}
+
+	alarm.Status = alarms.StatusCleared
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "cleared", Alarm: *alarm})
+	clk.Advance(30 * time.Minute)
+	if manager.Count() != 0 {
+		t.Fatalf("expected no stage 2 escalation after clear, manager=%d", manager.Count())
+	}
+	if team.Count() != 3 {
+		t.Fatalf("expected active, escalated and cleared notifications, team=%d", team.Count())
+	}
+}
+
+func TestNotifierEscalationChain_SortsStagesAndDropsTimers(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 26, 14, 0, 0, 0, time.UTC))
+	team, manager := &recordingChannel{}, &recordingChannel{}
+	alarm := &alarms.Alarm{ID: "alarm-chain-3", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-chain", Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 12}
+	rule := &alarms.AlarmRule{ID: alarm.RuleID, Name: "Rule", Operator: alarms.OperatorGreater, Threshold: 10, Severity: "high"}
+	notifier, err := NewNotifier(
+		stubRuleRepo{rule: rule},
+		stubStationRepo{station: &masterdata.Station{ID: alarm.StationID, Name: "Station A"}},
+		stubAlarmRepo{alarm: alarm},
+		team,
+		nil,
+		WithClock(clk),
+		// Out of order, and the critical-only stage is skipped for a high alarm.
+		WithEscalationChain(
+			EscalationStage{After: 30 * time.Minute, Channel: manager, MinSeverity: "critical"},
+			EscalationStage{After: 10 * time.Minute, Channel: manager},
+			EscalationStage{After: 5 * time.Minute},
+		),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	clk.Advance(5 * time.Minute)
+	if team.Count() != 2 || manager.Count() != 0 {
+		t.Fatalf("after 5m: team=%d manager=%d, want the 5m stage on the team channel", team.Count(), manager.Count())
+	}
+	clk.Advance(5 * time.Minute)
+	if manager.Count() != 1 {
+		t.Fatalf("after 10m: manager=%d, want the 10m stage", manager.Count())
+	}
+
+	notifier.mu.Lock()
+	_, pending := notifier.timers[alarm.ID]
+	notifier.mu.Unlock()
+	if pending {
+		t.Fatalf("timers kept after the last eligible stage fired")
+	}
+	clk.Advance(30 * time.Minute)
+	if manager.Count() != 1 {
+		t.Fatalf("critical-only stage fired for a high alarm, manager=%d", manager.Count())
+	}
+}
+
+func TestParseEscalationChain(t *testing.T) {
+	stages, err := ParseEscalationChain("5m,high; 15m,critical,http://example.com/manager")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(stages) != 2 || stages[0].After != 5*time.Minute || stages[0].Channel != nil ||
+		stages[1].MinSeverity != "critical" || stages[1].Channel == nil {
+		t.Fatalf("unexpected stages: %+v", stages)
+	}
+	for _, spec := range []string{"soon", "5m,urgent", "-1m"} {
+		if _, err := ParseEscalationChain(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestNotifierEscalation_AckStopsEscalation(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 26, 14, 0, 0, 0, time.UTC))
+	team, manager := &recordingChannel{}, &recordingChannel{}
+	alarm := &alarms.Alarm{ID: "alarm-ack-1", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-chain", Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 12}
+	notifier := newChainNotifier(t, clk, alarm, team, manager)
+
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	clk.Advance(time.Minute)
+	alarm.Status = alarms.StatusAcknowledged
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "acknowledged", Alarm: *alarm})
+
+	clk.Advance(time.Hour)
+	if strings.Contains(team.Latest(), "Escalated") || manager.Count() != 0 {
+		t.Fatalf("expected no escalation after ack: team=%d manager=%d", team.Count(), manager.Count())
+	}
+	if team.Count() != 2 {
+		t.Fatalf("expected active and acknowledged notifications, got %d", team.Count())
+	}
+
+	// Re-activation re-arms the chain.
+	alarm.Status = alarms.StatusActive
+	alarm.LastValue = 20
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	clk.Advance(5 * time.Minute)
+	if !strings.Contains(team.Latest(), "Escalated") {
+		t.Fatalf("expected escalation after re-activation, got %s", team.Latest())
+	}
+}
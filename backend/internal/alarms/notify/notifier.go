@@ -12,6 +12,7 @@ import (
 
 	alarmapp "microgrid-cloud/internal/alarms/application"
 	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
 	masterdata "microgrid-cloud/internal/masterdata/domain"
 )
 
@@ -31,9 +32,7 @@ type AlarmReader interface {
 }
 
 // Clock provides time for scheduling.
-type Clock interface {
-	Now() time.Time
-}
+type Clock = clock.Clock
 
 // ReportURLResolver provides a report link for an alarm when available.
 type ReportURLResolver func(ctx context.Context, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station) string
@@ -51,14 +50,25 @@ type Notifier struct {
 	channel        Channel
 	template       *Template
 	escalation     time.Duration
+	stages         []EscalationStage
 	clock          Clock
 	mu             sync.Mutex
-	timers         map[string]*time.Timer
+	timers         map[string][]clock.Timer
 	sent           map[string]sendRecord
 	cooldown       time.Duration
 	dedupeWindow   time.Duration
 	reportURL      ReportURLResolver
 	requestTimeout time.Duration
+	digestInterval time.Duration
+	digestTemplate *Template
+	digest         []TemplateData
+	digestSince    time.Time
+	digestTimer    clock.Timer
+	ruleChannels   map[string]Channel
+	retries        RetryStore
+	retryAttempts  int
+	retryBackoff   time.Duration
+	quietHours     map[string]QuietHours
 }
 
 // Option configures the notifier.
@@ -143,8 +153,8 @@ func NewNotifier(rules RuleReader, stations StationReader, alarms AlarmReader, c
 		channel:        channel,
 		template:       template,
 		escalation:     0,
-		clock:          systemClock{},
-		timers:         make(map[string]*time.Timer),
+		clock:          clock.System{},
+		timers:         make(map[string][]clock.Timer),
 		sent:           make(map[string]sendRecord),
 		requestTimeout: 5 * time.Second,
 	}
@@ -160,27 +170,43 @@ func (n *Notifier) Notify(ctx context.Context, event alarmapp.AlarmEvent) {
 		return
 	}
 	rule, station := n.lookup(ctx, event.Alarm)
-	n.dispatch(ctx, event.Type, event.Alarm, rule, station)
+	if n.digested(rule) {
+		// Events in quiet hours are dropped rather than buffered, or the
+		// digest flush would send them during the quiet window anyway.
+		if !n.quiet(event.Alarm, rule, station) {
+			n.bufferDigest(ctx, event.Type, event.Alarm, rule, station)
+		}
+	} else {
+		n.dispatch(ctx, defaultChannelName, event.Type, event.Type, event.Alarm, rule, station)
+	}
+	// A rule's own channel is never digested: operators route a rule there
+	// because it must not wait.
+	if n.ruleChannel(rule) != nil {
+		n.dispatch(ctx, ruleChannelName(rule.NotifyChannel), "channel:"+rule.NotifyChannel+":"+event.Type, event.Type, event.Alarm, rule, station)
+	}
 
+	// An acknowledged alarm is being worked on, so it stops escalating; if it
+	// goes active again the chain is re-armed from the start.
 	switch event.Type {
 	case "active":
 		n.scheduleEscalation(event.Alarm, rule)
-	case "cleared":
+	case "acknowledged", "cleared", alarmapp.EventStale:
 		n.cancelEscalation(event.Alarm.ID)
 	}
 }
 
-// Close stops all pending escalation timers.
+// Close stops all pending escalation timers and flushes any buffered digest.
 func (n *Notifier) Close() {
 	if n == nil {
 		return
 	}
+	n.flushDigest()
 	n.mu.Lock()
 	timers := n.timers
-	n.timers = make(map[string]*time.Timer)
+	n.timers = make(map[string][]clock.Timer)
 	n.mu.Unlock()
-	for _, timer := range timers {
-		if timer != nil {
+	for _, stageTimers := range timers {
+		for _, timer := range stageTimers {
 			timer.Stop()
 		}
 	}
@@ -204,88 +230,43 @@ func (n *Notifier) lookup(ctx context.Context, alarm alarms.Alarm) (*alarms.Alar
 	return rule, station
 }
 
-func (n *Notifier) dispatch(ctx context.Context, eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station) {
+// dispatch renders and sends one notification on the named channel. sendKey
+// scopes cooldown and dedupe bookkeeping, so escalation stages do not suppress
+// each other. A failed send is queued for retry when a retry store is set.
+func (n *Notifier) dispatch(ctx context.Context, channelName, sendKey, eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station) {
+	channel := n.channelByName(channelName)
+	if channel == nil {
+		return
+	}
 	reportURL := ""
 	if n != nil && n.reportURL != nil {
 		reportURL = n.reportURL(ctx, alarm, rule, station)
 	}
-	data := buildTemplateData(eventType, alarm, rule, station, reportURL)
+	data := buildTemplateData(eventType, alarm, rule, station, reportURL, n.clock.Now())
 	content, err := n.template.Render(data)
 	if err != nil {
 		return
 	}
-	if !n.shouldSend(alarm.ID, eventType, content) {
-		return
-	}
-	if err := n.channel.Send(ctx, content); err != nil {
-		return
-	}
-	n.markSent(alarm.ID, eventType, content)
-}
-
-func (n *Notifier) scheduleEscalation(alarm alarms.Alarm, rule *alarms.AlarmRule) {
-	if n == nil || n.escalation <= 0 || alarm.ID == "" {
-		return
-	}
-	if rule == nil || !severityAtLeast(rule.Severity, "high") {
+	if !n.shouldSend(alarm, rule, station, sendKey, content) {
 		return
 	}
-	n.mu.Lock()
-	if existing, ok := n.timers[alarm.ID]; ok {
-		if existing != nil {
-			existing.Stop()
+	fields := buildFields(eventType, alarm, rule, reportURL)
+	if err := send(ctx, channel, content, fields); err != nil {
+		if !n.enqueueRetry(ctx, alarm.ID, channelName, content, &fields, err) {
+			return
 		}
 	}
-	timer := time.AfterFunc(n.escalation, func() {
-		n.runEscalation(alarm.ID)
-	})
-	n.timers[alarm.ID] = timer
-	n.mu.Unlock()
+	n.markSent(alarm.ID, sendKey, content)
 }
 
-func (n *Notifier) cancelEscalation(alarmID string) {
-	if n == nil || alarmID == "" {
-		return
-	}
-	n.mu.Lock()
-	timer := n.timers[alarmID]
-	delete(n.timers, alarmID)
-	n.mu.Unlock()
-	if timer != nil {
-		timer.Stop()
-	}
-}
-
-func (n *Notifier) runEscalation(alarmID string) {
-	if n == nil || alarmID == "" {
-		return
-	}
-	n.mu.Lock()
-	delete(n.timers, alarmID)
-	n.mu.Unlock()
-
-	ctx := context.Background()
-	if n.requestTimeout > 0 {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, n.requestTimeout)
-		defer cancel()
+func send(ctx context.Context, channel Channel, content string, fields Fields) error {
+	if structured, ok := channel.(StructuredChannel); ok {
+		return structured.SendMessage(ctx, Message{Content: content, Fields: fields})
 	}
-
-	alarm, err := n.alarms.GetByID(ctx, alarmID)
-	if err != nil || alarm == nil {
-		return
-	}
-	if alarm.Status == alarms.StatusCleared {
-		return
-	}
-	rule, station := n.lookup(ctx, *alarm)
-	if rule == nil || !severityAtLeast(rule.Severity, "high") {
-		return
-	}
-	n.dispatch(ctx, "escalated", *alarm, rule, station)
+	return channel.Send(ctx, content)
 }
 
-func buildTemplateData(eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station, reportURL string) TemplateData {
+func buildTemplateData(eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station, reportURL string, now time.Time) TemplateData {
 	stationName := alarm.StationID
 	if station != nil && station.Name != "" {
 		stationName = station.Name
@@ -310,8 +291,22 @@ func buildTemplateData(eventType string, alarm alarms.Alarm, rule *alarms.AlarmR
 	if startAt.IsZero() {
 		startAt = alarm.CreatedAt
 	}
+	endAt := now
+	if !alarm.EndAt.IsZero() {
+		endAt = alarm.EndAt
+	}
+	duration := endAt.Sub(startAt)
+	if startAt.IsZero() || duration < 0 {
+		duration = 0
+	}
 	statusLabel := statusLabel(alarm.Status)
 	suggestion := suggestionFor(rule)
+	minValue, avgValue, maxValue := "", "", ""
+	if alarm.SampleCount > 0 {
+		minValue = formatFloat(alarm.MinValue)
+		avgValue = formatFloat(alarm.AvgValue)
+		maxValue = formatFloat(alarm.MaxValue)
+	}
 
 	return TemplateData{
 		Station:      stationName,
@@ -319,8 +314,14 @@ func buildTemplateData(eventType string, alarm alarms.Alarm, rule *alarms.AlarmR
 		Rule:         ruleName,
 		RuleID:       alarm.RuleID,
 		TriggerValue: formatFloat(alarm.LastValue),
+		Value:        alarm.LastValue,
+		MinValue:     minValue,
+		AvgValue:     avgValue,
+		MaxValue:     maxValue,
 		Threshold:    thresholdText,
 		StartTime:    startAt.UTC().Format(time.RFC3339),
+		StartedAt:    startAt.UTC(),
+		Duration:     duration,
 		Status:       statusLabel,
 		StatusCode:   alarm.Status,
 		Severity:     severity,
@@ -331,6 +332,33 @@ func buildTemplateData(eventType string, alarm alarms.Alarm, rule *alarms.AlarmR
 	}
 }
 
+func buildFields(eventType string, alarm alarms.Alarm, rule *alarms.AlarmRule, reportURL string) Fields {
+	startAt := alarm.StartAt
+	if startAt.IsZero() {
+		startAt = alarm.CreatedAt
+	}
+	fields := Fields{
+		Event:     eventType,
+		AlarmID:   alarm.ID,
+		StationID: alarm.StationID,
+		RuleID:    alarm.RuleID,
+		Value:     alarm.LastValue,
+		Status:    alarm.Status,
+		StartedAt: startAt.UTC(),
+		ReportURL: reportURL,
+	}
+	if alarm.SampleCount > 0 {
+		minValue, avgValue, maxValue := alarm.MinValue, alarm.AvgValue, alarm.MaxValue
+		fields.MinValue, fields.AvgValue, fields.MaxValue = &minValue, &avgValue, &maxValue
+	}
+	if rule != nil {
+		threshold := rule.Threshold
+		fields.Severity = rule.Severity
+		fields.Threshold = &threshold
+	}
+	return fields
+}
+
 func statusLabel(status string) string {
 	switch status {
 	case alarms.StatusActive:
@@ -354,6 +382,10 @@ func eventLabel(event string) string {
 		return "Cleared"
 	case "escalated":
 		return "Escalated"
+	case alarmapp.EventStale:
+		return "Cleared (stale data)"
+	case alarmapp.EventFlapping:
+		return "Flapping (held active)"
 	default:
 		return event
 	}
@@ -375,36 +407,25 @@ func suggestionFor(rule *alarms.AlarmRule) string {
 }
 
 func severityAtLeast(value, target string) bool {
-	return severityRank(value) >= severityRank(target)
-}
-
-func severityRank(value string) int {
-	switch strings.TrimSpace(strings.ToLower(value)) {
-	case "critical":
-		return 4
-	case "high":
-		return 3
-	case "medium":
-		return 2
-	case "low":
-		return 1
-	default:
-		return 0
-	}
+	return alarms.SeverityRank(value) >= alarms.SeverityRank(target)
 }
 
 func formatFloat(value float64) string {
 	return fmt.Sprintf("%.2f", value)
 }
 
-func (n *Notifier) shouldSend(alarmID, eventType, content string) bool {
+// shouldSend applies quiet hours, cooldown and dedupe to one notification.
+func (n *Notifier) shouldSend(alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station, eventType, content string) bool {
 	if n == nil {
 		return false
 	}
+	if n.quiet(alarm, rule, station) {
+		return false
+	}
 	if n.cooldown <= 0 && n.dedupeWindow <= 0 {
 		return true
 	}
-	key := notificationKey(alarmID, eventType)
+	key := notificationKey(alarm.ID, eventType)
 	now := n.clock.Now().UTC()
 	hash := hashContent(content)
 
@@ -444,7 +465,3 @@ func hashContent(content string) string {
 	sum := sha1.Sum([]byte(content))
 	return hex.EncodeToString(sum[:8])
 }
-
-type systemClock struct{}
-
-func (systemClock) Now() time.Time { return time.Now().UTC() }
@@ -76,15 +76,19 @@ func TestWebhookNotifierPayload(t *testing.T) {
 	}
 	station := &masterdata.Station{ID: "station-1", Name: "Station A"}
 	alarm := &alarms.Alarm{
-		ID:        "alarm-1",
-		TenantID:  "tenant-1",
-		StationID: "station-1",
-		RuleID:    "rule-1",
-		Status:    alarms.StatusActive,
-		StartAt:   time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
-		LastValue: 123.45,
-		CreatedAt: time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
-		UpdatedAt: time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
+		ID:          "alarm-1",
+		TenantID:    "tenant-1",
+		StationID:   "station-1",
+		RuleID:      "rule-1",
+		Status:      alarms.StatusActive,
+		StartAt:     time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
+		LastValue:   123.45,
+		MinValue:    101,
+		MaxValue:    150.5,
+		AvgValue:    125,
+		SampleCount: 3,
+		CreatedAt:   time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC),
 	}
 
 	notifier, err := NewNotifier(
@@ -117,6 +121,7 @@ func TestWebhookNotifierPayload(t *testing.T) {
 			"Station: Station A",
 			"Rule: Charge Power High",
 			"Trigger Value: 123.45",
+			"Min/Avg/Max: 101.00 / 125.00 / 150.50",
 			"Threshold: > 100.00",
 			"Start Time: 2026-01-26T08:00:00Z",
 			"Current Status: active",
@@ -296,3 +301,67 @@ func TestNotifierEscalation(t *testing.T) {
 		t.Fatalf("expected escalated notification content, got %s", channel.Latest())
 	}
 }
+
+func TestWebhookNotifierStructuredFields(t *testing.T) {
+	for _, structured := range []bool{true, false} {
+		payloadCh := make(chan webhookPayload, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload webhookPayload
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			payloadCh <- payload
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		channel, err := NewWebhookChannel(server.URL, WithStructuredFields(structured))
+		if err != nil {
+			t.Fatalf("new webhook channel: %v", err)
+		}
+		rule := &alarms.AlarmRule{ID: "rule-4", Name: "SOC Low", Operator: alarms.OperatorLess, Threshold: 10, Severity: "critical"}
+		alarm := &alarms.Alarm{ID: "alarm-4", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-4", Status: alarms.StatusActive, StartAt: time.Date(2026, 1, 26, 8, 0, 0, 0, time.UTC), LastValue: 7.5}
+		notifier, err := NewNotifier(
+			stubRuleRepo{rule: rule},
+			stubStationRepo{},
+			stubAlarmRepo{alarm: alarm},
+			channel,
+			nil,
+			WithReportURLResolver(func(_ context.Context, _ alarms.Alarm, _ *alarms.AlarmRule, _ *masterdata.Station) string {
+				return "http://example.com/report"
+			}),
+		)
+		if err != nil {
+			t.Fatalf("new notifier: %v", err)
+		}
+
+		notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+		var payload webhookPayload
+		select {
+		case payload = <-payloadCh:
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout waiting for webhook payload")
+		}
+		server.Close()
+
+		if payload.MsgType != "text" || !strings.Contains(payload.Text.Content, "Rule: SOC Low") {
+			t.Fatalf("expected rendered text, got %+v", payload.Text)
+		}
+		if !structured {
+			if payload.Fields != nil {
+				t.Fatalf("expected no fields when disabled, got %+v", payload.Fields)
+			}
+			continue
+		}
+		fields := payload.Fields
+		if fields == nil {
+			t.Fatal("expected structured fields")
+		}
+		if fields.StationID != "station-1" || fields.RuleID != "rule-4" || fields.Severity != "critical" ||
+			fields.Value != 7.5 || fields.Threshold == nil || *fields.Threshold != 10 ||
+			fields.Status != alarms.StatusActive || !fields.StartedAt.Equal(alarm.StartAt) ||
+			fields.ReportURL != "http://example.com/report" {
+			t.Fatalf("unexpected fields: %+v", fields)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// quietHoursBypassSeverity is the lowest severity notified during quiet hours.
+const quietHoursBypassSeverity = "critical"
+
+// quietHoursDefaultTenant keys the window used by tenants without their own.
+const quietHoursDefaultTenant = "*"
+
+// QuietHours is a daily window, in minutes since local midnight, during which
+// non-critical notifications are suppressed. A window whose end is not after
+// its start wraps midnight, e.g. 22:00-07:00.
+type QuietHours struct {
+	StartMinute int
+	EndMinute   int
+}
+
+// Contains reports whether local falls inside the window.
+func (q QuietHours) Contains(local time.Time) bool {
+	minute := local.Hour()*60 + local.Minute()
+	if q.StartMinute < q.EndMinute {
+		return minute >= q.StartMinute && minute < q.EndMinute
+	}
+	return minute >= q.StartMinute || minute < q.EndMinute
+}
+
+// WithQuietHours sets per-tenant quiet-hour windows, keyed by tenant id; the
+// "*" key applies to every other tenant. Windows are evaluated in the station
+// timezone.
+func WithQuietHours(windows map[string]QuietHours) Option {
+	return func(n *Notifier) {
+		if len(windows) > 0 {
+			n.quietHours = windows
+		}
+	}
+}
+
+// ParseQuietHours parses "tenant=HH:MM-HH:MM;..." into windows, e.g.
+// "tenant-a=22:00-07:00;*=23:00-06:00". "*" names the default window.
+func ParseQuietHours(spec string) (map[string]QuietHours, error) {
+	windows := make(map[string]QuietHours)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		tenantID, window, ok := strings.Cut(entry, "=")
+		tenantID = strings.TrimSpace(tenantID)
+		if !ok || tenantID == "" {
+			return nil, fmt.Errorf("quiet hours: invalid entry %q", entry)
+		}
+		if _, dup := windows[tenantID]; dup {
+			return nil, fmt.Errorf("quiet hours: duplicate tenant %q", tenantID)
+		}
+		start, end, ok := strings.Cut(window, "-")
+		if !ok {
+			return nil, fmt.Errorf("quiet hours: invalid window in %q", entry)
+		}
+		startMinute, err := parseClockMinute(start)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours: invalid start in %q", entry)
+		}
+		endMinute, err := parseClockMinute(end)
+		if err != nil {
+			return nil, fmt.Errorf("quiet hours: invalid end in %q", entry)
+		}
+		if startMinute == endMinute {
+			return nil, fmt.Errorf("quiet hours: empty window in %q", entry)
+		}
+		windows[tenantID] = QuietHours{StartMinute: startMinute, EndMinute: endMinute}
+	}
+	return windows, nil
+}
+
+func parseClockMinute(value string) (int, error) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+// quiet reports whether a notification for alarm is suppressed by its
+// tenant's quiet hours. Critical alarms, and alarms whose rule cannot be
+// loaded, are never suppressed. Stations without a valid timezone use UTC.
+func (n *Notifier) quiet(alarm alarms.Alarm, rule *alarms.AlarmRule, station *masterdata.Station) bool {
+	if len(n.quietHours) == 0 || rule == nil || severityAtLeast(rule.Severity, quietHoursBypassSeverity) {
+		return false
+	}
+	window, ok := n.quietHours[alarm.TenantID]
+	if !ok {
+		if window, ok = n.quietHours[quietHoursDefaultTenant]; !ok {
+			return false
+		}
+	}
+	location := time.UTC
+	if station != nil && station.Timezone != "" {
+		if loc, err := time.LoadLocation(station.Timezone); err == nil {
+			location = loc
+		}
+	}
+	return window.Contains(n.clock.Now().In(location))
+}
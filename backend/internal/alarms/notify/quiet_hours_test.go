@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+func TestNotifierQuietHours_SuppressesNonCriticalInStationTime(t *testing.T) {
+	// 15:00 UTC is 23:00 in Shanghai, inside the 22:00-07:00 window.
+	clock := &fakeClock{now: time.Date(2026, 1, 26, 15, 0, 0, 0, time.UTC)}
+	channel := &recordingChannel{}
+	tpl, err := NewTemplate("")
+	if err != nil {
+		t.Fatalf("new template: %v", err)
+	}
+	windows, err := ParseQuietHours("tenant-1=22:00-07:00")
+	if err != nil {
+		t.Fatalf("parse quiet hours: %v", err)
+	}
+	rule := &alarms.AlarmRule{ID: "rule-1", Name: "Rule", Operator: alarms.OperatorGreater, Threshold: 10, Severity: "high"}
+	station := &masterdata.Station{ID: "station-1", Name: "Station A", Timezone: "Asia/Shanghai"}
+	alarm := &alarms.Alarm{ID: "alarm-1", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-1", Status: alarms.StatusActive, StartAt: clock.Now(), LastValue: 12}
+
+	notifier, err := NewNotifier(
+		stubRuleRepo{rule: rule},
+		stubStationRepo{station: station},
+		stubAlarmRepo{alarm: alarm},
+		channel,
+		tpl,
+		WithClock(clock),
+		WithQuietHours(windows),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	if got := channel.Count(); got != 0 {
+		t.Fatalf("expected high alarm to be quiet at 23:00 local, got %d notifications", got)
+	}
+
+	rule.Severity = "critical"
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: *alarm})
+	if got := channel.Count(); got != 1 {
+		t.Fatalf("expected critical alarm to notify during quiet hours, got %d", got)
+	}
+
+	// 01:00 UTC is 09:00 in Shanghai, after the window.
+	rule.Severity = "high"
+	clock.Add(10 * time.Hour)
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "cleared", Alarm: *alarm})
+	if got := channel.Count(); got != 2 {
+		t.Fatalf("expected high alarm to notify at 09:00 local, got %d", got)
+	}
+
+	// Other tenants have no window and the default "*" is not configured.
+	other := *alarm
+	other.ID, other.TenantID = "alarm-2", "tenant-2"
+	clock.Add(-10 * time.Hour)
+	notifier.Notify(context.Background(), alarmapp.AlarmEvent{Type: "active", Alarm: other})
+	if got := channel.Count(); got != 3 {
+		t.Fatalf("expected tenant without quiet hours to notify, got %d", got)
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	windows, err := ParseQuietHours(" tenant-a=22:00-07:00 ; *=12:30-13:30 ")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := windows["tenant-a"]; got != (QuietHours{StartMinute: 22 * 60, EndMinute: 7 * 60}) {
+		t.Fatalf("tenant-a = %+v", got)
+	}
+	day := time.Date(2026, 1, 26, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		window QuietHours
+		at     time.Duration
+		want   bool
+	}{
+		{windows["tenant-a"], 23 * time.Hour, true},
+		{windows["tenant-a"], 6*time.Hour + 59*time.Minute, true},
+		{windows["tenant-a"], 7 * time.Hour, false},
+		{windows["tenant-a"], 21*time.Hour + 59*time.Minute, false},
+		{windows["*"], 12*time.Hour + 30*time.Minute, true},
+		{windows["*"], 13*time.Hour + 30*time.Minute, false},
+	}
+	for _, tc := range cases {
+		if got := tc.window.Contains(day.Add(tc.at)); got != tc.want {
+			t.Fatalf("%+v contains %s = %v, want %v", tc.window, tc.at, got, tc.want)
+		}
+	}
+
+	for _, spec := range []string{"tenant-a", "=22:00-07:00", "tenant-a=22:00", "tenant-a=25:00-07:00", "tenant-a=08:00-08:00", "t=22:00-07:00;t=01:00-02:00"} {
+		if _, err := ParseQuietHours(spec); err == nil {
+			t.Fatalf("expected %q to fail", spec)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/observability/metrics"
+)
+
+// Defaults of WithRetryQueue.
+const (
+	DefaultRetryAttempts = 5
+	DefaultRetryBackoff  = time.Minute
+)
+
+const (
+	defaultChannelName      = "default"
+	escalationChannelPrefix = "escalation#"
+	ruleChannelPrefix       = "rule:"
+	retryBatchSize          = 50
+)
+
+// RetryStore persists notifications whose delivery failed.
+type RetryStore interface {
+	Enqueue(ctx context.Context, retry *alarms.NotificationRetry) error
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]alarms.NotificationRetry, error)
+	MarkSent(ctx context.Context, id int64, attempts int) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkExhausted(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+// WithRetryQueue queues failed sends in store and redelivers them from
+// RetryFailed until they have been attempted maxAttempts times, waiting backoff
+// times the attempt count between tries. Without it a failed send is dropped.
+func WithRetryQueue(store RetryStore, maxAttempts int, backoff time.Duration) Option {
+	return func(n *Notifier) {
+		if store == nil || maxAttempts <= 1 {
+			return
+		}
+		n.retries = store
+		n.retryAttempts = maxAttempts
+		n.retryBackoff = DefaultRetryBackoff
+		if backoff > 0 {
+			n.retryBackoff = backoff
+		}
+	}
+}
+
+// RetryFailed redelivers the queued notifications that are due and returns
+// how many were sent. Retries that fail for the last time, or whose channel is
+// no longer configured, are marked exhausted.
+func (n *Notifier) RetryFailed(ctx context.Context) (int, error) {
+	if n == nil || n.retries == nil {
+		return 0, nil
+	}
+	now := n.clock.Now().UTC()
+	due, err := n.retries.ClaimDue(ctx, now, retryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var firstErr error
+	record := func(err error) {
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, retry := range due {
+		attempts := retry.Attempts + 1
+		channel := n.channelByName(retry.Channel)
+		if channel == nil {
+			metrics.IncAlarmNotifyRetryExhausted()
+			record(n.retries.MarkExhausted(ctx, retry.ID, retry.Attempts, "channel "+retry.Channel+" is not configured"))
+			continue
+		}
+		sendErr := n.resend(ctx, channel, retry)
+		switch {
+		case sendErr == nil:
+			sent++
+			record(n.retries.MarkSent(ctx, retry.ID, attempts))
+		case attempts >= n.retryAttempts:
+			metrics.IncAlarmNotifyRetryExhausted()
+			record(n.retries.MarkExhausted(ctx, retry.ID, attempts, sendErr.Error()))
+		default:
+			next := now.Add(n.retryBackoff * time.Duration(attempts))
+			record(n.retries.MarkRetry(ctx, retry.ID, attempts, next, sendErr.Error()))
+		}
+	}
+	return sent, firstErr
+}
+
+func (n *Notifier) resend(ctx context.Context, channel Channel, retry alarms.NotificationRetry) error {
+	if n.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.requestTimeout)
+		defer cancel()
+	}
+	if len(retry.Fields) == 0 {
+		return channel.Send(ctx, retry.Content)
+	}
+	var fields Fields
+	if err := json.Unmarshal(retry.Fields, &fields); err != nil {
+		return err
+	}
+	return send(ctx, channel, retry.Content, fields)
+}
+
+// enqueueRetry queues a failed send and reports whether it was queued.
+func (n *Notifier) enqueueRetry(ctx context.Context, alarmID, channelName, content string, fields *Fields, sendErr error) bool {
+	if n.retries == nil {
+		return false
+	}
+	retry := &alarms.NotificationRetry{
+		AlarmID:       alarmID,
+		Channel:       channelName,
+		Content:       content,
+		Attempts:      1,
+		NextAttemptAt: n.clock.Now().UTC().Add(n.retryBackoff),
+		LastError:     sendErr.Error(),
+	}
+	if fields != nil {
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return false
+		}
+		retry.Fields = raw
+	}
+	return n.retries.Enqueue(ctx, retry) == nil
+}
+
+// channelByName resolves a channel from the name stored with a retry:
+// "default", "escalation#<stage>" or "rule:<notify_channel>".
+func (n *Notifier) channelByName(name string) Channel {
+	switch {
+	case name == defaultChannelName:
+		return n.channel
+	case strings.HasPrefix(name, escalationChannelPrefix):
+		idx, err := strconv.Atoi(strings.TrimPrefix(name, escalationChannelPrefix))
+		stages := n.escalationStages()
+		if err != nil || idx < 0 || idx >= len(stages) {
+			return nil
+		}
+		return stages[idx].Channel
+	case strings.HasPrefix(name, ruleChannelPrefix):
+		return n.ruleChannels[strings.TrimPrefix(name, ruleChannelPrefix)]
+	}
+	return nil
+}
+
+func escalationChannelName(stageIdx int) string {
+	return escalationChannelPrefix + strconv.Itoa(stageIdx)
+}
+
+func ruleChannelName(name string) string {
+	return ruleChannelPrefix + name
+}
@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// flakyChannel fails until its failures are used up, then records sends.
+type flakyChannel struct {
+	recordingChannel
+	failures int
+}
+
+func (f *flakyChannel) Send(ctx context.Context, content string) error {
+	f.mu.Lock()
+	if f.failures > 0 {
+		f.failures--
+		f.mu.Unlock()
+		return errors.New("webhook unavailable")
+	}
+	f.mu.Unlock()
+	return f.recordingChannel.Send(ctx, content)
+}
+
+type memoryRetryStore struct {
+	mu      sync.Mutex
+	retries []alarms.NotificationRetry
+}
+
+func (m *memoryRetryStore) Enqueue(_ context.Context, retry *alarms.NotificationRetry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	retry.ID = int64(len(m.retries) + 1)
+	retry.Status = alarms.RetryStatusPending
+	m.retries = append(m.retries, *retry)
+	return nil
+}
+
+func (m *memoryRetryStore) ClaimDue(_ context.Context, now time.Time, limit int) ([]alarms.NotificationRetry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var due []alarms.NotificationRetry
+	for _, retry := range m.retries {
+		if retry.Status == alarms.RetryStatusPending && !retry.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, retry)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryRetryStore) MarkSent(_ context.Context, id int64, attempts int) error {
+	return m.set(id, alarms.RetryStatusSent, attempts, time.Time{})
+}
+
+func (m *memoryRetryStore) MarkRetry(_ context.Context, id int64, attempts int, next time.Time, _ string) error {
+	return m.set(id, alarms.RetryStatusPending, attempts, next)
+}
+
+func (m *memoryRetryStore) MarkExhausted(_ context.Context, id int64, attempts int, _ string) error {
+	return m.set(id, alarms.RetryStatusExhausted, attempts, time.Time{})
+}
+
+func (m *memoryRetryStore) set(id int64, status string, attempts int, next time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	retry := &m.retries[id-1]
+	retry.Status = status
+	retry.Attempts = attempts
+	if !next.IsZero() {
+		retry.NextAttemptAt = next
+	}
+	return nil
+}
+
+func (m *memoryRetryStore) Get(id int64) alarms.NotificationRetry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.retries[id-1]
+}
+
+func newRetryNotifier(t *testing.T, clk *clock.Fixed, channel Channel, store RetryStore, attempts int) *Notifier {
+	t.Helper()
+	notifier, err := NewNotifier(
+		stubRuleRepo{rule: &alarms.AlarmRule{ID: "rule-1", Name: "Temp High", Operator: alarms.OperatorGreater, Threshold: 45, Severity: "high"}},
+		stubStationRepo{station: &masterdata.Station{ID: "station-1", Name: "Station A"}},
+		stubAlarmRepo{},
+		channel,
+		nil,
+		WithClock(clk),
+		WithRetryQueue(store, attempts, time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	return notifier
+}
+
+func retryEvent(clk *clock.Fixed) alarmapp.AlarmEvent {
+	return alarmapp.AlarmEvent{Type: "active", Alarm: alarms.Alarm{ID: "alarm-1", TenantID: "tenant-1", StationID: "station-1", RuleID: "rule-1", Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 50}}
+}
+
+func TestNotifierRetry_FailedSendIsRedelivered(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 27, 9, 0, 0, 0, time.UTC))
+	channel := &flakyChannel{failures: 2}
+	store := &memoryRetryStore{}
+	notifier := newRetryNotifier(t, clk, channel, store, 5)
+	ctx := context.Background()
+
+	notifier.Notify(ctx, retryEvent(clk))
+	if channel.Count() != 0 || len(store.retries) != 1 {
+		t.Fatalf("expected failed send to be queued, sent=%d queued=%d", channel.Count(), len(store.retries))
+	}
+	if sent, err := notifier.RetryFailed(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected retry to wait for its backoff, sent=%d err=%v", sent, err)
+	}
+
+	clk.Advance(time.Minute)
+	if sent, err := notifier.RetryFailed(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected second attempt to fail, sent=%d err=%v", sent, err)
+	}
+	if retry := store.Get(1); retry.Attempts != 2 || !retry.NextAttemptAt.Equal(clk.Now().Add(2*time.Minute)) {
+		t.Fatalf("expected linear backoff after attempt 2, got %+v", retry)
+	}
+
+	clk.Advance(2 * time.Minute)
+	if sent, err := notifier.RetryFailed(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected redelivery, sent=%d err=%v", sent, err)
+	}
+	if channel.Count() != 1 || !strings.Contains(channel.Latest(), "Temp High") {
+		t.Fatalf("expected queued notification on the channel, sent=%d latest=%s", channel.Count(), channel.Latest())
+	}
+	if retry := store.Get(1); retry.Status != alarms.RetryStatusSent || retry.Attempts != 3 {
+		t.Fatalf("expected retry marked sent after 3 attempts, got %+v", retry)
+	}
+}
+
+func TestNotifierRetry_ExhaustsAfterMaxAttempts(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 27, 9, 0, 0, 0, time.UTC))
+	channel := &flakyChannel{failures: 10}
+	store := &memoryRetryStore{}
+	notifier := newRetryNotifier(t, clk, channel, store, 2)
+	ctx := context.Background()
+
+	notifier.Notify(ctx, retryEvent(clk))
+	clk.Advance(time.Minute)
+	if _, err := notifier.RetryFailed(ctx); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if retry := store.Get(1); retry.Status != alarms.RetryStatusExhausted || retry.Attempts != 2 {
+		t.Fatalf("expected retry exhausted after 2 attempts, got %+v", retry)
+	}
+	clk.Advance(time.Hour)
+	if sent, _ := notifier.RetryFailed(ctx); sent != 0 || channel.Count() != 0 {
+		t.Fatalf("expected exhausted retry to stay dropped, sent=%d", sent)
+	}
+}
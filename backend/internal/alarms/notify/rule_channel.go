@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+
+	alarms "microgrid-cloud/internal/alarms/domain"
+)
+
+// WithRuleChannels registers named channels that rules can reference through
+// their notify_channel. A rule with a known override is also sent to that
+// channel, on top of the default path; other rules are unaffected.
+func WithRuleChannels(channels map[string]Channel) Option {
+	return func(n *Notifier) {
+		n.ruleChannels = make(map[string]Channel, len(channels))
+		for name, channel := range channels {
+			if name != "" && channel != nil {
+				n.ruleChannels[name] = channel
+			}
+		}
+	}
+}
+
+// ParseNamedChannels parses "name=webhook_url;..." into channels keyed by
+// name, e.g. "fire-safety=https://hooks.example.com/fire".
+func ParseNamedChannels(spec string, opts ...WebhookOption) (map[string]Channel, error) {
+	channels := make(map[string]Channel)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, url, ok := strings.Cut(entry, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("notify channels: invalid entry %q", entry)
+		}
+		if _, dup := channels[name]; dup {
+			return nil, fmt.Errorf("notify channels: duplicate name %q", name)
+		}
+		channel, err := NewWebhookChannel(strings.TrimSpace(url), opts...)
+		if err != nil {
+			return nil, err
+		}
+		channels[name] = channel
+	}
+	return channels, nil
+}
+
+// ruleChannel resolves the override of rule; an unknown name resolves to nil.
+func (n *Notifier) ruleChannel(rule *alarms.AlarmRule) Channel {
+	if rule == nil || rule.NotifyChannel == "" {
+		return nil
+	}
+	return n.ruleChannels[rule.NotifyChannel]
+}
@@ -0,0 +1,79 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	alarmapp "microgrid-cloud/internal/alarms/application"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+func TestNotifierRuleChannel_OverrideReachesExtraChannel(t *testing.T) {
+	clk := clock.NewFixed(time.Date(2026, 1, 27, 9, 0, 0, 0, time.UTC))
+	defaults := &recordingChannel{}
+	fire := &recordingChannel{}
+	rules := ruleMapRepo{
+		"rule-fire": {ID: "rule-fire", Name: "Smoke Detected", Operator: alarms.OperatorGreater, Threshold: 0, Severity: "low", NotifyChannel: "fire-safety"},
+		"rule-temp": {ID: "rule-temp", Name: "Temp High", Operator: alarms.OperatorGreater, Threshold: 45, Severity: "critical"},
+		"rule-gone": {ID: "rule-gone", Name: "SOC Low", Operator: alarms.OperatorLess, Threshold: 20, Severity: "critical", NotifyChannel: "unknown"},
+	}
+	notifier, err := NewNotifier(
+		rules,
+		stubStationRepo{station: &masterdata.Station{ID: "station-1", Name: "Station A"}},
+		stubAlarmRepo{},
+		defaults,
+		nil,
+		WithClock(clk),
+		WithDigest(15*time.Minute),
+		WithRuleChannels(map[string]Channel{"fire-safety": fire}),
+	)
+	if err != nil {
+		t.Fatalf("new notifier: %v", err)
+	}
+	event := func(id, ruleID, eventType string) alarmapp.AlarmEvent {
+		return alarmapp.AlarmEvent{Type: eventType, Alarm: alarms.Alarm{ID: id, TenantID: "tenant-1", StationID: "station-1", RuleID: ruleID, Status: alarms.StatusActive, StartAt: clk.Now(), LastValue: 1}}
+	}
+
+	// The low-severity override is digested on the default path but sent to
+	// its own channel right away.
+	notifier.Notify(context.Background(), event("alarm-1", "rule-fire", "active"))
+	if fire.Count() != 1 || !strings.Contains(fire.Latest(), "Smoke Detected") {
+		t.Fatalf("expected override channel to receive the alarm, sent=%d latest=%s", fire.Count(), fire.Latest())
+	}
+	if defaults.Count() != 0 {
+		t.Fatalf("expected default path to keep digesting, sent=%d", defaults.Count())
+	}
+
+	notifier.Notify(context.Background(), event("alarm-2", "rule-temp", "active"))
+	notifier.Notify(context.Background(), event("alarm-3", "rule-gone", "active"))
+	if defaults.Count() != 2 {
+		t.Fatalf("expected rules to stay on the default path, sent=%d", defaults.Count())
+	}
+	if fire.Count() != 1 {
+		t.Fatalf("expected only the overriding rule on the extra channel, sent=%d", fire.Count())
+	}
+
+	notifier.Close()
+	if defaults.Count() != 3 || !strings.Contains(defaults.Latest(), "Smoke Detected") {
+		t.Fatalf("expected digest flush on the default channel, sent=%d", defaults.Count())
+	}
+}
+
+func TestParseNamedChannels(t *testing.T) {
+	channels, err := ParseNamedChannels(" fire-safety=https://hooks.example.com/fire ; ops=https://hooks.example.com/ops;")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(channels) != 2 || channels["fire-safety"] == nil || channels["ops"] == nil {
+		t.Fatalf("unexpected channels: %v", channels)
+	}
+	for _, spec := range []string{"fire-safety", "=https://hooks.example.com/x", "a=", "a=https://x;a=https://y"} {
+		if _, err := ParseNamedChannels(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"errors"
 	"text/template"
+	"time"
 )
 
 const DefaultTemplate = `[Alarm {{.EventLabel}}]
 Station: {{.Station}}
 Rule: {{.Rule}}
 Trigger Value: {{.TriggerValue}}
+{{- if .MaxValue }}
+Min/Avg/Max: {{.MinValue}} / {{.AvgValue}} / {{.MaxValue}}
+{{- end }}
 Threshold: {{.Threshold}}
 Start Time: {{.StartTime}}
 Current Status: {{.Status}}
@@ -19,15 +23,30 @@ Suggestion: {{.Suggestion}}
 Report: {{.ReportURL}}
 {{ end }}`
 
-// TemplateData provides fields for rendering notification content.
+// DefaultDigestTemplate summarizes buffered events in one notification.
+const DefaultDigestTemplate = `[Alarm Digest] {{.Count}} events from {{.From}} to {{.To}}
+{{- range .Items }}
+- [{{.EventLabel}}] {{.Station}} / {{.Rule}}: {{.TriggerValue}} (threshold {{.Threshold}}, severity {{.Severity}}, {{.Status}})
+{{- end }}
+`
+
+// TemplateData provides fields for rendering notification content. The string
+// fields are preformatted; Value, StartedAt and Duration are raw values for
+// use with the template funcs.
 type TemplateData struct {
 	Station      string
 	StationID    string
 	Rule         string
 	RuleID       string
 	TriggerValue string
+	Value        float64
+	MinValue     string
+	AvgValue     string
+	MaxValue     string
 	Threshold    string
 	StartTime    string
+	StartedAt    time.Time
+	Duration     time.Duration
 	Status       string
 	StatusCode   string
 	Severity     string
@@ -37,6 +56,14 @@ type TemplateData struct {
 	EventLabel   string
 }
 
+// DigestData provides fields for rendering a digest notification.
+type DigestData struct {
+	Count int
+	From  string
+	To    string
+	Items []TemplateData
+}
+
 // Template renders notification content.
 type Template struct {
 	tpl *template.Template
@@ -47,15 +74,32 @@ func NewTemplate(tpl string) (*Template, error) {
 	if tpl == "" {
 		tpl = DefaultTemplate
 	}
-	parsed, err := template.New("alarm-notification").Parse(tpl)
+	parsed, err := template.New("alarm-notification").Funcs(templateFuncs).Parse(tpl)
 	if err != nil {
 		return nil, err
 	}
 	return &Template{tpl: parsed}, nil
 }
 
+// NewDigestTemplate parses a digest template, falling back to DefaultDigestTemplate.
+func NewDigestTemplate(tpl string) (*Template, error) {
+	if tpl == "" {
+		tpl = DefaultDigestTemplate
+	}
+	return NewTemplate(tpl)
+}
+
 // Render applies the template to data.
 func (t *Template) Render(data TemplateData) (string, error) {
+	return t.execute(data)
+}
+
+// RenderDigest applies the template to digest data.
+func (t *Template) RenderDigest(data DigestData) (string, error) {
+	return t.execute(data)
+}
+
+func (t *Template) execute(data any) (string, error) {
 	if t == nil || t.tpl == nil {
 		return "", errors.New("alarm template: nil")
 	}
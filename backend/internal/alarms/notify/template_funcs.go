@@ -0,0 +1,149 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are available to notification and digest templates:
+//
+//	upper, lower         change the case of a string
+//	round N V            V (number or numeric string) with N decimals
+//	humanizeDuration D   D (duration or duration string) as "1h 5m", "45s"
+//	formatTime L T       T (time or RFC3339 string) in layout L, UTC
+//	default F V          F when V is empty or zero, otherwise V
+var templateFuncs = template.FuncMap{
+	"upper":            strings.ToUpper,
+	"lower":            strings.ToLower,
+	"round":            roundValue,
+	"humanizeDuration": humanizeDuration,
+	"formatTime":       formatTime,
+	"default":          defaultValue,
+}
+
+func roundValue(places int, value any) (string, error) {
+	if places < 0 {
+		return "", fmt.Errorf("round: negative places %d", places)
+	}
+	var number float64
+	switch v := value.(type) {
+	case float64:
+		number = v
+	case float32:
+		number = float64(v)
+	case int:
+		number = float64(v)
+	case int64:
+		number = float64(v)
+	case string:
+		if v == "" {
+			return "", nil
+		}
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return "", fmt.Errorf("round: %q is not a number", v)
+		}
+		number = parsed
+	default:
+		return "", fmt.Errorf("round: unsupported value %T", value)
+	}
+	return strconv.FormatFloat(number, 'f', places, 64), nil
+}
+
+func humanizeDuration(value any) (string, error) {
+	var d time.Duration
+	switch v := value.(type) {
+	case time.Duration:
+		d = v
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return "", fmt.Errorf("humanizeDuration: %q is not a duration", v)
+		}
+		d = parsed
+	default:
+		return "", fmt.Errorf("humanizeDuration: unsupported value %T", value)
+	}
+	if d < 0 {
+		d = -d
+	}
+	d = d.Round(time.Second)
+	if d < time.Second {
+		return "0s", nil
+	}
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	seconds := (d - minutes*time.Minute) / time.Second
+
+	parts := make([]string, 0, 2)
+	for _, part := range []struct {
+		n    time.Duration
+		unit string
+	}{{days, "d"}, {hours, "h"}, {minutes, "m"}, {seconds, "s"}} {
+		if part.n > 0 {
+			parts = append(parts, strconv.FormatInt(int64(part.n), 10)+part.unit)
+		}
+		// Two units are precise enough for a notification.
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " "), nil
+}
+
+func formatTime(layout string, value any) (string, error) {
+	var t time.Time
+	switch v := value.(type) {
+	case time.Time:
+		t = v
+	case string:
+		if v == "" {
+			return "", nil
+		}
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("formatTime: %q is not an RFC3339 time", v)
+		}
+		t = parsed
+	default:
+		return "", fmt.Errorf("formatTime: unsupported value %T", value)
+	}
+	if t.IsZero() {
+		return "", nil
+	}
+	return t.UTC().Format(layout), nil
+}
+
+func defaultValue(fallback, value any) any {
+	switch v := value.(type) {
+	case nil:
+		return fallback
+	case string:
+		if v == "" {
+			return fallback
+		}
+	case float64:
+		if v == 0 {
+			return fallback
+		}
+	case int:
+		if v == 0 {
+			return fallback
+		}
+	case time.Duration:
+		if v == 0 {
+			return fallback
+		}
+	case time.Time:
+		if v.IsZero() {
+			return fallback
+		}
+	}
+	return value
+}
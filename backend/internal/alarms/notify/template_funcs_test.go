@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTemplateFuncs_Render(t *testing.T) {
+	tpl, err := NewTemplate(`{{.Severity | upper}} {{.Rule}}: {{.Value | round 1}} (avg {{.AvgValue | round 0}})
+since {{.StartedAt | formatTime "2006-01-02 15:04"}} for {{humanizeDuration .Duration}}
+{{- if eq .Severity "critical"}} CALL ON-DUTY{{end}}
+report: {{.ReportURL | default "n/a"}}`)
+	if err != nil {
+		t.Fatalf("parse template: %v", err)
+	}
+	content, err := tpl.Render(TemplateData{
+		Rule:      "Temp High",
+		Severity:  "critical",
+		Value:     52.347,
+		AvgValue:  "48.6",
+		StartedAt: time.Date(2026, 1, 27, 9, 0, 0, 0, time.UTC),
+		Duration:  time.Hour + 5*time.Minute + 12*time.Second,
+	})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	want := "CRITICAL Temp High: 52.3 (avg 49)\nsince 2026-01-27 09:00 for 1h 5m CALL ON-DUTY\nreport: n/a"
+	if content != want {
+		t.Fatalf("unexpected content:\n%s\nwant:\n%s", content, want)
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	cases := map[time.Duration]string{
+		0:                                       "0s",
+		45 * time.Second:                        "45s",
+		90 * time.Minute:                        "1h 30m",
+		26*time.Hour + 3*time.Minute:            "1d 2h",
+		24*time.Hour + 7*time.Second:            "1d 7s",
+		-(2*time.Minute + 500*time.Millisecond): "2m 1s",
+	}
+	for in, want := range cases {
+		got, err := humanizeDuration(in)
+		if err != nil || got != want {
+			t.Fatalf("humanizeDuration(%s) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := humanizeDuration("soon"); err == nil {
+		t.Fatalf("expected error for invalid duration string")
+	}
+}
@@ -15,10 +15,41 @@ type Channel interface {
 	Send(ctx context.Context, content string) error
 }
 
+// StructuredChannel is implemented by channels that can deliver machine-readable
+// fields alongside the rendered content.
+type StructuredChannel interface {
+	Channel
+	SendMessage(ctx context.Context, msg Message) error
+}
+
+// Message is a rendered notification together with its structured fields.
+type Message struct {
+	Content string
+	Fields  Fields
+}
+
+// Fields carries the alarm attributes of a notification for programmatic consumers.
+type Fields struct {
+	Event     string    `json:"event"`
+	AlarmID   string    `json:"alarm_id"`
+	StationID string    `json:"station_id"`
+	RuleID    string    `json:"rule_id"`
+	Severity  string    `json:"severity,omitempty"`
+	Value     float64   `json:"value"`
+	MinValue  *float64  `json:"min_value,omitempty"`
+	AvgValue  *float64  `json:"avg_value,omitempty"`
+	MaxValue  *float64  `json:"max_value,omitempty"`
+	Threshold *float64  `json:"threshold,omitempty"`
+	Status    string    `json:"status"`
+	StartedAt time.Time `json:"started_at"`
+	ReportURL string    `json:"report_url,omitempty"`
+}
+
 type webhookPayload struct {
-	MsgType string       `json:"msgtype"`
-	Text    webhookText  `json:"text"`
+	MsgType  string           `json:"msgtype"`
+	Text     webhookText      `json:"text"`
 	Markdown *webhookMarkdown `json:"markdown,omitempty"`
+	Fields   *Fields          `json:"fields,omitempty"`
 }
 
 type webhookText struct {
@@ -31,8 +62,9 @@ type webhookMarkdown struct {
 
 // WebhookChannel sends notifications to a webhook endpoint.
 type WebhookChannel struct {
-	url    string
-	client *http.Client
+	url        string
+	client     *http.Client
+	structured bool
 }
 
 // WebhookOption configures the webhook channel.
@@ -47,6 +79,14 @@ func WithHTTPClient(client *http.Client) WebhookOption {
 	}
 }
 
+// WithStructuredFields adds a "fields" object with alarm attributes to the payload.
+// Off by default so plain DingTalk/WeCom robots keep receiving the payload they expect.
+func WithStructuredFields(enabled bool) WebhookOption {
+	return func(ch *WebhookChannel) {
+		ch.structured = enabled
+	}
+}
+
 // NewWebhookChannel constructs a webhook channel.
 func NewWebhookChannel(url string, opts ...WebhookOption) (*WebhookChannel, error) {
 	if url == "" {
@@ -64,13 +104,23 @@ func NewWebhookChannel(url string, opts ...WebhookOption) (*WebhookChannel, erro
 
 // Send posts the content using DingTalk/WeCom-compatible payload.
 func (w *WebhookChannel) Send(ctx context.Context, content string) error {
+	return w.post(ctx, webhookPayload{MsgType: "text", Text: webhookText{Content: content}})
+}
+
+// SendMessage posts the content and, when enabled, the structured fields.
+func (w *WebhookChannel) SendMessage(ctx context.Context, msg Message) error {
+	payload := webhookPayload{MsgType: "text", Text: webhookText{Content: msg.Content}}
+	if w != nil && w.structured {
+		fields := msg.Fields
+		payload.Fields = &fields
+	}
+	return w.post(ctx, payload)
+}
+
+func (w *WebhookChannel) post(ctx context.Context, payload webhookPayload) error {
 	if w == nil || w.url == "" {
 		return errors.New("webhook channel: empty url")
 	}
-	payload := webhookPayload{
-		MsgType: "text",
-		Text:    webhookText{Content: content},
-	}
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
@@ -0,0 +1,162 @@
+package backfill
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/events"
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+)
+
+// Job statuses, in cascade order. A job only moves forward.
+const (
+	StatusPending   = "pending"   // hour not recalculated yet
+	StatusHourDone  = "hour_done" // waiting for the day rollup
+	StatusDayDone   = "day_done"  // waiting for the day settlement
+	StatusCompleted = "completed" // settlement recalculated
+)
+
+// Stage is one step of the hour -> day -> settlement cascade.
+type Stage string
+
+const (
+	StageHour       Stage = "hour"
+	StageDay        Stage = "day"
+	StageSettlement Stage = "settlement"
+)
+
+// Status is the job status once s has completed.
+func (s Stage) Status() string {
+	switch s {
+	case StageHour:
+		return StatusHourDone
+	case StageDay:
+		return StatusDayDone
+	case StageSettlement:
+		return StatusCompleted
+	}
+	return ""
+}
+
+// Before lists the statuses a job can advance from when s completes. Stages
+// may be reported out of order, so a later stage also advances a job that
+// missed an earlier one.
+func (s Stage) Before() []string {
+	switch s {
+	case StageHour:
+		return []string{StatusPending}
+	case StageDay:
+		return []string{StatusPending, StatusHourDone}
+	case StageSettlement:
+		return []string{StatusPending, StatusHourDone, StatusDayDone}
+	}
+	return nil
+}
+
+// Job tracks one backfilled hour through the cascade.
+type Job struct {
+	ID           string
+	TenantID     string
+	StationID    string
+	HourStart    time.Time
+	DayStart     time.Time
+	Status       string
+	HourAt       *time.Time
+	DayAt        *time.Time
+	SettlementAt *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// Store persists backfill jobs.
+type Store interface {
+	Create(ctx context.Context, job *Job) error
+	// Get returns nil when the job does not exist.
+	Get(ctx context.Context, id string) (*Job, error)
+	// Advance records stage completion at `at` on the open jobs of a station
+	// whose hour (StageHour) or day (later stages) starts at periodStart.
+	Advance(ctx context.Context, stationID string, stage Stage, periodStart, at time.Time) (int, error)
+}
+
+// Tracker creates jobs for backfills and advances them from cascade events.
+type Tracker struct {
+	store Store
+	clock domainstatistic.Clock
+}
+
+// NewTracker constructs a tracker.
+func NewTracker(store Store, clock domainstatistic.Clock) (*Tracker, error) {
+	if store == nil {
+		return nil, errors.New("backfill tracker: nil store")
+	}
+	if clock == nil {
+		clock = domainstatistic.SystemClock{}
+	}
+	return &Tracker{store: store, clock: clock}, nil
+}
+
+// Start records a pending job for the recalculation of one station hour.
+func (t *Tracker) Start(ctx context.Context, tenantID, stationID string, hourStart time.Time) (*Job, error) {
+	if stationID == "" || hourStart.IsZero() {
+		return nil, errors.New("backfill tracker: station and hour required")
+	}
+	hourStart = hourStart.UTC()
+	now := t.clock.Now().UTC()
+	job := &Job{
+		ID:        newJobID(),
+		TenantID:  tenantID,
+		StationID: stationID,
+		HourStart: hourStart,
+		DayStart:  time.Date(hourStart.Year(), hourStart.Month(), hourStart.Day(), 0, 0, 0, 0, time.UTC),
+		Status:    StatusPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := t.store.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Get loads a job; it returns nil when the job does not exist.
+func (t *Tracker) Get(ctx context.Context, id string) (*Job, error) {
+	return t.store.Get(ctx, id)
+}
+
+// HandleStatisticCalculated advances jobs on recalculated HOUR and DAY
+// statistics. Statistics computed outside a backfill are ignored.
+func (t *Tracker) HandleStatisticCalculated(ctx context.Context, event events.StatisticCalculated) error {
+	if !event.Recalculate {
+		return nil
+	}
+	switch event.Granularity {
+	case domainstatistic.GranularityHour:
+		return t.advance(ctx, event.StationID, StageHour, event.PeriodStart)
+	case domainstatistic.GranularityDay:
+		return t.advance(ctx, event.StationID, StageDay, event.PeriodStart)
+	}
+	return nil
+}
+
+// HandleSettlementCalculated completes jobs once the day settlement of a
+// station has been recalculated.
+func (t *Tracker) HandleSettlementCalculated(ctx context.Context, stationID string, dayStart time.Time) error {
+	return t.advance(ctx, stationID, StageSettlement, dayStart)
+}
+
+func (t *Tracker) advance(ctx context.Context, stationID string, stage Stage, periodStart time.Time) error {
+	if stationID == "" || periodStart.IsZero() {
+		return nil
+	}
+	_, err := t.store.Advance(ctx, stationID, stage, periodStart.UTC(), t.clock.Now().UTC())
+	return err
+}
+
+func newJobID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return "backfill-" + hex.EncodeToString(buf)
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"microgrid-cloud/internal/analytics/application/eventbus"
@@ -54,6 +55,13 @@ type TelemetryPoint struct {
 // ErrDuplicateStatistic is returned when a statistic already exists (idempotency).
 var ErrDuplicateStatistic = errors.New("analytics: statistic already exists")
 
+// ErrFutureWindow is returned for a telemetry window that has not closed yet.
+var ErrFutureWindow = errors.New("analytics: telemetry window ends in the future")
+
+// DefaultFutureWindowSkew is how far a window end may lie ahead of the clock
+// before it is rejected, absorbing clock drift between ingest and analytics.
+const DefaultFutureWindowSkew = 5 * time.Minute
+
 // HourlyStatisticAppServiceImpl is the default application service implementation.
 type HourlyStatisticAppServiceImpl struct {
 	repo       HourlyStatisticRepository
@@ -62,6 +70,20 @@ type HourlyStatisticAppServiceImpl struct {
 	bus        eventbus.EventBus
 	idFactory  StatisticIDFactory
 	clock      Clock
+	futureSkew time.Duration
+}
+
+// HourlyOption customizes the hourly statistic service.
+type HourlyOption func(*HourlyStatisticAppServiceImpl)
+
+// WithFutureWindowSkew sets how far a window end may be ahead of the clock.
+// Negative values are ignored.
+func WithFutureWindowSkew(skew time.Duration) HourlyOption {
+	return func(s *HourlyStatisticAppServiceImpl) {
+		if skew >= 0 {
+			s.futureSkew = skew
+		}
+	}
 }
 
 // NewHourlyStatisticAppService builds a HourlyStatisticAppServiceImpl.
@@ -72,15 +94,23 @@ func NewHourlyStatisticAppService(
 	bus eventbus.EventBus,
 	idFactory StatisticIDFactory,
 	clock Clock,
+	opts ...HourlyOption,
 ) *HourlyStatisticAppServiceImpl {
-	return &HourlyStatisticAppServiceImpl{
+	s := &HourlyStatisticAppServiceImpl{
 		repo:       repo,
 		telemetry:  telemetry,
 		calculator: calculator,
 		bus:        bus,
 		idFactory:  idFactory,
 		clock:      clock,
+		futureSkew: DefaultFutureWindowSkew,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
 	}
+	return s
 }
 
 // HandleTelemetryWindowClosed orchestrates the hourly statistic calculation.
@@ -95,6 +125,12 @@ func (s *HourlyStatisticAppServiceImpl) HandleTelemetryWindowClosed(ctx context.
 		result = metrics.ResultError
 		return errors.New("analytics: invalid telemetry window")
 	}
+	// A window that has not closed yet would be aggregated from partial
+	// telemetry and never revisited, so refuse it instead of storing it.
+	if evt.WindowEnd.After(s.clock.Now().Add(s.futureSkew)) {
+		result = metrics.ResultError
+		return fmt.Errorf("%w: station %s window end %s", ErrFutureWindow, evt.StationID, evt.WindowEnd.UTC().Format(time.RFC3339))
+	}
 
 	existing, err := s.repo.FindByStationHour(ctx, evt.StationID, evt.WindowStart)
 	if err != nil {
@@ -134,6 +170,7 @@ func (s *HourlyStatisticAppServiceImpl) HandleTelemetryWindowClosed(ctx context.
 		return err
 	}
 
+	ctx = statistic.WithTrigger(ctx, statistic.TriggerTelemetryWindowClosed)
 	if err := s.repo.Save(ctx, agg); err != nil {
 		if errors.Is(err, ErrDuplicateStatistic) {
 			return nil
@@ -155,3 +192,27 @@ func (s *HourlyStatisticAppServiceImpl) HandleTelemetryWindowClosed(ctx context.
 		Recalculate: evt.Recalculate,
 	})
 }
+
+// RecalculateHour recomputes the hour starting at hourStart from its telemetry,
+// exactly as a recalculate window close would, and returns the stored
+// statistic. Downstream rollups still follow through the bus.
+func (s *HourlyStatisticAppServiceImpl) RecalculateHour(ctx context.Context, stationID string, hourStart time.Time) (*statistic.StatisticAggregate, error) {
+	hourStart = hourStart.UTC()
+	if err := s.HandleTelemetryWindowClosed(ctx, events.TelemetryWindowClosed{
+		StationID:   stationID,
+		WindowStart: hourStart,
+		WindowEnd:   hourStart.Add(time.Hour),
+		OccurredAt:  s.clock.Now(),
+		Recalculate: true,
+	}); err != nil {
+		return nil, err
+	}
+	agg, err := s.repo.FindByStationHour(ctx, stationID, hourStart)
+	if err != nil {
+		return nil, err
+	}
+	if agg == nil {
+		return nil, fmt.Errorf("analytics: station %s hour %s not stored after recalculation", stationID, hourStart.Format(time.RFC3339))
+	}
+	return agg, nil
+}
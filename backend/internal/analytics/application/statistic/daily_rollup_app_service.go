@@ -16,6 +16,20 @@ type DailyRollupAppService struct {
 	repo      domainstatistic.StatisticRepository
 	bus       eventbus.EventBus
 	clock     domainstatistic.Clock
+	batchSize int
+}
+
+// DailyRollupAppOption configures the daily rollup application service.
+type DailyRollupAppOption func(*DailyRollupAppService)
+
+// WithCatchUpBatchSize bounds how many statistics a catch-up pass loads at a
+// time.
+func WithCatchUpBatchSize(size int) DailyRollupAppOption {
+	return func(s *DailyRollupAppService) {
+		if size > 0 {
+			s.batchSize = size
+		}
+	}
 }
 
 // NewDailyRollupAppService constructs the application service.
@@ -24,6 +38,7 @@ func NewDailyRollupAppService(
 	repo domainstatistic.StatisticRepository,
 	bus eventbus.EventBus,
 	clock domainstatistic.Clock,
+	opts ...DailyRollupAppOption,
 ) (*DailyRollupAppService, error) {
 	if rollup == nil {
 		return nil, errors.New("daily rollup app service: nil rollup service")
@@ -35,12 +50,17 @@ func NewDailyRollupAppService(
 		clock = domainstatistic.SystemClock{}
 	}
 
-	return &DailyRollupAppService{
+	service := &DailyRollupAppService{
 		rollup:    rollup,
 		repo:      repo,
 		bus:       bus,
 		clock:     clock,
-	}, nil
+		batchSize: domainstatistic.DefaultListBatchSize,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service, nil
 }
 
 // HandleStatisticCalculated reacts to HOUR statistics and performs day rollups.
@@ -55,24 +75,36 @@ func (s *DailyRollupAppService) HandleStatisticCalculated(ctx context.Context, e
 	}
 	dayStart := time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, period.Location())
 
-	dayAggregate, err := s.rollup.RollupDay(ctx, dayStart, event.Recalculate)
+	_, err := s.rollupDay(ctx, event.StationID, dayStart, event.Recalculate, event.OccurredAt)
+	return err
+}
+
+// rollupDay rolls the day up from its hours, saves it and announces it once
+// completed. It returns the saved day, or nil when nothing was rolled up.
+func (s *DailyRollupAppService) rollupDay(ctx context.Context, stationID string, dayStart time.Time, recalculate bool, occurredAt time.Time) (*domainstatistic.StatisticAggregate, error) {
+	dayAggregate, err := s.rollup.RollupDay(ctx, dayStart, recalculate)
 	if err != nil {
 		if errors.Is(err, domainstatistic.ErrDayAlreadyCompleted) ||
 			errors.Is(err, domainstatistic.ErrIncompleteHourStatistics) ||
 			errors.Is(err, domainstatistic.ErrHourStatisticsNotCompleted) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 	if dayAggregate == nil {
-		return nil
+		return nil, nil
 	}
 
+	ctx = domainstatistic.WithTrigger(ctx, domainstatistic.TriggerStatisticCalculated)
 	if err := s.repo.Save(ctx, dayAggregate); err != nil {
-		return err
+		return nil, err
+	}
+	// A partial day is stored for queries but not announced: settlement and
+	// month rollups only act on completed days.
+	if !dayAggregate.IsCompleted() {
+		return dayAggregate, nil
 	}
 
-	occurredAt := event.OccurredAt
 	if occurredAt.IsZero() {
 		if completedAt, ok := dayAggregate.CompletedAt(); ok {
 			occurredAt = completedAt
@@ -82,16 +114,15 @@ func (s *DailyRollupAppService) HandleStatisticCalculated(ctx context.Context, e
 	}
 
 	if s.bus == nil {
-		return nil
+		return dayAggregate, nil
 	}
 
-	return s.bus.Publish(ctx, events.StatisticCalculated{
-		StationID:   event.StationID,
+	return dayAggregate, s.bus.Publish(ctx, events.StatisticCalculated{
+		StationID:   stationID,
 		StatisticID: dayAggregate.ID(),
 		Granularity: domainstatistic.GranularityDay,
 		PeriodStart: dayAggregate.PeriodStart(),
 		OccurredAt:  occurredAt,
-		Recalculate: event.Recalculate,
+		Recalculate: recalculate,
 	})
 }
-
@@ -0,0 +1,82 @@
+package statistic
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+)
+
+// CatchUpResult summarizes one catch-up pass.
+type CatchUpResult struct {
+	DaysScanned   int
+	DaysRolledUp  int
+	DaysCompleted int
+}
+
+// CatchUp rolls up past days within lookback that have completed hour
+// statistics but no completed day aggregate, e.g. because the service was down
+// when their last hour closed. Completed days are announced like the
+// event-driven path, so month rollups and settlement follow. The current day
+// is left to the event-driven path.
+func (s *DailyRollupAppService) CatchUp(ctx context.Context, stationID string, lookback time.Duration) (CatchUpResult, error) {
+	var result CatchUpResult
+	if lookback <= 0 {
+		return result, errors.New("daily rollup catch-up: lookback must be positive")
+	}
+	now := s.clock.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := now.Add(-lookback)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if !start.Before(end) {
+		return result, nil
+	}
+
+	// Statistics are walked in batches so a long lookback does not load every
+	// hour at once; only per-day flags are kept.
+	completedDays := make(map[time.Time]bool)
+	err := domainstatistic.EachByGranularityAndPeriod(ctx, s.repo, domainstatistic.GranularityDay, start, end, s.batchSize, func(day *domainstatistic.StatisticAggregate) error {
+		if day != nil && day.IsCompleted() {
+			completedDays[day.PeriodStart().UTC()] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+	pending := make(map[time.Time]bool)
+	err = domainstatistic.EachByGranularityAndPeriod(ctx, s.repo, domainstatistic.GranularityHour, start, end, s.batchSize, func(hour *domainstatistic.StatisticAggregate) error {
+		if hour == nil || !hour.IsCompleted() {
+			return nil
+		}
+		period := hour.PeriodStart().UTC()
+		dayStart := time.Date(period.Year(), period.Month(), period.Day(), 0, 0, 0, 0, time.UTC)
+		if !completedDays[dayStart] {
+			pending[dayStart] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	for dayStart := start; dayStart.Before(end); dayStart = dayStart.AddDate(0, 0, 1) {
+		result.DaysScanned++
+		if !pending[dayStart] {
+			continue
+		}
+		dayAggregate, err := s.rollupDay(ctx, stationID, dayStart, false, time.Time{})
+		if err != nil {
+			return result, err
+		}
+		if dayAggregate == nil {
+			continue
+		}
+		result.DaysRolledUp++
+		if dayAggregate.IsCompleted() {
+			result.DaysCompleted++
+		}
+	}
+	return result, nil
+}
@@ -36,15 +36,17 @@ func (f StatisticFact) Validate() error {
 // 1) Only HOUR/DAY/MONTH/YEAR granularity is allowed.
 // 2) Once completed, it is frozen and cannot be modified.
 // 3) Completing twice is an error (idempotency guard).
+// 4) Before completion it may carry a partial fact of the sub-periods present so far.
 // Note: The persistence unique key is subjectId + timeType + timeKey.
 type StatisticAggregate struct {
 	id          StatisticID
 	granularity Granularity
 	periodStart time.Time
 
-	fact        StatisticFact
-	completed   bool
-	completedAt time.Time
+	fact         StatisticFact
+	presentHours int
+	completed    bool
+	completedAt  time.Time
 }
 
 // NewStatisticAggregate creates a new aggregate in "not completed" state.
@@ -84,6 +86,24 @@ func (a *StatisticAggregate) Complete(fact StatisticFact, completedAt time.Time)
 	return nil
 }
 
+// MarkPartial records a preliminary fact summed from presentHours hours while
+// the aggregate is still incomplete.
+func (a *StatisticAggregate) MarkPartial(fact StatisticFact, presentHours int) error {
+	if a.completed {
+		return ErrAlreadyCompleted
+	}
+	if presentHours < 0 {
+		return ErrInvalidPresentHours
+	}
+	if err := fact.Validate(); err != nil {
+		return err
+	}
+
+	a.fact = fact
+	a.presentHours = presentHours
+	return nil
+}
+
 // ID returns aggregate identity.
 func (a *StatisticAggregate) ID() StatisticID { return a.id }
 
@@ -104,6 +124,17 @@ func (a *StatisticAggregate) TimeKey() (TimeKey, error) {
 // Fact returns the computed fact and whether it is available.
 func (a *StatisticAggregate) Fact() (StatisticFact, bool) { return a.fact, a.completed }
 
+// PartialFact returns the preliminary fact and whether the aggregate is partial.
+func (a *StatisticAggregate) PartialFact() (StatisticFact, bool) {
+	if a.completed || a.presentHours == 0 {
+		return StatisticFact{}, false
+	}
+	return a.fact, true
+}
+
+// PresentHours returns how many hours contributed to the fact; 0 when unknown.
+func (a *StatisticAggregate) PresentHours() int { return a.presentHours }
+
 // CompletedAt returns completion timestamp and whether it is available.
 func (a *StatisticAggregate) CompletedAt() (time.Time, bool) {
 	if !a.completed {
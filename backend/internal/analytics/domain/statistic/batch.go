@@ -0,0 +1,59 @@
+package statistic
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultListBatchSize is the number of aggregates loaded per page when
+// walking a period range.
+const DefaultListBatchSize = 500
+
+// StatisticPageLister is implemented by repositories that can list a period
+// range one page at a time, so long ranges are not loaded at once.
+type StatisticPageLister interface {
+	// ListPageByGranularityAndPeriod returns up to limit aggregates within the
+	// range ordered by period start. A non-zero after is the period start of
+	// the last aggregate of the previous page; only later periods are returned.
+	ListPageByGranularityAndPeriod(ctx context.Context, granularity Granularity, startInclusive, endExclusive, after time.Time, limit int) ([]*StatisticAggregate, error)
+}
+
+// EachByGranularityAndPeriod calls fn for every aggregate within the range,
+// loading at most batchSize aggregates at a time when repo implements
+// StatisticPageLister. Other repositories are listed in one call. Aggregates
+// are visited in period order only when paging.
+func EachByGranularityAndPeriod(ctx context.Context, repo StatisticRepository, granularity Granularity, startInclusive, endExclusive time.Time, batchSize int, fn func(*StatisticAggregate) error) error {
+	pager, ok := repo.(StatisticPageLister)
+	if !ok {
+		aggregates, err := repo.ListByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive)
+		if err != nil {
+			return err
+		}
+		for _, agg := range aggregates {
+			if err := fn(agg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if batchSize <= 0 {
+		batchSize = DefaultListBatchSize
+	}
+	var after time.Time
+	for {
+		page, err := pager.ListPageByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive, after, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, agg := range page {
+			if err := fn(agg); err != nil {
+				return err
+			}
+		}
+		if len(page) < batchSize || page[len(page)-1] == nil {
+			return nil
+		}
+		after = page[len(page)-1].PeriodStart()
+	}
+}
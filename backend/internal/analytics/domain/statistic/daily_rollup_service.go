@@ -30,10 +30,23 @@ type DailyRollupService struct {
 	repo          StatisticRepository
 	clock         Clock
 	expectedHours int
+	batchSize     int
+}
+
+// DailyRollupOption configures a DailyRollupService.
+type DailyRollupOption func(*DailyRollupService)
+
+// WithListBatchSize bounds how many hour statistics are loaded at a time.
+func WithListBatchSize(size int) DailyRollupOption {
+	return func(s *DailyRollupService) {
+		if size > 0 {
+			s.batchSize = size
+		}
+	}
 }
 
 // NewDailyRollupService constructs a DailyRollupService.
-func NewDailyRollupService(repo StatisticRepository, clock Clock, expectedHours int) (*DailyRollupService, error) {
+func NewDailyRollupService(repo StatisticRepository, clock Clock, expectedHours int, opts ...DailyRollupOption) (*DailyRollupService, error) {
 	if repo == nil {
 		return nil, errors.New("statistic: nil repository")
 	}
@@ -44,15 +57,22 @@ func NewDailyRollupService(repo StatisticRepository, clock Clock, expectedHours
 		expectedHours = 24
 	}
 
-	return &DailyRollupService{
+	service := &DailyRollupService{
 		repo:          repo,
 		clock:         clock,
 		expectedHours: expectedHours,
-	}, nil
+		batchSize:     DefaultListBatchSize,
+	}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service, nil
 }
 
 // RollupDay aggregates all hour statistics for the day.
-// If force is true, a completed day aggregate will be recalculated and overwritten.
+// When some hours are still missing it returns a partial (not completed) day
+// summing the hours present so far; once every expected hour exists the day is
+// completed. If force is true, a completed day aggregate will be recalculated and overwritten.
 func (s *DailyRollupService) RollupDay(ctx context.Context, dayStart time.Time, force bool) (*StatisticAggregate, error) {
 	if dayStart.IsZero() {
 		return nil, ErrInvalidPeriodStart
@@ -73,44 +93,38 @@ func (s *DailyRollupService) RollupDay(ctx context.Context, dayStart time.Time,
 	}
 
 	dayEnd := dayStart.Add(time.Duration(s.expectedHours) * time.Hour)
-	hours, err := s.repo.ListByGranularityAndPeriod(ctx, GranularityHour, dayStart, dayEnd)
-	if err != nil {
-		return nil, err
-	}
-
 	factByHour := make(map[time.Time]StatisticFact, s.expectedHours)
-	for _, hourAgg := range hours {
+	err = EachByGranularityAndPeriod(ctx, s.repo, GranularityHour, dayStart, dayEnd, s.batchSize, func(hourAgg *StatisticAggregate) error {
 		if hourAgg == nil {
-			continue
+			return nil
 		}
 		if hourAgg.Granularity() != GranularityHour {
-			continue
+			return nil
 		}
 		period := hourAgg.PeriodStart()
 		if period.Before(dayStart) || !period.Before(dayEnd) {
-			continue
+			return nil
 		}
 		fact, ok := hourAgg.Fact()
 		if !ok {
-			return nil, ErrHourStatisticsNotCompleted
+			return ErrHourStatisticsNotCompleted
 		}
 		if err := fact.Validate(); err != nil {
-			return nil, err
+			return err
 		}
 		factByHour[period] = fact
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if len(factByHour) < s.expectedHours {
+	if len(factByHour) == 0 {
 		return nil, ErrIncompleteHourStatistics
 	}
 
 	var sum StatisticFact
-	for i := 0; i < s.expectedHours; i++ {
-		period := dayStart.Add(time.Duration(i) * time.Hour)
-		fact, ok := factByHour[period]
-		if !ok {
-			return nil, ErrIncompleteHourStatistics
-		}
+	for _, fact := range factByHour {
 		sum.ChargeKWh += fact.ChargeKWh
 		sum.DischargeKWh += fact.DischargeKWh
 		sum.Earnings += fact.Earnings
@@ -121,6 +135,12 @@ func (s *DailyRollupService) RollupDay(ctx context.Context, dayStart time.Time,
 	if err != nil {
 		return nil, err
 	}
+	if err := dayAgg.MarkPartial(sum, len(factByHour)); err != nil {
+		return nil, err
+	}
+	if len(factByHour) < s.expectedHours {
+		return dayAgg, nil
+	}
 	if err := dayAgg.Complete(sum, s.clock.Now()); err != nil {
 		return nil, err
 	}
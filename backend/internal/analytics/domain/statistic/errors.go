@@ -13,6 +13,8 @@ var (
 	ErrInvalidCompletedAt = errors.New("statistic: invalid completed_at")
 	// ErrAlreadyCompleted guards idempotent completion.
 	ErrAlreadyCompleted = errors.New("statistic: already completed")
+	// ErrInvalidPresentHours is returned when a partial aggregate has a negative hour count.
+	ErrInvalidPresentHours = errors.New("statistic: invalid present hours")
 	// ErrNegativeFactValue is returned when a fact has negative values.
 	ErrNegativeFactValue = errors.New("statistic: negative fact value")
 	// ErrStatisticNotFound is returned when a statistic aggregate cannot be found.
@@ -0,0 +1,30 @@
+package statistic
+
+import "context"
+
+type contextKey string
+
+const contextKeyTrigger contextKey = "statistic.trigger"
+
+// WithTrigger records which event caused the statistics written under ctx,
+// so repositories can attribute corrections in their history.
+func WithTrigger(ctx context.Context, trigger string) context.Context {
+	return context.WithValue(ctx, contextKeyTrigger, trigger)
+}
+
+// TriggerFromContext extracts the trigger stored by WithTrigger.
+func TriggerFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if trigger, ok := ctx.Value(contextKeyTrigger).(string); ok {
+		return trigger
+	}
+	return ""
+}
+
+// Triggers recorded by the analytics services.
+const (
+	TriggerTelemetryWindowClosed = "telemetry_window_closed"
+	TriggerStatisticCalculated   = "statistic_calculated"
+)
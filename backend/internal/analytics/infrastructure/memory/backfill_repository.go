@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/backfill"
+)
+
+// BackfillRepository is an in-memory backfill job store for demo/testing.
+type BackfillRepository struct {
+	mu   sync.Mutex
+	jobs map[string]*backfill.Job
+}
+
+// NewBackfillRepository constructs a repository.
+func NewBackfillRepository() *BackfillRepository {
+	return &BackfillRepository{jobs: make(map[string]*backfill.Job)}
+}
+
+// Create stores a new job.
+func (r *BackfillRepository) Create(ctx context.Context, job *backfill.Job) error {
+	_ = ctx
+	if job == nil || job.ID == "" {
+		return errors.New("memory backfill repo: invalid job")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.jobs[job.ID]; ok {
+		return errors.New("memory backfill repo: duplicate job")
+	}
+	stored := *job
+	r.jobs[job.ID] = &stored
+	return nil
+}
+
+// Get returns a copy of a job, or nil when it does not exist.
+func (r *BackfillRepository) Get(ctx context.Context, id string) (*backfill.Job, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *job
+	return &copied, nil
+}
+
+// Advance records stage completion on matching open jobs.
+func (r *BackfillRepository) Advance(ctx context.Context, stationID string, stage backfill.Stage, periodStart, at time.Time) (int, error) {
+	_ = ctx
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	advanced := 0
+	for _, job := range r.jobs {
+		period := job.DayStart
+		if stage == backfill.StageHour {
+			period = job.HourStart
+		}
+		if job.StationID != stationID || !period.Equal(periodStart) || job.Status == backfill.StatusCompleted {
+			continue
+		}
+		stamp := at
+		switch stage {
+		case backfill.StageHour:
+			if job.HourAt == nil {
+				job.HourAt = &stamp
+			}
+		case backfill.StageDay:
+			if job.DayAt == nil {
+				job.DayAt = &stamp
+			}
+		case backfill.StageSettlement:
+			if job.SettlementAt == nil {
+				job.SettlementAt = &stamp
+			}
+		}
+		if slices.Contains(stage.Before(), job.Status) {
+			job.Status = stage.Status()
+		}
+		job.UpdatedAt = at
+		advanced++
+	}
+	return advanced, nil
+}
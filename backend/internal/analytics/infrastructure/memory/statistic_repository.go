@@ -3,6 +3,7 @@ package memory
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -85,6 +86,30 @@ func (r *StatisticRepository) ListByGranularityAndPeriod(ctx context.Context, gr
 	return result, nil
 }
 
+// ListPageByGranularityAndPeriod returns one page of aggregates in the given
+// range, ordered by period start, after the previous page's last period.
+func (r *StatisticRepository) ListPageByGranularityAndPeriod(ctx context.Context, granularity statistic.Granularity, startInclusive, endExclusive, after time.Time, limit int) ([]*statistic.StatisticAggregate, error) {
+	if limit <= 0 {
+		return nil, errors.New("memory statistic repo: page limit must be positive")
+	}
+	all, err := r.ListByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].PeriodStart().Before(all[j].PeriodStart()) })
+	page := make([]*statistic.StatisticAggregate, 0, limit)
+	for _, agg := range all {
+		if !after.IsZero() && !agg.PeriodStart().After(after) {
+			continue
+		}
+		page = append(page, agg)
+		if len(page) == limit {
+			break
+		}
+	}
+	return page, nil
+}
+
 // Save persists an aggregate.
 func (r *StatisticRepository) Save(ctx context.Context, agg *statistic.StatisticAggregate) error {
 	_ = ctx
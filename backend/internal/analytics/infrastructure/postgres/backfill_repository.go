@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/backfill"
+)
+
+// BackfillRepository is a Postgres store for backfill jobs.
+type BackfillRepository struct {
+	db *sql.DB
+}
+
+// NewBackfillRepository constructs a repository.
+func NewBackfillRepository(db *sql.DB) *BackfillRepository {
+	return &BackfillRepository{db: db}
+}
+
+// Create inserts a new job.
+func (r *BackfillRepository) Create(ctx context.Context, job *backfill.Job) error {
+	if r == nil || r.db == nil {
+		return errors.New("backfill repo: nil db")
+	}
+	if job == nil || job.ID == "" {
+		return errors.New("backfill repo: invalid job")
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO backfill_jobs (
+	id, tenant_id, station_id, hour_start, day_start, status, created_at, updated_at
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		job.ID, job.TenantID, job.StationID, job.HourStart.UTC(), job.DayStart.UTC(), job.Status, job.CreatedAt, job.UpdatedAt)
+	return err
+}
+
+// Get loads a job, or nil when it does not exist.
+func (r *BackfillRepository) Get(ctx context.Context, id string) (*backfill.Job, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("backfill repo: nil db")
+	}
+	var job backfill.Job
+	var hourAt, dayAt, settlementAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+SELECT id, tenant_id, station_id, hour_start, day_start, status,
+	hour_at, day_at, settlement_at, created_at, updated_at
+FROM backfill_jobs
+WHERE id = $1`, id).Scan(
+		&job.ID,
+		&job.TenantID,
+		&job.StationID,
+		&job.HourStart,
+		&job.DayStart,
+		&job.Status,
+		&hourAt,
+		&dayAt,
+		&settlementAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job.HourStart = job.HourStart.UTC()
+	job.DayStart = job.DayStart.UTC()
+	job.CreatedAt = job.CreatedAt.UTC()
+	job.UpdatedAt = job.UpdatedAt.UTC()
+	job.HourAt = nullTimePtr(hourAt)
+	job.DayAt = nullTimePtr(dayAt)
+	job.SettlementAt = nullTimePtr(settlementAt)
+	return &job, nil
+}
+
+// Advance records stage completion on the open jobs of a station whose hour
+// (StageHour) or day (later stages) starts at periodStart.
+func (r *BackfillRepository) Advance(ctx context.Context, stationID string, stage backfill.Stage, periodStart, at time.Time) (int, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("backfill repo: nil db")
+	}
+	var periodColumn, stampColumn string
+	switch stage {
+	case backfill.StageHour:
+		periodColumn, stampColumn = "hour_start", "hour_at"
+	case backfill.StageDay:
+		periodColumn, stampColumn = "day_start", "day_at"
+	case backfill.StageSettlement:
+		periodColumn, stampColumn = "day_start", "settlement_at"
+	default:
+		return 0, fmt.Errorf("backfill repo: unknown stage %q", stage)
+	}
+	before := stage.Before()
+	placeholders := ""
+	args := []any{stationID, periodStart.UTC(), at.UTC(), stage.Status()}
+	for i, status := range before {
+		if i > 0 {
+			placeholders += ", "
+		}
+		args = append(args, status)
+		placeholders += fmt.Sprintf("$%d", len(args))
+	}
+	result, err := r.db.ExecContext(ctx, fmt.Sprintf(`
+UPDATE backfill_jobs
+SET %[2]s = COALESCE(%[2]s, $3),
+	status = CASE WHEN status IN (%[3]s) THEN $4 ELSE status END,
+	updated_at = $3
+WHERE station_id = $1
+	AND %[1]s = $2
+	AND status <> '%[4]s'`, periodColumn, stampColumn, placeholders, backfill.StatusCompleted), args...)
+	if err != nil {
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	return int(affected), err
+}
+
+func nullTimePtr(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time.UTC()
+	return &t
+}
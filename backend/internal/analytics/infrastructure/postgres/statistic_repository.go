@@ -11,7 +11,11 @@ import (
 	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
 )
 
-const defaultStatisticTable = "analytics_statistics"
+const (
+	defaultStatisticTable = "analytics_statistics"
+	historyTableSuffix    = "_history"
+	defaultHistoryTrigger = "recalculate"
+)
 
 // PostgresStatisticRepository is a Postgres implementation for statistic aggregates.
 // It is subject-scoped: all read/write operations are bound to a subject_id.
@@ -80,7 +84,8 @@ SELECT
 	charge_kwh,
 	discharge_kwh,
 	earnings,
-	carbon_reduction
+	carbon_reduction,
+	present_hours
 FROM %s
 WHERE subject_id = $1
 	AND time_type = $2
@@ -118,7 +123,8 @@ SELECT
 	charge_kwh,
 	discharge_kwh,
 	earnings,
-	carbon_reduction
+	carbon_reduction,
+	present_hours
 FROM %s
 WHERE subject_id = $1
 	AND statistic_id = $2
@@ -137,6 +143,20 @@ LIMIT 1`, r.table)
 
 // ListByGranularityAndPeriod lists statistics within a period range.
 func (r *PostgresStatisticRepository) ListByGranularityAndPeriod(ctx context.Context, granularity domainstatistic.Granularity, startInclusive, endExclusive time.Time) ([]*domainstatistic.StatisticAggregate, error) {
+	return r.listByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive, time.Time{}, 0)
+}
+
+// ListPageByGranularityAndPeriod lists one page of statistics within a period
+// range, after the period start of the previous page's last row (keyset
+// paging on the subject/time_type/period_start index).
+func (r *PostgresStatisticRepository) ListPageByGranularityAndPeriod(ctx context.Context, granularity domainstatistic.Granularity, startInclusive, endExclusive, after time.Time, limit int) ([]*domainstatistic.StatisticAggregate, error) {
+	if limit <= 0 {
+		return nil, errors.New("statistic repo: page limit must be positive")
+	}
+	return r.listByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive, after, limit)
+}
+
+func (r *PostgresStatisticRepository) listByGranularityAndPeriod(ctx context.Context, granularity domainstatistic.Granularity, startInclusive, endExclusive, after time.Time, limit int) ([]*domainstatistic.StatisticAggregate, error) {
 	subjectID, err := r.resolveSubjectID("")
 	if err != nil {
 		return nil, err
@@ -145,6 +165,17 @@ func (r *PostgresStatisticRepository) ListByGranularityAndPeriod(ctx context.Con
 		return nil, domainstatistic.ErrInvalidGranularity
 	}
 
+	args := []any{subjectID, string(granularity), startInclusive, endExclusive}
+	filter := ""
+	if !after.IsZero() {
+		args = append(args, after)
+		filter = fmt.Sprintf("\n\tAND period_start > $%d", len(args))
+	}
+	page := ""
+	if limit > 0 {
+		args = append(args, limit)
+		page = fmt.Sprintf("\nLIMIT $%d", len(args))
+	}
 	query := fmt.Sprintf(`
 SELECT
 	time_type,
@@ -155,15 +186,72 @@ SELECT
 	charge_kwh,
 	discharge_kwh,
 	earnings,
-	carbon_reduction
+	carbon_reduction,
+	present_hours
+FROM %s
+WHERE subject_id = $1
+	AND time_type = $2
+	AND period_start >= $3
+	AND period_start < $4%s
+ORDER BY period_start ASC%s`, r.table, filter, page)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*domainstatistic.StatisticAggregate
+	for rows.Next() {
+		agg, err := scanAggregate(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, agg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListLateByGranularityAndPeriod lists the completed statistics within a
+// period range whose completed_at is more than lateAfter past their
+// period_start, i.e. the periods that settled late because their data did.
+func (r *PostgresStatisticRepository) ListLateByGranularityAndPeriod(ctx context.Context, granularity domainstatistic.Granularity, startInclusive, endExclusive time.Time, lateAfter time.Duration) ([]*domainstatistic.StatisticAggregate, error) {
+	subjectID, err := r.resolveSubjectID("")
+	if err != nil {
+		return nil, err
+	}
+	if !granularity.IsValid() {
+		return nil, domainstatistic.ErrInvalidGranularity
+	}
+	if lateAfter < 0 {
+		return nil, errors.New("statistic repo: late threshold must not be negative")
+	}
+
+	query := fmt.Sprintf(`
+SELECT
+	time_type,
+	period_start,
+	statistic_id,
+	is_completed,
+	completed_at,
+	charge_kwh,
+	discharge_kwh,
+	earnings,
+	carbon_reduction,
+	present_hours
 FROM %s
 WHERE subject_id = $1
 	AND time_type = $2
 	AND period_start >= $3
 	AND period_start < $4
+	AND is_completed
+	AND completed_at > period_start + make_interval(secs => $5)
 ORDER BY period_start ASC`, r.table)
 
-	rows, err := r.db.QueryContext(ctx, query, subjectID, string(granularity), startInclusive, endExclusive)
+	rows, err := r.db.QueryContext(ctx, query, subjectID, string(granularity), startInclusive, endExclusive, lateAfter.Seconds())
 	if err != nil {
 		return nil, err
 	}
@@ -183,7 +271,9 @@ ORDER BY period_start ASC`, r.table)
 	return result, nil
 }
 
-// Save upserts a statistic aggregate for the current subject.
+// Save upserts a statistic aggregate for the current subject. Overwriting a
+// completed row is a recalculation: the previous and new facts are appended to
+// the history table together with the trigger from the context.
 func (r *PostgresStatisticRepository) Save(ctx context.Context, agg *domainstatistic.StatisticAggregate) error {
 	subjectID, err := r.resolveSubjectID("")
 	if err != nil {
@@ -207,6 +297,10 @@ func (r *PostgresStatisticRepository) Save(ctx context.Context, agg *domainstati
 	if ok {
 		completedAtValue = sql.NullTime{Time: completedAt, Valid: true}
 	}
+	presentHours := sql.NullInt32{}
+	if agg.PresentHours() > 0 {
+		presentHours = sql.NullInt32{Int32: int32(agg.PresentHours()), Valid: true}
+	}
 
 	query := fmt.Sprintf(`
 INSERT INTO %s (
@@ -220,9 +314,10 @@ INSERT INTO %s (
 	charge_kwh,
 	discharge_kwh,
 	earnings,
-	carbon_reduction
+	carbon_reduction,
+	present_hours
 ) VALUES (
-	$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+	$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 ON CONFLICT (subject_id, time_type, time_key)
 DO UPDATE SET
@@ -234,9 +329,21 @@ DO UPDATE SET
 	discharge_kwh = EXCLUDED.discharge_kwh,
 	earnings = EXCLUDED.earnings,
 	carbon_reduction = EXCLUDED.carbon_reduction,
+	present_hours = EXCLUDED.present_hours,
 	updated_at = NOW()`, r.table)
 
-	_, err = r.db.ExecContext(
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	before, recalculated, err := r.lockCompletedFact(ctx, tx, subjectID, agg.Granularity(), timeKey)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(
 		ctx,
 		query,
 		subjectID,
@@ -250,6 +357,90 @@ DO UPDATE SET
 		fact.DischargeKWh,
 		fact.Earnings,
 		fact.CarbonReduction,
+		presentHours,
+	); err != nil {
+		return err
+	}
+
+	if recalculated {
+		if err := r.appendHistory(ctx, tx, subjectID, agg, timeKey, before, fact); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// lockCompletedFact locks the stored row and returns its fact when it is completed.
+func (r *PostgresStatisticRepository) lockCompletedFact(ctx context.Context, tx *sql.Tx, subjectID string, granularity domainstatistic.Granularity, timeKey domainstatistic.TimeKey) (domainstatistic.StatisticFact, bool, error) {
+	query := fmt.Sprintf(`
+SELECT is_completed, charge_kwh, discharge_kwh, earnings, carbon_reduction
+FROM %s
+WHERE subject_id = $1
+	AND time_type = $2
+	AND time_key = $3
+FOR UPDATE`, r.table)
+
+	var (
+		fact      domainstatistic.StatisticFact
+		completed bool
+	)
+	err := tx.QueryRowContext(ctx, query, subjectID, string(granularity), timeKey.String()).Scan(
+		&completed,
+		&fact.ChargeKWh,
+		&fact.DischargeKWh,
+		&fact.Earnings,
+		&fact.CarbonReduction,
+	)
+	if err == sql.ErrNoRows {
+		return domainstatistic.StatisticFact{}, false, nil
+	}
+	if err != nil {
+		return domainstatistic.StatisticFact{}, false, err
+	}
+	return fact, completed, nil
+}
+
+func (r *PostgresStatisticRepository) appendHistory(ctx context.Context, tx *sql.Tx, subjectID string, agg *domainstatistic.StatisticAggregate, timeKey domainstatistic.TimeKey, before, after domainstatistic.StatisticFact) error {
+	trigger := domainstatistic.TriggerFromContext(ctx)
+	if trigger == "" {
+		trigger = defaultHistoryTrigger
+	}
+
+	query := fmt.Sprintf(`
+INSERT INTO %s (
+	subject_id,
+	time_type,
+	time_key,
+	statistic_id,
+	before_charge_kwh,
+	before_discharge_kwh,
+	before_earnings,
+	before_carbon_reduction,
+	after_charge_kwh,
+	after_discharge_kwh,
+	after_earnings,
+	after_carbon_reduction,
+	trigger_event
+) VALUES (
+	$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
+)`, r.table+historyTableSuffix)
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		subjectID,
+		string(agg.Granularity()),
+		timeKey.String(),
+		string(agg.ID()),
+		before.ChargeKWh,
+		before.DischargeKWh,
+		before.Earnings,
+		before.CarbonReduction,
+		after.ChargeKWh,
+		after.DischargeKWh,
+		after.Earnings,
+		after.CarbonReduction,
+		trigger,
 	)
 	return err
 }
@@ -269,15 +460,16 @@ func (r *PostgresStatisticRepository) resolveSubjectID(subjectID string) (string
 
 func scanAggregate(scanner interface{ Scan(dest ...any) error }) (*domainstatistic.StatisticAggregate, error) {
 	var (
-		timeType       string
-		periodStart    time.Time
-		statisticID    string
-		isCompleted    bool
-		completedAt    sql.NullTime
-		chargeKWh      float64
-		dischargeKWh   float64
-		earnings       float64
+		timeType        string
+		periodStart     time.Time
+		statisticID     string
+		isCompleted     bool
+		completedAt     sql.NullTime
+		chargeKWh       float64
+		dischargeKWh    float64
+		earnings        float64
 		carbonReduction float64
+		presentHours    sql.NullInt32
 	)
 
 	if err := scanner.Scan(
@@ -290,6 +482,7 @@ func scanAggregate(scanner interface{ Scan(dest ...any) error }) (*domainstatist
 		&dischargeKWh,
 		&earnings,
 		&carbonReduction,
+		&presentHours,
 	); err != nil {
 		return nil, err
 	}
@@ -304,16 +497,21 @@ func scanAggregate(scanner interface{ Scan(dest ...any) error }) (*domainstatist
 		return nil, err
 	}
 
+	fact := domainstatistic.StatisticFact{
+		ChargeKWh:       chargeKWh,
+		DischargeKWh:    dischargeKWh,
+		Earnings:        earnings,
+		CarbonReduction: carbonReduction,
+	}
+	if presentHours.Valid && presentHours.Int32 > 0 {
+		if err := agg.MarkPartial(fact, int(presentHours.Int32)); err != nil {
+			return nil, err
+		}
+	}
 	if isCompleted {
 		if !completedAt.Valid {
 			return nil, domainstatistic.ErrInvalidCompletedAt
 		}
-		fact := domainstatistic.StatisticFact{
-			ChargeKWh:       chargeKWh,
-			DischargeKWh:    dischargeKWh,
-			Earnings:        earnings,
-			CarbonReduction: carbonReduction,
-		}
 		if err := agg.Complete(fact, completedAt.Time); err != nil {
 			return nil, err
 		}
@@ -321,4 +519,3 @@ func scanAggregate(scanner interface{ Scan(dest ...any) error }) (*domainstatist
 
 	return agg, nil
 }
-
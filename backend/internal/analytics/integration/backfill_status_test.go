@@ -0,0 +1,176 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/backfill"
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	appstatistic "microgrid-cloud/internal/analytics/application/statistic"
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	"microgrid-cloud/internal/analytics/infrastructure/memory"
+	analyticsinterfaces "microgrid-cloud/internal/analytics/interfaces"
+	"microgrid-cloud/internal/auth"
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlementmemory "microgrid-cloud/internal/settlement/infrastructure/memory"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+func TestBackfillStatus_CompletesThroughSettlement(t *testing.T) {
+	ctx := context.Background()
+
+	stationID := "station-backfill-001"
+	dayStart := time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)
+	clock := fixedClock{now: dayStart.Add(48 * time.Hour)}
+
+	repo := newRecalcStatisticRepository()
+	bus := eventbus.NewInMemoryBus()
+	telemetry := newTelemetryStore()
+
+	// The in-memory bus delivers depth-first; subscribing the tracker first
+	// lets it observe each stage in cascade order, as outbox dispatch does.
+	tracker, err := backfill.NewTracker(memory.NewBackfillRepository(), clock)
+	if err != nil {
+		t.Fatalf("new backfill tracker: %v", err)
+	}
+	bus.Subscribe(eventbus.EventTypeOf[events.StatisticCalculated](), func(ctx context.Context, event any) error {
+		return tracker.HandleStatisticCalculated(ctx, event.(events.StatisticCalculated))
+	})
+	bus.Subscribe(eventbus.EventTypeOf[settlementapp.SettlementCalculated](), func(ctx context.Context, event any) error {
+		evt := event.(settlementapp.SettlementCalculated)
+		if !evt.Recalculate {
+			return nil
+		}
+		return tracker.HandleSettlementCalculated(ctx, evt.SubjectID, evt.DayStart)
+	})
+
+	hourlyApp := application.NewHourlyStatisticAppService(repo, telemetry, sumStatisticCalculator{}, bus, hourStatisticIDFactory{}, clock)
+	rollupService, err := domainstatistic.NewDailyRollupService(repo, clock, 24)
+	if err != nil {
+		t.Fatalf("new daily rollup service: %v", err)
+	}
+	dailyApp, err := appstatistic.NewDailyRollupAppService(rollupService, repo, bus, clock)
+	if err != nil {
+		t.Fatalf("new daily rollup app service: %v", err)
+	}
+	application.WireAnalyticsEventBus(bus, hourlyApp, dailyApp, nil)
+
+	settlementService, err := settlementapp.NewDaySettlementApplicationService(
+		settlementmemory.NewSettlementRepository(),
+		fixedDayEnergy{kwh: 10},
+		unitTariff{},
+		busSettlementPublisher{bus: bus},
+		clock,
+	)
+	if err != nil {
+		t.Fatalf("new day settlement service: %v", err)
+	}
+	settlementHandler, err := settlementinterfaces.NewDayStatisticCalculatedHandler(settlementService, nil)
+	if err != nil {
+		t.Fatalf("new settlement handler: %v", err)
+	}
+	bus.Subscribe(eventbus.EventTypeOf[events.StatisticCalculated](), settlementHandler.HandleStatisticCalculated)
+
+	for i := 0; i < 24; i++ {
+		hourStart := dayStart.Add(time.Duration(i) * time.Hour)
+		telemetry.SetHour(hourStart, []application.TelemetryPoint{{At: hourStart.Add(10 * time.Minute), ChargePowerKW: 1}})
+		if err := bus.Publish(ctx, events.TelemetryWindowClosed{
+			StationID:   stationID,
+			WindowStart: hourStart,
+			WindowEnd:   hourStart.Add(time.Hour),
+			OccurredAt:  hourStart.Add(30 * time.Minute),
+		}); err != nil {
+			t.Fatalf("publish telemetry window closed: %v", err)
+		}
+	}
+	if waitForDayAggregate(t, ctx, repo, dayStart, 2*time.Second) == nil {
+		t.Fatalf("day aggregate missing")
+	}
+
+	windowClose, err := analyticsinterfaces.NewWindowCloseHandler(bus, nil, analyticsinterfaces.WithBackfillTracker(tracker))
+	if err != nil {
+		t.Fatalf("new window close handler: %v", err)
+	}
+	status, err := analyticsinterfaces.NewBackfillStatusHandler(tracker, nil)
+	if err != nil {
+		t.Fatalf("new backfill status handler: %v", err)
+	}
+
+	backfillHour := dayStart.Add(7 * time.Hour)
+	telemetry.SetHour(backfillHour, []application.TelemetryPoint{{At: backfillHour.Add(15 * time.Minute), ChargePowerKW: 5}})
+	repo.ForceRecalculateHour(backfillHour)
+
+	identity := auth.WithIdentity(ctx, "tenant-backfill", auth.RoleAdmin, "admin")
+	body := `{"stationId":"` + stationID + `","windowStart":"` + backfillHour.Format(time.RFC3339) + `","recalculate":true}`
+	resp := httptest.NewRecorder()
+	windowClose.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/analytics/window-close", strings.NewReader(body)).WithContext(identity))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("window close = %d: %s", resp.Code, resp.Body.String())
+	}
+	var started struct {
+		BackfillID string `json:"backfillId"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &started); err != nil || started.BackfillID == "" {
+		t.Fatalf("backfill id missing: %v (body %s)", err, resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	status.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/analytics/backfills/"+started.BackfillID, nil).WithContext(identity))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("backfill status = %d: %s", resp.Code, resp.Body.String())
+	}
+	var job struct {
+		Status       string     `json:"status"`
+		Done         bool       `json:"done"`
+		HourAt       *time.Time `json:"hourAt"`
+		DayAt        *time.Time `json:"dayAt"`
+		SettlementAt *time.Time `json:"settlementAt"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &job); err != nil {
+		t.Fatalf("decode status: %v", err)
+	}
+	if job.Status != backfill.StatusCompleted || !job.Done {
+		t.Fatalf("backfill status = %s done=%v, want completed", job.Status, job.Done)
+	}
+	if job.HourAt == nil || job.DayAt == nil || job.SettlementAt == nil {
+		t.Fatalf("stage timestamps missing: %+v", job)
+	}
+
+	resp = httptest.NewRecorder()
+	otherTenant := auth.WithIdentity(ctx, "tenant-other", auth.RoleViewer, "viewer")
+	status.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/analytics/backfills/"+started.BackfillID, nil).WithContext(otherTenant))
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("other tenant status = %d, want 404", resp.Code)
+	}
+}
+
+type fixedDayEnergy struct {
+	kwh float64
+}
+
+func (e fixedDayEnergy) ListDayHourEnergy(ctx context.Context, subjectID string, dayStart time.Time) ([]settlementapp.HourEnergy, error) {
+	return []settlementapp.HourEnergy{{HourStart: dayStart, EnergyKWh: e.kwh}}, nil
+}
+
+type unitTariff struct{}
+
+func (unitTariff) PriceAt(ctx context.Context, subjectID string, at time.Time) (float64, error) {
+	return 1, nil
+}
+
+// busSettlementPublisher republishes settlements on the analytics bus, as the
+// outbox does in production.
+type busSettlementPublisher struct {
+	bus eventbus.EventBus
+}
+
+func (p busSettlementPublisher) PublishSettlementCalculated(ctx context.Context, event settlementapp.SettlementCalculated) error {
+	return p.bus.Publish(ctx, event)
+}
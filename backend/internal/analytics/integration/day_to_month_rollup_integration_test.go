@@ -17,7 +17,7 @@ func TestStatisticCalculated_DayToMonthRollup_ClosedLoop(t *testing.T) {
 
 	stationID := "station-integration-month-001"
 	monthStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
-	clock := fixedClock{now: monthStart.Add(72 * time.Hour)}
+	clock := fixedClock{now: monthStart.AddDate(0, 1, 3)}
 
 	expectedDays := 2
 
@@ -0,0 +1,94 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	telemetryadapters "microgrid-cloud/internal/telemetry/adapters/analytics"
+	telemetry "microgrid-cloud/internal/telemetry/domain"
+)
+
+func TestQueryAdapter_DuplicateTimestampsCountedOnce(t *testing.T) {
+	ctx := context.Background()
+	hourStart := time.Date(2026, time.January, 26, 10, 0, 0, 0, time.UTC)
+	// The 10:00 charge reading is resent with a corrected value.
+	query := duplicateTelemetryQuery{points: []telemetry.TelemetryPoint{
+		{At: hourStart, Values: map[string]float64{"charge": 2, "discharge": 1}},
+		{At: hourStart.Add(30 * time.Minute), Values: map[string]float64{"charge": 1}},
+		{At: hourStart, Values: map[string]float64{"charge": 3}},
+	}}
+	mappings := duplicateMappings{
+		{StationID: "station-dup", PointKey: "charge", Semantic: string(masterdata.SemanticChargePowerKW), Factor: 1},
+		{StationID: "station-dup", PointKey: "discharge", Semantic: string(masterdata.SemanticDischargePowerKW), Factor: 1},
+	}
+
+	want := map[telemetry.DuplicatePolicy]float64{
+		"":                       4,
+		telemetry.DuplicateFirst: 3,
+		telemetry.DuplicateLast:  4,
+		telemetry.DuplicateMax:   4,
+	}
+	for policy, charge := range want {
+		adapter, err := telemetryadapters.NewQueryAdapter("tenant-dup", query, mappings, telemetryadapters.WithDuplicatePolicy(policy))
+		if err != nil {
+			t.Fatalf("%q: new adapter: %v", policy, err)
+		}
+		points, err := adapter.QueryHour(ctx, "station-dup", hourStart, hourStart.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("%q: query hour: %v", policy, err)
+		}
+		if len(points) != 2 {
+			t.Fatalf("%q: points = %d, want the duplicate timestamp merged into 2", policy, len(points))
+		}
+		fact, err := telemetryadapters.SumStatisticCalculator{}.CalculateHour(ctx, "station-dup", hourStart, points)
+		if err != nil {
+			t.Fatalf("%q: calculate: %v", policy, err)
+		}
+		if fact.ChargeKWh != charge || fact.DischargeKWh != 1 {
+			t.Fatalf("%q: charge=%v discharge=%v, want %v and 1", policy, fact.ChargeKWh, fact.DischargeKWh, charge)
+		}
+	}
+
+	// Max keeps the larger value whichever arrives first.
+	query.points[2].Values["charge"] = 0.5
+	adapter, err := telemetryadapters.NewQueryAdapter("tenant-dup", query, mappings, telemetryadapters.WithDuplicatePolicy(telemetry.DuplicateMax))
+	if err != nil {
+		t.Fatalf("max: new adapter: %v", err)
+	}
+	points, err := adapter.QueryHour(ctx, "station-dup", hourStart, hourStart.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("max: query hour: %v", err)
+	}
+	if points[0].ChargePowerKW != 2 {
+		t.Fatalf("max: charge at 10:00 = %v, want 2", points[0].ChargePowerKW)
+	}
+
+	for _, value := range []string{"", "First", " max "} {
+		if _, err := telemetry.ParseDuplicatePolicy(value); err != nil {
+			t.Fatalf("parse %q: %v", value, err)
+		}
+	}
+	if _, err := telemetry.ParseDuplicatePolicy("sum"); err == nil {
+		t.Fatalf("expected error for unknown policy")
+	}
+}
+
+type duplicateTelemetryQuery struct {
+	points []telemetry.TelemetryPoint
+}
+
+func (q duplicateTelemetryQuery) QueryHour(context.Context, string, string, time.Time, time.Time) ([]telemetry.TelemetryPoint, error) {
+	return q.points, nil
+}
+
+type duplicateMappings []masterdata.PointMapping
+
+func (m duplicateMappings) ListByStation(context.Context, string) ([]masterdata.PointMapping, error) {
+	return m, nil
+}
+
+func (m duplicateMappings) Save(context.Context, *masterdata.PointMapping) error {
+	return nil
+}
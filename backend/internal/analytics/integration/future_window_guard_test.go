@@ -0,0 +1,57 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+)
+
+func TestHourlyStatistic_RejectsFutureWindow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.January, 20, 10, 2, 0, 0, time.UTC)
+	repo := newRecalcStatisticRepository()
+	bus := eventbus.NewInMemoryBus()
+	recorder := newEventRecorder()
+	bus.Subscribe(eventbus.EventTypeOf[events.StatisticCalculated](), recorder.HandleStatisticCalculated)
+
+	hourlyApp := application.NewHourlyStatisticAppService(
+		repo,
+		newTelemetryStore(),
+		sumStatisticCalculator{},
+		bus,
+		hourStatisticIDFactory{},
+		fixedClock{now: now},
+		application.WithFutureWindowSkew(5*time.Minute),
+	)
+
+	future := time.Date(2026, time.January, 20, 11, 0, 0, 0, time.UTC)
+	err := hourlyApp.HandleTelemetryWindowClosed(ctx, events.TelemetryWindowClosed{
+		StationID:   "station-future-001",
+		WindowStart: future,
+		WindowEnd:   future.Add(time.Hour),
+	})
+	if !errors.Is(err, application.ErrFutureWindow) {
+		t.Fatalf("expected ErrFutureWindow, got %v", err)
+	}
+	if agg, _ := repo.FindByStationHour(ctx, "station-future-001", future); agg != nil {
+		t.Fatalf("future window must not be stored")
+	}
+
+	// The hour that just closed is within the allowed skew and still computed.
+	closed := time.Date(2026, time.January, 20, 9, 0, 0, 0, time.UTC)
+	if err := hourlyApp.HandleTelemetryWindowClosed(ctx, events.TelemetryWindowClosed{
+		StationID:   "station-future-001",
+		WindowStart: closed,
+		WindowEnd:   closed.Add(time.Hour),
+	}); err != nil {
+		t.Fatalf("closed window: %v", err)
+	}
+	if hourCount, _, _, _ := recorder.Counts(); hourCount != 1 {
+		t.Fatalf("expected 1 hour statistic event, got %d", hourCount)
+	}
+}
@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestListLateByGranularityAndPeriod_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	if !tableExists(db, "analytics_statistics") {
+		t.Skip("required tables missing; run migrations")
+	}
+
+	ctx := context.Background()
+	stationID := "station-it-late"
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+	defer db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+
+	repo := analyticsrepo.NewPostgresStatisticRepository(db, stationID)
+	monthStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	onTime := monthStart.Add(10 * time.Hour)
+	late := monthStart.Add(11 * time.Hour)
+	boundary := monthStart.Add(12 * time.Hour)
+	nextMonth := monthStart.AddDate(0, 1, 0)
+
+	// Hours complete 5 minutes, exactly 6 hours and 2 days after they start;
+	// the hour of the next month is late but out of range.
+	for _, hour := range []struct {
+		start       time.Time
+		completedAt time.Time
+	}{
+		{onTime, onTime.Add(time.Hour + 5*time.Minute)},
+		{late, late.Add(48 * time.Hour)},
+		{boundary, boundary.Add(6 * time.Hour)},
+		{nextMonth, nextMonth.Add(48 * time.Hour)},
+	} {
+		agg, err := domainstatistic.NewStatisticAggregate(domainstatistic.StatisticID("stat-late-"+hour.start.Format("20060102T15")), domainstatistic.GranularityHour, hour.start)
+		if err != nil {
+			t.Fatalf("new aggregate: %v", err)
+		}
+		if err := agg.Complete(domainstatistic.StatisticFact{ChargeKWh: 1}, hour.completedAt); err != nil {
+			t.Fatalf("complete aggregate: %v", err)
+		}
+		if err := repo.Save(ctx, agg); err != nil {
+			t.Fatalf("save %s: %v", hour.start, err)
+		}
+	}
+
+	got, err := repo.ListLateByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, monthStart, nextMonth, 6*time.Hour)
+	if err != nil {
+		t.Fatalf("list late: %v", err)
+	}
+	if len(got) != 1 || !got[0].PeriodStart().Equal(late) {
+		t.Fatalf("late hours = %d, want only %s", len(got), late)
+	}
+	if completedAt, ok := got[0].CompletedAt(); !ok || !completedAt.Equal(late.Add(48*time.Hour)) {
+		t.Fatalf("completed_at = %v (%t)", completedAt, ok)
+	}
+
+	all, err := repo.ListLateByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, monthStart, nextMonth, 0)
+	if err != nil {
+		t.Fatalf("list late with zero threshold: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("hours completed after their start = %d, want 3", len(all))
+	}
+	if _, err := repo.ListLateByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, monthStart, nextMonth, -time.Hour); err == nil {
+		t.Fatalf("negative threshold accepted")
+	}
+}
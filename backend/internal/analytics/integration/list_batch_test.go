@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	"microgrid-cloud/internal/analytics/infrastructure/memory"
+	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
+)
+
+func TestEachByGranularityAndPeriod_BatchesMatchWholeRange(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+
+	repo := &countingPager{StatisticRepository: memory.NewStatisticRepository()}
+	seedBatchStatistics(t, ctx, repo, start)
+
+	whole, err := repo.ListByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, start, end)
+	if err != nil {
+		t.Fatalf("list whole range: %v", err)
+	}
+	sort.Slice(whole, func(i, j int) bool { return whole[i].PeriodStart().Before(whole[j].PeriodStart()) })
+
+	for _, batchSize := range []int{1, 7, 58, 59, 500} {
+		repo.pages = 0
+		var batched []*domainstatistic.StatisticAggregate
+		err := domainstatistic.EachByGranularityAndPeriod(ctx, repo, domainstatistic.GranularityHour, start, end, batchSize, func(agg *domainstatistic.StatisticAggregate) error {
+			batched = append(batched, agg)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("batch %d: %v", batchSize, err)
+		}
+		assertSameAggregates(t, batchSize, batched, whole)
+		if want := len(whole)/batchSize + 1; repo.pages != want {
+			t.Fatalf("batch %d: %d page queries, want %d", batchSize, repo.pages, want)
+		}
+	}
+}
+
+func TestEachByGranularityAndPeriod_BatchesMatchWholeRange_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	if !tableExists(db, "analytics_statistics") {
+		t.Skip("required tables missing; run migrations")
+	}
+
+	ctx := context.Background()
+	stationID := "station-it-list-batch"
+	start := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(72 * time.Hour)
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+
+	repo := analyticsrepo.NewPostgresStatisticRepository(db, stationID)
+	seedBatchStatistics(t, ctx, repo, start)
+
+	whole, err := repo.ListByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, start, end)
+	if err != nil {
+		t.Fatalf("list whole range: %v", err)
+	}
+	var batched []*domainstatistic.StatisticAggregate
+	err = domainstatistic.EachByGranularityAndPeriod(ctx, repo, domainstatistic.GranularityHour, start, end, 7, func(agg *domainstatistic.StatisticAggregate) error {
+		batched = append(batched, agg)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("batched walk: %v", err)
+	}
+	assertSameAggregates(t, 7, batched, whole)
+}
+
+// seedBatchStatistics stores three days of hour statistics with gaps, plus
+// day statistics that must not show up in hour listings.
+func seedBatchStatistics(t *testing.T, ctx context.Context, repo domainstatistic.StatisticRepository, start time.Time) {
+	t.Helper()
+	for i := 0; i < 72; i++ {
+		if i%5 == 3 {
+			continue
+		}
+		saveCompleted(t, ctx, repo, domainstatistic.GranularityHour, start.Add(time.Duration(i)*time.Hour), float64(i))
+	}
+	for d := 0; d < 3; d++ {
+		saveCompleted(t, ctx, repo, domainstatistic.GranularityDay, start.AddDate(0, 0, d), 1)
+	}
+}
+
+func saveCompleted(t *testing.T, ctx context.Context, repo domainstatistic.StatisticRepository, granularity domainstatistic.Granularity, periodStart time.Time, charge float64) {
+	t.Helper()
+	id, err := domainstatistic.BuildStatisticID(granularity, periodStart)
+	if err != nil {
+		t.Fatalf("build id: %v", err)
+	}
+	agg, err := domainstatistic.NewStatisticAggregate(id, granularity, periodStart)
+	if err != nil {
+		t.Fatalf("new aggregate: %v", err)
+	}
+	if err := agg.Complete(domainstatistic.StatisticFact{ChargeKWh: charge}, periodStart.Add(time.Hour)); err != nil {
+		t.Fatalf("complete aggregate: %v", err)
+	}
+	if err := repo.Save(ctx, agg); err != nil {
+		t.Fatalf("save aggregate: %v", err)
+	}
+}
+
+func assertSameAggregates(t *testing.T, batchSize int, got, want []*domainstatistic.StatisticAggregate) {
+	t.Helper()
+	if len(want) == 0 || len(got) != len(want) {
+		t.Fatalf("batch %d: %d aggregates, want %d", batchSize, len(got), len(want))
+	}
+	for i := range want {
+		if got[i].ID() != want[i].ID() || !got[i].PeriodStart().Equal(want[i].PeriodStart()) {
+			t.Fatalf("batch %d: aggregate %d = %s, want %s", batchSize, i, got[i].ID(), want[i].ID())
+		}
+	}
+}
+
+// countingPager counts page queries against the memory repository.
+type countingPager struct {
+	*memory.StatisticRepository
+	pages int
+}
+
+func (r *countingPager) ListPageByGranularityAndPeriod(ctx context.Context, granularity domainstatistic.Granularity, startInclusive, endExclusive, after time.Time, limit int) ([]*domainstatistic.StatisticAggregate, error) {
+	r.pages++
+	return r.StatisticRepository.ListPageByGranularityAndPeriod(ctx, granularity, startInclusive, endExclusive, after, limit)
+}
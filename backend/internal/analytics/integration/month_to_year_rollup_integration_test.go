@@ -17,7 +17,7 @@ func TestStatisticCalculated_MonthToYearRollup_ClosedLoop(t *testing.T) {
 
 	stationID := "station-integration-year-001"
 	yearStart := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
-	clock := fixedClock{now: yearStart.Add(72 * time.Hour)}
+	clock := fixedClock{now: yearStart.AddDate(1, 0, 3)}
 
 	expectedMonths := 2
 	expectedDaysPerMonth := 1
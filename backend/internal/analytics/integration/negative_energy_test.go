@@ -0,0 +1,50 @@
+package integration_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/energy"
+	telemetryadapters "microgrid-cloud/internal/telemetry/adapters/analytics"
+)
+
+func TestSumStatisticCalculator_NegativeEnergyPolicies(t *testing.T) {
+	hourStart := time.Date(2026, time.January, 25, 10, 0, 0, 0, time.UTC)
+	// Discharge reported with the wrong sign sums to -8 kWh for the hour.
+	points := []application.TelemetryPoint{
+		{At: hourStart, ChargePowerKW: 3, DischargePowerKW: -5},
+		{At: hourStart.Add(30 * time.Minute), ChargePowerKW: 1, DischargePowerKW: -3},
+	}
+
+	want := map[energy.NegativePolicy]float64{
+		energy.NegativePassThrough: -8,
+		energy.NegativeClamp:       0,
+		energy.NegativeAbsolute:    8,
+	}
+	for policy, discharge := range want {
+		fact, err := telemetryadapters.SumStatisticCalculator{NegativeEnergy: policy}.CalculateHour(context.Background(), "station-negative", hourStart, points)
+		if err != nil {
+			t.Fatalf("%s: calculate: %v", policy, err)
+		}
+		if fact.ChargeKWh != 4 || fact.DischargeKWh != discharge {
+			t.Fatalf("%s: charge=%v discharge=%v, want 4 and %v", policy, fact.ChargeKWh, fact.DischargeKWh, discharge)
+		}
+	}
+
+	_, err := telemetryadapters.SumStatisticCalculator{NegativeEnergy: energy.NegativeReject}.CalculateHour(context.Background(), "station-negative", hourStart, points)
+	if !errors.Is(err, energy.ErrNegativeEnergy) {
+		t.Fatalf("reject: err = %v, want ErrNegativeEnergy", err)
+	}
+
+	for _, value := range []string{"", "Clamp", " absolute "} {
+		if _, err := energy.ParseNegativePolicy(value); err != nil {
+			t.Fatalf("parse %q: %v", value, err)
+		}
+	}
+	if _, err := energy.ParseNegativePolicy("ignore"); err == nil {
+		t.Fatalf("expected error for unknown policy")
+	}
+}
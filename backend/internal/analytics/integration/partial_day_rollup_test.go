@@ -0,0 +1,88 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	appstatistic "microgrid-cloud/internal/analytics/application/statistic"
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+)
+
+func TestDailyRollup_PartialDayThenCompleted(t *testing.T) {
+	ctx := context.Background()
+	stationID := "station-partial-001"
+	dayStart := time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)
+	clock := fixedClock{now: dayStart.Add(48 * time.Hour)}
+
+	repo := newRecalcStatisticRepository()
+	bus := eventbus.NewInMemoryBus()
+	telemetry := newTelemetryStore()
+	recorder := newEventRecorder()
+
+	hourlyApp := application.NewHourlyStatisticAppService(repo, telemetry, sumStatisticCalculator{}, bus, hourStatisticIDFactory{}, clock)
+	rollupService, err := domainstatistic.NewDailyRollupService(repo, clock, 24)
+	if err != nil {
+		t.Fatalf("new daily rollup service: %v", err)
+	}
+	dailyApp, err := appstatistic.NewDailyRollupAppService(rollupService, repo, bus, clock)
+	if err != nil {
+		t.Fatalf("new daily rollup app service: %v", err)
+	}
+	application.WireAnalyticsEventBus(bus, hourlyApp, dailyApp, nil)
+	bus.Subscribe(eventbus.EventTypeOf[events.StatisticCalculated](), recorder.HandleStatisticCalculated)
+
+	publishHours := func(from, to int) {
+		for i := from; i < to; i++ {
+			hourStart := dayStart.Add(time.Duration(i) * time.Hour)
+			telemetry.SetHour(hourStart, []application.TelemetryPoint{{At: hourStart.Add(10 * time.Minute), ChargePowerKW: 2, DischargePowerKW: 1}})
+			if err := bus.Publish(ctx, events.TelemetryWindowClosed{
+				StationID:   stationID,
+				WindowStart: hourStart,
+				WindowEnd:   hourStart.Add(time.Hour),
+			}); err != nil {
+				t.Fatalf("publish hour %d: %v", i, err)
+			}
+		}
+	}
+
+	dayID, err := domainstatistic.BuildStatisticID(domainstatistic.GranularityDay, dayStart)
+	if err != nil {
+		t.Fatalf("day id: %v", err)
+	}
+
+	publishHours(0, 12)
+	day, err := repo.Get(ctx, dayID)
+	if err != nil {
+		t.Fatalf("partial day missing: %v", err)
+	}
+	if day.IsCompleted() {
+		t.Fatalf("day with 12 of 24 hours must not be completed")
+	}
+	partial, ok := day.PartialFact()
+	if !ok || day.PresentHours() != 12 || partial.ChargeKWh != 24 || partial.DischargeKWh != 12 {
+		t.Fatalf("unexpected partial day: present=%d fact=%+v ok=%v", day.PresentHours(), partial, ok)
+	}
+	if _, dayCount, _, _ := recorder.Counts(); dayCount != 0 {
+		t.Fatalf("partial day must not publish a day event, got %d", dayCount)
+	}
+
+	publishHours(12, 24)
+	day, err = repo.Get(ctx, dayID)
+	if err != nil {
+		t.Fatalf("completed day missing: %v", err)
+	}
+	fact, completed := day.Fact()
+	if !completed || day.PresentHours() != 24 || fact.ChargeKWh != 48 || fact.DischargeKWh != 24 {
+		t.Fatalf("unexpected completed day: completed=%v present=%d fact=%+v", completed, day.PresentHours(), fact)
+	}
+	if _, ok := day.PartialFact(); ok {
+		t.Fatalf("completed day must not report a partial fact")
+	}
+	if _, dayCount, _, _ := recorder.Counts(); dayCount != 1 {
+		t.Fatalf("expected 1 day event after completion, got %d", dayCount)
+	}
+}
@@ -0,0 +1,137 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/domain/statistic"
+	analyticsinterfaces "microgrid-cloud/internal/analytics/interfaces"
+	"microgrid-cloud/internal/auth"
+)
+
+func TestRecalculateHour_ReturnsRecomputedFact(t *testing.T) {
+	hourStart := time.Date(2026, time.January, 20, 10, 0, 0, 0, time.UTC)
+	service := &stubHourRecalculator{fact: statistic.StatisticFact{ChargeKWh: 3, DischargeKWh: 1.5, Earnings: 2, CarbonReduction: 0.5}}
+	handler, err := analyticsinterfaces.NewRecalculateHourHandler(service, recalcChecker{}, nil)
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+
+	rec := serveRecalculateHour(handler, "tenant-recalc", `{"station_id":"station-recalc","hour_start":"2026-01-20T10:00:00Z"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		StationID    string     `json:"station_id"`
+		StatisticID  string     `json:"statistic_id"`
+		PeriodStart  time.Time  `json:"period_start"`
+		IsCompleted  bool       `json:"is_completed"`
+		CompletedAt  *time.Time `json:"completed_at"`
+		ChargeKWh    float64    `json:"charge_kwh"`
+		DischargeKWh float64    `json:"discharge_kwh"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.StationID != "station-recalc" || !body.PeriodStart.Equal(hourStart) || !body.IsCompleted || body.CompletedAt == nil {
+		t.Fatalf("response = %+v", body)
+	}
+	if body.ChargeKWh != 3 || body.DischargeKWh != 1.5 {
+		t.Fatalf("fact = charge %v discharge %v, want 3 and 1.5", body.ChargeKWh, body.DischargeKWh)
+	}
+	if len(service.calls) != 1 || service.calls[0] != "station-recalc@"+hourStart.Format(time.RFC3339) {
+		t.Fatalf("service calls = %v", service.calls)
+	}
+}
+
+func TestRecalculateHour_RejectsInvalidRequests(t *testing.T) {
+	service := &stubHourRecalculator{}
+	handler, err := analyticsinterfaces.NewRecalculateHourHandler(service, recalcChecker{}, nil)
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		tenant string
+		body   string
+		status int
+		code   string
+	}{
+		{"invalid json", "tenant-recalc", `{`, http.StatusBadRequest, "invalid_json"},
+		{"missing station", "tenant-recalc", `{"hour_start":"2026-01-20T10:00:00Z"}`, http.StatusBadRequest, "invalid_argument"},
+		{"bad hour", "tenant-recalc", `{"station_id":"station-recalc","hour_start":"2026-01-20"}`, http.StatusBadRequest, "invalid_argument"},
+		{"unaligned hour", "tenant-recalc", `{"station_id":"station-recalc","hour_start":"2026-01-20T10:30:00Z"}`, http.StatusBadRequest, "invalid_argument"},
+		{"other tenant", "tenant-other", `{"station_id":"station-recalc","hour_start":"2026-01-20T10:00:00Z"}`, http.StatusForbidden, "forbidden"},
+		{"unknown station", "tenant-recalc", `{"station_id":"station-missing","hour_start":"2026-01-20T10:00:00Z"}`, http.StatusNotFound, "not_found"},
+		{"open hour", "tenant-recalc", `{"station_id":"station-recalc","hour_start":"2099-01-01T00:00:00Z"}`, http.StatusBadRequest, "invalid_argument"},
+	}
+	for _, tc := range cases {
+		rec := serveRecalculateHour(handler, tc.tenant, tc.body)
+		if rec.Code != tc.status {
+			t.Fatalf("%s: status = %d, want %d", tc.name, rec.Code, tc.status)
+		}
+		var body struct {
+			Error struct {
+				Code string `json:"code"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Error.Code != tc.code {
+			t.Fatalf("%s: error body %s, want code %s", tc.name, rec.Body.String(), tc.code)
+		}
+	}
+	// Only the open hour got past validation and the tenant check.
+	if len(service.calls) != 1 {
+		t.Fatalf("service calls = %v, want only the open hour", service.calls)
+	}
+}
+
+func serveRecalculateHour(handler http.Handler, tenantID, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/analytics/recalculate-hour", strings.NewReader(body))
+	req = req.WithContext(auth.WithIdentity(req.Context(), tenantID, auth.RoleOperator, "operator"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// stubHourRecalculator completes the requested hour with a fixed fact and
+// refuses hours after 2030 as not closed yet.
+type stubHourRecalculator struct {
+	fact  statistic.StatisticFact
+	calls []string
+}
+
+func (s *stubHourRecalculator) RecalculateHour(_ context.Context, stationID string, hourStart time.Time) (*statistic.StatisticAggregate, error) {
+	s.calls = append(s.calls, stationID+"@"+hourStart.Format(time.RFC3339))
+	if hourStart.Year() > 2030 {
+		return nil, fmt.Errorf("%w: station %s", application.ErrFutureWindow, stationID)
+	}
+	agg, err := statistic.NewStatisticAggregate(statistic.StatisticID(stationID+"-hour"), statistic.GranularityHour, hourStart)
+	if err != nil {
+		return nil, err
+	}
+	if err := agg.Complete(s.fact, hourStart.Add(2*time.Hour)); err != nil {
+		return nil, err
+	}
+	return agg, nil
+}
+
+// recalcChecker lets tenant-recalc use station-recalc only.
+type recalcChecker struct{}
+
+func (recalcChecker) EnsureStationTenant(_ context.Context, tenantID, stationID string) error {
+	if stationID != "station-recalc" {
+		return auth.ErrNotFound
+	}
+	if tenantID != "tenant-recalc" {
+		return auth.ErrTenantMismatch
+	}
+	return nil
+}
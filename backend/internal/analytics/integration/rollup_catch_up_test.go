@@ -0,0 +1,86 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	appstatistic "microgrid-cloud/internal/analytics/application/statistic"
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+)
+
+func TestDailyRollupCatchUp_RollsUpDayMissedDuringDowntime(t *testing.T) {
+	ctx := context.Background()
+	stationID := "station-catch-up-001"
+	missedDay := time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)
+	clock := fixedClock{now: missedDay.Add(50 * time.Hour)}
+
+	repo := newRecalcStatisticRepository()
+	telemetry := newTelemetryStore()
+
+	// Hours were calculated while the day rollup was not listening.
+	hourlyApp := application.NewHourlyStatisticAppService(repo, telemetry, sumStatisticCalculator{}, eventbus.NewInMemoryBus(), hourStatisticIDFactory{}, clock)
+	for i := 0; i < 24; i++ {
+		hourStart := missedDay.Add(time.Duration(i) * time.Hour)
+		telemetry.SetHour(hourStart, []application.TelemetryPoint{{At: hourStart.Add(10 * time.Minute), ChargePowerKW: 2, DischargePowerKW: 1}})
+		if err := hourlyApp.HandleTelemetryWindowClosed(ctx, events.TelemetryWindowClosed{
+			StationID:   stationID,
+			WindowStart: hourStart,
+			WindowEnd:   hourStart.Add(time.Hour),
+		}); err != nil {
+			t.Fatalf("close hour %d: %v", i, err)
+		}
+	}
+	dayID, err := domainstatistic.BuildStatisticID(domainstatistic.GranularityDay, missedDay)
+	if err != nil {
+		t.Fatalf("day id: %v", err)
+	}
+	if _, err := repo.Get(ctx, dayID); err == nil {
+		t.Fatalf("day aggregate must be missing before catch-up")
+	}
+
+	bus := eventbus.NewInMemoryBus()
+	recorder := newEventRecorder()
+	bus.Subscribe(eventbus.EventTypeOf[events.StatisticCalculated](), recorder.HandleStatisticCalculated)
+	rollupService, err := domainstatistic.NewDailyRollupService(repo, clock, 24)
+	if err != nil {
+		t.Fatalf("new daily rollup service: %v", err)
+	}
+	dailyApp, err := appstatistic.NewDailyRollupAppService(rollupService, repo, bus, clock)
+	if err != nil {
+		t.Fatalf("new daily rollup app service: %v", err)
+	}
+
+	// A lookback that ends before the missed day does not reach it.
+	if result, err := dailyApp.CatchUp(ctx, stationID, 24*time.Hour); err != nil || result.DaysRolledUp != 0 {
+		t.Fatalf("short lookback = %+v, %v", result, err)
+	}
+
+	result, err := dailyApp.CatchUp(ctx, stationID, 72*time.Hour)
+	if err != nil {
+		t.Fatalf("catch up: %v", err)
+	}
+	if result.DaysScanned != 3 || result.DaysRolledUp != 1 || result.DaysCompleted != 1 {
+		t.Fatalf("catch-up result = %+v", result)
+	}
+	day, err := repo.Get(ctx, dayID)
+	if err != nil {
+		t.Fatalf("day aggregate after catch-up: %v", err)
+	}
+	fact, ok := day.Fact()
+	if !day.IsCompleted() || !ok || fact.ChargeKWh != 48 {
+		t.Fatalf("day completed=%t fact=%+v", day.IsCompleted(), fact)
+	}
+	if _, dayEvents, _, _ := recorder.Counts(); dayEvents != 1 {
+		t.Fatalf("expected one DAY StatisticCalculated, got %d", dayEvents)
+	}
+
+	// Completed days are not rolled up again.
+	again, err := dailyApp.CatchUp(ctx, stationID, 72*time.Hour)
+	if err != nil || again.DaysRolledUp != 0 {
+		t.Fatalf("second catch-up = %+v, %v", again, err)
+	}
+}
@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestHourlyBackfill_RecordsStatisticsHistory_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "analytics_statistics") || !tableExists(db, "analytics_statistics_history") {
+		t.Skip("required tables missing; run migrations")
+	}
+
+	ctx := context.Background()
+	stationID := "station-it-history"
+	hourStart := time.Date(2026, time.January, 22, 8, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics_history WHERE subject_id = $1", stationID)
+
+	statsRepo := analyticsrepo.NewPostgresStatisticRepository(db, stationID)
+	telemetry := newTelemetryStore()
+	hourlyApp := application.NewHourlyStatisticAppService(
+		statsRepo,
+		telemetry,
+		sumStatisticCalculator{},
+		eventbus.NewInMemoryBus(),
+		hourStatisticIDFactory{},
+		fixedClock{now: hourStart.Add(2 * time.Hour)},
+	)
+
+	closeWindow := func(chargeKW float64, recalculate bool) {
+		telemetry.SetHour(hourStart, []application.TelemetryPoint{{At: hourStart.Add(10 * time.Minute), ChargePowerKW: chargeKW}})
+		if err := hourlyApp.HandleTelemetryWindowClosed(ctx, events.TelemetryWindowClosed{
+			StationID:   stationID,
+			WindowStart: hourStart,
+			WindowEnd:   hourStart.Add(time.Hour),
+			Recalculate: recalculate,
+		}); err != nil {
+			t.Fatalf("close window (recalculate=%t): %v", recalculate, err)
+		}
+	}
+
+	closeWindow(4, false)
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM analytics_statistics_history WHERE subject_id = $1", stationID).Scan(&count); err != nil {
+		t.Fatalf("count history: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("first calculation must not write history, got %d rows", count)
+	}
+
+	// Backfill: late telemetry changes the hour and the window is recalculated.
+	closeWindow(6, true)
+
+	var (
+		timeType      string
+		timeKey       string
+		beforeCharge  float64
+		afterCharge   float64
+		triggerEvent  string
+		historyRecord time.Time
+	)
+	if err := db.QueryRowContext(ctx, `
+SELECT time_type, time_key, before_charge_kwh, after_charge_kwh, trigger_event, recorded_at
+FROM analytics_statistics_history
+WHERE subject_id = $1`, stationID).Scan(&timeType, &timeKey, &beforeCharge, &afterCharge, &triggerEvent, &historyRecord); err != nil {
+		t.Fatalf("load history: %v", err)
+	}
+	if timeType != string(domainstatistic.GranularityHour) {
+		t.Fatalf("time_type = %q, want HOUR", timeType)
+	}
+	if beforeCharge != 4 || afterCharge != 6 {
+		t.Fatalf("charge before/after = %v/%v, want 4/6", beforeCharge, afterCharge)
+	}
+	if triggerEvent != domainstatistic.TriggerTelemetryWindowClosed {
+		t.Fatalf("trigger_event = %q, want %q", triggerEvent, domainstatistic.TriggerTelemetryWindowClosed)
+	}
+	if historyRecord.IsZero() {
+		t.Fatalf("recorded_at not set")
+	}
+	if timeKey == "" {
+		t.Fatalf("time_key not set")
+	}
+}
@@ -0,0 +1,87 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/backfill"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+const backfillPathPrefix = "/analytics/backfills/"
+
+// BackfillStatusHandler reports the progress of backfill jobs.
+type BackfillStatusHandler struct {
+	tracker *backfill.Tracker
+	logger  *log.Logger
+}
+
+// NewBackfillStatusHandler constructs the handler.
+func NewBackfillStatusHandler(tracker *backfill.Tracker, logger *log.Logger) (*BackfillStatusHandler, error) {
+	if tracker == nil {
+		return nil, errors.New("backfill status handler: nil tracker")
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &BackfillStatusHandler{tracker: tracker, logger: logger}, nil
+}
+
+type backfillStatusResponse struct {
+	ID           string     `json:"id"`
+	StationID    string     `json:"stationId"`
+	HourStart    string     `json:"hourStart"`
+	DayStart     string     `json:"dayStart"`
+	Status       string     `json:"status"`
+	Done         bool       `json:"done"`
+	HourAt       *time.Time `json:"hourAt,omitempty"`
+	DayAt        *time.Time `json:"dayAt,omitempty"`
+	SettlementAt *time.Time `json:"settlementAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// ServeHTTP handles GET /analytics/backfills/{id}.
+func (h *BackfillStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	id := strings.TrimPrefix(r.URL.Path, backfillPathPrefix)
+	if id == "" || strings.Contains(id, "/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	job, err := h.tracker.Get(r.Context(), id)
+	if err != nil {
+		h.logger.Printf("backfill status: load %s error: %v", id, err)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "load backfill error")
+		return
+	}
+	// Jobs of another tenant are reported as missing rather than forbidden.
+	if tenantID := auth.TenantIDFromContext(r.Context()); job == nil || (tenantID != "" && job.TenantID != "" && job.TenantID != tenantID) {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "backfill not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(backfillStatusResponse{
+		ID:           job.ID,
+		StationID:    job.StationID,
+		HourStart:    job.HourStart.Format(time.RFC3339),
+		DayStart:     job.DayStart.Format(time.RFC3339),
+		Status:       job.Status,
+		Done:         job.Status == backfill.StatusCompleted,
+		HourAt:       job.HourAt,
+		DayAt:        job.DayAt,
+		SettlementAt: job.SettlementAt,
+		CreatedAt:    job.CreatedAt,
+		UpdatedAt:    job.UpdatedAt,
+	})
+}
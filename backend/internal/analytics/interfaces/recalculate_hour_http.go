@@ -0,0 +1,131 @@
+package interfaces
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/domain/statistic"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+// HourRecalculator recomputes a single hourly statistic synchronously.
+type HourRecalculator interface {
+	RecalculateHour(ctx context.Context, stationID string, hourStart time.Time) (*statistic.StatisticAggregate, error)
+}
+
+// RecalculateHourHandler recomputes one hour in the request and returns the
+// stored statistic, instead of publishing a window close for the consumer.
+type RecalculateHourHandler struct {
+	service        HourRecalculator
+	stationChecker auth.StationTenantChecker
+	logger         *log.Logger
+}
+
+// NewRecalculateHourHandler constructs the handler.
+func NewRecalculateHourHandler(service HourRecalculator, checker auth.StationTenantChecker, logger *log.Logger) (*RecalculateHourHandler, error) {
+	if service == nil {
+		return nil, errors.New("recalculate hour handler: nil service")
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &RecalculateHourHandler{service: service, stationChecker: checker, logger: logger}, nil
+}
+
+type recalculateHourRequest struct {
+	StationID string `json:"station_id"`
+	HourStart string `json:"hour_start"`
+}
+
+type recalculateHourResponse struct {
+	StationID       string     `json:"station_id"`
+	StatisticID     string     `json:"statistic_id"`
+	PeriodStart     time.Time  `json:"period_start"`
+	IsCompleted     bool       `json:"is_completed"`
+	CompletedAt     *time.Time `json:"completed_at"`
+	ChargeKWh       float64    `json:"charge_kwh"`
+	DischargeKWh    float64    `json:"discharge_kwh"`
+	Earnings        float64    `json:"earnings"`
+	CarbonReduction float64    `json:"carbon_reduction"`
+}
+
+// ServeHTTP handles POST /api/v1/analytics/recalculate-hour.
+func (h *RecalculateHourHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req recalculateHourRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	if req.StationID == "" {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
+		return
+	}
+	hourStart, err := time.Parse(time.RFC3339, req.HourStart)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "hour_start must be RFC3339")
+		return
+	}
+	hourStart = hourStart.UTC()
+	if !hourStart.Equal(hourStart.Truncate(time.Hour)) {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "hour_start must be on the hour")
+		return
+	}
+
+	if tenantID := auth.TenantIDFromContext(r.Context()); tenantID != "" && h.stationChecker != nil {
+		if err := h.stationChecker.EnsureStationTenant(r.Context(), tenantID, req.StationID); err != nil {
+			respondTenantError(w, err)
+			return
+		}
+	}
+
+	agg, err := h.service.RecalculateHour(r.Context(), req.StationID, hourStart)
+	if err != nil {
+		if errors.Is(err, application.ErrFutureWindow) {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "hour has not closed yet")
+			return
+		}
+		h.logger.Printf("recalculate hour: station %s hour %s error: %v", req.StationID, hourStart.Format(time.RFC3339), err)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "recalculate hour error")
+		return
+	}
+
+	fact, completed := agg.Fact()
+	response := recalculateHourResponse{
+		StationID:       req.StationID,
+		StatisticID:     string(agg.ID()),
+		PeriodStart:     agg.PeriodStart(),
+		IsCompleted:     completed,
+		ChargeKWh:       fact.ChargeKWh,
+		DischargeKWh:    fact.DischargeKWh,
+		Earnings:        fact.Earnings,
+		CarbonReduction: fact.CarbonReduction,
+	}
+	if completedAt, ok := agg.CompletedAt(); ok {
+		response.CompletedAt = &completedAt
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+func respondTenantError(w http.ResponseWriter, err error) {
+	if errors.Is(err, auth.ErrTenantMismatch) {
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
+		return
+	}
+	if errors.Is(err, auth.ErrNotFound) {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
+		return
+	}
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
+}
@@ -8,26 +8,45 @@ import (
 	"net/http"
 	"time"
 
+	"microgrid-cloud/internal/analytics/application/backfill"
 	"microgrid-cloud/internal/analytics/application/eventbus"
 	"microgrid-cloud/internal/analytics/application/events"
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
 	"microgrid-cloud/internal/observability/metrics"
 )
 
 // WindowCloseHandler publishes TelemetryWindowClosed events.
 type WindowCloseHandler struct {
-	bus    eventbus.EventBus
-	logger *log.Logger
+	bus       eventbus.EventBus
+	logger    *log.Logger
+	backfills *backfill.Tracker
+}
+
+// WindowCloseOption configures the window close handler.
+type WindowCloseOption func(*WindowCloseHandler)
+
+// WithBackfillTracker records a backfill job for every recalculate request;
+// its id is returned as backfillId.
+func WithBackfillTracker(tracker *backfill.Tracker) WindowCloseOption {
+	return func(h *WindowCloseHandler) {
+		h.backfills = tracker
+	}
 }
 
 // NewWindowCloseHandler constructs the handler.
-func NewWindowCloseHandler(bus eventbus.EventBus, logger *log.Logger) (*WindowCloseHandler, error) {
+func NewWindowCloseHandler(bus eventbus.EventBus, logger *log.Logger, opts ...WindowCloseOption) (*WindowCloseHandler, error) {
 	if bus == nil {
 		return nil, errors.New("window close handler: nil event bus")
 	}
 	if logger == nil {
 		logger = log.Default()
 	}
-	return &WindowCloseHandler{bus: bus, logger: logger}, nil
+	handler := &WindowCloseHandler{bus: bus, logger: logger}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	return handler, nil
 }
 
 // ServeHTTP publishes TelemetryWindowClosed events.
@@ -45,7 +64,7 @@ func (h *WindowCloseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		result = metrics.ResultError
 		h.logger.Printf("window close: read body error: %v", err)
-		http.Error(w, "read body error", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "read body error")
 		metrics.ObserveWindowClose(result, time.Since(start))
 		return
 	}
@@ -55,7 +74,7 @@ func (h *WindowCloseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err := json.Unmarshal(body, &req); err != nil {
 		result = metrics.ResultError
 		h.logger.Printf("window close: decode error: %v", err)
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		metrics.ObserveWindowClose(result, time.Since(start))
 		return
 	}
@@ -64,11 +83,25 @@ func (h *WindowCloseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		result = metrics.ResultError
 		h.logger.Printf("window close: invalid payload: %v", err)
-		http.Error(w, "invalid payload", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "invalid payload")
 		metrics.ObserveWindowClose(result, time.Since(start))
 		return
 	}
 
+	// The job is recorded before publishing so that no stage can complete
+	// before it exists.
+	var job *backfill.Job
+	if req.Recalculate && h.backfills != nil {
+		job, err = h.backfills.Start(r.Context(), auth.TenantIDFromContext(r.Context()), req.StationID, windowStart)
+		if err != nil {
+			result = metrics.ResultError
+			h.logger.Printf("window close: backfill job error: %v", err)
+			apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "backfill job error")
+			metrics.ObserveWindowClose(result, time.Since(start))
+			return
+		}
+	}
+
 	if err := h.bus.Publish(r.Context(), events.TelemetryWindowClosed{
 		StationID:   req.StationID,
 		WindowStart: windowStart,
@@ -78,17 +111,21 @@ func (h *WindowCloseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}); err != nil {
 		result = metrics.ResultError
 		h.logger.Printf("window close: publish error: %v", err)
-		http.Error(w, "publish error", http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "publish error")
 		metrics.ObserveWindowClose(result, time.Since(start))
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]any{
+	response := map[string]any{
 		"status":      "ok",
 		"windowStart": windowStart.Format(time.RFC3339),
 		"windowEnd":   windowEnd.Format(time.RFC3339),
-	})
+	}
+	if job != nil {
+		response["backfillId"] = job.ID
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
 	duration := time.Since(start)
 	metrics.ObserveWindowClose(result, duration)
 	h.logger.Printf("window_close duration_ms=%d station_id=%s window_start=%s window_end=%s recalc=%t result=%s",
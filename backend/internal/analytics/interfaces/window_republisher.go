@@ -0,0 +1,53 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	"microgrid-cloud/internal/clock"
+)
+
+// WindowRepublisher publishes TelemetryWindowClosed recalculations for
+// windows found missing by reconciliation.
+type WindowRepublisher struct {
+	bus   eventbus.EventBus
+	clock clock.Clock
+}
+
+// WindowRepublisherOption configures the window republisher.
+type WindowRepublisherOption func(*WindowRepublisher)
+
+// WithRepublisherClock overrides the clock that stamps OccurredAt.
+func WithRepublisherClock(c clock.Clock) WindowRepublisherOption {
+	return func(p *WindowRepublisher) {
+		if c != nil {
+			p.clock = c
+		}
+	}
+}
+
+// NewWindowRepublisher constructs the republisher.
+func NewWindowRepublisher(bus eventbus.EventBus, opts ...WindowRepublisherOption) (*WindowRepublisher, error) {
+	if bus == nil {
+		return nil, errors.New("window republisher: nil event bus")
+	}
+	p := &WindowRepublisher{bus: bus, clock: clock.System{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// PublishWindowClosed publishes a recalculating TelemetryWindowClosed event.
+func (p *WindowRepublisher) PublishWindowClosed(ctx context.Context, stationID string, windowStart, windowEnd time.Time) error {
+	return p.bus.Publish(ctx, events.TelemetryWindowClosed{
+		StationID:   stationID,
+		WindowStart: windowStart.UTC(),
+		WindowEnd:   windowEnd.UTC(),
+		OccurredAt:  p.clock.Now().UTC(),
+		Recalculate: true,
+	})
+}
@@ -0,0 +1,46 @@
+// Package apierror writes API error responses as JSON, so clients can branch
+// on a stable code instead of parsing the message.
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Error codes. The HTTP status stays the primary signal; the code tells apart
+// failures that share one.
+const (
+	CodeInvalidJSON     = "invalid_json"
+	CodeInvalidArgument = "invalid_argument"
+	CodeUnauthenticated = "unauthenticated"
+	CodeForbidden       = "forbidden"
+	CodeNotFound        = "not_found"
+	CodeConflict        = "conflict"
+	CodeTooLarge        = "too_large"
+	CodeInternal        = "internal"
+	CodeNotImplemented  = "not_implemented"
+	CodeUnavailable     = "unavailable"
+	CodeTimeout         = "timeout"
+	CodeRateLimited     = "rate_limited"
+)
+
+// Body is the JSON error response.
+type Body struct {
+	Error Detail `json:"error"`
+}
+
+// Detail describes one error.
+type Detail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Write replies with status and {"error": {"code", "message"}}. Like
+// http.Error, it expects nothing else to have been written to w.
+func Write(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Body{Error: Detail{Code: code, Message: message}})
+}
@@ -0,0 +1,29 @@
+package apierror
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	resp := httptest.NewRecorder()
+	resp.Header().Set("Content-Type", "text/csv")
+	resp.Header().Set("Content-Length", "42")
+
+	Write(resp, http.StatusConflict, CodeConflict, `statement "s-1" is frozen`)
+
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Fatalf("content type = %q", ct)
+	}
+	if cl := resp.Header().Get("Content-Length"); cl != "" {
+		t.Fatalf("stale content length %q kept", cl)
+	}
+	want := `{"error":{"code":"conflict","message":"statement \"s-1\" is frozen"}}` + "\n"
+	if resp.Body.String() != want {
+		t.Fatalf("body = %q, want %q", resp.Body.String(), want)
+	}
+}
@@ -6,10 +6,15 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/api/pagination"
 	"microgrid-cloud/internal/auth"
 )
 
@@ -19,11 +24,12 @@ const timeLayout = time.RFC3339
 type StatsHandler struct {
 	db             *sql.DB
 	stationChecker auth.StationTenantChecker
+	queryLimits
 }
 
 // NewStatsHandler constructs a StatsHandler.
-func NewStatsHandler(db *sql.DB, stationChecker auth.StationTenantChecker) *StatsHandler {
-	return &StatsHandler{db: db, stationChecker: stationChecker}
+func NewStatsHandler(db *sql.DB, stationChecker auth.StationTenantChecker, opts ...QueryOption) *StatsHandler {
+	return &StatsHandler{db: db, stationChecker: stationChecker, queryLimits: newQueryLimits(opts)}
 }
 
 // ServeHTTP handles GET /api/v1/stats.
@@ -33,13 +39,13 @@ func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if h == nil || h.db == nil {
-		http.Error(w, "server not ready", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "server not ready")
 		return
 	}
 
 	stationID := r.URL.Query().Get("station_id")
 	if stationID == "" {
-		http.Error(w, "station_id is required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
 		return
 	}
 
@@ -51,33 +57,32 @@ func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	from, err := parseTimeQuery(r, "from")
+	granularity := r.URL.Query().Get("granularity")
+	timeType, err := resolveTimeType(granularity)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	to, err := parseTimeQuery(r, "to")
+	from, to, err := h.queryRange(r, granularity)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-
-	granularity := r.URL.Query().Get("granularity")
-	timeType, err := resolveTimeType(granularity)
+	page, err := pagination.Parse(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 
-	stats, err := queryStats(r.Context(), h.db, tenantID, stationID, timeType, from, to)
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	stats, total, err := queryStats(ctx, h.db, tenantID, stationID, timeType, from, to, page)
 	if err != nil {
-		http.Error(w, "query stats error", http.StatusInternalServerError)
+		respondQueryError(ctx, w, err, "query stats error")
 		return
 	}
+	pagination.SetHeaders(w, page, total)
+	roundStatRows(stats, h.floatPrecision)
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(stats)
@@ -88,21 +93,22 @@ type SettlementsHandler struct {
 	db             *sql.DB
 	tenantID       string
 	stationChecker auth.StationTenantChecker
+	queryLimits
 }
 
 // NewSettlementsHandler constructs a SettlementsHandler.
-func NewSettlementsHandler(db *sql.DB, tenantID string, stationChecker auth.StationTenantChecker) *SettlementsHandler {
-	return &SettlementsHandler{db: db, tenantID: tenantID, stationChecker: stationChecker}
+func NewSettlementsHandler(db *sql.DB, tenantID string, stationChecker auth.StationTenantChecker, opts ...QueryOption) *SettlementsHandler {
+	return &SettlementsHandler{db: db, tenantID: tenantID, stationChecker: stationChecker, queryLimits: newQueryLimits(opts)}
 }
 
-// ServeHTTP handles GET /api/v1/settlements.
+// ServeHTTP handles GET /api/v1/settlements and GET /api/v1/settlements/{station}/{day}.
 func (h *SettlementsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 	if h == nil || h.db == nil {
-		http.Error(w, "server not ready", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "server not ready")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
@@ -110,13 +116,18 @@ func (h *SettlementsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		tenantID = h.tenantID
 	}
 	if tenantID == "" {
-		http.Error(w, "tenant_id is required", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "tenant_id is required")
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, settlementsPathPrefix) {
+		h.serveDay(w, r, tenantID)
 		return
 	}
 
 	stationID := r.URL.Query().Get("station_id")
 	if stationID == "" {
-		http.Error(w, "station_id is required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
 		return
 	}
 
@@ -127,29 +138,67 @@ func (h *SettlementsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	from, err := parseTimeQuery(r, "from")
+	from, to, err := h.queryRange(r, "day")
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	page, err := pagination.Parse(r)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	rows, total, err := querySettlements(ctx, h.db, tenantID, stationID, from, to, page)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		respondQueryError(ctx, w, err, "query settlements error")
 		return
 	}
-	to, err := parseTimeQuery(r, "to")
+	pagination.SetHeaders(w, page, total)
+	roundSettlementRows(rows, h.floatPrecision)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(rows)
+}
+
+const settlementsPathPrefix = "/api/v1/settlements/"
+
+// serveDay handles GET /api/v1/settlements/{station}/{day}, where day is a
+// UTC date (YYYY-MM-DD), answering 404 when the day has no settlement.
+func (h *SettlementsHandler) serveDay(w http.ResponseWriter, r *http.Request, tenantID string) {
+	stationID, dayKey, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, settlementsPathPrefix), "/")
+	if !ok || stationID == "" || dayKey == "" || strings.Contains(dayKey, "/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	day, err := time.Parse("2006-01-02", dayKey)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "day must be YYYY-MM-DD")
 		return
 	}
-	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+	if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
+		respondTenantError(w, err)
 		return
 	}
 
-	rows, err := querySettlements(r.Context(), h.db, tenantID, stationID, from, to)
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	row, err := querySettlementDay(ctx, h.db, tenantID, stationID, day)
 	if err != nil {
-		http.Error(w, "query settlements error", http.StatusInternalServerError)
+		respondQueryError(ctx, w, err, "query settlement error")
+		return
+	}
+	if row == nil {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "settlement not found")
 		return
 	}
+	rows := []settlementRow{*row}
+	roundSettlementRows(rows, h.floatPrecision)
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(rows)
+	_ = json.NewEncoder(w).Encode(rows[0])
 }
 
 // ExportSettlementsCSVHandler serves settlement CSV exports.
@@ -157,11 +206,12 @@ type ExportSettlementsCSVHandler struct {
 	db             *sql.DB
 	tenantID       string
 	stationChecker auth.StationTenantChecker
+	queryLimits
 }
 
 // NewExportSettlementsCSVHandler constructs a ExportSettlementsCSVHandler.
-func NewExportSettlementsCSVHandler(db *sql.DB, tenantID string, stationChecker auth.StationTenantChecker) *ExportSettlementsCSVHandler {
-	return &ExportSettlementsCSVHandler{db: db, tenantID: tenantID, stationChecker: stationChecker}
+func NewExportSettlementsCSVHandler(db *sql.DB, tenantID string, stationChecker auth.StationTenantChecker, opts ...QueryOption) *ExportSettlementsCSVHandler {
+	return &ExportSettlementsCSVHandler{db: db, tenantID: tenantID, stationChecker: stationChecker, queryLimits: newQueryLimits(opts)}
 }
 
 // ServeHTTP handles GET /api/v1/exports/settlements.csv.
@@ -171,7 +221,7 @@ func (h *ExportSettlementsCSVHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 		return
 	}
 	if h == nil || h.db == nil {
-		http.Error(w, "server not ready", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "server not ready")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
@@ -179,13 +229,13 @@ func (h *ExportSettlementsCSVHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 		tenantID = h.tenantID
 	}
 	if tenantID == "" {
-		http.Error(w, "tenant_id is required", http.StatusServiceUnavailable)
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "tenant_id is required")
 		return
 	}
 
 	stationID := r.URL.Query().Get("station_id")
 	if stationID == "" {
-		http.Error(w, "station_id is required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
 		return
 	}
 
@@ -196,54 +246,48 @@ func (h *ExportSettlementsCSVHandler) ServeHTTP(w http.ResponseWriter, r *http.R
 		}
 	}
 
-	from, err := parseTimeQuery(r, "from")
+	from, to, err := h.queryRange(r, "day")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	to, err := parseTimeQuery(r, "to")
+	delimiter, bom, err := parseCSVDialect(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+	columns, err := parseCSVColumns(r, settlementCSVColumns)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 
-	rows, err := querySettlements(r.Context(), h.db, tenantID, stationID, from, to)
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	rows, _, err := querySettlements(ctx, h.db, tenantID, stationID, from, to, pagination.Page{})
 	if err != nil {
-		http.Error(w, "query settlements error", http.StatusInternalServerError)
+		respondQueryError(ctx, w, err, "query settlements error")
 		return
 	}
+	roundSettlementRows(rows, h.floatPrecision)
 
 	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	if bom {
+		_, _ = w.Write(utf8BOM)
+	}
 	writer := csv.NewWriter(w)
-	_ = writer.Write([]string{
-		"tenant_id",
-		"station_id",
-		"day_start",
-		"energy_kwh",
-		"amount",
-		"currency",
-		"status",
-		"version",
-		"created_at",
-		"updated_at",
-	})
+	writer.Comma = delimiter
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.name
+	}
+	_ = writer.Write(header)
+	record := make([]string, len(columns))
 	for _, row := range rows {
-		_ = writer.Write([]string{
-			row.TenantID,
-			row.StationID,
-			row.DayStart.Format(timeLayout),
-			formatFloat(row.EnergyKWh),
-			formatFloat(row.Amount),
-			row.Currency,
-			row.Status,
-			formatInt(row.Version),
-			formatTime(row.CreatedAt),
-			formatTime(row.UpdatedAt),
-		})
+		for i, column := range columns {
+			record[i] = column.value(row)
+		}
+		_ = writer.Write(record)
 	}
 	writer.Flush()
 }
@@ -260,6 +304,7 @@ type statRow struct {
 	DischargeKWh    float64    `json:"discharge_kwh"`
 	Earnings        float64    `json:"earnings"`
 	CarbonReduction float64    `json:"carbon_reduction"`
+	PresentHours    *int       `json:"present_hours,omitempty"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
@@ -277,30 +322,28 @@ type settlementRow struct {
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
-func queryStats(ctx context.Context, db *sql.DB, tenantID, stationID, timeType string, from, to time.Time) ([]statRow, error) {
-	query := `
-SELECT
-	subject_id,
-	time_type,
-	time_key,
-	period_start,
-	statistic_id,
-	is_completed,
-	completed_at,
-	charge_kwh,
-	discharge_kwh,
-	earnings,
-	carbon_reduction,
-	created_at,
-	updated_at
-FROM analytics_statistics
-WHERE subject_id = $1
-	AND time_type = $2
-	AND period_start >= $3
-	AND period_start < $4`
+// queryStats loads the statistics of the station in [from, to), applying
+// page in SQL. total counts every matching row.
+func queryStats(ctx context.Context, db *sql.DB, tenantID, stationID, timeType string, from, to time.Time, page pagination.Page) ([]statRow, int, error) {
+	where := `
+FROM analytics_statistics s
+WHERE s.subject_id = $1
+	AND s.time_type = $2
+	AND s.period_start >= $3
+	AND s.period_start < $4`
 	args := []any{stationID, timeType, from.UTC(), to.UTC()}
 	if tenantID != "" {
-		query = `
+		where = `
+FROM analytics_statistics s
+JOIN stations st ON st.id = s.subject_id
+WHERE st.tenant_id = $1
+	AND s.subject_id = $2
+	AND s.time_type = $3
+	AND s.period_start >= $4
+	AND s.period_start < $5`
+		args = []any{tenantID, stationID, timeType, from.UTC(), to.UTC()}
+	}
+	query := `
 SELECT
 	s.subject_id,
 	s.time_type,
@@ -313,21 +356,13 @@ SELECT
 	s.discharge_kwh,
 	s.earnings,
 	s.carbon_reduction,
+	s.present_hours,
 	s.created_at,
-	s.updated_at
-FROM analytics_statistics s
-JOIN stations st ON st.id = s.subject_id
-WHERE st.tenant_id = $1
-	AND s.subject_id = $2
-	AND s.time_type = $3
-	AND s.period_start >= $4
-	AND s.period_start < $5`
-		args = []any{tenantID, stationID, timeType, from.UTC(), to.UTC()}
-	}
-	query += "\nORDER BY period_start ASC"
-	rows, err := db.QueryContext(ctx, query, args...)
+	s.updated_at` + where + "\nORDER BY s.period_start ASC"
+	pageQuery, pageArgs := pageSQL(query, args, page)
+	rows, err := db.QueryContext(ctx, pageQuery, pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -335,6 +370,7 @@ WHERE st.tenant_id = $1
 	for rows.Next() {
 		var row statRow
 		var completedAt sql.NullTime
+		var presentHours sql.NullInt32
 		if err := rows.Scan(
 			&row.SubjectID,
 			&row.TimeType,
@@ -347,10 +383,11 @@ WHERE st.tenant_id = $1
 			&row.DischargeKWh,
 			&row.Earnings,
 			&row.CarbonReduction,
+			&presentHours,
 			&row.CreatedAt,
 			&row.UpdatedAt,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		row.PeriodStart = row.PeriodStart.UTC()
 		row.CreatedAt = row.CreatedAt.UTC()
@@ -359,16 +396,33 @@ WHERE st.tenant_id = $1
 			t := completedAt.Time.UTC()
 			row.CompletedAt = &t
 		}
+		if presentHours.Valid {
+			n := int(presentHours.Int32)
+			row.PresentHours = &n
+		}
 		result = append(result, row)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return result, nil
+	total, err := countRows(ctx, db, where, args, page, len(result))
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
 }
 
-func querySettlements(ctx context.Context, db *sql.DB, tenantID, stationID string, from, to time.Time) ([]settlementRow, error) {
-	rows, err := db.QueryContext(ctx, `
+// querySettlements loads the day settlements of the station in [from, to),
+// applying page in SQL. total counts every matching row.
+func querySettlements(ctx context.Context, db *sql.DB, tenantID, stationID string, from, to time.Time, page pagination.Page) ([]settlementRow, int, error) {
+	where := `
+FROM settlements_day
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND day_start >= $3
+	AND day_start < $4`
+	args := []any{tenantID, stationID, from.UTC(), to.UTC()}
+	query := `
 SELECT
 	tenant_id,
 	station_id,
@@ -379,15 +433,11 @@ SELECT
 	status,
 	version,
 	created_at,
-	updated_at
-FROM settlements_day
-WHERE tenant_id = $1
-	AND station_id = $2
-	AND day_start >= $3
-	AND day_start < $4
-ORDER BY day_start ASC`, tenantID, stationID, from.UTC(), to.UTC())
+	updated_at` + where + "\nORDER BY day_start ASC"
+	pageQuery, pageArgs := pageSQL(query, args, page)
+	rows, err := db.QueryContext(ctx, pageQuery, pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -406,7 +456,7 @@ ORDER BY day_start ASC`, tenantID, stationID, from.UTC(), to.UTC())
 			&row.CreatedAt,
 			&row.UpdatedAt,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		row.DayStart = row.DayStart.UTC()
 		row.CreatedAt = row.CreatedAt.UTC()
@@ -414,9 +464,84 @@ ORDER BY day_start ASC`, tenantID, stationID, from.UTC(), to.UTC())
 		result = append(result, row)
 	}
 	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	total, err := countRows(ctx, db, where, args, page, len(result))
+	if err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
+}
+
+// pageSQL appends the LIMIT and OFFSET of page to query as parameters after
+// args.
+func pageSQL(query string, args []any, page pagination.Page) (string, []any) {
+	pageArgs := append([]any(nil), args...)
+	if page.Limit > 0 {
+		pageArgs = append(pageArgs, page.Limit)
+		query += fmt.Sprintf("\nLIMIT $%d", len(pageArgs))
+	}
+	if page.Offset > 0 {
+		pageArgs = append(pageArgs, page.Offset)
+		query += fmt.Sprintf("\nOFFSET $%d", len(pageArgs))
+	}
+	return query, pageArgs
+}
+
+// countRows counts the rows matching where (a FROM ... WHERE clause) for a
+// paged request. An unpaged request loaded every matching row, so loaded is
+// the total.
+func countRows(ctx context.Context, db *sql.DB, where string, args []any, page pagination.Page, loaded int) (int, error) {
+	if !page.Paged() {
+		return loaded, nil
+	}
+	var total int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*)"+where, args...).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// querySettlementDay loads the settlement of one day; a missing day yields nil.
+func querySettlementDay(ctx context.Context, db *sql.DB, tenantID, stationID string, day time.Time) (*settlementRow, error) {
+	var row settlementRow
+	err := db.QueryRowContext(ctx, `
+SELECT
+	tenant_id,
+	station_id,
+	day_start,
+	energy_kwh,
+	amount,
+	currency,
+	status,
+	version,
+	created_at,
+	updated_at
+FROM settlements_day
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND day_start = $3`, tenantID, stationID, day.UTC()).Scan(
+		&row.TenantID,
+		&row.StationID,
+		&row.DayStart,
+		&row.EnergyKWh,
+		&row.Amount,
+		&row.Currency,
+		&row.Status,
+		&row.Version,
+		&row.CreatedAt,
+		&row.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
-	return result, nil
+	row.DayStart = row.DayStart.UTC()
+	row.CreatedAt = row.CreatedAt.UTC()
+	row.UpdatedAt = row.UpdatedAt.UTC()
+	return &row, nil
 }
 
 func ensureStationTenant(r *http.Request, checker auth.StationTenantChecker, tenantID, stationID string) error {
@@ -431,14 +556,14 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
 
 func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
@@ -453,6 +578,88 @@ func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
 	return parsed.UTC(), nil
 }
 
+// utf8BOM lets Excel detect UTF-8 when opening a CSV export.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// parseCSVDialect reads the optional delimiter and bom query params of CSV
+// exports. The delimiter is a single character or "tab"; it defaults to a
+// comma and no BOM is written unless bom=true.
+func parseCSVDialect(r *http.Request) (rune, bool, error) {
+	query := r.URL.Query()
+	delimiter := ','
+	switch value := query.Get("delimiter"); value {
+	case "":
+	case "tab":
+		delimiter = '\t'
+	default:
+		runes := []rune(value)
+		if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' || runes[0] == utf8.RuneError {
+			return 0, false, errors.New("delimiter must be a single character other than a quote or line break, or tab")
+		}
+		delimiter = runes[0]
+	}
+	bom := false
+	if value := query.Get("bom"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return 0, false, errors.New("bom must be true or false")
+		}
+		bom = parsed
+	}
+	return delimiter, bom, nil
+}
+
+// csvColumn is one selectable column of a CSV export.
+type csvColumn[T any] struct {
+	name  string
+	value func(T) string
+}
+
+// settlementCSVColumns is the settlement export column set, in default order.
+var settlementCSVColumns = []csvColumn[settlementRow]{
+	{"tenant_id", func(row settlementRow) string { return row.TenantID }},
+	{"station_id", func(row settlementRow) string { return row.StationID }},
+	{"day_start", func(row settlementRow) string { return row.DayStart.Format(timeLayout) }},
+	{"energy_kwh", func(row settlementRow) string { return formatFloat(row.EnergyKWh) }},
+	{"amount", func(row settlementRow) string { return formatFloat(row.Amount) }},
+	{"currency", func(row settlementRow) string { return row.Currency }},
+	{"status", func(row settlementRow) string { return row.Status }},
+	{"version", func(row settlementRow) string { return formatInt(row.Version) }},
+	{"created_at", func(row settlementRow) string { return formatTime(row.CreatedAt) }},
+	{"updated_at", func(row settlementRow) string { return formatTime(row.UpdatedAt) }},
+}
+
+// parseCSVColumns reads the optional comma-separated columns query param of
+// CSV exports, which selects and orders the columns. Without it every known
+// column is written in default order.
+func parseCSVColumns[T any](r *http.Request, known []csvColumn[T]) ([]csvColumn[T], error) {
+	value := r.URL.Query().Get("columns")
+	if value == "" {
+		return known, nil
+	}
+	byName := make(map[string]csvColumn[T], len(known))
+	names := make([]string, len(known))
+	for i, column := range known {
+		byName[column.name] = column
+		names[i] = column.name
+	}
+	seen := make(map[string]bool)
+	var selected []csvColumn[T]
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		column, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q; columns are %s", name, strings.Join(names, ","))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("column %q is listed twice", name)
+		}
+		seen[name] = true
+		selected = append(selected, column)
+	}
+	return selected, nil
+}
+
 func resolveTimeType(granularity string) (string, error) {
 	switch granularity {
 	case "hour":
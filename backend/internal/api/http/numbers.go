@@ -0,0 +1,61 @@
+package apihttp
+
+import "strconv"
+
+// DefaultFloatPrecision is the number of decimals JSON and CSV responses
+// round floats to, hiding float64 artifacts such as 0.30000000000000004.
+const DefaultFloatPrecision = 6
+
+// WithFloatPrecision rounds floats in responses to decimals places; a
+// negative value returns them unrounded.
+func WithFloatPrecision(decimals int) QueryOption {
+	return func(q *queryLimits) {
+		q.floatPrecision = decimals
+	}
+}
+
+// roundFloat rounds value to decimals places through its decimal text, so the
+// result is the float64 closest to the rounded decimal and encodes as such in
+// both encoding/json and formatFloat.
+func roundFloat(value float64, decimals int) float64 {
+	if decimals < 0 {
+		return value
+	}
+	rounded, err := strconv.ParseFloat(strconv.FormatFloat(value, 'f', decimals, 64), 64)
+	if err != nil {
+		return value
+	}
+	return rounded
+}
+
+func roundStatRows(rows []statRow, decimals int) {
+	for i := range rows {
+		row := &rows[i]
+		row.ChargeKWh = roundFloat(row.ChargeKWh, decimals)
+		row.DischargeKWh = roundFloat(row.DischargeKWh, decimals)
+		row.Earnings = roundFloat(row.Earnings, decimals)
+		row.CarbonReduction = roundFloat(row.CarbonReduction, decimals)
+	}
+}
+
+func roundSettlementRows(rows []settlementRow, decimals int) {
+	for i := range rows {
+		rows[i].EnergyKWh = roundFloat(rows[i].EnergyKWh, decimals)
+		rows[i].Amount = roundFloat(rows[i].Amount, decimals)
+	}
+}
+
+func roundTelemetryPoints(rows []telemetryPointRow, decimals int) {
+	for i := range rows {
+		rows[i].Value = roundFloat(rows[i].Value, decimals)
+	}
+}
+
+func roundTelemetryBuckets(rows []telemetryBucketRow, decimals int) {
+	for i := range rows {
+		row := &rows[i]
+		row.Avg = roundFloat(row.Avg, decimals)
+		row.Min = roundFloat(row.Min, decimals)
+		row.Max = roundFloat(row.Max, decimals)
+	}
+}
@@ -0,0 +1,153 @@
+package apihttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/clock"
+)
+
+// DefaultQueryTimeout bounds a single handler query when no timeout is configured.
+const DefaultQueryTimeout = 30 * time.Second
+
+const (
+	// DefaultMaxHourRange caps the from/to span of hourly queries.
+	DefaultMaxHourRange = 31 * 24 * time.Hour
+	// DefaultMaxDayRange caps the from/to span of daily queries.
+	DefaultMaxDayRange = 366 * 24 * time.Hour
+)
+
+// QueryOption configures the query limits and number formatting of the stats
+// and settlements handlers.
+type QueryOption func(*queryLimits)
+
+// WithQueryTimeout cancels a handler query server-side after timeout; zero disables the limit.
+func WithQueryTimeout(timeout time.Duration) QueryOption {
+	return func(q *queryLimits) {
+		if timeout >= 0 {
+			q.timeout = timeout
+		}
+	}
+}
+
+// WithMaxRange caps the from/to span for granularity ("hour" or "day");
+// longer windows are rejected with 400. Zero removes the cap.
+func WithMaxRange(granularity string, max time.Duration) QueryOption {
+	return func(q *queryLimits) {
+		if max < 0 {
+			return
+		}
+		switch granularity {
+		case "hour":
+			q.maxHourRange = max
+		case "day":
+			q.maxDayRange = max
+		}
+	}
+}
+
+// WithDefaultRange lets clients omit from/to: a missing to defaults to now and
+// a missing from to span before to. Zero keeps both parameters required.
+func WithDefaultRange(span time.Duration) QueryOption {
+	return func(q *queryLimits) {
+		if span >= 0 {
+			q.defaultRange = span
+		}
+	}
+}
+
+// WithQueryClock overrides the clock a missing to defaults to.
+func WithQueryClock(c clock.Clock) QueryOption {
+	return func(q *queryLimits) {
+		if c != nil {
+			q.clock = c
+		}
+	}
+}
+
+type queryLimits struct {
+	clock        clock.Clock
+	timeout      time.Duration
+	defaultRange time.Duration
+	maxHourRange time.Duration
+	maxDayRange  time.Duration
+
+	floatPrecision int
+}
+
+func newQueryLimits(opts []QueryOption) queryLimits {
+	q := queryLimits{
+		clock:        clock.System{},
+		timeout:      DefaultQueryTimeout,
+		maxHourRange: DefaultMaxHourRange,
+		maxDayRange:  DefaultMaxDayRange,
+
+		floatPrecision: DefaultFloatPrecision,
+	}
+	for _, opt := range opts {
+		opt(&q)
+	}
+	return q
+}
+
+// queryContext derives the context for one query from the request context.
+func (q queryLimits) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if q.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, q.timeout)
+}
+
+// queryRange resolves the from/to window of a request, filling omitted bounds
+// from the default range and rejecting windows longer than the granularity's cap.
+func (q queryLimits) queryRange(r *http.Request, granularity string) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	var from, to time.Time
+	var err error
+	if q.defaultRange <= 0 || (query.Get("from") != "" && query.Get("to") != "") {
+		if from, err = parseTimeQuery(r, "from"); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if to, err = parseTimeQuery(r, "to"); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	} else {
+		to = q.clock.Now().UTC()
+		if query.Get("to") != "" {
+			if to, err = parseTimeQuery(r, "to"); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+		from = to.Add(-q.defaultRange)
+		if query.Get("from") != "" {
+			if from, err = parseTimeQuery(r, "from"); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+	max := q.maxDayRange
+	if granularity == "hour" {
+		max = q.maxHourRange
+	}
+	if max > 0 && to.Sub(from) > max {
+		return time.Time{}, time.Time{}, errors.New("range exceeds the maximum of " + max.String() + " for " + granularity + " granularity")
+	}
+	return from, to, nil
+}
+
+// respondQueryError reports a query that ran out of time as 504 and any other
+// failure as 500. Drivers do not always wrap the context error, so the query
+// context is checked as well.
+func respondQueryError(ctx context.Context, w http.ResponseWriter, err error, message string) {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		apierror.Write(w, http.StatusGatewayTimeout, apierror.CodeTimeout, "query timeout")
+		return
+	}
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, message)
+}
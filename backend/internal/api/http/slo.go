@@ -0,0 +1,59 @@
+package apihttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/clock"
+	"microgrid-cloud/internal/observability/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SLOHandler reports pipeline success rates and latencies from the
+// in-process metrics.
+type SLOHandler struct {
+	gatherer prometheus.Gatherer
+	clock    clock.Clock
+}
+
+// SLOOption configures the SLO handler.
+type SLOOption func(*SLOHandler)
+
+// WithSLOClock overrides the clock that stamps generated_at.
+func WithSLOClock(c clock.Clock) SLOOption {
+	return func(h *SLOHandler) {
+		if c != nil {
+			h.clock = c
+		}
+	}
+}
+
+// NewSLOHandler constructs an SLOHandler; a nil gatherer reads
+// prometheus.DefaultGatherer.
+func NewSLOHandler(gatherer prometheus.Gatherer, opts ...SLOOption) *SLOHandler {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	h := &SLOHandler{gatherer: gatherer, clock: clock.System{}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP handles GET /api/v1/admin/slo.
+func (h *SLOHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	report, err := metrics.BuildSLOReport(h.gatherer, h.clock.Now())
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "gather metrics error")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
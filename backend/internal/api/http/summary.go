@@ -0,0 +1,205 @@
+package apihttp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+const stationsPathPrefix = "/api/v1/stations/"
+
+// StationSummaryHandler serves lifetime energy totals of a station.
+type StationSummaryHandler struct {
+	db             *sql.DB
+	stationChecker auth.StationTenantChecker
+	queryLimits
+}
+
+// NewStationSummaryHandler constructs a StationSummaryHandler.
+func NewStationSummaryHandler(db *sql.DB, stationChecker auth.StationTenantChecker, opts ...QueryOption) *StationSummaryHandler {
+	return &StationSummaryHandler{db: db, stationChecker: stationChecker, queryLimits: newQueryLimits(opts)}
+}
+
+type stationSummary struct {
+	StationID       string               `json:"station_id"`
+	Since           *time.Time           `json:"since,omitempty"`
+	ChargeKWh       float64              `json:"charge_kwh"`
+	DischargeKWh    float64              `json:"discharge_kwh"`
+	Earnings        float64              `json:"earnings"`
+	CarbonReduction float64              `json:"carbon_reduction"`
+	Sources         stationSummarySource `json:"sources"`
+}
+
+// stationSummarySource counts the aggregates each granularity contributed.
+type stationSummarySource struct {
+	Years  int `json:"years"`
+	Months int `json:"months"`
+	Days   int `json:"days"`
+}
+
+// ServeHTTP handles GET /api/v1/stations/{id}/summary.
+func (h *StationSummaryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if h == nil || h.db == nil {
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "server not ready")
+		return
+	}
+	stationID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, stationsPathPrefix), "/summary")
+	if !ok || stationID == "" || strings.Contains(stationID, "/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	tenantID := auth.TenantIDFromContext(r.Context())
+	if tenantID != "" {
+		if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
+			respondTenantError(w, err)
+			return
+		}
+	}
+
+	var since time.Time
+	if r.URL.Query().Get("since") != "" {
+		parsed, err := parseTimeQuery(r, "since")
+		if err != nil {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+			return
+		}
+		since = parsed
+	}
+
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	summary, err := queryStationSummary(ctx, h.db, tenantID, stationID, since)
+	if err != nil {
+		respondQueryError(ctx, w, err, "query station summary error")
+		return
+	}
+	summary.ChargeKWh = roundFloat(summary.ChargeKWh, h.floatPrecision)
+	summary.DischargeKWh = roundFloat(summary.DischargeKWh, h.floatPrecision)
+	summary.Earnings = roundFloat(summary.Earnings, h.floatPrecision)
+	summary.CarbonReduction = roundFloat(summary.CarbonReduction, h.floatPrecision)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(summary)
+}
+
+type summaryRow struct {
+	timeKey         string
+	count           int
+	chargeKWh       float64
+	dischargeKWh    float64
+	earnings        float64
+	carbonReduction float64
+}
+
+// queryStationSummary totals the statistics of a station starting at or after
+// since (all of them when since is zero). Completed YEAR aggregates are used
+// where present, then completed MONTH aggregates for months outside those
+// years, then day sums, grouped by month in the database, for the remaining
+// months. Periods starting before since are not counted, so a year or month
+// straddling since is covered by its days.
+func queryStationSummary(ctx context.Context, db *sql.DB, tenantID, stationID string, since time.Time) (stationSummary, error) {
+	summary := stationSummary{StationID: stationID}
+	if !since.IsZero() {
+		since = since.UTC()
+		summary.Since = &since
+	}
+
+	rollups, err := querySummaryRows(ctx, db, tenantID, stationID, since, `
+SELECT s.time_type, s.time_key, 1, s.charge_kwh, s.discharge_kwh, s.earnings, s.carbon_reduction
+FROM analytics_statistics s%[1]s
+WHERE %[2]s
+	AND s.time_type IN ('YEAR', 'MONTH')
+	AND s.is_completed = TRUE
+	AND s.period_start >= $%[3]d`)
+	if err != nil {
+		return stationSummary{}, err
+	}
+	days, err := querySummaryRows(ctx, db, tenantID, stationID, since, `
+SELECT 'DAY', LEFT(s.time_key, 6), COUNT(*), SUM(s.charge_kwh), SUM(s.discharge_kwh), SUM(s.earnings), SUM(s.carbon_reduction)
+FROM analytics_statistics s%[1]s
+WHERE %[2]s
+	AND s.time_type = 'DAY'
+	AND s.period_start >= $%[3]d
+GROUP BY LEFT(s.time_key, 6)`)
+	if err != nil {
+		return stationSummary{}, err
+	}
+
+	years := make(map[string]bool)
+	months := make(map[string]bool)
+	for _, row := range rollups["YEAR"] {
+		years[row.timeKey] = true
+		summary.add(row)
+		summary.Sources.Years++
+	}
+	for _, row := range rollups["MONTH"] {
+		if len(row.timeKey) < 4 || years[row.timeKey[:4]] {
+			continue
+		}
+		months[row.timeKey] = true
+		summary.add(row)
+		summary.Sources.Months++
+	}
+	for _, row := range days["DAY"] {
+		if len(row.timeKey) < 4 || years[row.timeKey[:4]] || months[row.timeKey] {
+			continue
+		}
+		summary.add(row)
+		summary.Sources.Days += row.count
+	}
+	return summary, nil
+}
+
+func (s *stationSummary) add(row summaryRow) {
+	s.ChargeKWh += row.chargeKWh
+	s.DischargeKWh += row.dischargeKWh
+	s.Earnings += row.earnings
+	s.CarbonReduction += row.carbonReduction
+}
+
+// querySummaryRows runs a summary query template, filling in the tenant join
+// (%[1]s), the station filter (%[2]s) and the since placeholder number (%[3]d),
+// and groups rows by time type.
+func querySummaryRows(ctx context.Context, db *sql.DB, tenantID, stationID string, since time.Time, template string) (map[string][]summaryRow, error) {
+	join := ""
+	filter := "s.subject_id = $1"
+	args := []any{stationID}
+	if tenantID != "" {
+		join = "\nJOIN stations st ON st.id = s.subject_id"
+		filter = "st.tenant_id = $1\n\tAND s.subject_id = $2"
+		args = []any{tenantID, stationID}
+	}
+	args = append(args, since)
+
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(template, join, filter, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string][]summaryRow)
+	for rows.Next() {
+		var timeType string
+		var row summaryRow
+		if err := rows.Scan(&timeType, &row.timeKey, &row.count, &row.chargeKWh, &row.dischargeKWh, &row.earnings, &row.carbonReduction); err != nil {
+			return nil, err
+		}
+		result[timeType] = append(result[timeType], row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,280 @@
+package apihttp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+)
+
+const (
+	defaultMaxTelemetryBuckets   = 2000
+	defaultMaxTelemetryRawPoints = 10000
+)
+
+// telemetryBuckets lists the supported downsampling bucket sizes.
+var telemetryBuckets = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"30m": 30 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// TelemetryHandler serves raw and downsampled telemetry queries.
+type TelemetryHandler struct {
+	db             *sql.DB
+	tenantID       string
+	stationChecker auth.StationTenantChecker
+	maxBuckets     int
+	maxRawPoints   int
+	queryLimits
+}
+
+// TelemetryOption configures the telemetry handler.
+type TelemetryOption func(*TelemetryHandler)
+
+// WithMaxTelemetryBuckets caps the number of buckets a single query may return.
+func WithMaxTelemetryBuckets(max int) TelemetryOption {
+	return func(h *TelemetryHandler) {
+		if max > 0 {
+			h.maxBuckets = max
+		}
+	}
+}
+
+// WithMaxTelemetryRawPoints caps the number of raw points returned without a bucket.
+func WithMaxTelemetryRawPoints(max int) TelemetryOption {
+	return func(h *TelemetryHandler) {
+		if max > 0 {
+			h.maxRawPoints = max
+		}
+	}
+}
+
+// WithTelemetryQueryTimeout cancels a telemetry query server-side after timeout; zero disables the limit.
+func WithTelemetryQueryTimeout(timeout time.Duration) TelemetryOption {
+	return func(h *TelemetryHandler) {
+		WithQueryTimeout(timeout)(&h.queryLimits)
+	}
+}
+
+// WithTelemetryFloatPrecision rounds telemetry values to decimals places; a
+// negative value returns them unrounded.
+func WithTelemetryFloatPrecision(decimals int) TelemetryOption {
+	return func(h *TelemetryHandler) {
+		WithFloatPrecision(decimals)(&h.queryLimits)
+	}
+}
+
+// NewTelemetryHandler constructs a TelemetryHandler.
+func NewTelemetryHandler(db *sql.DB, tenantID string, stationChecker auth.StationTenantChecker, opts ...TelemetryOption) *TelemetryHandler {
+	h := &TelemetryHandler{
+		db:             db,
+		tenantID:       tenantID,
+		stationChecker: stationChecker,
+		maxBuckets:     defaultMaxTelemetryBuckets,
+		maxRawPoints:   defaultMaxTelemetryRawPoints,
+		queryLimits:    newQueryLimits(nil),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// ServeHTTP handles GET /api/v1/telemetry.
+func (h *TelemetryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if h == nil || h.db == nil {
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "server not ready")
+		return
+	}
+	tenantID := auth.TenantIDFromContext(r.Context())
+	if tenantID == "" {
+		tenantID = h.tenantID
+	}
+	if tenantID == "" {
+		apierror.Write(w, http.StatusServiceUnavailable, apierror.CodeUnavailable, "tenant_id is required")
+		return
+	}
+
+	stationID := r.URL.Query().Get("station_id")
+	if stationID == "" {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id is required")
+		return
+	}
+
+	if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
+		respondTenantError(w, err)
+		return
+	}
+
+	from, err := parseTimeQuery(r, "from")
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	to, err := parseTimeQuery(r, "to")
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if !to.After(from) {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be after from")
+		return
+	}
+	pointKey := r.URL.Query().Get("point_key")
+
+	bucketParam := r.URL.Query().Get("bucket")
+	if bucketParam == "" {
+		ctx, cancel := h.queryContext(r.Context())
+		defer cancel()
+		points, err := queryTelemetryPoints(ctx, h.db, tenantID, stationID, pointKey, from, to, h.maxRawPoints)
+		if err != nil {
+			respondQueryError(ctx, w, err, "query telemetry error")
+			return
+		}
+		roundTelemetryPoints(points, h.floatPrecision)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(points)
+		return
+	}
+
+	bucket, err := resolveTelemetryBucket(bucketParam)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if count := bucketCount(from, to, bucket); count > h.maxBuckets {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "too many buckets: "+strconv.Itoa(count)+" exceeds limit "+strconv.Itoa(h.maxBuckets))
+		return
+	}
+
+	ctx, cancel := h.queryContext(r.Context())
+	defer cancel()
+	buckets, err := queryTelemetryBuckets(ctx, h.db, tenantID, stationID, pointKey, from, to, bucket)
+	if err != nil {
+		respondQueryError(ctx, w, err, "query telemetry error")
+		return
+	}
+	roundTelemetryBuckets(buckets, h.floatPrecision)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(buckets)
+}
+
+type telemetryPointRow struct {
+	DeviceID string    `json:"device_id"`
+	PointKey string    `json:"point_key"`
+	TS       time.Time `json:"ts"`
+	Value    float64   `json:"value"`
+}
+
+type telemetryBucketRow struct {
+	PointKey    string    `json:"point_key"`
+	BucketStart time.Time `json:"bucket_start"`
+	Avg         float64   `json:"avg"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Count       int       `json:"count"`
+}
+
+func resolveTelemetryBucket(value string) (time.Duration, error) {
+	bucket, ok := telemetryBuckets[value]
+	if !ok {
+		return 0, errors.New("bucket must be one of 1m, 5m, 15m, 30m, 1h, 6h, 1d")
+	}
+	return bucket, nil
+}
+
+func bucketCount(from, to time.Time, bucket time.Duration) int {
+	span := to.Sub(from)
+	count := int(span / bucket)
+	if span%bucket != 0 {
+		count++
+	}
+	return count
+}
+
+func queryTelemetryPoints(ctx context.Context, db *sql.DB, tenantID, stationID, pointKey string, from, to time.Time, limit int) ([]telemetryPointRow, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT device_id, point_key, ts, value_numeric
+FROM telemetry_points
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND ts >= $3
+	AND ts < $4
+	AND value_numeric IS NOT NULL
+	AND ($5 = '' OR point_key = $5)
+ORDER BY ts ASC, point_key ASC
+LIMIT $6`, tenantID, stationID, from.UTC(), to.UTC(), pointKey, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]telemetryPointRow, 0)
+	for rows.Next() {
+		var row telemetryPointRow
+		if err := rows.Scan(&row.DeviceID, &row.PointKey, &row.TS, &row.Value); err != nil {
+			return nil, err
+		}
+		row.TS = row.TS.UTC()
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func queryTelemetryBuckets(ctx context.Context, db *sql.DB, tenantID, stationID, pointKey string, from, to time.Time, bucket time.Duration) ([]telemetryBucketRow, error) {
+	// Buckets are aligned to the requested range start so the first bucket always begins at `from`.
+	rows, err := db.QueryContext(ctx, `
+SELECT
+	point_key,
+	date_bin(make_interval(secs => $5), ts, $3) AS bucket_start,
+	AVG(value_numeric),
+	MIN(value_numeric),
+	MAX(value_numeric),
+	COUNT(*)
+FROM telemetry_points
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND ts >= $3
+	AND ts < $4
+	AND value_numeric IS NOT NULL
+	AND ($6 = '' OR point_key = $6)
+GROUP BY point_key, bucket_start
+ORDER BY bucket_start ASC, point_key ASC`, tenantID, stationID, from.UTC(), to.UTC(), bucket.Seconds(), pointKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]telemetryBucketRow, 0)
+	for rows.Next() {
+		var row telemetryBucketRow
+		if err := rows.Scan(&row.PointKey, &row.BucketStart, &row.Avg, &row.Min, &row.Max, &row.Count); err != nil {
+			return nil, err
+		}
+		row.BucketStart = row.BucketStart.UTC()
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
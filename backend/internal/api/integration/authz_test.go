@@ -78,6 +78,8 @@ func applyTenantMigrations(db *sql.DB) error {
 	files := []string{
 		filepath.Join(root, "migrations", "001_init.sql"),
 		filepath.Join(root, "migrations", "003_masterdata.sql"),
+		filepath.Join(root, "migrations", "019_statistic_present_hours.sql"),
+		filepath.Join(root, "migrations", "020_statistics_history.sql"),
 	}
 	for _, path := range files {
 		content, err := os.ReadFile(path)
@@ -0,0 +1,38 @@
+package integration_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"microgrid-cloud/internal/api/apierror"
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+func TestStatsHandler_BadRequestIsJSON(t *testing.T) {
+	db, err := sql.Open("apihttp-settlement-row", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	resp := httptest.NewRecorder()
+	apihttp.NewStatsHandler(db, nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/stats?granularity=hour", nil))
+
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Fatalf("content type = %q", ct)
+	}
+	var body map[string]map[string]string
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode %q: %v", resp.Body.String(), err)
+	}
+	want := map[string]string{"code": apierror.CodeInvalidArgument, "message": "station_id is required"}
+	if len(body) != 1 || len(body["error"]) != 2 || body["error"]["code"] != want["code"] || body["error"]["message"] != want["message"] {
+		t.Fatalf("body = %s, want {\"error\": %v}", resp.Body.String(), want)
+	}
+}
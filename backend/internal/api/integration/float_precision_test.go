@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+func init() {
+	sql.Register("apihttp-settlement-row", settlementRowDriver{})
+}
+
+func TestSettlements_FloatsEncodeCleanly(t *testing.T) {
+	db, err := sql.Open("apihttp-settlement-row", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	target := "/api/v1/settlements?station_id=station-float&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z"
+	resp := httptest.NewRecorder()
+	apihttp.NewSettlementsHandler(db, "tenant-float", nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %q)", resp.Code, resp.Body.String())
+	}
+	body := resp.Body.String()
+	if !strings.Contains(body, `"energy_kwh":0.3,`) || !strings.Contains(body, `"amount":1.21,`) {
+		t.Fatalf("json body %s, want energy 0.3 and amount 1.21", body)
+	}
+
+	csvTarget := strings.Replace(target, "/api/v1/settlements", "/api/v1/exports/settlements.csv", 1)
+	resp = httptest.NewRecorder()
+	apihttp.NewExportSettlementsCSVHandler(db, "tenant-float", nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, csvTarget, nil))
+	if !strings.Contains(resp.Body.String(), ",0.3,1.21,") {
+		t.Fatalf("csv body %q, want the same numbers as json", resp.Body.String())
+	}
+
+	// A negative precision leaves the float as stored.
+	resp = httptest.NewRecorder()
+	apihttp.NewSettlementsHandler(db, "tenant-float", nil, apihttp.WithFloatPrecision(-1)).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+	if !strings.Contains(resp.Body.String(), `"energy_kwh":0.30000000000000004`) {
+		t.Fatalf("unrounded json body %s", resp.Body.String())
+	}
+}
+
+// settlementRowDriver answers every query with one settlements_day row whose
+// numbers carry float64 artifacts.
+type settlementRowDriver struct{}
+
+func (settlementRowDriver) Open(string) (driver.Conn, error) {
+	return settlementRowConn{}, nil
+}
+
+type settlementRowConn struct{}
+
+func (settlementRowConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	day := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	energy, price := 0.1, 1.1
+	return &settlementRows{rows: [][]driver.Value{{
+		"tenant-float", "station-float", day, energy + 0.2, price * price, "CNY", "CALCULATED", int64(1), day, day,
+	}}}, nil
+}
+
+func (settlementRowConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("settlement row driver: prepare not supported")
+}
+
+func (settlementRowConn) Close() error { return nil }
+
+func (settlementRowConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("settlement row driver: transactions not supported")
+}
+
+type settlementRows struct {
+	rows [][]driver.Value
+}
+
+func (r *settlementRows) Columns() []string { return make([]string, 10) }
+
+func (r *settlementRows) Close() error { return nil }
+
+func (r *settlementRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
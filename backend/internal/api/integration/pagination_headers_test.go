@@ -0,0 +1,156 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+// settlementDayQueries counts the page and count queries run through the
+// apihttp-settlement-days driver.
+var settlementDayQueries queryLog
+
+func init() {
+	sql.Register("apihttp-settlement-days", settlementDaysDriver{})
+}
+
+func TestSettlements_PaginationHeaders(t *testing.T) {
+	db, err := sql.Open("apihttp-settlement-days", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+	handler := apihttp.NewSettlementsHandler(db, "tenant-page", nil)
+	base := "/api/v1/settlements?station_id=station-page&from=2026-01-01T00:00:00Z&to=2026-01-06T00:00:00Z"
+
+	cases := []struct {
+		name                string
+		params              string
+		total, limit, off   string
+		wantRows            int
+		wantFirstDayOfMonth int
+		wantCounts          int
+	}{
+		{"unpaged", "", "5", "", "0", 5, 1, 0},
+		{"first page", "&limit=2", "5", "2", "0", 2, 1, 1},
+		{"middle page", "&limit=2&offset=2", "5", "2", "2", 2, 3, 1},
+		{"past the end", "&limit=2&offset=9", "5", "2", "9", 0, 0, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			settlementDayQueries.Reset()
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+tc.params, nil))
+			if resp.Code != http.StatusOK {
+				t.Fatalf("status = %d (body %q)", resp.Code, resp.Body.String())
+			}
+			header := resp.Header()
+			if header.Get("X-Total-Count") != tc.total || header.Get("X-Limit") != tc.limit || header.Get("X-Offset") != tc.off {
+				t.Fatalf("headers total=%q limit=%q offset=%q, want %s/%s/%s",
+					header.Get("X-Total-Count"), header.Get("X-Limit"), header.Get("X-Offset"), tc.total, tc.limit, tc.off)
+			}
+			var rows []struct {
+				DayStart time.Time `json:"day_start"`
+			}
+			if err := json.Unmarshal(resp.Body.Bytes(), &rows); err != nil {
+				t.Fatalf("decode body %q: %v", resp.Body.String(), err)
+			}
+			if len(rows) != tc.wantRows {
+				t.Fatalf("rows = %d, want %d", len(rows), tc.wantRows)
+			}
+			if tc.wantRows > 0 && rows[0].DayStart.Day() != tc.wantFirstDayOfMonth {
+				t.Fatalf("first day = %s, want day %d", rows[0].DayStart, tc.wantFirstDayOfMonth)
+			}
+			// Paged requests read only their page and count the rest in SQL.
+			if pages, counts := settlementDayQueries.Count("page"), settlementDayQueries.Count("count"); pages != 1 || counts != tc.wantCounts {
+				t.Fatalf("page queries = %d, count queries = %d; want 1 and %d", pages, counts, tc.wantCounts)
+			}
+		})
+	}
+
+	for _, params := range []string{"&limit=0", "&limit=1001", "&limit=x", "&offset=-1"} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+params, nil))
+		if resp.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", params, resp.Code)
+		}
+	}
+}
+
+// settlementDaysDriver serves five consecutive settlements_day rows starting
+// 2026-01-01, applying the query's LIMIT and OFFSET parameters, and answers
+// COUNT(*) with 5.
+type settlementDaysDriver struct{}
+
+func (settlementDaysDriver) Open(string) (driver.Conn, error) {
+	return settlementDaysConn{}, nil
+}
+
+type settlementDaysConn struct{}
+
+var pageParam = regexp.MustCompile(`(LIMIT|OFFSET) \$(\d+)`)
+
+func (settlementDaysConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if strings.HasPrefix(strings.TrimSpace(query), "SELECT COUNT(*)") {
+		settlementDayQueries.Record("count", args)
+		return &countRows{count: 5}, nil
+	}
+	settlementDayQueries.Record("page", args)
+	rows := make([][]driver.Value, 0, 5)
+	for i := 0; i < 5; i++ {
+		day := time.Date(2026, time.January, 1+i, 0, 0, 0, 0, time.UTC)
+		rows = append(rows, []driver.Value{"tenant-page", "station-page", day, 10.0, 5.0, "CNY", "CALCULATED", int64(1), day, day})
+	}
+	limit := len(rows)
+	for _, match := range pageParam.FindAllStringSubmatch(query, -1) {
+		index, _ := strconv.Atoi(match[2])
+		value := int(args[index-1].Value.(int64))
+		if match[1] == "LIMIT" {
+			limit = value
+		} else {
+			rows = rows[min(value, len(rows)):]
+		}
+	}
+	return &settlementRows{rows: rows[:min(limit, len(rows))]}, nil
+}
+
+func (settlementDaysConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("settlement days driver: prepare not supported")
+}
+
+func (settlementDaysConn) Close() error { return nil }
+
+func (settlementDaysConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("settlement days driver: transactions not supported")
+}
+
+// countRows is the single-column result of a COUNT(*) query.
+type countRows struct {
+	count int64
+	done  bool
+}
+
+func (r *countRows) Columns() []string { return []string{"count"} }
+
+func (r *countRows) Close() error { return nil }
+
+func (r *countRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0] = r.count
+	r.done = true
+	return nil
+}
@@ -0,0 +1,93 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
+	apihttp "microgrid-cloud/internal/api/http"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestStatsAPI_ReportsPartialDay(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	stationID := "station-partial-day-001"
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+
+	dayStart := time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)
+	dayID, err := domainstatistic.BuildStatisticID(domainstatistic.GranularityDay, dayStart)
+	if err != nil {
+		t.Fatalf("day id: %v", err)
+	}
+	day, err := domainstatistic.NewStatisticAggregate(dayID, domainstatistic.GranularityDay, dayStart)
+	if err != nil {
+		t.Fatalf("new day aggregate: %v", err)
+	}
+	if err := day.MarkPartial(domainstatistic.StatisticFact{ChargeKWh: 10, DischargeKWh: 5}, 10); err != nil {
+		t.Fatalf("mark partial: %v", err)
+	}
+	repo := analyticsrepo.NewPostgresStatisticRepository(db, stationID)
+	if err := repo.Save(ctx, day); err != nil {
+		t.Fatalf("save partial day: %v", err)
+	}
+
+	stored, err := repo.Get(ctx, dayID)
+	if err != nil {
+		t.Fatalf("get partial day: %v", err)
+	}
+	if stored.IsCompleted() || stored.PresentHours() != 10 {
+		t.Fatalf("partial day round trip: completed=%v present=%d", stored.IsCompleted(), stored.PresentHours())
+	}
+
+	server := httptest.NewServer(apihttp.NewStatsHandler(db, nil))
+	defer server.Close()
+
+	from := dayStart.Format(time.RFC3339)
+	to := dayStart.Add(24 * time.Hour).Format(time.RFC3339)
+	resp, err := http.Get(server.URL + "?station_id=" + stationID + "&from=" + from + "&to=" + to + "&granularity=day")
+	if err != nil {
+		t.Fatalf("get stats: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("stats status: %d", resp.StatusCode)
+	}
+
+	var stats []struct {
+		IsCompleted  bool    `json:"is_completed"`
+		PresentHours *int    `json:"present_hours"`
+		ChargeKWh    float64 `json:"charge_kwh"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		t.Fatalf("decode stats: %v", err)
+	}
+	if len(stats) != 1 {
+		t.Fatalf("expected 1 day stat, got %d", len(stats))
+	}
+	if stats[0].IsCompleted || stats[0].PresentHours == nil || *stats[0].PresentHours != 10 || stats[0].ChargeKWh != 10 {
+		t.Fatalf("unexpected partial day stat: %+v", stats[0])
+	}
+}
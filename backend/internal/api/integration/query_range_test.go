@@ -0,0 +1,119 @@
+package integration_test
+
+import (
+	"database/sql"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+	"microgrid-cloud/internal/clock"
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+	shadowhttp "microgrid-cloud/internal/shadowrun/interfaces/http"
+)
+
+func TestQueryHandlers_RejectRangeAboveMaximum(t *testing.T) {
+	db := openRangeDB(t)
+	shadowHandler := newRangeShadowHandler(t, db)
+
+	fortyDays := "&from=2026-01-01T00:00:00Z&to=2026-02-10T00:00:00Z"
+	twoYears := "&from=2025-01-01T00:00:00Z&to=2027-01-01T00:00:00Z"
+	cases := []struct {
+		name    string
+		handler http.Handler
+		target  string
+		want    int
+	}{
+		{"hourly stats over a month", apihttp.NewStatsHandler(db, nil), "/api/v1/stats?station_id=station-range&granularity=hour" + fortyDays, http.StatusBadRequest},
+		{"daily stats over a month", apihttp.NewStatsHandler(db, nil), "/api/v1/stats?station_id=station-range&granularity=day" + fortyDays, http.StatusOK},
+		{"daily stats over a year", apihttp.NewStatsHandler(db, nil), "/api/v1/stats?station_id=station-range&granularity=day" + twoYears, http.StatusBadRequest},
+		{"hourly stats with raised cap", apihttp.NewStatsHandler(db, nil, apihttp.WithMaxRange("hour", 60*24*time.Hour)), "/api/v1/stats?station_id=station-range&granularity=hour" + fortyDays, http.StatusOK},
+		{"settlements over a year", apihttp.NewSettlementsHandler(db, "tenant-range", nil), "/api/v1/settlements?station_id=station-range" + twoYears, http.StatusBadRequest},
+		{"settlements csv over a year", apihttp.NewExportSettlementsCSVHandler(db, "tenant-range", nil), "/api/v1/exports/settlements.csv?station_id=station-range" + twoYears, http.StatusBadRequest},
+		{"shadowrun reports over a year", shadowHandler, "/api/v1/shadowrun/reports?station_id=station-range" + twoYears, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recordedQueries.Reset()
+			resp := httptest.NewRecorder()
+			tc.handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if resp.Code != tc.want {
+				t.Fatalf("status = %d, want %d (body %q)", resp.Code, tc.want, resp.Body.String())
+			}
+			if tc.want == http.StatusBadRequest && recordedQueries.Count("range") != 0 {
+				t.Fatalf("rejected range still reached the database")
+			}
+		})
+	}
+}
+
+func TestQueryHandlers_DefaultRangeWhenParamsOmitted(t *testing.T) {
+	db := openRangeDB(t)
+
+	resp := httptest.NewRecorder()
+	apihttp.NewStatsHandler(db, nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/stats?station_id=station-range&granularity=hour", nil))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("without a default range status = %d, want 400", resp.Code)
+	}
+
+	recordedQueries.Reset()
+	now := time.Date(2026, time.January, 20, 12, 0, 0, 0, time.UTC)
+	resp = httptest.NewRecorder()
+	apihttp.NewStatsHandler(db, nil, apihttp.WithDefaultRange(24*time.Hour), apihttp.WithQueryClock(clock.NewFixed(now))).ServeHTTP(resp,
+		httptest.NewRequest(http.MethodGet, "/api/v1/stats?station_id=station-range&granularity=hour", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (body %q)", resp.Code, resp.Body.String())
+	}
+	args := recordedQueries.LastArgs("range")
+	if len(args) != 4 {
+		t.Fatalf("query args = %v", args)
+	}
+	from, to := args[2].Value.(time.Time), args[3].Value.(time.Time)
+	if !to.Equal(now) || to.Sub(from) != 24*time.Hour {
+		t.Fatalf("default window = %s..%s, want the last 24h", from, to)
+	}
+
+	// An explicit to anchors the default window.
+	resp = httptest.NewRecorder()
+	apihttp.NewSettlementsHandler(db, "tenant-range", nil, apihttp.WithDefaultRange(24*time.Hour)).ServeHTTP(resp,
+		httptest.NewRequest(http.MethodGet, "/api/v1/settlements?station_id=station-range&to=2026-01-10T00:00:00Z", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("settlements status = %d, want 200", resp.Code)
+	}
+	args = recordedQueries.LastArgs("range")
+	if from := args[2].Value.(time.Time); !from.Equal(time.Date(2026, time.January, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("settlements from = %s, want 24h before to", from)
+	}
+
+	resp = httptest.NewRecorder()
+	newRangeShadowHandler(t, db, shadowhttp.WithReportRange(24*time.Hour, 0)).ServeHTTP(resp,
+		httptest.NewRequest(http.MethodGet, "/api/v1/shadowrun/reports?station_id=station-range", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("shadowrun reports status = %d, want 200 (body %q)", resp.Code, resp.Body.String())
+	}
+}
+
+func openRangeDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("apihttp-record", "range")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func newRangeShadowHandler(t *testing.T, db *sql.DB, opts ...shadowhttp.HandlerOption) *shadowhttp.Handler {
+	t.Helper()
+	repo := shadowrepo.NewRepository(db)
+	runner := shadowapp.NewRunner(repo, db, shadowapp.Config{}, nil, nil, log.New(io.Discard, "", 0))
+	handler, err := shadowhttp.NewHandler(runner, repo, "tenant-range", nil, opts...)
+	if err != nil {
+		t.Fatalf("shadowrun handler: %v", err)
+	}
+	return handler
+}
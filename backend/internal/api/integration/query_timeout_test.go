@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+func init() {
+	sql.Register("apihttp-sleep", sleepDriver{})
+}
+
+func TestQueryHandlers_TimeoutReturns504(t *testing.T) {
+	db, err := sql.Open("apihttp-sleep", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	timeout := apihttp.WithQueryTimeout(20 * time.Millisecond)
+	window := "&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z"
+	cases := []struct {
+		name    string
+		handler http.Handler
+		target  string
+	}{
+		{"stats", apihttp.NewStatsHandler(db, nil, timeout), "/api/v1/stats?station_id=station-timeout&granularity=hour" + window},
+		{"settlements", apihttp.NewSettlementsHandler(db, "tenant-timeout", nil, timeout), "/api/v1/settlements?station_id=station-timeout" + window},
+		{"settlements csv", apihttp.NewExportSettlementsCSVHandler(db, "tenant-timeout", nil, timeout), "/api/v1/exports/settlements.csv?station_id=station-timeout" + window},
+		{"telemetry", apihttp.NewTelemetryHandler(db, "tenant-timeout", nil, apihttp.WithTelemetryQueryTimeout(20*time.Millisecond)), "/api/v1/telemetry?station_id=station-timeout" + window},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			resp := httptest.NewRecorder()
+			tc.handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if resp.Code != http.StatusGatewayTimeout {
+				t.Fatalf("status = %d, want 504 (body %q)", resp.Code, resp.Body.String())
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("query was not cancelled server-side, took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestQueryHandlers_FailureWithoutTimeoutReturns500(t *testing.T) {
+	db, err := sql.Open("apihttp-sleep", "fail")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	resp := httptest.NewRecorder()
+	apihttp.NewStatsHandler(db, nil).ServeHTTP(resp, httptest.NewRequest(http.MethodGet,
+		"/api/v1/stats?station_id=station-timeout&granularity=hour&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z", nil))
+	if resp.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", resp.Code)
+	}
+}
+
+// sleepDriver is a database/sql driver whose queries block until their
+// context ends, standing in for a runaway query. The "fail" DSN makes
+// queries fail immediately instead.
+type sleepDriver struct{}
+
+func (sleepDriver) Open(name string) (driver.Conn, error) {
+	return sleepConn{fail: name == "fail"}, nil
+}
+
+type sleepConn struct {
+	fail bool
+}
+
+func (c sleepConn) QueryContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	if c.fail {
+		return nil, errors.New("relation does not exist")
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (sleepConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("sleep driver: prepare not supported")
+}
+
+func (sleepConn) Close() error { return nil }
+
+func (sleepConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("sleep driver: transactions not supported")
+}
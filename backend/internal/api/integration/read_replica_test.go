@@ -0,0 +1,154 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	apihttp "microgrid-cloud/internal/api/http"
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+	shadowhttp "microgrid-cloud/internal/shadowrun/interfaces/http"
+)
+
+var recordedQueries = &queryLog{counts: make(map[string]int), args: make(map[string][]driver.NamedValue)}
+
+func init() {
+	sql.Register("apihttp-record", recordDriver{})
+}
+
+func TestReadHandlers_UseReplicaHandle(t *testing.T) {
+	primary, err := sql.Open("apihttp-record", "primary")
+	if err != nil {
+		t.Fatalf("open primary: %v", err)
+	}
+	defer primary.Close()
+	replica, err := sql.Open("apihttp-record", "replica")
+	if err != nil {
+		t.Fatalf("open replica: %v", err)
+	}
+	defer replica.Close()
+
+	statementService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(primary), "tenant-replica",
+		settlementapp.WithReadRepository(settlementrepo.NewStatementRepository(replica)),
+	)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	statementHandler, err := settlementinterfaces.NewStatementHandler(statementService, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+	shadowRepo := shadowrepo.NewRepository(primary)
+	runner := shadowapp.NewRunner(shadowRepo, primary, shadowapp.Config{}, nil, nil, log.New(io.Discard, "", 0))
+	shadowHandler, err := shadowhttp.NewHandler(runner, shadowRepo, "tenant-replica", nil, shadowhttp.WithReadRepository(shadowrepo.NewRepository(replica)))
+	if err != nil {
+		t.Fatalf("shadowrun handler: %v", err)
+	}
+
+	window := "&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z"
+	cases := []struct {
+		name    string
+		handler http.Handler
+		target  string
+	}{
+		{"stats", apihttp.NewStatsHandler(replica, nil), "/api/v1/stats?station_id=station-replica&granularity=hour" + window},
+		{"settlements", apihttp.NewSettlementsHandler(replica, "tenant-replica", nil), "/api/v1/settlements?station_id=station-replica" + window},
+		{"statements list", statementHandler, "/api/v1/statements?station_id=station-replica&month=2026-01"},
+		{"shadowrun reports", shadowHandler, "/api/v1/shadowrun/reports?station_id=station-replica" + window},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			recordedQueries.Reset()
+			resp := httptest.NewRecorder()
+			tc.handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, tc.target, nil))
+			if resp.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200 (body %q)", resp.Code, resp.Body.String())
+			}
+			if got := recordedQueries.Count("replica"); got == 0 {
+				t.Fatalf("no query reached the replica")
+			}
+			if got := recordedQueries.Count("primary"); got != 0 {
+				t.Fatalf("%d queries reached the primary, want none", got)
+			}
+		})
+	}
+}
+
+type queryLog struct {
+	mu     sync.Mutex
+	counts map[string]int
+	args   map[string][]driver.NamedValue
+}
+
+func (l *queryLog) Record(name string, args []driver.NamedValue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts[name]++
+	l.args[name] = args
+}
+
+func (l *queryLog) Count(name string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.counts[name]
+}
+
+// LastArgs returns the arguments of the latest query against name.
+func (l *queryLog) LastArgs(name string) []driver.NamedValue {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.args[name]
+}
+
+func (l *queryLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.counts = make(map[string]int)
+	l.args = make(map[string][]driver.NamedValue)
+}
+
+// recordDriver is a database/sql driver that counts queries per DSN and
+// answers every query with an empty result set.
+type recordDriver struct{}
+
+func (recordDriver) Open(name string) (driver.Conn, error) {
+	return recordConn{name: name}, nil
+}
+
+type recordConn struct {
+	name string
+}
+
+func (c recordConn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	recordedQueries.Record(c.name, args)
+	return emptyRows{}, nil
+}
+
+func (recordConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("record driver: prepare not supported")
+}
+
+func (recordConn) Close() error { return nil }
+
+func (recordConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("record driver: transactions not supported")
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string { return nil }
+
+func (emptyRows) Close() error { return nil }
+
+func (emptyRows) Next([]driver.Value) error { return io.EOF }
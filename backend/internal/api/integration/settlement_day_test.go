@@ -0,0 +1,116 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+	"microgrid-cloud/internal/auth"
+)
+
+var settlementDay = time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+
+func init() {
+	sql.Register("apihttp-settlement-day", settlementDayDriver{})
+}
+
+func TestSettlementDay_ReturnsSingleDayOr404(t *testing.T) {
+	db, err := sql.Open("apihttp-settlement-day", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	handler := apihttp.NewSettlementsHandler(db, "tenant-day", settlementDayChecker{})
+	get := func(path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		ctx := auth.WithIdentity(context.Background(), "tenant-day", auth.RoleViewer, "viewer")
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+		return resp
+	}
+
+	resp := get("/api/v1/settlements/station-day/2026-01-05")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("present day = %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		StationID string    `json:"station_id"`
+		DayStart  time.Time `json:"day_start"`
+		EnergyKWh float64   `json:"energy_kwh"`
+		Amount    float64   `json:"amount"`
+		Status    string    `json:"status"`
+		Version   int       `json:"version"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v (body %s)", err, resp.Body.String())
+	}
+	if body.StationID != "station-day" || !body.DayStart.Equal(settlementDay) || body.EnergyKWh != 42.5 || body.Amount != 21.25 || body.Status != "FINAL" || body.Version != 3 {
+		t.Fatalf("settlement = %+v", body)
+	}
+
+	cases := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/settlements/station-day/2026-01-06", http.StatusNotFound},
+		{"/api/v1/settlements/station-day/05-01-2026", http.StatusBadRequest},
+		{"/api/v1/settlements/station-day", http.StatusNotFound},
+		{"/api/v1/settlements/station-other/2026-01-05", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if resp := get(tc.path); resp.Code != tc.want {
+			t.Fatalf("%s = %d, want %d", tc.path, resp.Code, tc.want)
+		}
+	}
+}
+
+// settlementDayChecker lets tenant-day read station-day only.
+type settlementDayChecker struct{}
+
+func (settlementDayChecker) EnsureStationTenant(_ context.Context, tenantID, stationID string) error {
+	if tenantID != "tenant-day" || stationID != "station-day" {
+		return auth.ErrTenantMismatch
+	}
+	return nil
+}
+
+// settlementDayDriver stores one settlement, of station-day on 2026-01-05.
+type settlementDayDriver struct{}
+
+func (settlementDayDriver) Open(string) (driver.Conn, error) {
+	return settlementDayConn{}, nil
+}
+
+type settlementDayConn struct{}
+
+func (settlementDayConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM settlements_day") || !strings.Contains(query, "day_start = $3") {
+		return nil, errors.New("settlement day driver: unexpected query")
+	}
+	day, _ := args[2].Value.(time.Time)
+	if args[0].Value != "tenant-day" || args[1].Value != "station-day" || !day.Equal(settlementDay) {
+		return &settlementRows{}, nil
+	}
+	updated := settlementDay.Add(26 * time.Hour)
+	return &settlementRows{rows: [][]driver.Value{{
+		"tenant-day", "station-day", settlementDay, 42.5, 21.25, "CNY", "FINAL", int64(3), updated, updated,
+	}}}, nil
+}
+
+func (settlementDayConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("settlement day driver: prepare not supported")
+}
+
+func (settlementDayConn) Close() error { return nil }
+
+func (settlementDayConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("settlement day driver: transactions not supported")
+}
@@ -0,0 +1,42 @@
+package integration_test
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+func TestExportSettlementsCSV_ColumnSelection(t *testing.T) {
+	db, err := sql.Open("apihttp-settlement-row", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	handler := apihttp.NewExportSettlementsCSVHandler(db, "tenant-float", nil)
+	get := func(columns string) *httptest.ResponseRecorder {
+		target := "/api/v1/exports/settlements.csv?station_id=station-float&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z&columns=" + url.QueryEscape(columns)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, target, nil))
+		return resp
+	}
+
+	resp := get("amount, day_start,station_id")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d (body %q)", resp.Code, resp.Body.String())
+	}
+	want := "amount,day_start,station_id\n1.21,2026-01-01T00:00:00Z,station-float\n"
+	if resp.Body.String() != want {
+		t.Fatalf("body = %q, want %q", resp.Body.String(), want)
+	}
+
+	for _, columns := range []string{"amount,price", "amount,amount", "amount,"} {
+		if resp := get(columns); resp.Code != http.StatusBadRequest {
+			t.Fatalf("columns %q = %d, want 400", columns, resp.Code)
+		}
+	}
+}
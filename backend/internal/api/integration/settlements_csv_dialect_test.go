@@ -0,0 +1,53 @@
+package integration_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apihttp "microgrid-cloud/internal/api/http"
+)
+
+func TestExportSettlementsCSV_DelimiterAndBOM(t *testing.T) {
+	db := openRangeDB(t)
+	handler := apihttp.NewExportSettlementsCSVHandler(db, "tenant-csv", nil)
+	base := "/api/v1/exports/settlements.csv?station_id=station-csv&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z"
+	bom := []byte{0xEF, 0xBB, 0xBF}
+
+	cases := []struct {
+		name      string
+		params    string
+		wantBOM   bool
+		wantFirst string
+	}{
+		{"default", "", false, "tenant_id,station_id,day_start"},
+		{"semicolon with bom", "&delimiter=%3B&bom=true", true, "tenant_id;station_id;day_start"},
+		{"tab", "&delimiter=tab&bom=false", false, "tenant_id\tstation_id\tday_start"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+tc.params, nil))
+			if resp.Code != http.StatusOK {
+				t.Fatalf("status = %d (body %q)", resp.Code, resp.Body.String())
+			}
+			body := resp.Body.Bytes()
+			if got := bytes.HasPrefix(body, bom); got != tc.wantBOM {
+				t.Fatalf("bom present = %t, want %t", got, tc.wantBOM)
+			}
+			if header := string(bytes.TrimPrefix(body, bom)); !strings.HasPrefix(header, tc.wantFirst) {
+				t.Fatalf("header = %q, want prefix %q", header, tc.wantFirst)
+			}
+		})
+	}
+
+	for _, params := range []string{"&delimiter=%22", "&delimiter=ab", "&bom=maybe"} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, base+params, nil))
+		if resp.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", params, resp.Code)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package integration_test
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
+	"microgrid-cloud/internal/observability/metrics"
+)
+
+func TestSLOReport_SummarizesObservations(t *testing.T) {
+	metrics.Init(nil, nil)
+	for i := 0; i < 9; i++ {
+		metrics.ObserveIngest(metrics.ResultSuccess, 2*time.Millisecond)
+	}
+	metrics.ObserveIngest(metrics.ResultError, 200*time.Millisecond)
+	for i := 0; i < 3; i++ {
+		metrics.ObserveSettlementDay(metrics.ResultSuccess, 5*time.Millisecond)
+	}
+	metrics.ObserveSettlementDay(metrics.ResultWaiting, time.Millisecond)
+
+	mux := http.NewServeMux()
+	generatedAt := time.Date(2026, time.March, 2, 8, 0, 0, 0, time.UTC)
+	mux.Handle("/api/v1/admin/slo", apihttp.NewSLOHandler(nil, apihttp.WithSLOClock(clock.NewFixed(generatedAt))))
+	secret := []byte("test-secret")
+	server := httptest.NewServer(auth.NewMiddleware(secret, auth.NewDefaultPolicy(nil, nil)).Wrap(mux))
+	defer server.Close()
+
+	forbidden := getSLO(t, server.URL, mustToken(t, secret, "tenant-slo", "operator"))
+	forbidden.Body.Close()
+	if forbidden.StatusCode != http.StatusForbidden {
+		t.Fatalf("operator status = %d, want 403", forbidden.StatusCode)
+	}
+
+	resp := getSLO(t, server.URL, mustToken(t, secret, "tenant-slo", "admin"))
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("admin status = %d, want 200", resp.StatusCode)
+	}
+	var report struct {
+		GeneratedAt time.Time `json:"generated_at"`
+		Pipelines   []struct {
+			Name        string   `json:"name"`
+			Metric      string   `json:"metric"`
+			Total       uint64   `json:"total"`
+			Success     uint64   `json:"success"`
+			Errors      uint64   `json:"errors"`
+			SuccessRate *float64 `json:"success_rate"`
+			P50Seconds  *float64 `json:"p50_seconds"`
+			P95Seconds  *float64 `json:"p95_seconds"`
+		} `json:"pipelines"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if !report.GeneratedAt.Equal(generatedAt) {
+		t.Fatalf("generated_at = %s, want %s", report.GeneratedAt, generatedAt)
+	}
+	byName := map[string]int{}
+	for i, pipeline := range report.Pipelines {
+		byName[pipeline.Name] = i
+	}
+	for _, name := range []string{"ingest", "window_close", "analytics_window", "settlement_day", "statement_generate", "outbox_dispatch"} {
+		if _, ok := byName[name]; !ok {
+			t.Fatalf("pipeline %s missing from %+v", name, report.Pipelines)
+		}
+	}
+
+	ingest := report.Pipelines[byName["ingest"]]
+	if ingest.Metric != "platform_ingest_latency_seconds" || ingest.Total != 10 || ingest.Success != 9 || ingest.Errors != 1 {
+		t.Fatalf("ingest = %+v", ingest)
+	}
+	if ingest.SuccessRate == nil || *ingest.SuccessRate != 0.9 {
+		t.Fatalf("ingest success_rate = %v, want 0.9", ingest.SuccessRate)
+	}
+	// The slowest tenth lies in the 0.1-0.25s bucket; p95 is its midpoint.
+	if ingest.P95Seconds == nil || math.Abs(*ingest.P95Seconds-0.175) > 1e-9 {
+		t.Fatalf("ingest p95 = %v, want 0.175", ingest.P95Seconds)
+	}
+	if ingest.P50Seconds == nil || *ingest.P50Seconds > 0.0025 {
+		t.Fatalf("ingest p50 = %v, want within the 2.5ms bucket", ingest.P50Seconds)
+	}
+
+	// A settlement still waiting for its hours is not a failure.
+	settlement := report.Pipelines[byName["settlement_day"]]
+	if settlement.Total != 4 || settlement.SuccessRate == nil || *settlement.SuccessRate != 1 {
+		t.Fatalf("settlement_day = %+v, want 4 observations at success rate 1", settlement)
+	}
+
+	idle := report.Pipelines[byName["statement_generate"]]
+	if idle.Total != 0 || idle.SuccessRate != nil || idle.P95Seconds != nil {
+		t.Fatalf("statement_generate = %+v, want no rates without observations", idle)
+	}
+}
+
+func getSLO(t *testing.T, baseURL, token string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, baseURL+"/api/v1/admin/slo", nil)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do request: %v", err)
+	}
+	return resp
+}
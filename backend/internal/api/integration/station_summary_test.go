@@ -0,0 +1,195 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+	"microgrid-cloud/internal/auth"
+)
+
+func init() {
+	sql.Register("apihttp-station-summary", summaryDriver{})
+}
+
+func TestStationSummary_PrefersYearAggregates(t *testing.T) {
+	db, err := sql.Open("apihttp-station-summary", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	handler := apihttp.NewStationSummaryHandler(db, summaryChecker{})
+	get := func(path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		ctx := auth.WithIdentity(context.Background(), "tenant-sum", auth.RoleViewer, "viewer")
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+		return resp
+	}
+	type summary struct {
+		ChargeKWh       float64 `json:"charge_kwh"`
+		DischargeKWh    float64 `json:"discharge_kwh"`
+		Earnings        float64 `json:"earnings"`
+		CarbonReduction float64 `json:"carbon_reduction"`
+		Sources         struct {
+			Years  int `json:"years"`
+			Months int `json:"months"`
+			Days   int `json:"days"`
+		} `json:"sources"`
+	}
+	decode := func(resp *httptest.ResponseRecorder) summary {
+		t.Helper()
+		if resp.Code != http.StatusOK {
+			t.Fatalf("summary = %d: %s", resp.Code, resp.Body.String())
+		}
+		var body summary
+		if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode: %v (body %s)", err, resp.Body.String())
+		}
+		return body
+	}
+
+	// Lifetime: years 2024 and 2025, then the February 2026 month aggregate,
+	// then March 2026 days. January 2024 and the days of 2025 and February
+	// 2026 are already covered.
+	lifetime := decode(get("/api/v1/stations/station-sum/summary"))
+	if lifetime.ChargeKWh != 1000+1200+90+12 || lifetime.DischargeKWh != 500+600+45+6 || lifetime.Earnings != 250.5+300+22.5+3 || lifetime.CarbonReduction != 10+12+1+0.5 {
+		t.Fatalf("lifetime = %+v", lifetime)
+	}
+	if lifetime.Sources.Years != 2 || lifetime.Sources.Months != 1 || lifetime.Sources.Days != 4 {
+		t.Fatalf("lifetime sources = %+v", lifetime.Sources)
+	}
+
+	// Since mid-2025 the 2025 year no longer counts; its months are not
+	// aggregated, so its days are summed.
+	since := decode(get("/api/v1/stations/station-sum/summary?since=2025-07-01T00:00:00Z"))
+	if since.ChargeKWh != 600+90+12 || since.Sources.Years != 0 || since.Sources.Months != 1 || since.Sources.Days != 184+4 {
+		t.Fatalf("since = %+v", since)
+	}
+
+	cases := []struct {
+		path string
+		want int
+	}{
+		{"/api/v1/stations/station-other/summary", http.StatusForbidden},
+		{"/api/v1/stations/station-sum/summary?since=2025-07-01", http.StatusBadRequest},
+		{"/api/v1/stations/station-sum", http.StatusNotFound},
+		{"/api/v1/stations/station-sum/other", http.StatusNotFound},
+	}
+	for _, tc := range cases {
+		if resp := get(tc.path); resp.Code != tc.want {
+			t.Fatalf("%s = %d, want %d", tc.path, resp.Code, tc.want)
+		}
+	}
+}
+
+// summaryChecker lets tenant-sum read station-sum only.
+type summaryChecker struct{}
+
+func (summaryChecker) EnsureStationTenant(_ context.Context, tenantID, stationID string) error {
+	if tenantID != "tenant-sum" || stationID != "station-sum" {
+		return auth.ErrTenantMismatch
+	}
+	return nil
+}
+
+// summaryRollups are the completed YEAR and MONTH aggregates of station-sum.
+var summaryRollups = []summaryStat{
+	{"YEAR", "2024", date(2024, 1, 1), 1, 1000, 500, 250.5, 10},
+	{"MONTH", "202401", date(2024, 1, 1), 1, 80, 40, 20, 1},
+	{"YEAR", "2025", date(2025, 1, 1), 1, 1200, 600, 300, 12},
+	{"MONTH", "202602", date(2026, 2, 1), 1, 90, 45, 22.5, 1},
+}
+
+// summaryDays are the day sums per month of station-sum; 2025 is split in
+// halves so a since filter can cut it.
+var summaryDays = []summaryStat{
+	{"DAY", "202412", date(2024, 12, 1), 31, 90, 45, 20, 1},
+	{"DAY", "202501", date(2025, 1, 1), 181, 500, 250, 120, 5},
+	{"DAY", "202507", date(2025, 7, 1), 184, 600, 300, 150, 6},
+	{"DAY", "202602", date(2026, 2, 1), 28, 88, 44, 22, 1},
+	{"DAY", "202603", date(2026, 3, 1), 4, 12, 6, 3, 0.5},
+}
+
+type summaryStat struct {
+	timeType  string
+	timeKey   string
+	start     time.Time
+	count     int64
+	charge    float64
+	discharge float64
+	earnings  float64
+	carbon    float64
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+type summaryDriver struct{}
+
+func (summaryDriver) Open(string) (driver.Conn, error) {
+	return summaryConn{}, nil
+}
+
+type summaryConn struct{}
+
+func (summaryConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 3 || args[0].Value != "tenant-sum" || args[1].Value != "station-sum" {
+		return &summaryRows{}, nil
+	}
+	since, _ := args[2].Value.(time.Time)
+	var source []summaryStat
+	switch {
+	case strings.Contains(query, "IN ('YEAR', 'MONTH')") && strings.Contains(query, "is_completed = TRUE"):
+		source = summaryRollups
+	case strings.Contains(query, "GROUP BY LEFT(s.time_key, 6)"):
+		source = summaryDays
+	default:
+		return nil, errors.New("summary driver: unexpected query")
+	}
+	rows := &summaryRows{}
+	for _, stat := range source {
+		if stat.start.Before(since) {
+			continue
+		}
+		rows.rows = append(rows.rows, []driver.Value{stat.timeType, stat.timeKey, stat.count, stat.charge, stat.discharge, stat.earnings, stat.carbon})
+	}
+	return rows, nil
+}
+
+func (summaryConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("summary driver: prepare not supported")
+}
+
+func (summaryConn) Close() error { return nil }
+
+func (summaryConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("summary driver: transactions not supported")
+}
+
+type summaryRows struct {
+	rows [][]driver.Value
+}
+
+func (r *summaryRows) Columns() []string { return make([]string, 7) }
+
+func (r *summaryRows) Close() error { return nil }
+
+func (r *summaryRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
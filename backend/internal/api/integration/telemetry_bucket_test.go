@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	apihttp "microgrid-cloud/internal/api/http"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestTelemetryQuery_BucketedAggregation(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-bucket"
+	stationID := "station-bucket-001"
+	_, _ = db.ExecContext(ctx, "DELETE FROM telemetry_points WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	start := time.Date(2026, time.January, 20, 10, 0, 0, 0, time.UTC)
+	// Two 5m buckets: [10:00,10:05) holds 1,2,3 and [10:05,10:10) holds 10,20.
+	samples := []struct {
+		offset time.Duration
+		value  float64
+	}{
+		{0, 1},
+		{time.Minute, 2},
+		{4*time.Minute + 59*time.Second, 3},
+		{5 * time.Minute, 10},
+		{9 * time.Minute, 20},
+	}
+	for _, sample := range samples {
+		if err := insertTelemetryRow(ctx, db, tenantID, stationID, "charge_power_kw", start.Add(sample.offset), sample.value); err != nil {
+			t.Fatalf("insert telemetry: %v", err)
+		}
+	}
+	if err := insertTelemetryRow(ctx, db, tenantID, stationID, "soc", start, 55); err != nil {
+		t.Fatalf("insert telemetry: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/v1/telemetry", apihttp.NewTelemetryHandler(db, tenantID, nil, apihttp.WithMaxTelemetryBuckets(10)))
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	from := start.Format(time.RFC3339)
+	to := start.Add(10 * time.Minute).Format(time.RFC3339)
+	resp, err := http.Get(server.URL + "/api/v1/telemetry?station_id=" + stationID + "&from=" + from + "&to=" + to + "&bucket=5m&point_key=charge_power_kw")
+	if err != nil {
+		t.Fatalf("get telemetry: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("telemetry status: %d", resp.StatusCode)
+	}
+
+	var buckets []telemetryBucketResponse
+	if err := json.NewDecoder(resp.Body).Decode(&buckets); err != nil {
+		t.Fatalf("decode telemetry: %v", err)
+	}
+	if len(buckets) != 2 {
+		t.Fatalf("expected 2 buckets, got %d", len(buckets))
+	}
+	if !buckets[0].BucketStart.Equal(start) || buckets[0].Count != 3 || buckets[0].Avg != 2 || buckets[0].Min != 1 || buckets[0].Max != 3 {
+		t.Fatalf("first bucket mismatch: %+v", buckets[0])
+	}
+	if !buckets[1].BucketStart.Equal(start.Add(5*time.Minute)) || buckets[1].Count != 2 || buckets[1].Avg != 15 || buckets[1].Min != 10 || buckets[1].Max != 20 {
+		t.Fatalf("second bucket mismatch: %+v", buckets[1])
+	}
+
+	badResp, err := http.Get(server.URL + "/api/v1/telemetry?station_id=" + stationID + "&from=" + from + "&to=" + to + "&bucket=7m")
+	if err != nil {
+		t.Fatalf("get telemetry: %v", err)
+	}
+	badResp.Body.Close()
+	if badResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid bucket, got %d", badResp.StatusCode)
+	}
+
+	wideTo := start.Add(time.Hour).Format(time.RFC3339)
+	capResp, err := http.Get(server.URL + "/api/v1/telemetry?station_id=" + stationID + "&from=" + from + "&to=" + wideTo + "&bucket=1m")
+	if err != nil {
+		t.Fatalf("get telemetry: %v", err)
+	}
+	capResp.Body.Close()
+	if capResp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 when bucket cap exceeded, got %d", capResp.StatusCode)
+	}
+}
+
+type telemetryBucketResponse struct {
+	PointKey    string    `json:"point_key"`
+	BucketStart time.Time `json:"bucket_start"`
+	Avg         float64   `json:"avg"`
+	Min         float64   `json:"min"`
+	Max         float64   `json:"max"`
+	Count       int       `json:"count"`
+}
+
+func insertTelemetryRow(ctx context.Context, db *sql.DB, tenantID, stationID, pointKey string, ts time.Time, value float64) error {
+	_, err := db.ExecContext(ctx, `
+INSERT INTO telemetry_points (tenant_id, station_id, device_id, point_key, ts, value_numeric, quality)
+VALUES ($1, $2, 'device-1', $3, $4, $5, 'good')`, tenantID, stationID, pointKey, ts.UTC(), value)
+	return err
+}
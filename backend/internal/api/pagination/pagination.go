@@ -0,0 +1,72 @@
+// Package pagination pages list responses with limit/offset query parameters
+// and reports the page in response headers.
+//
+// Repositories apply the page in SQL with LIMIT/OFFSET and count the matching
+// rows, so limit and offset bound the rows read as well as the response.
+package pagination
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// Response headers describing the returned page.
+const (
+	HeaderTotalCount = "X-Total-Count"
+	HeaderLimit      = "X-Limit"
+	HeaderOffset     = "X-Offset"
+)
+
+// MaxLimit caps the limit parameter.
+const MaxLimit = 1000
+
+// ExposedHeaders lists the headers for Access-Control-Expose-Headers, so
+// browser clients can read them.
+const ExposedHeaders = HeaderTotalCount + ", " + HeaderLimit + ", " + HeaderOffset
+
+// Page is the window requested by a client. A zero Limit returns the whole
+// list from Offset, so clients that do not page keep their full responses.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// Parse reads the optional limit and offset query parameters.
+func Parse(r *http.Request) (Page, error) {
+	var page Page
+	query := r.URL.Query()
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return Page{}, errors.New("limit must be between 1 and " + strconv.Itoa(MaxLimit))
+		}
+		page.Limit = limit
+	}
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return Page{}, errors.New("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+	return page, nil
+}
+
+// Paged reports whether the page narrows the list, so callers can skip the
+// count query for requests without limit or offset.
+func (p Page) Paged() bool {
+	return p.Limit > 0 || p.Offset > 0
+}
+
+// SetHeaders sets the pagination headers for a page of a list of total rows.
+// X-Limit is only set when the client sent a limit. Headers must be set
+// before the body is written.
+func SetHeaders(w http.ResponseWriter, page Page, total int) {
+	header := w.Header()
+	if page.Limit > 0 {
+		header.Set(HeaderLimit, strconv.Itoa(page.Limit))
+	}
+	header.Set(HeaderTotalCount, strconv.Itoa(total))
+	header.Set(HeaderOffset, strconv.Itoa(page.Offset))
+}
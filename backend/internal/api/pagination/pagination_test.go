@@ -0,0 +1,47 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	page, err := Parse(httptest.NewRequest("GET", "/items?limit=20&offset=40", nil))
+	if err != nil || page != (Page{Limit: 20, Offset: 40}) {
+		t.Fatalf("parse = %+v, %v", page, err)
+	}
+	if page, err := Parse(httptest.NewRequest("GET", "/items", nil)); err != nil || page != (Page{}) {
+		t.Fatalf("parse without params = %+v, %v", page, err)
+	}
+	for _, target := range []string{"/items?limit=0", "/items?limit=1001", "/items?limit=x", "/items?offset=-1"} {
+		if _, err := Parse(httptest.NewRequest("GET", target, nil)); err == nil {
+			t.Fatalf("expected error for %s", target)
+		}
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	cases := []struct {
+		page   Page
+		paged  bool
+		header string
+	}{
+		{Page{Limit: 2, Offset: 1}, true, "2"},
+		{Page{Offset: 3}, true, ""},
+		{Page{}, false, ""},
+	}
+	for _, tc := range cases {
+		if tc.page.Paged() != tc.paged {
+			t.Fatalf("%+v Paged = %v, want %v", tc.page, tc.page.Paged(), tc.paged)
+		}
+		resp := httptest.NewRecorder()
+		SetHeaders(resp, tc.page, 5)
+		if _, hasLimit := resp.Header()[HeaderLimit]; hasLimit != (tc.header != "") {
+			t.Fatalf("SetHeaders(%+v) X-Limit present = %v", tc.page, hasLimit)
+		}
+		if resp.Header().Get(HeaderTotalCount) != "5" || resp.Header().Get(HeaderLimit) != tc.header || resp.Header().Get(HeaderOffset) != strconv.Itoa(tc.page.Offset) {
+			t.Fatalf("SetHeaders(%+v) headers = %v", tc.page, resp.Header())
+		}
+	}
+}
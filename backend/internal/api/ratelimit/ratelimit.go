@@ -0,0 +1,259 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
+	"microgrid-cloud/internal/observability/metrics"
+)
+
+// Route classes. Each class has its own limit and its own buckets.
+const (
+	ClassDefault = "default"
+	ClassExport  = "export" // statement generation, month close and exports
+	ClassIngest  = "ingest" // keyed by client IP rather than tenant
+)
+
+// DefaultLimits leaves limiting off; deployments opt in per class. A
+// default cap on ingest would throttle ThingsBoard, which posts from a
+// single address.
+const DefaultLimits = "off"
+
+// sweepInterval bounds how often idle buckets are dropped.
+const sweepInterval = 10 * time.Minute
+
+// Limit is a token bucket refilled at Rate tokens per second up to Burst.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// ParseLimits parses "class=rate:burst;..." such as "export=0.5:10;ingest=100:200".
+// Rate is in requests per second; "off" disables limiting.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit)
+	if strings.EqualFold(strings.TrimSpace(spec), "off") {
+		return limits, nil
+	}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		class, value, ok := strings.Cut(entry, "=")
+		class = strings.TrimSpace(class)
+		if !ok || class == "" {
+			return nil, fmt.Errorf("rate limit %q: want class=rate:burst", entry)
+		}
+		rateText, burstText, ok := strings.Cut(value, ":")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: want class=rate:burst", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateText), 64)
+		if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+			return nil, fmt.Errorf("rate limit %q: invalid rate", entry)
+		}
+		burst, err := strconv.Atoi(strings.TrimSpace(burstText))
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("rate limit %q: invalid burst", entry)
+		}
+		limits[class] = Limit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
+
+// Classify maps a request to its route class.
+func Classify(r *http.Request) string {
+	path := r.URL.Path
+	switch {
+	case strings.HasPrefix(path, "/ingest/"):
+		return ClassIngest
+	case path == "/api/v1/statements/generate",
+		path == "/api/v1/statements/close",
+		strings.HasPrefix(path, "/api/v1/exports/"),
+		strings.HasPrefix(path, "/api/v1/statements/") && strings.Contains(path, "/export."):
+		return ClassExport
+	}
+	return ClassDefault
+}
+
+// Limiter is an HTTP middleware applying per-class token buckets keyed by
+// tenant, or by client IP for ingest and unauthenticated requests.
+type Limiter struct {
+	limits     map[string]Limit
+	exempt     map[string]struct{}
+	clock      clock.Clock
+	trustProxy bool
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// Option configures a Limiter.
+type Option func(*Limiter)
+
+// WithClock overrides the limiter clock (tests).
+func WithClock(c clock.Clock) Option {
+	return func(l *Limiter) {
+		if c != nil {
+			l.clock = c
+		}
+	}
+}
+
+// WithExemptPaths skips limiting for the given exact paths.
+func WithExemptPaths(paths ...string) Option {
+	return func(l *Limiter) {
+		for _, path := range paths {
+			l.exempt[path] = struct{}{}
+		}
+	}
+}
+
+// WithTrustedProxy keys requests without a tenant by the client address the
+// fronting proxy appended to X-Forwarded-For instead of the connection
+// address. Enable it only behind a proxy that sets the header, or clients
+// can pick their own key.
+func WithTrustedProxy() Option {
+	return func(l *Limiter) {
+		l.trustProxy = true
+	}
+}
+
+// NewLimiter constructs a limiter. Classes without a limit are not limited.
+func NewLimiter(limits map[string]Limit, opts ...Option) *Limiter {
+	l := &Limiter{
+		limits:  make(map[string]Limit, len(limits)),
+		exempt:  make(map[string]struct{}),
+		clock:   clock.System{},
+		buckets: make(map[string]*bucket),
+	}
+	for class, limit := range limits {
+		l.limits[class] = limit
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Wrap applies rate limiting to the handler. It must run inside the auth
+// middleware so the tenant is known.
+func (l *Limiter) Wrap(next http.Handler) http.Handler {
+	if l == nil || len(l.limits) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := l.exempt[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		class := Classify(r)
+		limit, ok := l.limits[class]
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if wait, allowed := l.take(class+"|"+l.clientKey(r, class), limit); !allowed {
+			metrics.IncRateLimited(class)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			apierror.Write(w, http.StatusTooManyRequests, apierror.CodeRateLimited, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// take spends one token from the bucket at key. When the bucket is empty it
+// returns how long until the next token.
+func (l *Limiter) take(key string, limit Limit) (time.Duration, bool) {
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now, limit: limit}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	if wait < time.Second {
+		wait = time.Second
+	}
+	return wait, false
+}
+
+// sweep drops buckets that have refilled completely; they are equivalent to
+// a fresh bucket.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+	b.updated = now
+}
+
+func (l *Limiter) clientKey(r *http.Request, class string) string {
+	if class != ClassIngest {
+		if tenantID := auth.TenantIDFromContext(r.Context()); tenantID != "" {
+			return "tenant:" + tenantID
+		}
+	}
+	if l.trustProxy {
+		if host := forwardedFor(r); host != "" {
+			return "ip:" + host
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// forwardedFor returns the last X-Forwarded-For entry, the one added by the
+// proxy in front of us; earlier entries are client supplied.
+func forwardedFor(r *http.Request) string {
+	values := r.Header.Values("X-Forwarded-For")
+	if len(values) == 0 {
+		return ""
+	}
+	hops := strings.Split(values[len(values)-1], ",")
+	return strings.TrimSpace(hops[len(hops)-1])
+}
@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/auth"
+)
+
+func TestLimiter_AllowsBurstThenThrottles(t *testing.T) {
+	now := &stepClock{now: time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)}
+	limiter := NewLimiter(map[string]Limit{
+		ClassExport: {Rate: 0.5, Burst: 3},
+	}, WithClock(now), WithExemptPaths("/healthz"))
+	handler := limiter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(path, tenantID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req = req.WithContext(auth.WithIdentity(context.Background(), tenantID, auth.RoleOperator, "op"))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	for i := 0; i < 3; i++ {
+		if resp := do("/api/v1/statements/generate", "tenant-a"); resp.Code != http.StatusOK {
+			t.Fatalf("burst request %d = %d", i+1, resp.Code)
+		}
+	}
+	resp := do("/api/v1/statements/generate", "tenant-a")
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("over-burst request = %d, want 429", resp.Code)
+	}
+	if got := resp.Header().Get("Retry-After"); got != "2" {
+		t.Fatalf("Retry-After = %q, want 2", got)
+	}
+	if body := resp.Body.String(); !strings.Contains(body, `"code":"rate_limited"`) {
+		t.Fatalf("429 body = %q, want a JSON error", body)
+	}
+
+	// Buckets are per tenant, and unlimited classes and exempt paths pass.
+	if resp := do("/api/v1/statements/generate", "tenant-b"); resp.Code != http.StatusOK {
+		t.Fatalf("other tenant = %d", resp.Code)
+	}
+	if resp := do("/api/v1/stats", "tenant-a"); resp.Code != http.StatusOK {
+		t.Fatalf("default class = %d", resp.Code)
+	}
+
+	now.now = now.now.Add(2 * time.Second)
+	if resp := do("/api/v1/exports/settlements.csv", "tenant-a"); resp.Code != http.StatusOK {
+		t.Fatalf("after refill = %d", resp.Code)
+	}
+	if resp := do("/api/v1/statements/stmt-1/export.pdf", "tenant-a"); resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("refilled token reused = %d, want 429", resp.Code)
+	}
+}
+
+func TestLimiter_IngestKeyedByIPAndExemptPaths(t *testing.T) {
+	limiter := NewLimiter(map[string]Limit{
+		ClassIngest:  {Rate: 1, Burst: 1},
+		ClassDefault: {Rate: 1, Burst: 1},
+	}, WithClock(&stepClock{now: time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)}), WithExemptPaths("/healthz", "/metrics"))
+	handler := limiter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(path, remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = remoteAddr
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	if code := do("/ingest/thingsboard/telemetry", "10.0.0.1:5000"); code != http.StatusOK {
+		t.Fatalf("first ingest = %d", code)
+	}
+	if code := do("/ingest/thingsboard/telemetry", "10.0.0.1:5001"); code != http.StatusTooManyRequests {
+		t.Fatalf("same ip ingest = %d, want 429", code)
+	}
+	if code := do("/ingest/thingsboard/telemetry", "10.0.0.2:5000"); code != http.StatusOK {
+		t.Fatalf("other ip ingest = %d", code)
+	}
+	for i := 0; i < 5; i++ {
+		if code := do("/healthz", "10.0.0.1:5000"); code != http.StatusOK {
+			t.Fatalf("healthz %d = %d", i, code)
+		}
+		if code := do("/metrics", "10.0.0.1:5000"); code != http.StatusOK {
+			t.Fatalf("metrics %d = %d", i, code)
+		}
+	}
+}
+
+func TestLimiter_TrustedProxyKeysByForwardedFor(t *testing.T) {
+	limiter := NewLimiter(map[string]Limit{ClassIngest: {Rate: 1, Burst: 1}},
+		WithClock(&stepClock{now: time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)}), WithTrustedProxy())
+	handler := limiter.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	do := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/ingest/thingsboard/telemetry", nil)
+		req.RemoteAddr = "10.0.0.9:443"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	// Two clients behind one proxy get their own buckets.
+	if code := do("192.0.2.1"); code != http.StatusOK {
+		t.Fatalf("first client = %d", code)
+	}
+	if code := do("192.0.2.2"); code != http.StatusOK {
+		t.Fatalf("second client = %d", code)
+	}
+	// A spoofed leading entry does not buy a fresh bucket.
+	if code := do("198.51.100.7, 192.0.2.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed client = %d, want 429", code)
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits(DefaultLimits)
+	if err != nil || len(limits) != 0 {
+		t.Fatalf("defaults = %v, %v, want no limits", limits, err)
+	}
+	limits, err = ParseLimits("export=0.5:10;default=20:60")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := limits[ClassExport]; got.Rate != 0.5 || got.Burst != 10 {
+		t.Fatalf("export limit = %+v", got)
+	}
+	if _, ok := limits[ClassIngest]; ok {
+		t.Fatalf("ingest limited without a spec entry")
+	}
+	if limits, err := ParseLimits("off"); err != nil || len(limits) != 0 {
+		t.Fatalf("off = %v, %v", limits, err)
+	}
+	for _, spec := range []string{"export", "export=1", "export=0:1", "export=1:0", "=1:1"} {
+		if _, err := ParseLimits(spec); err == nil {
+			t.Fatalf("spec %q accepted", spec)
+		}
+	}
+}
+
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
@@ -3,6 +3,8 @@ package auth
 import (
 	"net/http"
 	"strings"
+
+	"microgrid-cloud/internal/api/apierror"
 )
 
 // Middleware validates JWTs and enforces RBAC.
@@ -36,13 +38,13 @@ func (m *Middleware) Wrap(next http.Handler) http.Handler {
 		token := extractBearer(r)
 		claims, err := ParseJWT(token, m.Secret)
 		if err != nil {
-			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			apierror.Write(w, http.StatusUnauthorized, apierror.CodeUnauthenticated, "unauthorized")
 			return
 		}
 		role, _ := NormalizeRole(claims.Role)
 		ctx := WithIdentity(r.Context(), claims.TenantID, role, claims.Subject)
 		if !RoleAtLeast(role, required) {
-			http.Error(w, "forbidden", http.StatusForbidden)
+			apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 			return
 		}
 		next.ServeHTTP(w, r.WithContext(ctx))
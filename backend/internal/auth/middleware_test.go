@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"microgrid-cloud/internal/api/apierror"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
@@ -23,6 +26,10 @@ func TestAuthMiddleware_NoToken(t *testing.T) {
 	if resp.Code != http.StatusUnauthorized {
 		t.Fatalf("expected 401, got %d", resp.Code)
 	}
+	var body apierror.Body
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Error.Code != apierror.CodeUnauthenticated {
+		t.Fatalf("401 body = %q, want code %s", resp.Body.String(), apierror.CodeUnauthenticated)
+	}
 }
 
 func TestAuthMiddleware_ViewerForbiddenCommandsPost(t *testing.T) {
@@ -41,6 +48,10 @@ func TestAuthMiddleware_ViewerForbiddenCommandsPost(t *testing.T) {
 	if resp.Code != http.StatusForbidden {
 		t.Fatalf("expected 403, got %d", resp.Code)
 	}
+	var body apierror.Body
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil || body.Error.Code != apierror.CodeForbidden {
+		t.Fatalf("403 body = %q, want code %s", resp.Body.String(), apierror.CodeForbidden)
+	}
 }
 
 func TestAuthMiddleware_ViewerForbiddenStatementFreeze(t *testing.T) {
@@ -61,6 +72,44 @@ func TestAuthMiddleware_ViewerForbiddenStatementFreeze(t *testing.T) {
 	}
 }
 
+func TestAuthMiddleware_ViewerForbiddenAlarmRuleTest(t *testing.T) {
+	secret := []byte("test-secret")
+	token := mustToken(t, secret, "tenant-a", "viewer")
+	policy := NewDefaultPolicy(nil, nil)
+	mw := NewMiddleware(secret, policy)
+	handler := mw.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, path := range []string{"/api/v1/alarm-rules/rule-1/test", "/api/v1/alarms/rules/rule-1/test"} {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		if resp.Code != http.StatusForbidden {
+			t.Fatalf("%s: expected 403, got %d", path, resp.Code)
+		}
+	}
+}
+
+func TestPolicy_AlarmRuleTestAliasMatchesRuleRoutes(t *testing.T) {
+	policy := NewDefaultPolicy(nil, nil)
+	for _, tc := range []struct {
+		method string
+		path   string
+		want   Role
+	}{
+		{http.MethodPost, "/api/v1/alarm-rules/rule-1/test", RoleOperator},
+		{http.MethodPost, "/api/v1/alarms/rules/rule-1/test", RoleOperator},
+		{http.MethodGet, "/api/v1/alarms/rules/rule-1/test", RoleViewer},
+	} {
+		role, ok := policy.RequiredRole(httptest.NewRequest(tc.method, tc.path, nil))
+		if !ok || role != tc.want {
+			t.Fatalf("%s %s: role = %q (%v), want %q", tc.method, tc.path, role, ok, tc.want)
+		}
+	}
+}
+
 func mustToken(t *testing.T, secret []byte, tenantID, role string) string {
 	t.Helper()
 	claims := Claims{
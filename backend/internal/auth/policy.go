@@ -47,6 +47,11 @@ func (p Policy) RequiredRole(r *http.Request) (Role, bool) {
 	switch {
 	case path == "/api/v1/provisioning/stations":
 		return RoleAdmin, true
+	case strings.HasPrefix(path, "/api/v1/provisioning/stations/"):
+		if method == http.MethodGet {
+			return RoleOperator, true
+		}
+		return RoleAdmin, true
 	case path == "/api/v1/commands":
 		if method == http.MethodPost {
 			return RoleOperator, true
@@ -58,6 +63,16 @@ func (p Policy) RequiredRole(r *http.Request) (Role, bool) {
 		return RoleViewer, true
 	case strings.HasPrefix(path, "/api/v1/alarms/") && method == http.MethodPost:
 		return RoleOperator, true
+	case path == "/api/v1/alarm-rules" || strings.HasPrefix(path, "/api/v1/alarm-rules/") || strings.HasPrefix(path, "/api/v1/alarms/rules/"):
+		if method == http.MethodGet {
+			return RoleViewer, true
+		}
+		return RoleOperator, true
+	case path == "/api/v1/alarm-rule-templates" || strings.HasPrefix(path, "/api/v1/alarm-rule-templates/"):
+		if method == http.MethodGet {
+			return RoleViewer, true
+		}
+		return RoleOperator, true
 	case strings.HasPrefix(path, "/api/v1/strategies/"):
 		if method == http.MethodGet {
 			return RoleViewer, true
@@ -65,7 +80,11 @@ func (p Policy) RequiredRole(r *http.Request) (Role, bool) {
 		return RoleOperator, true
 	case path == "/api/v1/stats":
 		return RoleViewer, true
-	case path == "/api/v1/settlements":
+	case strings.HasPrefix(path, "/api/v1/stations/"):
+		return RoleViewer, true
+	case path == "/api/v1/settlements" || strings.HasPrefix(path, "/api/v1/settlements/"):
+		return RoleViewer, true
+	case path == "/api/v1/telemetry":
 		return RoleViewer, true
 	case path == "/api/v1/exports/settlements.csv":
 		return RoleViewer, true
@@ -86,7 +105,11 @@ func (p Policy) RequiredRole(r *http.Request) (Role, bool) {
 			return RoleViewer, true
 		}
 		return RoleAdmin, true
-	case path == "/analytics/window-close":
+	case path == "/analytics/window-close" || path == "/api/v1/analytics/recalculate-hour":
+		return RoleAdmin, true
+	case strings.HasPrefix(path, "/analytics/backfills/"):
+		return RoleViewer, true
+	case strings.HasPrefix(path, "/api/v1/admin/"):
 		return RoleAdmin, true
 	}
 
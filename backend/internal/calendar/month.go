@@ -0,0 +1,40 @@
+// Package calendar parses the calendar values shared by the API, services and
+// tools, so every entry point accepts and rejects the same input.
+package calendar
+
+import (
+	"errors"
+	"time"
+)
+
+// MonthLayout is the YYYY-MM format of month parameters and labels.
+const MonthLayout = "2006-01"
+
+// Month parsing errors.
+var (
+	ErrMonthRequired = errors.New("month required")
+	ErrMonthFormat   = errors.New("month must be YYYY-MM")
+)
+
+// ParseMonth parses a YYYY-MM month and returns its first instant in UTC.
+// Surrounding spaces, single-digit months, days and out-of-range months such
+// as 2026-13 are rejected with ErrMonthFormat.
+func ParseMonth(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, ErrMonthRequired
+	}
+	t, err := time.Parse(MonthLayout, value)
+	if err != nil || t.Year() < 1 {
+		return time.Time{}, ErrMonthFormat
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// MonthRange returns the [start, end) range of a YYYY-MM month in UTC.
+func MonthRange(value string) (time.Time, time.Time, error) {
+	start, err := ParseMonth(value)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return start, start.AddDate(0, 1, 0), nil
+}
@@ -0,0 +1,43 @@
+package calendar
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseMonth(t *testing.T) {
+	valid := map[string]time.Time{
+		"2026-01": time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		"2026-12": time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC),
+		"2024-02": time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for value, want := range valid {
+		got, err := ParseMonth(value)
+		if err != nil || !got.Equal(want) || got.Location() != time.UTC {
+			t.Fatalf("ParseMonth(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseMonth(""); !errors.Is(err, ErrMonthRequired) {
+		t.Fatalf("empty month err = %v, want ErrMonthRequired", err)
+	}
+	for _, value := range []string{"2026-13", "2026-00", "2026-1", "26-01", "2026/01", "2026-01-15", " 2026-01", "2026-01 ", "0000-01", "january"} {
+		if got, err := ParseMonth(value); !errors.Is(err, ErrMonthFormat) {
+			t.Fatalf("ParseMonth(%q) = %v, %v; want ErrMonthFormat", value, got, err)
+		}
+	}
+}
+
+func TestMonthRange(t *testing.T) {
+	start, end, err := MonthRange("2026-12")
+	if err != nil {
+		t.Fatalf("month range: %v", err)
+	}
+	if !start.Equal(time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC)) || !end.Equal(time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("range = [%v, %v)", start, end)
+	}
+	if _, _, err := MonthRange("2026-13"); !errors.Is(err, ErrMonthFormat) {
+		t.Fatalf("month 13 err = %v, want ErrMonthFormat", err)
+	}
+}
@@ -0,0 +1,120 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock provides the current time. Services take a Clock so tests can freeze time.
+type Clock interface {
+	Now() time.Time
+}
+
+// Timer is a cancellable scheduled callback.
+type Timer interface {
+	Stop() bool
+}
+
+// Scheduler is a Clock that can also run callbacks after a delay.
+type Scheduler interface {
+	Clock
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// System reads the wall clock in UTC.
+type System struct{}
+
+// Now returns time.Now in UTC.
+func (System) Now() time.Time { return time.Now().UTC() }
+
+// AfterFunc wraps time.AfterFunc.
+func (System) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// Fixed is a controllable clock for tests and harnesses. Callbacks scheduled
+// with AfterFunc run synchronously when Set or Advance reaches their due time.
+type Fixed struct {
+	mu      sync.Mutex
+	now     time.Time
+	pending []*fixedTimer
+}
+
+type fixedTimer struct {
+	clock *Fixed
+	at    time.Time
+	f     func()
+	done  bool
+}
+
+// Stop cancels the callback; it reports whether the timer was still pending.
+func (t *fixedTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	if t.done {
+		return false
+	}
+	t.done = true
+	return true
+}
+
+// NewFixed returns a clock frozen at t.
+func NewFixed(t time.Time) *Fixed {
+	return &Fixed{now: t.UTC()}
+}
+
+// Now returns the frozen time.
+func (f *Fixed) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// AfterFunc schedules f to run once the clock has advanced by d.
+func (f *Fixed) AfterFunc(d time.Duration, fn func()) Timer {
+	f.mu.Lock()
+	timer := &fixedTimer{clock: f, at: f.now.Add(d), f: fn}
+	f.pending = append(f.pending, timer)
+	f.mu.Unlock()
+	if d <= 0 {
+		f.fire()
+	}
+	return timer
+}
+
+// Set moves the clock to t and runs callbacks that became due.
+func (f *Fixed) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t.UTC()
+	f.mu.Unlock()
+	f.fire()
+}
+
+// Advance moves the clock forward by d and runs callbacks that became due.
+func (f *Fixed) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+	f.fire()
+}
+
+func (f *Fixed) fire() {
+	f.mu.Lock()
+	var due []*fixedTimer
+	remaining := f.pending[:0]
+	for _, timer := range f.pending {
+		switch {
+		case timer.done:
+		case !timer.at.After(f.now):
+			timer.done = true
+			due = append(due, timer)
+		default:
+			remaining = append(remaining, timer)
+		}
+	}
+	f.pending = remaining
+	f.mu.Unlock()
+	sort.SliceStable(due, func(i, j int) bool { return due[i].at.Before(due[j].at) })
+	for _, timer := range due {
+		timer.f()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
 	commandsevents "microgrid-cloud/internal/commands/application/events"
 	commands "microgrid-cloud/internal/commands/domain"
 	commandsrepo "microgrid-cloud/internal/commands/infrastructure/postgres"
@@ -45,10 +46,24 @@ type Service struct {
 	publisher      *eventing.Publisher
 	tenantID       string
 	idempotencyTTL time.Duration
+	clock          clock.Clock
+}
+
+// Option configures the command service.
+type Option func(*Service)
+
+// WithClock overrides the clock used to stamp commands and scope the
+// idempotency window.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		if c != nil {
+			s.clock = c
+		}
+	}
 }
 
 // NewService constructs a command service.
-func NewService(repo *commandsrepo.CommandRepository, publisher *eventing.Publisher, tenantID string) (*Service, error) {
+func NewService(repo *commandsrepo.CommandRepository, publisher *eventing.Publisher, tenantID string, opts ...Option) (*Service, error) {
 	if repo == nil {
 		return nil, errors.New("commands: nil repo")
 	}
@@ -58,12 +73,17 @@ func NewService(repo *commandsrepo.CommandRepository, publisher *eventing.Publis
 	if tenantID == "" {
 		return nil, errors.New("commands: empty tenant id")
 	}
-	return &Service{
+	s := &Service{
 		repo:           repo,
 		publisher:      publisher,
 		tenantID:       tenantID,
 		idempotencyTTL: 10 * time.Minute,
-	}, nil
+		clock:          clock.System{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // IssueCommand creates a command and publishes CommandIssued.
@@ -86,7 +106,7 @@ func (s *Service) IssueCommand(ctx context.Context, req IssueRequest) (*IssueRes
 		idempotencyKey = buildIdempotencyKey(tenantID, req.StationID, req.DeviceID, req.CommandType, req.Payload)
 	}
 
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
 	existing, err := s.repo.FindByIdempotencyKey(ctx, tenantID, idempotencyKey, now.Add(-s.idempotencyTTL))
 	if err != nil {
 		return nil, err
@@ -166,6 +186,17 @@ func (s *Service) ListCommands(ctx context.Context, stationID string, from, to t
 	return s.repo.ListByStationAndTime(ctx, tenantID, stationID, from.UTC(), to.UTC())
 }
 
+// LatestForDevice returns the most recent command to a device, or nil.
+func (s *Service) LatestForDevice(ctx context.Context, tenantID, deviceID string) (*commands.Command, error) {
+	if deviceID == "" {
+		return nil, errors.New("commands: device id required")
+	}
+	if tenantID == "" {
+		tenantID = s.tenantID
+	}
+	return s.repo.LatestByDevice(ctx, tenantID, deviceID)
+}
+
 // MarkTimeouts marks commands that timed out.
 func (s *Service) MarkTimeouts(ctx context.Context, before time.Time) (int, error) {
 	count, err := s.repo.MarkTimeoutBefore(ctx, before)
@@ -81,6 +81,21 @@ INSERT INTO commands (
 	return err
 }
 
+// LatestByDevice returns the most recent command to a device, or nil.
+func (r *CommandRepository) LatestByDevice(ctx context.Context, tenantID, deviceID string) (*commands.Command, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("command repo: nil db")
+	}
+	row := r.db.QueryRowContext(ctx, `
+SELECT command_id, tenant_id, station_id, device_id, command_type, payload, idempotency_key,
+	status, created_at, sent_at, acked_at, error
+FROM commands
+WHERE tenant_id = $1 AND device_id = $2
+ORDER BY created_at DESC
+LIMIT 1`, tenantID, deviceID)
+	return scanCommand(row)
+}
+
 // MarkSent marks command as sent.
 func (r *CommandRepository) MarkSent(ctx context.Context, id string, sentAt time.Time) error {
 	if r == nil || r.db == nil {
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"time"
 
+	"microgrid-cloud/internal/api/apierror"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
 	commandsapp "microgrid-cloud/internal/commands/application"
@@ -42,20 +43,20 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "read body error", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "read body error")
 		return
 	}
 	defer r.Body.Close()
 
 	var req commandsapp.IssueRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && req.TenantID != "" && req.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if tenantID != "" {
@@ -68,7 +69,7 @@ func (h *Handler) handlePost(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := h.service.IssueCommand(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -82,21 +83,21 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
 	fromValue := r.URL.Query().Get("from")
 	toValue := r.URL.Query().Get("to")
 	if stationID == "" || fromValue == "" || toValue == "" {
-		http.Error(w, "station_id/from/to required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id/from/to required")
 		return
 	}
 	from, err := time.Parse(time.RFC3339, fromValue)
 	if err != nil {
-		http.Error(w, "from must be RFC3339", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "from must be RFC3339")
 		return
 	}
 	to, err := time.Parse(time.RFC3339, toValue)
 	if err != nil {
-		http.Error(w, "to must be RFC3339", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be RFC3339")
 		return
 	}
 	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be after from")
 		return
 	}
 
@@ -110,7 +111,7 @@ func (h *Handler) handleGet(w http.ResponseWriter, r *http.Request) {
 
 	list, err := h.service.ListCommands(r.Context(), stationID, from, to)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -151,12 +152,12 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
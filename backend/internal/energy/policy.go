@@ -0,0 +1,56 @@
+package energy
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrNegativeEnergy is returned by NegativeReject for a negative value.
+var ErrNegativeEnergy = errors.New("energy: negative value")
+
+// NegativePolicy decides how negative energy values are handled. Energy is
+// charge plus discharge, so a sign error in telemetry or net export shows up
+// as a negative value.
+type NegativePolicy string
+
+const (
+	// NegativePassThrough leaves negative values unchanged.
+	NegativePassThrough NegativePolicy = "pass"
+	// NegativeClamp replaces negative values with zero.
+	NegativeClamp NegativePolicy = "clamp"
+	// NegativeReject fails with ErrNegativeEnergy on a negative value.
+	NegativeReject NegativePolicy = "reject"
+	// NegativeAbsolute uses the absolute value.
+	NegativeAbsolute NegativePolicy = "absolute"
+)
+
+// ParseNegativePolicy parses a policy name; empty means NegativePassThrough.
+func ParseNegativePolicy(value string) (NegativePolicy, error) {
+	switch policy := NegativePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return NegativePassThrough, nil
+	case NegativePassThrough, NegativeClamp, NegativeReject, NegativeAbsolute:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("energy: unknown negative policy %q (want pass, clamp, reject or absolute)", value)
+	}
+}
+
+// Apply returns kwh under the policy. The zero policy passes values through.
+func (p NegativePolicy) Apply(kwh float64) (float64, error) {
+	if kwh >= 0 {
+		return kwh, nil
+	}
+	switch p {
+	case NegativeClamp:
+		return 0, nil
+	case NegativeReject:
+		return 0, fmt.Errorf("%w: %v kWh", ErrNegativeEnergy, kwh)
+	case NegativeAbsolute:
+		return math.Abs(kwh), nil
+	default:
+		return kwh, nil
+	}
+}
@@ -7,12 +7,33 @@ import (
 	"microgrid-cloud/internal/observability/metrics"
 )
 
-// Dispatcher sends outbox events to the in-process bus.
+// Dispatcher sends outbox events to the in-process bus. By default a failed
+// delivery is dead-lettered at once; WithRetry re-queues it instead.
 type Dispatcher struct {
-	bus      EventBus
-	outbox   OutboxStore
-	registry *Registry
-	dlq      DLQStore
+	bus          EventBus
+	outbox       OutboxStore
+	registry     *Registry
+	dlq          DLQStore
+	maxAttempts  int
+	retryBackoff time.Duration
+	now          func() time.Time
+}
+
+// DispatcherOption configures the dispatcher.
+type DispatcherOption func(*Dispatcher)
+
+// WithRetry re-queues records whose delivery failed until they have been
+// attempted maxAttempts times, waiting backoff times the attempt count between
+// tries. Records that still fail are marked failed and dead-lettered.
+func WithRetry(maxAttempts int, backoff time.Duration) DispatcherOption {
+	return func(d *Dispatcher) {
+		if maxAttempts > 0 {
+			d.maxAttempts = maxAttempts
+		}
+		if backoff >= 0 {
+			d.retryBackoff = backoff
+		}
+	}
 }
 
 // EventBus is the minimal publish interface.
@@ -25,6 +46,7 @@ type OutboxStore interface {
 	ListPending(ctx context.Context, limit int) ([]OutboxRecord, error)
 	MarkSent(ctx context.Context, id string) error
 	MarkFailed(ctx context.Context, id string) error
+	MarkRetry(ctx context.Context, id string, nextAttemptAt time.Time) error
 }
 
 // DLQStore records failures.
@@ -36,6 +58,7 @@ type DLQStore interface {
 type OutboxRecord struct {
 	ID       string
 	Envelope Envelope
+	Attempts int
 }
 
 // DispatchResult captures the outcome of a dispatch run.
@@ -44,12 +67,17 @@ type DispatchResult struct {
 	Claimed   int
 	Sent      int
 	Failed    int
+	Retried   int
 	DLQ       int
 }
 
 // NewDispatcher constructs a dispatcher.
-func NewDispatcher(bus EventBus, outbox OutboxStore, registry *Registry, dlq DLQStore) *Dispatcher {
-	return &Dispatcher{bus: bus, outbox: outbox, registry: registry, dlq: dlq}
+func NewDispatcher(bus EventBus, outbox OutboxStore, registry *Registry, dlq DLQStore, opts ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{bus: bus, outbox: outbox, registry: registry, dlq: dlq, maxAttempts: 1, now: time.Now}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
 }
 
 // Dispatch pulls pending outbox messages and delivers them.
@@ -94,6 +122,14 @@ func (d *Dispatcher) Dispatch(ctx context.Context, limit int) (DispatchResult, e
 
 		ctxWithEnv := WithEnvelope(ctx, env)
 		if err := d.bus.Publish(ctxWithEnv, payload); err != nil {
+			if attempts := record.Attempts + 1; attempts < d.maxAttempts {
+				nextAttemptAt := d.now().UTC().Add(d.retryBackoff * time.Duration(attempts))
+				if err := d.outbox.MarkRetry(ctx, record.ID, nextAttemptAt); err != nil && firstErr == nil {
+					firstErr = err
+				}
+				result.Retried++
+				continue
+			}
 			if err := d.outbox.MarkFailed(ctx, record.ID); err != nil && firstErr == nil {
 				firstErr = err
 			}
@@ -83,7 +83,7 @@ func (s *OutboxStore) ListPending(ctx context.Context, limit int) ([]eventing.Ou
 WITH claimed AS (
 	SELECT id
 	FROM %s
-	WHERE status = 'pending'
+	WHERE status = 'pending' AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
 	ORDER BY created_at ASC
 	FOR UPDATE SKIP LOCKED
 	LIMIT $1
@@ -92,7 +92,7 @@ UPDATE %s o
 SET status = 'processing'
 FROM claimed
 WHERE o.id = claimed.id
-RETURNING o.id, o.payload`, s.table, s.table)
+RETURNING o.id, o.payload, o.attempts`, s.table, s.table)
 
 	rows, err := s.db.QueryContext(ctx, query, limit)
 	if err != nil {
@@ -104,14 +104,15 @@ RETURNING o.id, o.payload`, s.table, s.table)
 	for rows.Next() {
 		var id string
 		var payload []byte
-		if err := rows.Scan(&id, &payload); err != nil {
+		var attempts int
+		if err := rows.Scan(&id, &payload, &attempts); err != nil {
 			return nil, err
 		}
 		var env eventing.Envelope
 		if err := json.Unmarshal(payload, &env); err != nil {
 			return nil, err
 		}
-		result = append(result, eventing.OutboxRecord{ID: id, Envelope: env})
+		result = append(result, eventing.OutboxRecord{ID: id, Envelope: env, Attempts: attempts})
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -144,3 +145,17 @@ WHERE id = $1`, s.table)
 	_, err := s.db.ExecContext(ctx, query, id)
 	return err
 }
+
+// MarkRetry returns outbox record to pending, increments attempts and delays
+// the next claim until nextAttemptAt.
+func (s *OutboxStore) MarkRetry(ctx context.Context, id string, nextAttemptAt time.Time) error {
+	if s == nil || s.db == nil {
+		return errors.New("outbox store: nil db")
+	}
+	query := fmt.Sprintf(`
+UPDATE %s
+SET status = 'pending', attempts = attempts + 1, next_attempt_at = $1
+WHERE id = $2`, s.table)
+	_, err := s.db.ExecContext(ctx, query, nextAttemptAt, id)
+	return err
+}
@@ -0,0 +1,251 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	"microgrid-cloud/internal/analytics/application/events"
+	"microgrid-cloud/internal/eventing"
+	eventingrepo "microgrid-cloud/internal/eventing/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestDispatcher_RetriesFailedDeliveryOnRelay(t *testing.T) {
+	ctx := context.Background()
+	outbox := newMemoryOutbox()
+	dlq := &countingDLQ{}
+
+	baseBus := eventbus.NewInMemoryBus()
+	registry := eventing.NewRegistry()
+	registry.Register(events.TelemetryWindowClosed{})
+	handler := &flakyHandler{failures: 1}
+	baseBus.Subscribe(eventbus.EventTypeOf[events.TelemetryWindowClosed](), handler.Handle)
+
+	dispatcher := eventing.NewDispatcher(baseBus, outbox, registry, dlq, eventing.WithRetry(3, 0))
+	publisher := eventing.NewPublisher(outbox, "tenant-test", baseBus)
+	if err := publisher.Publish(ctx, retryEvent()); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	first, err := dispatcher.Dispatch(ctx, 10)
+	if err != nil {
+		t.Fatalf("first dispatch: %v", err)
+	}
+	if first.Retried != 1 || first.Sent != 0 || first.DLQ != 0 {
+		t.Fatalf("first dispatch = %+v, want one retry", first)
+	}
+
+	second, err := dispatcher.Dispatch(ctx, 10)
+	if err != nil {
+		t.Fatalf("relay dispatch: %v", err)
+	}
+	if second.Sent != 1 {
+		t.Fatalf("relay dispatch = %+v, want the event delivered", second)
+	}
+	if handler.Calls() != 2 || dlq.count != 0 {
+		t.Fatalf("handler calls=%d dlq=%d, want 2 calls and no dlq", handler.Calls(), dlq.count)
+	}
+	if status := outbox.Status(); status != "sent" {
+		t.Fatalf("outbox status = %q, want sent", status)
+	}
+}
+
+func TestDispatcher_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	outbox := newMemoryOutbox()
+	dlq := &countingDLQ{}
+
+	baseBus := eventbus.NewInMemoryBus()
+	registry := eventing.NewRegistry()
+	registry.Register(events.TelemetryWindowClosed{})
+	handler := &flakyHandler{failures: 10}
+	baseBus.Subscribe(eventbus.EventTypeOf[events.TelemetryWindowClosed](), handler.Handle)
+
+	dispatcher := eventing.NewDispatcher(baseBus, outbox, registry, dlq, eventing.WithRetry(2, time.Hour))
+	publisher := eventing.NewPublisher(outbox, "tenant-test", baseBus)
+	if err := publisher.Publish(ctx, retryEvent()); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+
+	if result, _ := dispatcher.Dispatch(ctx, 10); result.Retried != 1 {
+		t.Fatalf("first dispatch = %+v, want one retry", result)
+	}
+	// The backoff keeps the record out of the next poll.
+	if result, _ := dispatcher.Dispatch(ctx, 10); result.Claimed != 0 {
+		t.Fatalf("record claimed before its backoff elapsed: %+v", result)
+	}
+	outbox.ExpireBackoff()
+	if result, _ := dispatcher.Dispatch(ctx, 10); result.DLQ != 1 || result.Failed != 1 {
+		t.Fatalf("final dispatch = %+v, want dead-lettered", result)
+	}
+	if status := outbox.Status(); status != "failed" {
+		t.Fatalf("outbox status = %q, want failed", status)
+	}
+}
+
+func TestEventing_RetryOnRelay_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "event_outbox") ||
+		!tableExists(db, "processed_events") ||
+		!tableExists(db, "dead_letter_events") {
+		t.Skip("missing tables; run migrations")
+	}
+	migration, err := os.ReadFile(filepath.Join("..", "..", "..", "migrations", "023_outbox_retry.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("apply migration: %v", err)
+	}
+
+	ctx := context.Background()
+	_, _ = db.ExecContext(ctx, "DELETE FROM processed_events")
+	_, _ = db.ExecContext(ctx, "DELETE FROM dead_letter_events")
+	_, _ = db.ExecContext(ctx, "DELETE FROM event_outbox")
+
+	baseBus := eventbus.NewInMemoryBus()
+	registry := eventing.NewRegistry()
+	registry.Register(events.TelemetryWindowClosed{})
+
+	outboxStore := eventingrepo.NewOutboxStore(db)
+	processedStore := eventingrepo.NewProcessedStore(db)
+	dlqStore := eventingrepo.NewDLQStore(db)
+	dispatcher := eventing.NewDispatcher(baseBus, outboxStore, registry, dlqStore, eventing.WithRetry(3, 0))
+	publisher := eventing.NewPublisher(outboxStore, "tenant-test", baseBus)
+
+	handler := &flakyHandler{failures: 1}
+	eventing.Subscribe(baseBus, eventbus.EventTypeOf[events.TelemetryWindowClosed](), "consumer-flaky", handler.Handle, processedStore)
+
+	if err := publisher.Publish(ctx, retryEvent()); err != nil {
+		t.Fatalf("publish event: %v", err)
+	}
+	if result, _ := dispatcher.Dispatch(ctx, 10); result.Retried != 1 {
+		t.Fatalf("first dispatch = %+v, want one retry", result)
+	}
+	if result, _ := dispatcher.Dispatch(ctx, 10); result.Sent != 1 {
+		t.Fatalf("relay dispatch = %+v, want the event delivered", result)
+	}
+
+	var status string
+	var attempts int
+	if err := db.QueryRowContext(ctx, "SELECT status, attempts FROM event_outbox").Scan(&status, &attempts); err != nil {
+		t.Fatalf("load outbox: %v", err)
+	}
+	if status != "sent" || attempts != 1 {
+		t.Fatalf("outbox status=%s attempts=%d, want sent after one failed attempt", status, attempts)
+	}
+	var dlqCount int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM dead_letter_events").Scan(&dlqCount); err != nil {
+		t.Fatalf("count dlq: %v", err)
+	}
+	if dlqCount != 0 || handler.Calls() != 2 {
+		t.Fatalf("dlq=%d handler calls=%d, want 0 and 2", dlqCount, handler.Calls())
+	}
+}
+
+func retryEvent() events.TelemetryWindowClosed {
+	return events.TelemetryWindowClosed{
+		StationID:   "station-retry",
+		WindowStart: time.Date(2026, time.January, 25, 14, 0, 0, 0, time.UTC),
+		WindowEnd:   time.Date(2026, time.January, 25, 15, 0, 0, 0, time.UTC),
+		OccurredAt:  time.Date(2026, time.January, 25, 15, 0, 0, 0, time.UTC),
+	}
+}
+
+// flakyHandler fails its first calls and succeeds afterwards.
+type flakyHandler struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (h *flakyHandler) Handle(context.Context, any) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("subscriber unavailable")
+	}
+	return nil
+}
+
+func (h *flakyHandler) Calls() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.calls
+}
+
+type countingDLQ struct {
+	count int
+}
+
+func (d *countingDLQ) RecordFailure(context.Context, eventing.Envelope, error) error {
+	d.count++
+	return nil
+}
+
+// memoryOutbox is a single-record outbox with the Postgres store's semantics.
+type memoryOutbox struct {
+	env           eventing.Envelope
+	status        string
+	attempts      int
+	nextAttemptAt time.Time
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{}
+}
+
+func (o *memoryOutbox) Insert(_ context.Context, env eventing.Envelope) (string, error) {
+	o.env, o.status = env, "pending"
+	return "outbox-1", nil
+}
+
+func (o *memoryOutbox) ListPending(context.Context, int) ([]eventing.OutboxRecord, error) {
+	if o.status != "pending" || time.Now().Before(o.nextAttemptAt) {
+		return nil, nil
+	}
+	o.status = "processing"
+	return []eventing.OutboxRecord{{ID: "outbox-1", Envelope: o.env, Attempts: o.attempts}}, nil
+}
+
+func (o *memoryOutbox) MarkSent(context.Context, string) error {
+	o.status = "sent"
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(context.Context, string) error {
+	o.status, o.attempts = "failed", o.attempts+1
+	return nil
+}
+
+func (o *memoryOutbox) MarkRetry(_ context.Context, _ string, nextAttemptAt time.Time) error {
+	o.status, o.attempts, o.nextAttemptAt = "pending", o.attempts+1, nextAttemptAt
+	return nil
+}
+
+func (o *memoryOutbox) ExpireBackoff() {
+	o.nextAttemptAt = time.Time{}
+}
+
+func (o *memoryOutbox) Status() string {
+	return o.status
+}
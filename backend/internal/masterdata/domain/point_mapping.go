@@ -6,6 +6,19 @@ import (
 	"time"
 )
 
+// Aggregation modes fold the points of one telemetry batch that map to the
+// same semantic into a single value.
+const (
+	// AggregationSum adds the values, e.g. the power of several meters.
+	AggregationSum = "sum"
+	// AggregationLast keeps the value of the latest point, e.g. SOC.
+	AggregationLast = "last"
+	// AggregationMax keeps the largest value, e.g. cell temperatures.
+	AggregationMax = "max"
+	// AggregationAvg averages the values.
+	AggregationAvg = "avg"
+)
+
 // PointMapping binds a raw telemetry point to a semantic meaning.
 type PointMapping struct {
 	ID        string
@@ -15,8 +28,10 @@ type PointMapping struct {
 	Semantic  string
 	Unit      string
 	Factor    float64
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	// Aggregation is one of the Aggregation modes; empty means AggregationSum.
+	Aggregation string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
 }
 
 // Validate checks mapping invariants.
@@ -36,9 +51,22 @@ func (m PointMapping) Validate() error {
 	if m.Unit == "" {
 		return errors.New("point mapping: empty unit")
 	}
+	switch m.Aggregation {
+	case "", AggregationSum, AggregationLast, AggregationMax, AggregationAvg:
+	default:
+		return errors.New("point mapping: aggregation must be sum, last, max or avg")
+	}
 	return nil
 }
 
+// AggregationMode returns the aggregation mode, defaulting to AggregationSum.
+func (m PointMapping) AggregationMode() string {
+	if m.Aggregation == "" {
+		return AggregationSum
+	}
+	return m.Aggregation
+}
+
 // PointMappingRepository manages point mapping persistence.
 type PointMappingRepository interface {
 	ListByStation(ctx context.Context, stationID string) ([]PointMapping, error)
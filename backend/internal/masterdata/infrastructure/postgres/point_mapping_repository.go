@@ -49,7 +49,7 @@ func (r *PointMappingRepository) ListByStation(ctx context.Context, stationID st
 	}
 
 	query := fmt.Sprintf(`
-SELECT id, station_id, device_id, point_key, semantic, unit, factor, created_at, updated_at
+SELECT id, station_id, device_id, point_key, semantic, unit, factor, aggregation, created_at, updated_at
 FROM %s
 WHERE station_id = $1
 ORDER BY point_key ASC`, r.table)
@@ -72,6 +72,7 @@ ORDER BY point_key ASC`, r.table)
 			&mapping.Semantic,
 			&mapping.Unit,
 			&mapping.Factor,
+			&mapping.Aggregation,
 			&mapping.CreatedAt,
 			&mapping.UpdatedAt,
 		); err != nil {
@@ -110,9 +111,10 @@ INSERT INTO %s (
 	point_key,
 	semantic,
 	unit,
-	factor
+	factor,
+	aggregation
 ) VALUES (
-	$1, $2, $3, $4, $5, $6, $7
+	$1, $2, $3, $4, $5, $6, $7, $8
 )
 ON CONFLICT (id)
 DO UPDATE SET
@@ -122,6 +124,7 @@ DO UPDATE SET
 	semantic = EXCLUDED.semantic,
 	unit = EXCLUDED.unit,
 	factor = EXCLUDED.factor,
+	aggregation = EXCLUDED.aggregation,
 	updated_at = NOW()`, r.table)
 
 	var deviceID sql.NullString
@@ -139,6 +142,7 @@ DO UPDATE SET
 		mapping.Semantic,
 		mapping.Unit,
 		mapping.Factor,
+		mapping.AggregationMode(),
 	)
 	if err != nil {
 		return err
@@ -77,6 +77,38 @@ LIMIT 1`, r.table)
 	return &station, nil
 }
 
+// ListStationIDs returns the ids of a tenant's stations in id order.
+func (r *StationRepository) ListStationIDs(ctx context.Context, tenantID string) ([]string, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("station repo: nil db")
+	}
+	if tenantID == "" {
+		return nil, errors.New("station repo: empty tenant id")
+	}
+
+	query := fmt.Sprintf(`
+SELECT id
+FROM %s
+WHERE tenant_id = $1
+ORDER BY id ASC`, r.table)
+
+	rows, err := r.db.QueryContext(ctx, query, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
 // Save upserts a station.
 func (r *StationRepository) Save(ctx context.Context, station *masterdata.Station) error {
 	if r == nil || r.db == nil {
@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// TenantSettingsRepository loads per-tenant settings.
+type TenantSettingsRepository struct {
+	db DBTX
+}
+
+// NewTenantSettingsRepository constructs a repository.
+func NewTenantSettingsRepository(db DBTX) *TenantSettingsRepository {
+	return &TenantSettingsRepository{db: db}
+}
+
+// CurrencyForTenant returns the tenant's settlement currency, or "" when the
+// tenant has no row or no currency configured.
+func (r *TenantSettingsRepository) CurrencyForTenant(ctx context.Context, tenantID string) (string, error) {
+	if r == nil || r.db == nil {
+		return "", errors.New("tenant settings repo: nil db")
+	}
+	if tenantID == "" {
+		return "", nil
+	}
+	var currency sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+SELECT currency
+FROM tenant_settings
+WHERE tenant_id = $1`, tenantID).Scan(&currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return currency.String, nil
+}
+
+// BillingCycleDayForTenant returns the day of month the tenant's billing cycle
+// starts on, or 0 when the tenant has no row or bills calendar months.
+func (r *TenantSettingsRepository) BillingCycleDayForTenant(ctx context.Context, tenantID string) (int, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("tenant settings repo: nil db")
+	}
+	if tenantID == "" {
+		return 0, nil
+	}
+	var day sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+SELECT billing_cycle_day
+FROM tenant_settings
+WHERE tenant_id = $1`, tenantID).Scan(&day)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return int(day.Int64), nil
+}
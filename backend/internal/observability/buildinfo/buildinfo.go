@@ -0,0 +1,51 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const unset = "dev"
+
+// Build metadata injected at link time, e.g.
+// -ldflags "-X microgrid-cloud/internal/observability/buildinfo.Version=v1.2.3".
+var (
+	Version   string
+	Commit    string
+	BuildTime string
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// Get returns the injected build metadata, with "dev" for unset values.
+func Get() Info {
+	return Info{
+		Version:   valueOrDev(Version),
+		Commit:    valueOrDev(Commit),
+		BuildTime: valueOrDev(BuildTime),
+	}
+}
+
+// Handler serves GET /version.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Get())
+	})
+}
+
+func valueOrDev(value string) string {
+	if value == "" {
+		return unset
+	}
+	return value
+}
@@ -0,0 +1,42 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler_ReturnsInjectedValues(t *testing.T) {
+	cases := []struct {
+		name                     string
+		version, commit, builtAt string
+		want                     Info
+	}{
+		{name: "unset", want: Info{Version: "dev", Commit: "dev", BuildTime: "dev"}},
+		{
+			name:    "injected",
+			version: "v1.4.0", commit: "abc1234", builtAt: "2026-01-02T03:04:05Z",
+			want: Info{Version: "v1.4.0", Commit: "abc1234", BuildTime: "2026-01-02T03:04:05Z"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			Version, Commit, BuildTime = tc.version, tc.commit, tc.builtAt
+			defer func() { Version, Commit, BuildTime = "", "", "" }()
+
+			w := httptest.NewRecorder()
+			Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status=%d", w.Code)
+			}
+			var got Info
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
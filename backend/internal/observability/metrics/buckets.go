@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Histogram names (without the platform_ prefix) accepted by WithBuckets.
+const (
+	HistogramIngestLatency            = "ingest_latency_seconds"
+	HistogramStatementGenerateLatency = "statement_generate_latency_seconds"
+	HistogramStatementFreezeLatency   = "statement_freeze_latency_seconds"
+	HistogramStatementExportLatency   = "statement_export_latency_seconds"
+	HistogramAnalyticsWindowLatency   = "analytics_window_latency_seconds"
+	HistogramSettlementDayLatency     = "settlement_day_latency_seconds"
+	HistogramWindowCloseLatency       = "window_close_latency_seconds"
+	HistogramOutboxPublishLatency     = "outbox_publish_latency_seconds"
+	HistogramOutboxDispatchLatency    = "outbox_dispatch_latency_seconds"
+)
+
+// defaultBuckets are tuned to the expected latency of each operation rather
+// than prometheus.DefBuckets, which targets web requests.
+var defaultBuckets = map[string][]float64{
+	HistogramIngestLatency:            {0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	HistogramStatementGenerateLatency: {0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	HistogramStatementFreezeLatency:   {0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	HistogramStatementExportLatency:   {0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	HistogramAnalyticsWindowLatency:   {0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25},
+	HistogramSettlementDayLatency:     {0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	HistogramWindowCloseLatency:       {0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+	HistogramOutboxPublishLatency:     {0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5},
+	HistogramOutboxDispatchLatency:    {0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+}
+
+// Option configures Init.
+type Option func(*config)
+
+type config struct {
+	buckets map[string][]float64
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{buckets: make(map[string][]float64, len(defaultBuckets))}
+	for name, buckets := range defaultBuckets {
+		cfg.buckets[name] = buckets
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return cfg
+}
+
+// WithBuckets overrides the bucket upper bounds of a latency histogram.
+// Unknown names and empty or repeating bucket lists are ignored, since
+// Prometheus panics on bounds that are not strictly increasing.
+func WithBuckets(name string, buckets []float64) Option {
+	return func(cfg *config) {
+		if _, ok := defaultBuckets[name]; !ok {
+			return
+		}
+		if sorted, err := sortBuckets(buckets); err == nil {
+			cfg.buckets[name] = sorted
+		}
+	}
+}
+
+// sortBuckets sorts a copy of buckets and checks the bounds are strictly
+// increasing.
+func sortBuckets(buckets []float64) ([]float64, error) {
+	if len(buckets) == 0 {
+		return nil, fmt.Errorf("no buckets")
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i] <= sorted[i-1] {
+			return nil, fmt.Errorf("duplicate bucket %v", sorted[i])
+		}
+	}
+	return sorted, nil
+}
+
+// ParseBucketSpec parses "name=b1,b2,...;name=b1,..." into bucket options.
+func ParseBucketSpec(spec string) ([]Option, error) {
+	var opts []Option
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, list, ok := strings.Cut(entry, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), metricPrefix)
+		if !ok {
+			return nil, fmt.Errorf("metrics buckets: invalid entry %q", entry)
+		}
+		if _, known := defaultBuckets[name]; !known {
+			return nil, fmt.Errorf("metrics buckets: unknown histogram %q", name)
+		}
+		var buckets []float64
+		for _, raw := range strings.Split(list, ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil || value <= 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+				return nil, fmt.Errorf("metrics buckets: invalid bucket %q for %s", raw, name)
+			}
+			buckets = append(buckets, value)
+		}
+		if _, err := sortBuckets(buckets); err != nil {
+			return nil, fmt.Errorf("metrics buckets: %s: %w", name, err)
+		}
+		opts = append(opts, WithBuckets(name, buckets))
+	}
+	return opts, nil
+}
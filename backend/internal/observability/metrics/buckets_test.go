@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestInit_AppliesCustomBuckets(t *testing.T) {
+	opts, err := ParseBucketSpec("platform_statement_export_latency_seconds=5,1,30")
+	if err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+	Init(nil, nil, opts...)
+
+	ObserveStatementExport("csv", resultSuccess, 0)
+	ObserveAnalyticsWindow(resultSuccess, 0)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("gather: %v", err)
+	}
+	got := map[string][]float64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if metric.GetHistogram() == nil {
+				continue
+			}
+			var bounds []float64
+			for _, bucket := range metric.GetHistogram().GetBucket() {
+				bounds = append(bounds, bucket.GetUpperBound())
+			}
+			got[family.GetName()] = bounds
+		}
+	}
+
+	if want := []float64{1, 5, 30}; !reflect.DeepEqual(got[metricPrefix+HistogramStatementExportLatency], want) {
+		t.Fatalf("export buckets = %v, want %v", got[metricPrefix+HistogramStatementExportLatency], want)
+	}
+	if want := defaultBuckets[HistogramAnalyticsWindowLatency]; !reflect.DeepEqual(got[metricPrefix+HistogramAnalyticsWindowLatency], want) {
+		t.Fatalf("analytics buckets = %v, want %v", got[metricPrefix+HistogramAnalyticsWindowLatency], want)
+	}
+}
+
+func TestParseBucketSpec_RejectsInvalidEntries(t *testing.T) {
+	for _, spec := range []string{
+		"unknown_latency_seconds=1,2",
+		"ingest_latency_seconds",
+		"ingest_latency_seconds=0.1,abc",
+		"ingest_latency_seconds=-1",
+		"ingest_latency_seconds=1,1",
+		"ingest_latency_seconds=2,0.5,2",
+		"ingest_latency_seconds=NaN",
+	} {
+		if _, err := ParseBucketSpec(spec); err == nil {
+			t.Fatalf("expected error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestStationAndTenantLabels_CollapseUnknownIDs(t *testing.T) {
+	SetKnownStations([]string{"station-001", ""})
+	SetKnownTenants([]string{"tenant-a"})
+	defer SetKnownStations(nil)
+	defer SetKnownTenants(nil)
+
+	cases := []struct {
+		got, want string
+	}{
+		{StationLabel("station-001"), "station-001"},
+		{StationLabel("station-unknown"), LabelOther},
+		{StationLabel(""), LabelOther},
+		{TenantLabel("tenant-a"), "tenant-a"},
+		{TenantLabel("tenant-flood-12345"), LabelOther},
+	}
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Fatalf("label = %q, want %q", tc.got, tc.want)
+		}
+	}
+
+	SetKnownStations([]string{"station-002"})
+	if got := StationLabel("station-001"); got != LabelOther {
+		t.Fatalf("expected refreshed set to drop station-001, got %q", got)
+	}
+}
+
+func TestStationLabel_BoundsSeriesUnderFlood(t *testing.T) {
+	SetKnownStations([]string{"station-001", "station-002"})
+	defer SetKnownStations(nil)
+
+	vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_station_flood_total"}, []string{"station"})
+	for i := 0; i < 1000; i++ {
+		vec.WithLabelValues(StationLabel(fmt.Sprintf("station-flood-%d", i))).Inc()
+	}
+	vec.WithLabelValues(StationLabel("station-001")).Inc()
+	vec.WithLabelValues(StationLabel("station-002")).Inc()
+
+	if n := testutil.CollectAndCount(vec); n != 3 {
+		t.Fatalf("series = %d, want the two known stations and other", n)
+	}
+	if got := testutil.ToFloat64(vec.WithLabelValues(LabelOther)); got != 1000 {
+		t.Fatalf("other = %v, want 1000", got)
+	}
+}
@@ -1,11 +1,14 @@
 package metrics
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"sync"
 	"time"
 
+	"microgrid-cloud/internal/observability/buildinfo"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -14,15 +17,21 @@ const (
 
 	resultSuccess = "success"
 	resultError   = "error"
+	resultWaiting = "waiting"
 
 	commandResultAcked   = "acked"
 	commandResultFailed  = "failed"
 	commandResultTimeout = "timeout"
+
+	// LabelOther replaces station/tenant label values that are not in the known set.
+	LabelOther = "other"
 )
 
 var (
 	registerOnce sync.Once
 
+	buildInfo *prometheus.GaugeVec
+
 	ingestRequests *prometheus.CounterVec
 	ingestErrors   *prometheus.CounterVec
 	ingestLatency  *prometheus.HistogramVec
@@ -42,22 +51,49 @@ var (
 	analyticsWindowTotal   *prometheus.CounterVec
 	analyticsWindowLatency *prometheus.HistogramVec
 
-	settlementDayTotal   *prometheus.CounterVec
-	settlementDayLatency *prometheus.HistogramVec
+	settlementDayTotal     *prometheus.CounterVec
+	settlementDayLatency   *prometheus.HistogramVec
+	settlementAnomalyTotal *prometheus.CounterVec
 
-	alarmEventsTotal *prometheus.CounterVec
+	alarmEventsTotal          *prometheus.CounterVec
+	alarmStreamEvictionsTotal prometheus.Counter
+	alarmNotifyExhaustedTotal prometheus.Counter
+	alarmFlapsTotal           prometheus.Counter
 
 	windowCloseLatency *prometheus.HistogramVec
 
+	httpRateLimitedTotal *prometheus.CounterVec
+
+	tbCircuitTransitions *prometheus.CounterVec
+	tbCircuitRejected    prometheus.Counter
+
+	settlementWebhookTotal *prometheus.CounterVec
+
 	outboxPublishLatency  *prometheus.HistogramVec
 	outboxDispatchLatency *prometheus.HistogramVec
 	outboxDispatchTotal   *prometheus.CounterVec
 	outboxDispatchEvents  *prometheus.CounterVec
+
+	labelMu       sync.RWMutex
+	knownStations = map[string]struct{}{}
+	knownTenants  = map[string]struct{}{}
 )
 
 // Init registers observability metrics and DB-backed gauges.
-func Init(db *sql.DB, logger *log.Logger) {
+func Init(db *sql.DB, logger *log.Logger, opts ...Option) {
 	registerOnce.Do(func() {
+		cfg := newConfig(opts)
+
+		buildInfo = prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: metricPrefix + "build_info",
+				Help: "Build metadata of the running binary (always 1)",
+			},
+			[]string{"version", "commit", "build_time"},
+		)
+		info := buildinfo.Get()
+		buildInfo.WithLabelValues(info.Version, info.Commit, info.BuildTime).Set(1)
+
 		ingestRequests = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
 				Name: metricPrefix + "ingest_requests_total",
@@ -74,9 +110,9 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		ingestLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "ingest_latency_seconds",
+				Name:    metricPrefix + HistogramIngestLatency,
 				Help:    "Ingest latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramIngestLatency],
 			},
 			[]string{"result"},
 		)
@@ -112,9 +148,9 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		statementGenerateLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "statement_generate_latency_seconds",
+				Name:    metricPrefix + HistogramStatementGenerateLatency,
 				Help:    "Statement generate latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramStatementGenerateLatency],
 			},
 			[]string{"result"},
 		)
@@ -127,9 +163,9 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		statementFreezeLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "statement_freeze_latency_seconds",
+				Name:    metricPrefix + HistogramStatementFreezeLatency,
 				Help:    "Statement freeze latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramStatementFreezeLatency],
 			},
 			[]string{"result"},
 		)
@@ -142,9 +178,9 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		statementExportLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "statement_export_latency_seconds",
+				Name:    metricPrefix + HistogramStatementExportLatency,
 				Help:    "Statement export latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramStatementExportLatency],
 			},
 			[]string{"format", "result"},
 		)
@@ -158,9 +194,9 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		analyticsWindowLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "analytics_window_latency_seconds",
+				Name:    metricPrefix + HistogramAnalyticsWindowLatency,
 				Help:    "Analytics hourly window latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramAnalyticsWindowLatency],
 			},
 			[]string{"result"},
 		)
@@ -174,12 +210,19 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 		settlementDayLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "settlement_day_latency_seconds",
+				Name:    metricPrefix + HistogramSettlementDayLatency,
 				Help:    "Day settlement latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramSettlementDayLatency],
 			},
 			[]string{"result"},
 		)
+		settlementAnomalyTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "settlement_anomaly_total",
+				Help: "Total day settlements flagged as implausible by reason",
+			},
+			[]string{"reason"},
+		)
 
 		alarmEventsTotal = prometheus.NewCounterVec(
 			prometheus.CounterOpts{
@@ -188,29 +231,77 @@ func Init(db *sql.DB, logger *log.Logger) {
 			},
 			[]string{"event"},
 		)
+		alarmStreamEvictionsTotal = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "alarm_stream_evictions_total",
+				Help: "Total alarm stream clients evicted because their buffer overflowed",
+			},
+		)
+		alarmNotifyExhaustedTotal = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "alarm_notify_retry_exhausted_total",
+				Help: "Total alarm notifications dropped after their last delivery retry failed",
+			},
+		)
+		alarmFlapsTotal = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "alarm_flaps_total",
+				Help: "Total alarms detected flapping between triggered and cleared",
+			},
+		)
+
+		httpRateLimitedTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "http_rate_limited_total",
+				Help: "Total HTTP requests rejected by the rate limiter by route class",
+			},
+			[]string{"class"},
+		)
+
+		tbCircuitTransitions = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "tb_circuit_transitions_total",
+				Help: "Total ThingsBoard circuit breaker state changes by new state",
+			},
+			[]string{"state"},
+		)
+		tbCircuitRejected = prometheus.NewCounter(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "tb_circuit_rejected_total",
+				Help: "Total ThingsBoard calls failed fast by an open circuit breaker",
+			},
+		)
+
+		settlementWebhookTotal = prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: metricPrefix + "settlement_webhook_deliveries_total",
+				Help: "Total settlement webhook deliveries by result, counted once per event after retries",
+			},
+			[]string{"result"},
+		)
 
 		windowCloseLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "window_close_latency_seconds",
+				Name:    metricPrefix + HistogramWindowCloseLatency,
 				Help:    "Window close handler latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramWindowCloseLatency],
 			},
 			[]string{"result"},
 		)
 
 		outboxPublishLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "outbox_publish_latency_seconds",
+				Name:    metricPrefix + HistogramOutboxPublishLatency,
 				Help:    "Outbox publish latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramOutboxPublishLatency],
 			},
 			[]string{"result"},
 		)
 		outboxDispatchLatency = prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    metricPrefix + "outbox_dispatch_latency_seconds",
+				Name:    metricPrefix + HistogramOutboxDispatchLatency,
 				Help:    "Outbox dispatch latency in seconds",
-				Buckets: prometheus.DefBuckets,
+				Buckets: cfg.buckets[HistogramOutboxDispatchLatency],
 			},
 			[]string{"result"},
 		)
@@ -230,6 +321,7 @@ func Init(db *sql.DB, logger *log.Logger) {
 		)
 
 		prometheus.MustRegister(
+			buildInfo,
 			ingestRequests,
 			ingestErrors,
 			ingestLatency,
@@ -246,8 +338,16 @@ func Init(db *sql.DB, logger *log.Logger) {
 			analyticsWindowLatency,
 			settlementDayTotal,
 			settlementDayLatency,
+			settlementAnomalyTotal,
 			alarmEventsTotal,
+			alarmStreamEvictionsTotal,
+			alarmNotifyExhaustedTotal,
+			alarmFlapsTotal,
 			windowCloseLatency,
+			httpRateLimitedTotal,
+			tbCircuitTransitions,
+			tbCircuitRejected,
+			settlementWebhookTotal,
 			outboxPublishLatency,
 			outboxDispatchLatency,
 			outboxDispatchTotal,
@@ -391,6 +491,16 @@ func ObserveSettlementDay(result string, duration time.Duration) {
 	}
 }
 
+// IncSettlementAnomaly counts a day settlement flagged as implausible.
+func IncSettlementAnomaly(reason string) {
+	if reason == "" {
+		reason = "unknown"
+	}
+	if settlementAnomalyTotal != nil {
+		settlementAnomalyTotal.WithLabelValues(reason).Inc()
+	}
+}
+
 // ObserveWindowClose records window-close handler latency.
 func ObserveWindowClose(result string, duration time.Duration) {
 	if result == "" {
@@ -445,6 +555,55 @@ func IncAlarmEvent(event string) {
 	}
 }
 
+// IncAlarmStreamEviction counts an alarm stream client dropped for falling behind.
+func IncAlarmStreamEviction() {
+	if alarmStreamEvictionsTotal != nil {
+		alarmStreamEvictionsTotal.Inc()
+	}
+}
+
+// IncAlarmNotifyRetryExhausted counts an alarm notification given up after its retries.
+func IncAlarmNotifyRetryExhausted() {
+	if alarmNotifyExhaustedTotal != nil {
+		alarmNotifyExhaustedTotal.Inc()
+	}
+}
+
+// IncAlarmFlap counts an alarm detected flapping.
+func IncAlarmFlap() {
+	if alarmFlapsTotal != nil {
+		alarmFlapsTotal.Inc()
+	}
+}
+
+// IncRateLimited counts a request rejected by the rate limiter.
+func IncRateLimited(class string) {
+	if httpRateLimitedTotal != nil {
+		httpRateLimitedTotal.WithLabelValues(class).Inc()
+	}
+}
+
+// IncTBCircuitTransition counts a ThingsBoard circuit breaker entering state.
+func IncTBCircuitTransition(state string) {
+	if tbCircuitTransitions != nil {
+		tbCircuitTransitions.WithLabelValues(state).Inc()
+	}
+}
+
+// IncTBCircuitRejected counts a ThingsBoard call failed fast by an open breaker.
+func IncTBCircuitRejected() {
+	if tbCircuitRejected != nil {
+		tbCircuitRejected.Inc()
+	}
+}
+
+// IncSettlementWebhook counts a settlement webhook delivery by result.
+func IncSettlementWebhook(result string) {
+	if settlementWebhookTotal != nil {
+		settlementWebhookTotal.WithLabelValues(result).Inc()
+	}
+}
+
 // Exported constants for callers.
 const (
 	IngestResultSuccess = resultSuccess
@@ -452,8 +611,111 @@ const (
 
 	ResultSuccess = resultSuccess
 	ResultError   = resultError
+	ResultWaiting = resultWaiting
 
 	CommandResultAcked   = commandResultAcked
 	CommandResultFailed  = commandResultFailed
 	CommandResultTimeout = commandResultTimeout
 )
+
+// SetKnownStations replaces the set of station ids allowed as label values.
+func SetKnownStations(ids []string) {
+	set := toSet(ids)
+	labelMu.Lock()
+	knownStations = set
+	labelMu.Unlock()
+}
+
+// SetKnownTenants replaces the set of tenant ids allowed as label values.
+func SetKnownTenants(ids []string) {
+	set := toSet(ids)
+	labelMu.Lock()
+	knownTenants = set
+	labelMu.Unlock()
+}
+
+// StationLabel returns the station id when known, otherwise LabelOther.
+// Use it for every station-labeled series to keep cardinality bounded.
+func StationLabel(stationID string) string {
+	labelMu.RLock()
+	_, ok := knownStations[stationID]
+	labelMu.RUnlock()
+	if !ok {
+		return LabelOther
+	}
+	return stationID
+}
+
+// TenantLabel returns the tenant id when known, otherwise LabelOther.
+func TenantLabel(tenantID string) string {
+	labelMu.RLock()
+	_, ok := knownTenants[tenantID]
+	labelMu.RUnlock()
+	if !ok {
+		return LabelOther
+	}
+	return tenantID
+}
+
+// RefreshLabelSets loads known station and tenant ids from the stations table.
+func RefreshLabelSets(ctx context.Context, db *sql.DB) error {
+	if db == nil {
+		return nil
+	}
+	rows, err := db.QueryContext(ctx, "SELECT id, tenant_id FROM stations")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var stations, tenants []string
+	for rows.Next() {
+		var stationID, tenantID string
+		if err := rows.Scan(&stationID, &tenantID); err != nil {
+			return err
+		}
+		stations = append(stations, stationID)
+		tenants = append(tenants, tenantID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	SetKnownStations(stations)
+	SetKnownTenants(tenants)
+	return nil
+}
+
+// StartLabelRefresh loads the known label sets immediately and then every interval until ctx is done.
+func StartLabelRefresh(ctx context.Context, db *sql.DB, interval time.Duration, logger *log.Logger) {
+	refresh := func() {
+		if err := RefreshLabelSets(ctx, db); err != nil && logger != nil {
+			logger.Printf("metrics label refresh failed: %v", err)
+		}
+	}
+	refresh()
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+}
+
+func toSet(ids []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			set[id] = struct{}{}
+		}
+	}
+	return set
+}
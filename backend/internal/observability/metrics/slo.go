@@ -0,0 +1,131 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// sloPipelines lists the pipelines of the SLO report, in report order, by the
+// latency histogram each one observes with a result label.
+var sloPipelines = []struct {
+	name      string
+	histogram string
+}{
+	{"ingest", HistogramIngestLatency},
+	{"window_close", HistogramWindowCloseLatency},
+	{"analytics_window", HistogramAnalyticsWindowLatency},
+	{"settlement_day", HistogramSettlementDayLatency},
+	{"statement_generate", HistogramStatementGenerateLatency},
+	{"outbox_dispatch", HistogramOutboxDispatchLatency},
+}
+
+// SLOReport summarizes the key pipelines from the in-process metrics. The
+// counts are cumulative since the process started.
+type SLOReport struct {
+	GeneratedAt time.Time     `json:"generated_at"`
+	Pipelines   []PipelineSLO `json:"pipelines"`
+}
+
+// PipelineSLO is the success rate and latency of one pipeline. SuccessRate is
+// success / (success + error); other results, e.g. a settlement still waiting
+// for its hours, count towards Total only. Rates and latencies are nil until
+// the pipeline has observations.
+type PipelineSLO struct {
+	Name        string   `json:"name"`
+	Metric      string   `json:"metric"`
+	Total       uint64   `json:"total"`
+	Success     uint64   `json:"success"`
+	Errors      uint64   `json:"errors"`
+	SuccessRate *float64 `json:"success_rate"`
+	P50Seconds  *float64 `json:"p50_seconds"`
+	P95Seconds  *float64 `json:"p95_seconds"`
+}
+
+// BuildSLOReport reads the pipeline histograms from gatherer, normally
+// prometheus.DefaultGatherer.
+func BuildSLOReport(gatherer prometheus.Gatherer, now time.Time) (SLOReport, error) {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return SLOReport{}, err
+	}
+	byName := make(map[string]*dto.MetricFamily, len(families))
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	report := SLOReport{GeneratedAt: now.UTC(), Pipelines: make([]PipelineSLO, 0, len(sloPipelines))}
+	for _, pipeline := range sloPipelines {
+		name := metricPrefix + pipeline.histogram
+		slo := PipelineSLO{Name: pipeline.name, Metric: name}
+		buckets := map[float64]uint64{}
+		if family := byName[name]; family != nil {
+			for _, metric := range family.GetMetric() {
+				histogram := metric.GetHistogram()
+				if histogram == nil {
+					continue
+				}
+				count := histogram.GetSampleCount()
+				slo.Total += count
+				switch resultLabel(metric) {
+				case resultSuccess:
+					slo.Success += count
+				case resultError:
+					slo.Errors += count
+				}
+				for _, bucket := range histogram.GetBucket() {
+					buckets[bucket.GetUpperBound()] += bucket.GetCumulativeCount()
+				}
+			}
+		}
+		if decided := slo.Success + slo.Errors; decided > 0 {
+			rate := float64(slo.Success) / float64(decided)
+			slo.SuccessRate = &rate
+		}
+		slo.P50Seconds = bucketQuantile(0.5, buckets, slo.Total)
+		slo.P95Seconds = bucketQuantile(0.95, buckets, slo.Total)
+		report.Pipelines = append(report.Pipelines, slo)
+	}
+	return report, nil
+}
+
+func resultLabel(metric *dto.Metric) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == "result" {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
+
+// bucketQuantile estimates the q quantile from cumulative bucket counts by
+// linear interpolation within the bucket, as histogram_quantile does. A rank
+// beyond the largest bound returns that bound.
+func bucketQuantile(q float64, buckets map[float64]uint64, total uint64) *float64 {
+	if total == 0 || len(buckets) == 0 {
+		return nil
+	}
+	bounds := make([]float64, 0, len(buckets))
+	for bound := range buckets {
+		bounds = append(bounds, bound)
+	}
+	sort.Float64s(bounds)
+
+	rank := q * float64(total)
+	lower, below := 0.0, uint64(0)
+	for _, bound := range bounds {
+		cumulative := buckets[bound]
+		if float64(cumulative) >= rank && !math.IsInf(bound, 1) {
+			value := bound
+			if inBucket := cumulative - below; inBucket > 0 {
+				value = lower + (bound-lower)*(rank-float64(below))/float64(inBucket)
+			}
+			return &value
+		}
+		lower, below = bound, cumulative
+	}
+	return &lower
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultBulkConcurrency = 4
+
+// BulkProvisionResult is the outcome of a single bulk row.
+type BulkProvisionResult struct {
+	Row       int                    `json:"row"`
+	StationID string                 `json:"station_id,omitempty"`
+	OK        bool                   `json:"ok"`
+	Error     string                 `json:"error,omitempty"`
+	TB        *TBProvisioningSummary `json:"tb,omitempty"`
+}
+
+// WithBulkConcurrency bounds how many stations are provisioned in parallel by ProvisionBulk.
+func WithBulkConcurrency(limit int) Option {
+	return func(s *Service) {
+		if limit > 0 {
+			s.bulkConcurrency = limit
+		}
+	}
+}
+
+// ProvisionBulk provisions each request independently and returns per-row results in input order.
+func (s *Service) ProvisionBulk(ctx context.Context, reqs []ProvisionRequest) []BulkProvisionResult {
+	results := make([]BulkProvisionResult, len(reqs))
+	sem := make(chan struct{}, s.bulkConcurrency)
+	var wg sync.WaitGroup
+	for i := range reqs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			result := BulkProvisionResult{Row: i + 1, StationID: reqs[i].Station.ID}
+			resp, err := s.ProvisionStation(ctx, reqs[i])
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.OK = true
+				result.StationID = resp.StationID
+				result.TB = &resp.TB
+			}
+			results[i] = result
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
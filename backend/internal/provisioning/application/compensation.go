@@ -0,0 +1,82 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PartialProvisionError reports TB entities left behind by a failed provisioning run.
+type PartialProvisionError struct {
+	StationID   string
+	AssetID     string
+	TBDeviceIDs []string
+	Compensated bool
+	Err         error
+}
+
+func (e *PartialProvisionError) Error() string {
+	if e.Compensated {
+		return fmt.Sprintf("provisioning: station %s failed and created tb entities were removed: %v", e.StationID, e.Err)
+	}
+	parts := make([]string, 0, len(e.TBDeviceIDs)+1)
+	if e.AssetID != "" {
+		parts = append(parts, "asset="+e.AssetID)
+	}
+	for _, id := range e.TBDeviceIDs {
+		parts = append(parts, "device="+id)
+	}
+	return fmt.Sprintf("provisioning: station %s failed with partial tb state [%s]: %v", e.StationID, strings.Join(parts, " "), e.Err)
+}
+
+func (e *PartialProvisionError) Unwrap() error {
+	return e.Err
+}
+
+type createdEntities struct {
+	AssetID string
+	Devices []createdDevice
+}
+
+type createdDevice struct {
+	DeviceID   string
+	TBDeviceID string
+}
+
+func (c *createdEntities) empty() bool {
+	return c.AssetID == "" && len(c.Devices) == 0
+}
+
+// compensate deletes TB entities created during a failed run, newest first.
+// Entities that could not be removed are reported in the returned error.
+func (s *Service) compensate(ctx context.Context, stationID string, created *createdEntities, cause error) error {
+	if created == nil || created.empty() {
+		return cause
+	}
+	partial := &PartialProvisionError{StationID: stationID, Err: cause}
+	if !s.compensation {
+		partial.AssetID = created.AssetID
+		for _, device := range created.Devices {
+			partial.TBDeviceIDs = append(partial.TBDeviceIDs, device.TBDeviceID)
+		}
+		return partial
+	}
+
+	for i := len(created.Devices) - 1; i >= 0; i-- {
+		device := created.Devices[i]
+		if err := s.tb.DeleteDevice(ctx, device.TBDeviceID); err != nil {
+			partial.TBDeviceIDs = append(partial.TBDeviceIDs, device.TBDeviceID)
+			continue
+		}
+		_ = updateDeviceTBMapping(ctx, s.db, device.DeviceID, "")
+	}
+	if created.AssetID != "" {
+		if err := s.tb.DeleteAsset(ctx, created.AssetID); err != nil {
+			partial.AssetID = created.AssetID
+		} else {
+			_ = clearStationTBMapping(ctx, s.db, stationID, created.AssetID)
+		}
+	}
+	partial.Compensated = partial.AssetID == "" && len(partial.TBDeviceIDs) == 0
+	return partial
+}
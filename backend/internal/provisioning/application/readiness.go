@@ -0,0 +1,143 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+)
+
+const defaultTelemetryWindow = 15 * time.Minute
+
+// Readiness check names.
+const (
+	CheckStation       = "station"
+	CheckPointMappings = "point_mappings"
+	CheckTBAsset       = "tb_asset"
+	CheckTBDevices     = "tb_devices"
+	CheckTelemetry     = "telemetry"
+)
+
+// ErrStationNotFound indicates the station is missing in masterdata.
+var ErrStationNotFound = errors.New("provisioning: station not found")
+
+// ReadinessReport summarizes commissioning checks for a station.
+type ReadinessReport struct {
+	StationID string           `json:"station_id"`
+	Ready     bool             `json:"ready"`
+	CheckedAt time.Time        `json:"checked_at"`
+	Checks    []ReadinessCheck `json:"checks"`
+}
+
+// ReadinessCheck is a single pass/fail check result.
+type ReadinessCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// CheckReadiness verifies masterdata, TB entities and telemetry flow for a station.
+func (s *Service) CheckReadiness(ctx context.Context, stationID string) (*ReadinessReport, error) {
+	if stationID == "" {
+		return nil, errors.New("provisioning: empty station id")
+	}
+	station, err := masterdatarepo.NewStationRepository(s.db).Get(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if station == nil {
+		return nil, ErrStationNotFound
+	}
+
+	now := s.clock.Now().UTC()
+	report := &ReadinessReport{StationID: stationID, CheckedAt: now}
+	report.add(CheckStation, true, "")
+
+	mappings, err := masterdatarepo.NewPointMappingRepository(s.db).ListByStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		report.add(CheckPointMappings, false, "no point mappings")
+	} else {
+		report.add(CheckPointMappings, true, fmt.Sprintf("%d mappings", len(mappings)))
+	}
+
+	report.addCheck(CheckTBAsset, s.checkTBEntity(ctx, "ASSET", station.TBAssetID, "station_id", stationID))
+
+	devices, err := masterdatarepo.NewDeviceRepository(s.db).ListByStation(ctx, stationID)
+	if err != nil {
+		return nil, err
+	}
+	devicesCheck := ReadinessCheck{Name: CheckTBDevices, Passed: true, Detail: fmt.Sprintf("%d devices", len(devices))}
+	for _, device := range devices {
+		if check := s.checkTBEntity(ctx, "DEVICE", device.TBEntityID, "device_id", device.ID); !check.Passed {
+			devicesCheck = ReadinessCheck{Name: CheckTBDevices, Passed: false, Detail: "device " + device.ID + ": " + check.Detail}
+			break
+		}
+	}
+	report.addCheck(CheckTBDevices, devicesCheck)
+
+	lastTS, err := s.lastTelemetryAt(ctx, station.TenantID, stationID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case lastTS.IsZero():
+		report.add(CheckTelemetry, false, "no telemetry received")
+	case now.Sub(lastTS) > s.telemetryWindow:
+		report.add(CheckTelemetry, false, "last telemetry at "+lastTS.Format(time.RFC3339))
+	default:
+		report.add(CheckTelemetry, true, "last telemetry at "+lastTS.Format(time.RFC3339))
+	}
+
+	report.Ready = true
+	for _, check := range report.Checks {
+		if !check.Passed {
+			report.Ready = false
+			break
+		}
+	}
+	return report, nil
+}
+
+func (s *Service) checkTBEntity(ctx context.Context, entityType, entityID, key, expected string) ReadinessCheck {
+	if entityID == "" {
+		return ReadinessCheck{Passed: false, Detail: "tb entity id not mapped"}
+	}
+	attrs, err := s.tb.GetAttributes(ctx, entityType, entityID)
+	if err != nil {
+		return ReadinessCheck{Passed: false, Detail: "tb lookup failed: " + err.Error()}
+	}
+	if value, ok := attrs[key]; !ok || fmt.Sprint(value) != expected {
+		return ReadinessCheck{Passed: false, Detail: "tb attribute " + key + " missing or mismatched"}
+	}
+	return ReadinessCheck{Passed: true, Detail: entityID}
+}
+
+func (s *Service) lastTelemetryAt(ctx context.Context, tenantID, stationID string) (time.Time, error) {
+	var ts *time.Time
+	if err := s.db.QueryRowContext(ctx, `
+SELECT MAX(ts)
+FROM telemetry_points
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND ts >= $3`, tenantID, stationID, s.clock.Now().UTC().Add(-24*time.Hour)).Scan(&ts); err != nil {
+		return time.Time{}, err
+	}
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	return ts.UTC(), nil
+}
+
+func (r *ReadinessReport) add(name string, passed bool, detail string) {
+	r.Checks = append(r.Checks, ReadinessCheck{Name: name, Passed: passed, Detail: detail})
+}
+
+func (r *ReadinessReport) addCheck(name string, check ReadinessCheck) {
+	check.Name = name
+	r.Checks = append(r.Checks, check)
+}
@@ -6,6 +6,9 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	alarms "microgrid-cloud/internal/alarms/domain"
+	"microgrid-cloud/internal/clock"
 	masterdata "microgrid-cloud/internal/masterdata/domain"
 	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
 	"microgrid-cloud/internal/tbadapter"
@@ -17,6 +20,11 @@ type ProvisionRequest struct {
 	Station       StationInput        `json:"station"`
 	Devices       []DeviceInput       `json:"devices"`
 	PointMappings []PointMappingInput `json:"point_mappings"`
+	// ApplyAlarmTemplates instantiates the alarm rule templates of the
+	// station's type once the station is provisioned.
+	ApplyAlarmTemplates bool `json:"apply_alarm_templates,omitempty"`
+	// AlarmThresholdOverrides maps template ids to this station's threshold.
+	AlarmThresholdOverrides map[string]float64 `json:"alarm_threshold_overrides,omitempty"`
 }
 
 // StationInput describes a station to provision.
@@ -46,12 +54,16 @@ type PointMappingInput struct {
 	Semantic string  `json:"semantic"`
 	Unit     string  `json:"unit"`
 	Factor   float64 `json:"factor"`
+	// Aggregation is sum (default), last, max or avg; see masterdata.PointMapping.
+	Aggregation string `json:"aggregation,omitempty"`
 }
 
 // ProvisionResponse summarizes provisioning output.
 type ProvisionResponse struct {
 	StationID string                `json:"station_id"`
 	TB        TBProvisioningSummary `json:"tb"`
+	// AlarmRules lists the ids of the rules instantiated from templates.
+	AlarmRules []string `json:"alarm_rules,omitempty"`
 }
 
 // TBProvisioningSummary returns tb mapping info.
@@ -70,19 +82,70 @@ type TBDeviceSummary struct {
 
 // Service provisions stations and mappings.
 type Service struct {
-	db *sql.DB
-	tb *tbadapter.Client
+	db              *sql.DB
+	tb              *tbadapter.Client
+	telemetryWindow time.Duration
+	compensation    bool
+	bulkConcurrency int
+	alarmTemplates  AlarmTemplateApplier
+	clock           clock.Clock
+}
+
+// AlarmTemplateApplier instantiates the alarm rule templates of a station
+// type onto a station.
+type AlarmTemplateApplier interface {
+	ApplyTemplates(ctx context.Context, tenantID, stationID, stationType string, overrides map[string]float64) ([]alarms.AlarmRule, error)
+}
+
+// Option configures the provisioning service.
+type Option func(*Service)
+
+// WithTelemetryWindow sets how recent telemetry must be for the readiness check.
+func WithTelemetryWindow(window time.Duration) Option {
+	return func(s *Service) {
+		if window > 0 {
+			s.telemetryWindow = window
+		}
+	}
+}
+
+// WithClock overrides the clock the readiness check measures telemetry age with.
+func WithClock(c clock.Clock) Option {
+	return func(s *Service) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// WithCompensation enables best-effort deletion of TB entities created by a failed provisioning run.
+func WithCompensation(enabled bool) Option {
+	return func(s *Service) {
+		s.compensation = enabled
+	}
+}
+
+// WithAlarmTemplates lets provisioning requests apply the alarm rule templates
+// of the station's type.
+func WithAlarmTemplates(applier AlarmTemplateApplier) Option {
+	return func(s *Service) {
+		s.alarmTemplates = applier
+	}
 }
 
 // NewService constructs a provisioning service.
-func NewService(db *sql.DB, tb *tbadapter.Client) (*Service, error) {
+func NewService(db *sql.DB, tb *tbadapter.Client, opts ...Option) (*Service, error) {
 	if db == nil {
 		return nil, errors.New("provisioning: nil db")
 	}
 	if tb == nil {
 		return nil, errors.New("provisioning: nil tb client")
 	}
-	return &Service{db: db, tb: tb}, nil
+	service := &Service{db: db, tb: tb, telemetryWindow: defaultTelemetryWindow, bulkConcurrency: defaultBulkConcurrency, clock: clock.System{}}
+	for _, opt := range opts {
+		opt(service)
+	}
+	return service, nil
 }
 
 // ProvisionStation provisions masterdata and syncs TB entities.
@@ -90,6 +153,12 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 	if err := validateProvision(req); err != nil {
 		return nil, err
 	}
+	if req.ApplyAlarmTemplates && s.alarmTemplates == nil {
+		return nil, errors.New("provisioning: alarm templates not configured")
+	}
+	if len(req.AlarmThresholdOverrides) > 0 && !req.ApplyAlarmTemplates {
+		return nil, errors.New("provisioning: alarm_threshold_overrides requires apply_alarm_templates")
+	}
 
 	stationID := req.Station.ID
 	if stationID == "" {
@@ -150,13 +219,14 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 
 	for _, mapping := range req.PointMappings {
 		item := &masterdata.PointMapping{
-			ID:        mapping.ID,
-			StationID: stationID,
-			DeviceID:  mapping.DeviceID,
-			PointKey:  mapping.PointKey,
-			Semantic:  mapping.Semantic,
-			Unit:      mapping.Unit,
-			Factor:    mapping.Factor,
+			ID:          mapping.ID,
+			StationID:   stationID,
+			DeviceID:    mapping.DeviceID,
+			PointKey:    mapping.PointKey,
+			Semantic:    mapping.Semantic,
+			Unit:        mapping.Unit,
+			Factor:      mapping.Factor,
+			Aggregation: mapping.Aggregation,
 		}
 		if err := mappingRepo.Save(ctx, item); err != nil {
 			_ = tx.Rollback()
@@ -168,6 +238,24 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 		return nil, err
 	}
 
+	created := &createdEntities{}
+	result, err := s.syncTB(ctx, req, stationID, created)
+	if err != nil {
+		return nil, s.compensate(ctx, stationID, created, err)
+	}
+	if req.ApplyAlarmTemplates {
+		rules, err := s.alarmTemplates.ApplyTemplates(ctx, req.Station.TenantID, stationID, req.Station.Type, req.AlarmThresholdOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("provisioning: apply alarm templates: %w", err)
+		}
+		for _, rule := range rules {
+			result.AlarmRules = append(result.AlarmRules, rule.ID)
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) syncTB(ctx context.Context, req ProvisionRequest, stationID string, created *createdEntities) (*ProvisionResponse, error) {
 	tbTenant, err := s.tb.EnsureTenant(ctx, req.Station.TenantID)
 	if err != nil {
 		return nil, err
@@ -177,6 +265,14 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	if asset.Created {
+		created.AssetID = asset.ID
+	}
+	// Recorded before the devices, so a partial run leaves the station
+	// pointing at its asset; compensation clears it with the asset.
+	if err := updateStationTBMapping(ctx, s.db, stationID, asset.ID, tbTenant.ID); err != nil {
+		return nil, err
+	}
 
 	attrs := map[string]any{
 		"station_id":   stationID,
@@ -202,6 +298,9 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 		if err != nil {
 			return nil, err
 		}
+		if device.Created {
+			created.Devices = append(created.Devices, createdDevice{DeviceID: input.ID, TBDeviceID: device.ID})
+		}
 		if err := s.tb.SetAttributes(ctx, "DEVICE", device.ID, map[string]any{
 			"device_id":  input.ID,
 			"station_id": stationID,
@@ -225,10 +324,6 @@ func (s *Service) ProvisionStation(ctx context.Context, req ProvisionRequest) (*
 		})
 	}
 
-	if err := updateStationTBMapping(ctx, s.db, stationID, asset.ID, tbTenant.ID); err != nil {
-		return nil, err
-	}
-
 	return result, nil
 }
 
@@ -249,6 +344,11 @@ func validateProvision(req ProvisionRequest) error {
 		if mapping.PointKey == "" || mapping.Semantic == "" || mapping.Unit == "" {
 			return errors.New("provisioning: invalid point mapping")
 		}
+		switch mapping.Aggregation {
+		case "", masterdata.AggregationSum, masterdata.AggregationLast, masterdata.AggregationMax, masterdata.AggregationAvg:
+		default:
+			return errors.New("provisioning: point mapping aggregation must be sum, last, max or avg")
+		}
 		if mapping.Factor == 0 {
 			// allow default 1 in caller
 			continue
@@ -265,6 +365,16 @@ WHERE id = $4`, assetID, tenantID, time.Now().UTC(), stationID)
 	return err
 }
 
+// clearStationTBMapping unsets the station's TB mapping if it still points at
+// assetID.
+func clearStationTBMapping(ctx context.Context, db *sql.DB, stationID, assetID string) error {
+	_, err := db.ExecContext(ctx, `
+UPDATE stations
+SET tb_asset_id = NULL, tb_tenant_id = NULL, updated_at = $3
+WHERE id = $1 AND tb_asset_id = $2`, stationID, assetID, time.Now().UTC())
+	return err
+}
+
 func updateDeviceTBMapping(ctx context.Context, db *sql.DB, deviceID, entityID string) error {
 	_, err := db.ExecContext(ctx, `
 UPDATE devices
@@ -0,0 +1,93 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	provisioning "microgrid-cloud/internal/provisioning/application"
+	provisioninghttp "microgrid-cloud/internal/provisioning/interfaces/http"
+	"microgrid-cloud/internal/tbadapter"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestBulkProvisioning_CSVReportsPerRowOutcome(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyProvisioningMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings")
+	_, _ = db.ExecContext(ctx, "DELETE FROM devices")
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations")
+
+	fake := newFakeTBServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := tbadapter.NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("tb client: %v", err)
+	}
+	service, err := provisioning.NewService(db, client, provisioning.WithBulkConcurrency(2))
+	if err != nil {
+		t.Fatalf("provisioning service: %v", err)
+	}
+	handler, err := provisioninghttp.NewBulkProvisioningHandler(service, nil)
+	if err != nil {
+		t.Fatalf("bulk handler: %v", err)
+	}
+
+	// Second row has no timezone and must fail validation without affecting the first.
+	body := strings.Join([]string{
+		"station_id,tenant_id,name,timezone,type,region,devices,point_mappings",
+		"station-bulk-001,tenant-bulk,Bulk A,UTC,microgrid,lab,inv-a:inverter,charge_power_kw:charge_power_kw:kW:1",
+		"station-bulk-002,tenant-bulk,Bulk B,,microgrid,lab,,charge_power_kw:charge_power_kw:kW",
+	}, "\n")
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/provisioning/stations/bulk", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/csv")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	var resp provisioninghttp.BulkProvisionResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Total != 2 || resp.Succeeded != 1 || resp.Failed != 1 {
+		t.Fatalf("summary mismatch: %+v", resp)
+	}
+	if !resp.Results[0].OK || resp.Results[0].StationID != "station-bulk-001" || resp.Results[0].TB == nil {
+		t.Fatalf("row 1 expected success, got %+v", resp.Results[0])
+	}
+	if resp.Results[1].OK || resp.Results[1].Error == "" || resp.Results[1].Row != 2 {
+		t.Fatalf("row 2 expected failure, got %+v", resp.Results[1])
+	}
+
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM stations WHERE id IN ('station-bulk-001','station-bulk-002')").Scan(&count); err != nil {
+		t.Fatalf("count stations: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 provisioned station, got %d", count)
+	}
+}
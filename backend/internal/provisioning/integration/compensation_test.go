@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	provisioning "microgrid-cloud/internal/provisioning/application"
+	"microgrid-cloud/internal/tbadapter"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestProvisioning_CompensatesAfterDeviceCreationFailure(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyProvisioningMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings")
+	_, _ = db.ExecContext(ctx, "DELETE FROM devices")
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations")
+
+	req := provisioning.ProvisionRequest{
+		Station: provisioning.StationInput{
+			TenantID: "tenant-compensate",
+			Name:     "station-compensate-001",
+			Timezone: "UTC",
+			Type:     "microgrid",
+		},
+		Devices: []provisioning.DeviceInput{
+			{Name: "device-a", DeviceType: "inverter"},
+		},
+		PointMappings: []provisioning.PointMappingInput{
+			{PointKey: "charge_power_kw", Semantic: "charge_power_kw", Unit: "kW", Factor: 1},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		compensation bool
+		wantAssets   int
+		wantDevices  int
+	}{
+		{name: "compensation enabled", compensation: true, wantAssets: 0, wantDevices: 0},
+		{name: "compensation disabled", compensation: false, wantAssets: 1, wantDevices: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fake := &failingRelationTB{fakeTBServer: newFakeTBServer()}
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client, err := tbadapter.NewClient(server.URL, "token")
+			if err != nil {
+				t.Fatalf("tb client: %v", err)
+			}
+			service, err := provisioning.NewService(db, client, provisioning.WithCompensation(tc.compensation))
+			if err != nil {
+				t.Fatalf("provisioning service: %v", err)
+			}
+
+			_, err = service.ProvisionStation(ctx, req)
+			var partial *provisioning.PartialProvisionError
+			if !errors.As(err, &partial) {
+				t.Fatalf("expected partial provision error, got %v", err)
+			}
+			if partial.Compensated != tc.compensation {
+				t.Fatalf("compensated mismatch: got %v", partial.Compensated)
+			}
+			if !tc.compensation && (partial.AssetID == "" || len(partial.TBDeviceIDs) != 1) {
+				t.Fatalf("expected partial state to be recorded, got %+v", partial)
+			}
+			if fake.assetCount() != tc.wantAssets || fake.deviceCount() != tc.wantDevices {
+				t.Fatalf("tb entities mismatch: assets=%d devices=%d", fake.assetCount(), fake.deviceCount())
+			}
+			var assetID sql.NullString
+			if err := db.QueryRowContext(ctx, "SELECT tb_asset_id FROM stations WHERE id = $1", partial.StationID).Scan(&assetID); err != nil {
+				t.Fatalf("load station mapping: %v", err)
+			}
+			if tc.compensation && assetID.Valid {
+				t.Fatalf("station still mapped to removed asset %s", assetID.String)
+			}
+			if !tc.compensation && assetID.String != partial.AssetID {
+				t.Fatalf("station asset = %q, want the leftover asset %s", assetID.String, partial.AssetID)
+			}
+		})
+	}
+}
+
+// failingRelationTB fails every relation call, so provisioning stops after
+// the asset and devices exist in TB, and serves the deletes compensation
+// issues.
+type failingRelationTB struct {
+	*fakeTBServer
+}
+
+func (f *failingRelationTB) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/api/relation":
+		_, _ = io.Copy(io.Discard, r.Body)
+		http.Error(w, "relation failed", http.StatusInternalServerError)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/asset/"):
+		f.remove(w, r, f.assets, "ASSET", strings.TrimPrefix(r.URL.Path, "/api/asset/"))
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/device/"):
+		f.remove(w, r, f.devices, "DEVICE", strings.TrimPrefix(r.URL.Path, "/api/device/"))
+	default:
+		f.fakeTBServer.ServeHTTP(w, r)
+	}
+}
+
+func (f *failingRelationTB) remove(w http.ResponseWriter, r *http.Request, entities map[string]string, entityType, id string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := entities[id]; !ok {
+		http.NotFound(w, r)
+		return
+	}
+	delete(entities, id)
+	delete(f.attrs, entityType+":"+id)
+	w.WriteHeader(http.StatusOK)
+}
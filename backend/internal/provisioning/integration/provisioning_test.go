@@ -107,6 +107,37 @@ func TestProvisioning_IdempotentTBMapping(t *testing.T) {
 	}
 }
 
+func TestFakeTBServer_ServesClientRequests(t *testing.T) {
+	fake := newFakeTBServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := tbadapter.NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("tb client: %v", err)
+	}
+	ctx := context.Background()
+	tenant, err := client.EnsureTenant(ctx, "tenant-fake")
+	if err != nil {
+		t.Fatalf("ensure tenant: %v", err)
+	}
+	asset, err := client.EnsureAsset(ctx, tenant.ID, "station-fake", "Station Fake", "microgrid")
+	if err != nil || !asset.Created {
+		t.Fatalf("ensure asset = %+v, %v", asset, err)
+	}
+	if err := client.SetAttributes(ctx, "ASSET", asset.ID, map[string]any{"station_id": "station-fake"}); err != nil {
+		t.Fatalf("set attributes: %v", err)
+	}
+	attrs, err := client.GetAttributes(ctx, "ASSET", asset.ID)
+	if err != nil || attrs["station_id"] != "station-fake" {
+		t.Fatalf("get attributes = %v, %v", attrs, err)
+	}
+	again, err := client.EnsureAsset(ctx, tenant.ID, "station-fake", "Station Fake", "microgrid")
+	if err != nil || again.Created || again.ID != asset.ID {
+		t.Fatalf("second ensure asset = %+v, %v; want the existing %s", again, err, asset.ID)
+	}
+}
+
 func doProvision(t *testing.T, handler http.Handler, req provisioning.ProvisionRequest) provisioning.ProvisionResponse {
 	t.Helper()
 	payload, err := json.Marshal(req)
@@ -132,6 +163,7 @@ func applyProvisioningMigrations(db *sql.DB) error {
 		filepath.Join(root, "migrations", "001_init.sql"),
 		filepath.Join(root, "migrations", "003_masterdata.sql"),
 		filepath.Join(root, "migrations", "006_provisioning.sql"),
+		filepath.Join(root, "migrations", "032_point_mapping_aggregation.sql"),
 	}
 	for _, path := range files {
 		content, err := os.ReadFile(path)
@@ -163,6 +195,23 @@ type fakeTBServer struct {
 	counter  int
 }
 
+// entitiesQuery is the part of the client's entity query the fake matches on.
+type entitiesQuery struct {
+	EntityFilter struct {
+		EntityType string `json:"entityType"`
+	} `json:"entityFilter"`
+	KeyFilters []struct {
+		Key struct {
+			Key string `json:"key"`
+		} `json:"key"`
+		Predicate struct {
+			Value struct {
+				DefaultValue string `json:"defaultValue"`
+			} `json:"value"`
+		} `json:"predicate"`
+	} `json:"keyFilters"`
+}
+
 func newFakeTBServer() *fakeTBServer {
 	return &fakeTBServer{
 		tenants: make(map[string]string),
@@ -195,12 +244,13 @@ func (f *fakeTBServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]any{"id": map[string]any{"id": id}, "title": name})
 		return
 	case r.Method == http.MethodPost && r.URL.Path == "/api/entitiesQuery/find":
-		var payload map[string]any
-		_ = json.NewDecoder(r.Body).Decode(&payload)
-		entityType := payload["entityType"].(string)
-		key := payload["key"].(string)
-		value := payload["value"].(string)
-		id := f.findByAttr(entityType, key, value)
+		var payload entitiesQuery
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || len(payload.KeyFilters) == 0 {
+			http.Error(w, "bad query", http.StatusBadRequest)
+			return
+		}
+		filter := payload.KeyFilters[0]
+		id := f.findByAttr(payload.EntityFilter.EntityType, filter.Key.Key, filter.Predicate.Value.DefaultValue)
 		resp := map[string]any{"data": []any{}}
 		if id != "" {
 			resp["data"] = []any{map[string]any{"entityId": map[string]any{"id": id}}}
@@ -224,19 +274,37 @@ func (f *fakeTBServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		_ = json.NewEncoder(w).Encode(map[string]any{"id": map[string]any{"id": id}, "name": name})
 		return
 	case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/api/plugins/telemetry/"):
+		// /api/plugins/telemetry/{type}/{id}/attributes/{scope}
 		parts := strings.Split(r.URL.Path, "/")
-		if len(parts) < 6 {
+		if len(parts) < 8 {
 			http.Error(w, "bad path", http.StatusBadRequest)
 			return
 		}
-		entityType := parts[3]
-		entityID := parts[4]
+		entityType := parts[4]
+		entityID := parts[5]
 		var payload map[string]any
 		_ = json.NewDecoder(r.Body).Decode(&payload)
 		key := entityType + ":" + entityID
 		f.attrs[key] = payload
 		w.WriteHeader(http.StatusOK)
 		return
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/api/plugins/telemetry/"):
+		parts := strings.Split(r.URL.Path, "/")
+		if len(parts) < 7 {
+			http.Error(w, "bad path", http.StatusBadRequest)
+			return
+		}
+		attrs, ok := f.attrs[parts[4]+":"+parts[5]]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		items := make([]map[string]any, 0, len(attrs))
+		for key, value := range attrs {
+			items = append(items, map[string]any{"key": key, "value": value})
+		}
+		_ = json.NewEncoder(w).Encode(items)
+		return
 	case r.Method == http.MethodPost && r.URL.Path == "/api/relation":
 		io.Copy(io.Discard, r.Body)
 		w.WriteHeader(http.StatusOK)
@@ -246,6 +314,12 @@ func (f *fakeTBServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (f *fakeTBServer) setAttrs(entityType, entityID string, attrs map[string]any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attrs[entityType+":"+entityID] = attrs
+}
+
 func (f *fakeTBServer) nextID(prefix string) string {
 	f.counter++
 	return prefix + "-" + fmt.Sprintf("%d", f.counter)
@@ -0,0 +1,134 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	provisioning "microgrid-cloud/internal/provisioning/application"
+	provisioninghttp "microgrid-cloud/internal/provisioning/interfaces/http"
+	"microgrid-cloud/internal/tbadapter"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestStationReadiness_ReportsPerCheckStatus(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyProvisioningMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-ready"
+	stationID := "station-ready-001"
+	_, _ = db.ExecContext(ctx, "DELETE FROM telemetry_points WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM point_mappings WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM devices WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM stations WHERE id = $1", stationID)
+
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO stations (id, tenant_id, name, timezone, station_type, region, tb_asset_id, tb_tenant_id)
+VALUES ($1, $2, 'ready', 'UTC', 'microgrid', 'lab', 'asset-ready', 'tb-tenant')`, stationID, tenantID); err != nil {
+		t.Fatalf("insert station: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO devices (id, station_id, tb_entity_id, device_type, name)
+VALUES ('device-ready-1', $1, 'tb-device-ready', 'inverter', 'inv-1')`, stationID); err != nil {
+		t.Fatalf("insert device: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO point_mappings (id, station_id, point_key, semantic, unit, factor)
+VALUES ('mapping-ready-1', $1, 'charge_power_kw', 'charge_power_kw', 'kW', 1)`, stationID); err != nil {
+		t.Fatalf("insert mapping: %v", err)
+	}
+
+	fake := newFakeTBServer()
+	fake.setAttrs("ASSET", "asset-ready", map[string]any{"station_id": stationID})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := tbadapter.NewClient(server.URL, "token")
+	if err != nil {
+		t.Fatalf("tb client: %v", err)
+	}
+	service, err := provisioning.NewService(db, client)
+	if err != nil {
+		t.Fatalf("provisioning service: %v", err)
+	}
+	handler, err := provisioninghttp.NewStationReadinessHandler(service, nil)
+	if err != nil {
+		t.Fatalf("readiness handler: %v", err)
+	}
+
+	report := getReadiness(t, handler, stationID)
+	if report.Ready {
+		t.Fatalf("expected station not ready before device attrs and telemetry")
+	}
+	assertCheck(t, report, provisioning.CheckStation, true)
+	assertCheck(t, report, provisioning.CheckPointMappings, true)
+	assertCheck(t, report, provisioning.CheckTBAsset, true)
+	assertCheck(t, report, provisioning.CheckTBDevices, false)
+	assertCheck(t, report, provisioning.CheckTelemetry, false)
+
+	fake.setAttrs("DEVICE", "tb-device-ready", map[string]any{"device_id": "device-ready-1"})
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO telemetry_points (tenant_id, station_id, device_id, point_key, ts, value_numeric, quality)
+VALUES ($1, $2, 'device-ready-1', 'charge_power_kw', $3, 1, 'good')`, tenantID, stationID, time.Now().UTC().Add(-time.Minute)); err != nil {
+		t.Fatalf("insert telemetry: %v", err)
+	}
+
+	report = getReadiness(t, handler, stationID)
+	if !report.Ready {
+		t.Fatalf("expected station ready, got %+v", report.Checks)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/provisioning/stations/station-missing/readiness", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown station, got %d", w.Code)
+	}
+}
+
+func getReadiness(t *testing.T, handler http.Handler, stationID string) provisioning.ReadinessReport {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/provisioning/stations/"+stationID+"/readiness", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+	var report provisioning.ReadinessReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	return report
+}
+
+func assertCheck(t *testing.T, report provisioning.ReadinessReport, name string, passed bool) {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			if check.Passed != passed {
+				t.Fatalf("check %s: expected passed=%v, got %v (%s)", name, passed, check.Passed, check.Detail)
+			}
+			return
+		}
+	}
+	t.Fatalf("check %s missing", name)
+}
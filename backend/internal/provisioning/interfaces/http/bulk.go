@@ -0,0 +1,275 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/audit"
+	"microgrid-cloud/internal/auth"
+	provisioning "microgrid-cloud/internal/provisioning/application"
+)
+
+const (
+	maxBulkRows = 500
+	// maxBulkBodyBytes caps the upload before it is parsed; 500 rows with
+	// generous device and mapping lists fit well within it.
+	maxBulkBodyBytes = 4 << 20
+)
+
+var errTooManyRows = errors.New("too many stations: limit is " + strconv.Itoa(maxBulkRows))
+
+// BulkProvisioningHandler provisions several stations from a CSV or JSON array upload.
+type BulkProvisioningHandler struct {
+	service     *provisioning.Service
+	auditLogger audit.Logger
+}
+
+// BulkProvisionResponse summarizes a bulk provisioning run.
+type BulkProvisionResponse struct {
+	Total     int                                `json:"total"`
+	Succeeded int                                `json:"succeeded"`
+	Failed    int                                `json:"failed"`
+	Results   []provisioning.BulkProvisionResult `json:"results"`
+}
+
+// NewBulkProvisioningHandler constructs a bulk handler.
+func NewBulkProvisioningHandler(service *provisioning.Service, auditLogger audit.Logger) (*BulkProvisioningHandler, error) {
+	if service == nil {
+		return nil, errors.New("bulk provisioning handler: nil service")
+	}
+	return &BulkProvisioningHandler{service: service, auditLogger: auditLogger}, nil
+}
+
+// ServeHTTP handles POST /api/v1/provisioning/stations/bulk.
+func (h *BulkProvisioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer r.Body.Close()
+	body := http.MaxBytesReader(w, r.Body, maxBulkBodyBytes)
+
+	var rows []bulkRow
+	var err error
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		rows, err = parseBulkCSV(body)
+	} else {
+		rows, err = parseBulkJSON(body)
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		apierror.Write(w, http.StatusRequestEntityTooLarge, apierror.CodeTooLarge, "request body too large: limit is "+strconv.Itoa(maxBulkBodyBytes)+" bytes")
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if len(rows) == 0 {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "no stations in request")
+		return
+	}
+	if len(rows) > maxBulkRows {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, errTooManyRows.Error())
+		return
+	}
+
+	tenantID := auth.TenantIDFromContext(r.Context())
+	results := make([]provisioning.BulkProvisionResult, len(rows))
+	pending := make([]provisioning.ProvisionRequest, 0, len(rows))
+	pendingIdx := make([]int, 0, len(rows))
+	for i, row := range rows {
+		if row.err == nil && tenantID != "" {
+			if row.req.Station.TenantID != "" && row.req.Station.TenantID != tenantID {
+				row.err = errors.New("forbidden")
+			} else {
+				row.req.Station.TenantID = tenantID
+			}
+		}
+		if row.err != nil {
+			results[i] = provisioning.BulkProvisionResult{Row: i + 1, StationID: row.req.Station.ID, Error: row.err.Error()}
+			continue
+		}
+		pending = append(pending, row.req)
+		pendingIdx = append(pendingIdx, i)
+	}
+
+	for j, result := range h.service.ProvisionBulk(r.Context(), pending) {
+		i := pendingIdx[j]
+		result.Row = i + 1
+		results[i] = result
+		if result.OK {
+			metadata, _ := json.Marshal(map[string]any{"bulk": true, "row": result.Row})
+			logStationAudit(r, h.auditLogger, pending[j].Station.TenantID, result.StationID, metadata)
+		}
+	}
+
+	resp := BulkProvisionResponse{Total: len(results), Results: results}
+	for _, result := range results {
+		if result.OK {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+type bulkRow struct {
+	req provisioning.ProvisionRequest
+	err error
+}
+
+func parseBulkJSON(body io.Reader) ([]bulkRow, error) {
+	var reqs []provisioning.ProvisionRequest
+	if err := json.NewDecoder(body).Decode(&reqs); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		return nil, errors.New("invalid json: expected an array of station definitions")
+	}
+	rows := make([]bulkRow, 0, len(reqs))
+	for _, req := range reqs {
+		rows = append(rows, bulkRow{req: req})
+	}
+	return rows, nil
+}
+
+// parseBulkCSV reads a header row followed by one station per row. Columns:
+// station_id, tenant_id, name, timezone, type, region, devices, point_mappings.
+// devices is "name:device_type[:credentials[:device_id]]" and point_mappings
+// is "point_key:semantic:unit[:factor[:aggregation[:device_id]]]", each list
+// separated by ";". An empty factor keeps the default of 1; a mapping's
+// device_id must name a device of the same row. Reading stops past
+// maxBulkRows.
+func parseBulkCSV(body io.Reader) ([]bulkRow, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("invalid csv: missing header")
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "timezone", "point_mappings"} {
+		if _, ok := columns[required]; !ok {
+			return nil, errors.New("invalid csv: missing column " + required)
+		}
+	}
+
+	var rows []bulkRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		if len(rows) == maxBulkRows {
+			return nil, errTooManyRows
+		}
+		field := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+		row := bulkRow{req: provisioning.ProvisionRequest{
+			Station: provisioning.StationInput{
+				ID:       field("station_id"),
+				TenantID: field("tenant_id"),
+				Name:     field("name"),
+				Timezone: field("timezone"),
+				Type:     field("type"),
+				Region:   field("region"),
+			},
+		}}
+		row.req.Devices, row.err = parseDeviceList(field("devices"))
+		if row.err == nil {
+			row.req.PointMappings, row.err = parseMappingList(field("point_mappings"), row.req.Devices)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func parseDeviceList(value string) ([]provisioning.DeviceInput, error) {
+	var devices []provisioning.DeviceInput
+	for _, item := range splitList(value) {
+		parts := strings.Split(item, ":")
+		if len(parts) < 2 || len(parts) > 4 || parts[0] == "" {
+			return nil, errors.New("invalid device entry: " + item)
+		}
+		device := provisioning.DeviceInput{Name: parts[0], DeviceType: parts[1]}
+		if len(parts) >= 3 {
+			device.Credentials = parts[2]
+		}
+		if len(parts) == 4 {
+			device.ID = parts[3]
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+func parseMappingList(value string, devices []provisioning.DeviceInput) ([]provisioning.PointMappingInput, error) {
+	deviceIDs := make(map[string]struct{}, len(devices))
+	for _, device := range devices {
+		if device.ID != "" {
+			deviceIDs[device.ID] = struct{}{}
+		}
+	}
+	var mappings []provisioning.PointMappingInput
+	for _, item := range splitList(value) {
+		parts := strings.Split(item, ":")
+		if len(parts) < 3 || len(parts) > 6 {
+			return nil, errors.New("invalid point mapping entry: " + item)
+		}
+		mapping := provisioning.PointMappingInput{PointKey: parts[0], Semantic: parts[1], Unit: parts[2]}
+		if len(parts) >= 4 && parts[3] != "" {
+			factor, err := strconv.ParseFloat(parts[3], 64)
+			if err != nil {
+				return nil, errors.New("invalid point mapping factor: " + item)
+			}
+			mapping.Factor = factor
+		}
+		if len(parts) >= 5 {
+			mapping.Aggregation = parts[4]
+		}
+		if len(parts) == 6 && parts[5] != "" {
+			if _, ok := deviceIDs[parts[5]]; !ok {
+				return nil, errors.New("point mapping refers to a device not in the row: " + item)
+			}
+			mapping.DeviceID = parts[5]
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ";") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"microgrid-cloud/internal/api/apierror"
+)
+
+func TestParseBulkCSV_DeviceColumns(t *testing.T) {
+	body := strings.Join([]string{
+		"station_id,tenant_id,name,timezone,type,region,devices,point_mappings",
+		"station-c,tenant-demo,Station C,UTC,microgrid,lab,pcs-c:pcs::dev-pcs-c;bms-c:bms::dev-bms-c,charge_power_kw:charge_power_kw:kW::sum:dev-pcs-c;soc:soc:%::last:dev-bms-c",
+		"station-d,tenant-demo,Station D,UTC,microgrid,lab,pcs-d:pcs,soc:soc:%::last:dev-missing",
+	}, "\n")
+	rows, err := parseBulkCSV(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(rows))
+	}
+
+	first := rows[0]
+	if first.err != nil {
+		t.Fatalf("row 1: %v", first.err)
+	}
+	if len(first.req.Devices) != 2 || first.req.Devices[0].ID != "dev-pcs-c" || first.req.Devices[1].ID != "dev-bms-c" {
+		t.Fatalf("devices = %+v", first.req.Devices)
+	}
+	mappings := first.req.PointMappings
+	if len(mappings) != 2 || mappings[0].DeviceID != "dev-pcs-c" || mappings[1].DeviceID != "dev-bms-c" || mappings[1].Aggregation != "last" {
+		t.Fatalf("mappings = %+v", mappings)
+	}
+
+	if rows[1].err == nil {
+		t.Fatalf("row 2: expected an error for a mapping to an unknown device")
+	}
+}
+
+func TestParseBulkCSV_StopsAtRowLimit(t *testing.T) {
+	lines := []string{"station_id,tenant_id,name,timezone,type,region,devices,point_mappings"}
+	for i := 0; i <= maxBulkRows; i++ {
+		lines = append(lines, "station-x,tenant-demo,X,UTC,microgrid,lab,,charge_power_kw:charge_power_kw:kW")
+	}
+	if _, err := parseBulkCSV(strings.NewReader(strings.Join(lines, "\n"))); !errors.Is(err, errTooManyRows) {
+		t.Fatalf("err = %v, want errTooManyRows", err)
+	}
+}
+
+func TestBulkProvisioningHandler_RejectsWithJSONErrors(t *testing.T) {
+	handler := &BulkProvisioningHandler{}
+	cases := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+	}{
+		{"empty", "[]", http.StatusBadRequest, apierror.CodeInvalidArgument},
+		{"too large", "[" + strings.Repeat(" ", maxBulkBodyBytes) + "]", http.StatusRequestEntityTooLarge, apierror.CodeTooLarge},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/provisioning/stations/bulk", strings.NewReader(tc.body)))
+			var body apierror.Body
+			if resp.Code != tc.status || json.Unmarshal(resp.Body.Bytes(), &body) != nil || body.Error.Code != tc.code {
+				t.Fatalf("status = %d, body = %q; want %d with code %s", resp.Code, resp.Body.String(), tc.status, tc.code)
+			}
+		})
+	}
+}
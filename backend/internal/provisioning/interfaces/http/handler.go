@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 
+	"microgrid-cloud/internal/api/apierror"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
 	provisioning "microgrid-cloud/internal/provisioning/application"
@@ -33,19 +34,19 @@ func (h *StationProvisioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "read body error", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "read body error")
 		return
 	}
 	defer r.Body.Close()
 
 	var req provisioning.ProvisionRequest
 	if err := json.Unmarshal(body, &req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && req.Station.TenantID != "" && req.Station.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if tenantID != "" {
@@ -54,7 +55,7 @@ func (h *StationProvisioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 
 	resp, err := h.service.ProvisionStation(r.Context(), req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 
@@ -64,10 +65,14 @@ func (h *StationProvisioningHandler) ServeHTTP(w http.ResponseWriter, r *http.Re
 }
 
 func (h *StationProvisioningHandler) logAudit(r *http.Request, tenantID, stationID string) {
-	if h.auditLogger == nil || tenantID == "" {
+	logStationAudit(r, h.auditLogger, tenantID, stationID, nil)
+}
+
+func logStationAudit(r *http.Request, auditLogger audit.Logger, tenantID, stationID string, metadata json.RawMessage) {
+	if auditLogger == nil || tenantID == "" {
 		return
 	}
-	_ = h.auditLogger.Log(r.Context(), audit.Entry{
+	_ = auditLogger.Log(r.Context(), audit.Entry{
 		TenantID:     tenantID,
 		Actor:        auth.SubjectFromContext(r.Context()),
 		Role:         string(auth.RoleFromContext(r.Context())),
@@ -75,7 +80,7 @@ func (h *StationProvisioningHandler) logAudit(r *http.Request, tenantID, station
 		ResourceType: "station",
 		ResourceID:   stationID,
 		StationID:    stationID,
-		Metadata:     nil,
+		Metadata:     metadata,
 		IP:           audit.ClientIP(r),
 		UserAgent:    r.UserAgent(),
 	})
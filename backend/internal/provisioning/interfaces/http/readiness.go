@@ -0,0 +1,81 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/auth"
+	provisioning "microgrid-cloud/internal/provisioning/application"
+)
+
+// StationReadinessHandler reports commissioning readiness for a station.
+type StationReadinessHandler struct {
+	service        *provisioning.Service
+	stationChecker auth.StationTenantChecker
+}
+
+// NewStationReadinessHandler constructs a readiness handler.
+func NewStationReadinessHandler(service *provisioning.Service, stationChecker auth.StationTenantChecker) (*StationReadinessHandler, error) {
+	if service == nil {
+		return nil, errors.New("readiness handler: nil service")
+	}
+	return &StationReadinessHandler{service: service, stationChecker: stationChecker}, nil
+}
+
+// ServeHTTP handles GET /api/v1/provisioning/stations/{id}/readiness.
+func (h *StationReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/api/v1/provisioning/stations/")
+	stationID, ok := strings.CutSuffix(path, "/readiness")
+	if !ok || stationID == "" || strings.Contains(stationID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	tenantID := auth.TenantIDFromContext(r.Context())
+	if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
+		respondTenantError(w, err)
+		return
+	}
+
+	report, err := h.service.CheckReadiness(r.Context(), stationID)
+	if err != nil {
+		if errors.Is(err, provisioning.ErrStationNotFound) {
+			apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
+			return
+		}
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+func ensureStationTenant(r *http.Request, checker auth.StationTenantChecker, tenantID, stationID string) error {
+	if checker == nil || tenantID == "" || stationID == "" {
+		return nil
+	}
+	return checker.EnsureStationTenant(r.Context(), tenantID, stationID)
+}
+
+func respondTenantError(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	if errors.Is(err, auth.ErrTenantMismatch) {
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
+		return
+	}
+	if errors.Is(err, auth.ErrNotFound) {
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
+		return
+	}
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
+}
@@ -0,0 +1,139 @@
+// Package reconcile compares hourly statistics against daily settlements. It is
+// shared by the shadowrun runner and the standalone reconcile tool so both
+// produce the same diff_summary.json.
+package reconcile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SummaryFile is the file name of the machine-readable diff summary.
+const SummaryFile = "diff_summary.json"
+
+// Hour is one hourly statistic priced under the station tariff.
+type Hour struct {
+	Start     time.Time
+	EnergyKWh float64
+	Amount    float64
+}
+
+// Settlement is one daily settlement row.
+type Settlement struct {
+	DayStart  time.Time
+	EnergyKWh float64
+	Amount    float64
+}
+
+// DayDiff compares the summed hours of one UTC day with its settlement.
+type DayDiff struct {
+	DayStart     time.Time `json:"day_start"`
+	EnergyHour   float64   `json:"energy_hour"`
+	EnergySettle float64   `json:"energy_settlement"`
+	EnergyDiff   float64   `json:"energy_diff"`
+	AmountHour   float64   `json:"amount_hour"`
+	AmountSettle float64   `json:"amount_settlement"`
+	AmountDiff   float64   `json:"amount_diff"`
+	MissingHours int       `json:"missing_hours"`
+}
+
+// Summary is the content of diff_summary.json.
+type Summary struct {
+	Month             string    `json:"month"`
+	StationID         string    `json:"station_id"`
+	DiffEnergyMax     float64   `json:"diff_energy_max"`
+	DiffAmountMax     float64   `json:"diff_amount_max"`
+	MissingHoursTotal int       `json:"missing_hours_total"`
+	LateDataCount     int       `json:"late_data_count"`
+	GeneratedAt       string    `json:"generated_at"`
+	DayDiffs          []DayDiff `json:"day_diffs"`
+}
+
+// BuildSummary diffs every day of [monthStart, monthEnd). When asOf falls
+// inside the month, days from asOf's day onwards are not diffed yet, so an
+// in-progress month does not report future hours as missing.
+func BuildSummary(stationID string, hours []Hour, settlements []Settlement, monthStart, monthEnd, asOf, generatedAt time.Time) Summary {
+	hourByDay := make(map[time.Time][]Hour)
+	for _, row := range hours {
+		day := dayOf(row.Start)
+		hourByDay[day] = append(hourByDay[day], row)
+	}
+	settlementByDay := make(map[time.Time]Settlement)
+	for _, row := range settlements {
+		settlementByDay[dayOf(row.DayStart)] = row
+	}
+
+	endDate := diffEnd(monthStart, monthEnd, asOf)
+
+	summary := Summary{
+		Month:       monthStart.Format("2006-01"),
+		StationID:   stationID,
+		GeneratedAt: generatedAt.UTC().Format(time.RFC3339),
+	}
+	for day := monthStart; day.Before(endDate); day = day.AddDate(0, 0, 1) {
+		dayHours := hourByDay[day]
+		settle := settlementByDay[day]
+		var energyHour, amountHour float64
+		for _, hr := range dayHours {
+			energyHour += hr.EnergyKWh
+			amountHour += hr.Amount
+		}
+		missing := 24 - len(dayHours)
+		if missing < 0 {
+			missing = 0
+		}
+		diff := DayDiff{
+			DayStart:     day,
+			EnergyHour:   energyHour,
+			EnergySettle: settle.EnergyKWh,
+			EnergyDiff:   energyHour - settle.EnergyKWh,
+			AmountHour:   amountHour,
+			AmountSettle: settle.Amount,
+			AmountDiff:   amountHour - settle.Amount,
+			MissingHours: missing,
+		}
+		summary.MissingHoursTotal += missing
+		if abs(diff.EnergyDiff) > summary.DiffEnergyMax {
+			summary.DiffEnergyMax = abs(diff.EnergyDiff)
+		}
+		if abs(diff.AmountDiff) > summary.DiffAmountMax {
+			summary.DiffAmountMax = abs(diff.AmountDiff)
+		}
+		summary.DayDiffs = append(summary.DayDiffs, diff)
+	}
+	return summary
+}
+
+// WriteSummaryJSON writes v as indented JSON to outDir/diff_summary.json.
+func WriteSummaryJSON(outDir string, v any) error {
+	file, err := os.Create(filepath.Join(outDir, SummaryFile))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// diffEnd is the exclusive end of the diffed days: monthEnd, or asOf's day
+// when asOf falls inside the month.
+func diffEnd(monthStart, monthEnd, asOf time.Time) time.Time {
+	if asOf.Before(monthEnd) && asOf.After(monthStart) {
+		return dayOf(asOf)
+	}
+	return monthEnd
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func abs(value float64) float64 {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
@@ -0,0 +1,44 @@
+package reconcile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildSummary_DiffsAndMissingHours(t *testing.T) {
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	var hours []Hour
+	for h := 0; h < 24; h++ {
+		hours = append(hours, Hour{Start: monthStart.Add(time.Duration(h) * time.Hour), EnergyKWh: 1, Amount: 2})
+	}
+	// Day 2 is missing four hours.
+	for h := 0; h < 20; h++ {
+		hours = append(hours, Hour{Start: monthStart.AddDate(0, 0, 1).Add(time.Duration(h) * time.Hour), EnergyKWh: 1, Amount: 2})
+	}
+	settlements := []Settlement{
+		{DayStart: monthStart, EnergyKWh: 24, Amount: 48},
+		{DayStart: monthStart.AddDate(0, 0, 1), EnergyKWh: 24, Amount: 45},
+	}
+
+	summary := BuildSummary("station-1", hours, settlements, monthStart, monthEnd, time.Time{}, monthEnd)
+	if len(summary.DayDiffs) != 28 {
+		t.Fatalf("expected 28 day diffs, got %d", len(summary.DayDiffs))
+	}
+	if summary.DiffEnergyMax != 4 || summary.DiffAmountMax != 5 {
+		t.Fatalf("maxima energy=%v amount=%v", summary.DiffEnergyMax, summary.DiffAmountMax)
+	}
+	if summary.MissingHoursTotal != 4+26*24 {
+		t.Fatalf("missing hours=%d", summary.MissingHoursTotal)
+	}
+	if summary.Month != "2026-02" || summary.StationID != "station-1" {
+		t.Fatalf("unexpected header %+v", summary)
+	}
+
+	asOf := monthStart.AddDate(0, 0, 2).Add(9 * time.Hour)
+	partial := BuildSummary("station-1", hours, settlements, monthStart, monthEnd, asOf, monthEnd)
+	if len(partial.DayDiffs) != 2 || partial.MissingHoursTotal != 4 {
+		t.Fatalf("as-of summary days=%d missing=%d", len(partial.DayDiffs), partial.MissingHoursTotal)
+	}
+}
@@ -0,0 +1,135 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HealMode controls whether missing hours are republished for recalculation.
+type HealMode string
+
+const (
+	// HealOff disables auto-heal.
+	HealOff HealMode = "off"
+	// HealDryRun lists the hours that would be republished without publishing.
+	HealDryRun HealMode = "dry-run"
+	// HealApply republishes the hours.
+	HealApply HealMode = "apply"
+)
+
+// ParseHealMode parses off, dry-run or apply; empty means off.
+func ParseHealMode(value string) (HealMode, error) {
+	switch mode := HealMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return HealOff, nil
+	case HealOff, HealDryRun, HealApply:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("auto-heal: invalid mode %q (want off, dry-run or apply)", value)
+	}
+}
+
+// TelemetryChecker reports whether raw telemetry exists for a station window.
+type TelemetryChecker interface {
+	HasTelemetry(ctx context.Context, stationID string, windowStart, windowEnd time.Time) (bool, error)
+}
+
+// WindowPublisher republishes a closed telemetry window as a recalculation.
+type WindowPublisher interface {
+	PublishWindowClosed(ctx context.Context, stationID string, windowStart, windowEnd time.Time) error
+}
+
+// HealResult reports what an auto-heal pass did.
+type HealResult struct {
+	Mode HealMode `json:"mode"`
+	// Hours are the missing hours that have telemetry; they were published in apply mode.
+	Hours []time.Time `json:"hours"`
+	// NoTelemetry counts missing hours skipped because no telemetry exists to recalculate from.
+	NoTelemetry int `json:"no_telemetry"`
+	Published   int `json:"published"`
+}
+
+// MissingHourStarts lists the hours of the diffed range (see BuildSummary)
+// that have no hourly statistic, never later than now.
+func MissingHourStarts(hours []Hour, monthStart, monthEnd, asOf, now time.Time) []time.Time {
+	present := make(map[time.Time]struct{}, len(hours))
+	for _, row := range hours {
+		present[row.Start.UTC().Truncate(time.Hour)] = struct{}{}
+	}
+	var missing []time.Time
+	for hour := monthStart; hour.Before(diffEnd(monthStart, monthEnd, asOf)); hour = hour.Add(time.Hour) {
+		if hour.Add(time.Hour).After(now) {
+			break
+		}
+		if _, ok := present[hour]; !ok {
+			missing = append(missing, hour)
+		}
+	}
+	return missing
+}
+
+// Heal republishes every missing hour that has telemetry. In dry-run mode it
+// only reports them. Publishing stops at the first error.
+func Heal(ctx context.Context, mode HealMode, stationID string, missing []time.Time, checker TelemetryChecker, publisher WindowPublisher) (HealResult, error) {
+	result := HealResult{Mode: mode}
+	if mode == HealOff || len(missing) == 0 {
+		return result, nil
+	}
+	if checker == nil {
+		return result, errors.New("auto-heal: nil telemetry checker")
+	}
+	if mode == HealApply && publisher == nil {
+		return result, errors.New("auto-heal: nil publisher")
+	}
+
+	for _, hour := range missing {
+		ok, err := checker.HasTelemetry(ctx, stationID, hour, hour.Add(time.Hour))
+		if err != nil {
+			return result, err
+		}
+		if !ok {
+			result.NoTelemetry++
+			continue
+		}
+		result.Hours = append(result.Hours, hour)
+	}
+	if mode != HealApply {
+		return result, nil
+	}
+	for _, hour := range result.Hours {
+		if err := publisher.PublishWindowClosed(ctx, stationID, hour, hour.Add(time.Hour)); err != nil {
+			return result, fmt.Errorf("auto-heal: publish %s: %w", hour.Format(time.RFC3339), err)
+		}
+		result.Published++
+	}
+	return result, nil
+}
+
+// SQLTelemetryChecker checks telemetry_points for raw measurements.
+type SQLTelemetryChecker struct {
+	db *sql.DB
+}
+
+// NewSQLTelemetryChecker constructs a checker.
+func NewSQLTelemetryChecker(db *sql.DB) *SQLTelemetryChecker {
+	return &SQLTelemetryChecker{db: db}
+}
+
+// HasTelemetry implements TelemetryChecker.
+func (c *SQLTelemetryChecker) HasTelemetry(ctx context.Context, stationID string, windowStart, windowEnd time.Time) (bool, error) {
+	if c == nil || c.db == nil {
+		return false, errors.New("auto-heal: nil db")
+	}
+	var exists bool
+	err := c.db.QueryRowContext(ctx, `
+SELECT EXISTS (
+	SELECT 1
+	FROM telemetry_points
+	WHERE station_id = $1 AND ts >= $2 AND ts < $3
+)`, stationID, windowStart.UTC(), windowEnd.UTC()).Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,88 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+type stubTelemetryChecker map[time.Time]bool
+
+func (s stubTelemetryChecker) HasTelemetry(_ context.Context, _ string, windowStart, _ time.Time) (bool, error) {
+	return s[windowStart], nil
+}
+
+type stubWindowPublisher struct {
+	windows []time.Time
+}
+
+func (p *stubWindowPublisher) PublishWindowClosed(_ context.Context, _ string, windowStart, _ time.Time) error {
+	p.windows = append(p.windows, windowStart)
+	return nil
+}
+
+func TestMissingHourStarts_RespectsAsOfAndNow(t *testing.T) {
+	monthStart := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+
+	var hours []Hour
+	for h := 0; h < 48; h++ {
+		if h == 5 || h == 30 {
+			continue
+		}
+		hours = append(hours, Hour{Start: monthStart.Add(time.Duration(h) * time.Hour)})
+	}
+
+	// As of day 3: only days 1 and 2 are diffed.
+	missing := MissingHourStarts(hours, monthStart, monthEnd, monthStart.AddDate(0, 0, 2), monthEnd)
+	if len(missing) != 2 || !missing[0].Equal(monthStart.Add(5*time.Hour)) || !missing[1].Equal(monthStart.Add(30*time.Hour)) {
+		t.Fatalf("missing = %v", missing)
+	}
+
+	// Hours that have not closed yet are never reported.
+	missing = MissingHourStarts(hours, monthStart, monthEnd, time.Time{}, monthStart.Add(10*time.Hour))
+	if len(missing) != 1 {
+		t.Fatalf("expected 1 closed missing hour, got %v", missing)
+	}
+}
+
+func TestHeal_DryRunPreviewsAndApplyPublishes(t *testing.T) {
+	withTelemetry := time.Date(2026, time.March, 1, 5, 0, 0, 0, time.UTC)
+	withoutTelemetry := withTelemetry.Add(time.Hour)
+	missing := []time.Time{withTelemetry, withoutTelemetry}
+	checker := stubTelemetryChecker{withTelemetry: true}
+
+	publisher := &stubWindowPublisher{}
+	preview, err := Heal(context.Background(), HealDryRun, "station-1", missing, checker, publisher)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if len(preview.Hours) != 1 || preview.NoTelemetry != 1 || preview.Published != 0 || len(publisher.windows) != 0 {
+		t.Fatalf("dry run must not publish: %+v published=%v", preview, publisher.windows)
+	}
+
+	applied, err := Heal(context.Background(), HealApply, "station-1", missing, checker, publisher)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if applied.Published != 1 || len(publisher.windows) != 1 || !publisher.windows[0].Equal(withTelemetry) {
+		t.Fatalf("apply result %+v published=%v", applied, publisher.windows)
+	}
+
+	off, err := Heal(context.Background(), HealOff, "station-1", missing, checker, nil)
+	if err != nil || len(off.Hours) != 0 {
+		t.Fatalf("off must do nothing: %+v %v", off, err)
+	}
+}
+
+func TestParseHealMode(t *testing.T) {
+	for value, want := range map[string]HealMode{"": HealOff, "off": HealOff, "Dry-Run": HealDryRun, "apply": HealApply} {
+		got, err := ParseHealMode(value)
+		if err != nil || got != want {
+			t.Fatalf("ParseHealMode(%q) = %q, %v", value, got, err)
+		}
+	}
+	if _, err := ParseHealMode("yes"); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
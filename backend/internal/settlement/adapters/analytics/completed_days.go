@@ -0,0 +1,41 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ListCompletedDays returns the starts of the completed DAY statistics of a
+// station within [start, end), oldest first.
+func (r *DayHourEnergyReader) ListCompletedDays(ctx context.Context, subjectID string, start, end time.Time) ([]time.Time, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("day hour energy reader: nil db")
+	}
+	if subjectID == "" {
+		return nil, errors.New("day hour energy reader: empty subject id")
+	}
+
+	query := fmt.Sprintf(`
+SELECT period_start
+FROM %s
+WHERE subject_id = $1 AND time_type = 'DAY' AND is_completed AND period_start >= $2 AND period_start < $3
+ORDER BY period_start ASC`, r.table)
+
+	rows, err := r.db.QueryContext(ctx, query, subjectID, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var periodStart time.Time
+		if err := rows.Scan(&periodStart); err != nil {
+			return nil, err
+		}
+		days = append(days, periodStart.UTC())
+	}
+	return days, rows.Err()
+}
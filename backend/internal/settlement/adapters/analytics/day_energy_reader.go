@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"microgrid-cloud/internal/energy"
 	settlementapp "microgrid-cloud/internal/settlement/application"
 )
 
@@ -17,6 +18,7 @@ type DayHourEnergyReader struct {
 	db            *sql.DB
 	table         string
 	expectedHours int
+	negative      energy.NegativePolicy
 }
 
 // NewDayHourEnergyReader constructs a reader.
@@ -49,6 +51,15 @@ func WithExpectedHours(expected int) ReaderOption {
 	}
 }
 
+// WithNegativeEnergyPolicy applies policy to the energy of each hour.
+func WithNegativeEnergyPolicy(policy energy.NegativePolicy) ReaderOption {
+	return func(reader *DayHourEnergyReader) {
+		if reader != nil {
+			reader.negative = policy
+		}
+	}
+}
+
 // ListDayHourEnergy returns hour energy (charge + discharge) for a station/day.
 func (r *DayHourEnergyReader) ListDayHourEnergy(ctx context.Context, subjectID string, dayStart time.Time) ([]settlementapp.HourEnergy, error) {
 	if r == nil || r.db == nil {
@@ -86,11 +97,15 @@ ORDER BY period_start ASC`, r.table)
 			return nil, err
 		}
 		if !completed {
-			return nil, errors.New("day hour energy reader: hour statistic not completed")
+			return nil, fmt.Errorf("day hour energy reader: hour %s not completed: %w", periodStart.UTC().Format(time.RFC3339), settlementapp.ErrHoursIncomplete)
+		}
+		energyKWh, err := r.negative.Apply(charge + discharge)
+		if err != nil {
+			return nil, fmt.Errorf("day hour energy reader: hour %s: %w", periodStart.UTC().Format(time.RFC3339), err)
 		}
 		result = append(result, settlementapp.HourEnergy{
 			HourStart: periodStart.UTC(),
-			EnergyKWh: charge + discharge,
+			EnergyKWh: energyKWh,
 		})
 	}
 	if err := rows.Err(); err != nil {
@@ -98,7 +113,7 @@ ORDER BY period_start ASC`, r.table)
 	}
 
 	if len(result) < r.expectedHours {
-		return nil, errors.New("day hour energy reader: incomplete hour statistics")
+		return nil, fmt.Errorf("day hour energy reader: %d of %d hours: %w", len(result), r.expectedHours, settlementapp.ErrHoursIncomplete)
 	}
 	return result, nil
 }
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// BillingFloor is the minimum day energy a contract bills. A day whose energy
+// is below its floor is settled with amount 0 but keeps its energy.
+type BillingFloor struct {
+	// MinDayEnergyKWh applies to tenants and categories without their own
+	// floor; 0 bills every day.
+	MinDayEnergyKWh float64
+	// TenantMinDayEnergyKWh overrides MinDayEnergyKWh per tenant.
+	TenantMinDayEnergyKWh map[string]float64
+	// CategoryMinDayEnergyKWh replaces the tenant floor on statements of a
+	// category; 0 bills every day. See validate for floors below a tenant's.
+	CategoryMinDayEnergyKWh map[string]float64
+}
+
+// tenantFloor returns the floor of a tenant's day settlements.
+func (f BillingFloor) tenantFloor(tenantID string) float64 {
+	if floor, ok := f.TenantMinDayEnergyKWh[tenantID]; ok {
+		return floor
+	}
+	return f.MinDayEnergyKWh
+}
+
+// categoryFloor returns the floor of a tenant's statements of category.
+func (f BillingFloor) categoryFloor(tenantID, category string) float64 {
+	if floor, ok := f.CategoryMinDayEnergyKWh[category]; ok {
+		return floor
+	}
+	return f.tenantFloor(tenantID)
+}
+
+// validate rejects category floors below a tenant floor for categories
+// without their own price. Their statements bill the day settlement amounts,
+// which are already 0 below the tenant floor, so the lower floor could not
+// bill those days; priced categories derive amounts from the energy instead.
+func (f BillingFloor) validate(pricer CategoryPricer) error {
+	tenantFloor := f.MinDayEnergyKWh
+	for _, floor := range f.TenantMinDayEnergyKWh {
+		tenantFloor = max(tenantFloor, floor)
+	}
+	categories := make([]string, 0, len(f.CategoryMinDayEnergyKWh))
+	for category := range f.CategoryMinDayEnergyKWh {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		floor := f.CategoryMinDayEnergyKWh[category]
+		if floor >= tenantFloor {
+			continue
+		}
+		if pricer != nil {
+			if _, ok := pricer.CategoryPrice(category); ok {
+				continue
+			}
+		}
+		return fmt.Errorf("billing floor: %s floor %v is below the tenant floor %v and %s has no category price", category, floor, tenantFloor, category)
+	}
+	return nil
+}
+
+// belowFloor reports whether day energy is not billable under floor. The
+// comparison uses the unrounded day energy.
+func belowFloor(energyKWh, floor float64) bool {
+	return floor > 0 && energyKWh < floor
+}
+
+// WithBillingFloor settles days below the floor of tenantID, the tenant the
+// service settles for, with amount 0.
+func WithBillingFloor(floor BillingFloor, tenantID string) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		s.minBillableKWh = floor.tenantFloor(tenantID)
+	}
+}
+
+// WithStatementBillingFloor zeroes the amount of statement items whose day
+// energy is below the floor of the statement's category, or of its tenant for
+// categories without one.
+func WithStatementBillingFloor(floor BillingFloor) StatementOption {
+	return func(s *StatementService) {
+		s.floor = floor
+	}
+}
+
+// applyFloor zeroes the items below floor and returns the new total amount.
+func applyFloor(items []settlement.StatementItem, floor float64) float64 {
+	var total float64
+	for i := range items {
+		if belowFloor(items[i].EnergyKWh, floor) {
+			items[i].Amount = 0
+		}
+		total += items[i].Amount
+	}
+	return total
+}
+
+// ParseBillingFloors parses "key=kwh,key=kwh" into per-tenant or per-category
+// billing floors.
+func ParseBillingFloors(spec string) (map[string]float64, error) {
+	floors := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, raw, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("billing floor: invalid floor %q", entry)
+		}
+		floor, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || floor < 0 {
+			return nil, fmt.Errorf("billing floor: invalid floor %q for %s", raw, key)
+		}
+		floors[key] = floor
+	}
+	return floors, nil
+}
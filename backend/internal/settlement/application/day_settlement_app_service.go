@@ -3,12 +3,18 @@ package application
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"microgrid-cloud/internal/energy"
 	"microgrid-cloud/internal/observability/metrics"
 	"microgrid-cloud/internal/settlement/domain"
 )
 
+// maxSettleAttempts bounds how often a day settlement is recalculated after
+// losing an optimistic-locking race to a concurrent recalculation.
+const maxSettleAttempts = 3
+
 // DayEnergyCalculated represents the day settlement trigger from analytics.
 type DayEnergyCalculated struct {
 	SubjectID   string
@@ -17,12 +23,14 @@ type DayEnergyCalculated struct {
 	OccurredAt  time.Time
 }
 
-// SettlementCalculated is emitted when a day settlement is first created.
+// SettlementCalculated is emitted when a day settlement is first created, and
+// again whenever a backfill recalculates it.
 type SettlementCalculated struct {
-	SubjectID  string
-	DayStart   time.Time
-	Amount     float64
-	OccurredAt time.Time
+	SubjectID   string
+	DayStart    time.Time
+	Amount      float64
+	OccurredAt  time.Time
+	Recalculate bool
 }
 
 // HourEnergy represents a single hour energy bucket.
@@ -63,6 +71,15 @@ type DaySettlementApplicationService struct {
 	pricing   TariffProvider
 	publisher SettlementPublisher
 	clock     Clock
+
+	anomalies        AnomalyPolicy
+	anomalyPublisher AnomalyPublisher
+
+	expectedHours  int
+	minBillableKWh float64
+	negative       energy.NegativePolicy
+
+	completedDays CompletedDayLister
 }
 
 // NewDaySettlementApplicationService constructs the service.
@@ -72,6 +89,7 @@ func NewDaySettlementApplicationService(
 	pricing TariffProvider,
 	publisher SettlementPublisher,
 	clock Clock,
+	opts ...DaySettlementOption,
 ) (*DaySettlementApplicationService, error) {
 	if repo == nil {
 		return nil, errors.New("day settlement app service: nil repository")
@@ -86,13 +104,29 @@ func NewDaySettlementApplicationService(
 		clock = SystemClock{}
 	}
 
-	return &DaySettlementApplicationService{
+	s := &DaySettlementApplicationService{
 		repo:      repo,
 		energy:    energy,
 		pricing:   pricing,
 		publisher: publisher,
 		clock:     clock,
-	}, nil
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s, nil
+}
+
+// WithNegativeEnergyPolicy applies policy to each hour's energy before it is
+// priced. Under energy.NegativeReject a day with a negative hour is not
+// settled and the trigger fails; the default passes negatives through to the
+// negative_energy anomaly check.
+func WithNegativeEnergyPolicy(policy energy.NegativePolicy) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		s.negative = policy
+	}
 }
 
 // HandleDayEnergyCalculated recalculates day settlement amounts.
@@ -112,61 +146,98 @@ func (s *DaySettlementApplicationService) HandleDayEnergyCalculated(ctx context.
 		return settlement.ErrInvalidDayStart
 	}
 
-	hourly, err := s.energy.ListDayHourEnergy(ctx, event.SubjectID, event.DayStart)
+	var (
+		wasNew  bool
+		settled *settlement.SettlementAggregate
+		err     error
+	)
+	for attempt := 1; ; attempt++ {
+		wasNew, settled, err = s.settleDay(ctx, event)
+		if errors.Is(err, settlement.ErrVersionConflict) && attempt < maxSettleAttempts {
+			continue
+		}
+		break
+	}
+	if errors.Is(err, errWaitingForHours) {
+		result = metrics.ResultWaiting
+		return nil
+	}
 	if err != nil {
 		result = metrics.ResultError
 		return err
 	}
 
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = s.clock.Now()
+	}
+
+	if (wasNew || event.Recalculate) && s.publisher != nil {
+		if err := s.publisher.PublishSettlementCalculated(ctx, SettlementCalculated{
+			SubjectID:   event.SubjectID,
+			DayStart:    event.DayStart,
+			Amount:      settled.Amount(),
+			OccurredAt:  occurredAt,
+			Recalculate: event.Recalculate,
+		}); err != nil {
+			return err
+		}
+	}
+	return s.reportAnomaly(ctx, settled, occurredAt)
+}
+
+// settleDay loads the day settlement, recalculates it from the current hourly
+// energy and saves it at the version it was loaded at. Implausible day energy
+// is saved flagged with its anomaly instead of failing the settlement.
+func (s *DaySettlementApplicationService) settleDay(ctx context.Context, event DayEnergyCalculated) (bool, *settlement.SettlementAggregate, error) {
+	hourly, err := s.energy.ListDayHourEnergy(ctx, event.SubjectID, event.DayStart)
+	if s.waitingForHours(hourly, err) {
+		return false, nil, errWaitingForHours
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
 	var energyKWh float64
 	var amount float64
 	for _, hour := range hourly {
+		hourKWh, err := s.negative.Apply(hour.EnergyKWh)
+		if err != nil {
+			return false, nil, fmt.Errorf("day settlement: hour %s: %w", hour.HourStart.UTC().Format(time.RFC3339), err)
+		}
 		price, err := s.pricing.PriceAt(ctx, event.SubjectID, hour.HourStart)
 		if err != nil {
-			result = metrics.ResultError
-			return err
+			return false, nil, err
 		}
-		energyKWh += hour.EnergyKWh
-		amount += hour.EnergyKWh * price
+		energyKWh += hourKWh
+		amount += hourKWh * price
 	}
 
 	agg, err := s.repo.FindBySubjectAndDay(ctx, event.SubjectID, event.DayStart)
 	if err != nil {
-		result = metrics.ResultError
-		return err
+		return false, nil, err
 	}
 	if agg == nil {
 		agg, err = settlement.NewDaySettlementAggregate(event.SubjectID, event.DayStart)
 		if err != nil {
-			result = metrics.ResultError
-			return err
+			return false, nil, err
 		}
 	}
 	wasNew := agg.IsNew()
 
-	if err := agg.Recalculate(energyKWh, amount); err != nil {
-		result = metrics.ResultError
-		return err
-	}
-
-	if err := s.repo.Save(ctx, agg); err != nil {
-		result = metrics.ResultError
-		return err
+	if belowFloor(energyKWh, s.minBillableKWh) {
+		amount = 0
 	}
-
-	if !wasNew || s.publisher == nil {
-		return nil
+	if anomaly := s.anomalies.classify(event.SubjectID, energyKWh); anomaly != settlement.AnomalyNone {
+		if !s.anomalies.PriceAnomalies {
+			amount = 0
+		}
+		agg.FlagAnomaly(anomaly, energyKWh, amount)
+	} else if err := agg.Recalculate(energyKWh, amount); err != nil {
+		return false, nil, err
 	}
-
-	occurredAt := event.OccurredAt
-	if occurredAt.IsZero() {
-		occurredAt = s.clock.Now()
+	if err := s.repo.Save(ctx, agg); err != nil {
+		return false, nil, err
 	}
-
-	return s.publisher.PublishSettlementCalculated(ctx, SettlementCalculated{
-		SubjectID:  event.SubjectID,
-		DayStart:   event.DayStart,
-		Amount:     amount,
-		OccurredAt: occurredAt,
-	})
+	return wasNew, agg, nil
 }
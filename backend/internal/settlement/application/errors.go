@@ -0,0 +1,58 @@
+package application
+
+import "errors"
+
+// Kinds of statement service errors. Every error the statement and month-close
+// services return, apart from auth.ErrTenantMismatch, matches exactly one of
+// them with errors.Is.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("invalid request")
+	ErrInternal   = errors.New("internal error")
+)
+
+// ServiceError is a classified service error. Kind is one of the sentinels
+// above; Err, when set, is the underlying cause.
+type ServiceError struct {
+	Kind    error
+	Message string
+	Err     error
+}
+
+func (e *ServiceError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return e.Message + ": " + e.Err.Error()
+}
+
+// Unwrap exposes both the kind and the cause to errors.Is and errors.As.
+func (e *ServiceError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+func notFoundError(message string) error {
+	return &ServiceError{Kind: ErrNotFound, Message: message}
+}
+
+func conflictError(message string) error {
+	return &ServiceError{Kind: ErrConflict, Message: message}
+}
+
+func validationError(message string) error {
+	return &ServiceError{Kind: ErrValidation, Message: message}
+}
+
+// internalError wraps a failure of a dependency, such as the database.
+// Errors that are already classified are returned unchanged.
+func internalError(message string, err error) error {
+	var classified *ServiceError
+	if errors.As(err, &classified) {
+		return err
+	}
+	return &ServiceError{Kind: ErrInternal, Message: message, Err: err}
+}
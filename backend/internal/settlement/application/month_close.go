@@ -0,0 +1,199 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"microgrid-cloud/internal/auth"
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// Month-close outcomes of a single station.
+const (
+	MonthCloseFrozen    = "frozen"
+	MonthCloseGenerated = "generated"
+	MonthCloseBlocked   = "blocked"
+	MonthCloseFailed    = "failed"
+)
+
+// StationLister lists the stations of a tenant.
+type StationLister interface {
+	ListStationIDs(ctx context.Context, tenantID string) ([]string, error)
+}
+
+// ReconcileOutcome is the verdict of reconciling one station month.
+type ReconcileOutcome struct {
+	WithinThresholds bool
+	ReportID         string
+	Action           string
+}
+
+// MonthReconciler reconciles a station month against its thresholds.
+type MonthReconciler interface {
+	ReconcileMonth(ctx context.Context, tenantID, stationID string, month time.Time) (ReconcileOutcome, error)
+}
+
+// MonthReconcilerFunc adapts a function to MonthReconciler.
+type MonthReconcilerFunc func(ctx context.Context, tenantID, stationID string, month time.Time) (ReconcileOutcome, error)
+
+// ReconcileMonth calls f.
+func (f MonthReconcilerFunc) ReconcileMonth(ctx context.Context, tenantID, stationID string, month time.Time) (ReconcileOutcome, error) {
+	return f(ctx, tenantID, stationID, month)
+}
+
+// MonthStatements generates and freezes statements; StatementService implements it.
+type MonthStatements interface {
+	Generate(ctx context.Context, stationID, month, category string, regenerate bool) (*settlement.StatementAggregate, error)
+	Freeze(ctx context.Context, id string) (*settlement.StatementAggregate, error)
+}
+
+// MonthCloseRequest selects what to close. An empty StationIDs closes every
+// station of the tenant.
+type MonthCloseRequest struct {
+	Month      string
+	Category   string
+	StationIDs []string
+	Regenerate bool
+	Freeze     bool
+}
+
+// MonthCloseStation reports what month close did for one station.
+type MonthCloseStation struct {
+	StationID         string   `json:"station_id"`
+	Status            string   `json:"status"`
+	ReconcileReportID string   `json:"reconcile_report_id,omitempty"`
+	StatementID       string   `json:"statement_id,omitempty"`
+	Blockers          []string `json:"blockers,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+// MonthCloseReport is the per-station result of a month close.
+type MonthCloseReport struct {
+	TenantID string              `json:"tenant_id"`
+	Month    string              `json:"month"`
+	Category string              `json:"category"`
+	Freeze   bool                `json:"freeze"`
+	Stations []MonthCloseStation `json:"stations"`
+	Blocked  int                 `json:"blocked"`
+	Failed   int                 `json:"failed"`
+}
+
+// MonthCloseService runs reconcile, statement generation and freeze for every
+// station of a month. A station with blockers keeps its draft and is not
+// frozen; the other stations are closed regardless.
+type MonthCloseService struct {
+	statements MonthStatements
+	stations   StationLister
+	reconciler MonthReconciler
+	tenantID   string
+}
+
+// NewMonthCloseService constructs the service. A nil reconciler skips the
+// reconcile check.
+func NewMonthCloseService(statements MonthStatements, stations StationLister, reconciler MonthReconciler, tenantID string) (*MonthCloseService, error) {
+	if statements == nil {
+		return nil, errors.New("month close: nil statements")
+	}
+	if stations == nil {
+		return nil, errors.New("month close: nil station lister")
+	}
+	if tenantID == "" {
+		return nil, errors.New("month close: empty tenant id")
+	}
+	return &MonthCloseService{statements: statements, stations: stations, reconciler: reconciler, tenantID: tenantID}, nil
+}
+
+// Close closes the requested month and reports the outcome per station.
+// Only a failure to parse the month or list stations is returned as an error.
+func (s *MonthCloseService) Close(ctx context.Context, req MonthCloseRequest) (*MonthCloseReport, error) {
+	monthStart, err := parseMonth(req.Month)
+	if err != nil {
+		return nil, err
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		tenantID = s.tenantID
+	}
+	category := req.Category
+	if category == "" {
+		category = "owner"
+	}
+	stationIDs := req.StationIDs
+	if len(stationIDs) == 0 {
+		if stationIDs, err = s.stations.ListStationIDs(ctx, tenantID); err != nil {
+			return nil, internalError("month close: list stations", err)
+		}
+	}
+
+	report := &MonthCloseReport{
+		TenantID: tenantID,
+		Month:    monthStart.Format("2006-01"),
+		Category: category,
+		Freeze:   req.Freeze,
+		Stations: make([]MonthCloseStation, 0, len(stationIDs)),
+	}
+	for _, stationID := range stationIDs {
+		station := s.closeStation(ctx, tenantID, stationID, monthStart, category, req)
+		switch station.Status {
+		case MonthCloseBlocked:
+			report.Blocked++
+		case MonthCloseFailed:
+			report.Failed++
+		}
+		report.Stations = append(report.Stations, station)
+	}
+	return report, nil
+}
+
+func (s *MonthCloseService) closeStation(ctx context.Context, tenantID, stationID string, monthStart time.Time, category string, req MonthCloseRequest) MonthCloseStation {
+	result := MonthCloseStation{StationID: stationID}
+	failed := func(step string, err error) MonthCloseStation {
+		result.Status = MonthCloseFailed
+		result.Error = step + ": " + err.Error()
+		return result
+	}
+
+	// Statements are only generated from reconciled data.
+	if s.reconciler != nil {
+		outcome, err := s.reconciler.ReconcileMonth(ctx, tenantID, stationID, monthStart)
+		if err != nil {
+			return failed("reconcile", err)
+		}
+		result.ReconcileReportID = outcome.ReportID
+		if !outcome.WithinThresholds {
+			result.Status = MonthCloseBlocked
+			result.Blockers = append(result.Blockers, "reconcile exceeds thresholds: "+outcome.Action)
+			return result
+		}
+	}
+
+	stmt, err := s.statements.Generate(ctx, stationID, monthStart.Format("2006-01"), category, req.Regenerate)
+	if err != nil {
+		return failed("generate", err)
+	}
+	result.StatementID = stmt.ID
+	if stmt.Status == settlement.StatementStatusFrozen {
+		result.Status = MonthCloseFrozen
+		return result
+	}
+	result.Status = MonthCloseGenerated
+	if stmt.Partial {
+		days := make([]string, 0, len(stmt.MissingDays))
+		for _, day := range stmt.MissingDays {
+			days = append(days, day.Format("2006-01-02"))
+		}
+		result.Status = MonthCloseBlocked
+		result.Blockers = append(result.Blockers, "incomplete days: "+strings.Join(days, ","))
+		return result
+	}
+
+	if req.Freeze {
+		if _, err := s.statements.Freeze(ctx, stmt.ID); err != nil {
+			return failed("freeze", err)
+		}
+		result.Status = MonthCloseFrozen
+	}
+	return result
+}
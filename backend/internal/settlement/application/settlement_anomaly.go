@@ -0,0 +1,120 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"microgrid-cloud/internal/observability/metrics"
+	"microgrid-cloud/internal/settlement/domain"
+)
+
+// AnomalyPolicy bounds the day energy considered plausible for settlement.
+// Negative day energy is always an anomaly.
+type AnomalyPolicy struct {
+	// DayEnergyCapKWh is the highest plausible day energy of stations without
+	// their own cap; 0 disables the check.
+	DayEnergyCapKWh float64
+	// StationDayEnergyCapKWh overrides DayEnergyCapKWh per station.
+	StationDayEnergyCapKWh map[string]float64
+	// PriceAnomalies keeps pricing anomalous days; by default their amount is 0.
+	PriceAnomalies bool
+}
+
+// capFor returns the day energy cap of a station; 0 means no cap.
+func (p AnomalyPolicy) capFor(subjectID string) float64 {
+	if cap, ok := p.StationDayEnergyCapKWh[subjectID]; ok {
+		return cap
+	}
+	return p.DayEnergyCapKWh
+}
+
+// classify returns the anomaly of a station's day energy.
+func (p AnomalyPolicy) classify(subjectID string, energyKWh float64) settlement.Anomaly {
+	if energyKWh < 0 {
+		return settlement.AnomalyNegativeEnergy
+	}
+	if cap := p.capFor(subjectID); cap > 0 && energyKWh > cap {
+		return settlement.AnomalyEnergyOverCap
+	}
+	return settlement.AnomalyNone
+}
+
+// ParseStationEnergyCaps parses "station=kwh,station=kwh" into per-station day energy caps.
+func ParseStationEnergyCaps(spec string) (map[string]float64, error) {
+	caps := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		stationID, raw, ok := strings.Cut(entry, "=")
+		stationID = strings.TrimSpace(stationID)
+		if !ok || stationID == "" {
+			return nil, fmt.Errorf("settlement anomaly: invalid station cap %q", entry)
+		}
+		cap, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || cap < 0 {
+			return nil, fmt.Errorf("settlement anomaly: invalid cap %q for station %s", raw, stationID)
+		}
+		caps[stationID] = cap
+	}
+	return caps, nil
+}
+
+// SettlementAnomalyDetected is emitted whenever a day settlement is flagged as implausible.
+type SettlementAnomalyDetected struct {
+	SubjectID  string
+	DayStart   time.Time
+	Anomaly    string
+	EnergyKWh  float64
+	CapKWh     float64
+	Amount     float64
+	OccurredAt time.Time
+}
+
+// AnomalyPublisher emits settlement anomaly events.
+type AnomalyPublisher interface {
+	PublishSettlementAnomaly(ctx context.Context, event SettlementAnomalyDetected) error
+}
+
+// DaySettlementOption configures the day settlement service.
+type DaySettlementOption func(*DaySettlementApplicationService)
+
+// WithAnomalyPolicy sets the sanity bounds for day energy.
+func WithAnomalyPolicy(policy AnomalyPolicy) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		s.anomalies = policy
+	}
+}
+
+// WithAnomalyPublisher announces flagged settlements so ops can investigate.
+func WithAnomalyPublisher(publisher AnomalyPublisher) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		s.anomalyPublisher = publisher
+	}
+}
+
+// reportAnomaly counts a flagged settlement and announces it. It runs on every
+// recalculation that is still implausible, so a redelivered trigger reports again.
+func (s *DaySettlementApplicationService) reportAnomaly(ctx context.Context, agg *settlement.SettlementAggregate, occurredAt time.Time) error {
+	anomaly := agg.Anomaly()
+	if anomaly == settlement.AnomalyNone {
+		return nil
+	}
+	metrics.IncSettlementAnomaly(string(anomaly))
+	if s.anomalyPublisher == nil {
+		return nil
+	}
+	return s.anomalyPublisher.PublishSettlementAnomaly(ctx, SettlementAnomalyDetected{
+		SubjectID:  agg.SubjectID(),
+		DayStart:   agg.DayStart(),
+		Anomaly:    string(anomaly),
+		EnergyKWh:  agg.EnergyKWh(),
+		CapKWh:     s.anomalies.capFor(agg.SubjectID()),
+		Amount:     agg.Amount(),
+		OccurredAt: occurredAt,
+	})
+}
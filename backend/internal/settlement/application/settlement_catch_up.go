@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// CompletedDayLister lists the days of a station whose day statistic is
+// completed.
+type CompletedDayLister interface {
+	ListCompletedDays(ctx context.Context, subjectID string, start, end time.Time) ([]time.Time, error)
+}
+
+// WithCompletedDays lets CatchUp find the completed days to check.
+func WithCompletedDays(lister CompletedDayLister) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		s.completedDays = lister
+	}
+}
+
+// SettlementCatchUpResult summarizes one settlement catch-up pass.
+type SettlementCatchUpResult struct {
+	DaysCompleted int
+	DaysMissing   int
+	DaysSettled   int
+}
+
+// CatchUp settles the completed days within lookback that have no settlement
+// yet, e.g. because the settlement subscriber was down when the day statistic
+// was announced. They are settled like the event-driven path, so new
+// settlements are published as usual. Days already settled are left alone;
+// the current day is left to the event-driven path.
+func (s *DaySettlementApplicationService) CatchUp(ctx context.Context, subjectID string, lookback time.Duration) (SettlementCatchUpResult, error) {
+	var result SettlementCatchUpResult
+	if s.completedDays == nil {
+		return result, errors.New("settlement catch-up: no completed day lister")
+	}
+	if lookback <= 0 {
+		return result, errors.New("settlement catch-up: lookback must be positive")
+	}
+	now := s.clock.Now().UTC()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := now.Add(-lookback)
+	start := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	if !start.Before(end) {
+		return result, nil
+	}
+
+	days, err := s.completedDays.ListCompletedDays(ctx, subjectID, start, end)
+	if err != nil {
+		return result, err
+	}
+	for _, dayStart := range days {
+		result.DaysCompleted++
+		existing, err := s.repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+		if err != nil {
+			return result, err
+		}
+		if existing != nil {
+			continue
+		}
+		result.DaysMissing++
+		if err := s.HandleDayEnergyCalculated(ctx, DayEnergyCalculated{
+			SubjectID:  subjectID,
+			DayStart:   dayStart,
+			OccurredAt: now,
+		}); err != nil {
+			return result, err
+		}
+		// A day still waiting for hours is not saved; the next pass retries it.
+		settled, err := s.repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+		if err != nil {
+			return result, err
+		}
+		if settled != nil {
+			result.DaysSettled++
+		}
+	}
+	return result, nil
+}
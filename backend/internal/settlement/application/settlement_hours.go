@@ -0,0 +1,33 @@
+package application
+
+import "errors"
+
+// ErrHoursIncomplete is reported by day energy readers when the hour
+// statistics of a day are not all in yet.
+var ErrHoursIncomplete = errors.New("day settlement: hour statistics incomplete")
+
+// errWaitingForHours marks a settlement deferred until the day is complete.
+var errWaitingForHours = errors.New("day settlement: waiting for hours")
+
+// WithExpectedHours defers settling a day until expected hours are available;
+// an incomplete day is left unsettled and counted with result "waiting", and
+// the next trigger for the day settles it. Zero, the default, settles
+// whatever hours the reader returns.
+func WithExpectedHours(expected int) DaySettlementOption {
+	return func(s *DaySettlementApplicationService) {
+		if expected >= 0 {
+			s.expectedHours = expected
+		}
+	}
+}
+
+// waitingForHours reports whether a day must wait for more hour statistics.
+func (s *DaySettlementApplicationService) waitingForHours(hourly []HourEnergy, err error) bool {
+	if s.expectedHours <= 0 {
+		return false
+	}
+	if err != nil {
+		return errors.Is(err, ErrHoursIncomplete)
+	}
+	return len(hourly) < s.expectedHours
+}
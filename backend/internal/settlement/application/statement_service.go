@@ -4,33 +4,119 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
-	"sort"
 	"strconv"
 	"time"
 
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/calendar"
 	"microgrid-cloud/internal/observability/metrics"
 	settlement "microgrid-cloud/internal/settlement/domain"
 	statementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
 )
 
+// CategoryPricer provides a price per kWh specific to a statement category.
+// ok is false when the category uses the price already applied to the day settlements.
+type CategoryPricer interface {
+	CategoryPrice(category string) (price float64, ok bool)
+}
+
 // StatementService handles settlement statement workflows.
 type StatementService struct {
-	repo     *statementrepo.StatementRepository
-	tenantID string
+	repo      *statementrepo.StatementRepository
+	readRepo  *statementrepo.StatementRepository
+	tenantID  string
+	pricer    CategoryPricer
+	floor     BillingFloor
+	snapshots settlement.SnapshotAlgorithm
+
+	currencies      settlement.CurrencyResolver
+	defaultCurrency string
+	billingCycles   settlement.BillingCycleResolver
+	clock           Clock
+}
+
+// StatementOption configures the statement service.
+type StatementOption func(*StatementService)
+
+// WithCategoryPricer reprices statement items for categories that have their own rate.
+func WithCategoryPricer(pricer CategoryPricer) StatementOption {
+	return func(s *StatementService) {
+		s.pricer = pricer
+	}
+}
+
+// WithReadRepository serves statement listings from repo, typically backed by
+// a read replica. Freezing, voiding and exports keep using the primary.
+func WithReadRepository(repo *statementrepo.StatementRepository) StatementOption {
+	return func(s *StatementService) {
+		if repo != nil {
+			s.readRepo = repo
+		}
+	}
+}
+
+// WithStatementCurrency sets the currency of statements for months without
+// settled days: the tenant's currency from resolver when it has one, else
+// fallback. Months with settled days keep the currency they were settled in.
+func WithStatementCurrency(resolver settlement.CurrencyResolver, fallback string) StatementOption {
+	return func(s *StatementService) {
+		s.currencies = resolver
+		if fallback != "" {
+			s.defaultCurrency = fallback
+		}
+	}
+}
+
+// WithBillingCycle generates statements over each tenant's billing cycle from
+// resolver instead of the calendar month. Tenants without a cycle keep
+// calendar months.
+func WithBillingCycle(resolver settlement.BillingCycleResolver) StatementOption {
+	return func(s *StatementService) {
+		s.billingCycles = resolver
+	}
+}
+
+// WithSnapshotAlgorithm sets the digest used for snapshot hashes on freeze.
+func WithSnapshotAlgorithm(algorithm settlement.SnapshotAlgorithm) StatementOption {
+	return func(s *StatementService) {
+		s.snapshots = algorithm
+	}
+}
+
+// WithStatementClock overrides the clock that stamps generated, frozen and
+// voided statements.
+func WithStatementClock(clock Clock) StatementOption {
+	return func(s *StatementService) {
+		if clock != nil {
+			s.clock = clock
+		}
+	}
 }
 
 // NewStatementService constructs a service.
-func NewStatementService(repo *statementrepo.StatementRepository, tenantID string) (*StatementService, error) {
+func NewStatementService(repo *statementrepo.StatementRepository, tenantID string, opts ...StatementOption) (*StatementService, error) {
 	if repo == nil {
 		return nil, errors.New("statement service: nil repo")
 	}
 	if tenantID == "" {
 		return nil, errors.New("statement service: empty tenant id")
 	}
-	return &StatementService{repo: repo, tenantID: tenantID}, nil
+	s := &StatementService{repo: repo, readRepo: repo, tenantID: tenantID, snapshots: settlement.DefaultSnapshotAlgorithm, defaultCurrency: settlement.DefaultCurrency, clock: SystemClock{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	algorithm, err := settlement.ParseSnapshotAlgorithm(string(s.snapshots))
+	if err != nil {
+		return nil, err
+	}
+	s.snapshots = algorithm
+	if err := s.floor.validate(s.pricer); err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
 // Generate creates or returns a statement draft.
@@ -43,7 +129,7 @@ func (s *StatementService) Generate(ctx context.Context, stationID, month, categ
 
 	if stationID == "" {
 		result = metrics.ResultError
-		return nil, errors.New("statement service: station_id required")
+		return nil, validationError("statement service: station_id required")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
@@ -62,7 +148,7 @@ func (s *StatementService) Generate(ctx context.Context, stationID, month, categ
 		existing, err := s.repo.FindLatestActive(ctx, tenantID, stationID, monthStart, category)
 		if err != nil {
 			result = metrics.ResultError
-			return nil, err
+			return nil, internalError("statement service: find statement", err)
 		}
 		if existing != nil {
 			if tenantID != "" && existing.TenantID != tenantID {
@@ -76,22 +162,47 @@ func (s *StatementService) Generate(ctx context.Context, stationID, month, categ
 	version, err := s.repo.NextVersion(ctx, tenantID, stationID, monthStart, category)
 	if err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: next version", err)
 	}
 
-	items, totals, currency, err := s.repo.BuildItemsFromSettlements(ctx, tenantID, stationID, monthStart)
+	periodStart, periodEnd, err := s.billingPeriod(ctx, tenantID, monthStart)
 	if err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: billing cycle", err)
 	}
+	items, totals, currency, err := s.repo.BuildItemsFromSettlements(ctx, tenantID, stationID, periodStart, periodEnd)
+	if err != nil {
+		result = metrics.ResultError
+		return nil, internalError("statement service: build items", err)
+	}
+	if currency == "" {
+		if currency, err = s.tenantCurrency(ctx, tenantID); err != nil {
+			result = metrics.ResultError
+			return nil, internalError("statement service: tenant currency", err)
+		}
+	}
+	if s.pricer != nil {
+		if price, ok := s.pricer.CategoryPrice(category); ok {
+			totals.TotalAmount = repriceItems(items, price)
+		}
+	}
+	// The category floor replaces the tenant floor, 0 included: repriced items
+	// below the tenant floor are billed again unless the category floor says
+	// otherwise.
+	totals.TotalAmount = applyFloor(items, s.floor.categoryFloor(tenantID, category))
 	statementID := buildStatementID(stationID, monthStart, category, version)
-	now := time.Now().UTC()
+	now := s.clock.Now().UTC()
+	// A period with unsettled days would silently total too low; flag it
+	// instead so the statement is not mistaken for a complete one.
+	missingDays := settlement.MissingSettlementDays(periodStart, periodEnd, now, items)
 
 	stmt := &settlement.StatementAggregate{
 		ID:             statementID,
 		TenantID:       tenantID,
 		StationID:      stationID,
 		StatementMonth: monthStart,
+		PeriodStart:    periodStart,
+		PeriodEnd:      periodEnd,
 		Category:       category,
 		Status:         settlement.StatementStatusDraft,
 		Version:        version,
@@ -100,16 +211,19 @@ func (s *StatementService) Generate(ctx context.Context, stationID, month, categ
 		Currency:       currency,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		Partial:        len(missingDays) > 0,
+		MissingDays:    missingDays,
 	}
 
 	if err := s.repo.CreateWithItems(ctx, stmt, items); err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: create statement", err)
 	}
 	return stmt, nil
 }
 
-// Freeze freezes a statement and computes snapshot hash.
+// Freeze freezes a draft statement and computes its snapshot hash. Freezing a
+// frozen or voided statement is a conflict.
 func (s *StatementService) Freeze(ctx context.Context, id string) (*settlement.StatementAggregate, error) {
 	start := time.Now()
 	result := metrics.ResultSuccess
@@ -120,11 +234,11 @@ func (s *StatementService) Freeze(ctx context.Context, id string) (*settlement.S
 	stmt, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: get statement", err)
 	}
 	if stmt == nil {
 		result = metrics.ResultError
-		return nil, errors.New("statement service: not found")
+		return nil, notFoundError("statement service: not found")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
@@ -134,28 +248,25 @@ func (s *StatementService) Freeze(ctx context.Context, id string) (*settlement.S
 		result = metrics.ResultError
 		return nil, auth.ErrTenantMismatch
 	}
-	if stmt.Status == settlement.StatementStatusFrozen {
-		return stmt, nil
-	}
-	if stmt.Status == settlement.StatementStatusVoided {
+	if !settlement.CanTransitionStatement(stmt.Status, settlement.StatementStatusFrozen) {
 		result = metrics.ResultError
-		return nil, errors.New("statement service: statement is voided")
+		return nil, transitionConflict("freeze", stmt.Status)
 	}
 
 	items, err := s.repo.ListItems(ctx, id)
 	if err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: list items", err)
 	}
-	hash, err := computeSnapshotHash(stmt, items)
+	hash, err := settlement.ComputeSnapshotHash(s.snapshots, stmt, items)
 	if err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, internalError("statement service: snapshot hash", err)
 	}
-	now := time.Now().UTC()
-	if err := s.repo.MarkFrozen(ctx, id, hash, now); err != nil {
+	now := s.clock.Now().UTC()
+	if err := s.repo.MarkFrozen(ctx, id, stmt.Status, hash, now); err != nil {
 		result = metrics.ResultError
-		return nil, err
+		return nil, statusUpdateError("freeze", err)
 	}
 	stmt.Status = settlement.StatementStatusFrozen
 	stmt.SnapshotHash = hash
@@ -164,14 +275,14 @@ func (s *StatementService) Freeze(ctx context.Context, id string) (*settlement.S
 	return stmt, nil
 }
 
-// Void voids a statement.
+// Void voids a draft or frozen statement; voiding a voided statement is a conflict.
 func (s *StatementService) Void(ctx context.Context, id, reason string) (*settlement.StatementAggregate, error) {
 	stmt, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, internalError("statement service: get statement", err)
 	}
 	if stmt == nil {
-		return nil, errors.New("statement service: not found")
+		return nil, notFoundError("statement service: not found")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
@@ -180,12 +291,12 @@ func (s *StatementService) Void(ctx context.Context, id, reason string) (*settle
 	if tenantID != "" && stmt.TenantID != tenantID {
 		return nil, auth.ErrTenantMismatch
 	}
-	if stmt.Status == settlement.StatementStatusVoided {
-		return stmt, nil
+	if !settlement.CanTransitionStatement(stmt.Status, settlement.StatementStatusVoided) {
+		return nil, transitionConflict("void", stmt.Status)
 	}
-	now := time.Now().UTC()
-	if err := s.repo.MarkVoided(ctx, id, reason, now); err != nil {
-		return nil, err
+	now := s.clock.Now().UTC()
+	if err := s.repo.MarkVoided(ctx, id, stmt.Status, reason, now); err != nil {
+		return nil, statusUpdateError("void", err)
 	}
 	stmt.Status = settlement.StatementStatusVoided
 	stmt.VoidReason = reason
@@ -194,14 +305,76 @@ func (s *StatementService) Void(ctx context.Context, id, reason string) (*settle
 	return stmt, nil
 }
 
+// VoidImpact describes what voiding a statement would entail.
+type VoidImpact struct {
+	Statement *settlement.StatementAggregate
+	// CanVoid reports whether the state machine allows the void.
+	CanVoid bool
+	// NewerVersions are the later versions of the same station month and
+	// category, oldest first.
+	NewerVersions []settlement.StatementAggregate
+	// Exports are the recorded exports of the statement.
+	Exports []settlement.StatementExport
+}
+
+// VoidImpact previews Void without changing anything, so finance can check
+// the totals, later versions and exports a void would affect.
+func (s *StatementService) VoidImpact(ctx context.Context, id string) (*VoidImpact, error) {
+	stmt, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, internalError("statement service: get statement", err)
+	}
+	if stmt == nil {
+		return nil, notFoundError("statement service: not found")
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		tenantID = s.tenantID
+	}
+	if tenantID != "" && stmt.TenantID != tenantID {
+		return nil, auth.ErrTenantMismatch
+	}
+	versions, err := s.repo.ListByStationMonthCategory(ctx, stmt.TenantID, stmt.StationID, stmt.StatementMonth, stmt.Category)
+	if err != nil {
+		return nil, internalError("statement service: list statements", err)
+	}
+	exports, err := s.repo.ListExports(ctx, id)
+	if err != nil {
+		return nil, internalError("statement service: list exports", err)
+	}
+	impact := &VoidImpact{
+		Statement: stmt,
+		CanVoid:   settlement.CanTransitionStatement(stmt.Status, settlement.StatementStatusVoided),
+		Exports:   exports,
+	}
+	for _, version := range versions {
+		if version.Version > stmt.Version {
+			impact.NewerVersions = append(impact.NewerVersions, version)
+		}
+	}
+	return impact, nil
+}
+
+// RecordExport records a successful download of stmt in format, so
+// VoidImpact lists it.
+func (s *StatementService) RecordExport(ctx context.Context, stmt *settlement.StatementAggregate, format string) error {
+	if stmt == nil {
+		return validationError("statement service: nil statement")
+	}
+	if err := s.repo.RecordExport(ctx, stmt.ID, format, "generated", ""); err != nil {
+		return internalError("statement service: record export", err)
+	}
+	return nil
+}
+
 // Get returns a statement with items.
 func (s *StatementService) Get(ctx context.Context, id string) (*settlement.StatementAggregate, []settlement.StatementItem, error) {
 	stmt, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, internalError("statement service: get statement", err)
 	}
 	if stmt == nil {
-		return nil, nil, errors.New("statement service: not found")
+		return nil, nil, notFoundError("statement service: not found")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
@@ -212,15 +385,16 @@ func (s *StatementService) Get(ctx context.Context, id string) (*settlement.Stat
 	}
 	items, err := s.repo.ListItems(ctx, id)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, internalError("statement service: list items", err)
 	}
 	return stmt, items, nil
 }
 
-// List returns statements for a station month/category.
-func (s *StatementService) List(ctx context.Context, stationID, month, category string) ([]settlement.StatementAggregate, error) {
+// List returns a page of the statements for a station month/category and the
+// number of statements; a limit of 0 returns all from offset.
+func (s *StatementService) List(ctx context.Context, stationID, month, category string, limit, offset int) ([]settlement.StatementAggregate, int, error) {
 	if stationID == "" {
-		return nil, errors.New("statement service: station_id required")
+		return nil, 0, validationError("statement service: station_id required")
 	}
 	tenantID := auth.TenantIDFromContext(ctx)
 	if tenantID == "" {
@@ -228,50 +402,84 @@ func (s *StatementService) List(ctx context.Context, stationID, month, category
 	}
 	monthStart, err := parseMonth(month)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if category == "" {
 		category = "owner"
 	}
-	return s.repo.ListByStationMonthCategory(ctx, tenantID, stationID, monthStart, category)
+	list, total, err := s.readRepo.ListByStationMonthCategoryPage(ctx, tenantID, stationID, monthStart, category, limit, offset)
+	if err != nil {
+		return nil, 0, internalError("statement service: list statements", err)
+	}
+	return list, total, nil
 }
 
-func parseMonth(month string) (time.Time, error) {
-	if month == "" {
-		return time.Time{}, errors.New("statement service: month required")
+// transitionConflict reports an action the statement state machine forbids.
+func transitionConflict(action, status string) error {
+	return conflictError("statement service: cannot " + action + " a " + status + " statement")
+}
+
+// statusUpdateError classifies a failed status update; a statement changed by
+// a concurrent request is a conflict.
+func statusUpdateError(action string, err error) error {
+	if errors.Is(err, settlement.ErrVersionConflict) {
+		return conflictError("statement service: statement changed during " + action + "; reload and retry")
+	}
+	return internalError("statement service: "+action+" statement", err)
+}
+
+// tenantCurrency resolves the currency of a tenant, falling back to the default.
+func (s *StatementService) tenantCurrency(ctx context.Context, tenantID string) (string, error) {
+	if s.currencies == nil {
+		return s.defaultCurrency, nil
 	}
-	t, err := time.Parse("2006-01", month)
+	currency, err := s.currencies.CurrencyForTenant(ctx, tenantID)
 	if err != nil {
-		return time.Time{}, errors.New("statement service: month must be YYYY-MM")
+		return "", err
+	}
+	if currency == "" {
+		return s.defaultCurrency, nil
 	}
-	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	return currency, nil
 }
 
-type totals struct {
-	TotalEnergyKWh float64
-	TotalAmount    float64
+// billingPeriod returns the window the statement labeled monthStart covers
+// for the tenant: its billing cycle, or the calendar month.
+func (s *StatementService) billingPeriod(ctx context.Context, tenantID string, monthStart time.Time) (time.Time, time.Time, error) {
+	day := 0
+	if s.billingCycles != nil {
+		var err error
+		if day, err = s.billingCycles.BillingCycleDayForTenant(ctx, tenantID); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	start, end := settlement.BillingPeriod(monthStart, day)
+	return start, end, nil
 }
 
-func computeSnapshotHash(stmt *settlement.StatementAggregate, items []settlement.StatementItem) (string, error) {
-	if stmt == nil {
-		return "", errors.New("statement service: nil statement")
-	}
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].DayStart.Before(items[j].DayStart)
-	})
-	payload := struct {
-		Statement *settlement.StatementAggregate `json:"statement"`
-		Items     []settlement.StatementItem     `json:"items"`
-	}{
-		Statement: stmt,
-		Items:     items,
-	}
-	data, err := json.Marshal(payload)
+// parseMonth parses the YYYY-MM label of a statement. With a billing cycle
+// the label names the month the cycle ends in.
+func parseMonth(month string) (time.Time, error) {
+	monthStart, err := calendar.ParseMonth(month)
 	if err != nil {
-		return "", err
+		return time.Time{}, validationError("statement service: " + err.Error())
 	}
-	hash := sha256.Sum256(data)
-	return hex.EncodeToString(hash[:]), nil
+	return monthStart, nil
+}
+
+// repriceItems sets each item amount to its energy at price and returns the new total.
+func repriceItems(items []settlement.StatementItem, price float64) float64 {
+	var total float64
+	for i := range items {
+		items[i].Amount = items[i].EnergyKWh * price
+		total += items[i].Amount
+	}
+	return total
+}
+
+type totals struct {
+	TotalEnergyKWh float64
+	TotalAmount    float64
 }
 
 func buildStatementID(stationID string, month time.Time, category string, version int) string {
@@ -15,8 +15,11 @@ type SettlementAggregate struct {
 
 	energyKWh float64
 	amount    float64
+	anomaly   Anomaly
 
-	isNew bool
+	// version is the stored row version this aggregate was loaded at; 0 when new.
+	version int
+	isNew   bool
 }
 
 // BuildSettlementID builds the aggregate identity from subject and day start.
@@ -58,9 +61,19 @@ func (a *SettlementAggregate) Recalculate(energyKWh, amount float64) error {
 	}
 	a.energyKWh = energyKWh
 	a.amount = amount
+	a.anomaly = AnomalyNone
 	return nil
 }
 
+// FlagAnomaly overwrites the settlement values with implausible day energy and
+// records why it is implausible. Unlike Recalculate, negative values are kept
+// so they can be investigated.
+func (a *SettlementAggregate) FlagAnomaly(anomaly Anomaly, energyKWh, amount float64) {
+	a.energyKWh = energyKWh
+	a.amount = amount
+	a.anomaly = anomaly
+}
+
 // ID returns aggregate identity.
 func (a *SettlementAggregate) ID() SettlementID { return a.id }
 
@@ -79,6 +92,19 @@ func (a *SettlementAggregate) EnergyKWh() float64 { return a.energyKWh }
 // Amount returns the settlement amount.
 func (a *SettlementAggregate) Amount() float64 { return a.amount }
 
+// Anomaly returns why the settlement is implausible, or AnomalyNone.
+func (a *SettlementAggregate) Anomaly() Anomaly { return a.anomaly }
+
+// Version returns the stored version the aggregate was loaded at.
+func (a *SettlementAggregate) Version() int { return a.version }
+
+// SetVersion records the stored version after a load or save.
+func (a *SettlementAggregate) SetVersion(version int) {
+	if a != nil {
+		a.version = version
+	}
+}
+
 // IsNew reports whether the aggregate was freshly created.
 func (a *SettlementAggregate) IsNew() bool { return a.isNew }
 
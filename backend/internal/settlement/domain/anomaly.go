@@ -0,0 +1,14 @@
+package settlement
+
+// Anomaly classifies day energy that is implausible for settlement, e.g. a
+// miswired meter sign or a runaway counter.
+type Anomaly string
+
+const (
+	// AnomalyNone marks a plausible settlement.
+	AnomalyNone Anomaly = ""
+	// AnomalyNegativeEnergy marks a day whose energy sums below zero.
+	AnomalyNegativeEnergy Anomaly = "negative_energy"
+	// AnomalyEnergyOverCap marks a day whose energy exceeds the station cap.
+	AnomalyEnergyOverCap Anomaly = "energy_over_cap"
+)
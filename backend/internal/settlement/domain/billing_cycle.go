@@ -0,0 +1,28 @@
+package settlement
+
+import (
+	"context"
+	"time"
+)
+
+// MaxBillingCycleDay is the last allowed cycle start day, so every month has it.
+const MaxBillingCycleDay = 28
+
+// BillingCycleResolver looks up the day of month a tenant's billing cycle
+// starts on; 0 means the tenant has none configured and bills calendar months.
+type BillingCycleResolver interface {
+	BillingCycleDayForTenant(ctx context.Context, tenantID string) (int, error)
+}
+
+// BillingPeriod returns the settlement window [start, end) of the statement
+// labeled month. A cycle day of 0 or 1 is the calendar month; any other day
+// starts the cycle on that day of the previous month, so "2026-02" with day
+// 26 covers 2026-01-26 through 2026-02-25.
+func BillingPeriod(month time.Time, cycleDay int) (time.Time, time.Time) {
+	monthStart := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if cycleDay <= 1 || cycleDay > MaxBillingCycleDay {
+		return monthStart, monthStart.AddDate(0, 1, 0)
+	}
+	end := monthStart.AddDate(0, 0, cycleDay-1)
+	return end.AddDate(0, -1, 0), end
+}
@@ -0,0 +1,12 @@
+package settlement
+
+import "context"
+
+// DefaultCurrency is used when neither the tenant nor the deployment sets one.
+const DefaultCurrency = "CNY"
+
+// CurrencyResolver looks up the settlement currency of a tenant; an empty
+// result means the tenant has none configured and the default applies.
+type CurrencyResolver interface {
+	CurrencyForTenant(ctx context.Context, tenantID string) (string, error)
+}
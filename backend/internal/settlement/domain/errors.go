@@ -13,4 +13,6 @@ var (
 	ErrNilAggregate = errors.New("settlement: nil aggregate")
 	// ErrSettlementNotFound is returned when a settlement is not found.
 	ErrSettlementNotFound = errors.New("settlement: not found")
+	// ErrVersionConflict is returned when the stored settlement changed since it was loaded.
+	ErrVersionConflict = errors.New("settlement: version conflict")
 )
@@ -0,0 +1,121 @@
+package settlement
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SnapshotCanonicalHeader is the first line of the canonical snapshot and
+// versions the serialization below.
+const SnapshotCanonicalHeader = "settlement-statement-snapshot/v1"
+
+// SnapshotAlgorithm names the digest used for statement snapshot hashes.
+type SnapshotAlgorithm string
+
+const (
+	SnapshotSHA256 SnapshotAlgorithm = "sha256"
+	SnapshotSHA512 SnapshotAlgorithm = "sha512"
+
+	// DefaultSnapshotAlgorithm is used when none is configured.
+	DefaultSnapshotAlgorithm = SnapshotSHA256
+)
+
+// ErrUnknownSnapshotAlgorithm is returned for unsupported snapshot algorithms.
+var ErrUnknownSnapshotAlgorithm = errors.New("settlement: unknown snapshot hash algorithm")
+
+// ParseSnapshotAlgorithm parses an algorithm name; empty means the default.
+func ParseSnapshotAlgorithm(value string) (SnapshotAlgorithm, error) {
+	switch SnapshotAlgorithm(strings.ToLower(strings.TrimSpace(value))) {
+	case "":
+		return DefaultSnapshotAlgorithm, nil
+	case SnapshotSHA256:
+		return SnapshotSHA256, nil
+	case SnapshotSHA512:
+		return SnapshotSHA512, nil
+	default:
+		return "", ErrUnknownSnapshotAlgorithm
+	}
+}
+
+func (a SnapshotAlgorithm) newHash() (hash.Hash, error) {
+	switch a {
+	case SnapshotSHA256:
+		return sha256.New(), nil
+	case SnapshotSHA512:
+		return sha512.New(), nil
+	default:
+		return nil, ErrUnknownSnapshotAlgorithm
+	}
+}
+
+// CanonicalSnapshot serializes the frozen content of a statement so that any
+// party can recompute its hash. The output is UTF-8 text, one "key=value"
+// field per line terminated by "\n", in this order:
+//
+//	settlement-statement-snapshot/v1
+//	id, tenant_id, station_id, statement_month (YYYY-MM), category, version,
+//	currency, total_energy_kwh, total_amount
+//	item=<YYYY-MM-DD>|<energy_kwh>|<amount>|<currency>  (one per item)
+//
+// Items are sorted by day (UTC). Numbers are fixed-point with six decimals
+// and negative zero is written as zero. Mutable fields (status, timestamps,
+// void reason) are not part of the snapshot. items is not modified.
+func CanonicalSnapshot(stmt *StatementAggregate, items []StatementItem) []byte {
+	sorted := append([]StatementItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DayStart.Before(sorted[j].DayStart)
+	})
+
+	var buf bytes.Buffer
+	buf.WriteString(SnapshotCanonicalHeader + "\n")
+	writeField := func(key, value string) {
+		buf.WriteString(key + "=" + value + "\n")
+	}
+	writeField("id", stmt.ID)
+	writeField("tenant_id", stmt.TenantID)
+	writeField("station_id", stmt.StationID)
+	writeField("statement_month", stmt.StatementMonth.UTC().Format("2006-01"))
+	writeField("category", stmt.Category)
+	writeField("version", strconv.Itoa(stmt.Version))
+	writeField("currency", stmt.Currency)
+	writeField("total_energy_kwh", canonicalNumber(stmt.TotalEnergyKWh))
+	writeField("total_amount", canonicalNumber(stmt.TotalAmount))
+	for _, item := range sorted {
+		writeField("item", strings.Join([]string{
+			item.DayStart.UTC().Format("2006-01-02"),
+			canonicalNumber(item.EnergyKWh),
+			canonicalNumber(item.Amount),
+			item.Currency,
+		}, "|"))
+	}
+	return buf.Bytes()
+}
+
+// ComputeSnapshotHash hashes the canonical snapshot and returns
+// "<algorithm>:<hex digest>".
+func ComputeSnapshotHash(algorithm SnapshotAlgorithm, stmt *StatementAggregate, items []StatementItem) (string, error) {
+	if stmt == nil {
+		return "", errors.New("settlement: nil statement")
+	}
+	h, err := algorithm.newHash()
+	if err != nil {
+		return "", err
+	}
+	_, _ = h.Write(CanonicalSnapshot(stmt, items))
+	return string(algorithm) + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func canonicalNumber(value float64) string {
+	formatted := strconv.FormatFloat(value, 'f', 6, 64)
+	if strings.Trim(formatted, "-0.") == "" {
+		return "0.000000"
+	}
+	return formatted
+}
@@ -8,12 +8,17 @@ const (
 	StatementStatusVoided = "voided"
 )
 
-// StatementAggregate represents a monthly settlement statement.
+// StatementAggregate represents a monthly settlement statement. It covers the
+// days from PeriodStart up to PeriodEnd, the calendar StatementMonth unless the
+// tenant bills on a cycle. Partial is set, with the days listed in MissingDays,
+// when some days of the period had no settlement at generation time.
 type StatementAggregate struct {
 	ID             string
 	TenantID       string
 	StationID      string
 	StatementMonth time.Time
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
 	Category       string
 	Status         string
 	Version        int
@@ -26,6 +31,27 @@ type StatementAggregate struct {
 	UpdatedAt      time.Time
 	FrozenAt       time.Time
 	VoidedAt       time.Time
+	Partial        bool
+	MissingDays    []time.Time
+}
+
+// statementTransitions is the statement state machine. A draft is frozen or
+// voided; a frozen statement can only be voided, once a regenerated version
+// supersedes it; a voided statement is final.
+var statementTransitions = map[string][]string{
+	StatementStatusDraft:  {StatementStatusFrozen, StatementStatusVoided},
+	StatementStatusFrozen: {StatementStatusVoided},
+}
+
+// CanTransitionStatement reports whether a statement may move from status
+// from to status to.
+func CanTransitionStatement(from, to string) bool {
+	for _, next := range statementTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
 }
 
 // StatementItem represents a daily item in a statement.
@@ -37,3 +63,44 @@ type StatementItem struct {
 	Currency    string
 	CreatedAt   time.Time
 }
+
+// StatementExport records a rendered export of a statement, e.g. a PDF handed
+// to invoicing.
+type StatementExport struct {
+	ID          string
+	StatementID string
+	Format      string
+	Status      string
+	PathOrKey   string
+	CreatedAt   time.Time
+}
+
+// StatementBranding holds the per-tenant assets printed on exported statements.
+type StatementBranding struct {
+	TenantID    string
+	CompanyName string
+	LogoPath    string
+	FooterText  string
+}
+
+// MissingSettlementDays returns the UTC days of the period [periodStart,
+// periodEnd) that have no item. Only days that ended before asOf are
+// expected, so the current period is judged up to yesterday.
+func MissingSettlementDays(periodStart, periodEnd, asOf time.Time, items []StatementItem) []time.Time {
+	end := periodEnd
+	if asOf.Before(periodEnd) {
+		end = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	present := make(map[time.Time]struct{}, len(items))
+	for _, item := range items {
+		day := item.DayStart.UTC()
+		present[time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)] = struct{}{}
+	}
+	var missing []time.Time
+	for day := periodStart; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if _, ok := present[day]; !ok {
+			missing = append(missing, day)
+		}
+	}
+	return missing
+}
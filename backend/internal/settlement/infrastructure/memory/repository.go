@@ -36,7 +36,8 @@ func (r *SettlementRepository) FindBySubjectAndDay(ctx context.Context, subjectI
 	return agg.Clone(), nil
 }
 
-// Save persists an aggregate (overwrites existing).
+// Save persists an aggregate, bumping its version. It fails with
+// ErrVersionConflict when the stored version differs from the loaded one.
 func (r *SettlementRepository) Save(ctx context.Context, aggregate *settlement.SettlementAggregate) error {
 	_ = ctx
 	if aggregate == nil {
@@ -47,11 +48,20 @@ func (r *SettlementRepository) Save(ctx context.Context, aggregate *settlement.S
 		return settlement.ErrEmptySubjectID
 	}
 
-	copy := aggregate.Clone()
 	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.data[string(id)]
+	switch {
+	case stored == nil && !aggregate.IsNew(),
+		stored != nil && (aggregate.IsNew() || stored.Version() != aggregate.Version()):
+		return settlement.ErrVersionConflict
+	}
+
+	copy := aggregate.Clone()
+	copy.SetVersion(aggregate.Version() + 1)
 	r.data[string(id)] = copy
-	r.mu.Unlock()
 
+	aggregate.SetVersion(copy.Version())
 	aggregate.MarkPersisted()
 	return nil
 }
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// BrandingRepository loads per-tenant statement branding.
+type BrandingRepository struct {
+	db *sql.DB
+}
+
+// NewBrandingRepository constructs a repository.
+func NewBrandingRepository(db *sql.DB) *BrandingRepository {
+	return &BrandingRepository{db: db}
+}
+
+// FindByTenant returns the tenant's branding, or nil when none is configured.
+func (r *BrandingRepository) FindByTenant(ctx context.Context, tenantID string) (*settlement.StatementBranding, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("branding repo: nil db")
+	}
+	branding := settlement.StatementBranding{TenantID: tenantID}
+	err := r.db.QueryRowContext(ctx, `
+SELECT company_name, logo_path, footer_text
+FROM statement_branding
+WHERE tenant_id = $1`, tenantID).Scan(&branding.CompanyName, &branding.LogoPath, &branding.FooterText)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &branding, nil
+}
@@ -19,6 +19,8 @@ type SettlementRepository struct {
 	tenantID string
 	currency string
 	status   string
+
+	currencies settlement.CurrencyResolver
 }
 
 // NewSettlementRepository constructs a repository with defaults.
@@ -26,7 +28,7 @@ func NewSettlementRepository(db *sql.DB, opts ...RepositoryOption) *SettlementRe
 	repo := &SettlementRepository{
 		db:       db,
 		table:    defaultSettlementTable,
-		currency: "CNY",
+		currency: settlement.DefaultCurrency,
 		status:   "CALCULATED",
 	}
 	for _, opt := range opts {
@@ -65,6 +67,14 @@ func WithCurrency(currency string) RepositoryOption {
 	}
 }
 
+// WithCurrencyResolver settles in the tenant's own currency when the resolver
+// has one; the WithCurrency value remains the fallback.
+func WithCurrencyResolver(resolver settlement.CurrencyResolver) RepositoryOption {
+	return func(repo *SettlementRepository) {
+		repo.currencies = resolver
+	}
+}
+
 // WithStatus sets the status string.
 func WithStatus(status string) RepositoryOption {
 	return func(repo *SettlementRepository) {
@@ -90,7 +100,7 @@ func (r *SettlementRepository) FindBySubjectAndDay(ctx context.Context, subjectI
 	}
 
 	query := fmt.Sprintf(`
-SELECT day_start, energy_kwh, amount
+SELECT day_start, energy_kwh, amount, version, anomaly
 FROM %s
 WHERE tenant_id = $1 AND station_id = $2 AND day_start = $3
 LIMIT 1`, r.table)
@@ -98,8 +108,10 @@ LIMIT 1`, r.table)
 	var storedDay time.Time
 	var energy float64
 	var amount float64
+	var version int
+	var anomaly sql.NullString
 	row := r.db.QueryRowContext(ctx, query, r.tenantID, subjectID, dayStart.UTC())
-	if err := row.Scan(&storedDay, &energy, &amount); err != nil {
+	if err := row.Scan(&storedDay, &energy, &amount, &version, &anomaly); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -110,14 +122,20 @@ LIMIT 1`, r.table)
 	if err != nil {
 		return nil, err
 	}
-	if err := agg.Recalculate(energy, amount); err != nil {
+	if anomaly.Valid && anomaly.String != "" {
+		agg.FlagAnomaly(settlement.Anomaly(anomaly.String), energy, amount)
+	} else if err := agg.Recalculate(energy, amount); err != nil {
 		return nil, err
 	}
+	agg.SetVersion(version)
 	agg.MarkPersisted()
 	return agg, nil
 }
 
-// Save upserts the settlement aggregate.
+// Save persists the settlement aggregate with optimistic locking. A new
+// aggregate is inserted at version 1; a loaded one is updated only if the
+// stored version still matches, otherwise ErrVersionConflict is returned so
+// the caller can reload and recalculate.
 func (r *SettlementRepository) Save(ctx context.Context, aggregate *settlement.SettlementAggregate) error {
 	if r == nil || r.db == nil {
 		return errors.New("settlement repo: nil db")
@@ -129,6 +147,36 @@ func (r *SettlementRepository) Save(ctx context.Context, aggregate *settlement.S
 		return errors.New("settlement repo: empty tenant id")
 	}
 
+	var (
+		res sql.Result
+		err error
+	)
+	if aggregate.IsNew() {
+		res, err = r.insert(ctx, aggregate)
+	} else {
+		res, err = r.update(ctx, aggregate)
+	}
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return settlement.ErrVersionConflict
+	}
+
+	aggregate.SetVersion(aggregate.Version() + 1)
+	aggregate.MarkPersisted()
+	return nil
+}
+
+func (r *SettlementRepository) insert(ctx context.Context, aggregate *settlement.SettlementAggregate) (sql.Result, error) {
+	currency, err := r.currencyFor(ctx)
+	if err != nil {
+		return nil, err
+	}
 	query := fmt.Sprintf(`
 INSERT INTO %s (
 	tenant_id,
@@ -138,20 +186,14 @@ INSERT INTO %s (
 	amount,
 	currency,
 	status,
+	anomaly,
 	version
 ) VALUES (
-	$1, $2, $3, $4, $5, $6, $7, 1
+	$1, $2, $3, $4, $5, $6, $7, $8, 1
 )
-ON CONFLICT (tenant_id, station_id, day_start)
-DO UPDATE SET
-	energy_kwh = EXCLUDED.energy_kwh,
-	amount = EXCLUDED.amount,
-	currency = EXCLUDED.currency,
-	status = EXCLUDED.status,
-	version = %s.version + 1,
-	updated_at = NOW()`, r.table, r.table)
-
-	_, err := r.db.ExecContext(
+ON CONFLICT (tenant_id, station_id, day_start) DO NOTHING`, r.table)
+
+	return r.db.ExecContext(
 		ctx,
 		query,
 		r.tenantID,
@@ -159,13 +201,64 @@ DO UPDATE SET
 		aggregate.DayStart().UTC(),
 		aggregate.EnergyKWh(),
 		aggregate.Amount(),
-		r.currency,
+		currency,
 		r.status,
+		anomalyValue(aggregate.Anomaly()),
 	)
+}
+
+func (r *SettlementRepository) update(ctx context.Context, aggregate *settlement.SettlementAggregate) (sql.Result, error) {
+	currency, err := r.currencyFor(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	query := fmt.Sprintf(`
+UPDATE %s
+SET
+	energy_kwh = $4,
+	amount = $5,
+	currency = $6,
+	status = $7,
+	anomaly = $9,
+	version = version + 1,
+	updated_at = NOW()
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND day_start = $3
+	AND version = $8`, r.table)
 
-	aggregate.MarkPersisted()
-	return nil
+	return r.db.ExecContext(
+		ctx,
+		query,
+		r.tenantID,
+		aggregate.SubjectID(),
+		aggregate.DayStart().UTC(),
+		aggregate.EnergyKWh(),
+		aggregate.Amount(),
+		currency,
+		r.status,
+		aggregate.Version(),
+		anomalyValue(aggregate.Anomaly()),
+	)
+}
+
+// currencyFor returns the repository tenant's currency, falling back to the
+// configured default.
+func (r *SettlementRepository) currencyFor(ctx context.Context) (string, error) {
+	if r.currencies == nil {
+		return r.currency, nil
+	}
+	currency, err := r.currencies.CurrencyForTenant(ctx, r.tenantID)
+	if err != nil {
+		return "", fmt.Errorf("settlement repo: resolve currency: %w", err)
+	}
+	if currency == "" {
+		return r.currency, nil
+	}
+	return currency, nil
+}
+
+// anomalyValue stores plausible settlements with a NULL anomaly.
+func anomalyValue(anomaly settlement.Anomaly) sql.NullString {
+	return sql.NullString{String: string(anomaly), Valid: anomaly != settlement.AnomalyNone}
 }
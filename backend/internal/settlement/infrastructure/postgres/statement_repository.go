@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -34,7 +35,8 @@ func (r *StatementRepository) FindLatestActive(ctx context.Context, tenantID, st
 	row := r.db.QueryRowContext(ctx, `
 SELECT id, tenant_id, station_id, statement_month, category, status, version,
 	total_energy_kwh, total_amount, currency, snapshot_hash, void_reason,
-	created_at, updated_at, frozen_at, voided_at
+	created_at, updated_at, frozen_at, voided_at, partial, missing_days,
+	period_start, period_end
 FROM settlement_statements
 WHERE tenant_id = $1 AND station_id = $2 AND statement_month = $3 AND category = $4
 	AND status IN ('draft','frozen')
@@ -70,6 +72,10 @@ func (r *StatementRepository) CreateWithItems(ctx context.Context, stmt *settlem
 	if stmt == nil {
 		return errors.New("statement repo: nil statement")
 	}
+	missingDays, err := encodeMissingDays(stmt.MissingDays)
+	if err != nil {
+		return err
+	}
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -77,12 +83,14 @@ func (r *StatementRepository) CreateWithItems(ctx context.Context, stmt *settlem
 	_, err = tx.ExecContext(ctx, `
 INSERT INTO settlement_statements (
 	id, tenant_id, station_id, statement_month, category, status, version,
-	total_energy_kwh, total_amount, currency, snapshot_hash, void_reason, created_at, updated_at
+	total_energy_kwh, total_amount, currency, snapshot_hash, void_reason, created_at, updated_at,
+	partial, missing_days, period_start, period_end
 ) VALUES (
-	$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14
+	$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18
 )`,
 		stmt.ID, stmt.TenantID, stmt.StationID, stmt.StatementMonth, stmt.Category, stmt.Status, stmt.Version,
 		stmt.TotalEnergyKWh, stmt.TotalAmount, stmt.Currency, stmt.SnapshotHash, stmt.VoidReason, stmt.CreatedAt, stmt.UpdatedAt,
+		stmt.Partial, missingDays, stmt.PeriodStart, stmt.PeriodEnd,
 	)
 	if err != nil {
 		_ = tx.Rollback()
@@ -110,7 +118,8 @@ func (r *StatementRepository) GetByID(ctx context.Context, id string) (*settleme
 	row := r.db.QueryRowContext(ctx, `
 SELECT id, tenant_id, station_id, statement_month, category, status, version,
 	total_energy_kwh, total_amount, currency, snapshot_hash, void_reason,
-	created_at, updated_at, frozen_at, voided_at
+	created_at, updated_at, frozen_at, voided_at, partial, missing_days,
+	period_start, period_end
 FROM settlement_statements
 WHERE id = $1
 LIMIT 1`, id)
@@ -119,18 +128,39 @@ LIMIT 1`, id)
 
 // ListByStationMonthCategory lists all versions for a month.
 func (r *StatementRepository) ListByStationMonthCategory(ctx context.Context, tenantID, stationID string, month time.Time, category string) ([]settlement.StatementAggregate, error) {
+	list, _, err := r.ListByStationMonthCategoryPage(ctx, tenantID, stationID, month, category, 0, 0)
+	return list, err
+}
+
+// ListByStationMonthCategoryPage lists a page of the statement versions of a
+// station month and category, oldest first. A limit above 0 and an offset
+// page the list in SQL; total is the number of versions.
+func (r *StatementRepository) ListByStationMonthCategoryPage(ctx context.Context, tenantID, stationID string, month time.Time, category string, limit, offset int) ([]settlement.StatementAggregate, int, error) {
 	if r == nil || r.db == nil {
-		return nil, errors.New("statement repo: nil db")
+		return nil, 0, errors.New("statement repo: nil db")
 	}
-	rows, err := r.db.QueryContext(ctx, `
+	where := `
+FROM settlement_statements
+WHERE tenant_id = $1 AND station_id = $2 AND statement_month = $3 AND category = $4`
+	args := []any{tenantID, stationID, month, category}
+	query := `
 SELECT id, tenant_id, station_id, statement_month, category, status, version,
 	total_energy_kwh, total_amount, currency, snapshot_hash, void_reason,
-	created_at, updated_at, frozen_at, voided_at
-FROM settlement_statements
-WHERE tenant_id = $1 AND station_id = $2 AND statement_month = $3 AND category = $4
-ORDER BY version ASC`, tenantID, stationID, month, category)
+	created_at, updated_at, frozen_at, voided_at, partial, missing_days,
+	period_start, period_end` + where + `
+ORDER BY version ASC`
+	pageArgs := append([]any(nil), args...)
+	if limit > 0 {
+		pageArgs = append(pageArgs, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(pageArgs))
+	}
+	if offset > 0 {
+		pageArgs = append(pageArgs, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(pageArgs))
+	}
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -138,16 +168,23 @@ ORDER BY version ASC`, tenantID, stationID, month, category)
 	for rows.Next() {
 		stmt, err := scanStatement(rows)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		if stmt != nil {
 			result = append(result, *stmt)
 		}
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return result, nil
+	if limit <= 0 && offset <= 0 {
+		return result, len(result), nil
+	}
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
 }
 
 // ListItems returns items for a statement.
@@ -181,32 +218,51 @@ ORDER BY day_start ASC`, statementID)
 	return result, nil
 }
 
-// MarkFrozen marks statement as frozen.
-func (r *StatementRepository) MarkFrozen(ctx context.Context, id, hash string, frozenAt time.Time) error {
+// MarkFrozen marks statement as frozen. It returns settlement.ErrVersionConflict
+// when the statement is no longer in status from.
+func (r *StatementRepository) MarkFrozen(ctx context.Context, id, from, hash string, frozenAt time.Time) error {
 	if r == nil || r.db == nil {
 		return errors.New("statement repo: nil db")
 	}
-	_, err := r.db.ExecContext(ctx, `
+	result, err := r.db.ExecContext(ctx, `
 UPDATE settlement_statements
 SET status = $1, snapshot_hash = $2, frozen_at = $3, updated_at = $3
-WHERE id = $4`, settlement.StatementStatusFrozen, hash, frozenAt, id)
-	return err
+WHERE id = $4 AND status = $5`, settlement.StatementStatusFrozen, hash, frozenAt, id, from)
+	if err != nil {
+		return err
+	}
+	return requireStatementRow(result)
 }
 
-// MarkVoided marks statement as voided.
-func (r *StatementRepository) MarkVoided(ctx context.Context, id, reason string, voidedAt time.Time) error {
+// MarkVoided marks statement as voided. It returns settlement.ErrVersionConflict
+// when the statement is no longer in status from.
+func (r *StatementRepository) MarkVoided(ctx context.Context, id, from, reason string, voidedAt time.Time) error {
 	if r == nil || r.db == nil {
 		return errors.New("statement repo: nil db")
 	}
-	_, err := r.db.ExecContext(ctx, `
+	result, err := r.db.ExecContext(ctx, `
 UPDATE settlement_statements
 SET status = $1, void_reason = $2, voided_at = $3, updated_at = $3
-WHERE id = $4`, settlement.StatementStatusVoided, reason, voidedAt, id)
-	return err
+WHERE id = $4 AND status = $5`, settlement.StatementStatusVoided, reason, voidedAt, id, from)
+	if err != nil {
+		return err
+	}
+	return requireStatementRow(result)
+}
+
+// requireStatementRow turns a status-guarded update that matched no row into
+// a conflict: another request changed the statement since it was loaded.
+func requireStatementRow(result sql.Result) error {
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return settlement.ErrVersionConflict
+	}
+	return nil
 }
 
-// BuildItemsFromSettlements loads settlements_day and builds items/totals.
-func (r *StatementRepository) BuildItemsFromSettlements(ctx context.Context, tenantID, stationID string, monthStart time.Time) ([]settlement.StatementItem, struct {
+// BuildItemsFromSettlements loads the settlements_day rows of [periodStart,
+// periodEnd) and builds items/totals. The currency is that of the first
+// settled day, or "" for a period without any.
+func (r *StatementRepository) BuildItemsFromSettlements(ctx context.Context, tenantID, stationID string, periodStart, periodEnd time.Time) ([]settlement.StatementItem, struct {
 	TotalEnergyKWh float64
 	TotalAmount    float64
 }, string, error) {
@@ -216,12 +272,11 @@ func (r *StatementRepository) BuildItemsFromSettlements(ctx context.Context, ten
 			TotalAmount    float64
 		}{}, "", errors.New("statement repo: nil db")
 	}
-	monthEnd := monthStart.AddDate(0, 1, 0)
 	rows, err := r.db.QueryContext(ctx, `
 SELECT day_start, energy_kwh, amount, currency
 FROM settlements_day
 WHERE tenant_id = $1 AND station_id = $2 AND day_start >= $3 AND day_start < $4
-ORDER BY day_start ASC`, tenantID, stationID, monthStart, monthEnd)
+ORDER BY day_start ASC`, tenantID, stationID, periodStart, periodEnd)
 	if err != nil {
 		return nil, struct {
 			TotalEnergyKWh float64
@@ -266,9 +321,6 @@ ORDER BY day_start ASC`, tenantID, stationID, monthStart, monthEnd)
 			TotalAmount    float64
 		}{}, "", err
 	}
-	if currency == "" {
-		currency = "CNY"
-	}
 	totals := struct {
 		TotalEnergyKWh float64
 		TotalAmount    float64
@@ -286,6 +338,9 @@ func scanStatement(row rowScanner) (*settlement.StatementAggregate, error) {
 	var voidReason sql.NullString
 	var frozenAt sql.NullTime
 	var voidedAt sql.NullTime
+	var missingDays []byte
+	var periodStart sql.NullTime
+	var periodEnd sql.NullTime
 	err := row.Scan(
 		&stmt.ID,
 		&stmt.TenantID,
@@ -303,6 +358,10 @@ func scanStatement(row rowScanner) (*settlement.StatementAggregate, error) {
 		&stmt.UpdatedAt,
 		&frozenAt,
 		&voidedAt,
+		&stmt.Partial,
+		&missingDays,
+		&periodStart,
+		&periodEnd,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -322,12 +381,80 @@ func scanStatement(row rowScanner) (*settlement.StatementAggregate, error) {
 	if voidedAt.Valid {
 		stmt.VoidedAt = voidedAt.Time.UTC()
 	}
+	if stmt.MissingDays, err = decodeMissingDays(missingDays); err != nil {
+		return nil, err
+	}
 	stmt.StatementMonth = stmt.StatementMonth.UTC()
+	// Statements generated before billing cycles cover their calendar month.
+	stmt.PeriodStart, stmt.PeriodEnd = settlement.BillingPeriod(stmt.StatementMonth, 0)
+	if periodStart.Valid && periodEnd.Valid {
+		stmt.PeriodStart, stmt.PeriodEnd = periodStart.Time.UTC(), periodEnd.Time.UTC()
+	}
 	stmt.CreatedAt = stmt.CreatedAt.UTC()
 	stmt.UpdatedAt = stmt.UpdatedAt.UTC()
 	return &stmt, nil
 }
 
+func encodeMissingDays(days []time.Time) (string, error) {
+	values := make([]string, 0, len(days))
+	for _, day := range days {
+		values = append(values, day.UTC().Format("2006-01-02"))
+	}
+	data, err := json.Marshal(values)
+	return string(data), err
+}
+
+func decodeMissingDays(data []byte) ([]time.Time, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("statement repo: missing_days: %w", err)
+	}
+	var days []time.Time
+	for _, value := range values {
+		day, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, fmt.Errorf("statement repo: missing_days: %w", err)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// ListExports returns the export records of a statement, oldest first.
+func (r *StatementRepository) ListExports(ctx context.Context, statementID string) ([]settlement.StatementExport, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("statement repo: nil db")
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, statement_id, format, status, path_or_key, created_at
+FROM statement_exports
+WHERE statement_id = $1
+ORDER BY created_at ASC, id ASC`, statementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []settlement.StatementExport
+	for rows.Next() {
+		var export settlement.StatementExport
+		var path sql.NullString
+		if err := rows.Scan(&export.ID, &export.StatementID, &export.Format, &export.Status, &path, &export.CreatedAt); err != nil {
+			return nil, err
+		}
+		export.PathOrKey = path.String
+		export.CreatedAt = export.CreatedAt.UTC()
+		result = append(result, export)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // RecordExport stores an export record (optional).
 func (r *StatementRepository) RecordExport(ctx context.Context, statementID, format, status, path string) error {
 	if r == nil || r.db == nil {
@@ -3,20 +3,49 @@ package pricing
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
-// FixedPriceProvider returns a fixed price per kWh.
+// FixedPriceProvider returns a fixed price per kWh, optionally overridden per
+// statement category.
 type FixedPriceProvider struct {
-	price float64
+	price          float64
+	categoryPrices map[string]float64
+}
+
+// FixedPriceOption configures the fixed price provider.
+type FixedPriceOption func(*FixedPriceProvider)
+
+// WithCategoryPrice sets the price per kWh used for one statement category.
+func WithCategoryPrice(category string, price float64) FixedPriceOption {
+	return func(p *FixedPriceProvider) {
+		category = strings.TrimSpace(category)
+		if category != "" {
+			p.categoryPrices[category] = price
+		}
+	}
 }
 
 // NewFixedPriceProvider constructs the provider.
-func NewFixedPriceProvider(price float64) (*FixedPriceProvider, error) {
+func NewFixedPriceProvider(price float64, opts ...FixedPriceOption) (*FixedPriceProvider, error) {
 	if price < 0 {
 		return nil, errors.New("price provider: negative price")
 	}
-	return &FixedPriceProvider{price: price}, nil
+	p := &FixedPriceProvider{price: price, categoryPrices: make(map[string]float64)}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	for category, categoryPrice := range p.categoryPrices {
+		if categoryPrice < 0 {
+			return nil, fmt.Errorf("price provider: negative price for category %q", category)
+		}
+	}
+	return p, nil
 }
 
 // PriceAt returns the configured fixed price.
@@ -27,3 +56,35 @@ func (p *FixedPriceProvider) PriceAt(ctx context.Context, subjectID string, at t
 	// TODO: replace with dynamic tariff / pricing service once available.
 	return p.price, nil
 }
+
+// CategoryPrice returns the price configured for category. ok is false when
+// the category has no rate of its own and the single fixed price applies.
+func (p *FixedPriceProvider) CategoryPrice(category string) (price float64, ok bool) {
+	if p == nil {
+		return 0, false
+	}
+	price, ok = p.categoryPrices[category]
+	return price, ok
+}
+
+// ParseCategoryPriceSpec parses "category=price,category=price" into options.
+func ParseCategoryPriceSpec(spec string) ([]FixedPriceOption, error) {
+	var opts []FixedPriceOption
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		category, raw, ok := strings.Cut(entry, "=")
+		category = strings.TrimSpace(category)
+		if !ok || category == "" {
+			return nil, fmt.Errorf("price provider: invalid category price %q", entry)
+		}
+		price, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("price provider: invalid price %q for category %s", raw, category)
+		}
+		opts = append(opts, WithCategoryPrice(category, price))
+	}
+	return opts, nil
+}
@@ -0,0 +1,168 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+)
+
+func init() {
+	sql.Register("statement-cycle-stub", cycleStubDriver{})
+}
+
+func TestBillingPeriod(t *testing.T) {
+	day := func(year int, month time.Month, d int) time.Time {
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	cases := []struct {
+		name       string
+		month      time.Time
+		cycleDay   int
+		start, end time.Time
+	}{
+		{"unset is calendar", day(2026, time.February, 1), 0, day(2026, time.February, 1), day(2026, time.March, 1)},
+		{"day one is calendar", day(2026, time.February, 1), 1, day(2026, time.February, 1), day(2026, time.March, 1)},
+		{"26th to 25th", day(2026, time.March, 1), 26, day(2026, time.February, 26), day(2026, time.March, 26)},
+		{"crosses the year", day(2026, time.January, 1), 26, day(2025, time.December, 26), day(2026, time.January, 26)},
+		{"out of range is calendar", day(2026, time.March, 1), 31, day(2026, time.March, 1), day(2026, time.April, 1)},
+	}
+	for _, tc := range cases {
+		start, end := settlement.BillingPeriod(tc.month, tc.cycleDay)
+		if !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Fatalf("%s: period = %s..%s, want %s..%s", tc.name, start, end, tc.start, tc.end)
+		}
+	}
+}
+
+func TestStatementGenerate_UsesTenantBillingCycle(t *testing.T) {
+	db, err := sql.Open("statement-cycle-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-cycle",
+		settlementapp.WithBillingCycle(cycleDays{"tenant-cycle": 26}),
+	)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	stmt, err := service.Generate(context.Background(), "station-cycle", "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	wantStart := time.Date(2026, time.January, 26, 0, 0, 0, 0, time.UTC)
+	wantEnd := time.Date(2026, time.February, 26, 0, 0, 0, 0, time.UTC)
+	args := cycleSettlementQuery.Last()
+	if len(args) != 4 || args[2].Value != wantStart || args[3].Value != wantEnd {
+		t.Fatalf("settlements_day window = %v, want %s..%s", args, wantStart, wantEnd)
+	}
+	if !stmt.StatementMonth.Equal(time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("statement month = %s, want the month the cycle ends in", stmt.StatementMonth)
+	}
+	if !stmt.PeriodStart.Equal(wantStart) || !stmt.PeriodEnd.Equal(wantEnd) {
+		t.Fatalf("period = %s..%s", stmt.PeriodStart, stmt.PeriodEnd)
+	}
+	// The stub settles every cycle day but 2026-02-01, on the far side of the boundary.
+	if stmt.TotalEnergyKWh != 300 || len(stmt.MissingDays) != 1 || stmt.MissingDays[0].Format("2006-01-02") != "2026-02-01" {
+		t.Fatalf("energy = %v, missing = %v", stmt.TotalEnergyKWh, stmt.MissingDays)
+	}
+
+	// A tenant without a cycle keeps the calendar month.
+	calendar, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-calendar",
+		settlementapp.WithBillingCycle(cycleDays{}),
+	)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	if _, err := calendar.Generate(context.Background(), "station-cycle", "2026-02", "owner", false); err != nil {
+		t.Fatalf("generate calendar: %v", err)
+	}
+	args = cycleSettlementQuery.Last()
+	if args[2].Value != time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC) || args[3].Value != time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("calendar window = %v", args)
+	}
+}
+
+// cycleDays resolves billing cycle days from a map.
+type cycleDays map[string]int
+
+func (c cycleDays) BillingCycleDayForTenant(_ context.Context, tenantID string) (int, error) {
+	return c[tenantID], nil
+}
+
+// cycleSettlementQuery records the arguments of every settlements_day query.
+var cycleSettlementQuery queryArgsLog
+
+type queryArgsLog struct {
+	mu   sync.Mutex
+	last []driver.NamedValue
+}
+
+func (l *queryArgsLog) Record(args []driver.NamedValue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.last = args
+}
+
+func (l *queryArgsLog) Last() []driver.NamedValue {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.last
+}
+
+// cycleStubDriver has no statements and settles 10 kWh on every day from
+// 2026-01-26 to 2026-02-25 except 2026-02-01, whatever window is queried;
+// inserts succeed.
+type cycleStubDriver struct{}
+
+func (cycleStubDriver) Open(string) (driver.Conn, error) {
+	return cycleStubConn{}, nil
+}
+
+type cycleStubConn struct{}
+
+func (cycleStubConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	switch {
+	case strings.Contains(query, "MAX(version)"):
+		return &statementStubRows{rows: [][]driver.Value{{nil}}}, nil
+	case strings.Contains(query, "FROM settlements_day"):
+		cycleSettlementQuery.Record(args)
+		var rows [][]driver.Value
+		start := time.Date(2026, time.January, 26, 0, 0, 0, 0, time.UTC)
+		for day := start; day.Before(start.AddDate(0, 1, 0)); day = day.AddDate(0, 0, 1) {
+			if day.Day() == 1 {
+				continue
+			}
+			rows = append(rows, []driver.Value{day, 10.0, 5.0, "CNY"})
+		}
+		return &statementStubRows{rows: rows}, nil
+	}
+	return &statementStubRows{}, nil
+}
+
+func (cycleStubConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (cycleStubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("cycle stub: prepare not supported")
+}
+
+func (cycleStubConn) Close() error { return nil }
+
+func (c cycleStubConn) Begin() (driver.Tx, error) { return c, nil }
+
+func (cycleStubConn) Commit() error { return nil }
+
+func (cycleStubConn) Rollback() error { return nil }
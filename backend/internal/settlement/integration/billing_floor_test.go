@@ -0,0 +1,211 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	"microgrid-cloud/internal/settlement/infrastructure/pricing"
+)
+
+func TestDaySettlement_BillingFloor(t *testing.T) {
+	ctx := context.Background()
+	below := "station-floor-below"
+	above := "station-floor-above"
+	dayStart := time.Date(2026, time.January, 24, 0, 0, 0, 0, time.UTC)
+
+	repo := memory.NewSettlementRepository()
+	energy := newHourEnergyStore()
+	energy.SetDayEnergy(below, dayStart, 19.5)
+	energy.SetDayEnergy(above, dayStart, 20)
+	floor := settlementapp.BillingFloor{
+		MinDayEnergyKWh:       50,
+		TenantMinDayEnergyKWh: map[string]float64{"tenant-floor": 20},
+	}
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 2}, nil, fixedClock{now: dayStart.Add(25 * time.Hour)},
+		settlementapp.WithBillingFloor(floor, "tenant-floor"),
+	)
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+
+	for _, subjectID := range []string{below, above} {
+		if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}); err != nil {
+			t.Fatalf("settle %s: %v", subjectID, err)
+		}
+	}
+
+	agg, _ := repo.FindBySubjectAndDay(ctx, below, dayStart)
+	if agg.EnergyKWh() != 19.5 || agg.Amount() != 0 {
+		t.Fatalf("below floor: energy=%v amount=%v, want 19.5 kWh billed 0", agg.EnergyKWh(), agg.Amount())
+	}
+	// A day exactly at the floor is billed.
+	agg, _ = repo.FindBySubjectAndDay(ctx, above, dayStart)
+	if agg.EnergyKWh() != 20 || agg.Amount() != 40 {
+		t.Fatalf("at floor: energy=%v amount=%v, want 20 kWh billed 40", agg.EnergyKWh(), agg.Amount())
+	}
+
+	for _, spec := range []string{"tenant-a", "tenant-a=abc", "=5", "tenant-a=-1"} {
+		if _, err := settlementapp.ParseBillingFloors(spec); err == nil {
+			t.Fatalf("expected error for spec %q", spec)
+		}
+	}
+	if floors, err := settlementapp.ParseBillingFloors(" grid=5, owner=0 "); err != nil || floors["grid"] != 5 || len(floors) != 2 {
+		t.Fatalf("parse floors = %v, %v", floors, err)
+	}
+}
+
+func TestStatement_CategoryBillingFloor(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-floor"
+	stationID := "station-stmt-floor"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{10, 20}, []float64{10, 20}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	provider, err := pricing.NewFixedPriceProvider(1.0, pricing.WithCategoryPrice("grid", 0.5))
+	if err != nil {
+		t.Fatalf("price provider: %v", err)
+	}
+	floor := settlementapp.BillingFloor{CategoryMinDayEnergyKWh: map[string]float64{"grid": 15}}
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID,
+		settlementapp.WithCategoryPricer(provider),
+		settlementapp.WithStatementBillingFloor(floor),
+	)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+
+	owner, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate owner: %v", err)
+	}
+	grid, err := stmtService.Generate(ctx, stationID, "2026-02", "grid", false)
+	if err != nil {
+		t.Fatalf("generate grid: %v", err)
+	}
+	if owner.TotalAmount != 30 {
+		t.Fatalf("owner total = %v, want 30 without a floor", owner.TotalAmount)
+	}
+	// The 10 kWh day is below the grid floor; the 20 kWh day is repriced at 0.5.
+	if grid.TotalAmount != 10 || grid.TotalEnergyKWh != 30 {
+		t.Fatalf("grid total = %v for %v kWh, want 10 for 30 kWh", grid.TotalAmount, grid.TotalEnergyKWh)
+	}
+}
+
+func TestStatement_ZeroCategoryFloorBillsEveryDay(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-floor-zero"
+	stationID := "station-stmt-floor-zero"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	// The 10 kWh day was settled below the tenant floor of 15.
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{10, 20}, []float64{0, 20}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	provider, err := pricing.NewFixedPriceProvider(1.0, pricing.WithCategoryPrice("grid", 0.5))
+	if err != nil {
+		t.Fatalf("price provider: %v", err)
+	}
+	floor := settlementapp.BillingFloor{
+		TenantMinDayEnergyKWh:   map[string]float64{tenantID: 15},
+		CategoryMinDayEnergyKWh: map[string]float64{"grid": 0},
+	}
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID,
+		settlementapp.WithCategoryPricer(provider),
+		settlementapp.WithStatementBillingFloor(floor),
+	)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+
+	owner, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate owner: %v", err)
+	}
+	grid, err := stmtService.Generate(ctx, stationID, "2026-02", "grid", false)
+	if err != nil {
+		t.Fatalf("generate grid: %v", err)
+	}
+	if owner.TotalAmount != 20 {
+		t.Fatalf("owner total = %v, want 20 under the tenant floor", owner.TotalAmount)
+	}
+	// A grid floor of 0 bills both days at the grid price.
+	if grid.TotalAmount != 15 {
+		t.Fatalf("grid total = %v, want 15 with every day billed", grid.TotalAmount)
+	}
+}
+
+func TestStatementService_RejectsUnpricedCategoryFloorBelowTenantFloor(t *testing.T) {
+	provider, err := pricing.NewFixedPriceProvider(1.0, pricing.WithCategoryPrice("grid", 0.5))
+	if err != nil {
+		t.Fatalf("price provider: %v", err)
+	}
+	repo := settlementrepo.NewStatementRepository(nil)
+	floor := settlementapp.BillingFloor{
+		MinDayEnergyKWh:         5,
+		TenantMinDayEnergyKWh:   map[string]float64{"tenant-a": 15},
+		CategoryMinDayEnergyKWh: map[string]float64{"grid": 0, "owner": 20},
+	}
+	if _, err := settlementapp.NewStatementService(repo, "tenant-a",
+		settlementapp.WithCategoryPricer(provider),
+		settlementapp.WithStatementBillingFloor(floor),
+	); err != nil {
+		t.Fatalf("priced grid and higher owner floor: %v", err)
+	}
+
+	// owner bills the day settlement amounts, already 0 below tenant-a's floor.
+	floor.CategoryMinDayEnergyKWh = map[string]float64{"owner": 10}
+	if _, err := settlementapp.NewStatementService(repo, "tenant-a",
+		settlementapp.WithCategoryPricer(provider),
+		settlementapp.WithStatementBillingFloor(floor),
+	); err == nil {
+		t.Fatal("expected error for an unpriced category floor below the tenant floor")
+	}
+}
@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	"microgrid-cloud/internal/settlement/infrastructure/pricing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestFixedPriceProvider_CategoryPrices(t *testing.T) {
+	opts, err := pricing.ParseCategoryPriceSpec("grid=0.8, operator=0.6")
+	if err != nil {
+		t.Fatalf("parse spec: %v", err)
+	}
+	provider, err := pricing.NewFixedPriceProvider(1.0, opts...)
+	if err != nil {
+		t.Fatalf("new provider: %v", err)
+	}
+
+	if price, ok := provider.CategoryPrice("grid"); !ok || price != 0.8 {
+		t.Fatalf("grid price = %v, %t", price, ok)
+	}
+	if _, ok := provider.CategoryPrice("owner"); ok {
+		t.Fatalf("owner must fall back to the single price")
+	}
+	if price, err := provider.PriceAt(context.Background(), "station-any", time.Now()); err != nil || price != 1.0 {
+		t.Fatalf("PriceAt = %v, %v", price, err)
+	}
+
+	for _, spec := range []string{"grid", "grid=abc", "=0.5", "grid=-1"} {
+		if _, err := pricing.ParseCategoryPriceSpec(spec); err == nil {
+			t.Fatalf("expected error for spec %q", spec)
+		}
+	}
+}
+
+func TestStatement_CategoryPricesYieldDifferentAmounts(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-category"
+	stationID := "station-stmt-category"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	// Day settlements priced at the single 1.0 rate.
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{10, 20}, []float64{10, 20}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	provider, err := pricing.NewFixedPriceProvider(1.0, pricing.WithCategoryPrice("grid", 0.5))
+	if err != nil {
+		t.Fatalf("price provider: %v", err)
+	}
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID, settlementapp.WithCategoryPricer(provider))
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+
+	owner, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate owner: %v", err)
+	}
+	grid, err := stmtService.Generate(ctx, stationID, "2026-02", "grid", false)
+	if err != nil {
+		t.Fatalf("generate grid: %v", err)
+	}
+
+	if owner.TotalAmount != 30 {
+		t.Fatalf("owner total = %v, want 30", owner.TotalAmount)
+	}
+	if grid.TotalAmount != 15 {
+		t.Fatalf("grid total = %v, want 15", grid.TotalAmount)
+	}
+	if owner.TotalEnergyKWh != grid.TotalEnergyKWh {
+		t.Fatalf("energy must not depend on category: owner=%v grid=%v", owner.TotalEnergyKWh, grid.TotalEnergyKWh)
+	}
+
+	_, items, err := stmtService.Get(ctx, grid.ID)
+	if err != nil {
+		t.Fatalf("get grid statement: %v", err)
+	}
+	for _, item := range items {
+		if item.Amount != item.EnergyKWh*0.5 {
+			t.Fatalf("grid item %s amount = %v, want %v", item.DayStart.Format("2006-01-02"), item.Amount, item.EnergyKWh*0.5)
+		}
+	}
+}
@@ -68,8 +68,13 @@ func TestDaySettlement_RecalculateOnEnergyBackfill(t *testing.T) {
 		t.Fatalf("amount should be overwritten, not accumulated")
 	}
 
-	if publisher.Count() != 1 {
-		t.Fatalf("expected SettlementCalculated event once, got %d", publisher.Count())
+	// The first calculation announces the new settlement; the backfill
+	// announces the recalculation so backfill tracking can complete.
+	if publisher.Count() != 2 {
+		t.Fatalf("expected SettlementCalculated for creation and backfill, got %d", publisher.Count())
+	}
+	if recalcs := publisher.Recalculated(); recalcs != 1 {
+		t.Fatalf("expected 1 recalculated SettlementCalculated event, got %d", recalcs)
 	}
 }
 
@@ -139,8 +144,9 @@ func (s *hourEnergyStore) ListDayHourEnergy(ctx context.Context, subjectID strin
 }
 
 type settlementEventRecorder struct {
-	mu    sync.RWMutex
-	count int
+	mu           sync.RWMutex
+	count        int
+	recalculated int
 }
 
 func newSettlementEventRecorder() *settlementEventRecorder {
@@ -149,10 +155,12 @@ func newSettlementEventRecorder() *settlementEventRecorder {
 
 func (r *settlementEventRecorder) PublishSettlementCalculated(ctx context.Context, event appsettlement.SettlementCalculated) error {
 	_ = ctx
-	_ = event
 
 	r.mu.Lock()
 	r.count++
+	if event.Recalculate {
+		r.recalculated++
+	}
 	r.mu.Unlock()
 	return nil
 }
@@ -163,6 +171,12 @@ func (r *settlementEventRecorder) Count() int {
 	return r.count
 }
 
+func (r *settlementEventRecorder) Recalculated() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.recalculated
+}
+
 func dayTimeKey(dayStart time.Time) string {
 	return dayStart.UTC().Format("20060102")
 }
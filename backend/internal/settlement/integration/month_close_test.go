@@ -0,0 +1,173 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+func TestMonthClose_BlocksStationWithIncompleteDays(t *testing.T) {
+	ctx := context.Background()
+	statements := newMonthStatementsStub()
+	statements.missing["station-gap"] = []time.Time{
+		time.Date(2026, time.January, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC),
+	}
+	reconciler := settlementapp.MonthReconcilerFunc(func(_ context.Context, _, stationID string, _ time.Time) (settlementapp.ReconcileOutcome, error) {
+		switch stationID {
+		case "station-drift":
+			return settlementapp.ReconcileOutcome{ReportID: "report-drift", Action: "check_tariff_or_settlement"}, nil
+		case "station-broken":
+			return settlementapp.ReconcileOutcome{}, errors.New("no tariff plan")
+		}
+		return settlementapp.ReconcileOutcome{WithinThresholds: true, ReportID: "report-" + stationID, Action: "none"}, nil
+	})
+	stations := stationList{"tenant-close": {"station-ok", "station-gap", "station-drift", "station-broken"}}
+	service, err := settlementapp.NewMonthCloseService(statements, stations, reconciler, "tenant-close")
+	if err != nil {
+		t.Fatalf("month close service: %v", err)
+	}
+
+	report, err := service.Close(ctx, settlementapp.MonthCloseRequest{Month: "2026-01", Freeze: true})
+	if err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if report.Month != "2026-01" || report.Category != "owner" || len(report.Stations) != 4 || report.Blocked != 2 || report.Failed != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	byStation := make(map[string]settlementapp.MonthCloseStation)
+	for _, station := range report.Stations {
+		byStation[station.StationID] = station
+	}
+
+	if ok := byStation["station-ok"]; ok.Status != settlementapp.MonthCloseFrozen || ok.StatementID == "" || ok.ReconcileReportID != "report-station-ok" {
+		t.Fatalf("complete station = %+v", ok)
+	}
+	gap := byStation["station-gap"]
+	if gap.Status != settlementapp.MonthCloseBlocked || gap.StatementID == "" || len(gap.Blockers) != 1 ||
+		gap.Blockers[0] != "incomplete days: 2026-01-30,2026-01-31" {
+		t.Fatalf("incomplete station = %+v", gap)
+	}
+	if statements.Frozen(gap.StatementID) {
+		t.Fatalf("statement with incomplete days was frozen")
+	}
+	drift := byStation["station-drift"]
+	if drift.Status != settlementapp.MonthCloseBlocked || drift.StatementID != "" || !strings.Contains(drift.Blockers[0], "check_tariff_or_settlement") {
+		t.Fatalf("unreconciled station = %+v", drift)
+	}
+	if broken := byStation["station-broken"]; broken.Status != settlementapp.MonthCloseFailed || !strings.Contains(broken.Error, "no tariff plan") {
+		t.Fatalf("failed station = %+v", broken)
+	}
+	if got := statements.Generated(); got != 2 {
+		t.Fatalf("generated %d statements, want 2 (reconciled stations only)", got)
+	}
+}
+
+func TestMonthClose_HTTPReportsPerStation(t *testing.T) {
+	db, err := sql.Open("statement-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+	statementService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-close")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	statements := newMonthStatementsStub()
+	statements.missing["station-gap"] = []time.Time{time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)}
+	monthClose, err := settlementapp.NewMonthCloseService(statements, stationList{}, nil, "tenant-close")
+	if err != nil {
+		t.Fatalf("month close service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(statementService, nil, nil, settlementinterfaces.WithMonthClose(monthClose))
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/statements/close",
+		strings.NewReader(`{"month":"2026-01","station_ids":["station-ok","station-gap"]}`)))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", resp.Code, resp.Body.String())
+	}
+	var report settlementapp.MonthCloseReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("decode report: %v", err)
+	}
+	if len(report.Stations) != 2 || report.Stations[0].Status != settlementapp.MonthCloseGenerated ||
+		report.Stations[1].Status != settlementapp.MonthCloseBlocked || report.Blocked != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/statements/close", strings.NewReader(`{"month":"January"}`)))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("invalid month status = %d, want 400", resp.Code)
+	}
+}
+
+type stationList map[string][]string
+
+func (l stationList) ListStationIDs(_ context.Context, tenantID string) ([]string, error) {
+	return l[tenantID], nil
+}
+
+// monthStatementsStub generates drafts in memory; stations listed in missing
+// get partial statements.
+type monthStatementsStub struct {
+	mu        sync.Mutex
+	missing   map[string][]time.Time
+	generated int
+	frozen    map[string]bool
+}
+
+func newMonthStatementsStub() *monthStatementsStub {
+	return &monthStatementsStub{missing: make(map[string][]time.Time), frozen: make(map[string]bool)}
+}
+
+func (s *monthStatementsStub) Generate(_ context.Context, stationID, month, category string, _ bool) (*settlement.StatementAggregate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generated++
+	missing := s.missing[stationID]
+	return &settlement.StatementAggregate{
+		ID:          "stmt-" + stationID + "-" + month + "-" + category,
+		StationID:   stationID,
+		Category:    category,
+		Status:      settlement.StatementStatusDraft,
+		Version:     1,
+		Partial:     len(missing) > 0,
+		MissingDays: missing,
+	}, nil
+}
+
+func (s *monthStatementsStub) Freeze(_ context.Context, id string) (*settlement.StatementAggregate, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frozen[id] = true
+	return &settlement.StatementAggregate{ID: id, Status: settlement.StatementStatusFrozen}, nil
+}
+
+func (s *monthStatementsStub) Generated() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generated
+}
+
+func (s *monthStatementsStub) Frozen(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.frozen[id]
+}
@@ -0,0 +1,139 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/energy"
+	settlementadapters "microgrid-cloud/internal/settlement/adapters/analytics"
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+)
+
+func init() {
+	sql.Register("settlement-negative-hours", negativeHoursDriver{})
+}
+
+func TestDaySettlement_NegativeEnergyPolicies(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "station-negative"
+	dayStart := time.Date(2026, time.January, 25, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		policy  energy.NegativePolicy
+		energy  float64
+		amount  float64
+		anomaly settlement.Anomaly
+		reject  bool
+	}{
+		{policy: energy.NegativePassThrough, energy: -30, amount: 0, anomaly: settlement.AnomalyNegativeEnergy},
+		{policy: energy.NegativeClamp, energy: 0, amount: 0, anomaly: settlement.AnomalyNone},
+		{policy: energy.NegativeAbsolute, energy: 30, amount: 60, anomaly: settlement.AnomalyNone},
+		{policy: energy.NegativeReject, reject: true},
+	}
+	for _, tc := range cases {
+		repo := memory.NewSettlementRepository()
+		hours := newHourEnergyStore()
+		hours.SetDayEnergy(subjectID, dayStart, -30)
+		app, err := settlementapp.NewDaySettlementApplicationService(repo, hours, fixedPrice{unit: 2}, nil, fixedClock{now: dayStart.Add(25 * time.Hour)},
+			settlementapp.WithNegativeEnergyPolicy(tc.policy),
+		)
+		if err != nil {
+			t.Fatalf("%s: new app service: %v", tc.policy, err)
+		}
+
+		err = app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart})
+		agg, _ := repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+		if tc.reject {
+			if !errors.Is(err, energy.ErrNegativeEnergy) || agg != nil {
+				t.Fatalf("%s: err = %v, settlement = %v; want ErrNegativeEnergy and nothing saved", tc.policy, err, agg)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: settle: %v", tc.policy, err)
+		}
+		if agg.EnergyKWh() != tc.energy || agg.Amount() != tc.amount || agg.Anomaly() != tc.anomaly {
+			t.Fatalf("%s: energy=%v amount=%v anomaly=%q, want %v %v %q", tc.policy, agg.EnergyKWh(), agg.Amount(), agg.Anomaly(), tc.energy, tc.amount, tc.anomaly)
+		}
+	}
+}
+
+func TestDayHourEnergyReader_NegativeEnergyPolicies(t *testing.T) {
+	db, err := sql.Open("settlement-negative-hours", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+	dayStart := time.Date(2026, time.January, 25, 0, 0, 0, 0, time.UTC)
+
+	// The stub hour has charge 5 and discharge -12, so its energy is -7.
+	want := map[energy.NegativePolicy]float64{
+		energy.NegativePassThrough: -7,
+		energy.NegativeClamp:       0,
+		energy.NegativeAbsolute:    7,
+	}
+	for policy, energyKWh := range want {
+		reader := settlementadapters.NewDayHourEnergyReader(db, settlementadapters.WithExpectedHours(1), settlementadapters.WithNegativeEnergyPolicy(policy))
+		hours, err := reader.ListDayHourEnergy(context.Background(), "station-negative", dayStart)
+		if err != nil {
+			t.Fatalf("%s: list hours: %v", policy, err)
+		}
+		if len(hours) != 1 || hours[0].EnergyKWh != energyKWh {
+			t.Fatalf("%s: hours = %+v, want one hour of %v kWh", policy, hours, energyKWh)
+		}
+	}
+
+	reader := settlementadapters.NewDayHourEnergyReader(db, settlementadapters.WithExpectedHours(1), settlementadapters.WithNegativeEnergyPolicy(energy.NegativeReject))
+	if _, err := reader.ListDayHourEnergy(context.Background(), "station-negative", dayStart); !errors.Is(err, energy.ErrNegativeEnergy) {
+		t.Fatalf("reject: err = %v, want ErrNegativeEnergy", err)
+	}
+}
+
+// negativeHoursDriver answers every query with one completed hour whose
+// discharge outweighs its charge with the wrong sign.
+type negativeHoursDriver struct{}
+
+func (negativeHoursDriver) Open(string) (driver.Conn, error) {
+	return negativeHoursConn{}, nil
+}
+
+type negativeHoursConn struct{}
+
+func (negativeHoursConn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	dayStart, _ := args[1].Value.(time.Time)
+	return &negativeHoursRows{rows: [][]driver.Value{{dayStart, 5.0, -12.0, true}}}, nil
+}
+
+func (negativeHoursConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("negative hours driver: prepare not supported")
+}
+
+func (negativeHoursConn) Close() error { return nil }
+
+func (negativeHoursConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("negative hours driver: transactions not supported")
+}
+
+type negativeHoursRows struct {
+	rows [][]driver.Value
+}
+
+func (r *negativeHoursRows) Columns() []string { return make([]string, 4) }
+
+func (r *negativeHoursRows) Close() error { return nil }
+
+func (r *negativeHoursRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
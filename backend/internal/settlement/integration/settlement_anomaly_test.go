@@ -0,0 +1,200 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestDaySettlement_FlagsNegativeEnergy(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "station-anomaly-negative"
+	dayStart := time.Date(2026, time.January, 22, 0, 0, 0, 0, time.UTC)
+
+	repo := memory.NewSettlementRepository()
+	energy := newHourEnergyStore()
+	energy.SetDayEnergy(subjectID, dayStart, -40)
+	anomalies := &anomalyRecorder{}
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 2}, nil, fixedClock{now: dayStart.Add(25 * time.Hour)},
+		settlementapp.WithAnomalyPublisher(anomalies),
+	)
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}); err != nil {
+		t.Fatalf("negative energy must be flagged, not rejected: %v", err)
+	}
+	agg, err := repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+	if err != nil || agg == nil {
+		t.Fatalf("load settlement: %v, %v", agg, err)
+	}
+	if agg.Anomaly() != settlement.AnomalyNegativeEnergy || agg.EnergyKWh() != -40 || agg.Amount() != 0 {
+		t.Fatalf("settlement anomaly=%q energy=%v amount=%v, want negative_energy, -40 kWh, unpriced", agg.Anomaly(), agg.EnergyKWh(), agg.Amount())
+	}
+	events := anomalies.Events()
+	if len(events) != 1 || events[0].Anomaly != string(settlement.AnomalyNegativeEnergy) || events[0].EnergyKWh != -40 {
+		t.Fatalf("anomaly events = %+v", events)
+	}
+
+	// A corrected recalculation clears the flag.
+	energy.SetDayEnergy(subjectID, dayStart, 40)
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart, Recalculate: true}); err != nil {
+		t.Fatalf("recalculate: %v", err)
+	}
+	agg, _ = repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+	if agg.Anomaly() != settlement.AnomalyNone || agg.Amount() != 80 {
+		t.Fatalf("corrected settlement anomaly=%q amount=%v", agg.Anomaly(), agg.Amount())
+	}
+	if len(anomalies.Events()) != 1 {
+		t.Fatalf("plausible recalculation must not report an anomaly")
+	}
+}
+
+func TestDaySettlement_FlagsEnergyOverStationCap(t *testing.T) {
+	ctx := context.Background()
+	capped := "station-anomaly-capped"
+	uncapped := "station-anomaly-default"
+	dayStart := time.Date(2026, time.January, 23, 0, 0, 0, 0, time.UTC)
+
+	repo := memory.NewSettlementRepository()
+	energy := newHourEnergyStore()
+	energy.SetDayEnergy(capped, dayStart, 600)
+	energy.SetDayEnergy(uncapped, dayStart, 600)
+	anomalies := &anomalyRecorder{}
+	policy := settlementapp.AnomalyPolicy{
+		DayEnergyCapKWh:        1000,
+		StationDayEnergyCapKWh: map[string]float64{capped: 500},
+		PriceAnomalies:         true,
+	}
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 1}, nil, fixedClock{now: dayStart.Add(25 * time.Hour)},
+		settlementapp.WithAnomalyPolicy(policy),
+		settlementapp.WithAnomalyPublisher(anomalies),
+	)
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+
+	for _, subjectID := range []string{capped, uncapped} {
+		if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}); err != nil {
+			t.Fatalf("settle %s: %v", subjectID, err)
+		}
+	}
+
+	agg, _ := repo.FindBySubjectAndDay(ctx, capped, dayStart)
+	if agg.Anomaly() != settlement.AnomalyEnergyOverCap || agg.Amount() != 600 {
+		t.Fatalf("capped settlement anomaly=%q amount=%v, want energy_over_cap still priced", agg.Anomaly(), agg.Amount())
+	}
+	agg, _ = repo.FindBySubjectAndDay(ctx, uncapped, dayStart)
+	if agg.Anomaly() != settlement.AnomalyNone {
+		t.Fatalf("station under the default cap flagged as %q", agg.Anomaly())
+	}
+	events := anomalies.Events()
+	if len(events) != 1 || events[0].SubjectID != capped || events[0].CapKWh != 500 {
+		t.Fatalf("anomaly events = %+v", events)
+	}
+}
+
+func TestParseStationEnergyCaps(t *testing.T) {
+	caps, err := settlementapp.ParseStationEnergyCaps(" station-a=500, station-b=1200.5 ")
+	if err != nil || caps["station-a"] != 500 || caps["station-b"] != 1200.5 {
+		t.Fatalf("caps = %v, %v", caps, err)
+	}
+	for _, spec := range []string{"station-a", "=10", "station-a=-1", "station-a=lots"} {
+		if _, err := settlementapp.ParseStationEnergyCaps(spec); err == nil {
+			t.Fatalf("spec %q must be rejected", spec)
+		}
+	}
+}
+
+func TestDaySettlement_AnomalyRoundTrip_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "settlements_day") {
+		t.Skip("missing tables; run migrations")
+	}
+	migration, err := os.ReadFile(filepath.Join(projectRoot(), "migrations", "024_settlement_anomaly.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("apply migration: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-settlement-anomaly"
+	stationID := "station-settlement-anomaly"
+	dayStart := time.Date(2026, time.January, 24, 0, 0, 0, 0, time.UTC)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	repo := settlementrepo.NewSettlementRepository(db, settlementrepo.WithTenantID(tenantID))
+	energy := newHourEnergyStore()
+	energy.SetDayEnergy(stationID, dayStart, -12)
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 1}, nil, settlementapp.SystemClock{})
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: stationID, DayStart: dayStart}); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+
+	// The flagged row must load back despite its negative energy.
+	agg, err := repo.FindBySubjectAndDay(ctx, stationID, dayStart)
+	if err != nil || agg == nil {
+		t.Fatalf("load flagged settlement: %v, %v", agg, err)
+	}
+	if agg.Anomaly() != settlement.AnomalyNegativeEnergy || agg.EnergyKWh() != -12 {
+		t.Fatalf("loaded anomaly=%q energy=%v", agg.Anomaly(), agg.EnergyKWh())
+	}
+
+	energy.SetDayEnergy(stationID, dayStart, 12)
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: stationID, DayStart: dayStart, Recalculate: true}); err != nil {
+		t.Fatalf("recalculate: %v", err)
+	}
+	var anomaly sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT anomaly FROM settlements_day WHERE tenant_id = $1 AND station_id = $2 AND day_start = $3",
+		tenantID, stationID, dayStart).Scan(&anomaly); err != nil {
+		t.Fatalf("load anomaly column: %v", err)
+	}
+	if anomaly.Valid {
+		t.Fatalf("corrected settlement still flagged as %q", anomaly.String)
+	}
+}
+
+type anomalyRecorder struct {
+	mu     sync.Mutex
+	events []settlementapp.SettlementAnomalyDetected
+}
+
+func (r *anomalyRecorder) PublishSettlementAnomaly(_ context.Context, event settlementapp.SettlementAnomalyDetected) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *anomalyRecorder) Events() []settlementapp.SettlementAnomalyDetected {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]settlementapp.SettlementAnomalyDetected(nil), r.events...)
+}
@@ -0,0 +1,87 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+)
+
+func TestSettlementCatchUp_SettlesCompletedDayWithoutSettlement(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "station-catchup"
+	settledDay := time.Date(2026, time.January, 20, 0, 0, 0, 0, time.UTC)
+	missedDay := settledDay.AddDate(0, 0, 1)
+	oldDay := settledDay.AddDate(0, 0, -10)
+	now := missedDay.AddDate(0, 0, 1).Add(time.Hour)
+
+	repo := memory.NewSettlementRepository()
+	hours := newHourEnergyStore()
+	for _, day := range []time.Time{oldDay, settledDay, missedDay} {
+		hours.SetDayEnergy(subjectID, day, 10)
+	}
+	recorder := newSettlementEventRecorder()
+	days := completedDays{subjectID: {oldDay, settledDay, missedDay}}
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, hours, fixedPrice{unit: 2}, recorder, fixedClock{now: now},
+		settlementapp.WithCompletedDays(days),
+	)
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+
+	// The first day was settled through its event, the second missed it.
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: settledDay}); err != nil {
+		t.Fatalf("settle first day: %v", err)
+	}
+	hours.SetDayEnergy(subjectID, settledDay, 99)
+
+	result, err := app.CatchUp(ctx, subjectID, 72*time.Hour)
+	if err != nil {
+		t.Fatalf("catch up: %v", err)
+	}
+	if result.DaysCompleted != 2 || result.DaysMissing != 1 || result.DaysSettled != 1 {
+		t.Fatalf("catch-up result = %+v, want 2 completed, 1 missing, 1 settled", result)
+	}
+
+	missed, err := repo.FindBySubjectAndDay(ctx, subjectID, missedDay)
+	if err != nil || missed == nil {
+		t.Fatalf("missed day settlement = %v, err %v", missed, err)
+	}
+	if missed.EnergyKWh() != 10 || missed.Amount() != 20 {
+		t.Fatalf("missed day energy=%v amount=%v, want 10 and 20", missed.EnergyKWh(), missed.Amount())
+	}
+	settled, err := repo.FindBySubjectAndDay(ctx, subjectID, settledDay)
+	if err != nil || settled == nil || settled.EnergyKWh() != 10 {
+		t.Fatalf("already settled day was recalculated: %v, err %v", settled, err)
+	}
+	if old, _ := repo.FindBySubjectAndDay(ctx, subjectID, oldDay); old != nil {
+		t.Fatalf("day outside the lookback was settled")
+	}
+	if recorder.Count() != 2 {
+		t.Fatalf("settlement events = %d, want one per new settlement", recorder.Count())
+	}
+
+	// A second pass finds nothing left to do.
+	result, err = app.CatchUp(ctx, subjectID, 72*time.Hour)
+	if err != nil {
+		t.Fatalf("second catch up: %v", err)
+	}
+	if result.DaysMissing != 0 {
+		t.Fatalf("second catch-up result = %+v, want nothing missing", result)
+	}
+}
+
+// completedDays lists the completed days of each station within the range.
+type completedDays map[string][]time.Time
+
+func (c completedDays) ListCompletedDays(_ context.Context, subjectID string, start, end time.Time) ([]time.Time, error) {
+	var days []time.Time
+	for _, day := range c[subjectID] {
+		if !day.Before(start) && day.Before(end) {
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,112 @@
+package integration_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+)
+
+func TestDaySettlement_WaitsForExpectedHours(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "station-expected-hours"
+	dayStart := time.Date(2026, time.January, 26, 0, 0, 0, 0, time.UTC)
+
+	repo := memory.NewSettlementRepository()
+	hours := &hourListStore{}
+	for hour := 0; hour < 23; hour++ {
+		hours.Add(dayStart.Add(time.Duration(hour)*time.Hour), 1)
+	}
+	events := &settlementEventRecorder{}
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, hours, fixedPrice{unit: 2}, events, fixedClock{now: dayStart.Add(25 * time.Hour)},
+		settlementapp.WithExpectedHours(24),
+	)
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+	trigger := settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}
+
+	if err := app.HandleDayEnergyCalculated(ctx, trigger); err != nil {
+		t.Fatalf("incomplete day must be deferred, not failed: %v", err)
+	}
+	if agg, _ := repo.FindBySubjectAndDay(ctx, subjectID, dayStart); agg != nil {
+		t.Fatalf("day with 23 of 24 hours settled at %v kWh", agg.EnergyKWh())
+	}
+	if events.Count() != 0 {
+		t.Fatalf("deferred day published %d settlement events", events.Count())
+	}
+
+	// A reader that reports incompleteness itself defers the day the same way.
+	hours.SetErr(fmt.Errorf("hour 23 pending: %w", settlementapp.ErrHoursIncomplete))
+	if err := app.HandleDayEnergyCalculated(ctx, trigger); err != nil {
+		t.Fatalf("reader-reported incomplete day must be deferred: %v", err)
+	}
+	hours.SetErr(nil)
+
+	hours.Add(dayStart.Add(23*time.Hour), 1)
+	if err := app.HandleDayEnergyCalculated(ctx, trigger); err != nil {
+		t.Fatalf("settle complete day: %v", err)
+	}
+	agg, err := repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+	if err != nil || agg == nil {
+		t.Fatalf("load settlement: %v, %v", agg, err)
+	}
+	if agg.EnergyKWh() != 24 || agg.Amount() != 48 {
+		t.Fatalf("settlement energy=%v amount=%v, want 24 kWh, 48", agg.EnergyKWh(), agg.Amount())
+	}
+	if events.Count() != 1 {
+		t.Fatalf("settlement events = %d, want 1", events.Count())
+	}
+}
+
+func TestDaySettlement_SettlesPartialDaysByDefault(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "station-partial-hours"
+	dayStart := time.Date(2026, time.January, 27, 0, 0, 0, 0, time.UTC)
+
+	repo := memory.NewSettlementRepository()
+	hours := &hourListStore{}
+	hours.Add(dayStart, 5)
+	app, err := settlementapp.NewDaySettlementApplicationService(repo, hours, fixedPrice{unit: 1}, nil, fixedClock{now: dayStart.Add(25 * time.Hour)})
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+	if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}); err != nil {
+		t.Fatalf("settle: %v", err)
+	}
+	if agg, _ := repo.FindBySubjectAndDay(ctx, subjectID, dayStart); agg == nil || agg.EnergyKWh() != 5 {
+		t.Fatalf("partial day settlement = %v", agg)
+	}
+}
+
+// hourListStore returns the same list of hours for any subject and day.
+type hourListStore struct {
+	mu    sync.Mutex
+	hours []settlementapp.HourEnergy
+	err   error
+}
+
+func (s *hourListStore) Add(hourStart time.Time, energy float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hours = append(s.hours, settlementapp.HourEnergy{HourStart: hourStart, EnergyKWh: energy})
+}
+
+func (s *hourListStore) SetErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *hourListStore) ListDayHourEnergy(context.Context, string, time.Time) ([]settlementapp.HourEnergy, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return append([]settlementapp.HourEnergy(nil), s.hours...), nil
+}
@@ -0,0 +1,155 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestDaySettlement_ConcurrentRecalculationsBothApply(t *testing.T) {
+	repo := memory.NewSettlementRepository()
+	dayStart := time.Date(2026, time.January, 21, 0, 0, 0, 0, time.UTC)
+
+	version := runConcurrentRecalculations(t, repo, "subject-settlement-race", dayStart)
+	if version != 3 {
+		t.Fatalf("expected version 3 after two concurrent recalculations, got %d", version)
+	}
+}
+
+func TestDaySettlement_ConcurrentRecalculationsBothApply_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "settlements_day") {
+		t.Skip("missing tables; run migrations")
+	}
+
+	tenantID := "tenant-settlement-race"
+	stationID := "station-settlement-race"
+	dayStart := time.Date(2026, time.January, 21, 0, 0, 0, 0, time.UTC)
+	_, _ = db.ExecContext(context.Background(), "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+
+	repo := settlementrepo.NewSettlementRepository(db, settlementrepo.WithTenantID(tenantID))
+	runConcurrentRecalculations(t, repo, stationID, dayStart)
+
+	row, err := loadSettlement(context.Background(), db, tenantID, stationID, dayStart)
+	if err != nil {
+		t.Fatalf("load settlement: %v", err)
+	}
+	if row.Version != 3 {
+		t.Fatalf("expected version 3 after two concurrent recalculations, got %d", row.Version)
+	}
+}
+
+// runConcurrentRecalculations settles the day once, then fires two
+// recalculations that both load the same version before either saves, and
+// returns the version of the final aggregate.
+func runConcurrentRecalculations(t *testing.T, repo settlement.Repository, subjectID string, dayStart time.Time) int {
+	t.Helper()
+	ctx := context.Background()
+
+	energy := newHourEnergyStore()
+	energy.SetDayEnergy(subjectID, dayStart, 100)
+
+	initial, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 1}, nil, settlementapp.SystemClock{})
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+	if err := initial.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: subjectID, DayStart: dayStart}); err != nil {
+		t.Fatalf("initial settlement: %v", err)
+	}
+
+	gate := newRaceGateRepository(repo, 2)
+	app, err := settlementapp.NewDaySettlementApplicationService(gate, energy, fixedPrice{unit: 1}, nil, settlementapp.SystemClock{})
+	if err != nil {
+		t.Fatalf("new app service: %v", err)
+	}
+
+	energy.SetDayEnergy(subjectID, dayStart, 120)
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{
+				SubjectID:   subjectID,
+				DayStart:    dayStart,
+				Recalculate: true,
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent recalculation: %v", err)
+		}
+	}
+
+	agg, err := repo.FindBySubjectAndDay(ctx, subjectID, dayStart)
+	if err != nil {
+		t.Fatalf("find settlement: %v", err)
+	}
+	if agg == nil {
+		t.Fatalf("settlement not found")
+	}
+	if agg.Amount() != 120 {
+		t.Fatalf("amount mismatch: got=%v want=120", agg.Amount())
+	}
+	return agg.Version()
+}
+
+// raceGateRepository holds every save until the given number of loads have
+// happened, so concurrent recalculations deterministically read the same version.
+type raceGateRepository struct {
+	settlement.Repository
+
+	mu      sync.Mutex
+	pending int
+	ready   chan struct{}
+}
+
+func newRaceGateRepository(repo settlement.Repository, loads int) *raceGateRepository {
+	return &raceGateRepository{Repository: repo, pending: loads, ready: make(chan struct{})}
+}
+
+func (g *raceGateRepository) FindBySubjectAndDay(ctx context.Context, subjectID string, dayStart time.Time) (*settlement.SettlementAggregate, error) {
+	agg, err := g.Repository.FindBySubjectAndDay(ctx, subjectID, dayStart)
+	g.mu.Lock()
+	if g.pending > 0 {
+		g.pending--
+		if g.pending == 0 {
+			close(g.ready)
+		}
+	}
+	g.mu.Unlock()
+	return agg, err
+}
+
+func (g *raceGateRepository) Save(ctx context.Context, aggregate *settlement.SettlementAggregate) error {
+	select {
+	case <-g.ready:
+	case <-time.After(5 * time.Second):
+		return context.DeadlineExceeded
+	}
+	return g.Repository.Save(ctx, aggregate)
+}
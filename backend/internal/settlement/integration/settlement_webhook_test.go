@@ -0,0 +1,124 @@
+package integration_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/eventing"
+	appsettlement "microgrid-cloud/internal/settlement/application"
+	"microgrid-cloud/internal/settlement/infrastructure/memory"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+func TestSettlementWebhook_DeliversSignedPayload(t *testing.T) {
+	ctx := context.Background()
+	subjectID := "subject-webhook-001"
+	dayStart := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	var mu sync.Mutex
+	var deliveries [][]byte
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		// The first attempt fails so the delivery is retried.
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		mac := hmac.New(sha256.New, []byte("hook-secret"))
+		_, _ = mac.Write([]byte(r.Header.Get("X-Settlement-Timestamp") + "\n"))
+		_, _ = mac.Write(body)
+		if r.Header.Get("X-Settlement-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		deliveries = append(deliveries, body)
+	}))
+	defer server.Close()
+
+	webhook, err := settlementinterfaces.NewSettlementWebhook(server.URL,
+		settlementinterfaces.WithWebhookSecret("hook-secret"),
+		settlementinterfaces.WithWebhookRetry(3, time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("new settlement webhook: %v", err)
+	}
+
+	energyStore := newHourEnergyStore()
+	energyStore.SetDayEnergy(subjectID, dayStart, 80)
+	app := newDaySettlementAppService(t, memory.NewSettlementRepository(), energyStore, fixedPrice{unit: 1.5},
+		webhookPublisher{webhook: webhook}, fixedClock{now: dayStart.Add(26 * time.Hour)})
+	err = app.HandleDayEnergyCalculated(ctx, appsettlement.DayEnergyCalculated{
+		SubjectID:  subjectID,
+		DayStart:   dayStart,
+		OccurredAt: dayStart.Add(25 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("handle day settlement: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 2 || len(deliveries) != 1 {
+		t.Fatalf("calls = %d, signed deliveries = %d; want 2 and 1", calls, len(deliveries))
+	}
+	var payload struct {
+		Event       string    `json:"event"`
+		EventID     string    `json:"event_id"`
+		TenantID    string    `json:"tenant_id"`
+		StationID   string    `json:"station_id"`
+		DayStart    time.Time `json:"day_start"`
+		Amount      float64   `json:"amount"`
+		Recalculate bool      `json:"recalculate"`
+	}
+	if err := json.Unmarshal(deliveries[0], &payload); err != nil {
+		t.Fatalf("decode payload: %v (%s)", err, deliveries[0])
+	}
+	if payload.Event != settlementinterfaces.SettlementWebhookEvent || payload.EventID != "evt-webhook-1" || payload.TenantID != "tenant-webhook" {
+		t.Fatalf("payload envelope = %+v", payload)
+	}
+	if payload.StationID != subjectID || !payload.DayStart.Equal(dayStart) || payload.Amount != 120 || payload.Recalculate {
+		t.Fatalf("payload settlement = %+v", payload)
+	}
+}
+
+func TestSettlementWebhook_ClientErrorIsNotRetried(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	webhook, err := settlementinterfaces.NewSettlementWebhook(server.URL, settlementinterfaces.WithWebhookRetry(3, time.Millisecond))
+	if err != nil {
+		t.Fatalf("new settlement webhook: %v", err)
+	}
+	err = webhook.HandleSettlementCalculated(context.Background(), appsettlement.SettlementCalculated{SubjectID: "subject-webhook-002"})
+	if err == nil || calls != 1 {
+		t.Fatalf("err = %v, calls = %d; want an error after 1 call", err, calls)
+	}
+}
+
+// webhookPublisher hands settlement events to the webhook the way the outbox
+// dispatcher does, with the event envelope in the context.
+type webhookPublisher struct {
+	webhook *settlementinterfaces.SettlementWebhook
+}
+
+func (p webhookPublisher) PublishSettlementCalculated(ctx context.Context, event appsettlement.SettlementCalculated) error {
+	ctx = eventing.WithEnvelope(ctx, eventing.Envelope{EventID: "evt-webhook-1", TenantID: "tenant-webhook"})
+	return p.webhook.HandleSettlementCalculated(ctx, event)
+}
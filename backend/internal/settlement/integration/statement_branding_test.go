@@ -0,0 +1,172 @@
+package integration_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestBrandedStatementRenderer_IncludesBranding(t *testing.T) {
+	logoPath := filepath.Join(t.TempDir(), "logo.png")
+	writeTestLogo(t, logoPath)
+
+	stmt, items := sampleStatement()
+	renderer := settlementinterfaces.NewBrandedStatementRenderer(settlement.StatementBranding{
+		TenantID:    "tenant-branded",
+		CompanyName: "Acme Microgrid Ltd",
+		LogoPath:    logoPath,
+		FooterText:  "Acme Microgrid Ltd - billing@acme.example",
+	})
+
+	pdfData, err := renderer.RenderPDF(stmt, items)
+	if err != nil {
+		t.Fatalf("render pdf: %v", err)
+	}
+	if !bytes.Contains(pdfData, []byte("Acme Microgrid Ltd")) {
+		t.Fatalf("branded pdf does not mention the company name")
+	}
+
+	xlsxData, err := renderer.RenderXLSX(stmt, items)
+	if err != nil {
+		t.Fatalf("render xlsx: %v", err)
+	}
+	assertSummaryCell(t, xlsxData, "A2", "Acme Microgrid Ltd")
+	assertSummaryCell(t, xlsxData, "A13", "Acme Microgrid Ltd - billing@acme.example")
+
+	defaultPDF, err := settlementinterfaces.DefaultStatementRenderer{}.RenderPDF(stmt, items)
+	if err != nil {
+		t.Fatalf("render default pdf: %v", err)
+	}
+	if bytes.Contains(defaultPDF, []byte("Acme Microgrid Ltd")) {
+		t.Fatalf("default pdf must stay unbranded")
+	}
+}
+
+func TestStatementExport_UsesTenantBranding(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-branding"
+	stationID := "station-stmt-branding"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO statement_branding (tenant_id, company_name, footer_text)
+VALUES ($1, 'Branded Energy Co', 'Thank you for your business')
+ON CONFLICT (tenant_id) DO UPDATE SET company_name = EXCLUDED.company_name, footer_text = EXCLUDED.footer_text`, tenantID); err != nil {
+		t.Fatalf("seed branding: %v", err)
+	}
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{10}, []float64{5}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	stmt, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	handler, err := settlementinterfaces.NewStatementHandler(stmtService, nil, nil,
+		settlementinterfaces.WithBrandingResolver(settlementrepo.NewBrandingRepository(db)))
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+stmt.ID+"/export.xlsx", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("xlsx status %d", resp.Code)
+	}
+	assertSummaryCell(t, resp.Body.Bytes(), "A2", "Branded Energy Co")
+	assertSummaryCell(t, resp.Body.Bytes(), "A13", "Thank you for your business")
+}
+
+func sampleStatement() (*settlement.StatementAggregate, []settlement.StatementItem) {
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	stmt := &settlement.StatementAggregate{
+		ID:             "stmt-branding",
+		TenantID:       "tenant-branded",
+		StationID:      "station-branded",
+		StatementMonth: monthStart,
+		Category:       "owner",
+		Status:         settlement.StatementStatusDraft,
+		Version:        1,
+		TotalEnergyKWh: 10,
+		TotalAmount:    5,
+		Currency:       "CNY",
+		CreatedAt:      monthStart,
+	}
+	items := []settlement.StatementItem{{StatementID: stmt.ID, DayStart: monthStart, EnergyKWh: 10, Amount: 5, Currency: "CNY"}}
+	return stmt, items
+}
+
+func writeTestLogo(t *testing.T, path string) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 4; y++ {
+			img.Set(x, y, color.RGBA{R: 0, G: 128, B: 255, A: 255})
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create logo: %v", err)
+	}
+	defer file.Close()
+	if err := png.Encode(file, img); err != nil {
+		t.Fatalf("encode logo: %v", err)
+	}
+}
+
+func assertSummaryCell(t *testing.T, data []byte, cell, want string) {
+	t.Helper()
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("open xlsx: %v", err)
+	}
+	defer f.Close()
+	got, err := f.GetCellValue("summary", cell)
+	if err != nil {
+		t.Fatalf("read %s: %v", cell, err)
+	}
+	if got != want {
+		t.Fatalf("summary %s = %q, want %q", cell, got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestMissingSettlementDays(t *testing.T) {
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	afterMonth := monthStart.AddDate(0, 2, 0)
+
+	var items []settlement.StatementItem
+	for day := 0; day < 28; day++ {
+		items = append(items, settlement.StatementItem{DayStart: monthStart.AddDate(0, 0, day)})
+	}
+	if missing := settlement.MissingSettlementDays(monthStart, monthEnd, afterMonth, items); len(missing) != 0 {
+		t.Fatalf("complete month reported missing days: %v", missing)
+	}
+
+	incomplete := append(append([]settlement.StatementItem(nil), items[:9]...), items[11:]...)
+	missing := settlement.MissingSettlementDays(monthStart, monthEnd, afterMonth, incomplete)
+	if len(missing) != 2 || !missing[0].Equal(monthStart.AddDate(0, 0, 9)) || !missing[1].Equal(monthStart.AddDate(0, 0, 10)) {
+		t.Fatalf("missing = %v", missing)
+	}
+
+	// In the current month only days before as-of are expected.
+	asOf := monthStart.AddDate(0, 0, 5).Add(13 * time.Hour)
+	if missing := settlement.MissingSettlementDays(monthStart, monthEnd, asOf, items[:5]); len(missing) != 0 {
+		t.Fatalf("current month reported future days missing: %v", missing)
+	}
+}
+
+func TestStatement_FlagsIncompleteMonthAsPartial(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-complete"
+	completeStation := "station-stmt-complete"
+	partialStation := "station-stmt-partial"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	energy := make([]float64, 28)
+	amount := make([]float64, 28)
+	for i := range energy {
+		energy[i], amount[i] = 10, 5
+	}
+	for _, stationID := range []string{completeStation, partialStation} {
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+	}
+	if err := seedSettlementsDay(ctx, db, tenantID, completeStation, monthStart, energy, amount); err != nil {
+		t.Fatalf("seed complete month: %v", err)
+	}
+	// Partial station: days 1-20 only.
+	if err := seedSettlementsDay(ctx, db, tenantID, partialStation, monthStart, energy[:20], amount[:20]); err != nil {
+		t.Fatalf("seed partial month: %v", err)
+	}
+
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+
+	complete, err := stmtService.Generate(ctx, completeStation, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate complete: %v", err)
+	}
+	if complete.Partial || len(complete.MissingDays) != 0 {
+		t.Fatalf("complete month flagged partial: %+v", complete.MissingDays)
+	}
+
+	partial, err := stmtService.Generate(ctx, partialStation, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate partial: %v", err)
+	}
+	if !partial.Partial || len(partial.MissingDays) != 8 {
+		t.Fatalf("expected partial with 8 missing days, got partial=%t days=%v", partial.Partial, partial.MissingDays)
+	}
+
+	stored, _, err := stmtService.Get(ctx, partial.ID)
+	if err != nil {
+		t.Fatalf("get partial: %v", err)
+	}
+	if !stored.Partial || len(stored.MissingDays) != 8 || !stored.MissingDays[0].Equal(monthStart.AddDate(0, 0, 20)) {
+		t.Fatalf("stored statement lost partial flag: partial=%t days=%v", stored.Partial, stored.MissingDays)
+	}
+}
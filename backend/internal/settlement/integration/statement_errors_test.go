@@ -0,0 +1,152 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/auth"
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+// statementFaultMode selects how the statement-fault-stub driver answers:
+// "missing" finds no statement, "down" fails like an unreachable database,
+// "stale" serves stubbedStatement but updates match no row, anything else
+// serves stubbedStatement.
+var statementFaultMode string
+
+func init() {
+	sql.Register("statement-fault-stub", statementFaultDriver{})
+}
+
+func TestStatementHandler_MapsServiceErrorKinds(t *testing.T) {
+	db := openStatementFaultDB(t)
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-errors")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+	now := time.Date(2026, time.February, 3, 8, 0, 0, 0, time.UTC)
+	stubbedStatement = settlement.StatementAggregate{
+		ID:             "stmt-errors",
+		TenantID:       "tenant-errors",
+		StationID:      "station-errors",
+		StatementMonth: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Category:       "owner",
+		Status:         settlement.StatementStatusVoided,
+		Version:        1,
+		Currency:       "CNY",
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	cases := []struct {
+		name     string
+		mode     string
+		tenantID string
+		method   string
+		target   string
+		body     string
+		want     int
+	}{
+		{"validation", "", "", http.MethodPost, "/api/v1/statements/generate", `{"station_id":"station-errors","month":"January"}`, http.StatusBadRequest},
+		{"month 13", "", "", http.MethodPost, "/api/v1/statements/generate", `{"station_id":"station-errors","month":"2026-13"}`, http.StatusBadRequest},
+		{"list month 13", "", "", http.MethodGet, "/api/v1/statements?station_id=station-errors&month=2026-13", "", http.StatusBadRequest},
+		{"not found", "missing", "", http.MethodGet, "/api/v1/statements/stmt-errors", "", http.StatusNotFound},
+		{"conflict", "", "", http.MethodPost, "/api/v1/statements/stmt-errors/freeze", "", http.StatusConflict},
+		{"internal", "down", "", http.MethodGet, "/api/v1/statements/stmt-errors", "", http.StatusInternalServerError},
+		{"tenant mismatch", "", "tenant-other", http.MethodGet, "/api/v1/statements/stmt-errors", "", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			statementFaultMode = tc.mode
+			defer func() { statementFaultMode = "" }()
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.tenantID != "" {
+				req = req.WithContext(auth.WithIdentity(req.Context(), tc.tenantID, auth.RoleViewer, "it-viewer"))
+			}
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			if resp.Code != tc.want {
+				t.Fatalf("status = %d, want %d (body %q)", resp.Code, tc.want, resp.Body.String())
+			}
+			if tc.want == http.StatusInternalServerError && strings.Contains(resp.Body.String(), "connection refused") {
+				t.Fatalf("internal error leaked its cause: %q", resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestServiceError_MatchesKindAndCause(t *testing.T) {
+	db := openStatementFaultDB(t)
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-errors")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+
+	statementFaultMode = "down"
+	defer func() { statementFaultMode = "" }()
+	_, _, err = service.Get(context.Background(), "stmt-errors")
+	if !errors.Is(err, settlementapp.ErrInternal) || !errors.Is(err, errStatementDBDown) {
+		t.Fatalf("expected internal error wrapping the driver error, got %v", err)
+	}
+	if errors.Is(err, settlementapp.ErrNotFound) || errors.Is(err, settlementapp.ErrValidation) {
+		t.Fatalf("error matches more than one kind: %v", err)
+	}
+}
+
+func openStatementFaultDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("statement-fault-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+var errStatementDBDown = errors.New("dial tcp 127.0.0.1:5432: connection refused")
+
+// statementFaultDriver wraps the statement-stub connection with the failure
+// modes of statementFaultMode.
+type statementFaultDriver struct{}
+
+func (statementFaultDriver) Open(string) (driver.Conn, error) {
+	return statementFaultConn{}, nil
+}
+
+type statementFaultConn struct {
+	statementStubConn
+}
+
+func (c statementFaultConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	switch statementFaultMode {
+	case "down":
+		return nil, errStatementDBDown
+	case "missing":
+		return &statementStubRows{}, nil
+	}
+	return c.statementStubConn.QueryContext(ctx, query, args)
+}
+
+func (c statementFaultConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	switch statementFaultMode {
+	case "down":
+		return nil, errStatementDBDown
+	case "stale":
+		return driver.RowsAffected(0), nil
+	}
+	return c.statementStubConn.ExecContext(ctx, query, args)
+}
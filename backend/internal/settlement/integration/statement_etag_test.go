@@ -0,0 +1,189 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+// stubbedStatement is the row served by the statement-stub driver.
+var stubbedStatement settlement.StatementAggregate
+
+// stubbedExecs records the arguments of every statement-stub exec.
+var stubbedExecs execLog
+
+func init() {
+	sql.Register("statement-stub", statementStubDriver{})
+}
+
+func TestStatementGet_ETagConditionalRequests(t *testing.T) {
+	db, err := sql.Open("statement-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-etag")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		return resp
+	}
+
+	updatedAt := time.Date(2026, time.February, 3, 8, 0, 0, 0, time.UTC)
+	stubbedStatement = settlement.StatementAggregate{
+		ID:             "stmt-etag",
+		TenantID:       "tenant-etag",
+		StationID:      "station-etag",
+		StatementMonth: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Category:       "owner",
+		Status:         settlement.StatementStatusDraft,
+		Version:        1,
+		Currency:       "CNY",
+		CreatedAt:      updatedAt,
+		UpdatedAt:      updatedAt,
+	}
+
+	first := get("/api/v1/statements/stmt-etag", "")
+	draftTag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || draftTag == "" {
+		t.Fatalf("first get = %d, etag %q", first.Code, draftTag)
+	}
+	if resp := get("/api/v1/statements/stmt-etag", draftTag); resp.Code != http.StatusNotModified || resp.Body.Len() != 0 {
+		t.Fatalf("matching etag = %d with %d body bytes, want empty 304", resp.Code, resp.Body.Len())
+	}
+	if resp := get("/api/v1/statements/stmt-etag", `"other", W/`+draftTag); resp.Code != http.StatusNotModified {
+		t.Fatalf("etag list with weak match = %d, want 304", resp.Code)
+	}
+	if resp := get("/api/v1/statements/stmt-etag", `"stale"`); resp.Code != http.StatusOK {
+		t.Fatalf("non-matching etag = %d, want 200", resp.Code)
+	}
+
+	// Freezing changes the representation, so the draft tag no longer matches.
+	stubbedStatement.Status = settlement.StatementStatusFrozen
+	stubbedStatement.SnapshotHash = "sha256:abc123"
+	stubbedStatement.UpdatedAt = updatedAt.Add(time.Hour)
+	frozen := get("/api/v1/statements/stmt-etag", draftTag)
+	frozenTag := frozen.Header().Get("ETag")
+	if frozen.Code != http.StatusOK || frozenTag == draftTag || !strings.Contains(frozenTag, "abc123") {
+		t.Fatalf("frozen get = %d, etag %q", frozen.Code, frozenTag)
+	}
+
+	// Exports carry their own tag per format.
+	pdf := get("/api/v1/statements/stmt-etag/export.pdf", frozenTag)
+	pdfTag := pdf.Header().Get("ETag")
+	if pdf.Code != http.StatusOK || pdfTag == frozenTag {
+		t.Fatalf("pdf with json etag = %d, etag %q", pdf.Code, pdfTag)
+	}
+	if resp := get("/api/v1/statements/stmt-etag/export.pdf", pdfTag); resp.Code != http.StatusNotModified || resp.Body.Len() != 0 {
+		t.Fatalf("pdf with matching etag = %d, want empty 304", resp.Code)
+	}
+}
+
+// statementStubDriver serves stubbedStatement for statement lookups and no
+// statement items; execs are recorded in stubbedExecs and affect one row.
+type statementStubDriver struct{}
+
+func (statementStubDriver) Open(string) (driver.Conn, error) {
+	return statementStubConn{}, nil
+}
+
+type statementStubConn struct{}
+
+func (statementStubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	if strings.Contains(query, "FROM settlement_statements") {
+		stmt := stubbedStatement
+		var snapshot driver.Value
+		if stmt.SnapshotHash != "" {
+			snapshot = stmt.SnapshotHash
+		}
+		return &statementStubRows{rows: [][]driver.Value{{
+			stmt.ID, stmt.TenantID, stmt.StationID, stmt.StatementMonth, stmt.Category, stmt.Status, int64(stmt.Version),
+			stmt.TotalEnergyKWh, stmt.TotalAmount, stmt.Currency, snapshot, nil,
+			stmt.CreatedAt, stmt.UpdatedAt, nil, nil, stmt.Partial, nil, nil, nil,
+		}}}, nil
+	}
+	return &statementStubRows{}, nil
+}
+
+func (statementStubConn) ExecContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Result, error) {
+	stubbedExecs.Record(args)
+	return driver.RowsAffected(1), nil
+}
+
+func (statementStubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("statement stub: prepare not supported")
+}
+
+func (statementStubConn) Close() error { return nil }
+
+func (statementStubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("statement stub: transactions not supported")
+}
+
+type execLog struct {
+	mu   sync.Mutex
+	args [][]driver.NamedValue
+}
+
+func (l *execLog) Record(args []driver.NamedValue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.args = append(l.args, args)
+}
+
+// Last returns the arguments of the latest exec.
+func (l *execLog) Last() []driver.NamedValue {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.args) == 0 {
+		return nil
+	}
+	return l.args[len(l.args)-1]
+}
+
+type statementStubRows struct {
+	rows [][]driver.Value
+}
+
+func (r *statementStubRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *statementStubRows) Close() error { return nil }
+
+func (r *statementStubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,98 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestBuildStatementHTML_EscapesAndBrands(t *testing.T) {
+	stmt, items := sampleStatement()
+	stmt.StationID = "<script>alert(1)</script>"
+
+	data, err := settlementinterfaces.NewBrandedStatementRenderer(settlement.StatementBranding{
+		CompanyName: "Acme & Sons",
+		FooterText:  "Questions? billing@acme.example",
+	}).RenderHTML(stmt, items)
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	page := string(data)
+	if strings.Contains(page, "<script>") {
+		t.Fatalf("station id not escaped: %s", page)
+	}
+	for _, want := range []string{"Acme &amp; Sons", "Questions? billing@acme.example", "2026-02-01", "10.000", "5.00"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("html missing %q", want)
+		}
+	}
+}
+
+func TestStatementExport_HTMLPreview(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-html"
+	stationID := "station-stmt-html"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{12.5, 7.5}, []float64{6.25, 3.75}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	stmt, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	handler, err := settlementinterfaces.NewStatementHandler(stmtService, nil, nil)
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+stmt.ID+"/export.html", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("html status %d", resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Fatalf("content-type = %q", ct)
+	}
+	page := resp.Body.String()
+	for _, want := range []string{stationID, "2026-02", "owner", "20.000", "10.00", "2026-02-01", "2026-02-02", "12.500", "3.75"} {
+		if !strings.Contains(page, want) {
+			t.Fatalf("html preview missing %q", want)
+		}
+	}
+}
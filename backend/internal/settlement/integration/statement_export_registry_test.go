@@ -0,0 +1,120 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func buildFakeExport(_ settlementinterfaces.StatementRenderer, stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return []byte(fmt.Sprintf("%s,%d", stmt.StationID, len(items))), nil
+}
+
+func TestExportRegistry_DefaultsAndRegistration(t *testing.T) {
+	registry := settlementinterfaces.DefaultExportRegistry()
+	for format, contentType := range map[string]string{
+		"pdf":  "application/pdf",
+		"xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"html": "text/html; charset=utf-8",
+	} {
+		export, ok := registry.Lookup(format)
+		if !ok || export.ContentType != contentType {
+			t.Fatalf("default %s export = %+v, %t", format, export, ok)
+		}
+	}
+	if _, ok := registry.Lookup("fake"); ok {
+		t.Fatalf("fake format must not be registered by default")
+	}
+
+	registry.Register("fake", "text/x-fake", buildFakeExport)
+	export, ok := registry.Lookup("fake")
+	if !ok || export.ContentType != "text/x-fake" {
+		t.Fatalf("fake export = %+v, %t", export, ok)
+	}
+	stmt, items := sampleStatement()
+	data, err := export.Build(settlementinterfaces.DefaultStatementRenderer{}, stmt, items)
+	if err != nil || string(data) != "station-branded,1" {
+		t.Fatalf("fake build = %q, %v", data, err)
+	}
+}
+
+func TestStatementExport_ServesRegisteredFormat(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-stmt-export-fmt"
+	stationID := "station-stmt-export-fmt"
+	monthStart := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE station_id = $1)", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE station_id = $1", stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+	if err := seedSettlementsDay(ctx, db, tenantID, stationID, monthStart, []float64{10, 20}, []float64{5, 10}); err != nil {
+		t.Fatalf("seed settlements: %v", err)
+	}
+
+	stmtService, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID)
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	stmt, err := stmtService.Generate(ctx, stationID, "2026-02", "owner", false)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	handler, err := settlementinterfaces.NewStatementHandler(stmtService, nil, nil,
+		settlementinterfaces.WithExportFormat("fake", "text/x-fake", buildFakeExport))
+	if err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+stmt.ID+"/export.fake", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("fake export status %d", resp.Code)
+	}
+	if resp.Header().Get("Content-Type") != "text/x-fake" {
+		t.Fatalf("content-type = %q", resp.Header().Get("Content-Type"))
+	}
+	if got := resp.Body.String(); got != stationID+",2" {
+		t.Fatalf("body = %q", got)
+	}
+
+	// Built-in formats stay available next to the registered one.
+	pdfResp := httptest.NewRecorder()
+	handler.ServeHTTP(pdfResp, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+stmt.ID+"/export.pdf", nil))
+	if pdfResp.Code != http.StatusOK {
+		t.Fatalf("pdf status %d", pdfResp.Code)
+	}
+
+	unknown := httptest.NewRecorder()
+	handler.ServeHTTP(unknown, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+stmt.ID+"/export.doc", nil))
+	if unknown.Code != http.StatusNotFound {
+		t.Fatalf("unregistered format status %d, want 404", unknown.Code)
+	}
+}
@@ -142,6 +142,10 @@ func applyStatementMigrations(db *sql.DB) error {
 	files := []string{
 		filepath.Join(root, "migrations", "002_settlement.sql"),
 		filepath.Join(root, "migrations", "008_statements.sql"),
+		filepath.Join(root, "migrations", "021_statement_partial.sql"),
+		filepath.Join(root, "migrations", "022_statement_branding.sql"),
+		filepath.Join(root, "migrations", "025_tenant_settings.sql"),
+		filepath.Join(root, "migrations", "030_billing_cycle.sql"),
 	}
 	for _, path := range files {
 		content, err := os.ReadFile(path)
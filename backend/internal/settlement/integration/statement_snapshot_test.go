@@ -0,0 +1,125 @@
+package integration_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+func snapshotFixture() (*settlement.StatementAggregate, []settlement.StatementItem) {
+	monthStart := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	stmt := &settlement.StatementAggregate{
+		ID:             "stmt-snapshot",
+		TenantID:       "tenant-snapshot",
+		StationID:      "station-snapshot",
+		StatementMonth: monthStart,
+		Category:       "owner",
+		Status:         settlement.StatementStatusDraft,
+		Version:        2,
+		TotalEnergyKWh: 30.1,
+		TotalAmount:    15.05,
+		Currency:       "CNY",
+		CreatedAt:      time.Now(),
+	}
+	items := []settlement.StatementItem{
+		{StatementID: stmt.ID, DayStart: monthStart, EnergyKWh: 10, Amount: 5, Currency: "CNY"},
+		{StatementID: stmt.ID, DayStart: monthStart.AddDate(0, 0, 1), EnergyKWh: 20.1, Amount: 10.05, Currency: "CNY"},
+	}
+	return stmt, items
+}
+
+func TestCanonicalSnapshot_Format(t *testing.T) {
+	stmt, items := snapshotFixture()
+	want := strings.Join([]string{
+		"settlement-statement-snapshot/v1",
+		"id=stmt-snapshot",
+		"tenant_id=tenant-snapshot",
+		"station_id=station-snapshot",
+		"statement_month=2026-03",
+		"category=owner",
+		"version=2",
+		"currency=CNY",
+		"total_energy_kwh=30.100000",
+		"total_amount=15.050000",
+		"item=2026-03-01|10.000000|5.000000|CNY",
+		"item=2026-03-02|20.100000|10.050000|CNY",
+	}, "\n") + "\n"
+	if got := string(settlement.CanonicalSnapshot(stmt, items)); got != want {
+		t.Fatalf("canonical snapshot:\n%s\nwant:\n%s", got, want)
+	}
+
+	hash, err := settlement.ComputeSnapshotHash(settlement.SnapshotSHA256, stmt, items)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	digest := sha256.Sum256([]byte(want))
+	if hash != "sha256:"+hex.EncodeToString(digest[:]) {
+		t.Fatalf("hash %s is not sha256 of the canonical snapshot", hash)
+	}
+}
+
+func TestComputeSnapshotHash_IgnoresItemOrderAndMutableFields(t *testing.T) {
+	stmt, items := snapshotFixture()
+	reversed := []settlement.StatementItem{items[1], items[0]}
+
+	first, err := settlement.ComputeSnapshotHash(settlement.DefaultSnapshotAlgorithm, stmt, items)
+	if err != nil {
+		t.Fatalf("hash: %v", err)
+	}
+	second, err := settlement.ComputeSnapshotHash(settlement.DefaultSnapshotAlgorithm, stmt, reversed)
+	if err != nil {
+		t.Fatalf("hash reversed: %v", err)
+	}
+	if first != second {
+		t.Fatalf("hash depends on item order: %s != %s", first, second)
+	}
+	if !reversed[0].DayStart.After(reversed[1].DayStart) {
+		t.Fatalf("hashing must not reorder the caller's items")
+	}
+
+	frozen := *stmt
+	frozen.Status = settlement.StatementStatusFrozen
+	frozen.FrozenAt = time.Now()
+	frozen.UpdatedAt = frozen.FrozenAt
+	if again, _ := settlement.ComputeSnapshotHash(settlement.DefaultSnapshotAlgorithm, &frozen, items); again != first {
+		t.Fatalf("hash changed after freezing: %s != %s", again, first)
+	}
+
+	changed := append([]settlement.StatementItem(nil), items...)
+	changed[1].Amount = 10.06
+	if other, _ := settlement.ComputeSnapshotHash(settlement.DefaultSnapshotAlgorithm, stmt, changed); other == first {
+		t.Fatalf("hash must change with item amounts")
+	}
+}
+
+func TestComputeSnapshotHash_Algorithms(t *testing.T) {
+	stmt, items := snapshotFixture()
+	stmt.TotalAmount = math.Copysign(0, -1)
+	if !strings.Contains(string(settlement.CanonicalSnapshot(stmt, items)), "total_amount=0.000000\n") {
+		t.Fatalf("negative zero must be written as zero")
+	}
+
+	hash, err := settlement.ComputeSnapshotHash(settlement.SnapshotSHA512, stmt, items)
+	if err != nil {
+		t.Fatalf("sha512 hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "sha512:") || len(hash) != len("sha512:")+128 {
+		t.Fatalf("sha512 hash = %s", hash)
+	}
+
+	if algorithm, err := settlement.ParseSnapshotAlgorithm(""); err != nil || algorithm != settlement.SnapshotSHA256 {
+		t.Fatalf("default algorithm = %q, %v", algorithm, err)
+	}
+	if _, err := settlement.ParseSnapshotAlgorithm("md5"); !errors.Is(err, settlement.ErrUnknownSnapshotAlgorithm) {
+		t.Fatalf("md5 must be rejected, got %v", err)
+	}
+	if _, err := settlement.ComputeSnapshotHash("md5", stmt, items); !errors.Is(err, settlement.ErrUnknownSnapshotAlgorithm) {
+		t.Fatalf("md5 hash must be rejected, got %v", err)
+	}
+}
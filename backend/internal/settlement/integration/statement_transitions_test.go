@@ -0,0 +1,91 @@
+package integration_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+// The statement state machine: draft -> frozen, draft -> voided and
+// frozen -> voided (a superseded version); voided is final.
+func TestStatementTransitions_GuardFreezeAndVoid(t *testing.T) {
+	db := openStatementFaultDB(t)
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-transitions")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		status string
+		mode   string
+		action string
+		want   int
+	}{
+		{"freeze draft", settlement.StatementStatusDraft, "", "freeze", http.StatusOK},
+		{"void draft", settlement.StatementStatusDraft, "", "void", http.StatusOK},
+		{"double freeze", settlement.StatementStatusFrozen, "", "freeze", http.StatusConflict},
+		{"void after freeze", settlement.StatementStatusFrozen, "", "void", http.StatusOK},
+		{"freeze after void", settlement.StatementStatusVoided, "", "freeze", http.StatusConflict},
+		{"double void", settlement.StatementStatusVoided, "", "void", http.StatusConflict},
+		{"freeze changed concurrently", settlement.StatementStatusDraft, "stale", "freeze", http.StatusConflict},
+		{"void changed concurrently", settlement.StatementStatusFrozen, "stale", "void", http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stubbedStatement = transitionStatement(tc.status)
+			statementFaultMode = tc.mode
+			defer func() { statementFaultMode = "" }()
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/statements/stmt-transitions/"+tc.action, strings.NewReader(`{"reason":"superseded"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			if resp.Code != tc.want {
+				t.Fatalf("%s of %s statement = %d, want %d (body %q)", tc.action, tc.status, resp.Code, tc.want, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestCanTransitionStatement(t *testing.T) {
+	statuses := []string{settlement.StatementStatusDraft, settlement.StatementStatusFrozen, settlement.StatementStatusVoided}
+	allowed := map[[2]string]bool{
+		{settlement.StatementStatusDraft, settlement.StatementStatusFrozen}:  true,
+		{settlement.StatementStatusDraft, settlement.StatementStatusVoided}:  true,
+		{settlement.StatementStatusFrozen, settlement.StatementStatusVoided}: true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got := settlement.CanTransitionStatement(from, to); got != allowed[[2]string{from, to}] {
+				t.Fatalf("CanTransitionStatement(%s, %s) = %v", from, to, got)
+			}
+		}
+	}
+}
+
+func transitionStatement(status string) settlement.StatementAggregate {
+	updatedAt := time.Date(2026, time.February, 3, 8, 0, 0, 0, time.UTC)
+	return settlement.StatementAggregate{
+		ID:             "stmt-transitions",
+		TenantID:       "tenant-transitions",
+		StationID:      "station-transitions",
+		StatementMonth: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Category:       "owner",
+		Status:         status,
+		Version:        1,
+		Currency:       "CNY",
+		CreatedAt:      updatedAt,
+		UpdatedAt:      updatedAt,
+	}
+}
@@ -0,0 +1,282 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
+)
+
+var voidImpactMonth = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// recordedExports holds the statement_exports rows inserted through the
+// statement-export-record-stub driver.
+var recordedExports exportRecordLog
+
+func init() {
+	sql.Register("statement-void-impact-stub", voidImpactDriver{})
+	sql.Register("statement-export-record-stub", exportRecordDriver{})
+}
+
+func TestStatementVoidImpact_ReportsVersionsAndExports(t *testing.T) {
+	db, err := sql.Open("statement-void-impact-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-void")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+	get := func(id string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/statements/"+id+"/void-impact", nil))
+		return resp
+	}
+
+	resp := get("stmt-void-v1")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("void impact = %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		StatementID        string  `json:"statement_id"`
+		Status             string  `json:"status"`
+		TotalAmount        float64 `json:"total_amount"`
+		Currency           string  `json:"currency"`
+		CanVoid            bool    `json:"can_void"`
+		NewerVersionExists bool    `json:"newer_version_exists"`
+		NewerVersions      []struct {
+			StatementID string `json:"statement_id"`
+			Version     int    `json:"version"`
+			Status      string `json:"status"`
+		} `json:"newer_versions"`
+		Exports []struct {
+			Format    string `json:"format"`
+			PathOrKey string `json:"path_or_key"`
+		} `json:"exports"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.StatementID != "stmt-void-v1" || body.Status != "frozen" || body.TotalAmount != 120.5 || body.Currency != "CNY" || !body.CanVoid {
+		t.Fatalf("statement fields = %+v", body)
+	}
+	if !body.NewerVersionExists || len(body.NewerVersions) != 1 || body.NewerVersions[0].StatementID != "stmt-void-v2" || body.NewerVersions[0].Version != 2 {
+		t.Fatalf("newer versions = %+v", body.NewerVersions)
+	}
+	if len(body.Exports) != 2 || body.Exports[0].Format != "pdf" || body.Exports[1].PathOrKey != "" {
+		t.Fatalf("exports = %+v", body.Exports)
+	}
+
+	// The latest version has nothing newer and no exports; a voided one
+	// cannot be voided again.
+	resp = get("stmt-void-v2")
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode v2: %v", err)
+	}
+	if body.NewerVersionExists || len(body.NewerVersions) != 0 || len(body.Exports) != 0 || body.CanVoid {
+		t.Fatalf("v2 impact = %+v", body)
+	}
+
+	if resp := get("stmt-missing"); resp.Code != http.StatusNotFound {
+		t.Fatalf("missing statement = %d, want 404", resp.Code)
+	}
+}
+
+func TestStatementVoidImpact_ListsDownloadedExports(t *testing.T) {
+	db, err := sql.Open("statement-export-record-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), "tenant-void")
+	if err != nil {
+		t.Fatalf("statement service: %v", err)
+	}
+	handler, err := settlementinterfaces.NewStatementHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("statement handler: %v", err)
+	}
+	get := func(path string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, path, nil))
+		return resp
+	}
+
+	for _, format := range []string{"pdf", "xlsx"} {
+		if resp := get("/api/v1/statements/stmt-void-v1/export." + format); resp.Code != http.StatusOK {
+			t.Fatalf("export %s = %d: %s", format, resp.Code, resp.Body.String())
+		}
+	}
+	// A failed download is not an export.
+	if resp := get("/api/v1/statements/stmt-missing/export.pdf"); resp.Code != http.StatusNotFound {
+		t.Fatalf("missing statement export = %d, want 404", resp.Code)
+	}
+
+	resp := get("/api/v1/statements/stmt-void-v1/void-impact")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("void impact = %d: %s", resp.Code, resp.Body.String())
+	}
+	var body struct {
+		Exports []struct {
+			Format string `json:"format"`
+			Status string `json:"status"`
+		} `json:"exports"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Exports) != 2 || body.Exports[0].Format != "pdf" || body.Exports[1].Format != "xlsx" || body.Exports[0].Status != "generated" {
+		t.Fatalf("exports = %+v, want the pdf and xlsx downloads", body.Exports)
+	}
+}
+
+// voidImpactDriver serves two versions of one statement month and the exports
+// of version 1. It rejects execs, so the preview must stay read-only.
+type voidImpactDriver struct{}
+
+func (voidImpactDriver) Open(string) (driver.Conn, error) {
+	return voidImpactConn{}, nil
+}
+
+type voidImpactConn struct{}
+
+func voidImpactStatement(id, status string, version int64, amount float64) []driver.Value {
+	created := voidImpactMonth.AddDate(0, 1, int(version))
+	return []driver.Value{
+		id, "tenant-void", "station-void", voidImpactMonth, "owner", status, version,
+		800.0, amount, "CNY", nil, nil,
+		created, created, nil, nil, false, nil, nil, nil,
+	}
+}
+
+func (voidImpactConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	v1 := voidImpactStatement("stmt-void-v1", "frozen", 1, 120.5)
+	v2 := voidImpactStatement("stmt-void-v2", "voided", 2, 118)
+	switch {
+	case strings.Contains(query, "FROM statement_exports"):
+		if args[0].Value != "stmt-void-v1" {
+			return &statementStubRows{}, nil
+		}
+		exported := voidImpactMonth.AddDate(0, 1, 3)
+		return &statementStubRows{rows: [][]driver.Value{
+			{"exp-1", "stmt-void-v1", "pdf", "generated", "s3://statements/stmt-void-v1.pdf", exported},
+			{"exp-2", "stmt-void-v1", "csv", "generated", nil, exported.Add(time.Hour)},
+		}}, nil
+	case strings.Contains(query, "ORDER BY version"):
+		return &statementStubRows{rows: [][]driver.Value{v1, v2}}, nil
+	case strings.Contains(query, "WHERE id = $1"):
+		switch args[0].Value {
+		case "stmt-void-v1":
+			return &statementStubRows{rows: [][]driver.Value{v1}}, nil
+		case "stmt-void-v2":
+			return &statementStubRows{rows: [][]driver.Value{v2}}, nil
+		}
+		return &statementStubRows{}, nil
+	}
+	return nil, errors.New("void impact stub: unexpected query")
+}
+
+func (voidImpactConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("void impact stub: prepare not supported")
+}
+
+func (voidImpactConn) Close() error { return nil }
+
+func (voidImpactConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("void impact stub: transactions not supported")
+}
+
+// exportRecordDriver serves version 1 of the void-impact statement without
+// items and keeps the statement_exports rows inserted through it.
+type exportRecordDriver struct{}
+
+func (exportRecordDriver) Open(string) (driver.Conn, error) {
+	return exportRecordConn{}, nil
+}
+
+type exportRecordConn struct{}
+
+func (exportRecordConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	v1 := voidImpactStatement("stmt-void-v1", "frozen", 1, 120.5)
+	switch {
+	case strings.Contains(query, "FROM statement_exports"):
+		return &statementStubRows{rows: recordedExports.For(args[0].Value)}, nil
+	case strings.Contains(query, "ORDER BY version"):
+		return &statementStubRows{rows: [][]driver.Value{v1}}, nil
+	case strings.Contains(query, "WHERE id = $1"):
+		if args[0].Value == "stmt-void-v1" {
+			return &statementStubRows{rows: [][]driver.Value{v1}}, nil
+		}
+		return &statementStubRows{}, nil
+	case strings.Contains(query, "FROM settlement_statement_items"):
+		return &statementStubRows{}, nil
+	}
+	return nil, errors.New("export record stub: unexpected query")
+}
+
+func (exportRecordConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if !strings.Contains(query, "INSERT INTO statement_exports") {
+		return nil, errors.New("export record stub: unexpected exec")
+	}
+	recordedExports.Record(args)
+	return driver.RowsAffected(1), nil
+}
+
+func (exportRecordConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("export record stub: prepare not supported")
+}
+
+func (exportRecordConn) Close() error { return nil }
+
+func (exportRecordConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("export record stub: transactions not supported")
+}
+
+type exportRecordLog struct {
+	mu   sync.Mutex
+	rows [][]driver.Value
+}
+
+// Record stores an insert's id, statement_id, format, status and path_or_key
+// with a created_at one minute after the previous row.
+func (l *exportRecordLog) Record(args []driver.NamedValue) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	row := make([]driver.Value, 0, len(args)+1)
+	for _, arg := range args {
+		row = append(row, arg.Value)
+	}
+	created := voidImpactMonth.AddDate(0, 1, 3).Add(time.Duration(len(l.rows)) * time.Minute)
+	l.rows = append(l.rows, append(row, created))
+}
+
+// For returns the recorded rows of statementID, oldest first.
+func (l *exportRecordLog) For(statementID driver.Value) [][]driver.Value {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var rows [][]driver.Value
+	for _, row := range l.rows {
+		if row[1] == statementID {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
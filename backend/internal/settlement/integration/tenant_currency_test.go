@@ -0,0 +1,140 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
+	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// tenantCurrencies resolves currencies from a fixed map.
+type tenantCurrencies map[string]string
+
+func (c tenantCurrencies) CurrencyForTenant(_ context.Context, tenantID string) (string, error) {
+	return c[tenantID], nil
+}
+
+func TestSettlementRepository_SavesTenantCurrency(t *testing.T) {
+	db, err := sql.Open("statement-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	resolver := tenantCurrencies{"tenant-cny": "CNY", "tenant-usd": "USD"}
+	dayStart := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		tenantID string
+		want     string
+	}{
+		{"tenant-cny", "CNY"},
+		{"tenant-usd", "USD"},
+		{"tenant-unset", "EUR"},
+	}
+	for _, tc := range cases {
+		repo := settlementrepo.NewSettlementRepository(db,
+			settlementrepo.WithTenantID(tc.tenantID),
+			settlementrepo.WithCurrency("EUR"),
+			settlementrepo.WithCurrencyResolver(resolver),
+		)
+		agg, err := settlement.NewDaySettlementAggregate("station-currency", dayStart)
+		if err != nil {
+			t.Fatalf("new aggregate: %v", err)
+		}
+		if err := agg.Recalculate(10, 5); err != nil {
+			t.Fatalf("recalculate: %v", err)
+		}
+		if err := repo.Save(context.Background(), agg); err != nil {
+			t.Fatalf("save %s: %v", tc.tenantID, err)
+		}
+		args := stubbedExecs.Last()
+		if len(args) < 6 || args[0].Value != tc.tenantID || args[5].Value != tc.want {
+			t.Fatalf("%s saved with args %v, want currency %s", tc.tenantID, args, tc.want)
+		}
+	}
+}
+
+func TestStatementGenerate_TenantCurrencies_Postgres(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "settlements_day") {
+		t.Skip("missing tables; run migrations")
+	}
+	if err := applyStatementMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	migration, err := os.ReadFile(filepath.Join(projectRoot(), "migrations", "025_tenant_settings.sql"))
+	if err != nil {
+		t.Fatalf("read migration: %v", err)
+	}
+	if _, err := db.Exec(string(migration)); err != nil {
+		t.Fatalf("apply migration: %v", err)
+	}
+
+	ctx := context.Background()
+	stationID := "station-tenant-currency"
+	dayStart := time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	tenants := map[string]string{"tenant-currency-cny": "CNY", "tenant-currency-usd": "USD"}
+	settings := masterdatarepo.NewTenantSettingsRepository(db)
+	for tenantID, currency := range tenants {
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statement_items WHERE statement_id IN (SELECT id FROM settlement_statements WHERE tenant_id = $1)", tenantID)
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlement_statements WHERE tenant_id = $1", tenantID)
+		_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day WHERE tenant_id = $1", tenantID)
+		if _, err := db.ExecContext(ctx, `
+INSERT INTO tenant_settings (tenant_id, currency) VALUES ($1, $2)
+ON CONFLICT (tenant_id) DO UPDATE SET currency = EXCLUDED.currency, updated_at = NOW()`, tenantID, currency); err != nil {
+			t.Fatalf("seed tenant settings: %v", err)
+		}
+
+		repo := settlementrepo.NewSettlementRepository(db, settlementrepo.WithTenantID(tenantID), settlementrepo.WithCurrencyResolver(settings))
+		energy := newHourEnergyStore()
+		energy.SetDayEnergy(stationID, dayStart, 10)
+		app, err := settlementapp.NewDaySettlementApplicationService(repo, energy, fixedPrice{unit: 1}, nil, settlementapp.SystemClock{})
+		if err != nil {
+			t.Fatalf("new app service: %v", err)
+		}
+		if err := app.HandleDayEnergyCalculated(ctx, settlementapp.DayEnergyCalculated{SubjectID: stationID, DayStart: dayStart}); err != nil {
+			t.Fatalf("settle %s: %v", tenantID, err)
+		}
+
+		service, err := settlementapp.NewStatementService(settlementrepo.NewStatementRepository(db), tenantID,
+			settlementapp.WithStatementCurrency(settings, "EUR"),
+		)
+		if err != nil {
+			t.Fatalf("statement service: %v", err)
+		}
+		stmt, err := service.Generate(ctx, stationID, "2026-01", "owner", false)
+		if err != nil {
+			t.Fatalf("generate %s: %v", tenantID, err)
+		}
+		if stmt.Currency != currency {
+			t.Fatalf("%s statement currency = %q, want %q", tenantID, stmt.Currency, currency)
+		}
+		// A month without settled days still carries the tenant's currency.
+		empty, err := service.Generate(ctx, stationID, "2026-02", "owner", false)
+		if err != nil {
+			t.Fatalf("generate empty month %s: %v", tenantID, err)
+		}
+		if empty.Currency != currency {
+			t.Fatalf("%s empty-month currency = %q, want %q", tenantID, empty.Currency, currency)
+		}
+	}
+}
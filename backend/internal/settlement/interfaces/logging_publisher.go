@@ -27,6 +27,16 @@ func (p *LoggingPublisher) PublishSettlementCalculated(ctx context.Context, even
 	if p == nil {
 		return errors.New("settlement publisher: nil publisher")
 	}
-	p.logger.Printf("settlement calculated: station=%s day=%s amount=%.4f", event.SubjectID, event.DayStart.Format("2006-01-02"), event.Amount)
+	p.logger.Printf("settlement calculated: station=%s day=%s amount=%.4f recalc=%v", event.SubjectID, event.DayStart.Format("2006-01-02"), event.Amount, event.Recalculate)
+	return nil
+}
+
+// PublishSettlementAnomaly logs the anomaly event.
+func (p *LoggingPublisher) PublishSettlementAnomaly(ctx context.Context, event application.SettlementAnomalyDetected) error {
+	_ = ctx
+	if p == nil {
+		return errors.New("settlement publisher: nil publisher")
+	}
+	p.logger.Printf("settlement anomaly: station=%s day=%s anomaly=%s energy_kwh=%.4f cap_kwh=%.4f", event.SubjectID, event.DayStart.Format("2006-01-02"), event.Anomaly, event.EnergyKWh, event.CapKWh)
 	return nil
 }
@@ -26,3 +26,12 @@ func (p *OutboxPublisher) PublishSettlementCalculated(ctx context.Context, event
 	ctx = eventing.WithTenantID(ctx, p.tenantID)
 	return p.publisher.Publish(ctx, event)
 }
+
+// PublishSettlementAnomaly writes the anomaly event to outbox.
+func (p *OutboxPublisher) PublishSettlementAnomaly(ctx context.Context, event application.SettlementAnomalyDetected) error {
+	if p == nil || p.publisher == nil {
+		return nil
+	}
+	ctx = eventing.WithTenantID(ctx, p.tenantID)
+	return p.publisher.Publish(ctx, event)
+}
@@ -0,0 +1,190 @@
+package interfaces
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"microgrid-cloud/internal/eventing"
+	"microgrid-cloud/internal/observability/metrics"
+	"microgrid-cloud/internal/settlement/application"
+)
+
+// Defaults of WithWebhookRetry.
+const (
+	DefaultWebhookAttempts = 3
+	DefaultWebhookBackoff  = time.Second
+)
+
+// SettlementWebhookEvent is the event name carried by settlement webhook payloads.
+const SettlementWebhookEvent = "settlement.calculated"
+
+// SettlementWebhook posts SettlementCalculated events to an integrator endpoint.
+type SettlementWebhook struct {
+	url      string
+	secret   []byte
+	client   *http.Client
+	attempts int
+	backoff  time.Duration
+	now      func() time.Time
+}
+
+// SettlementWebhookOption configures the settlement webhook.
+type SettlementWebhookOption func(*SettlementWebhook)
+
+// WithWebhookSecret signs every delivery with HMAC-SHA256 over
+// timestamp + "\n" + body, sent hex encoded in X-Settlement-Signature.
+func WithWebhookSecret(secret string) SettlementWebhookOption {
+	return func(w *SettlementWebhook) {
+		w.secret = []byte(secret)
+	}
+}
+
+// WithWebhookRetry makes up to attempts deliveries of an event, waiting
+// backoff times the attempt count between them. Transport errors, 429 and
+// 5xx responses are retried; other non-2xx responses are not.
+func WithWebhookRetry(attempts int, backoff time.Duration) SettlementWebhookOption {
+	return func(w *SettlementWebhook) {
+		if attempts > 0 {
+			w.attempts = attempts
+		}
+		if backoff > 0 {
+			w.backoff = backoff
+		}
+	}
+}
+
+// WithWebhookHTTPClient overrides the HTTP client.
+func WithWebhookHTTPClient(client *http.Client) SettlementWebhookOption {
+	return func(w *SettlementWebhook) {
+		if client != nil {
+			w.client = client
+		}
+	}
+}
+
+// NewSettlementWebhook constructs a settlement webhook.
+func NewSettlementWebhook(url string, opts ...SettlementWebhookOption) (*SettlementWebhook, error) {
+	if url == "" {
+		return nil, errors.New("settlement webhook: empty url")
+	}
+	webhook := &SettlementWebhook{
+		url:      url,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		attempts: DefaultWebhookAttempts,
+		backoff:  DefaultWebhookBackoff,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(webhook)
+	}
+	return webhook, nil
+}
+
+type settlementWebhookPayload struct {
+	Event       string    `json:"event"`
+	EventID     string    `json:"event_id,omitempty"`
+	TenantID    string    `json:"tenant_id,omitempty"`
+	StationID   string    `json:"station_id"`
+	DayStart    time.Time `json:"day_start"`
+	Amount      float64   `json:"amount"`
+	Recalculate bool      `json:"recalculate"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// HandleSettlementCalculated delivers a SettlementCalculated event. An error
+// is returned once every attempt failed, so the outbox redelivers the event.
+func (w *SettlementWebhook) HandleSettlementCalculated(ctx context.Context, event any) error {
+	if w == nil {
+		return errors.New("settlement webhook: nil webhook")
+	}
+	var evt application.SettlementCalculated
+	switch e := event.(type) {
+	case application.SettlementCalculated:
+		evt = e
+	case *application.SettlementCalculated:
+		if e == nil {
+			return nil
+		}
+		evt = *e
+	default:
+		return nil
+	}
+
+	payload := settlementWebhookPayload{
+		Event:       SettlementWebhookEvent,
+		StationID:   evt.SubjectID,
+		DayStart:    evt.DayStart.UTC(),
+		Amount:      evt.Amount,
+		Recalculate: evt.Recalculate,
+		OccurredAt:  evt.OccurredAt.UTC(),
+	}
+	if env, ok := eventing.EnvelopeFromContext(ctx); ok {
+		payload.EventID = env.EventID
+		payload.TenantID = env.TenantID
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryable, err := w.post(ctx, body)
+		if err == nil {
+			metrics.IncSettlementWebhook(metrics.ResultSuccess)
+			return nil
+		}
+		if !retryable || attempt >= w.attempts {
+			metrics.IncSettlementWebhook(metrics.ResultError)
+			return fmt.Errorf("settlement webhook: station %s day %s: %w", evt.SubjectID, evt.DayStart.UTC().Format("2006-01-02"), err)
+		}
+		timer := time.NewTimer(w.backoff * time.Duration(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			metrics.IncSettlementWebhook(metrics.ResultError)
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// post makes one delivery and reports whether a failure is worth retrying.
+func (w *SettlementWebhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(w.secret) > 0 {
+		timestamp := strconv.FormatInt(w.now().Unix(), 10)
+		req.Header.Set("X-Settlement-Timestamp", timestamp)
+		req.Header.Set("X-Settlement-Signature", signWebhookBody(w.secret, timestamp, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("non-2xx response %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+func signWebhookBody(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("\n"))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// statementETag derives the entity tag of one representation of a statement.
+// A frozen statement is immutable, so its snapshot hash identifies it; any
+// other statement is identified by its version and last update. variant
+// separates the JSON view from each export format.
+func statementETag(stmt *settlement.StatementAggregate, variant string) string {
+	key := stmt.SnapshotHash
+	if stmt.Status != settlement.StatementStatusFrozen || key == "" {
+		key = "v" + strconv.Itoa(stmt.Version) + "-" + strconv.FormatInt(stmt.UpdatedAt.UnixNano(), 36)
+	}
+	return `"` + strings.ReplaceAll(key, ":", "-") + "-" + variant + `"`
+}
+
+// notModified sets the ETag header and reports whether the request's
+// If-None-Match already names it, in which case 304 has been written.
+func notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+	header := r.Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
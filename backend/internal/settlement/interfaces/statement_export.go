@@ -3,6 +3,9 @@ package interfaces
 import (
 	"bytes"
 	"fmt"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"time"
 
 	"github.com/jung-kurt/gofpdf"
@@ -13,16 +16,40 @@ import (
 
 // BuildStatementPDF renders a minimal PDF for a statement.
 func BuildStatementPDF(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementPDF(stmt, items, nil)
+}
+
+// buildStatementPDF renders the statement PDF, adding the logo, company name
+// and footer when branding is set.
+func buildStatementPDF(stmt *settlement.StatementAggregate, items []settlement.StatementItem, branding *settlement.StatementBranding) ([]byte, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
+	if branding != nil && branding.FooterText != "" {
+		pdf.SetFooterFunc(func() {
+			pdf.SetY(-15)
+			pdf.SetFont("Arial", "I", 8)
+			pdf.CellFormat(0, 6, branding.FooterText, "", 0, "C", false, 0, "")
+		})
+	}
 	pdf.SetFont("Arial", "", 12)
 	pdf.AddPage()
 
+	if branding != nil {
+		pdf.SetAuthor(branding.CompanyName, false)
+		if branding.LogoPath != "" {
+			pdf.ImageOptions(branding.LogoPath, 10, 10, 0, 12, false, gofpdf.ImageOptions{ReadDpi: true}, 0, "")
+			pdf.SetY(25)
+		}
+		if branding.CompanyName != "" {
+			pdf.Cell(0, 8, branding.CompanyName)
+			pdf.Ln(8)
+		}
+	}
 	pdf.Cell(0, 8, "Settlement Statement")
 	pdf.Ln(10)
 	pdf.SetFont("Arial", "", 10)
 	pdf.Cell(0, 6, fmt.Sprintf("Station: %s", stmt.StationID))
 	pdf.Ln(5)
-	pdf.Cell(0, 6, fmt.Sprintf("Month: %s", stmt.StatementMonth.Format("2006-01")))
+	pdf.Cell(0, 6, fmt.Sprintf("Month: %s", statementMonthLabel(stmt)))
 	pdf.Ln(5)
 	pdf.Cell(0, 6, fmt.Sprintf("Category: %s", stmt.Category))
 	pdf.Ln(5)
@@ -41,7 +68,12 @@ func BuildStatementPDF(stmt *settlement.StatementAggregate, items []settlement.S
 	pdf.Cell(0, 6, fmt.Sprintf("Total Energy (kWh): %.3f", stmt.TotalEnergyKWh))
 	pdf.Ln(5)
 	pdf.Cell(0, 6, fmt.Sprintf("Total Amount (%s): %.2f", stmt.Currency, stmt.TotalAmount))
-	pdf.Ln(8)
+	pdf.Ln(5)
+	if stmt.Partial {
+		pdf.Cell(0, 6, fmt.Sprintf("Partial: %d days without settlement", len(stmt.MissingDays)))
+		pdf.Ln(5)
+	}
+	pdf.Ln(3)
 
 	// Items table
 	pdf.SetFont("Arial", "B", 10)
@@ -65,8 +97,25 @@ func BuildStatementPDF(stmt *settlement.StatementAggregate, items []settlement.S
 	return buf.Bytes(), nil
 }
 
+// statementMonthLabel names the statement month, followed by the days it
+// covers when the tenant bills on a cycle rather than calendar months.
+func statementMonthLabel(stmt *settlement.StatementAggregate) string {
+	month := stmt.StatementMonth.Format("2006-01")
+	start, end := settlement.BillingPeriod(stmt.StatementMonth, 0)
+	if stmt.PeriodStart.IsZero() || (stmt.PeriodStart.Equal(start) && stmt.PeriodEnd.Equal(end)) {
+		return month
+	}
+	return fmt.Sprintf("%s (%s to %s)", month, stmt.PeriodStart.Format("2006-01-02"), stmt.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"))
+}
+
 // BuildStatementXLSX renders a minimal XLSX for a statement.
 func BuildStatementXLSX(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementXLSX(stmt, items, nil)
+}
+
+// buildStatementXLSX renders the statement workbook. Branding puts the company
+// name in A2, the footer below the summary and the logo beside the title.
+func buildStatementXLSX(stmt *settlement.StatementAggregate, items []settlement.StatementItem, branding *settlement.StatementBranding) ([]byte, error) {
 	f := excelize.NewFile()
 	summarySheet := "summary"
 	itemsSheet := "items"
@@ -77,7 +126,7 @@ func BuildStatementXLSX(stmt *settlement.StatementAggregate, items []settlement.
 	_ = f.SetCellValue(summarySheet, "A3", "Station")
 	_ = f.SetCellValue(summarySheet, "B3", stmt.StationID)
 	_ = f.SetCellValue(summarySheet, "A4", "Month")
-	_ = f.SetCellValue(summarySheet, "B4", stmt.StatementMonth.Format("2006-01"))
+	_ = f.SetCellValue(summarySheet, "B4", statementMonthLabel(stmt))
 	_ = f.SetCellValue(summarySheet, "A5", "Category")
 	_ = f.SetCellValue(summarySheet, "B5", stmt.Category)
 	_ = f.SetCellValue(summarySheet, "A6", "Version")
@@ -90,6 +139,19 @@ func BuildStatementXLSX(stmt *settlement.StatementAggregate, items []settlement.
 	_ = f.SetCellValue(summarySheet, "B9", stmt.TotalAmount)
 	_ = f.SetCellValue(summarySheet, "A10", "Currency")
 	_ = f.SetCellValue(summarySheet, "B10", stmt.Currency)
+	_ = f.SetCellValue(summarySheet, "A11", "Partial")
+	_ = f.SetCellValue(summarySheet, "B11", stmt.Partial)
+	if branding != nil {
+		_ = f.SetCellValue(summarySheet, "A2", branding.CompanyName)
+		if branding.FooterText != "" {
+			_ = f.SetCellValue(summarySheet, "A13", branding.FooterText)
+		}
+		if branding.LogoPath != "" {
+			if err := f.AddPicture(summarySheet, "D1", branding.LogoPath, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	_ = f.SetCellValue(itemsSheet, "A1", "Day")
 	_ = f.SetCellValue(itemsSheet, "B1", "Energy (kWh)")
@@ -0,0 +1,125 @@
+package interfaces
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"time"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+var statementHTMLTemplate = template.Must(template.New("statement").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Settlement Statement {{.StationID}} {{.Month}}</title>
+<style>
+body { font-family: Arial, Helvetica, sans-serif; font-size: 14px; color: #222; margin: 24px; }
+h1 { font-size: 20px; margin: 0 0 12px; }
+.company { font-size: 16px; font-weight: bold; margin-bottom: 8px; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 4px 16px; margin: 0 0 16px; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+.partial { color: #b45309; font-weight: bold; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #999; padding: 4px 12px; }
+th { background: #f0f0f0; }
+td.num { text-align: right; }
+footer { margin-top: 24px; font-size: 12px; color: #666; }
+</style>
+</head>
+<body>
+{{if .CompanyName}}<div class="company">{{.CompanyName}}</div>
+{{end}}<h1>Settlement Statement</h1>
+<dl>
+<dt>Station</dt><dd>{{.StationID}}</dd>
+<dt>Month</dt><dd>{{.Month}}</dd>
+<dt>Category</dt><dd>{{.Category}}</dd>
+<dt>Version</dt><dd>{{.Version}}</dd>
+<dt>Status</dt><dd>{{.Status}}</dd>
+<dt>Generated</dt><dd>{{.Generated}}</dd>
+{{if .Frozen}}<dt>Frozen</dt><dd>{{.Frozen}}</dd>
+{{end}}<dt>Total Energy (kWh)</dt><dd>{{.TotalEnergy}}</dd>
+<dt>Total Amount ({{.Currency}})</dt><dd>{{.TotalAmount}}</dd>
+</dl>
+{{if .Partial}}<p class="partial">Partial: {{.MissingDays}} days without settlement</p>
+{{end}}<table>
+<thead><tr><th>Day</th><th>Energy (kWh)</th><th>Amount</th></tr></thead>
+<tbody>
+{{range .Items}}<tr><td>{{.Day}}</td><td class="num">{{.Energy}}</td><td class="num">{{.Amount}}</td></tr>
+{{end}}</tbody>
+</table>
+{{if .FooterText}}<footer>{{.FooterText}}</footer>
+{{end}}</body>
+</html>
+`))
+
+type statementHTMLItem struct {
+	Day    string
+	Energy string
+	Amount string
+}
+
+type statementHTMLView struct {
+	CompanyName string
+	FooterText  string
+	StationID   string
+	Month       string
+	Category    string
+	Version     int
+	Status      string
+	Generated   string
+	Frozen      string
+	TotalEnergy string
+	TotalAmount string
+	Currency    string
+	Partial     bool
+	MissingDays int
+	Items       []statementHTMLItem
+}
+
+// BuildStatementHTML renders a browser preview of a statement with the same
+// data as the PDF.
+func BuildStatementHTML(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementHTML(stmt, items, nil)
+}
+
+// buildStatementHTML renders the preview, adding the company name and footer
+// when branding is set. The logo is a server-side path and is left out.
+func buildStatementHTML(stmt *settlement.StatementAggregate, items []settlement.StatementItem, branding *settlement.StatementBranding) ([]byte, error) {
+	view := statementHTMLView{
+		StationID:   stmt.StationID,
+		Month:       statementMonthLabel(stmt),
+		Category:    stmt.Category,
+		Version:     stmt.Version,
+		Status:      stmt.Status,
+		Generated:   stmt.CreatedAt.Format(time.RFC3339),
+		TotalEnergy: fmt.Sprintf("%.3f", stmt.TotalEnergyKWh),
+		TotalAmount: fmt.Sprintf("%.2f", stmt.TotalAmount),
+		Currency:    stmt.Currency,
+		Partial:     stmt.Partial,
+		MissingDays: len(stmt.MissingDays),
+		Items:       make([]statementHTMLItem, 0, len(items)),
+	}
+	if !stmt.FrozenAt.IsZero() {
+		view.Frozen = stmt.FrozenAt.Format(time.RFC3339)
+	}
+	if branding != nil {
+		view.CompanyName = branding.CompanyName
+		view.FooterText = branding.FooterText
+	}
+	for _, item := range items {
+		view.Items = append(view.Items, statementHTMLItem{
+			Day:    item.DayStart.Format("2006-01-02"),
+			Energy: fmt.Sprintf("%.3f", item.EnergyKWh),
+			Amount: fmt.Sprintf("%.2f", item.Amount),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := statementHTMLTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,79 @@
+package interfaces
+
+import (
+	"sync"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// StatementExportBuilder renders one export format using the tenant's renderer.
+type StatementExportBuilder func(renderer StatementRenderer, stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error)
+
+// StatementExportFormat is a registered export served at /api/v1/statements/{id}/export.<format>.
+type StatementExportFormat struct {
+	ContentType string
+	Build       StatementExportBuilder
+}
+
+// ExportRegistry maps export format suffixes to builders.
+type ExportRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]StatementExportFormat
+}
+
+// NewExportRegistry constructs an empty registry.
+func NewExportRegistry() *ExportRegistry {
+	return &ExportRegistry{formats: make(map[string]StatementExportFormat)}
+}
+
+// DefaultExportRegistry returns a registry with the pdf, xlsx and html formats.
+func DefaultExportRegistry() *ExportRegistry {
+	registry := NewExportRegistry()
+	registry.Register("pdf", "application/pdf", func(renderer StatementRenderer, stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+		return renderer.RenderPDF(stmt, items)
+	})
+	registry.Register("xlsx", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", func(renderer StatementRenderer, stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+		return renderer.RenderXLSX(stmt, items)
+	})
+	registry.Register("html", "text/html; charset=utf-8", func(renderer StatementRenderer, stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+		return renderer.RenderHTML(stmt, items)
+	})
+	return registry
+}
+
+// Register adds or replaces the builder for a format suffix.
+func (r *ExportRegistry) Register(format, contentType string, build StatementExportBuilder) {
+	if r == nil || format == "" || build == nil {
+		return
+	}
+	r.mu.Lock()
+	r.formats[format] = StatementExportFormat{ContentType: contentType, Build: build}
+	r.mu.Unlock()
+}
+
+// Lookup returns the export registered for a format suffix.
+func (r *ExportRegistry) Lookup(format string) (StatementExportFormat, bool) {
+	if r == nil {
+		return StatementExportFormat{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	export, ok := r.formats[format]
+	return export, ok
+}
+
+// WithExportFormat registers an additional export format on the handler.
+func WithExportFormat(format, contentType string, build StatementExportBuilder) StatementHandlerOption {
+	return func(h *StatementHandler) {
+		h.exports.Register(format, contentType, build)
+	}
+}
+
+// WithExportRegistry replaces the handler's export formats.
+func WithExportRegistry(registry *ExportRegistry) StatementHandlerOption {
+	return func(h *StatementHandler) {
+		if registry != nil {
+			h.exports = registry
+		}
+	}
+}
@@ -3,10 +3,13 @@ package interfaces
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/api/pagination"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
 	"microgrid-cloud/internal/observability/metrics"
@@ -19,14 +22,38 @@ type StatementHandler struct {
 	service        *statementapp.StatementService
 	stationChecker auth.StationTenantChecker
 	auditLogger    audit.Logger
+	branding       BrandingResolver
+	exports        *ExportRegistry
+	monthClose     *statementapp.MonthCloseService
+	logger         *log.Logger
 }
 
 // NewStatementHandler constructs a handler.
-func NewStatementHandler(service *statementapp.StatementService, stationChecker auth.StationTenantChecker, auditLogger audit.Logger) (*StatementHandler, error) {
+func NewStatementHandler(service *statementapp.StatementService, stationChecker auth.StationTenantChecker, auditLogger audit.Logger, opts ...StatementHandlerOption) (*StatementHandler, error) {
 	if service == nil {
 		return nil, errors.New("statement handler: nil service")
 	}
-	return &StatementHandler{service: service, stationChecker: stationChecker, auditLogger: auditLogger}, nil
+	h := &StatementHandler{service: service, stationChecker: stationChecker, auditLogger: auditLogger, exports: DefaultExportRegistry(), logger: log.Default()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
+}
+
+// WithMonthClose serves POST /api/v1/statements/close with service.
+func WithMonthClose(service *statementapp.MonthCloseService) StatementHandlerOption {
+	return func(h *StatementHandler) {
+		h.monthClose = service
+	}
+}
+
+// WithLogger logs unexpected service errors to logger.
+func WithLogger(logger *log.Logger) StatementHandlerOption {
+	return func(h *StatementHandler) {
+		if logger != nil {
+			h.logger = logger
+		}
+	}
 }
 
 // ServeHTTP handles statement routes under /api/v1/statements.
@@ -36,6 +63,10 @@ func (h *StatementHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleGenerate(w, r)
 		return
 	}
+	if path == "/api/v1/statements/close" && r.Method == http.MethodPost && h.monthClose != nil {
+		h.handleMonthClose(w, r)
+		return
+	}
 	if path == "/api/v1/statements" && r.Method == http.MethodGet {
 		h.handleList(w, r)
 		return
@@ -57,12 +88,12 @@ func (h *StatementHandler) handleGenerate(w http.ResponseWriter, r *http.Request
 		Regenerate bool   `json:"regenerate"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && req.TenantID != "" && req.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if tenantID != "" {
@@ -73,13 +104,19 @@ func (h *StatementHandler) handleGenerate(w http.ResponseWriter, r *http.Request
 	}
 	stmt, err := h.service.Generate(r.Context(), req.StationID, req.Month, req.Category, req.Regenerate)
 	if err != nil {
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
 		return
 	}
+	missingDays := make([]string, 0, len(stmt.MissingDays))
+	for _, day := range stmt.MissingDays {
+		missingDays = append(missingDays, day.Format("2006-01-02"))
+	}
 	resp := map[string]any{
 		"statement_id": stmt.ID,
 		"status":       stmt.Status,
 		"version":      stmt.Version,
+		"partial":      stmt.Partial,
+		"missing_days": missingDays,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(resp)
@@ -94,10 +131,64 @@ func (h *StatementHandler) handleGenerate(w http.ResponseWriter, r *http.Request
 	})
 }
 
+func (h *StatementHandler) handleMonthClose(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		TenantID   string   `json:"tenant_id"`
+		Month      string   `json:"month"`
+		Category   string   `json:"category"`
+		StationIDs []string `json:"station_ids"`
+		Regenerate bool     `json:"regenerate"`
+		Freeze     bool     `json:"freeze"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	tenantID := auth.TenantIDFromContext(r.Context())
+	if tenantID != "" && req.TenantID != "" && req.TenantID != tenantID {
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
+		return
+	}
+	if tenantID != "" {
+		for _, stationID := range req.StationIDs {
+			if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
+				respondTenantError(w, err)
+				return
+			}
+		}
+	}
+	report, err := h.monthClose.Close(r.Context(), statementapp.MonthCloseRequest{
+		Month:      req.Month,
+		Category:   req.Category,
+		StationIDs: req.StationIDs,
+		Regenerate: req.Regenerate,
+		Freeze:     req.Freeze,
+	})
+	if err != nil {
+		h.respondServiceError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+	h.logAudit(r, "", "", "statement.month_close", map[string]any{
+		"month":    report.Month,
+		"category": report.Category,
+		"freeze":   report.Freeze,
+		"stations": len(report.Stations),
+		"blocked":  report.Blocked,
+		"failed":   report.Failed,
+	})
+}
+
 func (h *StatementHandler) handleList(w http.ResponseWriter, r *http.Request) {
 	stationID := r.URL.Query().Get("station_id")
 	month := r.URL.Query().Get("month")
 	category := r.URL.Query().Get("category")
+	page, err := pagination.Parse(r)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" {
 		if err := ensureStationTenant(r, h.stationChecker, tenantID, stationID); err != nil {
@@ -105,11 +196,12 @@ func (h *StatementHandler) handleList(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	list, err := h.service.List(r.Context(), stationID, month, category)
+	list, total, err := h.service.List(r.Context(), stationID, month, category, page.Limit, page.Offset)
 	if err != nil {
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
 		return
 	}
+	pagination.SetHeaders(w, page, total)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(list)
 }
@@ -137,14 +229,15 @@ func (h *StatementHandler) handleByID(w http.ResponseWriter, r *http.Request, re
 				h.handleVoid(w, r, id)
 				return
 			}
-		case "export.pdf":
+		case "void-impact":
 			if r.Method == http.MethodGet {
-				h.handleExportPDF(w, r, id)
+				h.handleVoidImpact(w, r, id)
 				return
 			}
-		case "export.xlsx":
-			if r.Method == http.MethodGet {
-				h.handleExportXLSX(w, r, id)
+		}
+		if format, ok := strings.CutPrefix(parts[1], "export."); ok && r.Method == http.MethodGet {
+			if export, found := h.exports.Lookup(format); found {
+				h.handleExport(w, r, id, format, export)
 				return
 			}
 		}
@@ -155,7 +248,10 @@ func (h *StatementHandler) handleByID(w http.ResponseWriter, r *http.Request, re
 func (h *StatementHandler) handleGet(w http.ResponseWriter, r *http.Request, id string) {
 	stmt, items, err := h.service.Get(r.Context(), id)
 	if err != nil {
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
+		return
+	}
+	if notModified(w, r, statementETag(stmt, "json")) {
 		return
 	}
 	resp := struct {
@@ -169,7 +265,7 @@ func (h *StatementHandler) handleGet(w http.ResponseWriter, r *http.Request, id
 func (h *StatementHandler) handleFreeze(w http.ResponseWriter, r *http.Request, id string) {
 	stmt, err := h.service.Freeze(r.Context(), id)
 	if err != nil {
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
 		return
 	}
 	resp := map[string]any{
@@ -192,7 +288,7 @@ func (h *StatementHandler) handleVoid(w http.ResponseWriter, r *http.Request, id
 	_ = json.NewDecoder(r.Body).Decode(&req)
 	stmt, err := h.service.Void(r.Context(), id, req.Reason)
 	if err != nil {
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
 		return
 	}
 	resp := map[string]any{
@@ -207,54 +303,83 @@ func (h *StatementHandler) handleVoid(w http.ResponseWriter, r *http.Request, id
 	})
 }
 
-func (h *StatementHandler) handleExportPDF(w http.ResponseWriter, r *http.Request, id string) {
-	start := time.Now()
-	result := metrics.ResultSuccess
-	defer func() {
-		metrics.ObserveStatementExport("pdf", result, time.Since(start))
-	}()
-
-	stmt, items, err := h.service.Get(r.Context(), id)
+func (h *StatementHandler) handleVoidImpact(w http.ResponseWriter, r *http.Request, id string) {
+	impact, err := h.service.VoidImpact(r.Context(), id)
 	if err != nil {
-		result = metrics.ResultError
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
 		return
 	}
-	data, err := BuildStatementPDF(stmt, items)
-	if err != nil {
-		result = metrics.ResultError
-		http.Error(w, "export pdf error", http.StatusInternalServerError)
-		return
+	stmt := impact.Statement
+	newer := make([]map[string]any, 0, len(impact.NewerVersions))
+	for _, version := range impact.NewerVersions {
+		newer = append(newer, map[string]any{
+			"statement_id": version.ID,
+			"version":      version.Version,
+			"status":       version.Status,
+			"total_amount": version.TotalAmount,
+		})
+	}
+	exports := make([]map[string]any, 0, len(impact.Exports))
+	for _, export := range impact.Exports {
+		exports = append(exports, map[string]any{
+			"id":          export.ID,
+			"format":      export.Format,
+			"status":      export.Status,
+			"path_or_key": export.PathOrKey,
+			"created_at":  export.CreatedAt,
+		})
 	}
-	w.Header().Set("Content-Type", "application/pdf")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write(data)
-	h.logAudit(r, stmt.StationID, stmt.ID, "statement.export", map[string]any{"format": "pdf"})
+	resp := map[string]any{
+		"statement_id":         stmt.ID,
+		"status":               stmt.Status,
+		"version":              stmt.Version,
+		"total_energy_kwh":     stmt.TotalEnergyKWh,
+		"total_amount":         stmt.TotalAmount,
+		"currency":             stmt.Currency,
+		"can_void":             impact.CanVoid,
+		"newer_version_exists": len(newer) > 0,
+		"newer_versions":       newer,
+		"exports":              exports,
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *StatementHandler) handleExportXLSX(w http.ResponseWriter, r *http.Request, id string) {
+func (h *StatementHandler) handleExport(w http.ResponseWriter, r *http.Request, id, format string, export StatementExportFormat) {
 	start := time.Now()
 	result := metrics.ResultSuccess
 	defer func() {
-		metrics.ObserveStatementExport("xlsx", result, time.Since(start))
+		metrics.ObserveStatementExport(format, result, time.Since(start))
 	}()
 
 	stmt, items, err := h.service.Get(r.Context(), id)
 	if err != nil {
 		result = metrics.ResultError
-		respondServiceError(w, err)
+		h.respondServiceError(w, err)
+		return
+	}
+	if notModified(w, r, statementETag(stmt, format)) {
+		return
+	}
+	renderer, err := h.rendererFor(r.Context(), stmt.TenantID)
+	if err != nil {
+		result = metrics.ResultError
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "statement branding error")
 		return
 	}
-	data, err := BuildStatementXLSX(stmt, items)
+	data, err := export.Build(renderer, stmt, items)
 	if err != nil {
 		result = metrics.ResultError
-		http.Error(w, "export xlsx error", http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "export "+format+" error")
 		return
 	}
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	w.Header().Set("Content-Type", export.ContentType)
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write(data)
-	h.logAudit(r, stmt.StationID, stmt.ID, "statement.export", map[string]any{"format": "xlsx"})
+	if err := h.service.RecordExport(r.Context(), stmt, format); err != nil {
+		h.logger.Printf("statement export record error: %v", err)
+	}
+	h.logAudit(r, stmt.StationID, stmt.ID, "statement.export", map[string]any{"format": format})
 }
 
 func (h *StatementHandler) logAudit(r *http.Request, stationID, statementID, action string, meta map[string]any) {
@@ -292,23 +417,33 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
 
-func respondServiceError(w http.ResponseWriter, err error) {
+// respondServiceError maps the statement service error kinds to status codes.
+// Unclassified errors are treated as internal; their cause is logged, not exposed.
+func (h *StatementHandler) respondServiceError(w http.ResponseWriter, err error) {
 	if err == nil {
 		return
 	}
-	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
+	switch {
+	case errors.Is(err, auth.ErrTenantMismatch):
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
+	case errors.Is(err, statementapp.ErrNotFound):
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, err.Error())
+	case errors.Is(err, statementapp.ErrConflict):
+		apierror.Write(w, http.StatusConflict, apierror.CodeConflict, err.Error())
+	case errors.Is(err, statementapp.ErrValidation):
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+	default:
+		h.logger.Printf("statement service error: %v", err)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "internal error")
 	}
-	http.Error(w, err.Error(), http.StatusBadRequest)
 }
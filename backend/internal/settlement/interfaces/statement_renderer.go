@@ -0,0 +1,88 @@
+package interfaces
+
+import (
+	"context"
+
+	settlement "microgrid-cloud/internal/settlement/domain"
+)
+
+// StatementRenderer builds the exported documents of a statement.
+type StatementRenderer interface {
+	RenderPDF(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error)
+	RenderXLSX(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error)
+	RenderHTML(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error)
+}
+
+// BrandingResolver looks up the branding of a tenant; nil means unbranded.
+type BrandingResolver interface {
+	FindByTenant(ctx context.Context, tenantID string) (*settlement.StatementBranding, error)
+}
+
+// DefaultStatementRenderer renders the unbranded layout.
+type DefaultStatementRenderer struct{}
+
+// RenderPDF implements StatementRenderer.
+func (DefaultStatementRenderer) RenderPDF(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return BuildStatementPDF(stmt, items)
+}
+
+// RenderXLSX implements StatementRenderer.
+func (DefaultStatementRenderer) RenderXLSX(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return BuildStatementXLSX(stmt, items)
+}
+
+// RenderHTML implements StatementRenderer.
+func (DefaultStatementRenderer) RenderHTML(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return BuildStatementHTML(stmt, items)
+}
+
+// BrandedStatementRenderer renders statements with a tenant's logo, company name and footer.
+type BrandedStatementRenderer struct {
+	Branding settlement.StatementBranding
+}
+
+// NewBrandedStatementRenderer constructs a branded renderer.
+func NewBrandedStatementRenderer(branding settlement.StatementBranding) *BrandedStatementRenderer {
+	return &BrandedStatementRenderer{Branding: branding}
+}
+
+// RenderPDF implements StatementRenderer.
+func (r *BrandedStatementRenderer) RenderPDF(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementPDF(stmt, items, &r.Branding)
+}
+
+// RenderXLSX implements StatementRenderer.
+func (r *BrandedStatementRenderer) RenderXLSX(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementXLSX(stmt, items, &r.Branding)
+}
+
+// RenderHTML implements StatementRenderer.
+func (r *BrandedStatementRenderer) RenderHTML(stmt *settlement.StatementAggregate, items []settlement.StatementItem) ([]byte, error) {
+	return buildStatementHTML(stmt, items, &r.Branding)
+}
+
+// StatementHandlerOption customizes the statement handler.
+type StatementHandlerOption func(*StatementHandler)
+
+// WithBrandingResolver renders exports with the requesting tenant's branding
+// when the resolver has one.
+func WithBrandingResolver(resolver BrandingResolver) StatementHandlerOption {
+	return func(h *StatementHandler) {
+		h.branding = resolver
+	}
+}
+
+// rendererFor selects the renderer for the tenant, falling back to the default.
+func (h *StatementHandler) rendererFor(ctx context.Context, tenantID string) (StatementRenderer, error) {
+	if h.branding == nil || tenantID == "" {
+		return DefaultStatementRenderer{}, nil
+	}
+	branding, err := h.branding.FindByTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if branding == nil {
+		return DefaultStatementRenderer{}, nil
+	}
+	return NewBrandedStatementRenderer(*branding), nil
+}
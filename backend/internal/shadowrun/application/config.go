@@ -6,6 +6,9 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	recon "microgrid-cloud/internal/reconcile"
 
 	"gopkg.in/yaml.v3"
 )
@@ -29,12 +32,23 @@ type Config struct {
 	WebhookURL    string                `yaml:"webhook_url"`
 	PublicBaseURL string                `yaml:"public_base_url"`
 	FallbackPrice float64               `yaml:"fallback_price"`
+	AutoHeal      string                `yaml:"auto_heal"`
+	// NotifyRecovered closes a station's open alerts and sends a recovered
+	// notification once its report is back within thresholds.
+	NotifyRecovered bool `yaml:"notify_recovered"`
+	// LateAfter counts an hour as late data when its statistic completed
+	// more than this long after the hour started, e.g. "6h"; 0 disables it.
+	LateAfter time.Duration `yaml:"late_after"`
 }
 
 // ScheduleConfig defines cron-like schedule.
 type ScheduleConfig struct {
 	DailyAt  string   `yaml:"daily_at"`
 	Stations []string `yaml:"stations"`
+	// Concurrency is how many stations run at a time.
+	Concurrency int `yaml:"concurrency"`
+	// StationTimeout bounds each station's run, e.g. "30m"; 0 disables it.
+	StationTimeout time.Duration `yaml:"station_timeout"`
 }
 
 // LoadConfig loads config from yaml or env.
@@ -70,9 +84,27 @@ func LoadConfig() (Config, error) {
 	if len(cfg.Schedule.Stations) == 0 {
 		cfg.Schedule.Stations = splitCSV(getenvDefault("SHADOWRUN_STATIONS", ""))
 	}
+	if cfg.Schedule.Concurrency == 0 {
+		cfg.Schedule.Concurrency = getenvIntDefault("SHADOWRUN_CONCURRENCY", DefaultScheduleConcurrency)
+	}
+	if cfg.Schedule.StationTimeout == 0 {
+		cfg.Schedule.StationTimeout = getenvDurationDefault("SHADOWRUN_STATION_TIMEOUT", DefaultScheduleStationTimeout)
+	}
 	if cfg.WebhookURL == "" {
 		cfg.WebhookURL = os.Getenv("SHADOWRUN_WEBHOOK_URL")
 	}
+	if cfg.AutoHeal == "" {
+		cfg.AutoHeal = getenvDefault("SHADOWRUN_AUTO_HEAL", string(recon.HealOff))
+	}
+	if !cfg.NotifyRecovered {
+		cfg.NotifyRecovered = getenvBoolDefault("SHADOWRUN_NOTIFY_RECOVERED", false)
+	}
+	if cfg.LateAfter == 0 {
+		cfg.LateAfter = getenvDurationDefault("SHADOWRUN_LATE_AFTER", 0)
+	}
+	if _, err := recon.ParseHealMode(cfg.AutoHeal); err != nil {
+		return cfg, err
+	}
 	if cfg.StorageRoot == "" {
 		return cfg, errors.New("shadowrun: storage root required")
 	}
@@ -131,6 +163,42 @@ func getenvFloatDefault(key string, fallback float64) float64 {
 	return parsed
 }
 
+func getenvIntDefault(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getenvBoolDefault(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
+func getenvDurationDefault(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
 func splitCSV(value string) []string {
 	if value == "" {
 		return nil
@@ -5,13 +5,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/csv"
-	"encoding/json"
 	"errors"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"time"
+
+	recon "microgrid-cloud/internal/reconcile"
 )
 
 const timeLayout = time.RFC3339
@@ -142,7 +142,17 @@ func reconcile(ctx context.Context, db *sql.DB, tenantID, stationID string, mont
 	}, plan, rules, nil
 }
 
+// tariffOverrideID identifies the plan and rule built from a tariff override.
+const tariffOverrideID = "override"
+
+// loadTariff returns the tariff of the station's month. A row in
+// tariff_overrides takes precedence over tariff_plans and prices every hour
+// at its flat price.
 func loadTariff(ctx context.Context, db *sql.DB, tenantID, stationID string, month time.Time) (*tariffPlan, []tariffRule, error) {
+	if override, rules, err := loadTariffOverride(ctx, db, tenantID, stationID, month); err != nil || override != nil {
+		return override, rules, err
+	}
+
 	var plan tariffPlan
 	err := db.QueryRowContext(ctx, `
 SELECT id, mode, currency
@@ -177,6 +187,24 @@ ORDER BY start_minute ASC`, plan.ID)
 	return &plan, rules, nil
 }
 
+// loadTariffOverride returns the pinned price of the station's month as a
+// single all-day rule, or a nil plan when none is pinned.
+func loadTariffOverride(ctx context.Context, db *sql.DB, tenantID, stationID string, month time.Time) (*tariffPlan, []tariffRule, error) {
+	var price float64
+	plan := tariffPlan{ID: tariffOverrideID, Mode: tariffOverrideID}
+	err := db.QueryRowContext(ctx, `
+SELECT price_per_kwh, currency
+FROM tariff_overrides
+WHERE tenant_id = $1 AND station_id = $2 AND month = $3`, tenantID, stationID, month).Scan(&price, &plan.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &plan, []tariffRule{{ID: tariffOverrideID, StartMinute: 0, EndMinute: 1440, PricePerKWh: price}}, nil
+}
+
 func matchRule(rules []tariffRule, minute int) (tariffRule, bool) {
 	for _, rule := range rules {
 		if rule.StartMinute <= minute && rule.EndMinute > minute {
@@ -722,101 +750,30 @@ func writeStatementSummary(outDir string, rows []statementSummary) error {
 	return nil
 }
 
-type diffDay struct {
-	DayStart     time.Time `json:"day_start"`
-	EnergyHour   float64   `json:"energy_hour"`
-	EnergySettle float64   `json:"energy_settlement"`
-	EnergyDiff   float64   `json:"energy_diff"`
-	AmountHour   float64   `json:"amount_hour"`
-	AmountSettle float64   `json:"amount_settlement"`
-	AmountDiff   float64   `json:"amount_diff"`
-	MissingHours int       `json:"missing_hours"`
-}
-
+// diffSummary is the shared reconcile summary plus the thresholds it was judged against.
 type diffSummary struct {
-	Month             string     `json:"month"`
-	StationID         string     `json:"station_id"`
-	DiffEnergyMax     float64    `json:"diff_energy_max"`
-	DiffAmountMax     float64    `json:"diff_amount_max"`
-	MissingHoursTotal int        `json:"missing_hours_total"`
-	LateDataCount     int        `json:"late_data_count"`
-	GeneratedAt       string     `json:"generated_at"`
-	DayDiffs          []diffDay  `json:"day_diffs"`
-	Thresholds        Thresholds `json:"thresholds"`
+	recon.Summary
+	Thresholds Thresholds        `json:"thresholds"`
+	AutoHeal   *recon.HealResult `json:"auto_heal,omitempty"`
+	Trend      *monthTrend       `json:"trend,omitempty"`
 }
 
 func buildDiffSummary(result reconcileResult, monthStart, monthEnd, jobDate time.Time, thresholds Thresholds) (diffSummary, error) {
-	hourByDay := make(map[time.Time][]hourStat)
-	for _, row := range result.Hours {
-		day := time.Date(row.PeriodStart.Year(), row.PeriodStart.Month(), row.PeriodStart.Day(), 0, 0, 0, 0, time.UTC)
-		hourByDay[day] = append(hourByDay[day], row)
-	}
-	settlementByDay := make(map[time.Time]settlementRow)
+	hours := reconHours(result)
+	settlements := make([]recon.Settlement, 0, len(result.Settlements))
 	for _, row := range result.Settlements {
-		day := time.Date(row.DayStart.Year(), row.DayStart.Month(), row.DayStart.Day(), 0, 0, 0, 0, time.UTC)
-		settlementByDay[day] = row
+		settlements = append(settlements, recon.Settlement{DayStart: row.DayStart, EnergyKWh: row.EnergyKWh, Amount: row.Amount})
 	}
+	summary := recon.BuildSummary(result.SettlementsStationID(), hours, settlements, monthStart, monthEnd, jobDate, time.Now())
+	return diffSummary{Summary: summary, Thresholds: thresholds}, nil
+}
 
-	endDate := monthEnd
-	if jobDate.Before(monthEnd) && jobDate.After(monthStart) {
-		endDate = time.Date(jobDate.Year(), jobDate.Month(), jobDate.Day(), 0, 0, 0, 0, time.UTC)
+func reconHours(result reconcileResult) []recon.Hour {
+	hours := make([]recon.Hour, 0, len(result.Hours))
+	for _, row := range result.Hours {
+		hours = append(hours, recon.Hour{Start: row.PeriodStart, EnergyKWh: row.EnergyKWh, Amount: row.Amount})
 	}
-
-	var diffs []diffDay
-	var maxEnergy float64
-	var maxAmount float64
-	var missingTotal int
-
-	for day := monthStart; day.Before(endDate); day = day.AddDate(0, 0, 1) {
-		hours := hourByDay[day]
-		settle := settlementByDay[day]
-		var energyHour float64
-		var amountHour float64
-		for _, hr := range hours {
-			energyHour += hr.EnergyKWh
-			amountHour += hr.Amount
-		}
-		energyDiff := energyHour - settle.EnergyKWh
-		amountDiff := amountHour - settle.Amount
-
-		missing := 24 - len(hours)
-		if missing < 0 {
-			missing = 0
-		}
-		missingTotal += missing
-
-		if abs(energyDiff) > maxEnergy {
-			maxEnergy = abs(energyDiff)
-		}
-		if abs(amountDiff) > maxAmount {
-			maxAmount = abs(amountDiff)
-		}
-
-		diffs = append(diffs, diffDay{
-			DayStart:     day,
-			EnergyHour:   energyHour,
-			EnergySettle: settle.EnergyKWh,
-			EnergyDiff:   energyDiff,
-			AmountHour:   amountHour,
-			AmountSettle: settle.Amount,
-			AmountDiff:   amountDiff,
-			MissingHours: missing,
-		})
-	}
-
-	sort.Slice(diffs, func(i, j int) bool { return diffs[i].DayStart.Before(diffs[j].DayStart) })
-
-	return diffSummary{
-		Month:             monthStart.Format("2006-01"),
-		StationID:         result.SettlementsStationID(),
-		DiffEnergyMax:     maxEnergy,
-		DiffAmountMax:     maxAmount,
-		MissingHoursTotal: missingTotal,
-		LateDataCount:     0,
-		GeneratedAt:       time.Now().UTC().Format(timeLayout),
-		DayDiffs:          diffs,
-		Thresholds:        thresholds,
-	}, nil
+	return hours
 }
 
 func (r reconcileResult) SettlementsStationID() string {
@@ -833,15 +790,7 @@ func (r reconcileResult) SettlementsStationID() string {
 }
 
 func writeSummaryJSON(outDir string, summary diffSummary) error {
-	path := filepath.Join(outDir, "diff_summary.json")
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(summary)
+	return recon.WriteSummaryJSON(outDir, summary)
 }
 
 func formatTime(value time.Time) string {
@@ -883,13 +832,6 @@ func formatBool(value bool) string {
 	return "false"
 }
 
-func abs(value float64) float64 {
-	if value < 0 {
-		return -value
-	}
-	return value
-}
-
 func validateMonth(monthStart, monthEnd time.Time) error {
 	if monthStart.IsZero() || monthEnd.IsZero() {
 		return errors.New("invalid month range")
@@ -7,8 +7,13 @@ import (
 	"fmt"
 	"log"
 	"path/filepath"
+	"strings"
 	"time"
 
+	domainstatistic "microgrid-cloud/internal/analytics/domain/statistic"
+	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
+	"microgrid-cloud/internal/clock"
+	recon "microgrid-cloud/internal/reconcile"
 	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
 	shadowmetrics "microgrid-cloud/internal/shadowrun/metrics"
 	shadownotify "microgrid-cloud/internal/shadowrun/notify"
@@ -20,8 +25,16 @@ const (
 	jobStatusRunning = "running"
 	jobStatusSuccess = "succeeded"
 	jobStatusFailed  = "failed"
+
+	actionReplayMissingHours = "replay_missing_hours"
+
+	alertStatusOpen      = "open"
+	alertStatusRecovered = "recovered"
 )
 
+// ActionNone is the recommended action of a report within all thresholds.
+const ActionNone = "none"
+
 // Runner executes shadowrun jobs.
 type Runner struct {
 	repo          *shadowrepo.Repository
@@ -33,25 +46,81 @@ type Runner struct {
 	publicBaseURL string
 	storageRoot   string
 	fallbackPrice float64
+	healMode      recon.HealMode
+	healChecker   recon.TelemetryChecker
+	healPublisher recon.WindowPublisher
+	// notifyRecovered closes open alerts once a report is within thresholds.
+	notifyRecovered bool
+	// lateAfter is how long after its start an hour may complete before it
+	// counts as late data; 0 skips the count.
+	lateAfter time.Duration
+	clock     clock.Clock
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithRunnerClock overrides the clock that stamps jobs and reports and bounds
+// which hours auto-heal considers missing.
+func WithRunnerClock(c clock.Clock) RunnerOption {
+	return func(r *Runner) {
+		if c != nil {
+			r.clock = c
+		}
+	}
+}
+
+// WithHealPublisher sets where auto-heal republishes missing hour windows.
+func WithHealPublisher(publisher recon.WindowPublisher) RunnerOption {
+	return func(r *Runner) {
+		r.healPublisher = publisher
+	}
 }
 
 // NewRunner constructs a Runner.
-func NewRunner(repo *shadowrepo.Repository, db *sql.DB, cfg Config, notifier shadownotify.Notifier, metrics *shadowmetrics.Metrics, logger *log.Logger) *Runner {
-	return &Runner{
-		repo:          repo,
-		db:            db,
-		thresholds:    cfg,
-		notifier:      notifier,
-		metrics:       metrics,
-		logger:        logger,
-		publicBaseURL: cfg.PublicBaseURL,
-		storageRoot:   cfg.StorageRoot,
-		fallbackPrice: cfg.FallbackPrice,
+func NewRunner(repo *shadowrepo.Repository, db *sql.DB, cfg Config, notifier shadownotify.Notifier, metrics *shadowmetrics.Metrics, logger *log.Logger, opts ...RunnerOption) *Runner {
+	healMode, err := recon.ParseHealMode(cfg.AutoHeal)
+	if err != nil {
+		healMode = recon.HealOff
+	}
+	r := &Runner{
+		repo:            repo,
+		db:              db,
+		thresholds:      cfg,
+		notifier:        notifier,
+		metrics:         metrics,
+		logger:          logger,
+		publicBaseURL:   cfg.PublicBaseURL,
+		storageRoot:     cfg.StorageRoot,
+		fallbackPrice:   cfg.FallbackPrice,
+		healMode:        healMode,
+		healChecker:     recon.NewSQLTelemetryChecker(db),
+		notifyRecovered: cfg.NotifyRecovered,
+		lateAfter:       cfg.LateAfter,
+		clock:           clock.System{},
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
 }
 
 // Run executes a shadowrun job for a station/month.
 func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time.Time, jobDate time.Time, override *Thresholds) (*shadowrepo.Report, error) {
+	return r.run(ctx, tenantID, stationID, month, jobDate, "", override)
+}
+
+// Rerun reconciles month again even when a report for the same job date
+// already exists. Each call records its own job keyed by at, so a same-day
+// rerun produces a fresh report instead of returning the earlier one.
+func (r *Runner) Rerun(ctx context.Context, tenantID, stationID string, month time.Time, at time.Time, override *Thresholds) (*shadowrepo.Report, error) {
+	at = at.UTC()
+	return r.run(ctx, tenantID, stationID, month, at, at.Format("150405.000000000"), override)
+}
+
+func (r *Runner) run(ctx context.Context, tenantID, stationID string, month time.Time, jobDate time.Time, runKey string, override *Thresholds) (*shadowrepo.Report, error) {
 	if r == nil {
 		return nil, fmt.Errorf("shadowrun runner: nil")
 	}
@@ -66,6 +135,9 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 	}
 
 	jobID := fmt.Sprintf("sr-%s-%s-%s", stationID, monthStart.Format("200601"), jobDate.Format("20060102"))
+	if runKey != "" {
+		jobID += "-" + runKey
+	}
 	job, err := r.repo.CreateJob(ctx, &shadowrepo.Job{
 		ID:        jobID,
 		TenantID:  tenantID,
@@ -73,6 +145,7 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 		Month:     monthStart,
 		JobDate:   jobDate,
 		JobType:   jobTypeShadowrun,
+		RunKey:    runKey,
 		Status:    jobStatusCreated,
 	})
 	if err != nil {
@@ -86,7 +159,7 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 		return nil, fmt.Errorf("shadowrun job already running")
 	}
 
-	started := time.Now().UTC()
+	started := r.clock.Now().UTC()
 	_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusRunning, "", &started, nil, true)
 	if r.metrics != nil {
 		r.metrics.JobsTotal.WithLabelValues(jobStatusRunning).Inc()
@@ -100,7 +173,7 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 
 	result, _, _, err := reconcile(ctx, r.db, tenantID, stationID, monthStart, monthEnd, r.fallbackPrice)
 	if err != nil {
-		ended := time.Now().UTC()
+		ended := r.clock.Now().UTC()
 		_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusFailed, err.Error(), &started, &ended, false)
 		if r.metrics != nil {
 			r.metrics.JobsTotal.WithLabelValues(jobStatusFailed).Inc()
@@ -111,7 +184,7 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 
 	reportDir := filepath.Join(r.storageRoot, tenantID, stationID, monthStart.Format("2006-01"), job.ID)
 	if err := writeReports(reportDir, result); err != nil {
-		ended := time.Now().UTC()
+		ended := r.clock.Now().UTC()
 		_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusFailed, err.Error(), &started, &ended, false)
 		if r.metrics != nil {
 			r.metrics.JobsTotal.WithLabelValues(jobStatusFailed).Inc()
@@ -122,22 +195,38 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 
 	summary, err := buildDiffSummary(result, monthStart, monthEnd, jobDate, thresholds)
 	if err != nil {
-		ended := time.Now().UTC()
+		ended := r.clock.Now().UTC()
 		_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusFailed, err.Error(), &started, &ended, false)
 		if r.metrics != nil {
 			r.metrics.JobsTotal.WithLabelValues(jobStatusFailed).Inc()
 		}
 		return nil, err
 	}
+	if r.lateAfter > 0 {
+		late, err := r.countLateHours(ctx, stationID, monthStart, monthEnd)
+		if err != nil {
+			r.logf("shadowrun_late_data_failed", tenantID, stationID, job.ID, "", err.Error())
+		} else {
+			summary.LateDataCount = late
+		}
+	}
+	if previous, err := r.previousMonthReport(ctx, tenantID, stationID, monthStart, jobDate); err != nil {
+		r.logf("shadowrun_trend_failed", tenantID, stationID, job.ID, "", err.Error())
+	} else {
+		summary.Trend = compareToPrevious(summary, previous)
+	}
+	recommended := recommendedAction(summary, thresholds)
+	if recommended == actionReplayMissingHours && r.healMode != recon.HealOff {
+		summary.AutoHeal = r.autoHeal(ctx, tenantID, stationID, job.ID, result, monthStart, monthEnd, jobDate)
+	}
 	_ = writeSummaryJSON(reportDir, summary)
 	archivePath, err := writeArchive(reportDir)
 	if err != nil {
-		ended := time.Now().UTC()
+		ended := r.clock.Now().UTC()
 		_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusFailed, err.Error(), &started, &ended, false)
 		return nil, err
 	}
 
-	recommended := recommendedAction(summary, thresholds)
 	summaryBytes, _ := json.Marshal(summary)
 	reportID := "report-" + job.ID
 
@@ -155,11 +244,11 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 		DiffAmountMax:     summary.DiffAmountMax,
 		MissingHours:      summary.MissingHoursTotal,
 		RecommendedAction: recommended,
-		CreatedAt:         time.Now().UTC(),
+		CreatedAt:         r.clock.Now().UTC(),
 	}
 
 	if err := r.repo.CreateReport(ctx, report); err != nil {
-		ended := time.Now().UTC()
+		ended := r.clock.Now().UTC()
 		_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusFailed, err.Error(), &started, &ended, false)
 		return nil, err
 	}
@@ -171,9 +260,11 @@ func (r *Runner) Run(ctx context.Context, tenantID, stationID string, month time
 		} else if r.metrics != nil {
 			r.metrics.AlertsTotal.Inc()
 		}
+	} else if r.notifyRecovered {
+		r.recoverAlerts(ctx, report, summary)
 	}
 
-	ended := time.Now().UTC()
+	ended := r.clock.Now().UTC()
 	_ = r.repo.UpdateJobStatus(ctx, job.ID, jobStatusSuccess, "", &started, &ended, false)
 	if r.metrics != nil {
 		r.metrics.JobsTotal.WithLabelValues(jobStatusSuccess).Inc()
@@ -209,8 +300,8 @@ func (r *Runner) createAlert(ctx context.Context, report *shadowrepo.Report, sum
 		Message:   fmt.Sprintf("Diff exceeds threshold for %s %s", report.StationID, summary.Month),
 		Payload:   payloadBytes,
 		ReportID:  report.ID,
-		Status:    "open",
-		CreatedAt: time.Now().UTC(),
+		Status:    alertStatusOpen,
+		CreatedAt: r.clock.Now().UTC(),
 	}
 	if err := r.repo.CreateSystemAlert(ctx, alert); err != nil {
 		return err
@@ -231,6 +322,49 @@ func (r *Runner) createAlert(ctx context.Context, report *shadowrepo.Report, sum
 	return nil
 }
 
+// recoverAlerts closes the station's open alerts for the report's month after
+// a within-threshold report and notifies that it recovered. Failures are logged and never fail
+// the job.
+func (r *Runner) recoverAlerts(ctx context.Context, report *shadowrepo.Report, summary diffSummary) {
+	alerts, err := r.repo.ResolveOpenAlerts(ctx, report.TenantID, report.StationID, report.Month, alertStatusRecovered)
+	if err != nil {
+		r.logf("shadowrun_recover_failed", report.TenantID, report.StationID, report.JobID, report.ID, err.Error())
+		return
+	}
+	if len(alerts) == 0 {
+		return
+	}
+	ids := make([]string, 0, len(alerts))
+	for _, alert := range alerts {
+		ids = append(ids, alert.ID)
+	}
+	if r.metrics != nil {
+		r.metrics.RecoveriesTotal.Inc()
+	}
+	r.logf("shadowrun_recovered", report.TenantID, report.StationID, report.JobID, report.ID, "")
+	if r.notifier == nil {
+		return
+	}
+	msg := shadownotify.AlertMessage{
+		Status:    shadownotify.StatusRecovered,
+		TenantID:  report.TenantID,
+		StationID: report.StationID,
+		Month:     summary.Month,
+		ReportID:  report.ID,
+		ReportURL: fmt.Sprintf("%s/api/v1/shadowrun/reports/%s/download", r.publicBaseURL, report.ID),
+		DiffSummary: map[string]any{
+			"diff_energy_max": summary.DiffEnergyMax,
+			"diff_amount_max": summary.DiffAmountMax,
+			"missing_hours":   summary.MissingHoursTotal,
+			"late_data_count": summary.LateDataCount,
+		},
+		Meta: map[string]string{"job_id": report.JobID, "recovered_alerts": strings.Join(ids, ",")},
+	}
+	if err := r.notifier.Notify(ctx, msg); err != nil {
+		r.logf("shadowrun_recover_failed", report.TenantID, report.StationID, report.JobID, report.ID, err.Error())
+	}
+}
+
 func isThresholdExceeded(summary diffSummary, thresholds Thresholds) bool {
 	if thresholds.MissingHours > 0 && summary.MissingHoursTotal >= thresholds.MissingHours {
 		return true
@@ -246,7 +380,7 @@ func isThresholdExceeded(summary diffSummary, thresholds Thresholds) bool {
 
 func recommendedAction(summary diffSummary, thresholds Thresholds) string {
 	if thresholds.MissingHours > 0 && summary.MissingHoursTotal >= thresholds.MissingHours {
-		return "replay_missing_hours"
+		return actionReplayMissingHours
 	}
 	if thresholds.EnergyAbs > 0 && summary.DiffEnergyMax >= thresholds.EnergyAbs {
 		return "check_mapping_or_tariff"
@@ -254,7 +388,31 @@ func recommendedAction(summary diffSummary, thresholds Thresholds) string {
 	if thresholds.AmountAbs > 0 && summary.DiffAmountMax >= thresholds.AmountAbs {
 		return "check_tariff_or_settlement"
 	}
-	return "none"
+	return ActionNone
+}
+
+// autoHeal republishes the missing hours that have telemetry, or only lists
+// them in dry-run mode. Failures are logged and never fail the job.
+func (r *Runner) autoHeal(ctx context.Context, tenantID, stationID, jobID string, result reconcileResult, monthStart, monthEnd, jobDate time.Time) *recon.HealResult {
+	missing := recon.MissingHourStarts(reconHours(result), monthStart, monthEnd, jobDate, r.clock.Now().UTC())
+	heal, err := recon.Heal(ctx, r.healMode, stationID, missing, r.healChecker, r.healPublisher)
+	if err != nil {
+		r.logf("shadowrun_auto_heal_failed", tenantID, stationID, jobID, "", err.Error())
+	} else {
+		r.logf("shadowrun_auto_heal", tenantID, stationID, jobID, "", "")
+	}
+	return &heal
+}
+
+// countLateHours counts the month's hours whose statistic completed more than
+// lateAfter after the hour started.
+func (r *Runner) countLateHours(ctx context.Context, stationID string, monthStart, monthEnd time.Time) (int, error) {
+	repo := analyticsrepo.NewPostgresStatisticRepository(r.db, stationID)
+	late, err := repo.ListLateByGranularityAndPeriod(ctx, domainstatistic.GranularityHour, monthStart, monthEnd, r.lateAfter)
+	if err != nil {
+		return 0, err
+	}
+	return len(late), nil
 }
 
 func (r *Runner) logf(event, tenantID, stationID, jobID, reportID, errMsg string) {
@@ -2,28 +2,96 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
 	"time"
+
+	obsmetrics "microgrid-cloud/internal/observability/metrics"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+	shadowmetrics "microgrid-cloud/internal/shadowrun/metrics"
+)
+
+// Defaults of the scheduled run.
+const (
+	DefaultScheduleConcurrency    = 4
+	DefaultScheduleStationTimeout = 30 * time.Minute
+)
+
+// Scheduled station run results reported in the station duration metric.
+const (
+	stationResultSuccess = "success"
+	stationResultError   = "error"
+	stationResultTimeout = "timeout"
 )
 
+// StationRunner runs the shadowrun job of one station; *Runner implements it.
+type StationRunner interface {
+	Run(ctx context.Context, tenantID, stationID string, month time.Time, jobDate time.Time, override *Thresholds) (*shadowrepo.Report, error)
+}
+
 // Scheduler triggers shadowrun jobs on schedule.
 type Scheduler struct {
-	runner   *Runner
-	tenantID string
-	stations []string
-	dailyAt  string
-	logger   *log.Logger
+	runner         StationRunner
+	tenantID       string
+	stations       []string
+	dailyAt        string
+	logger         *log.Logger
+	concurrency    int
+	stationTimeout time.Duration
+	metrics        *shadowmetrics.Metrics
+}
+
+// SchedulerOption configures a Scheduler.
+type SchedulerOption func(*Scheduler)
+
+// WithConcurrency runs up to n stations at a time; n below 1 runs them one
+// after another.
+func WithConcurrency(n int) SchedulerOption {
+	return func(s *Scheduler) {
+		if n < 1 {
+			n = 1
+		}
+		s.concurrency = n
+	}
+}
+
+// WithStationTimeout bounds the run of each station. A station that runs out
+// of time is reported as timed out and its slot goes to the next station,
+// even if its runner does not return. 0 disables the limit.
+func WithStationTimeout(timeout time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if timeout >= 0 {
+			s.stationTimeout = timeout
+		}
+	}
+}
+
+// WithSchedulerMetrics reports the duration of each scheduled station run.
+func WithSchedulerMetrics(metrics *shadowmetrics.Metrics) SchedulerOption {
+	return func(s *Scheduler) {
+		s.metrics = metrics
+	}
 }
 
 // NewScheduler constructs a Scheduler.
-func NewScheduler(runner *Runner, tenantID string, stations []string, dailyAt string, logger *log.Logger) *Scheduler {
-	return &Scheduler{
-		runner:   runner,
-		tenantID: tenantID,
-		stations: stations,
-		dailyAt:  dailyAt,
-		logger:   logger,
+func NewScheduler(runner StationRunner, tenantID string, stations []string, dailyAt string, logger *log.Logger, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		runner:         runner,
+		tenantID:       tenantID,
+		stations:       stations,
+		dailyAt:        dailyAt,
+		logger:         logger,
+		concurrency:    DefaultScheduleConcurrency,
+		stationTimeout: DefaultScheduleStationTimeout,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
 	}
+	return s
 }
 
 // Start begins the scheduler loop.
@@ -55,19 +123,78 @@ func (s *Scheduler) shouldRun(now time.Time) bool {
 	return now.Hour() == hour && now.Minute() == minute
 }
 
+// runOnce runs every scheduled station, at most concurrency at a time, and
+// returns once each has finished or timed out.
 func (s *Scheduler) runOnce(ctx context.Context, now time.Time) {
 	if len(s.stations) == 0 {
 		return
 	}
 	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 	jobDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	slots := make(chan struct{}, max(s.concurrency, 1))
+	var wg sync.WaitGroup
 	for _, stationID := range s.stations {
 		if stationID == "" {
 			continue
 		}
-		if _, err := s.runner.Run(ctx, s.tenantID, stationID, month, jobDate, nil); err != nil && s.logger != nil {
-			s.logger.Printf("shadowrun schedule error: station=%s err=%v", stationID, err)
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case slots <- struct{}{}:
 		}
+		wg.Add(1)
+		go func(stationID string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			s.runStation(ctx, stationID, month, jobDate)
+		}(stationID)
+	}
+	wg.Wait()
+}
+
+// runStation runs one station under the station timeout and records its
+// duration. The runner is called on its own goroutine so that a runner
+// ignoring its context cannot hold the slot past the timeout.
+func (s *Scheduler) runStation(ctx context.Context, stationID string, month, jobDate time.Time) {
+	if s.stationTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.stationTimeout)
+		defer cancel()
+	}
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("shadowrun schedule: station %s panicked: %v", stationID, r)
+			}
+		}()
+		_, err := s.runner.Run(ctx, s.tenantID, stationID, month, jobDate, nil)
+		done <- err
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := stationResultSuccess
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		result = stationResultTimeout
+	case err != nil:
+		result = stationResultError
+	}
+	if s.metrics != nil && s.metrics.StationDuration != nil {
+		s.metrics.StationDuration.WithLabelValues(obsmetrics.StationLabel(stationID), result).Observe(time.Since(started).Seconds())
+	}
+	if err != nil && s.logger != nil {
+		s.logger.Printf("shadowrun schedule error: station=%s result=%s duration=%s err=%v", stationID, result, time.Since(started).Round(time.Millisecond), err)
 	}
 }
 
@@ -0,0 +1,133 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	obsmetrics "microgrid-cloud/internal/observability/metrics"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+	shadowmetrics "microgrid-cloud/internal/shadowrun/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestScheduler_RunsStationsConcurrentlyAndIsolated(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	runner := &fakeStationRunner{release: release}
+	metrics := shadowmetrics.New()
+
+	stations := []string{"station-hung", "station-boom", "station-fail", "station-1", "station-2", "station-3", "station-4"}
+	// station-4 is not in the stations table, so it is reported as "other".
+	obsmetrics.SetKnownStations(stations[:6])
+	defer obsmetrics.SetKnownStations(nil)
+	scheduler := NewScheduler(runner, "tenant-sched", stations, "02:00", nil,
+		WithConcurrency(3),
+		WithStationTimeout(200*time.Millisecond),
+		WithSchedulerMetrics(metrics),
+	)
+
+	started := time.Now()
+	scheduler.runOnce(context.Background(), time.Date(2026, time.March, 5, 2, 0, 0, 0, time.UTC))
+	elapsed := time.Since(started)
+
+	// The hung station ignores its context; the run still ends once it times
+	// out, and the healthy stations are not held up by it.
+	if elapsed > 2*time.Second {
+		t.Fatalf("run took %s, want the hung station cut off after its timeout", elapsed)
+	}
+	for _, stationID := range stations {
+		if !runner.called(stationID) {
+			t.Fatalf("station %s was not run", stationID)
+		}
+	}
+	if got := runner.maxActive.Load(); got != 3 {
+		t.Fatalf("max concurrent stations = %d, want 3", got)
+	}
+	if runner.finished.Load() != 4 {
+		t.Fatalf("healthy stations finished = %d, want 4", runner.finished.Load())
+	}
+	if runner.month != time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC) || runner.jobDate != time.Date(2026, time.March, 5, 0, 0, 0, 0, time.UTC) {
+		t.Fatalf("month=%s jobDate=%s", runner.month, runner.jobDate)
+	}
+
+	results := map[string]string{
+		"station-hung":        stationResultTimeout,
+		"station-boom":        stationResultError,
+		"station-fail":        stationResultError,
+		"station-1":           stationResultSuccess,
+		obsmetrics.LabelOther: stationResultSuccess,
+	}
+	for stationID, result := range results {
+		var metric dto.Metric
+		observer := metrics.StationDuration.WithLabelValues(stationID, result)
+		if err := observer.(prometheus.Histogram).Write(&metric); err != nil {
+			t.Fatalf("read %s metric: %v", stationID, err)
+		}
+		if got := metric.GetHistogram().GetSampleCount(); got != 1 {
+			t.Fatalf("station %s result %s observed %d times, want 1", stationID, result, got)
+		}
+	}
+	if n := testutil.CollectAndCount(metrics.StationDuration); n != len(stations) {
+		t.Fatalf("station duration series = %d, want %d", n, len(stations))
+	}
+}
+
+// fakeStationRunner records the stations it runs. station-hung blocks until
+// the test ends regardless of its context, station-boom panics and
+// station-fail returns an error; the others take a moment and succeed.
+type fakeStationRunner struct {
+	release <-chan struct{}
+
+	mu        sync.Mutex
+	calls     map[string]bool
+	month     time.Time
+	jobDate   time.Time
+	active    atomic.Int32
+	maxActive atomic.Int32
+	finished  atomic.Int32
+}
+
+func (f *fakeStationRunner) Run(ctx context.Context, tenantID, stationID string, month time.Time, jobDate time.Time, override *Thresholds) (*shadowrepo.Report, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = make(map[string]bool)
+	}
+	f.calls[stationID] = true
+	f.month, f.jobDate = month, jobDate
+	f.mu.Unlock()
+
+	active := f.active.Add(1)
+	defer f.active.Add(-1)
+	for {
+		peak := f.maxActive.Load()
+		if active <= peak || f.maxActive.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	switch stationID {
+	case "station-hung":
+		<-f.release
+		return nil, nil
+	case "station-boom":
+		panic("runner bug")
+	case "station-fail":
+		return nil, errors.New("reconcile failed")
+	}
+	time.Sleep(50 * time.Millisecond)
+	f.finished.Add(1)
+	return &shadowrepo.Report{}, nil
+}
+
+func (f *fakeStationRunner) called(stationID string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[stationID]
+}
@@ -0,0 +1,83 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+)
+
+// trendEpsilon ignores float noise when comparing diffs between months.
+const trendEpsilon = 1e-6
+
+// monthTrend compares the max diffs of a report with the latest report of the
+// previous month, so stations whose reconciliation is degrading stand out.
+type monthTrend struct {
+	PreviousReportID    string   `json:"previous_report_id"`
+	PreviousMonth       string   `json:"previous_month"`
+	PreviousEnergyMax   float64  `json:"previous_diff_energy_max"`
+	PreviousAmountMax   float64  `json:"previous_diff_amount_max"`
+	PreviousMissing     int      `json:"previous_missing_hours_total"`
+	DiffEnergyMaxChange float64  `json:"diff_energy_max_change"`
+	DiffAmountMaxChange float64  `json:"diff_amount_max_change"`
+	MissingHoursChange  int      `json:"missing_hours_total_change"`
+	Worsening           bool     `json:"worsening"`
+	Worsened            []string `json:"worsened,omitempty"`
+}
+
+// previousMonthReport returns the latest report of the month before
+// monthStart for the station, or nil when there is none. Reports of a month
+// are dated within it or, for reruns, later, so the search runs up to jobDate.
+func (r *Runner) previousMonthReport(ctx context.Context, tenantID, stationID string, monthStart, jobDate time.Time) (*shadowrepo.Report, error) {
+	previousMonth := monthStart.AddDate(0, -1, 0)
+	reports, err := r.repo.ListReports(ctx, stationID, previousMonth, jobDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+	// Reports are listed newest first.
+	for i := range reports {
+		if reports[i].TenantID == tenantID && reports[i].Month.Equal(previousMonth) {
+			return &reports[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// compareToPrevious builds the trend of summary against the previous month's
+// report; nil without one. A diff counts as worsened when it grew.
+func compareToPrevious(summary diffSummary, previous *shadowrepo.Report) *monthTrend {
+	if previous == nil {
+		return nil
+	}
+	trend := &monthTrend{
+		PreviousReportID:  previous.ID,
+		PreviousMonth:     previous.Month.Format("2006-01"),
+		PreviousEnergyMax: previous.DiffEnergyKWhMax,
+		PreviousAmountMax: previous.DiffAmountMax,
+		PreviousMissing:   previous.MissingHours,
+	}
+	// Older reports only carry the totals in their columns; the summary JSON
+	// is authoritative when it parses.
+	var prior diffSummary
+	if err := json.Unmarshal(previous.DiffSummary, &prior); err == nil && prior.Month != "" {
+		trend.PreviousEnergyMax = prior.DiffEnergyMax
+		trend.PreviousAmountMax = prior.DiffAmountMax
+		trend.PreviousMissing = prior.MissingHoursTotal
+	}
+
+	trend.DiffEnergyMaxChange = summary.DiffEnergyMax - trend.PreviousEnergyMax
+	trend.DiffAmountMaxChange = summary.DiffAmountMax - trend.PreviousAmountMax
+	trend.MissingHoursChange = summary.MissingHoursTotal - trend.PreviousMissing
+	if trend.DiffEnergyMaxChange > trendEpsilon {
+		trend.Worsened = append(trend.Worsened, "diff_energy_max")
+	}
+	if trend.DiffAmountMaxChange > trendEpsilon {
+		trend.Worsened = append(trend.Worsened, "diff_amount_max")
+	}
+	if trend.MissingHoursChange > 0 {
+		trend.Worsened = append(trend.Worsened, "missing_hours_total")
+	}
+	trend.Worsening = len(trend.Worsened) > 0
+	return trend
+}
@@ -0,0 +1,40 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	recon "microgrid-cloud/internal/reconcile"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+)
+
+func TestCompareToPrevious(t *testing.T) {
+	if trend := compareToPrevious(diffSummary{}, nil); trend != nil {
+		t.Fatalf("trend without previous report = %+v, want nil", trend)
+	}
+
+	previous := &shadowrepo.Report{
+		ID:               "report-jan",
+		Month:            time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		DiffEnergyKWhMax: 4,
+		DiffAmountMax:    10,
+		MissingHours:     2,
+		DiffSummary:      []byte(`{"month":"2026-01","diff_energy_max":4.5,"diff_amount_max":10,"missing_hours_total":2}`),
+	}
+
+	improving := compareToPrevious(diffSummary{Summary: recon.Summary{DiffEnergyMax: 1, DiffAmountMax: 10, MissingHoursTotal: 0}}, previous)
+	if improving.Worsening || len(improving.Worsened) != 0 {
+		t.Fatalf("improving month flagged worsening: %+v", improving)
+	}
+	if improving.PreviousEnergyMax != 4.5 || improving.DiffEnergyMaxChange != -3.5 || improving.MissingHoursChange != -2 {
+		t.Fatalf("improving trend = %+v, want the previous summary JSON values", improving)
+	}
+
+	worsening := compareToPrevious(diffSummary{Summary: recon.Summary{DiffEnergyMax: 4.5, DiffAmountMax: 12, MissingHoursTotal: 5}}, previous)
+	if !worsening.Worsening || len(worsening.Worsened) != 2 || worsening.Worsened[0] != "diff_amount_max" || worsening.Worsened[1] != "missing_hours_total" {
+		t.Fatalf("worsening trend = %+v, want diff_amount_max and missing_hours_total", worsening)
+	}
+	if worsening.PreviousReportID != "report-jan" || worsening.PreviousMonth != "2026-01" {
+		t.Fatalf("previous report = %s %s", worsening.PreviousReportID, worsening.PreviousMonth)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -15,6 +16,7 @@ type Job struct {
 	Month     time.Time
 	JobDate   time.Time
 	JobType   string
+	RunKey    string
 	Status    string
 	Attempts  int
 	Error     string
@@ -78,24 +80,24 @@ func (r *Repository) CreateJob(ctx context.Context, job *Job) (*Job, error) {
 	now := time.Now().UTC()
 	_, _ = r.db.ExecContext(ctx, `
 INSERT INTO shadowrun_jobs (
-	id, tenant_id, station_id, month, job_date, job_type, status, attempts, created_at, updated_at
+	id, tenant_id, station_id, month, job_date, job_type, run_key, status, attempts, created_at, updated_at
 ) VALUES (
-	$1,$2,$3,$4,$5,$6,$7,0,$8,$8
+	$1,$2,$3,$4,$5,$6,$7,$8,0,$9,$9
 )
-ON CONFLICT (tenant_id, station_id, month, job_date, job_type)
+ON CONFLICT (tenant_id, station_id, month, job_date, job_type, run_key)
 DO NOTHING`,
-		job.ID, job.TenantID, job.StationID, job.Month, job.JobDate, job.JobType, job.Status, now,
+		job.ID, job.TenantID, job.StationID, job.Month, job.JobDate, job.JobType, job.RunKey, job.Status, now,
 	)
-	return r.GetJobByKey(ctx, job.TenantID, job.StationID, job.Month, job.JobDate, job.JobType)
+	return r.GetJobByKey(ctx, job.TenantID, job.StationID, job.Month, job.JobDate, job.JobType, job.RunKey)
 }
 
 // GetJobByKey returns job by unique key.
-func (r *Repository) GetJobByKey(ctx context.Context, tenantID, stationID string, month, jobDate time.Time, jobType string) (*Job, error) {
+func (r *Repository) GetJobByKey(ctx context.Context, tenantID, stationID string, month, jobDate time.Time, jobType, runKey string) (*Job, error) {
 	row := r.db.QueryRowContext(ctx, `
-SELECT id, tenant_id, station_id, month, job_date, job_type, status, attempts, error, created_at, updated_at, started_at, finished_at
+SELECT id, tenant_id, station_id, month, job_date, job_type, run_key, status, attempts, error, created_at, updated_at, started_at, finished_at
 FROM shadowrun_jobs
-WHERE tenant_id = $1 AND station_id = $2 AND month = $3 AND job_date = $4 AND job_type = $5`,
-		tenantID, stationID, month, jobDate, jobType)
+WHERE tenant_id = $1 AND station_id = $2 AND month = $3 AND job_date = $4 AND job_type = $5 AND run_key = $6`,
+		tenantID, stationID, month, jobDate, jobType, runKey)
 
 	return scanJob(row)
 }
@@ -146,17 +148,37 @@ INSERT INTO shadowrun_reports (
 
 // ListReports lists reports for a station and time range.
 func (r *Repository) ListReports(ctx context.Context, stationID string, from, to time.Time) ([]Report, error) {
+	reports, _, err := r.ListReportsPage(ctx, stationID, from, to, 0, 0)
+	return reports, err
+}
+
+// ListReportsPage lists a page of the reports for a station and time range,
+// newest first. A limit above 0 and an offset page the list in SQL; total is
+// the number of reports in the range.
+func (r *Repository) ListReportsPage(ctx context.Context, stationID string, from, to time.Time, limit, offset int) ([]Report, int, error) {
 	if r == nil || r.db == nil {
-		return nil, errors.New("shadowrun repo: nil db")
+		return nil, 0, errors.New("shadowrun repo: nil db")
 	}
-	rows, err := r.db.QueryContext(ctx, `
-SELECT id, job_id, tenant_id, station_id, month, report_date, status, report_location,
-	diff_summary, diff_energy_kwh_max, diff_amount_max, missing_hours, recommended_action, created_at
+	where := `
 FROM shadowrun_reports
-WHERE station_id = $1 AND report_date >= $2 AND report_date < $3
-ORDER BY report_date DESC`, stationID, from.UTC(), to.UTC())
+WHERE station_id = $1 AND report_date >= $2 AND report_date < $3`
+	args := []any{stationID, from.UTC(), to.UTC()}
+	query := `
+SELECT id, job_id, tenant_id, station_id, month, report_date, status, report_location,
+	diff_summary, diff_energy_kwh_max, diff_amount_max, missing_hours, recommended_action, created_at` + where + `
+ORDER BY report_date DESC, created_at DESC, id DESC`
+	pageArgs := append([]any(nil), args...)
+	if limit > 0 {
+		pageArgs = append(pageArgs, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(pageArgs))
+	}
+	if offset > 0 {
+		pageArgs = append(pageArgs, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(pageArgs))
+	}
+	rows, err := r.db.QueryContext(ctx, query, pageArgs...)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	defer rows.Close()
 
@@ -179,7 +201,7 @@ ORDER BY report_date DESC`, stationID, from.UTC(), to.UTC())
 			&report.RecommendedAction,
 			&report.CreatedAt,
 		); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		report.Month = report.Month.UTC()
 		report.ReportDate = report.ReportDate.UTC()
@@ -187,9 +209,16 @@ ORDER BY report_date DESC`, stationID, from.UTC(), to.UTC())
 		result = append(result, report)
 	}
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return result, nil
+	if limit <= 0 && offset <= 0 {
+		return result, len(result), nil
+	}
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	return result, total, nil
 }
 
 // GetReport returns report by id.
@@ -250,6 +279,54 @@ INSERT INTO shadowrun_alerts (
 	return err
 }
 
+// ResolveOpenAlerts moves the open shadowrun alerts of a station that were
+// raised by a report of month to status and returns them; none are returned
+// when the station had no open alert for that month. Alerts of other months
+// stay open until a report of their own month recovers.
+func (r *Repository) ResolveOpenAlerts(ctx context.Context, tenantID, stationID string, month time.Time, status string) ([]ShadowrunAlert, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("shadowrun repo: nil db")
+	}
+	rows, err := r.db.QueryContext(ctx, `
+UPDATE shadowrun_alerts a
+SET status = $3
+FROM shadowrun_reports rep
+WHERE rep.id = a.report_id
+	AND rep.month = $4
+	AND a.tenant_id = $1 AND a.station_id = $2 AND a.category = 'shadowrun' AND a.status = 'open'
+RETURNING a.id, a.tenant_id, a.station_id, a.category, a.severity, a.title, a.message, a.payload, a.report_id, a.status, a.created_at`,
+		tenantID, stationID, status, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []ShadowrunAlert
+	for rows.Next() {
+		var alert ShadowrunAlert
+		var reportID sql.NullString
+		if err := rows.Scan(
+			&alert.ID,
+			&alert.TenantID,
+			&alert.StationID,
+			&alert.Category,
+			&alert.Severity,
+			&alert.Title,
+			&alert.Message,
+			&alert.Payload,
+			&reportID,
+			&alert.Status,
+			&alert.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		alert.ReportID = reportID.String
+		alert.CreatedAt = alert.CreatedAt.UTC()
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
 type rowScanner interface {
 	Scan(dest ...any) error
 }
@@ -266,6 +343,7 @@ func scanJob(row rowScanner) (*Job, error) {
 		&job.Month,
 		&job.JobDate,
 		&job.JobType,
+		&job.RunKey,
 		&job.Status,
 		&job.Attempts,
 		&errMsg,
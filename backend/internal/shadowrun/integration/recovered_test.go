@@ -0,0 +1,173 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+	"time"
+
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+	shadownotify "microgrid-cloud/internal/shadowrun/notify"
+)
+
+func TestShadowrun_RecoveredClosesOpenAlert(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyShadowMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	cleanupShadowTables(ctx, db)
+
+	cfg := shadowapp.Config{
+		Defaults: shadowapp.Thresholds{
+			EnergyAbs:    5,
+			AmountAbs:    5,
+			MissingHours: 2,
+		},
+		StorageRoot:     t.TempDir(),
+		PublicBaseURL:   "http://localhost:8080",
+		FallbackPrice:   1.0,
+		NotifyRecovered: true,
+	}
+	notifier := &recordingNotifier{}
+	runner := shadowapp.NewRunner(shadowrepo.NewRepository(db), db, cfg, notifier, nil, nil)
+
+	month := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	dayStart := month.AddDate(0, 0, 2)
+
+	// The settlement is off by 76, so the first run alerts.
+	if err := seedHourAndSettlement(ctx, db, "tenant-recover", "station-recover", dayStart, 24, 1, 100); err != nil {
+		t.Fatalf("seed exceeded: %v", err)
+	}
+	exceeded, err := runner.Run(ctx, "tenant-recover", "station-recover", month, time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run exceeded: %v", err)
+	}
+	if got := alertStatus(t, db, "alert-"+exceeded.ID); got != "open" {
+		t.Fatalf("alert status after exceeded run = %q, want open", got)
+	}
+
+	// Once the settlement is corrected the next report is within thresholds.
+	if err := seedHourAndSettlement(ctx, db, "tenant-recover", "station-recover", dayStart, 24, 1, 24); err != nil {
+		t.Fatalf("seed corrected: %v", err)
+	}
+	recovered, err := runner.Run(ctx, "tenant-recover", "station-recover", month, time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run recovered: %v", err)
+	}
+	if got := alertStatus(t, db, "alert-"+exceeded.ID); got != "recovered" {
+		t.Fatalf("alert status after recovered run = %q, want recovered", got)
+	}
+
+	msgs := notifier.messages()
+	if len(msgs) != 2 {
+		t.Fatalf("notifications = %d, want alert then recovered", len(msgs))
+	}
+	if msgs[0].Status != "" || msgs[1].Status != shadownotify.StatusRecovered {
+		t.Fatalf("notification statuses = %q, %q", msgs[0].Status, msgs[1].Status)
+	}
+	if msgs[1].ReportID != recovered.ID || msgs[1].Meta["recovered_alerts"] != "alert-"+exceeded.ID {
+		t.Fatalf("recovered notification = %+v", msgs[1])
+	}
+
+	// Nothing is open any more, so a further clean report stays quiet.
+	if _, err := runner.Run(ctx, "tenant-recover", "station-recover", month, time.Date(2026, time.January, 17, 0, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("run clean: %v", err)
+	}
+	if n := len(notifier.messages()); n != 2 {
+		t.Fatalf("notifications after clean run = %d, want 2", n)
+	}
+}
+
+func TestShadowrun_RecoveredLeavesOtherMonthsOpen(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyShadowMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	cleanupShadowTables(ctx, db)
+
+	cfg := shadowapp.Config{
+		Defaults: shadowapp.Thresholds{
+			EnergyAbs:    5,
+			AmountAbs:    5,
+			MissingHours: 2,
+		},
+		StorageRoot:     t.TempDir(),
+		PublicBaseURL:   "http://localhost:8080",
+		FallbackPrice:   1.0,
+		NotifyRecovered: true,
+	}
+	notifier := &recordingNotifier{}
+	runner := shadowapp.NewRunner(shadowrepo.NewRepository(db), db, cfg, notifier, nil, nil)
+
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	// January's settlement is off, so its report alerts.
+	if err := seedHourAndSettlement(ctx, db, "tenant-months", "station-months", january.AddDate(0, 0, 2), 24, 1, 100); err != nil {
+		t.Fatalf("seed january: %v", err)
+	}
+	exceeded, err := runner.Run(ctx, "tenant-months", "station-months", january, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run january: %v", err)
+	}
+
+	// A clean February says nothing about January, whose alert stays open.
+	if err := seedHourAndSettlement(ctx, db, "tenant-months", "station-months", february.AddDate(0, 0, 2), 24, 1, 24); err != nil {
+		t.Fatalf("seed february: %v", err)
+	}
+	if _, err := runner.Run(ctx, "tenant-months", "station-months", february, time.Date(2026, time.February, 10, 0, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("run february: %v", err)
+	}
+	if got := alertStatus(t, db, "alert-"+exceeded.ID); got != "open" {
+		t.Fatalf("january alert after clean february = %q, want open", got)
+	}
+	if n := len(notifier.messages()); n != 1 {
+		t.Fatalf("notifications after clean february = %d, want only the january alert", n)
+	}
+
+	// Correcting January and rerunning it recovers the alert.
+	if err := seedHourAndSettlement(ctx, db, "tenant-months", "station-months", january.AddDate(0, 0, 2), 24, 1, 24); err != nil {
+		t.Fatalf("seed corrected january: %v", err)
+	}
+	if _, err := runner.Run(ctx, "tenant-months", "station-months", january, time.Date(2026, time.February, 11, 0, 0, 0, 0, time.UTC), nil); err != nil {
+		t.Fatalf("rerun january: %v", err)
+	}
+	if got := alertStatus(t, db, "alert-"+exceeded.ID); got != "recovered" {
+		t.Fatalf("january alert after clean january = %q, want recovered", got)
+	}
+}
+
+func alertStatus(t *testing.T, db *sql.DB, alertID string) string {
+	t.Helper()
+	var status string
+	if err := db.QueryRowContext(context.Background(), "SELECT status FROM shadowrun_alerts WHERE id = $1", alertID).Scan(&status); err != nil {
+		t.Fatalf("load alert %s: %v", alertID, err)
+	}
+	return status
+}
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	msgs []shadownotify.AlertMessage
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg shadownotify.AlertMessage) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.msgs = append(n.msgs, msg)
+	return nil
+}
+
+func (n *recordingNotifier) messages() []shadownotify.AlertMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]shadownotify.AlertMessage(nil), n.msgs...)
+}
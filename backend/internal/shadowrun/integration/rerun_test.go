@@ -0,0 +1,69 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+)
+
+func TestShadowrun_RerunSameDayProducesFreshReport(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyShadowMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	cleanupShadowTables(ctx, db)
+
+	cfg := shadowapp.Config{
+		Defaults: shadowapp.Thresholds{
+			EnergyAbs:    5,
+			AmountAbs:    5,
+			MissingHours: 2,
+		},
+		StorageRoot:   t.TempDir(),
+		FallbackPrice: 1.0,
+	}
+	runner := shadowapp.NewRunner(shadowrepo.NewRepository(db), db, cfg, nil, nil, nil)
+	month := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	jobDate := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	// The settlement amount disagrees with the hours, so the first run flags it.
+	if err := seedHourAndSettlement(ctx, db, "tenant-rerun", "station-rerun", month.AddDate(0, 0, 2), 24, 1, 100); err != nil {
+		t.Fatalf("seed hours: %v", err)
+	}
+	first, err := runner.Run(ctx, "tenant-rerun", "station-rerun", month, jobDate, nil)
+	if err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.RecommendedAction == shadowapp.ActionNone {
+		t.Fatalf("first run action = %s, want a mismatch", first.RecommendedAction)
+	}
+
+	// Once the settlement is fixed, the same-day scheduled run still returns
+	// the cached report, but a rerun reconciles again.
+	if err := seedHourAndSettlement(ctx, db, "tenant-rerun", "station-rerun", month.AddDate(0, 0, 2), 24, 1, 24); err != nil {
+		t.Fatalf("fix settlement: %v", err)
+	}
+	cached, err := runner.Run(ctx, "tenant-rerun", "station-rerun", month, jobDate, nil)
+	if err != nil {
+		t.Fatalf("cached run: %v", err)
+	}
+	if cached.ID != first.ID {
+		t.Fatalf("scheduled same-day run report = %s, want %s", cached.ID, first.ID)
+	}
+	rerun, err := runner.Rerun(ctx, "tenant-rerun", "station-rerun", month, jobDate.Add(9*time.Hour), nil)
+	if err != nil {
+		t.Fatalf("rerun: %v", err)
+	}
+	if rerun.ID == first.ID {
+		t.Fatalf("rerun returned the earlier report %s", rerun.ID)
+	}
+	if rerun.RecommendedAction != shadowapp.ActionNone {
+		t.Fatalf("rerun action = %s, want %s", rerun.RecommendedAction, shadowapp.ActionNone)
+	}
+}
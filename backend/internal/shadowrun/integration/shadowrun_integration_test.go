@@ -113,6 +113,7 @@ func cleanupShadowTables(ctx context.Context, db *sql.DB) {
 	_, _ = db.ExecContext(ctx, "DELETE FROM shadowrun_alerts")
 	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics")
 	_, _ = db.ExecContext(ctx, "DELETE FROM settlements_day")
+	_, _ = db.ExecContext(ctx, "DELETE FROM tariff_overrides")
 }
 
 func openDB(t *testing.T) *sql.DB {
@@ -137,6 +138,8 @@ func applyShadowMigrations(db *sql.DB) error {
 		filepath.Join(root, "migrations", "008_statements.sql"),
 		filepath.Join(root, "migrations", "011_shadowrun.sql"),
 		filepath.Join(root, "migrations", "014_shadowrun_alerts.sql"),
+		filepath.Join(root, "migrations", "035_tariff_overrides.sql"),
+		filepath.Join(root, "migrations", "038_shadowrun_job_run_key.sql"),
 	}
 	for _, path := range files {
 		content, err := os.ReadFile(path)
@@ -0,0 +1,70 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+)
+
+func TestShadowrun_TariffOverrideTakesPrecedence(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyShadowMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	cleanupShadowTables(ctx, db)
+	_, _ = db.ExecContext(ctx, "DELETE FROM tariff_rules WHERE plan_id = 'plan-override-test'")
+	_, _ = db.ExecContext(ctx, "DELETE FROM tariff_plans WHERE id = 'plan-override-test'")
+
+	cfg := shadowapp.Config{
+		Defaults: shadowapp.Thresholds{
+			EnergyAbs:    5,
+			AmountAbs:    5,
+			MissingHours: 2,
+		},
+		StorageRoot: t.TempDir(),
+	}
+	runner := shadowapp.NewRunner(shadowrepo.NewRepository(db), db, cfg, nil, nil, nil)
+	month := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	// The plan prices January at 1/kWh, but the station was billed 2/kWh.
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO tariff_plans (id, tenant_id, station_id, effective_month, currency, mode)
+VALUES ('plan-override-test', 'tenant-override', 'station-override', $1, 'CNY', 'fixed')`, month); err != nil {
+		t.Fatalf("seed plan: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO tariff_rules (id, plan_id, start_minute, end_minute, price_per_kwh)
+VALUES ('rule-override-test', 'plan-override-test', 0, 1440, 1)`); err != nil {
+		t.Fatalf("seed rule: %v", err)
+	}
+	if err := seedHourAndSettlement(ctx, db, "tenant-override", "station-override", month.AddDate(0, 0, 2), 24, 1, 48); err != nil {
+		t.Fatalf("seed hours: %v", err)
+	}
+
+	planned, err := runner.Run(ctx, "tenant-override", "station-override", month, time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run with plan: %v", err)
+	}
+	if planned.DiffAmountMax != 24 {
+		t.Fatalf("amount diff with plan = %v, want 24", planned.DiffAmountMax)
+	}
+
+	if _, err := db.ExecContext(ctx, `
+INSERT INTO tariff_overrides (tenant_id, station_id, month, price_per_kwh, reason)
+VALUES ('tenant-override', 'station-override', $1, 2, 'plan missed the January rate change')`, month); err != nil {
+		t.Fatalf("seed override: %v", err)
+	}
+	overridden, err := runner.Run(ctx, "tenant-override", "station-override", month, time.Date(2026, time.January, 16, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run with override: %v", err)
+	}
+	if overridden.DiffAmountMax != 0 || overridden.RecommendedAction != shadowapp.ActionNone {
+		t.Fatalf("with override: amount diff = %v, action = %s; want 0 and %s", overridden.DiffAmountMax, overridden.RecommendedAction, shadowapp.ActionNone)
+	}
+}
@@ -0,0 +1,89 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	shadowapp "microgrid-cloud/internal/shadowrun/application"
+	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
+)
+
+func TestShadowrun_TrendAgainstPreviousMonth(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyShadowMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	cleanupShadowTables(ctx, db)
+
+	cfg := shadowapp.Config{
+		Defaults: shadowapp.Thresholds{
+			EnergyAbs:    5,
+			AmountAbs:    5,
+			MissingHours: 2,
+		},
+		StorageRoot:   t.TempDir(),
+		FallbackPrice: 1.0,
+	}
+	runner := shadowapp.NewRunner(shadowrepo.NewRepository(db), db, cfg, nil, nil, nil)
+
+	january := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	// January is off by 76, February by 176.
+	if err := seedHourAndSettlement(ctx, db, "tenant-trend", "station-trend", january.AddDate(0, 0, 2), 24, 1, 100); err != nil {
+		t.Fatalf("seed january: %v", err)
+	}
+	first, err := runner.Run(ctx, "tenant-trend", "station-trend", january, time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run january: %v", err)
+	}
+	if summary := decodeTrendSummary(t, first.DiffSummary); summary.Trend != nil {
+		t.Fatalf("january trend = %+v, want none without a previous report", summary.Trend)
+	}
+
+	if err := seedHourAndSettlement(ctx, db, "tenant-trend", "station-trend", february.AddDate(0, 0, 2), 24, 1, 200); err != nil {
+		t.Fatalf("seed february: %v", err)
+	}
+	second, err := runner.Run(ctx, "tenant-trend", "station-trend", february, time.Date(2026, time.February, 28, 0, 0, 0, 0, time.UTC), nil)
+	if err != nil {
+		t.Fatalf("run february: %v", err)
+	}
+	trend := decodeTrendSummary(t, second.DiffSummary).Trend
+	if trend == nil {
+		t.Fatalf("february summary has no trend")
+	}
+	if trend.PreviousReportID != first.ID || trend.PreviousMonth != "2026-01" {
+		t.Fatalf("trend compares against %s (%s), want %s (2026-01)", trend.PreviousReportID, trend.PreviousMonth, first.ID)
+	}
+	if trend.PreviousAmountMax != 76 || trend.DiffAmountMaxChange != 100 {
+		t.Fatalf("amount trend = %v%+v, want 76+100", trend.PreviousAmountMax, trend.DiffAmountMaxChange)
+	}
+	if !trend.Worsening || len(trend.Worsened) != 1 || trend.Worsened[0] != "diff_amount_max" {
+		t.Fatalf("worsening=%v worsened=%v, want diff_amount_max", trend.Worsening, trend.Worsened)
+	}
+}
+
+type trendSummary struct {
+	Trend *struct {
+		PreviousReportID    string   `json:"previous_report_id"`
+		PreviousMonth       string   `json:"previous_month"`
+		PreviousAmountMax   float64  `json:"previous_diff_amount_max"`
+		DiffAmountMaxChange float64  `json:"diff_amount_max_change"`
+		Worsening           bool     `json:"worsening"`
+		Worsened            []string `json:"worsened"`
+	} `json:"trend"`
+}
+
+func decodeTrendSummary(t *testing.T, raw []byte) trendSummary {
+	t.Helper()
+	var summary trendSummary
+	if err := json.Unmarshal(raw, &summary); err != nil {
+		t.Fatalf("decode diff summary: %v", err)
+	}
+	return summary
+}
@@ -7,27 +7,81 @@ import (
 	"strings"
 	"time"
 
+	"microgrid-cloud/internal/api/apierror"
+	"microgrid-cloud/internal/api/pagination"
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/calendar"
+	"microgrid-cloud/internal/clock"
 	shadowapp "microgrid-cloud/internal/shadowrun/application"
 	shadowrepo "microgrid-cloud/internal/shadowrun/infrastructure/postgres"
 )
 
 const timeLayout = time.RFC3339
 
+// DefaultMaxReportRange caps the from/to span of report listings.
+const DefaultMaxReportRange = 366 * 24 * time.Hour
+
 // Handler provides shadowrun APIs.
 type Handler struct {
 	runner         *shadowapp.Runner
 	repo           *shadowrepo.Repository
+	readRepo       *shadowrepo.Repository
 	tenantID       string
 	stationChecker auth.StationTenantChecker
+	defaultRange   time.Duration
+	maxRange       time.Duration
+	clock          clock.Clock
+}
+
+// HandlerOption configures the shadowrun handler.
+type HandlerOption func(*Handler)
+
+// WithReadRepository serves report listings and lookups from repo, typically
+// backed by a read replica. Runs and replays keep using the primary.
+func WithReadRepository(repo *shadowrepo.Repository) HandlerOption {
+	return func(h *Handler) {
+		if repo != nil {
+			h.readRepo = repo
+		}
+	}
+}
+
+// WithReportRange lets report listings omit from/to, filling a missing to with
+// now and a missing from with defaultRange before to, and rejects spans longer
+// than maxRange with 400. Zero keeps from/to required or removes the cap.
+func WithReportRange(defaultRange, maxRange time.Duration) HandlerOption {
+	return func(h *Handler) {
+		if defaultRange >= 0 {
+			h.defaultRange = defaultRange
+		}
+		if maxRange >= 0 {
+			h.maxRange = maxRange
+		}
+	}
+}
+
+// WithHandlerClock overrides the clock that dates run and replay jobs and
+// fills a missing report listing to.
+func WithHandlerClock(c clock.Clock) HandlerOption {
+	return func(h *Handler) {
+		if c != nil {
+			h.clock = c
+		}
+	}
 }
 
 // NewHandler constructs a handler.
-func NewHandler(runner *shadowapp.Runner, repo *shadowrepo.Repository, tenantID string, stationChecker auth.StationTenantChecker) (*Handler, error) {
+func NewHandler(runner *shadowapp.Runner, repo *shadowrepo.Repository, tenantID string, stationChecker auth.StationTenantChecker, opts ...HandlerOption) (*Handler, error) {
 	if runner == nil || repo == nil {
 		return nil, errors.New("shadowrun handler: nil dependency")
 	}
-	return &Handler{runner: runner, repo: repo, tenantID: tenantID, stationChecker: stationChecker}, nil
+	h := &Handler{runner: runner, repo: repo, readRepo: repo, tenantID: tenantID, stationChecker: stationChecker, maxRange: DefaultMaxReportRange, clock: clock.System{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	return h, nil
 }
 
 // ServeHTTP routes shadowrun endpoints.
@@ -55,7 +109,7 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 		Thresholds *shadowapp.Thresholds `json:"thresholds"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
@@ -66,19 +120,19 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 		tenantID = h.tenantID
 	}
 	if tenantID == "" {
-		http.Error(w, "tenant_id required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "tenant_id required")
 		return
 	}
 	if len(req.StationIDs) == 0 {
-		http.Error(w, "station_ids required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_ids required")
 		return
 	}
-	month, err := parseMonth(req.Month)
+	month, err := calendar.ParseMonth(req.Month)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	jobDate := time.Now().UTC()
+	jobDate := h.clock.Now().UTC()
 	var results []map[string]any
 	for _, stationID := range req.StationIDs {
 		if tenantID != "" {
@@ -113,7 +167,7 @@ func (h *Handler) handleRun(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleReports(w http.ResponseWriter, r *http.Request) {
 	stationID := r.URL.Query().Get("station_id")
 	if stationID == "" {
-		http.Error(w, "station_id required", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "station_id required")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
@@ -123,25 +177,22 @@ func (h *Handler) handleReports(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	from, err := parseTimeQuery(r, "from")
+	from, to, err := h.reportRange(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	to, err := parseTimeQuery(r, "to")
+	page, err := pagination.Parse(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
-	reports, err := h.repo.ListReports(r.Context(), stationID, from, to)
+	reports, total, err := h.readRepo.ListReportsPage(r.Context(), stationID, from, to, page.Limit, page.Offset)
 	if err != nil {
-		http.Error(w, "query reports error", http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "query reports error")
 		return
 	}
+	pagination.SetHeaders(w, page, total)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(reports)
 }
@@ -176,18 +227,18 @@ func (h *Handler) handleReportByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) handleReportGet(w http.ResponseWriter, r *http.Request, reportID string) {
-	report, err := h.repo.GetReport(r.Context(), reportID)
+	report, err := h.readRepo.GetReport(r.Context(), reportID)
 	if err != nil {
-		http.Error(w, "report not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "report not found")
 		return
 	}
 	if report == nil {
-		http.Error(w, "report not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "report not found")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && report.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	resp := map[string]any{
@@ -206,14 +257,14 @@ func (h *Handler) handleReportGet(w http.ResponseWriter, r *http.Request, report
 }
 
 func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request, reportID string) {
-	report, err := h.repo.GetReport(r.Context(), reportID)
+	report, err := h.readRepo.GetReport(r.Context(), reportID)
 	if err != nil || report == nil {
-		http.Error(w, "report not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "report not found")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && report.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	http.ServeFile(w, r, report.Location)
@@ -222,15 +273,15 @@ func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request, reportI
 func (h *Handler) handleReplay(w http.ResponseWriter, r *http.Request, reportID string) {
 	report, err := h.repo.GetReport(r.Context(), reportID)
 	if err != nil || report == nil {
-		http.Error(w, "report not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "report not found")
 		return
 	}
 	tenantID := auth.TenantIDFromContext(r.Context())
 	if tenantID != "" && report.TenantID != tenantID {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
-	jobDate := time.Now().UTC()
+	jobDate := h.clock.Now().UTC()
 	job, err := h.repo.CreateJob(r.Context(), &shadowrepo.Job{
 		ID:        "replay-" + report.ID,
 		TenantID:  report.TenantID,
@@ -254,6 +305,41 @@ func (h *Handler) handleReplay(w http.ResponseWriter, r *http.Request, reportID
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// reportRange resolves the from/to window of a report listing.
+func (h *Handler) reportRange(r *http.Request) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	var from, to time.Time
+	var err error
+	if h.defaultRange <= 0 || (query.Get("from") != "" && query.Get("to") != "") {
+		if from, err = parseTimeQuery(r, "from"); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if to, err = parseTimeQuery(r, "to"); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	} else {
+		to = h.clock.Now().UTC()
+		if query.Get("to") != "" {
+			if to, err = parseTimeQuery(r, "to"); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+		from = to.Add(-h.defaultRange)
+		if query.Get("from") != "" {
+			if from, err = parseTimeQuery(r, "from"); err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+		}
+	}
+	if !to.After(from) {
+		return time.Time{}, time.Time{}, errors.New("to must be after from")
+	}
+	if h.maxRange > 0 && to.Sub(from) > h.maxRange {
+		return time.Time{}, time.Time{}, errors.New("range exceeds the maximum of " + h.maxRange.String())
+	}
+	return from, to, nil
+}
+
 func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
 	value := r.URL.Query().Get(key)
 	if value == "" {
@@ -266,17 +352,6 @@ func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
 	return parsed.UTC(), nil
 }
 
-func parseMonth(value string) (time.Time, error) {
-	if value == "" {
-		return time.Time{}, errors.New("month required")
-	}
-	t, err := time.Parse("2006-01", value)
-	if err != nil {
-		return time.Time{}, errors.New("month must be YYYY-MM")
-	}
-	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
-}
-
 func ensureStationTenant(r *http.Request, checker auth.StationTenantChecker, tenantID, stationID string) error {
 	if checker == nil || tenantID == "" || stationID == "" {
 		return nil
@@ -289,14 +364,14 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
 
 func tenantErrorMessage(err error) string {
@@ -4,13 +4,15 @@ import "github.com/prometheus/client_golang/prometheus"
 
 // Metrics bundles shadowrun metrics.
 type Metrics struct {
-	JobsTotal     *prometheus.CounterVec
-	JobDuration   prometheus.Histogram
-	DiffEnergyMax prometheus.Gauge
-	DiffAmountMax prometheus.Gauge
-	DiffMax       prometheus.Gauge
-	ReportsTotal  prometheus.Counter
-	AlertsTotal   prometheus.Counter
+	JobsTotal       *prometheus.CounterVec
+	JobDuration     prometheus.Histogram
+	DiffEnergyMax   prometheus.Gauge
+	DiffAmountMax   prometheus.Gauge
+	DiffMax         prometheus.Gauge
+	ReportsTotal    prometheus.Counter
+	AlertsTotal     prometheus.Counter
+	RecoveriesTotal prometheus.Counter
+	StationDuration *prometheus.HistogramVec
 }
 
 // New constructs and registers metrics.
@@ -28,6 +30,11 @@ func New() *Metrics {
 			Help:    "Shadowrun job duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		}),
+		StationDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "platform_shadowrun_station_duration_seconds",
+			Help:    "Scheduled shadowrun station run duration in seconds by station and result",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+		}, []string{"station", "result"}),
 		DiffEnergyMax: prometheus.NewGauge(prometheus.GaugeOpts{
 			Name: "platform_shadowrun_diff_energy_kwh_max",
 			Help: "Max energy diff in kWh",
@@ -48,15 +55,21 @@ func New() *Metrics {
 			Name: "platform_shadowrun_alerts_total",
 			Help: "Total shadowrun alerts",
 		}),
+		RecoveriesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "platform_shadowrun_recoveries_total",
+			Help: "Total shadowrun recovered notifications",
+		}),
 	}
 	prometheus.MustRegister(
 		m.JobsTotal,
 		m.JobDuration,
+		m.StationDuration,
 		m.DiffEnergyMax,
 		m.DiffAmountMax,
 		m.DiffMax,
 		m.ReportsTotal,
 		m.AlertsTotal,
+		m.RecoveriesTotal,
 	)
 	return m
 }
@@ -2,8 +2,13 @@ package notify
 
 import "context"
 
+// StatusRecovered marks a notification that closes a station's open alerts.
+const StatusRecovered = "recovered"
+
 // AlertMessage represents a notification payload.
 type AlertMessage struct {
+	// Status is empty for a diff alert and StatusRecovered for a recovery.
+	Status            string            `json:"status,omitempty"`
 	TenantID          string            `json:"tenant_id"`
 	StationID         string            `json:"station_id"`
 	Month             string            `json:"month"`
@@ -66,7 +66,11 @@ func (n *WebhookNotifier) Notify(ctx context.Context, msg AlertMessage) error {
 
 func formatAlertMessage(msg AlertMessage) string {
 	var b strings.Builder
-	b.WriteString("[Shadowrun Alert]\n")
+	if msg.Status == StatusRecovered {
+		b.WriteString("[Shadowrun Recovered]\n")
+	} else {
+		b.WriteString("[Shadowrun Alert]\n")
+	}
 	if msg.TenantID != "" {
 		fmt.Fprintf(&b, "Tenant: %s\n", msg.TenantID)
 	}
@@ -82,6 +86,9 @@ func formatAlertMessage(msg AlertMessage) string {
 	if msg.ReportURL != "" {
 		fmt.Fprintf(&b, "Report URL: %s\n", msg.ReportURL)
 	}
+	if alerts := msg.Meta["recovered_alerts"]; alerts != "" {
+		fmt.Fprintf(&b, "Closed Alerts: %s\n", alerts)
+	}
 	if msg.RecommendedAction != "" {
 		fmt.Fprintf(&b, "Suggested: %s\n", msg.RecommendedAction)
 	}
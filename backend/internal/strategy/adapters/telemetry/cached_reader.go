@@ -0,0 +1,211 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"microgrid-cloud/internal/clock"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+)
+
+// Cache defaults.
+const (
+	DefaultCacheTTL        = 2 * time.Minute
+	DefaultMappingCacheTTL = 5 * time.Minute
+)
+
+// CachedReader serves latest semantic values from TelemetryReceived events
+// and reads a point from the database when it has no fresh sample of it.
+// Events only reach the instance that dispatched them, so the TTL bounds how
+// long telemetry ingested elsewhere can go unnoticed.
+type CachedReader struct {
+	fallback   *LatestReader
+	ttl        time.Duration
+	mappingTTL time.Duration
+	clock      clock.Clock
+
+	mu       sync.RWMutex
+	samples  map[pointRef]cachedSample
+	mappings map[string]cachedMappings
+}
+
+type pointRef struct {
+	tenantID  string
+	stationID string
+	deviceID  string
+	pointKey  string
+}
+
+type cachedSample struct {
+	value    float64
+	ts       time.Time
+	cachedAt time.Time
+}
+
+type cachedMappings struct {
+	list     []mapping
+	loadedAt time.Time
+}
+
+// CachedReaderOption configures a CachedReader.
+type CachedReaderOption func(*CachedReader)
+
+// WithCacheTTL sets how long a cached sample is served before the point is
+// read from the database again.
+func WithCacheTTL(ttl time.Duration) CachedReaderOption {
+	return func(c *CachedReader) {
+		if ttl > 0 {
+			c.ttl = ttl
+		}
+	}
+}
+
+// WithMappingCacheTTL sets how long point mappings are cached; mapping
+// changes take up to this long to reach the strategy engine.
+func WithMappingCacheTTL(ttl time.Duration) CachedReaderOption {
+	return func(c *CachedReader) {
+		if ttl > 0 {
+			c.mappingTTL = ttl
+		}
+	}
+}
+
+// WithCacheClock overrides the clock that ages cached samples and mappings.
+func WithCacheClock(cl clock.Clock) CachedReaderOption {
+	return func(c *CachedReader) {
+		if cl != nil {
+			c.clock = cl
+		}
+	}
+}
+
+// NewCachedReader constructs a CachedReader backed by fallback.
+func NewCachedReader(fallback *LatestReader, opts ...CachedReaderOption) (*CachedReader, error) {
+	if fallback == nil || fallback.db == nil {
+		return nil, errors.New("strategy telemetry cache: nil fallback reader")
+	}
+	c := &CachedReader{
+		fallback:   fallback,
+		ttl:        DefaultCacheTTL,
+		mappingTTL: DefaultMappingCacheTTL,
+		clock:      clock.System{},
+		samples:    make(map[pointRef]cachedSample),
+		mappings:   make(map[string]cachedMappings),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	return c, nil
+}
+
+// HandleTelemetryReceived caches the points of a telemetry event. Older
+// samples never replace newer ones, so redelivered events are harmless.
+func (c *CachedReader) HandleTelemetryReceived(_ context.Context, event telemetryevents.TelemetryReceived) error {
+	now := c.clock.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, point := range event.Points {
+		ts := point.TS
+		if ts.IsZero() {
+			ts = event.OccurredAt
+		}
+		sample := cachedSample{value: point.Value, ts: ts.UTC(), cachedAt: now}
+		ref := pointRef{tenantID: event.TenantID, stationID: event.StationID, deviceID: event.DeviceID, pointKey: point.PointKey}
+		c.storeLocked(ref, sample)
+		// Mappings without a device read the latest sample of any device.
+		ref.deviceID = ""
+		c.storeLocked(ref, sample)
+	}
+	return nil
+}
+
+// LatestSemantic returns the latest semantic value for a station, like
+// LatestReader.LatestSemantic.
+func (c *CachedReader) LatestSemantic(ctx context.Context, tenantID, stationID string, semantic masterdata.Semantic) (LatestValue, error) {
+	if tenantID == "" || stationID == "" || semantic == "" {
+		return LatestValue{}, errors.New("strategy telemetry latest: invalid arguments")
+	}
+	mappings, err := c.mappingsFor(ctx, stationID, string(semantic))
+	if err != nil {
+		return LatestValue{}, err
+	}
+	if len(mappings) == 0 {
+		return LatestValue{}, errors.New("strategy telemetry latest: no mappings")
+	}
+
+	var (
+		total float64
+		ts    time.Time
+		keys  []string
+	)
+	for _, mapping := range mappings {
+		ref := pointRef{tenantID: tenantID, stationID: stationID, deviceID: mapping.DeviceID, pointKey: mapping.PointKey}
+		sample, ok := c.fresh(ref)
+		if !ok {
+			value, pointTS, found, err := c.fallback.loadLatestPoint(ctx, tenantID, stationID, mapping.PointKey, mapping.DeviceID)
+			if err != nil {
+				return LatestValue{}, err
+			}
+			if !found {
+				continue
+			}
+			sample = cachedSample{value: value, ts: pointTS, cachedAt: c.clock.Now()}
+			c.mu.Lock()
+			c.storeLocked(ref, sample)
+			c.mu.Unlock()
+		}
+		total += sample.value * mapping.Factor
+		if sample.ts.After(ts) {
+			ts = sample.ts
+		}
+		keys = append(keys, buildPointLabel(mapping.PointKey, mapping.DeviceID))
+	}
+
+	if ts.IsZero() {
+		return LatestValue{}, errors.New("strategy telemetry latest: no telemetry points")
+	}
+	return LatestValue{Value: total, Timestamp: ts.UTC(), Points: keys}, nil
+}
+
+func (c *CachedReader) fresh(ref pointRef) (cachedSample, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	sample, ok := c.samples[ref]
+	if !ok || c.clock.Now().Sub(sample.cachedAt) > c.ttl {
+		return cachedSample{}, false
+	}
+	return sample, true
+}
+
+// storeLocked keeps the newer of the cached and the given sample; a sample
+// with the same timestamp refreshes the cache time.
+func (c *CachedReader) storeLocked(ref pointRef, sample cachedSample) {
+	if existing, ok := c.samples[ref]; ok && existing.ts.After(sample.ts) {
+		return
+	}
+	c.samples[ref] = sample
+}
+
+func (c *CachedReader) mappingsFor(ctx context.Context, stationID, semantic string) ([]mapping, error) {
+	key := stationID + "|" + semantic
+	c.mu.RLock()
+	cached, ok := c.mappings[key]
+	c.mu.RUnlock()
+	if ok && c.clock.Now().Sub(cached.loadedAt) <= c.mappingTTL {
+		return cached.list, nil
+	}
+
+	list, err := loadMappings(ctx, c.fallback.db, stationID, semantic)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.mappings[key] = cachedMappings{list: list, loadedAt: c.clock.Now()}
+	c.mu.Unlock()
+	return list, nil
+}
@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// HistoryReader reads stored telemetry of a semantic over a time range.
+type HistoryReader struct {
+	db *sql.DB
+}
+
+// NewHistoryReader constructs a HistoryReader.
+func NewHistoryReader(db *sql.DB) *HistoryReader {
+	return &HistoryReader{db: db}
+}
+
+// SemanticSeries holds the samples of every point mapped to a semantic.
+type SemanticSeries struct {
+	points []pointSeries
+}
+
+type pointSeries struct {
+	label   string
+	factor  float64
+	samples []sample
+}
+
+type sample struct {
+	ts    time.Time
+	value float64
+}
+
+// LoadSemantic loads the samples of a semantic in [from, to), plus the last
+// sample before from of each point so the series is defined from the start.
+func (r *HistoryReader) LoadSemantic(ctx context.Context, tenantID, stationID string, semantic masterdata.Semantic, from, to time.Time) (*SemanticSeries, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("strategy telemetry history: nil db")
+	}
+	if tenantID == "" || stationID == "" || semantic == "" || !to.After(from) {
+		return nil, errors.New("strategy telemetry history: invalid arguments")
+	}
+
+	mappings, err := loadMappings(ctx, r.db, stationID, string(semantic))
+	if err != nil {
+		return nil, err
+	}
+	if len(mappings) == 0 {
+		return nil, errors.New("strategy telemetry history: no mappings")
+	}
+
+	series := &SemanticSeries{points: make([]pointSeries, 0, len(mappings))}
+	for _, mapping := range mappings {
+		samples, err := r.loadSamples(ctx, tenantID, stationID, mapping.PointKey, mapping.DeviceID, from, to)
+		if err != nil {
+			return nil, err
+		}
+		series.points = append(series.points, pointSeries{
+			label:   buildPointLabel(mapping.PointKey, mapping.DeviceID),
+			factor:  mapping.Factor,
+			samples: samples,
+		})
+	}
+	return series, nil
+}
+
+// At returns the value LatestReader would have returned at t, or false when
+// no point had a sample yet.
+func (s *SemanticSeries) At(t time.Time) (LatestValue, bool) {
+	var (
+		total float64
+		ts    time.Time
+		keys  []string
+	)
+	for _, point := range s.points {
+		i := sort.Search(len(point.samples), func(i int) bool { return point.samples[i].ts.After(t) })
+		if i == 0 {
+			continue
+		}
+		latest := point.samples[i-1]
+		total += latest.value * point.factor
+		if latest.ts.After(ts) {
+			ts = latest.ts
+		}
+		keys = append(keys, point.label)
+	}
+	if ts.IsZero() {
+		return LatestValue{}, false
+	}
+	return LatestValue{Value: total, Timestamp: ts, Points: keys}, true
+}
+
+func (r *HistoryReader) loadSamples(ctx context.Context, tenantID, stationID, pointKey, deviceID string, from, to time.Time) ([]sample, error) {
+	var samples []sample
+	row := r.db.QueryRowContext(ctx, `
+SELECT ts, value_numeric
+FROM telemetry_points
+WHERE tenant_id = $1 AND station_id = $2 AND point_key = $3 AND ($4 = '' OR device_id = $4)
+	AND ts < $5 AND value_numeric IS NOT NULL
+ORDER BY ts DESC
+LIMIT 1`, tenantID, stationID, pointKey, deviceID, from)
+	var carried sample
+	if err := row.Scan(&carried.ts, &carried.value); err == nil {
+		carried.ts = carried.ts.UTC()
+		samples = append(samples, carried)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+SELECT ts, value_numeric
+FROM telemetry_points
+WHERE tenant_id = $1 AND station_id = $2 AND point_key = $3 AND ($4 = '' OR device_id = $4)
+	AND ts >= $5 AND ts < $6 AND value_numeric IS NOT NULL
+ORDER BY ts ASC`, tenantID, stationID, pointKey, deviceID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var next sample
+		if err := rows.Scan(&next.ts, &next.value); err != nil {
+			return nil, err
+		}
+		next.ts = next.ts.UTC()
+		samples = append(samples, next)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
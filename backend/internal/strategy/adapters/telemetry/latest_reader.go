@@ -36,7 +36,7 @@ func (r *LatestReader) LatestSemantic(ctx context.Context, tenantID, stationID s
 		return LatestValue{}, errors.New("strategy telemetry latest: invalid arguments")
 	}
 
-	mappings, err := r.loadMappings(ctx, stationID, string(semantic))
+	mappings, err := loadMappings(ctx, r.db, stationID, string(semantic))
 	if err != nil {
 		return LatestValue{}, err
 	}
@@ -76,8 +76,8 @@ type mapping struct {
 	Factor   float64
 }
 
-func (r *LatestReader) loadMappings(ctx context.Context, stationID, semantic string) ([]mapping, error) {
-	rows, err := r.db.QueryContext(ctx, `
+func loadMappings(ctx context.Context, db *sql.DB, stationID, semantic string) ([]mapping, error) {
+	rows, err := db.QueryContext(ctx, `
 SELECT point_key, device_id, factor
 FROM point_mappings
 WHERE station_id = $1 AND semantic = $2
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	commandsapp "microgrid-cloud/internal/commands/application"
+	commands "microgrid-cloud/internal/commands/domain"
 	masterdata "microgrid-cloud/internal/masterdata/domain"
 	strategy "microgrid-cloud/internal/strategy/domain"
 	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
@@ -22,16 +23,56 @@ const (
 	StatusError    = "error"
 )
 
+// defaultAntiBackflowMaxKW caps the anti-backflow target when max_kw is unset.
+const defaultAntiBackflowMaxKW = 10000
+
+// Command guardrail defaults.
+const (
+	DefaultMinCommandInterval = 5 * time.Minute
+	DefaultCommandAckWait     = 5 * time.Minute
+)
+
+// LatestTelemetryReader reads the latest value of a semantic; the latest
+// and cached telemetry readers implement it.
+type LatestTelemetryReader interface {
+	LatestSemantic(ctx context.Context, tenantID, stationID string, semantic masterdata.Semantic) (telemetryadapter.LatestValue, error)
+}
+
 // Engine evaluates strategies and issues commands.
 type Engine struct {
-	repo      *strategyrepo.Repository
-	telemetry *telemetryadapter.LatestReader
-	commands  *commandsapp.Service
-	tenantID  string
+	repo               *strategyrepo.Repository
+	telemetry          LatestTelemetryReader
+	commands           *commandsapp.Service
+	tenantID           string
+	minCommandInterval time.Duration
+	commandAckWait     time.Duration
+}
+
+// EngineOption configures an Engine.
+type EngineOption func(*Engine)
+
+// WithMinCommandInterval sets the minimum time between two commands to the
+// same device. Zero disables the cooldown.
+func WithMinCommandInterval(interval time.Duration) EngineOption {
+	return func(e *Engine) {
+		if interval >= 0 {
+			e.minCommandInterval = interval
+		}
+	}
+}
+
+// WithCommandAckWait sets how long an unacked command to a device blocks
+// new ones. Zero disables the check.
+func WithCommandAckWait(wait time.Duration) EngineOption {
+	return func(e *Engine) {
+		if wait >= 0 {
+			e.commandAckWait = wait
+		}
+	}
 }
 
 // NewEngine constructs an Engine.
-func NewEngine(repo *strategyrepo.Repository, telemetry *telemetryadapter.LatestReader, commands *commandsapp.Service, tenantID string) (*Engine, error) {
+func NewEngine(repo *strategyrepo.Repository, telemetry LatestTelemetryReader, commands *commandsapp.Service, tenantID string, opts ...EngineOption) (*Engine, error) {
 	if repo == nil {
 		return nil, errors.New("strategy engine: nil repo")
 	}
@@ -44,12 +85,20 @@ func NewEngine(repo *strategyrepo.Repository, telemetry *telemetryadapter.Latest
 	if tenantID == "" {
 		return nil, errors.New("strategy engine: empty tenant id")
 	}
-	return &Engine{
-		repo:      repo,
-		telemetry: telemetry,
-		commands:  commands,
-		tenantID:  tenantID,
-	}, nil
+	engine := &Engine{
+		repo:               repo,
+		telemetry:          telemetry,
+		commands:           commands,
+		tenantID:           tenantID,
+		minCommandInterval: DefaultMinCommandInterval,
+		commandAckWait:     DefaultCommandAckWait,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(engine)
+		}
+	}
+	return engine, nil
 }
 
 // Tick evaluates all enabled auto strategies at the given time.
@@ -65,24 +114,38 @@ func (e *Engine) Tick(ctx context.Context, now time.Time) error {
 		return err
 	}
 	for _, item := range strategies {
-		if err := e.evaluate(ctx, item, now.UTC()); err != nil {
+		exec := &strategy.Execution{
+			StrategyID: item.StationID,
+			TS:         now.UTC(),
+			TemplateID: item.TemplateID,
+		}
+		if err := e.evaluate(ctx, item, now.UTC(), exec); err != nil {
 			_ = e.repo.InsertRun(ctx, &strategy.Run{
 				StrategyID: item.StationID,
 				TS:         now.UTC(),
 				Status:     StatusError,
 				Decision:   []byte(fmt.Sprintf(`{"error":%q}`, err.Error())),
 			})
+			exec.Outcome = StatusError
+			exec.Reason = err.Error()
 		}
+		// The execution log is diagnostic; failing to write it must not stop the tick.
+		_ = e.repo.InsertExecution(ctx, exec)
 	}
 	return nil
 }
 
-func (e *Engine) evaluate(ctx context.Context, item strategy.Strategy, now time.Time) error {
+// evaluate runs one strategy and describes the outcome in exec.
+func (e *Engine) evaluate(ctx context.Context, item strategy.Strategy, now time.Time, exec *strategy.Execution) error {
 	if item.Mode != strategy.ModeAuto || !item.Enabled {
+		exec.Outcome = StatusSkipped
+		exec.Reason = "strategy is not enabled in auto mode"
 		return nil
 	}
 
 	if !e.inCalendarWindow(ctx, item.StationID, now) {
+		exec.Outcome = StatusSkipped
+		exec.Reason = "outside calendar window"
 		return nil
 	}
 
@@ -96,8 +159,10 @@ func (e *Engine) evaluate(ctx context.Context, item strategy.Strategy, now time.
 
 	switch template.Type {
 	case "anti_backflow":
-		return e.runAntiBackflow(ctx, item, template, now)
+		return e.runAntiBackflow(ctx, item, template, now, exec)
 	default:
+		exec.Outcome = StatusSkipped
+		exec.Reason = fmt.Sprintf("unsupported template type %q", template.Type)
 		return nil
 	}
 }
@@ -105,10 +170,16 @@ func (e *Engine) evaluate(ctx context.Context, item strategy.Strategy, now time.
 func (e *Engine) inCalendarWindow(ctx context.Context, strategyID string, now time.Time) bool {
 	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
 	cal, err := e.repo.GetCalendar(ctx, strategyID, date)
-	if err != nil || cal == nil {
+	if err != nil {
 		return false
 	}
-	if !cal.Enabled {
+	return calendarAllows(cal, now)
+}
+
+// calendarAllows reports whether now falls inside the window of cal, the
+// calendar entry of now's date.
+func calendarAllows(cal *strategy.Calendar, now time.Time) bool {
+	if cal == nil || !cal.Enabled {
 		return false
 	}
 	start := time.Date(now.Year(), now.Month(), now.Day(), cal.StartTime.Hour(), cal.StartTime.Minute(), 0, 0, time.UTC)
@@ -127,22 +198,47 @@ type antiBackflowParams struct {
 	CommandType string  `json:"command_type"`
 }
 
-func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, template *strategy.Template, now time.Time) error {
+// parseAntiBackflowParams decodes template parameters and applies defaults.
+func parseAntiBackflowParams(raw []byte) antiBackflowParams {
 	var params antiBackflowParams
-	if len(template.Params) > 0 {
-		_ = json.Unmarshal(template.Params, &params)
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &params)
 	}
 	if params.CommandType == "" {
 		params.CommandType = "setPower"
 	}
 	if params.MaxKW == 0 {
-		params.MaxKW = 10000
+		params.MaxKW = defaultAntiBackflowMaxKW
 	}
+	return params
+}
+
+// decideAntiBackflow is the anti-backflow decision for one grid export
+// reading: StatusIssued with the target power when a command is due,
+// otherwise StatusNoAction or StatusError with the reason.
+func decideAntiBackflow(params antiBackflowParams, gridExportKW float64) (string, float64, string) {
+	if gridExportKW <= params.ThresholdKW {
+		return StatusNoAction, 0, fmt.Sprintf("grid export %g kW is within threshold %g kW", gridExportKW, params.ThresholdKW)
+	}
+	if params.DeviceID == "" {
+		return StatusError, 0, "missing device_id"
+	}
+	return StatusIssued, clamp(gridExportKW, params.MinKW, params.MaxKW), ""
+}
+
+func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, template *strategy.Template, now time.Time, exec *strategy.Execution) error {
+	params := parseAntiBackflowParams(template.Params)
 
 	latest, err := e.telemetry.LatestSemantic(ctx, e.tenantID, item.StationID, masterdata.SemanticGridExportKW)
 	if err != nil {
 		return err
 	}
+	exec.Inputs, _ = json.Marshal(map[string]any{
+		"grid_export_kw":  latest.Value,
+		"telemetry_ts":    latest.Timestamp.Format(time.RFC3339),
+		"semantic_points": latest.Points,
+		"params":          params,
+	})
 	decision := map[string]any{
 		"station_id":       item.StationID,
 		"template_id":      template.ID,
@@ -155,8 +251,10 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 		"mode":             item.Mode,
 	}
 
-	if latest.Value <= params.ThresholdKW {
+	status, target, reason := decideAntiBackflow(params, latest.Value)
+	if status == StatusNoAction {
 		payload, _ := json.Marshal(decision)
+		exec.Decision, exec.Outcome, exec.Reason = payload, StatusNoAction, reason
 		return e.repo.InsertRun(ctx, &strategy.Run{
 			StrategyID: item.StationID,
 			TS:         now,
@@ -165,9 +263,10 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 		})
 	}
 
-	if params.DeviceID == "" {
-		decision["error"] = "missing device_id"
+	if status == StatusError {
+		decision["error"] = reason
 		payload, _ := json.Marshal(decision)
+		exec.Decision, exec.Outcome, exec.Reason = payload, StatusError, reason
 		return e.repo.InsertRun(ctx, &strategy.Run{
 			StrategyID: item.StationID,
 			TS:         now,
@@ -176,9 +275,24 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 		})
 	}
 
-	target := clamp(latest.Value, params.MinKW, params.MaxKW)
 	decision["target_kw"] = target
 
+	blocked, err := e.commandGuard(ctx, params.DeviceID, now)
+	if err != nil {
+		return err
+	}
+	if blocked != "" {
+		decision["guard"] = blocked
+		payload, _ := json.Marshal(decision)
+		exec.Decision, exec.Outcome, exec.Reason = payload, StatusSkipped, blocked
+		return e.repo.InsertRun(ctx, &strategy.Run{
+			StrategyID: item.StationID,
+			TS:         now,
+			Decision:   payload,
+			Status:     StatusSkipped,
+		})
+	}
+
 	cmdPayload := map[string]any{"pcs_target_power_kw": target}
 	payloadBytes, _ := json.Marshal(cmdPayload)
 	idemKey := buildIdemKey(item.StationID, now, target)
@@ -194,6 +308,7 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 	if err != nil {
 		decision["error"] = err.Error()
 		payload, _ := json.Marshal(decision)
+		exec.Decision, exec.Outcome, exec.Reason = payload, StatusError, "issue command: "+err.Error()
 		return e.repo.InsertRun(ctx, &strategy.Run{
 			StrategyID: item.StationID,
 			TS:         now,
@@ -204,6 +319,7 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 
 	decision["command_id"] = resp.CommandID
 	payload, _ := json.Marshal(decision)
+	exec.Decision, exec.Outcome, exec.CommandID = payload, StatusIssued, resp.CommandID
 	return e.repo.InsertRun(ctx, &strategy.Run{
 		StrategyID: item.StationID,
 		TS:         now,
@@ -213,6 +329,29 @@ func (e *Engine) runAntiBackflow(ctx context.Context, item strategy.Strategy, te
 	})
 }
 
+// commandGuard explains why no command may be sent to the device now, or
+// returns "" when one may. A command still waiting for its ack conflicts with
+// a new one; an acked or failed one starts the cooldown.
+func (e *Engine) commandGuard(ctx context.Context, deviceID string, now time.Time) (string, error) {
+	if e.minCommandInterval == 0 && e.commandAckWait == 0 {
+		return "", nil
+	}
+	last, err := e.commands.LatestForDevice(ctx, e.tenantID, deviceID)
+	if err != nil || last == nil {
+		return "", err
+	}
+	age := now.Sub(last.CreatedAt)
+	pending := last.Status == commands.StatusCreated || last.Status == commands.StatusSent
+	if pending && age < e.commandAckWait {
+		return fmt.Sprintf("command %s to device %s is still %s", last.CommandID, deviceID, last.Status), nil
+	}
+	if age < e.minCommandInterval {
+		return fmt.Sprintf("command %s was sent to device %s %s ago, within the %s minimum interval",
+			last.CommandID, deviceID, age.Round(time.Second), e.minCommandInterval), nil
+	}
+	return "", nil
+}
+
 func clamp(value, min, max float64) float64 {
 	if value < min {
 		return min
@@ -106,6 +106,18 @@ func (s *Service) ListRuns(ctx context.Context, stationID string, from, to time.
 	return s.repo.ListRuns(ctx, stationID, from, to)
 }
 
+// ListExecutions returns up to limit engine executions of a station
+// strategy, newest first, older than beforeID when it is positive.
+func (s *Service) ListExecutions(ctx context.Context, stationID string, beforeID int64, limit int) ([]strategy.Execution, error) {
+	if stationID == "" {
+		return nil, errors.New("strategy service: station_id required")
+	}
+	if limit <= 0 {
+		return nil, errors.New("strategy service: limit must be positive")
+	}
+	return s.repo.ListExecutions(ctx, stationID, beforeID, limit)
+}
+
 func (s *Service) ensureTemplate(ctx context.Context, templateID, templateType string, params map[string]any) error {
 	payload := []byte("{}")
 	if params != nil {
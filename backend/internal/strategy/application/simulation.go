@@ -0,0 +1,235 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"microgrid-cloud/internal/auth"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	telemetryadapter "microgrid-cloud/internal/strategy/adapters/telemetry"
+	strategy "microgrid-cloud/internal/strategy/domain"
+	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
+)
+
+// Simulation bounds.
+const (
+	DefaultSimulationStep = time.Minute
+	MaxSimulationSteps    = 20160
+)
+
+// ErrInvalidSimulation marks simulation requests that cannot be replayed.
+var ErrInvalidSimulation = errors.New("strategy simulation: invalid request")
+
+// SimulationRequest selects the strategy and range to replay. Without
+// template type and params the station's saved template is used.
+type SimulationRequest struct {
+	StationID      string
+	From           time.Time
+	To             time.Time
+	Step           time.Duration
+	TemplateType   string
+	TemplateParams map[string]any
+	IgnoreCalendar bool
+}
+
+// SimulatedDecision is what the engine would have decided at one tick.
+type SimulatedDecision struct {
+	TS           time.Time `json:"ts"`
+	GridExportKW *float64  `json:"grid_export_kw,omitempty"`
+	TelemetryTS  string    `json:"telemetry_ts,omitempty"`
+	Outcome      string    `json:"outcome"`
+	TargetKW     *float64  `json:"target_kw,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// SimulationResult is the decision sequence of a replay.
+type SimulationResult struct {
+	StationID    string              `json:"station_id"`
+	From         time.Time           `json:"from"`
+	To           time.Time           `json:"to"`
+	Step         string              `json:"step"`
+	TemplateType string              `json:"template_type"`
+	Counts       map[string]int      `json:"counts"`
+	Decisions    []SimulatedDecision `json:"decisions"`
+}
+
+// Simulator replays stored telemetry through the engine's decision logic
+// without issuing commands.
+type Simulator struct {
+	repo               *strategyrepo.Repository
+	history            *telemetryadapter.HistoryReader
+	tenantID           string
+	minCommandInterval time.Duration
+}
+
+// SimulatorOption configures a Simulator.
+type SimulatorOption func(*Simulator)
+
+// WithSimulatedCommandInterval applies the engine's minimum command
+// interval to simulated commands. Zero disables it.
+func WithSimulatedCommandInterval(interval time.Duration) SimulatorOption {
+	return func(s *Simulator) {
+		if interval >= 0 {
+			s.minCommandInterval = interval
+		}
+	}
+}
+
+// NewSimulator constructs a Simulator.
+func NewSimulator(repo *strategyrepo.Repository, history *telemetryadapter.HistoryReader, tenantID string, opts ...SimulatorOption) (*Simulator, error) {
+	if repo == nil {
+		return nil, errors.New("strategy simulator: nil repo")
+	}
+	if history == nil {
+		return nil, errors.New("strategy simulator: nil history reader")
+	}
+	if tenantID == "" {
+		return nil, errors.New("strategy simulator: empty tenant id")
+	}
+	simulator := &Simulator{
+		repo:               repo,
+		history:            history,
+		tenantID:           tenantID,
+		minCommandInterval: DefaultMinCommandInterval,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(simulator)
+		}
+	}
+	return simulator, nil
+}
+
+// Simulate replays [From, To) one tick per Step. Errors wrapping
+// ErrInvalidSimulation describe a request that cannot be replayed.
+func (s *Simulator) Simulate(ctx context.Context, req SimulationRequest) (*SimulationResult, error) {
+	if req.StationID == "" {
+		return nil, fmt.Errorf("%w: station_id required", ErrInvalidSimulation)
+	}
+	if req.Step == 0 {
+		req.Step = DefaultSimulationStep
+	}
+	if req.Step < 0 {
+		return nil, fmt.Errorf("%w: step must be positive", ErrInvalidSimulation)
+	}
+	from, to := req.From.UTC(), req.To.UTC()
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", ErrInvalidSimulation)
+	}
+	if steps := to.Sub(from) / req.Step; steps > MaxSimulationSteps {
+		return nil, fmt.Errorf("%w: %d ticks exceed the limit of %d; use a larger step", ErrInvalidSimulation, steps, MaxSimulationSteps)
+	}
+
+	templateType, params, err := s.resolveTemplate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if templateType != "anti_backflow" {
+		return nil, fmt.Errorf("%w: unsupported template type %q", ErrInvalidSimulation, templateType)
+	}
+	tenantID := auth.TenantIDFromContext(ctx)
+	if tenantID == "" {
+		tenantID = s.tenantID
+	}
+	series, err := s.history.LoadSemantic(ctx, tenantID, req.StationID, masterdata.SemanticGridExportKW, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SimulationResult{
+		StationID:    req.StationID,
+		From:         from,
+		To:           to,
+		Step:         req.Step.String(),
+		TemplateType: templateType,
+		Counts:       make(map[string]int),
+		Decisions:    make([]SimulatedDecision, 0, int(to.Sub(from)/req.Step)+1),
+	}
+	calendars := make(map[time.Time]*strategy.Calendar)
+	var lastCommand time.Time
+	for tick := from; tick.Before(to); tick = tick.Add(req.Step) {
+		decision := SimulatedDecision{TS: tick}
+		allowed, err := s.calendarAllows(ctx, calendars, req, tick)
+		if err != nil {
+			return nil, err
+		}
+		latest, ok := series.At(tick)
+		switch {
+		case !allowed:
+			decision.Outcome, decision.Reason = StatusSkipped, "outside calendar window"
+		case !ok:
+			decision.Outcome, decision.Reason = StatusError, "no telemetry points"
+		default:
+			value := latest.Value
+			decision.GridExportKW = &value
+			decision.TelemetryTS = latest.Timestamp.Format(time.RFC3339)
+			status, target, reason := decideAntiBackflow(params, value)
+			decision.Outcome, decision.Reason = status, reason
+			if status != StatusIssued {
+				break
+			}
+			decision.TargetKW = &target
+			if !lastCommand.IsZero() && tick.Sub(lastCommand) < s.minCommandInterval {
+				decision.Outcome = StatusSkipped
+				decision.Reason = fmt.Sprintf("within the %s minimum command interval", s.minCommandInterval)
+				break
+			}
+			lastCommand = tick
+		}
+		result.Counts[decision.Outcome]++
+		result.Decisions = append(result.Decisions, decision)
+	}
+	return result, nil
+}
+
+func (s *Simulator) resolveTemplate(ctx context.Context, req SimulationRequest) (string, antiBackflowParams, error) {
+	if req.TemplateType != "" || req.TemplateParams != nil {
+		templateType := req.TemplateType
+		if templateType == "" {
+			templateType = defaultTemplateType
+		}
+		raw, err := json.Marshal(req.TemplateParams)
+		if err != nil {
+			return "", antiBackflowParams{}, fmt.Errorf("%w: template_params: %v", ErrInvalidSimulation, err)
+		}
+		return templateType, parseAntiBackflowParams(raw), nil
+	}
+
+	item, err := s.repo.GetStrategy(ctx, req.StationID)
+	if err != nil {
+		return "", antiBackflowParams{}, err
+	}
+	if item == nil {
+		return "", antiBackflowParams{}, fmt.Errorf("%w: station %s has no saved strategy; pass template_params", ErrInvalidSimulation, req.StationID)
+	}
+	template, err := s.repo.GetTemplate(ctx, item.TemplateID)
+	if err != nil {
+		return "", antiBackflowParams{}, err
+	}
+	if template == nil {
+		return "", antiBackflowParams{}, errors.New("strategy simulator: template not found")
+	}
+	return template.Type, parseAntiBackflowParams(template.Params), nil
+}
+
+// calendarAllows applies the saved calendar like the engine does, loading
+// each date once.
+func (s *Simulator) calendarAllows(ctx context.Context, cache map[time.Time]*strategy.Calendar, req SimulationRequest, tick time.Time) (bool, error) {
+	if req.IgnoreCalendar {
+		return true, nil
+	}
+	date := time.Date(tick.Year(), tick.Month(), tick.Day(), 0, 0, 0, 0, time.UTC)
+	cal, cached := cache[date]
+	if !cached {
+		loaded, err := s.repo.GetCalendar(ctx, req.StationID, date)
+		if err != nil {
+			return false, err
+		}
+		cache[date] = loaded
+		cal = loaded
+	}
+	return calendarAllows(cal, tick), nil
+}
@@ -0,0 +1,158 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+)
+
+// templateSemantics lists the semantics each template type reads.
+var templateSemantics = map[string][]masterdata.Semantic{
+	"anti_backflow": {masterdata.SemanticGridExportKW},
+}
+
+// MappingLister lists the point mappings of a station.
+type MappingLister interface {
+	ListByStation(ctx context.Context, stationID string) ([]masterdata.PointMapping, error)
+}
+
+// Definition is a strategy as submitted to the enable and calendar endpoints.
+type Definition struct {
+	StationID      string           `json:"station_id"`
+	TemplateType   string           `json:"template_type"`
+	TemplateParams map[string]any   `json:"template_params"`
+	Calendar       []CalendarWindow `json:"calendar"`
+}
+
+// CalendarWindow is a daily window as accepted by the calendar endpoint.
+type CalendarWindow struct {
+	Date      string `json:"date"`
+	Enabled   bool   `json:"enabled"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// ValidationError describes one problem of a definition.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Validator checks strategy definitions without persisting them.
+type Validator struct {
+	mappings MappingLister
+}
+
+// NewValidator constructs a Validator.
+func NewValidator(mappings MappingLister) (*Validator, error) {
+	if mappings == nil {
+		return nil, errors.New("strategy validator: nil mapping lister")
+	}
+	return &Validator{mappings: mappings}, nil
+}
+
+// Validate returns every problem found in def; an empty result means the
+// definition can be saved. The error is only set when mappings cannot be read.
+func (v *Validator) Validate(ctx context.Context, def Definition) ([]ValidationError, error) {
+	problems := make([]ValidationError, 0)
+	add := func(field, format string, args ...any) {
+		problems = append(problems, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if def.StationID == "" {
+		add("station_id", "is required")
+	}
+	templateType := def.TemplateType
+	if templateType == "" {
+		templateType = defaultTemplateType
+	}
+	semantics, known := templateSemantics[templateType]
+	if !known {
+		add("template_type", "unknown template type %q", templateType)
+	}
+	if templateType == "anti_backflow" {
+		validateAntiBackflowParams(def.TemplateParams, add)
+	}
+
+	if def.StationID != "" && len(semantics) > 0 {
+		mappings, err := v.mappings.ListByStation(ctx, def.StationID)
+		if err != nil {
+			return nil, err
+		}
+		mapped := make(map[string]bool, len(mappings))
+		for _, mapping := range mappings {
+			mapped[mapping.Semantic] = true
+		}
+		for _, semantic := range semantics {
+			if !mapped[string(semantic)] {
+				add("template_type", "station %s has no point mapping for semantic %q", def.StationID, semantic)
+			}
+		}
+	}
+
+	validateCalendar(def.Calendar, add)
+	return problems, nil
+}
+
+func validateAntiBackflowParams(params map[string]any, add func(field, format string, args ...any)) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		add("template_params", "invalid parameters: %v", err)
+		return
+	}
+	var decoded antiBackflowParams
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&decoded); err != nil {
+		add("template_params", "invalid parameters: %v", err)
+		return
+	}
+	if decoded.MaxKW == 0 {
+		decoded.MaxKW = defaultAntiBackflowMaxKW
+	}
+
+	if decoded.DeviceID == "" {
+		add("template_params.device_id", "is required to issue commands")
+	}
+	if decoded.ThresholdKW < 0 {
+		add("template_params.threshold_kw", "must not be negative")
+	}
+	if decoded.MinKW < 0 {
+		add("template_params.min_kw", "must not be negative")
+	}
+	if decoded.MaxKW < 0 {
+		add("template_params.max_kw", "must not be negative")
+	} else if decoded.MaxKW <= decoded.MinKW {
+		add("template_params.max_kw", "must be greater than min_kw (%g)", decoded.MinKW)
+	}
+}
+
+func validateCalendar(windows []CalendarWindow, add func(field, format string, args ...any)) {
+	seen := make(map[string]int, len(windows))
+	for i, window := range windows {
+		field := fmt.Sprintf("calendar[%d]", i)
+		if _, err := time.Parse("2006-01-02", window.Date); err != nil {
+			add(field+".date", "must be YYYY-MM-DD")
+		} else if first, dup := seen[window.Date]; dup {
+			add(field+".date", "conflicts with calendar[%d]: a station has one window per date", first)
+		} else {
+			seen[window.Date] = i
+		}
+		start, startErr := time.Parse("15:04", window.StartTime)
+		if startErr != nil {
+			add(field+".start_time", "must be HH:MM")
+		}
+		end, endErr := time.Parse("15:04", window.EndTime)
+		if endErr != nil {
+			add(field+".end_time", "must be HH:MM")
+		}
+		if startErr == nil && endErr == nil && !end.After(start) {
+			add(field+".end_time", "must be after start_time; the window would never be active")
+		}
+	}
+}
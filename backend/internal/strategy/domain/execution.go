@@ -0,0 +1,18 @@
+package strategy
+
+import "time"
+
+// Execution records one evaluation of a strategy by the engine, including
+// evaluations that issued no command and why.
+type Execution struct {
+	ID         int64
+	StrategyID string
+	TS         time.Time
+	TemplateID string
+	Inputs     []byte
+	Decision   []byte
+	Outcome    string
+	Reason     string
+	CommandID  string
+	CreatedAt  time.Time
+}
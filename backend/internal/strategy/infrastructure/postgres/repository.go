@@ -274,3 +274,75 @@ ORDER BY ts ASC`, strategyID, from.UTC(), to.UTC())
 	}
 	return result, nil
 }
+
+// InsertExecution appends a strategy execution.
+func (r *Repository) InsertExecution(ctx context.Context, exec *strategy.Execution) error {
+	if r == nil || r.db == nil {
+		return errors.New("strategy repo: nil db")
+	}
+	if exec == nil {
+		return errors.New("strategy repo: nil execution")
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO strategy_executions (
+	strategy_id, ts, template_id, inputs, decision, outcome, reason, command_id, created_at
+) VALUES (
+	$1,$2,NULLIF($3,''),$4,$5,$6,NULLIF($7,''),NULLIF($8,''),$9
+)`,
+		exec.StrategyID, exec.TS, exec.TemplateID, nullJSON(exec.Inputs), nullJSON(exec.Decision),
+		exec.Outcome, exec.Reason, exec.CommandID, time.Now().UTC(),
+	)
+	return err
+}
+
+// ListExecutions returns up to limit executions of a strategy, newest first.
+// A positive beforeID only returns executions older than that id.
+func (r *Repository) ListExecutions(ctx context.Context, strategyID string, beforeID int64, limit int) ([]strategy.Execution, error) {
+	if r == nil || r.db == nil {
+		return nil, errors.New("strategy repo: nil db")
+	}
+	rows, err := r.db.QueryContext(ctx, `
+SELECT id, strategy_id, ts, COALESCE(template_id, ''), inputs, decision, outcome,
+	COALESCE(reason, ''), COALESCE(command_id, ''), created_at
+FROM strategy_executions
+WHERE strategy_id = $1 AND ($2 <= 0 OR id < $2)
+ORDER BY id DESC
+LIMIT $3`, strategyID, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []strategy.Execution
+	for rows.Next() {
+		var exec strategy.Execution
+		if err := rows.Scan(
+			&exec.ID,
+			&exec.StrategyID,
+			&exec.TS,
+			&exec.TemplateID,
+			&exec.Inputs,
+			&exec.Decision,
+			&exec.Outcome,
+			&exec.Reason,
+			&exec.CommandID,
+			&exec.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		exec.TS = exec.TS.UTC()
+		exec.CreatedAt = exec.CreatedAt.UTC()
+		result = append(result, exec)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func nullJSON(payload []byte) any {
+	if len(payload) == 0 {
+		return nil
+	}
+	return payload
+}
@@ -132,6 +132,13 @@ func TestStrategy_AntiBackflow_IssuesCommand(t *testing.T) {
 	if len(runs) == 0 {
 		t.Fatalf("expected at least one run")
 	}
+	executions, err := strategyRepo.ListExecutions(ctx, stationID, 0, 10)
+	if err != nil {
+		t.Fatalf("list executions: %v", err)
+	}
+	if len(executions) != 1 || executions[0].Outcome != strategyapp.StatusIssued || executions[0].CommandID != cmd.CommandID || len(executions[0].Inputs) == 0 {
+		t.Fatalf("executions = %+v, want one issued execution for command %s", executions, cmd.CommandID)
+	}
 	if fake.callCount(deviceID) == 0 {
 		t.Fatalf("expected rpc call")
 	}
@@ -158,6 +165,7 @@ INSERT INTO telemetry_points (
 }
 
 func cleanupStrategyTables(ctx context.Context, db *sql.DB) {
+	_, _ = db.ExecContext(ctx, "DELETE FROM strategy_executions")
 	_, _ = db.ExecContext(ctx, "DELETE FROM strategy_runs")
 	_, _ = db.ExecContext(ctx, "DELETE FROM strategy_calendar")
 	_, _ = db.ExecContext(ctx, "DELETE FROM strategies")
@@ -191,6 +199,8 @@ func applyStrategyMigrations(db *sql.DB) error {
 		filepath.Join(root, "migrations", "005_eventing.sql"),
 		filepath.Join(root, "migrations", "007_commands.sql"),
 		filepath.Join(root, "migrations", "013_strategy.sql"),
+		filepath.Join(root, "migrations", "026_strategy_executions.sql"),
+		filepath.Join(root, "migrations", "027_commands_device_index.sql"),
 	}
 	for _, path := range files {
 		content, err := os.ReadFile(path)
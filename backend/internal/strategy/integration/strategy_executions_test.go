@@ -0,0 +1,220 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	commandsapp "microgrid-cloud/internal/commands/application"
+	commandsrepo "microgrid-cloud/internal/commands/infrastructure/postgres"
+	"microgrid-cloud/internal/eventing"
+	eventingrepo "microgrid-cloud/internal/eventing/infrastructure/postgres"
+	strategytelemetry "microgrid-cloud/internal/strategy/adapters/telemetry"
+	strategyapp "microgrid-cloud/internal/strategy/application"
+	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
+	strategyhttp "microgrid-cloud/internal/strategy/interfaces/http"
+)
+
+// stubbedExecutions are the rows served by the strategy-executions-stub
+// driver; the arguments of the latest query land in executionQueryArgs.
+var (
+	stubbedExecutions  [][]driver.Value
+	executionQueryMu   sync.Mutex
+	executionQueryArgs []driver.NamedValue
+)
+
+func init() {
+	sql.Register("strategy-executions-stub", executionsStubDriver{})
+}
+
+func TestStrategyExecutions_RecordsSkippedTicksAndPages(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyStrategyMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	tenantID := "tenant-strategy"
+	stationID := "station-strategy-exec"
+	cleanupStrategyTables(ctx, db)
+
+	strategyRepo := strategyrepo.NewRepository(db)
+	strategyService, err := strategyapp.NewService(strategyRepo)
+	if err != nil {
+		t.Fatalf("strategy service: %v", err)
+	}
+	if _, err := strategyService.SetMode(ctx, stationID, "auto"); err != nil {
+		t.Fatalf("set mode: %v", err)
+	}
+	if _, err := strategyService.SetEnabled(ctx, stationID, true, "anti_backflow", map[string]any{"threshold_kw": 10.0}); err != nil {
+		t.Fatalf("set enabled: %v", err)
+	}
+
+	publisher := eventing.NewPublisher(eventingrepo.NewOutboxStore(db), tenantID, eventbus.NewInMemoryBus())
+	commandService, err := commandsapp.NewService(commandsrepo.NewCommandRepository(db), publisher, tenantID)
+	if err != nil {
+		t.Fatalf("command service: %v", err)
+	}
+	engine, err := strategyapp.NewEngine(strategyRepo, strategytelemetry.NewLatestReader(db), commandService, tenantID)
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	// No calendar window is configured, so every tick is skipped.
+	start := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if err := engine.Tick(ctx, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatalf("tick %d: %v", i, err)
+		}
+	}
+
+	page, err := strategyRepo.ListExecutions(ctx, stationID, 0, 2)
+	if err != nil {
+		t.Fatalf("list executions: %v", err)
+	}
+	if len(page) != 2 || !page[0].TS.Equal(start.Add(2*time.Minute)) {
+		t.Fatalf("first page = %+v, want the two newest executions", page)
+	}
+	for _, exec := range page {
+		if exec.Outcome != strategyapp.StatusSkipped || exec.Reason != "outside calendar window" || exec.CommandID != "" {
+			t.Fatalf("execution = %+v, want a skip outside the calendar window", exec)
+		}
+	}
+	rest, err := strategyRepo.ListExecutions(ctx, stationID, page[1].ID, 2)
+	if err != nil {
+		t.Fatalf("list executions before %d: %v", page[1].ID, err)
+	}
+	if len(rest) != 1 || !rest[0].TS.Equal(start) {
+		t.Fatalf("second page = %+v, want the oldest execution", rest)
+	}
+}
+
+func TestStrategyExecutionsHandler_Pages(t *testing.T) {
+	db, err := sql.Open("strategy-executions-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	service, err := strategyapp.NewService(strategyrepo.NewRepository(db))
+	if err != nil {
+		t.Fatalf("strategy service: %v", err)
+	}
+	handler, err := strategyhttp.NewHandler(service, nil, nil)
+	if err != nil {
+		t.Fatalf("strategy handler: %v", err)
+	}
+
+	ts := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+	stubbedExecutions = [][]driver.Value{
+		{int64(12), "station-exec", ts, "tmpl-anti_backflow", []byte(`{"grid_export_kw":42}`), []byte(`{"target_kw":42}`), "issued", "", "cmd-12", ts},
+		{int64(11), "station-exec", ts.Add(-time.Minute), "tmpl-anti_backflow", []byte(`{"grid_export_kw":3}`), nil, "no_action", "grid export 3 kW is within threshold 10 kW", "", ts},
+		{int64(10), "station-exec", ts.Add(-2 * time.Minute), "tmpl-anti_backflow", nil, nil, "skipped", "outside calendar window", "", ts},
+	}
+
+	var page struct {
+		Executions []struct {
+			ID        int64           `json:"id"`
+			Inputs    json.RawMessage `json:"inputs"`
+			Outcome   string          `json:"outcome"`
+			Reason    string          `json:"reason"`
+			CommandID string          `json:"command_id"`
+		} `json:"executions"`
+		NextBefore int64 `json:"next_before"`
+	}
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/strategies/station-exec/executions?limit=2&before=13", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", resp.Code, resp.Body.String())
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatalf("decode executions: %v", err)
+	}
+	if len(page.Executions) != 2 || page.NextBefore != 11 {
+		t.Fatalf("page = %+v, want two executions and next_before 11", page)
+	}
+	if first := page.Executions[0]; first.CommandID != "cmd-12" || string(first.Inputs) != `{"grid_export_kw":42}` {
+		t.Fatalf("first execution = %+v", first)
+	}
+	if second := page.Executions[1]; second.Outcome != "no_action" || !strings.Contains(second.Reason, "threshold") {
+		t.Fatalf("second execution = %+v, want the no-op reason", second)
+	}
+
+	executionQueryMu.Lock()
+	args := executionQueryArgs
+	executionQueryMu.Unlock()
+	if len(args) != 3 || args[0].Value != "station-exec" || args[1].Value != int64(13) || args[2].Value != int64(3) {
+		t.Fatalf("query args = %+v, want station, cursor 13 and limit+1", args)
+	}
+
+	for _, target := range []string{"executions?limit=0", "executions?limit=501", "executions?before=abc"} {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/v1/strategies/station-exec/"+target, nil))
+		if resp.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want 400", target, resp.Code)
+		}
+	}
+}
+
+// executionsStubDriver serves stubbedExecutions, honouring the LIMIT argument.
+type executionsStubDriver struct{}
+
+func (executionsStubDriver) Open(string) (driver.Conn, error) {
+	return executionsStubConn{}, nil
+}
+
+type executionsStubConn struct{}
+
+func (executionsStubConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if !strings.Contains(query, "FROM strategy_executions") {
+		return &executionsStubRows{}, nil
+	}
+	executionQueryMu.Lock()
+	executionQueryArgs = args
+	executionQueryMu.Unlock()
+	rows := stubbedExecutions
+	if limit, ok := args[len(args)-1].Value.(int64); ok && int(limit) < len(rows) {
+		rows = rows[:limit]
+	}
+	return &executionsStubRows{rows: rows}, nil
+}
+
+func (executionsStubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("executions stub: prepare not supported")
+}
+
+func (executionsStubConn) Close() error { return nil }
+
+func (executionsStubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("executions stub: transactions not supported")
+}
+
+type executionsStubRows struct {
+	rows [][]driver.Value
+}
+
+func (r *executionsStubRows) Columns() []string {
+	return make([]string, 10)
+}
+
+func (r *executionsStubRows) Close() error { return nil }
+
+func (r *executionsStubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
@@ -0,0 +1,115 @@
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/analytics/application/eventbus"
+	commandsapp "microgrid-cloud/internal/commands/application"
+	commandsrepo "microgrid-cloud/internal/commands/infrastructure/postgres"
+	"microgrid-cloud/internal/eventing"
+	eventingrepo "microgrid-cloud/internal/eventing/infrastructure/postgres"
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	strategytelemetry "microgrid-cloud/internal/strategy/adapters/telemetry"
+	strategyapp "microgrid-cloud/internal/strategy/application"
+	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
+)
+
+func TestStrategy_AntiBackflow_ConsecutiveTicksIssueOneCommand(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyStrategyMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	tenantID := "tenant-strategy"
+	stationID := "station-strategy-guard"
+	deviceID := "device-strategy-guard"
+	cleanupStrategyTables(ctx, db)
+
+	if err := seedMapping(ctx, db, stationID, deviceID, string(masterdata.SemanticGridExportKW), "grid_export_kw"); err != nil {
+		t.Fatalf("seed mapping: %v", err)
+	}
+	if err := seedTelemetry(ctx, db, tenantID, stationID, deviceID, "grid_export_kw", 50); err != nil {
+		t.Fatalf("seed telemetry: %v", err)
+	}
+
+	strategyRepo := strategyrepo.NewRepository(db)
+	strategyService, err := strategyapp.NewService(strategyRepo)
+	if err != nil {
+		t.Fatalf("strategy service: %v", err)
+	}
+	if _, err := strategyService.SetMode(ctx, stationID, "auto"); err != nil {
+		t.Fatalf("set mode: %v", err)
+	}
+	if _, err := strategyService.SetEnabled(ctx, stationID, true, "anti_backflow", map[string]any{
+		"threshold_kw": 10.0,
+		"max_kw":       100.0,
+		"device_id":    deviceID,
+	}); err != nil {
+		t.Fatalf("set enabled: %v", err)
+	}
+	now := time.Now().UTC()
+	if err := strategyService.SetCalendar(ctx, stationID, now, true, time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(0, 1, 1, 23, 59, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("set calendar: %v", err)
+	}
+
+	publisher := eventing.NewPublisher(eventingrepo.NewOutboxStore(db), tenantID, eventbus.NewInMemoryBus())
+	commandRepo := commandsrepo.NewCommandRepository(db)
+	commandService, err := commandsapp.NewService(commandRepo, publisher, tenantID)
+	if err != nil {
+		t.Fatalf("command service: %v", err)
+	}
+	engine, err := strategyapp.NewEngine(strategyRepo, strategytelemetry.NewLatestReader(db), commandService, tenantID,
+		strategyapp.WithMinCommandInterval(5*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("engine: %v", err)
+	}
+
+	if err := engine.Tick(ctx, now); err != nil {
+		t.Fatalf("first tick: %v", err)
+	}
+	// The next tick wants a different target, so idempotency alone would issue a second command.
+	if err := seedTelemetry(ctx, db, tenantID, stationID, deviceID, "grid_export_kw", 60); err != nil {
+		t.Fatalf("seed telemetry: %v", err)
+	}
+	if err := engine.Tick(ctx, now.Add(time.Minute)); err != nil {
+		t.Fatalf("second tick: %v", err)
+	}
+
+	issued, err := commandRepo.ListByStationAndTime(ctx, tenantID, stationID, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("list commands: %v", err)
+	}
+	if len(issued) != 1 {
+		t.Fatalf("issued %d commands, want 1", len(issued))
+	}
+	executions, err := strategyRepo.ListExecutions(ctx, stationID, 0, 10)
+	if err != nil {
+		t.Fatalf("list executions: %v", err)
+	}
+	if len(executions) != 2 || executions[0].Outcome != strategyapp.StatusSkipped || executions[1].Outcome != strategyapp.StatusIssued {
+		t.Fatalf("executions = %+v, want the second tick skipped", executions)
+	}
+
+	// An acked command still holds the device until the interval has passed.
+	if err := commandRepo.MarkAcked(ctx, issued[0].CommandID, now); err != nil {
+		t.Fatalf("mark acked: %v", err)
+	}
+	if err := engine.Tick(ctx, now.Add(2*time.Minute)); err != nil {
+		t.Fatalf("third tick: %v", err)
+	}
+	if err := engine.Tick(ctx, now.Add(6*time.Minute)); err != nil {
+		t.Fatalf("fourth tick: %v", err)
+	}
+	issued, err = commandRepo.ListByStationAndTime(ctx, tenantID, stationID, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("list commands: %v", err)
+	}
+	if len(issued) != 2 {
+		t.Fatalf("issued %d commands after the interval, want 2", len(issued))
+	}
+}
@@ -0,0 +1,130 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	strategytelemetry "microgrid-cloud/internal/strategy/adapters/telemetry"
+	strategyapp "microgrid-cloud/internal/strategy/application"
+	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
+	strategyhttp "microgrid-cloud/internal/strategy/interfaces/http"
+)
+
+func TestStrategySimulation_ReplaysSeededTelemetry(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	if err := applyStrategyMigrations(db); err != nil {
+		t.Fatalf("apply migrations: %v", err)
+	}
+	ctx := context.Background()
+	tenantID := "tenant-strategy"
+	stationID := "station-strategy-sim"
+	deviceID := "device-strategy-sim"
+	cleanupStrategyTables(ctx, db)
+
+	if err := seedMapping(ctx, db, stationID, deviceID, string(masterdata.SemanticGridExportKW), "grid_export_kw"); err != nil {
+		t.Fatalf("seed mapping: %v", err)
+	}
+	start := time.Date(2026, time.February, 9, 10, 0, 0, 0, time.UTC)
+	series := []struct {
+		offset time.Duration
+		value  float64
+	}{
+		{-time.Hour, 5}, // carried into the range
+		{2 * time.Minute, 40},
+		{5 * time.Minute, 60},
+		{9 * time.Minute, 2},
+		{20 * time.Minute, 80}, // after the range
+	}
+	for _, point := range series {
+		if _, err := db.ExecContext(ctx, `
+INSERT INTO telemetry_points (tenant_id, station_id, device_id, point_key, ts, value_numeric)
+VALUES ($1,$2,$3,'grid_export_kw',$4,$5)`, tenantID, stationID, deviceID, start.Add(point.offset), point.value); err != nil {
+			t.Fatalf("seed telemetry: %v", err)
+		}
+	}
+
+	strategyRepo := strategyrepo.NewRepository(db)
+	service, err := strategyapp.NewService(strategyRepo)
+	if err != nil {
+		t.Fatalf("strategy service: %v", err)
+	}
+	simulator, err := strategyapp.NewSimulator(strategyRepo, strategytelemetry.NewHistoryReader(db), tenantID,
+		strategyapp.WithSimulatedCommandInterval(5*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("simulator: %v", err)
+	}
+	handler, err := strategyhttp.NewHandler(service, nil, nil, strategyhttp.WithSimulator(simulator))
+	if err != nil {
+		t.Fatalf("strategy handler: %v", err)
+	}
+
+	body := `{
+		"from": "2026-02-09T10:00:00Z",
+		"to": "2026-02-09T10:10:00Z",
+		"template_params": {"threshold_kw": 10, "max_kw": 50, "device_id": "` + deviceID + `"},
+		"ignore_calendar": true
+	}`
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/strategies/"+stationID+"/simulate", strings.NewReader(body)))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %q", resp.Code, resp.Body.String())
+	}
+	var result strategyapp.SimulationResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode simulation: %v", err)
+	}
+
+	want := []string{
+		strategyapp.StatusNoAction, strategyapp.StatusNoAction, // 5 kW carried in
+		strategyapp.StatusIssued,                                                                                   // 40 kW
+		strategyapp.StatusSkipped, strategyapp.StatusSkipped, strategyapp.StatusSkipped, strategyapp.StatusSkipped, // cooldown
+		strategyapp.StatusIssued, strategyapp.StatusSkipped, // 60 kW after the interval, capped at max_kw
+		strategyapp.StatusNoAction, // 2 kW
+	}
+	if len(result.Decisions) != len(want) {
+		t.Fatalf("got %d decisions, want %d: %+v", len(result.Decisions), len(want), result.Decisions)
+	}
+	for i, decision := range result.Decisions {
+		if decision.Outcome != want[i] {
+			t.Fatalf("decision %d at %s = %s (%s), want %s", i, decision.TS.Format("15:04"), decision.Outcome, decision.Reason, want[i])
+		}
+	}
+	if target := result.Decisions[7].TargetKW; target == nil || *target != 50 {
+		t.Fatalf("second command target = %v, want max_kw 50", target)
+	}
+	if result.Counts[strategyapp.StatusIssued] != 2 {
+		t.Fatalf("counts = %v, want 2 simulated commands", result.Counts)
+	}
+
+	var issued int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM commands WHERE station_id = $1", stationID).Scan(&issued); err != nil {
+		t.Fatalf("count commands: %v", err)
+	}
+	if issued != 0 {
+		t.Fatalf("simulation issued %d commands", issued)
+	}
+
+	// Without template params the saved strategy and its calendar apply.
+	if _, err := service.SetEnabled(ctx, stationID, true, "anti_backflow", map[string]any{"threshold_kw": 10.0, "device_id": deviceID}); err != nil {
+		t.Fatalf("set enabled: %v", err)
+	}
+	if err := service.SetCalendar(ctx, stationID, start, true, time.Date(0, 1, 1, 10, 5, 0, 0, time.UTC), time.Date(0, 1, 1, 10, 7, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("set calendar: %v", err)
+	}
+	saved, err := simulator.Simulate(ctx, strategyapp.SimulationRequest{StationID: stationID, From: start, To: start.Add(10 * time.Minute)})
+	if err != nil {
+		t.Fatalf("simulate saved strategy: %v", err)
+	}
+	if saved.Counts[strategyapp.StatusSkipped] != 9 || saved.Counts[strategyapp.StatusIssued] != 1 || saved.Decisions[5].Outcome != strategyapp.StatusIssued {
+		t.Fatalf("saved strategy counts = %v, want one command inside the 10:05-10:07 window", saved.Counts)
+	}
+}
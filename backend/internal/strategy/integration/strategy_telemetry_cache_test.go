@@ -0,0 +1,159 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	strategytelemetry "microgrid-cloud/internal/strategy/adapters/telemetry"
+	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+)
+
+// storedTelemetry is the latest point served by the strategy-telemetry-stub
+// driver; telemetryQueries counts the telemetry_points queries it answered.
+var (
+	storedTelemetryMu sync.Mutex
+	storedTelemetry   struct {
+		ts    time.Time
+		value float64
+	}
+	telemetryQueries int
+)
+
+func init() {
+	sql.Register("strategy-telemetry-stub", telemetryStubDriver{})
+}
+
+func TestCachedReader_ServesEventsAndFallsBackToDB(t *testing.T) {
+	db, err := sql.Open("strategy-telemetry-stub", "")
+	if err != nil {
+		t.Fatalf("open stub db: %v", err)
+	}
+	defer db.Close()
+
+	cache, err := strategytelemetry.NewCachedReader(strategytelemetry.NewLatestReader(db), strategytelemetry.WithCacheTTL(time.Hour))
+	if err != nil {
+		t.Fatalf("cached reader: %v", err)
+	}
+	ctx := context.Background()
+	stored := time.Now().UTC().Add(-10 * time.Minute).Truncate(time.Second)
+	storedTelemetryMu.Lock()
+	storedTelemetry.ts, storedTelemetry.value = stored, 7
+	telemetryQueries = 0
+	storedTelemetryMu.Unlock()
+
+	// Nothing cached yet: the point comes from the database.
+	latest, err := cache.LatestSemantic(ctx, "tenant-cache", "station-cache", masterdata.SemanticGridExportKW)
+	if err != nil {
+		t.Fatalf("latest from db: %v", err)
+	}
+	if latest.Value != 7 || !latest.Timestamp.Equal(stored) || queriedTelemetry() != 1 {
+		t.Fatalf("latest = %+v after %d queries, want the stored 7 kW from one query", latest, queriedTelemetry())
+	}
+
+	received := time.Now().UTC().Truncate(time.Second)
+	if err := cache.HandleTelemetryReceived(ctx, telemetryevents.TelemetryReceived{
+		TenantID:  "tenant-cache",
+		StationID: "station-cache",
+		DeviceID:  "device-cache",
+		Points:    []telemetryevents.TelemetryPoint{{PointKey: "grid_export_kw", Value: 42, TS: received}},
+	}); err != nil {
+		t.Fatalf("handle telemetry: %v", err)
+	}
+	// An older redelivered sample must not win over the newer one.
+	_ = cache.HandleTelemetryReceived(ctx, telemetryevents.TelemetryReceived{
+		TenantID:  "tenant-cache",
+		StationID: "station-cache",
+		DeviceID:  "device-cache",
+		Points:    []telemetryevents.TelemetryPoint{{PointKey: "grid_export_kw", Value: 1, TS: received.Add(-time.Minute)}},
+	})
+
+	for i := 0; i < 3; i++ {
+		latest, err = cache.LatestSemantic(ctx, "tenant-cache", "station-cache", masterdata.SemanticGridExportKW)
+		if err != nil {
+			t.Fatalf("latest from cache: %v", err)
+		}
+		if latest.Value != 42 || !latest.Timestamp.Equal(received) {
+			t.Fatalf("latest = %+v, want the 42 kW event", latest)
+		}
+	}
+	if got := queriedTelemetry(); got != 1 {
+		t.Fatalf("telemetry queried %d times, want cache hits after the event", got)
+	}
+
+	// Another tenant's station has not been seen and is read from the database.
+	if _, err := cache.LatestSemantic(ctx, "tenant-other", "station-cache", masterdata.SemanticGridExportKW); err != nil {
+		t.Fatalf("latest for other tenant: %v", err)
+	}
+	if got := queriedTelemetry(); got != 2 {
+		t.Fatalf("telemetry queried %d times, want a database fallback for the unseen point", got)
+	}
+}
+
+func queriedTelemetry() int {
+	storedTelemetryMu.Lock()
+	defer storedTelemetryMu.Unlock()
+	return telemetryQueries
+}
+
+// telemetryStubDriver maps grid_export_kw of device-cache and serves
+// storedTelemetry as its latest sample.
+type telemetryStubDriver struct{}
+
+func (telemetryStubDriver) Open(string) (driver.Conn, error) {
+	return telemetryStubConn{}, nil
+}
+
+type telemetryStubConn struct{}
+
+func (telemetryStubConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	switch {
+	case strings.Contains(query, "FROM point_mappings"):
+		return &telemetryStubRows{rows: [][]driver.Value{{"grid_export_kw", "device-cache", 1.0}}}, nil
+	case strings.Contains(query, "FROM telemetry_points"):
+		storedTelemetryMu.Lock()
+		defer storedTelemetryMu.Unlock()
+		telemetryQueries++
+		return &telemetryStubRows{rows: [][]driver.Value{{storedTelemetry.ts, storedTelemetry.value}}}, nil
+	}
+	return &telemetryStubRows{}, nil
+}
+
+func (telemetryStubConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("telemetry stub: prepare not supported")
+}
+
+func (telemetryStubConn) Close() error { return nil }
+
+func (telemetryStubConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("telemetry stub: transactions not supported")
+}
+
+type telemetryStubRows struct {
+	rows [][]driver.Value
+}
+
+func (r *telemetryStubRows) Columns() []string {
+	if len(r.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(r.rows[0]))
+}
+
+func (r *telemetryStubRows) Close() error { return nil }
+
+func (r *telemetryStubRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
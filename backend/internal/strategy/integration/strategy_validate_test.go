@@ -0,0 +1,112 @@
+package integration_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	masterdata "microgrid-cloud/internal/masterdata/domain"
+	strategyapp "microgrid-cloud/internal/strategy/application"
+	strategyrepo "microgrid-cloud/internal/strategy/infrastructure/postgres"
+	strategyhttp "microgrid-cloud/internal/strategy/interfaces/http"
+)
+
+func TestStrategyValidate_ValidDefinition(t *testing.T) {
+	handler := newValidateHandler(t)
+	body := `{
+		"station_id": "station-mapped",
+		"template_type": "anti_backflow",
+		"template_params": {"threshold_kw": 5, "min_kw": 0, "max_kw": 200, "device_id": "pcs-1"},
+		"calendar": [{"date": "2026-01-10", "enabled": true, "start_time": "08:00", "end_time": "18:00"}]
+	}`
+	code, result := postValidate(t, handler, body)
+	if code != http.StatusOK || !result.Valid || len(result.Errors) != 0 {
+		t.Fatalf("valid definition: status %d, result %+v", code, result)
+	}
+}
+
+func TestStrategyValidate_InvalidDefinition(t *testing.T) {
+	handler := newValidateHandler(t)
+	body := `{
+		"station_id": "station-unmapped",
+		"template_type": "anti_backflow",
+		"template_params": {"threshold_kw": -1, "min_kw": 50, "max_kw": 20, "treshold": 3},
+		"calendar": [
+			{"date": "2026-01-10", "enabled": true, "start_time": "18:00", "end_time": "08:00"},
+			{"date": "2026-01-10", "enabled": true, "start_time": "09:00", "end_time": "10:00"}
+		]
+	}`
+	code, result := postValidate(t, handler, body)
+	if code != http.StatusUnprocessableEntity || result.Valid {
+		t.Fatalf("invalid definition: status %d, result %+v", code, result)
+	}
+	fields := make(map[string]string)
+	for _, problem := range result.Errors {
+		fields[problem.Field] = problem.Message
+	}
+	// Unknown parameters stop the parameter checks, so the typo is the only params error.
+	for _, field := range []string{"template_type", "template_params", "calendar[0].end_time", "calendar[1].date"} {
+		if _, ok := fields[field]; !ok {
+			t.Fatalf("missing error for %s in %+v", field, result.Errors)
+		}
+	}
+	if !strings.Contains(fields["template_type"], string(masterdata.SemanticGridExportKW)) {
+		t.Fatalf("mapping error = %q, want the missing semantic named", fields["template_type"])
+	}
+
+	// Without the typo the parameter values themselves are checked.
+	code, result = postValidate(t, handler, `{"station_id":"station-mapped","template_params":{"threshold_kw":-1,"min_kw":50,"max_kw":20}}`)
+	fields = make(map[string]string)
+	for _, problem := range result.Errors {
+		fields[problem.Field] = problem.Message
+	}
+	for _, field := range []string{"template_params.threshold_kw", "template_params.max_kw", "template_params.device_id"} {
+		if _, ok := fields[field]; !ok {
+			t.Fatalf("status %d: missing error for %s in %+v", code, field, result.Errors)
+		}
+	}
+}
+
+type validateResult struct {
+	Valid  bool                          `json:"valid"`
+	Errors []strategyapp.ValidationError `json:"errors"`
+}
+
+func newValidateHandler(t *testing.T) http.Handler {
+	t.Helper()
+	service, err := strategyapp.NewService(strategyrepo.NewRepository(nil))
+	if err != nil {
+		t.Fatalf("strategy service: %v", err)
+	}
+	validator, err := strategyapp.NewValidator(stationMappings{
+		"station-mapped": {{StationID: "station-mapped", PointKey: "grid_export", Semantic: string(masterdata.SemanticGridExportKW), Factor: 1}},
+	})
+	if err != nil {
+		t.Fatalf("strategy validator: %v", err)
+	}
+	handler, err := strategyhttp.NewHandler(service, nil, nil, strategyhttp.WithValidator(validator))
+	if err != nil {
+		t.Fatalf("strategy handler: %v", err)
+	}
+	return handler
+}
+
+func postValidate(t *testing.T, handler http.Handler, body string) (int, validateResult) {
+	t.Helper()
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, "/api/v1/strategies/validate", strings.NewReader(body)))
+	var result validateResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode validate response (status %d): %v", resp.Code, err)
+	}
+	return resp.Code, result
+}
+
+type stationMappings map[string][]masterdata.PointMapping
+
+func (m stationMappings) ListByStation(_ context.Context, stationID string) ([]masterdata.PointMapping, error) {
+	return m[stationID], nil
+}
@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"microgrid-cloud/internal/api/apierror"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
 	strategyapp "microgrid-cloud/internal/strategy/application"
@@ -14,19 +16,50 @@ import (
 
 const timeLayout = time.RFC3339
 
+// Paging bounds of the executions endpoint.
+const (
+	defaultExecutionsLimit = 50
+	maxExecutionsLimit     = 500
+)
+
 // Handler serves strategy endpoints.
 type Handler struct {
 	service        *strategyapp.Service
 	stationChecker auth.StationTenantChecker
 	auditLogger    audit.Logger
+	validator      *strategyapp.Validator
+	simulator      *strategyapp.Simulator
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithValidator serves POST /api/v1/strategies/validate with validator.
+func WithValidator(validator *strategyapp.Validator) HandlerOption {
+	return func(h *Handler) {
+		h.validator = validator
+	}
+}
+
+// WithSimulator serves POST /api/v1/strategies/{id}/simulate with simulator.
+func WithSimulator(simulator *strategyapp.Simulator) HandlerOption {
+	return func(h *Handler) {
+		h.simulator = simulator
+	}
 }
 
 // NewHandler constructs a Handler.
-func NewHandler(service *strategyapp.Service, stationChecker auth.StationTenantChecker, auditLogger audit.Logger) (*Handler, error) {
+func NewHandler(service *strategyapp.Service, stationChecker auth.StationTenantChecker, auditLogger audit.Logger, opts ...HandlerOption) (*Handler, error) {
 	if service == nil {
 		return nil, errors.New("strategy handler: nil service")
 	}
-	return &Handler{service: service, stationChecker: stationChecker, auditLogger: auditLogger}, nil
+	h := &Handler{service: service, stationChecker: stationChecker, auditLogger: auditLogger}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(h)
+		}
+	}
+	return h, nil
 }
 
 // ServeHTTP routes strategy requests.
@@ -36,6 +69,10 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	path := strings.TrimPrefix(r.URL.Path, "/api/v1/strategies/")
+	if path == "validate" && r.Method == http.MethodPost && h.validator != nil {
+		h.handleValidate(w, r)
+		return
+	}
 	parts := strings.Split(path, "/")
 	if len(parts) < 1 || parts[0] == "" {
 		w.WriteHeader(http.StatusNotFound)
@@ -66,6 +103,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.handleRuns(w, r, stationID)
 		return
 	}
+	if len(parts) == 2 && parts[1] == "executions" && r.Method == http.MethodGet {
+		h.handleExecutions(w, r, stationID)
+		return
+	}
+	if len(parts) == 2 && parts[1] == "simulate" && r.Method == http.MethodPost && h.simulator != nil {
+		h.handleSimulate(w, r, stationID)
+		return
+	}
 
 	w.WriteHeader(http.StatusNotFound)
 }
@@ -75,12 +120,12 @@ func (h *Handler) handleMode(w http.ResponseWriter, r *http.Request, stationID s
 		Mode string `json:"mode"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	resp, err := h.service.SetMode(r.Context(), stationID, req.Mode)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -95,12 +140,12 @@ func (h *Handler) handleEnable(w http.ResponseWriter, r *http.Request, stationID
 		TemplateParams map[string]any `json:"template_params"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	resp, err := h.service.SetEnabled(r.Context(), stationID, req.Enabled, req.TemplateType, req.TemplateParams)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
@@ -116,26 +161,26 @@ func (h *Handler) handleCalendar(w http.ResponseWriter, r *http.Request, station
 		EndTime   string `json:"end_time"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid json", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
 		return
 	}
 	date, err := time.Parse("2006-01-02", req.Date)
 	if err != nil {
-		http.Error(w, "date must be YYYY-MM-DD", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "date must be YYYY-MM-DD")
 		return
 	}
 	start, err := parseClock(req.StartTime)
 	if err != nil {
-		http.Error(w, "start_time must be HH:MM", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "start_time must be HH:MM")
 		return
 	}
 	end, err := parseClock(req.EndTime)
 	if err != nil {
-		http.Error(w, "end_time must be HH:MM", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "end_time must be HH:MM")
 		return
 	}
 	if err := h.service.SetCalendar(r.Context(), stationID, date.UTC(), req.Enabled, start, end); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
@@ -147,30 +192,177 @@ func (h *Handler) handleCalendar(w http.ResponseWriter, r *http.Request, station
 	})
 }
 
+func (h *Handler) handleValidate(w http.ResponseWriter, r *http.Request) {
+	var def strategyapp.Definition
+	if err := json.NewDecoder(r.Body).Decode(&def); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	tenantID := auth.TenantIDFromContext(r.Context())
+	if tenantID != "" {
+		if err := ensureStationTenant(r, h.stationChecker, tenantID, def.StationID); err != nil {
+			respondTenantError(w, err)
+			return
+		}
+	}
+	problems, err := h.validator.Validate(r.Context(), def)
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "validate strategy error")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(problems) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"valid":  len(problems) == 0,
+		"errors": problems,
+	})
+}
+
+func (h *Handler) handleSimulate(w http.ResponseWriter, r *http.Request, stationID string) {
+	var req struct {
+		From           string         `json:"from"`
+		To             string         `json:"to"`
+		Step           string         `json:"step"`
+		TemplateType   string         `json:"template_type"`
+		TemplateParams map[string]any `json:"template_params"`
+		IgnoreCalendar bool           `json:"ignore_calendar"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidJSON, "invalid json")
+		return
+	}
+	from, err := time.Parse(timeLayout, req.From)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "from must be RFC3339")
+		return
+	}
+	to, err := time.Parse(timeLayout, req.To)
+	if err != nil {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be RFC3339")
+		return
+	}
+	var step time.Duration
+	if req.Step != "" {
+		if step, err = time.ParseDuration(req.Step); err != nil || step <= 0 {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "step must be a positive duration such as 5m")
+			return
+		}
+	}
+	result, err := h.simulator.Simulate(r.Context(), strategyapp.SimulationRequest{
+		StationID:      stationID,
+		From:           from,
+		To:             to,
+		Step:           step,
+		TemplateType:   req.TemplateType,
+		TemplateParams: req.TemplateParams,
+		IgnoreCalendar: req.IgnoreCalendar,
+	})
+	if errors.Is(err, strategyapp.ErrInvalidSimulation) {
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
+		return
+	}
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "simulate strategy error")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
 func (h *Handler) handleRuns(w http.ResponseWriter, r *http.Request, stationID string) {
 	from, err := parseTimeQuery(r, "from")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	to, err := parseTimeQuery(r, "to")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, err.Error())
 		return
 	}
 	if !to.After(from) {
-		http.Error(w, "to must be after from", http.StatusBadRequest)
+		apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "to must be after from")
 		return
 	}
 	list, err := h.service.ListRuns(r.Context(), stationID, from, to)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(list)
 }
 
+type executionView struct {
+	ID         int64           `json:"id"`
+	StrategyID string          `json:"strategy_id"`
+	TS         time.Time       `json:"ts"`
+	TemplateID string          `json:"template_id,omitempty"`
+	Inputs     json.RawMessage `json:"inputs,omitempty"`
+	Decision   json.RawMessage `json:"decision,omitempty"`
+	Outcome    string          `json:"outcome"`
+	Reason     string          `json:"reason,omitempty"`
+	CommandID  string          `json:"command_id,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// handleExecutions pages through executions newest first; next_before is
+// the cursor of the following page and is absent on the last one.
+func (h *Handler) handleExecutions(w http.ResponseWriter, r *http.Request, stationID string) {
+	query := r.URL.Query()
+	limit := defaultExecutionsLimit
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxExecutionsLimit {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "limit must be between 1 and "+strconv.Itoa(maxExecutionsLimit))
+			return
+		}
+		limit = parsed
+	}
+	var before int64
+	if value := query.Get("before"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			apierror.Write(w, http.StatusBadRequest, apierror.CodeInvalidArgument, "before must be a positive execution id")
+			return
+		}
+		before = parsed
+	}
+
+	// One extra row tells whether another page exists.
+	list, err := h.service.ListExecutions(r.Context(), stationID, before, limit+1)
+	if err != nil {
+		apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+	resp := struct {
+		Executions []executionView `json:"executions"`
+		NextBefore int64           `json:"next_before,omitempty"`
+	}{Executions: make([]executionView, 0, len(list))}
+	if len(list) > limit {
+		list = list[:limit]
+		resp.NextBefore = list[limit-1].ID
+	}
+	for _, exec := range list {
+		resp.Executions = append(resp.Executions, executionView{
+			ID:         exec.ID,
+			StrategyID: exec.StrategyID,
+			TS:         exec.TS,
+			TemplateID: exec.TemplateID,
+			Inputs:     exec.Inputs,
+			Decision:   exec.Decision,
+			Outcome:    exec.Outcome,
+			Reason:     exec.Reason,
+			CommandID:  exec.CommandID,
+			CreatedAt:  exec.CreatedAt,
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
 func (h *Handler) logAudit(r *http.Request, stationID, action string, meta map[string]any) {
 	if h.auditLogger == nil {
 		return
@@ -206,14 +398,14 @@ func respondTenantError(w http.ResponseWriter, err error) {
 		return
 	}
 	if errors.Is(err, auth.ErrTenantMismatch) {
-		http.Error(w, "forbidden", http.StatusForbidden)
+		apierror.Write(w, http.StatusForbidden, apierror.CodeForbidden, "forbidden")
 		return
 	}
 	if errors.Is(err, auth.ErrNotFound) {
-		http.Error(w, "not found", http.StatusNotFound)
+		apierror.Write(w, http.StatusNotFound, apierror.CodeNotFound, "not found")
 		return
 	}
-	http.Error(w, "tenant check failed", http.StatusInternalServerError)
+	apierror.Write(w, http.StatusInternalServerError, apierror.CodeInternal, "tenant check failed")
 }
 
 func parseTimeQuery(r *http.Request, key string) (time.Time, error) {
@@ -0,0 +1,112 @@
+package tbadapter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"microgrid-cloud/internal/clock"
+	"microgrid-cloud/internal/observability/metrics"
+)
+
+// ErrCircuitOpen is returned without calling ThingsBoard while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("tbadapter: circuit open, thingsboard unavailable")
+
+// Circuit breaker states, as reported in metrics.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// Defaults for the circuit breaker.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// breaker opens after threshold consecutive failures, fails fast for the
+// cooldown, then lets a single probe through: a successful probe closes it,
+// a failed one opens it again.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu       sync.Mutex
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration, c clock.Clock) *breaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &breaker{threshold: threshold, cooldown: cooldown, clock: c, state: CircuitClosed}
+}
+
+// allow reports whether a call may go to ThingsBoard.
+func (b *breaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.cooldown {
+			metrics.IncTBCircuitRejected()
+			return ErrCircuitOpen
+		}
+		b.transition(CircuitHalfOpen)
+		b.probing = true
+	case CircuitHalfOpen:
+		if b.probing {
+			metrics.IncTBCircuitRejected()
+			return ErrCircuitOpen
+		}
+		b.probing = true
+	}
+	return nil
+}
+
+// record feeds the outcome of an allowed call back into the breaker. failed
+// means ThingsBoard could not serve the call: a transport error or a 5xx.
+func (b *breaker) record(ctx context.Context, failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	probe := b.state == CircuitHalfOpen && b.probing
+	b.probing = false
+	switch {
+	case !failed:
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.transition(CircuitClosed)
+		}
+	case ctx.Err() != nil:
+		// The caller gave up; that says nothing about ThingsBoard.
+	default:
+		b.failures++
+		if probe || b.failures >= b.threshold {
+			b.openedAt = b.clock.Now()
+			if b.state != CircuitOpen {
+				b.transition(CircuitOpen)
+			}
+		}
+	}
+}
+
+func (b *breaker) transition(state string) {
+	b.state = state
+	metrics.IncTBCircuitTransition(state)
+}
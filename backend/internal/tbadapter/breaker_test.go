@@ -0,0 +1,95 @@
+package tbadapter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker_TripsAndRecovers(t *testing.T) {
+	var down atomic.Bool
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(RPCResponse{Status: "ok"})
+	}))
+	defer server.Close()
+
+	now := &stepClock{now: time.Date(2026, time.March, 1, 8, 0, 0, 0, time.UTC)}
+	client, err := NewClient(server.URL, "token", WithCircuitBreaker(3, 30*time.Second), WithClock(now))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	ctx := context.Background()
+	call := func() error {
+		_, err := client.SendRPC(ctx, "device-1", "setPower", json.RawMessage(`{}`))
+		return err
+	}
+
+	down.Store(true)
+	for i := 0; i < 3; i++ {
+		if err := call(); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("failure %d: err = %v, want http error", i+1, err)
+		}
+	}
+	if err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after threshold: err = %v, want ErrCircuitOpen", err)
+	}
+	if hits.Load() != 3 {
+		t.Fatalf("open breaker reached thingsboard: hits = %d, want 3", hits.Load())
+	}
+
+	// After the cooldown a failed probe opens the breaker again.
+	now.now = now.now.Add(30 * time.Second)
+	if err := call(); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe: err = %v, want http error", err)
+	}
+	if err := call(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("after failed probe: err = %v, want ErrCircuitOpen", err)
+	}
+
+	// Once ThingsBoard is back the next probe closes it.
+	down.Store(false)
+	now.now = now.now.Add(30 * time.Second)
+	for i := 0; i < 3; i++ {
+		if err := call(); err != nil {
+			t.Fatalf("recovered call %d: %v", i+1, err)
+		}
+	}
+	if hits.Load() != 7 {
+		t.Fatalf("hits = %d, want 7", hits.Load())
+	}
+}
+
+func TestCircuitBreaker_ClientErrorsDoNotTrip(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "token", WithCircuitBreaker(2, time.Minute))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err := client.SendRPC(context.Background(), "device-1", "setPower", json.RawMessage(`{}`))
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: err = %v, want http 400", i+1, err)
+		}
+	}
+}
+
+type stepClock struct {
+	now time.Time
+}
+
+func (c *stepClock) Now() time.Time { return c.now }
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"microgrid-cloud/internal/clock"
 )
 
 // Client is a minimal ThingsBoard REST client.
@@ -16,17 +18,51 @@ type Client struct {
 	baseURL string
 	token   string
 	client  *http.Client
+	breaker *breaker
+}
+
+// ClientOption configures the TB client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+}
+
+// WithCircuitBreaker fails calls fast with ErrCircuitOpen for cooldown after
+// threshold consecutive calls found ThingsBoard unavailable. A threshold of 0
+// disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.threshold = threshold
+		o.cooldown = cooldown
+	}
+}
+
+// WithClock overrides the clock used by the circuit breaker.
+func WithClock(c clock.Clock) ClientOption {
+	return func(o *clientOptions) {
+		if c != nil {
+			o.clock = c
+		}
+	}
 }
 
 // NewClient constructs a TB client.
-func NewClient(baseURL, token string) (*Client, error) {
+func NewClient(baseURL, token string, opts ...ClientOption) (*Client, error) {
 	if baseURL == "" {
 		return nil, errors.New("tbadapter: empty base url")
 	}
+	options := clientOptions{clock: clock.System{}}
+	for _, opt := range opts {
+		opt(&options)
+	}
 	return &Client{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		token:   token,
 		client:  &http.Client{Timeout: 10 * time.Second},
+		breaker: newBreaker(options.threshold, options.cooldown, options.clock),
 	}, nil
 }
 
@@ -38,8 +74,9 @@ type Tenant struct {
 
 // Asset represents a TB asset.
 type Asset struct {
-	ID   string
-	Name string
+	ID      string
+	Name    string
+	Created bool
 }
 
 // Device represents a TB device.
@@ -47,6 +84,7 @@ type Device struct {
 	ID          string
 	Name        string
 	Credentials string
+	Created     bool
 }
 
 // RPCResponse represents a minimal RPC response.
@@ -84,7 +122,7 @@ func (c *Client) EnsureTenant(ctx context.Context, tenantName string) (Tenant, e
 }
 
 type authUser struct {
-	Authority string  `json:"authority"`
+	Authority string   `json:"authority"`
 	TenantID  entityID `json:"tenantId"`
 }
 
@@ -124,7 +162,7 @@ func (c *Client) EnsureAsset(ctx context.Context, tenantID, stationID, name, ass
 	if err := c.doJSON(ctx, http.MethodPost, "/api/asset", body, &resp); err != nil {
 		return Asset{}, err
 	}
-	return Asset{ID: resp.ID.ID, Name: resp.Name}, nil
+	return Asset{ID: resp.ID.ID, Name: resp.Name, Created: true}, nil
 }
 
 // EnsureDevice finds or creates a device by external device id.
@@ -157,7 +195,29 @@ func (c *Client) EnsureDevice(ctx context.Context, tenantID, deviceID, name, dev
 	if err := c.doJSON(ctx, http.MethodPost, "/api/device", body, &resp); err != nil {
 		return Device{}, err
 	}
-	return Device{ID: resp.ID.ID, Name: resp.Name, Credentials: credentials}, nil
+	return Device{ID: resp.ID.ID, Name: resp.Name, Credentials: credentials, Created: true}, nil
+}
+
+// DeleteAsset deletes an asset by TB id. Missing assets are not an error.
+func (c *Client) DeleteAsset(ctx context.Context, assetID string) error {
+	if assetID == "" {
+		return errors.New("tbadapter: empty asset id")
+	}
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/asset/"+assetID, nil, nil); err != nil && !errors.Is(err, errNotFound) {
+		return err
+	}
+	return nil
+}
+
+// DeleteDevice deletes a device by TB id. Missing devices are not an error.
+func (c *Client) DeleteDevice(ctx context.Context, deviceID string) error {
+	if deviceID == "" {
+		return errors.New("tbadapter: empty device id")
+	}
+	if err := c.doJSON(ctx, http.MethodDelete, "/api/device/"+deviceID, nil, nil); err != nil && !errors.Is(err, errNotFound) {
+		return err
+	}
+	return nil
 }
 
 // CreateRelation creates an asset->device relation.
@@ -183,6 +243,23 @@ func (c *Client) SetAttributes(ctx context.Context, entityType, entityID string,
 	return c.doJSON(ctx, http.MethodPost, path, attrs, nil)
 }
 
+// GetAttributes reads server-scope attributes as a key/value map.
+func (c *Client) GetAttributes(ctx context.Context, entityType, entityID string) (map[string]any, error) {
+	if entityType == "" || entityID == "" {
+		return nil, errors.New("tbadapter: empty entity")
+	}
+	path := fmt.Sprintf("/api/plugins/telemetry/%s/%s/values/attributes/SERVER_SCOPE", strings.ToUpper(entityType), entityID)
+	var resp []attributeValue
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	attrs := make(map[string]any, len(resp))
+	for _, item := range resp {
+		attrs[item.Key] = item.Value
+	}
+	return attrs, nil
+}
+
 // SendRPC sends an RPC command to a device.
 func (c *Client) SendRPC(ctx context.Context, deviceID, commandType string, payload json.RawMessage) (RPCResponse, error) {
 	if deviceID == "" || commandType == "" {
@@ -287,6 +364,11 @@ type assetDeviceResponse struct {
 	Name string   `json:"name"`
 }
 
+type attributeValue struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+}
+
 type entityID struct {
 	ID string `json:"id"`
 }
@@ -326,11 +408,16 @@ func (c *Client) doJSON(ctx context.Context, method, path string, body any, out
 		req.Header.Set("X-Authorization", "Bearer "+c.token)
 	}
 
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
 	resp, err := c.client.Do(req)
 	if err != nil {
+		c.breaker.record(ctx, true)
 		return err
 	}
 	defer resp.Body.Close()
+	c.breaker.record(ctx, resp.StatusCode >= 500)
 
 	if resp.StatusCode == http.StatusNotFound {
 		return errNotFound
@@ -3,23 +3,37 @@ package analytics
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"microgrid-cloud/internal/analytics/application"
 	"microgrid-cloud/internal/analytics/domain/statistic"
+	"microgrid-cloud/internal/energy"
 	masterdata "microgrid-cloud/internal/masterdata/domain"
 	telemetry "microgrid-cloud/internal/telemetry/domain"
 )
 
 // QueryAdapter adapts telemetry queries to analytics application queries.
 type QueryAdapter struct {
-	tenantID string
-	query    telemetry.TelemetryQuery
-	mappings masterdata.PointMappingRepository
+	tenantID   string
+	query      telemetry.TelemetryQuery
+	mappings   masterdata.PointMappingRepository
+	duplicates telemetry.DuplicatePolicy
+}
+
+// QueryAdapterOption configures the query adapter.
+type QueryAdapterOption func(*QueryAdapter)
+
+// WithDuplicatePolicy sets which value is kept when the query returns the same
+// point key more than once at one timestamp; the default keeps the last one.
+func WithDuplicatePolicy(policy telemetry.DuplicatePolicy) QueryAdapterOption {
+	return func(a *QueryAdapter) {
+		a.duplicates = policy
+	}
 }
 
 // NewQueryAdapter constructs the adapter for a single tenant.
-func NewQueryAdapter(tenantID string, query telemetry.TelemetryQuery, mappings masterdata.PointMappingRepository) (*QueryAdapter, error) {
+func NewQueryAdapter(tenantID string, query telemetry.TelemetryQuery, mappings masterdata.PointMappingRepository, opts ...QueryAdapterOption) (*QueryAdapter, error) {
 	if tenantID == "" {
 		return nil, errors.New("telemetry query adapter: empty tenant id")
 	}
@@ -29,7 +43,13 @@ func NewQueryAdapter(tenantID string, query telemetry.TelemetryQuery, mappings m
 	if mappings == nil {
 		return nil, errors.New("telemetry query adapter: nil mapping repository")
 	}
-	return &QueryAdapter{tenantID: tenantID, query: query, mappings: mappings}, nil
+	adapter := &QueryAdapter{tenantID: tenantID, query: query, mappings: mappings}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(adapter)
+		}
+	}
+	return adapter, nil
 }
 
 // QueryHour returns analytics telemetry points within [start, end).
@@ -47,6 +67,7 @@ func (a *QueryAdapter) QueryHour(ctx context.Context, stationID string, start, e
 		return nil, err
 	}
 
+	points = a.dedupe(points)
 	result := make([]application.TelemetryPoint, 0, len(points))
 	for _, point := range points {
 		semanticValues := make(map[string]float64)
@@ -69,14 +90,44 @@ func (a *QueryAdapter) QueryHour(ctx context.Context, stationID string, start, e
 	return result, nil
 }
 
+// dedupe merges points sharing a timestamp, so that a value measured twice is
+// counted once, resolving keys present in both under the duplicate policy.
+func (a *QueryAdapter) dedupe(points []telemetry.TelemetryPoint) []telemetry.TelemetryPoint {
+	indexByTime := make(map[int64]int, len(points))
+	result := make([]telemetry.TelemetryPoint, 0, len(points))
+	for _, point := range points {
+		key := point.At.UnixNano()
+		idx, ok := indexByTime[key]
+		if !ok {
+			indexByTime[key] = len(result)
+			result = append(result, point)
+			continue
+		}
+		merged := make(map[string]float64, len(result[idx].Values)+len(point.Values))
+		for pointKey, value := range result[idx].Values {
+			merged[pointKey] = value
+		}
+		for pointKey, value := range point.Values {
+			if kept, ok := merged[pointKey]; ok {
+				value = a.duplicates.Resolve(kept, value)
+			}
+			merged[pointKey] = value
+		}
+		result[idx].Values = merged
+	}
+	return result
+}
+
 // SumStatisticCalculator sums telemetry values into a statistic fact.
-type SumStatisticCalculator struct{}
+type SumStatisticCalculator struct {
+	// NegativeEnergy is applied to the summed charge and discharge energy of
+	// the hour; the zero value passes them through.
+	NegativeEnergy energy.NegativePolicy
+}
 
 // CalculateHour sums telemetry points into a statistic fact.
-func (SumStatisticCalculator) CalculateHour(ctx context.Context, stationID string, periodStart time.Time, telemetryPoints []application.TelemetryPoint) (statistic.StatisticFact, error) {
+func (c SumStatisticCalculator) CalculateHour(ctx context.Context, stationID string, periodStart time.Time, telemetryPoints []application.TelemetryPoint) (statistic.StatisticFact, error) {
 	_ = ctx
-	_ = stationID
-	_ = periodStart
 
 	var fact statistic.StatisticFact
 	for _, point := range telemetryPoints {
@@ -85,6 +136,13 @@ func (SumStatisticCalculator) CalculateHour(ctx context.Context, stationID strin
 		fact.Earnings += point.Earnings
 		fact.CarbonReduction += point.CarbonReduction
 	}
+	var err error
+	if fact.ChargeKWh, err = c.NegativeEnergy.Apply(fact.ChargeKWh); err != nil {
+		return statistic.StatisticFact{}, fmt.Errorf("station %s hour %s charge: %w", stationID, periodStart.UTC().Format(time.RFC3339), err)
+	}
+	if fact.DischargeKWh, err = c.NegativeEnergy.Apply(fact.DischargeKWh); err != nil {
+		return statistic.StatisticFact{}, fmt.Errorf("station %s hour %s discharge: %w", stationID, periodStart.UTC().Format(time.RFC3339), err)
+	}
 	return fact, nil
 }
 
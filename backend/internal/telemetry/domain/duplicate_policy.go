@@ -0,0 +1,47 @@
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DuplicatePolicy decides which value is kept when the same point key is
+// measured more than once at the same timestamp, e.g. after a device resend.
+type DuplicatePolicy string
+
+const (
+	// DuplicateFirst keeps the value that arrived first.
+	DuplicateFirst DuplicatePolicy = "first"
+	// DuplicateLast keeps the value that arrived last.
+	DuplicateLast DuplicatePolicy = "last"
+	// DuplicateMax keeps the largest value.
+	DuplicateMax DuplicatePolicy = "max"
+)
+
+// ParseDuplicatePolicy parses a policy name; empty means DuplicateLast.
+func ParseDuplicatePolicy(value string) (DuplicatePolicy, error) {
+	switch policy := DuplicatePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return DuplicateLast, nil
+	case DuplicateFirst, DuplicateLast, DuplicateMax:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("telemetry: unknown duplicate policy %q (want first, last or max)", value)
+	}
+}
+
+// Resolve returns the value to keep when next arrives after kept. The zero
+// policy keeps the last value.
+func (p DuplicatePolicy) Resolve(kept, next float64) float64 {
+	switch p {
+	case DuplicateFirst:
+		return kept
+	case DuplicateMax:
+		if next > kept {
+			return next
+		}
+		return kept
+	default:
+		return next
+	}
+}
@@ -0,0 +1,26 @@
+package telemetry
+
+import (
+	"context"
+	"time"
+)
+
+// IngestErrorSample is a failing ingest request kept for inspection. Payload
+// is already redacted and cut to the configured size; PayloadBytes is the size
+// of the original body.
+type IngestErrorSample struct {
+	Reason       string
+	Error        string
+	TenantID     string
+	StationID    string
+	DeviceID     string
+	Payload      string
+	PayloadBytes int
+	Truncated    bool
+	CreatedAt    time.Time
+}
+
+// IngestErrorSampleRepository persists sampled ingest errors.
+type IngestErrorSampleRepository interface {
+	InsertIngestErrorSample(ctx context.Context, sample IngestErrorSample) error
+}
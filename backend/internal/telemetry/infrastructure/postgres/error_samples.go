@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"microgrid-cloud/internal/telemetry/domain"
+)
+
+// IngestErrorSampleRepository stores sampled ingest errors in Postgres.
+type IngestErrorSampleRepository struct {
+	db *sql.DB
+}
+
+// NewIngestErrorSampleRepository constructs the repository.
+func NewIngestErrorSampleRepository(db *sql.DB) *IngestErrorSampleRepository {
+	return &IngestErrorSampleRepository{db: db}
+}
+
+// InsertIngestErrorSample stores one sample.
+func (r *IngestErrorSampleRepository) InsertIngestErrorSample(ctx context.Context, sample telemetry.IngestErrorSample) error {
+	if r == nil || r.db == nil {
+		return errors.New("ingest error samples: nil db")
+	}
+	createdAt := sample.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	_, err := r.db.ExecContext(ctx, `
+INSERT INTO ingest_error_samples (
+	reason,
+	error,
+	tenant_id,
+	station_id,
+	device_id,
+	payload,
+	payload_bytes,
+	truncated,
+	created_at
+) VALUES (
+	$1, $2, $3, $4, $5, $6, $7, $8, $9
+)`, sample.Reason, sample.Error, sample.TenantID, sample.StationID, sample.DeviceID,
+		sample.Payload, sample.PayloadBytes, sample.Truncated, createdAt.UTC())
+	return err
+}
+
+// PurgeBefore deletes samples created before cutoff and returns the number of
+// rows deleted.
+func (r *IngestErrorSampleRepository) PurgeBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("ingest error samples: nil db")
+	}
+	if cutoff.IsZero() {
+		return 0, errors.New("ingest error samples: zero purge cutoff")
+	}
+	result, err := r.db.ExecContext(ctx, "DELETE FROM ingest_error_samples WHERE created_at < $1", cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -13,8 +13,9 @@ import (
 
 // TelemetryQuery is a Postgres query implementation.
 type TelemetryQuery struct {
-	db    *sql.DB
-	table string
+	db         *sql.DB
+	table      string
+	duplicates telemetry.DuplicatePolicy
 }
 
 // NewTelemetryQuery constructs a query with default table name.
@@ -42,7 +43,7 @@ WHERE tenant_id = $1
 	AND station_id = $2
 	AND ts >= $3
 	AND ts < $4
-ORDER BY ts ASC`, q.table)
+ORDER BY ts ASC, created_at ASC, device_id ASC`, q.table)
 
 	rows, err := q.db.QueryContext(ctx, query, tenantID, stationID, start, end)
 	if err != nil {
@@ -69,6 +70,11 @@ ORDER BY ts ASC`, q.table)
 			byTime[ts] = metrics
 			order = append(order, ts)
 		}
+		if kept, ok := metrics[pointKey]; ok {
+			// Several devices may report the same point key at one timestamp.
+			metrics[pointKey] = q.duplicates.Resolve(kept, value.Float64)
+			continue
+		}
 		metrics[pointKey] = value.Float64
 	}
 	if err := rows.Err(); err != nil {
@@ -86,6 +92,16 @@ ORDER BY ts ASC`, q.table)
 // QueryOption configures the telemetry query.
 type QueryOption func(*TelemetryQuery)
 
+// WithDuplicatePolicy sets which value is kept when a point key is measured
+// more than once at the same timestamp; the default keeps the last one.
+func WithDuplicatePolicy(policy telemetry.DuplicatePolicy) QueryOption {
+	return func(query *TelemetryQuery) {
+		if query != nil {
+			query.duplicates = policy
+		}
+	}
+}
+
 // WithQueryTable overrides the default table name for queries.
 func WithQueryTable(table string) QueryOption {
 	return func(query *TelemetryQuery) {
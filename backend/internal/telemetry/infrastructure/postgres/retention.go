@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// purgeablePredicate matches raw telemetry older than $1 whose hour has a
+// completed HOUR statistic, so no aggregate still depends on the raw rows.
+const purgeablePredicate = `t.ts < $1
+	AND EXISTS (
+		SELECT 1
+		FROM analytics_statistics s
+		WHERE s.subject_id = t.station_id
+			AND s.time_type = 'HOUR'
+			AND s.period_start = date_bin('1 hour', t.ts, TIMESTAMPTZ '2000-01-01 00:00:00+00')
+			AND s.is_completed
+	)`
+
+// PurgeBefore deletes raw telemetry older than cutoff whose hour aggregate is
+// completed and returns the number of rows deleted. With dryRun nothing is
+// deleted and the number of rows that would be is returned.
+func (r *TelemetryRepository) PurgeBefore(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	if r == nil || r.db == nil {
+		return 0, errors.New("telemetry repo: nil db")
+	}
+	if cutoff.IsZero() {
+		return 0, errors.New("telemetry repo: zero purge cutoff")
+	}
+	if dryRun {
+		var count int64
+		err := r.db.QueryRowContext(ctx, fmt.Sprintf(`
+SELECT COUNT(*)
+FROM %s t
+WHERE %s`, r.table, purgeablePredicate), cutoff.UTC()).Scan(&count)
+		return count, err
+	}
+	result, err := r.db.ExecContext(ctx, fmt.Sprintf(`
+DELETE FROM %s t
+WHERE %s`, r.table, purgeablePredicate), cutoff.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,96 @@
+package integration_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/clock"
+	telemetry "microgrid-cloud/internal/telemetry/domain"
+	"microgrid-cloud/internal/telemetry/interfaces/thingsboard"
+)
+
+func TestIngestErrorSamples_RecordsRedactedPayload(t *testing.T) {
+	samples := &recordingErrorSamples{}
+	now := time.Date(2026, time.February, 3, 4, 5, 6, 0, time.UTC)
+	handler, err := thingsboard.NewIngestHandler(discardMeasurements{}, nil, nil,
+		thingsboard.WithErrorSamples(samples, 1, 128), thingsboard.WithIngestClock(clock.NewFixed(now)))
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+
+	// Malformed JSON carrying a credential and a long tail.
+	body := `{"tenantId":"tenant-a","meta":{"apiToken":"s3cr3t"},"values":` + strings.Repeat("9", 200)
+	if rec := postIngest(handler, body); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid json status = %d, want 400", rec.Code)
+	}
+	// Valid JSON without a timestamp.
+	if rec := postIngest(handler, `{"tenantId":"tenant-a","stationId":"station-a","deviceId":"device-a","values":{"p":1}}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid payload status = %d, want 400", rec.Code)
+	}
+
+	got := samples.list()
+	if len(got) != 2 {
+		t.Fatalf("samples = %d, want 2", len(got))
+	}
+	malformed := got[0]
+	if malformed.Reason != "invalid_json" || malformed.Error == "" || !malformed.CreatedAt.Equal(now) {
+		t.Fatalf("malformed sample = %+v", malformed)
+	}
+	if strings.Contains(malformed.Payload, "s3cr3t") || !strings.Contains(malformed.Payload, `"apiToken":"[REDACTED]"`) {
+		t.Fatalf("payload not redacted: %s", malformed.Payload)
+	}
+	if !malformed.Truncated || len(malformed.Payload) != 128 || malformed.PayloadBytes != len(body) {
+		t.Fatalf("truncated=%v payload %d bytes of %d, want 128 of %d", malformed.Truncated, len(malformed.Payload), malformed.PayloadBytes, len(body))
+	}
+	invalid := got[1]
+	if invalid.Reason != "invalid_payload" || invalid.TenantID != "tenant-a" || invalid.StationID != "station-a" || invalid.DeviceID != "device-a" || invalid.Truncated {
+		t.Fatalf("invalid payload sample = %+v", invalid)
+	}
+
+	// Sampling is off at rate 0.
+	off := &recordingErrorSamples{}
+	handler, err = thingsboard.NewIngestHandler(discardMeasurements{}, nil, nil, thingsboard.WithErrorSamples(off, 0, 0))
+	if err != nil {
+		t.Fatalf("new handler: %v", err)
+	}
+	postIngest(handler, `{`)
+	if n := len(off.list()); n != 0 {
+		t.Fatalf("samples at rate 0 = %d, want 0", n)
+	}
+}
+
+func postIngest(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/ingest/thingsboard/telemetry", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+type discardMeasurements struct{}
+
+func (discardMeasurements) InsertMeasurements(context.Context, []telemetry.Measurement) error {
+	return nil
+}
+
+type recordingErrorSamples struct {
+	mu      sync.Mutex
+	samples []telemetry.IngestErrorSample
+}
+
+func (r *recordingErrorSamples) InsertIngestErrorSample(_ context.Context, sample telemetry.IngestErrorSample) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples = append(r.samples, sample)
+	return nil
+}
+
+func (r *recordingErrorSamples) list() []telemetry.IngestErrorSample {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]telemetry.IngestErrorSample(nil), r.samples...)
+}
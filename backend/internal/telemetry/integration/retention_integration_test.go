@@ -0,0 +1,107 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	telemetry "microgrid-cloud/internal/telemetry/domain"
+	telemetrypostgres "microgrid-cloud/internal/telemetry/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestTelemetryPurge_OnlyOldRowsWithCompletedHours(t *testing.T) {
+	dsn := os.Getenv("PG_DSN")
+	if dsn == "" {
+		t.Skip("PG_DSN not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if !tableExists(db, "telemetry_points") || !tableExists(db, "analytics_statistics") {
+		t.Skip("telemetry_points or analytics_statistics missing; run migrations")
+	}
+
+	ctx := context.Background()
+	tenantID := "tenant-purge"
+	stationID := "station-purge"
+	cutoff := time.Date(2026, time.January, 10, 0, 0, 0, 0, time.UTC)
+	completedHour := cutoff.Add(-48 * time.Hour)
+	partialHour := cutoff.Add(-47 * time.Hour)
+	recentHour := cutoff.Add(time.Hour)
+
+	_, _ = db.ExecContext(ctx, "DELETE FROM telemetry_points WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID)
+	_, _ = db.ExecContext(ctx, "DELETE FROM analytics_statistics WHERE subject_id = $1", stationID)
+
+	value := 1.0
+	var measurements []telemetry.Measurement
+	for _, hour := range []time.Time{completedHour, partialHour, recentHour} {
+		for _, minute := range []time.Duration{5, 35} {
+			measurements = append(measurements, telemetry.Measurement{
+				TenantID:     tenantID,
+				StationID:    stationID,
+				DeviceID:     "device-purge",
+				PointKey:     "charge_power_kw",
+				TS:           hour.Add(minute * time.Minute),
+				ValueNumeric: &value,
+				Quality:      "good",
+			})
+		}
+	}
+	repo := telemetrypostgres.NewTelemetryRepository(db)
+	if err := repo.InsertMeasurements(ctx, measurements); err != nil {
+		t.Fatalf("insert measurements: %v", err)
+	}
+	// The old completed hour may go; the old partial hour and the recent
+	// completed hour must stay.
+	for _, stat := range []struct {
+		hour      time.Time
+		completed bool
+	}{{completedHour, true}, {partialHour, false}, {recentHour, true}} {
+		_, err := db.ExecContext(ctx, `
+INSERT INTO analytics_statistics (subject_id, time_type, time_key, period_start, statistic_id, is_completed)
+VALUES ($1, 'HOUR', $2, $3, $4, $5)`,
+			stationID, stat.hour.Format("2006010215"), stat.hour, "stat-purge-"+stat.hour.Format("2006010215"), stat.completed)
+		if err != nil {
+			t.Fatalf("insert statistic: %v", err)
+		}
+	}
+
+	count := func() int {
+		var n int
+		if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM telemetry_points WHERE tenant_id = $1 AND station_id = $2", tenantID, stationID).Scan(&n); err != nil {
+			t.Fatalf("count rows: %v", err)
+		}
+		return n
+	}
+
+	wouldDelete, err := repo.PurgeBefore(ctx, cutoff, true)
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if wouldDelete < 2 || count() != 6 {
+		t.Fatalf("dry run reported %d and left %d rows, want >= 2 and 6", wouldDelete, count())
+	}
+
+	if _, err := repo.PurgeBefore(ctx, cutoff, false); err != nil {
+		t.Fatalf("purge: %v", err)
+	}
+	if got := count(); got != 4 {
+		t.Fatalf("rows after purge = %d, want 4", got)
+	}
+	var purgedHourRows int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM telemetry_points WHERE station_id = $1 AND ts >= $2 AND ts < $3",
+		stationID, completedHour, completedHour.Add(time.Hour)).Scan(&purgedHourRows); err != nil {
+		t.Fatalf("count purged hour: %v", err)
+	}
+	if purgedHourRows != 0 {
+		t.Fatalf("completed hour kept %d rows", purgedHourRows)
+	}
+}
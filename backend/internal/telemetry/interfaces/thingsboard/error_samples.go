@@ -0,0 +1,84 @@
+package thingsboard
+
+import (
+	"context"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+
+	"microgrid-cloud/internal/clock"
+	"microgrid-cloud/internal/telemetry/domain"
+)
+
+// DefaultErrorSampleMaxBytes caps the stored payload of a sampled ingest error.
+const DefaultErrorSampleMaxBytes = 4096
+
+// IngestOption configures the ingest handler.
+type IngestOption func(*IngestHandler)
+
+// WithErrorSamples stores the given fraction of failing requests (0 disables,
+// 1 keeps all) in repo, their payload redacted and cut to maxBytes. A
+// non-positive maxBytes uses DefaultErrorSampleMaxBytes.
+func WithErrorSamples(repo telemetry.IngestErrorSampleRepository, rate float64, maxBytes int) IngestOption {
+	return func(h *IngestHandler) {
+		if maxBytes <= 0 {
+			maxBytes = DefaultErrorSampleMaxBytes
+		}
+		h.samples = repo
+		h.sampleRate = rate
+		h.sampleMaxBytes = maxBytes
+	}
+}
+
+// WithIngestClock overrides the clock that stamps error samples and events
+// without a measurement time.
+func WithIngestClock(c clock.Clock) IngestOption {
+	return func(h *IngestHandler) {
+		if c != nil {
+			h.clock = c
+		}
+	}
+}
+
+// secretField matches JSON string members whose name suggests a credential,
+// e.g. "token", "apiKey" or "db_password".
+var secretField = regexp.MustCompile(`(?i)("[a-z0-9_\-]*(?:token|secret|password|passwd|signature|authorization|api_?key)[a-z0-9_\-]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// redactPayload blanks credential-like string members of body and cuts it to
+// maxBytes. It works on the raw text, so malformed JSON is redacted too.
+func redactPayload(body []byte, maxBytes int) (string, bool) {
+	payload := secretField.ReplaceAllString(string(body), `$1"[REDACTED]"`)
+	truncated := false
+	if len(payload) > maxBytes {
+		payload = payload[:maxBytes]
+		truncated = true
+	}
+	// Postgres text rejects NUL bytes and invalid UTF-8, e.g. a rune cut in half.
+	payload = strings.ToValidUTF8(strings.ReplaceAll(payload, "\x00", ""), "\uFFFD")
+	return payload, truncated
+}
+
+// sampleError stores a failing request when it falls into the sample. Errors
+// are logged only, the client still gets the original error response.
+func (h *IngestHandler) sampleError(ctx context.Context, reason string, cause error, body []byte, req ingestRequest) {
+	if h.samples == nil || h.sampleRate <= 0 || (h.sampleRate < 1 && rand.Float64() >= h.sampleRate) {
+		return
+	}
+	payload, truncated := redactPayload(body, h.sampleMaxBytes)
+	sample := telemetry.IngestErrorSample{
+		Reason:       reason,
+		TenantID:     req.TenantID,
+		StationID:    req.StationID,
+		DeviceID:     req.DeviceID,
+		Payload:      payload,
+		PayloadBytes: len(body),
+		Truncated:    truncated,
+		CreatedAt:    h.clock.Now().UTC(),
+	}
+	if cause != nil {
+		sample.Error = cause.Error()
+	}
+	if err := h.samples.InsertIngestErrorSample(ctx, sample); err != nil {
+		h.logger.Printf("telemetry ingest: store error sample: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"microgrid-cloud/internal/clock"
 	"microgrid-cloud/internal/eventing"
 	"microgrid-cloud/internal/observability/metrics"
 	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
@@ -19,17 +20,28 @@ type IngestHandler struct {
 	repo      telemetry.TelemetryRepository
 	publisher *eventing.Publisher
 	logger    *log.Logger
+	clock     clock.Clock
+
+	samples        telemetry.IngestErrorSampleRepository
+	sampleRate     float64
+	sampleMaxBytes int
 }
 
 // NewIngestHandler constructs an ingest handler.
-func NewIngestHandler(repo telemetry.TelemetryRepository, publisher *eventing.Publisher, logger *log.Logger) (*IngestHandler, error) {
+func NewIngestHandler(repo telemetry.TelemetryRepository, publisher *eventing.Publisher, logger *log.Logger, opts ...IngestOption) (*IngestHandler, error) {
 	if repo == nil {
 		return nil, errors.New("thingsboard ingest: nil repository")
 	}
 	if logger == nil {
 		logger = log.Default()
 	}
-	return &IngestHandler{repo: repo, publisher: publisher, logger: logger}, nil
+	handler := &IngestHandler{repo: repo, publisher: publisher, logger: logger, clock: clock.System{}, sampleMaxBytes: DefaultErrorSampleMaxBytes}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(handler)
+		}
+	}
+	return handler, nil
 }
 
 // ServeHTTP ingests telemetry data.
@@ -52,6 +64,7 @@ func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.logger.Printf("telemetry ingest: read body error: %v", err)
 		result = metrics.IngestResultError
 		metrics.IncIngestError("read_body")
+		h.sampleError(r.Context(), "read_body", err, body, ingestRequest{})
 		http.Error(w, "read body error", http.StatusBadRequest)
 		return
 	}
@@ -62,6 +75,7 @@ func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.logger.Printf("telemetry ingest: decode error: %v", err)
 		result = metrics.IngestResultError
 		metrics.IncIngestError("invalid_json")
+		h.sampleError(r.Context(), "invalid_json", err, body, ingestRequest{})
 		http.Error(w, "invalid json", http.StatusBadRequest)
 		return
 	}
@@ -71,6 +85,7 @@ func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.logger.Printf("telemetry ingest: invalid payload: %v", err)
 		result = metrics.IngestResultError
 		metrics.IncIngestError("invalid_payload")
+		h.sampleError(r.Context(), "invalid_payload", err, body, req)
 		http.Error(w, "invalid payload", http.StatusBadRequest)
 		return
 	}
@@ -79,6 +94,7 @@ func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		h.logger.Printf("telemetry ingest: insert error: %v", err)
 		result = metrics.IngestResultError
 		metrics.IncIngestError("insert_error")
+		h.sampleError(r.Context(), "insert_error", err, body, req)
 		http.Error(w, "insert error", http.StatusInternalServerError)
 		return
 	}
@@ -102,7 +118,7 @@ func (h *IngestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			})
 		}
 		if occurredAt.IsZero() {
-			occurredAt = time.Now().UTC()
+			occurredAt = h.clock.Now().UTC()
 		}
 		event := telemetryevents.TelemetryReceived{
 			EventID:    eventing.NewEventID(),
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -17,6 +18,7 @@ import (
 	alarmhttp "microgrid-cloud/internal/alarms/interfaces/http"
 	alarmnotify "microgrid-cloud/internal/alarms/notify"
 	"microgrid-cloud/internal/analytics/application"
+	"microgrid-cloud/internal/analytics/application/backfill"
 	"microgrid-cloud/internal/analytics/application/eventbus"
 	"microgrid-cloud/internal/analytics/application/events"
 	appstatistic "microgrid-cloud/internal/analytics/application/statistic"
@@ -24,22 +26,28 @@ import (
 	analyticsrepo "microgrid-cloud/internal/analytics/infrastructure/postgres"
 	analyticsinterfaces "microgrid-cloud/internal/analytics/interfaces"
 	apihttp "microgrid-cloud/internal/api/http"
+	"microgrid-cloud/internal/api/pagination"
+	"microgrid-cloud/internal/api/ratelimit"
 	"microgrid-cloud/internal/audit"
 	"microgrid-cloud/internal/auth"
+	"microgrid-cloud/internal/clock"
 	commandsapp "microgrid-cloud/internal/commands/application"
 	commandsevents "microgrid-cloud/internal/commands/application/events"
 	commandsrepo "microgrid-cloud/internal/commands/infrastructure/postgres"
 	commandsinterfaces "microgrid-cloud/internal/commands/interfaces"
 	commandshttp "microgrid-cloud/internal/commands/interfaces/http"
+	"microgrid-cloud/internal/energy"
 	"microgrid-cloud/internal/eventing"
 	eventingrepo "microgrid-cloud/internal/eventing/infrastructure/postgres"
 	masterdata "microgrid-cloud/internal/masterdata/domain"
 	masterdatarepo "microgrid-cloud/internal/masterdata/infrastructure/postgres"
+	"microgrid-cloud/internal/observability/buildinfo"
 	"microgrid-cloud/internal/observability/metrics"
 	provisioning "microgrid-cloud/internal/provisioning/application"
 	provisioninghttp "microgrid-cloud/internal/provisioning/interfaces/http"
 	settlementadapters "microgrid-cloud/internal/settlement/adapters/analytics"
 	settlementapp "microgrid-cloud/internal/settlement/application"
+	settlement "microgrid-cloud/internal/settlement/domain"
 	settlementrepo "microgrid-cloud/internal/settlement/infrastructure/postgres"
 	settlementpricing "microgrid-cloud/internal/settlement/infrastructure/pricing"
 	settlementinterfaces "microgrid-cloud/internal/settlement/interfaces"
@@ -55,6 +63,7 @@ import (
 	"microgrid-cloud/internal/tbadapter"
 	telemetryadapters "microgrid-cloud/internal/telemetry/adapters/analytics"
 	telemetryevents "microgrid-cloud/internal/telemetry/application/events"
+	telemetrydomain "microgrid-cloud/internal/telemetry/domain"
 	telemetrypostgres "microgrid-cloud/internal/telemetry/infrastructure/postgres"
 	thingsboard "microgrid-cloud/internal/telemetry/interfaces/thingsboard"
 
@@ -76,16 +85,59 @@ func main() {
 		logger.Fatalf("db ping error: %v", err)
 	}
 
-	metrics.Init(db, logger)
+	// Read-only query endpoints use the replica when one is configured.
+	readDB := db
+	if cfg.DatabaseReplicaURL != "" {
+		replicaDB, err := sql.Open("pgx", cfg.DatabaseReplicaURL)
+		if err != nil {
+			logger.Fatalf("replica db open error: %v", err)
+		}
+		defer replicaDB.Close()
+		if err := replicaDB.Ping(); err != nil {
+			logger.Fatalf("replica db ping error: %v", err)
+		}
+		readDB = replicaDB
+	}
+
+	metricsOpts, err := metrics.ParseBucketSpec(cfg.MetricsBuckets)
+	if err != nil {
+		logger.Fatalf("metrics buckets error: %v", err)
+	}
+	metrics.Init(db, logger, metricsOpts...)
+	clk := clock.Clock(clock.System{})
+	metrics.StartLabelRefresh(context.Background(), db, cfg.MetricsLabelRefresh, logger)
 	stationChecker := auth.NewStationChecker(db)
 	auditRepo := audit.NewRepository(db)
 
+	telemetryDuplicates, err := telemetrydomain.ParseDuplicatePolicy(cfg.TelemetryDuplicatePolicy)
+	if err != nil {
+		logger.Fatalf("telemetry duplicate policy error: %v", err)
+	}
 	telemetryRepo := telemetrypostgres.NewTelemetryRepository(db)
-	telemetryQuery := telemetrypostgres.NewTelemetryQuery(db)
+	telemetryQuery := telemetrypostgres.NewTelemetryQuery(db, telemetrypostgres.WithDuplicatePolicy(telemetryDuplicates))
+	if cfg.TelemetryRetention > 0 && cfg.TelemetryPurgeInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.TelemetryPurgeInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				cutoff := clk.Now().UTC().Add(-cfg.TelemetryRetention)
+				rows, err := telemetryRepo.PurgeBefore(context.Background(), cutoff, cfg.TelemetryPurgeDryRun)
+				if err != nil {
+					logger.Printf("telemetry purge error: %v", err)
+				} else if cfg.TelemetryPurgeDryRun {
+					logger.Printf("telemetry purge dry run: cutoff=%s would_delete=%d", cutoff.Format(time.RFC3339), rows)
+				} else if rows > 0 {
+					logger.Printf("telemetry purge: cutoff=%s deleted=%d", cutoff.Format(time.RFC3339), rows)
+				}
+			}
+		}()
+	}
 	pointMappingRepo := masterdatarepo.NewPointMappingRepository(db)
 	stationRepo := masterdatarepo.NewStationRepository(db)
 
-	queryAdapter, err := telemetryadapters.NewQueryAdapter(cfg.TenantID, telemetryQuery, pointMappingRepo)
+	queryAdapter, err := telemetryadapters.NewQueryAdapter(cfg.TenantID, telemetryQuery, pointMappingRepo,
+		telemetryadapters.WithDuplicatePolicy(telemetryDuplicates),
+	)
 	if err != nil {
 		logger.Fatalf("telemetry query adapter error: %v", err)
 	}
@@ -95,6 +147,7 @@ func main() {
 	registry.Register(events.TelemetryWindowClosed{})
 	registry.Register(events.StatisticCalculated{})
 	registry.Register(settlementapp.SettlementCalculated{})
+	registry.Register(settlementapp.SettlementAnomalyDetected{})
 	registry.Register(commandsevents.CommandIssued{})
 	registry.Register(commandsevents.CommandAcked{})
 	registry.Register(commandsevents.CommandFailed{})
@@ -103,7 +156,8 @@ func main() {
 	outboxStore := eventingrepo.NewOutboxStore(db)
 	processedStore := eventingrepo.NewProcessedStore(db)
 	dlqStore := eventingrepo.NewDLQStore(db)
-	dispatcher := eventing.NewDispatcher(baseBus, outboxStore, registry, dlqStore)
+	dispatcher := eventing.NewDispatcher(baseBus, outboxStore, registry, dlqStore,
+		eventing.WithRetry(cfg.OutboxMaxAttempts, cfg.OutboxRetryBackoff))
 	publisher := eventing.NewPublisher(outboxStore, cfg.TenantID, baseBus)
 	bus := publisher
 	statsRepo := analyticsrepo.NewPostgresStatisticRepository(db, cfg.StationID)
@@ -119,11 +173,11 @@ func main() {
 				result, err := dispatcher.Dispatch(context.Background(), dispatchBatch)
 				duration := time.Since(start)
 				if err != nil {
-					logger.Printf("outbox dispatch error: batch=%d claimed=%d sent=%d failed=%d dlq=%d duration=%s err=%v",
-						dispatchBatch, result.Claimed, result.Sent, result.Failed, result.DLQ, duration, err)
+					logger.Printf("outbox dispatch error: batch=%d claimed=%d sent=%d failed=%d retried=%d dlq=%d duration=%s err=%v",
+						dispatchBatch, result.Claimed, result.Sent, result.Failed, result.Retried, result.DLQ, duration, err)
 				} else if result.Claimed > 0 || result.Failed > 0 {
-					logger.Printf("outbox dispatch: batch=%d claimed=%d sent=%d failed=%d dlq=%d duration=%s",
-						dispatchBatch, result.Claimed, result.Sent, result.Failed, result.DLQ, duration)
+					logger.Printf("outbox dispatch: batch=%d claimed=%d sent=%d failed=%d retried=%d dlq=%d duration=%s",
+						dispatchBatch, result.Claimed, result.Sent, result.Failed, result.Retried, result.DLQ, duration)
 				}
 				<-ticker.C
 			}
@@ -132,24 +186,48 @@ func main() {
 		logger.Printf("outbox dispatch disabled: OUTBOX_DISPATCH_INTERVAL=%s", cfg.OutboxDispatchInterval)
 	}
 
+	negativeEnergy, err := energy.ParseNegativePolicy(cfg.NegativeEnergyPolicy)
+	if err != nil {
+		logger.Fatalf("negative energy policy error: %v", err)
+	}
 	hourlyService := application.NewHourlyStatisticAppService(
 		statsRepo,
 		queryAdapter,
-		telemetryadapters.SumStatisticCalculator{},
+		telemetryadapters.SumStatisticCalculator{NegativeEnergy: negativeEnergy},
 		bus,
 		hourStatisticIDFactory{},
-		systemClock{},
+		clk,
+		application.WithFutureWindowSkew(cfg.AnalyticsWindowSkew),
 	)
 
-	rollupService, err := domainstatistic.NewDailyRollupService(statsRepo, domainstatistic.SystemClock{}, cfg.ExpectedHours)
+	rollupService, err := domainstatistic.NewDailyRollupService(statsRepo, clk, cfg.ExpectedHours, domainstatistic.WithListBatchSize(cfg.AnalyticsListBatchSize))
 	if err != nil {
 		logger.Fatalf("daily rollup service error: %v", err)
 	}
-	dailyApp, err := appstatistic.NewDailyRollupAppService(rollupService, statsRepo, bus, domainstatistic.SystemClock{})
+	dailyApp, err := appstatistic.NewDailyRollupAppService(rollupService, statsRepo, bus, clk, appstatistic.WithCatchUpBatchSize(cfg.AnalyticsListBatchSize))
 	if err != nil {
 		logger.Fatalf("daily rollup app error: %v", err)
 	}
 
+	if cfg.RollupCatchUpInterval > 0 {
+		catchUpInterval := cfg.RollupCatchUpInterval
+		catchUpLookback := cfg.RollupCatchUpLookback
+		go func() {
+			ticker := time.NewTicker(catchUpInterval)
+			defer ticker.Stop()
+			for {
+				result, err := dailyApp.CatchUp(context.Background(), cfg.StationID, catchUpLookback)
+				if err != nil {
+					logger.Printf("rollup catch-up error: station=%s lookback=%s err=%v", cfg.StationID, catchUpLookback, err)
+				} else if result.DaysRolledUp > 0 {
+					logger.Printf("rollup catch-up: station=%s scanned=%d rolled_up=%d completed=%d",
+						cfg.StationID, result.DaysScanned, result.DaysRolledUp, result.DaysCompleted)
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
 	application.WireAnalyticsEventBus(baseBus, hourlyService, dailyApp, processedStore)
 	eventing.Subscribe(baseBus, eventbus.EventTypeOf[events.StatisticCalculated](), "analytics.log", func(ctx context.Context, event any) error {
 		evt, ok := event.(events.StatisticCalculated)
@@ -165,14 +243,57 @@ func main() {
 		return nil
 	}, processedStore)
 
-	dayEnergyReader := settlementadapters.NewDayHourEnergyReader(db, settlementadapters.WithExpectedHours(cfg.ExpectedHours))
-	priceProvider, err := settlementpricing.NewFixedPriceProvider(cfg.PricePerKWh)
+	dayEnergyReader := settlementadapters.NewDayHourEnergyReader(db,
+		settlementadapters.WithExpectedHours(cfg.ExpectedHours),
+		settlementadapters.WithNegativeEnergyPolicy(negativeEnergy),
+	)
+	categoryPriceOpts, err := settlementpricing.ParseCategoryPriceSpec(cfg.CategoryPrices)
+	if err != nil {
+		logger.Fatalf("category prices error: %v", err)
+	}
+	priceProvider, err := settlementpricing.NewFixedPriceProvider(cfg.PricePerKWh, categoryPriceOpts...)
 	if err != nil {
 		logger.Fatalf("price provider error: %v", err)
 	}
-	settlementRepo := settlementrepo.NewSettlementRepository(db, settlementrepo.WithTenantID(cfg.TenantID), settlementrepo.WithCurrency(cfg.Currency))
+	tenantSettings := masterdatarepo.NewTenantSettingsRepository(db)
+	settlementRepo := settlementrepo.NewSettlementRepository(db,
+		settlementrepo.WithTenantID(cfg.TenantID),
+		settlementrepo.WithCurrency(cfg.Currency),
+		settlementrepo.WithCurrencyResolver(tenantSettings),
+	)
 	settlementPublisher := settlementinterfaces.NewOutboxPublisher(publisher, cfg.TenantID)
-	settlementApp, err := settlementapp.NewDaySettlementApplicationService(settlementRepo, dayEnergyReader, priceProvider, settlementPublisher, systemClock{})
+	stationEnergyCaps, err := settlementapp.ParseStationEnergyCaps(cfg.StationEnergyCaps)
+	if err != nil {
+		logger.Fatalf("settlement energy caps error: %v", err)
+	}
+	tenantFloors, err := settlementapp.ParseBillingFloors(cfg.TenantMinBillableKWh)
+	if err != nil {
+		logger.Fatalf("settlement tenant billing floors error: %v", err)
+	}
+	categoryFloors, err := settlementapp.ParseBillingFloors(cfg.CategoryMinBillableKWh)
+	if err != nil {
+		logger.Fatalf("settlement category billing floors error: %v", err)
+	}
+	billingFloor := settlementapp.BillingFloor{
+		MinDayEnergyKWh:         cfg.MinBillableKWh,
+		TenantMinDayEnergyKWh:   tenantFloors,
+		CategoryMinDayEnergyKWh: categoryFloors,
+	}
+	settlementOpts := []settlementapp.DaySettlementOption{
+		settlementapp.WithAnomalyPolicy(settlementapp.AnomalyPolicy{
+			DayEnergyCapKWh:        cfg.DayEnergyCapKWh,
+			StationDayEnergyCapKWh: stationEnergyCaps,
+			PriceAnomalies:         cfg.PriceAnomalies,
+		}),
+		settlementapp.WithAnomalyPublisher(settlementPublisher),
+		settlementapp.WithBillingFloor(billingFloor, cfg.TenantID),
+		settlementapp.WithNegativeEnergyPolicy(negativeEnergy),
+		settlementapp.WithCompletedDays(dayEnergyReader),
+	}
+	if cfg.SettleFullDaysOnly {
+		settlementOpts = append(settlementOpts, settlementapp.WithExpectedHours(cfg.ExpectedHours))
+	}
+	settlementApp, err := settlementapp.NewDaySettlementApplicationService(settlementRepo, dayEnergyReader, priceProvider, settlementPublisher, clk, settlementOpts...)
 	if err != nil {
 		logger.Fatalf("settlement app error: %v", err)
 	}
@@ -182,19 +303,71 @@ func main() {
 	}
 	eventing.Subscribe(baseBus, eventbus.EventTypeOf[events.StatisticCalculated](), "settlement.day", settlementHandler.HandleStatisticCalculated, processedStore)
 
+	if cfg.SettleCatchUpInterval > 0 {
+		settlementCatchUpInterval := cfg.SettleCatchUpInterval
+		settlementCatchUpLookback := cfg.SettleCatchUpLookback
+		go func() {
+			ticker := time.NewTicker(settlementCatchUpInterval)
+			defer ticker.Stop()
+			for {
+				result, err := settlementApp.CatchUp(context.Background(), cfg.StationID, settlementCatchUpLookback)
+				if err != nil {
+					logger.Printf("settlement catch-up error: station=%s lookback=%s err=%v", cfg.StationID, settlementCatchUpLookback, err)
+				} else if result.DaysMissing > 0 {
+					logger.Printf("settlement catch-up: station=%s completed_days=%d missing=%d settled=%d",
+						cfg.StationID, result.DaysCompleted, result.DaysMissing, result.DaysSettled)
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
+	backfillTracker, err := backfill.NewTracker(analyticsrepo.NewBackfillRepository(db), clk)
+	if err != nil {
+		logger.Fatalf("backfill tracker error: %v", err)
+	}
+	eventing.Subscribe(baseBus, eventbus.EventTypeOf[events.StatisticCalculated](), "analytics.backfill", func(ctx context.Context, event any) error {
+		evt, ok := event.(events.StatisticCalculated)
+		if !ok {
+			return eventbus.ErrInvalidEventType
+		}
+		return backfillTracker.HandleStatisticCalculated(ctx, evt)
+	}, processedStore)
+	eventing.Subscribe(baseBus, eventbus.EventTypeOf[settlementapp.SettlementCalculated](), "analytics.backfill.settlement", func(ctx context.Context, event any) error {
+		evt, ok := event.(settlementapp.SettlementCalculated)
+		if !ok {
+			return eventbus.ErrInvalidEventType
+		}
+		if !evt.Recalculate {
+			return nil
+		}
+		return backfillTracker.HandleSettlementCalculated(ctx, evt.SubjectID, evt.DayStart)
+	}, processedStore)
+
+	if cfg.SettlementWebhookURL != "" {
+		settlementWebhook, err := settlementinterfaces.NewSettlementWebhook(cfg.SettlementWebhookURL,
+			settlementinterfaces.WithWebhookSecret(cfg.SettlementWebhookSecret),
+			settlementinterfaces.WithWebhookRetry(cfg.SettlementHookAttempts, cfg.SettlementHookBackoff),
+		)
+		if err != nil {
+			logger.Fatalf("settlement webhook error: %v", err)
+		}
+		eventing.Subscribe(baseBus, eventbus.EventTypeOf[settlementapp.SettlementCalculated](), "settlement.webhook", settlementWebhook.HandleSettlementCalculated, processedStore)
+	}
+
 	shadowCfg, err := shadowapp.LoadConfig()
 	if err != nil {
 		logger.Fatalf("shadowrun config error: %v", err)
 	}
 	shadowRepo := shadowrepo.NewRepository(db)
 
-	alarmRuleRepo := alarmrepo.NewAlarmRuleRepository(db)
+	alarmRuleRepo := alarmrepo.NewAlarmRuleRepository(db, alarmrepo.WithAlarmRuleClock(clk))
 	alarmRepo := alarmrepo.NewAlarmRepository(db)
 	alarmStateRepo := alarmrepo.NewAlarmRuleStateRepository(db)
-	alarmBroker := alarmhttp.NewSSEBroker()
+	alarmBroker := alarmhttp.NewSSEBroker(alarmhttp.WithClientBuffer(cfg.AlarmStreamClientBuffer))
 	alarmNotifiers := []alarmapp.AlarmNotifier{alarmBroker}
 	if cfg.AlarmWebhookURL != "" {
-		channel, err := alarmnotify.NewWebhookChannel(cfg.AlarmWebhookURL)
+		channel, err := alarmnotify.NewWebhookChannel(cfg.AlarmWebhookURL, alarmnotify.WithStructuredFields(cfg.AlarmWebhookStructured))
 		if err != nil {
 			logger.Fatalf("alarm webhook error: %v", err)
 		}
@@ -202,13 +375,44 @@ func main() {
 		if err != nil {
 			logger.Fatalf("alarm template error: %v", err)
 		}
+		digestTpl, err := alarmnotify.NewDigestTemplate(cfg.AlarmDigestTemplate)
+		if err != nil {
+			logger.Fatalf("alarm digest template error: %v", err)
+		}
 		opts := []alarmnotify.Option{
 			alarmnotify.WithEscalation(cfg.AlarmEscalationAfter),
 			alarmnotify.WithCooldown(cfg.AlarmNotifyCooldown),
 			alarmnotify.WithDedupeWindow(cfg.AlarmNotifyDedupeWindow),
 			alarmnotify.WithRequestTimeout(cfg.AlarmNotifyTimeout),
+			alarmnotify.WithClock(clk),
+			alarmnotify.WithDigest(cfg.AlarmDigestInterval),
+			alarmnotify.WithDigestTemplate(digestTpl),
+		}
+		if cfg.AlarmEscalationChain != "" {
+			stages, err := alarmnotify.ParseEscalationChain(cfg.AlarmEscalationChain, alarmnotify.WithStructuredFields(cfg.AlarmWebhookStructured))
+			if err != nil {
+				logger.Fatalf("alarm escalation chain error: %v", err)
+			}
+			opts = append(opts, alarmnotify.WithEscalationChain(stages...))
+		}
+		if cfg.AlarmNotifyChannels != "" {
+			channels, err := alarmnotify.ParseNamedChannels(cfg.AlarmNotifyChannels, alarmnotify.WithStructuredFields(cfg.AlarmWebhookStructured))
+			if err != nil {
+				logger.Fatalf("alarm notify channels error: %v", err)
+			}
+			opts = append(opts, alarmnotify.WithRuleChannels(channels))
+		}
+		if cfg.AlarmNotifyQuietHours != "" {
+			windows, err := alarmnotify.ParseQuietHours(cfg.AlarmNotifyQuietHours)
+			if err != nil {
+				logger.Fatalf("alarm quiet hours error: %v", err)
+			}
+			opts = append(opts, alarmnotify.WithQuietHours(windows))
+		}
+		if cfg.AlarmRetryAttempts > 1 && cfg.AlarmRetryBackoff > 0 {
+			opts = append(opts, alarmnotify.WithRetryQueue(alarmrepo.NewNotificationRetryRepository(db), cfg.AlarmRetryAttempts, cfg.AlarmRetryBackoff))
 		}
-		if resolver := buildShadowrunReportResolver(shadowRepo, cfg.AlarmReportBaseURL, cfg.AlarmReportLookbackDays); resolver != nil {
+		if resolver := buildShadowrunReportResolver(shadowRepo, cfg.AlarmReportBaseURL, cfg.AlarmReportLookbackDays, clk); resolver != nil {
 			opts = append(opts, alarmnotify.WithReportURLResolver(resolver))
 		}
 		alarmNotifier, err := alarmnotify.NewNotifier(alarmRuleRepo, stationRepo, alarmRepo, channel, tpl, opts...)
@@ -216,11 +420,49 @@ func main() {
 			logger.Fatalf("alarm notifier error: %v", err)
 		}
 		alarmNotifiers = append(alarmNotifiers, alarmNotifier)
+		if cfg.AlarmRetryAttempts > 1 && cfg.AlarmRetryBackoff > 0 {
+			go func() {
+				ticker := time.NewTicker(cfg.AlarmRetryBackoff)
+				defer ticker.Stop()
+				for range ticker.C {
+					if _, err := alarmNotifier.RetryFailed(context.Background()); err != nil {
+						logger.Printf("alarm notify retry error: %v", err)
+					}
+				}
+			}()
+		}
+	} else if cfg.AlarmNotifyChannels != "" {
+		logger.Printf("alarm notify channels ignored: ALARM_WEBHOOK_URL is not set")
 	}
-	alarmService, err := alarmapp.NewService(alarmRuleRepo, alarmRepo, alarmStateRepo, pointMappingRepo, cfg.TenantID, alarmapp.WithNotifier(alarmnotify.NewMultiNotifier(alarmNotifiers...)))
+	alarmService, err := alarmapp.NewService(alarmRuleRepo, alarmRepo, alarmStateRepo, pointMappingRepo, cfg.TenantID, alarmapp.WithNotifier(alarmnotify.NewMultiNotifier(alarmNotifiers...)), alarmapp.WithClock(clk), alarmapp.WithStaleAfter(cfg.AlarmStaleAfter), alarmapp.WithEvaluationInterval(cfg.AlarmEvalInterval), alarmapp.WithFlapDetection(cfg.AlarmFlapWindow, cfg.AlarmFlapThreshold, cfg.AlarmFlapSuppress), alarmapp.WithRuleTemplates(alarmrepo.NewAlarmRuleTemplateRepository(db), stationRepo))
 	if err != nil {
 		logger.Fatalf("alarm service error: %v", err)
 	}
+	if cfg.AlarmStaleAfter > 0 && cfg.AlarmStaleSweepInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.AlarmStaleSweepInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				cleared, err := alarmService.SweepStale(context.Background())
+				if err != nil {
+					logger.Printf("alarm stale sweep error: %v", err)
+				} else if cleared > 0 {
+					logger.Printf("alarm stale sweep: cleared=%d", cleared)
+				}
+			}
+		}()
+	}
+	if cfg.AlarmEvalInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.AlarmEvalInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if _, err := alarmService.FlushSampled(context.Background()); err != nil {
+					logger.Printf("alarm sampled flush error: %v", err)
+				}
+			}
+		}()
+	}
 	alarmConsumer, err := alarminterfaces.NewTelemetryReceivedConsumer(alarmService)
 	if err != nil {
 		logger.Fatalf("alarm consumer error: %v", err)
@@ -234,29 +476,62 @@ func main() {
 	}, processedStore)
 
 	statementRepo := settlementrepo.NewStatementRepository(db)
-	statementService, err := settlementapp.NewStatementService(statementRepo, cfg.TenantID)
+	snapshotAlgorithm, err := settlement.ParseSnapshotAlgorithm(cfg.SnapshotAlgorithm)
 	if err != nil {
-		logger.Fatalf("statement service error: %v", err)
+		logger.Fatalf("statement snapshot algorithm error: %v", err)
 	}
-	statementHandler, err := settlementinterfaces.NewStatementHandler(statementService, stationChecker, auditRepo)
+	statementService, err := settlementapp.NewStatementService(statementRepo, cfg.TenantID,
+		settlementapp.WithCategoryPricer(priceProvider),
+		settlementapp.WithStatementBillingFloor(billingFloor),
+		settlementapp.WithSnapshotAlgorithm(snapshotAlgorithm),
+		settlementapp.WithStatementCurrency(tenantSettings, cfg.Currency),
+		settlementapp.WithBillingCycle(tenantSettings),
+		settlementapp.WithReadRepository(settlementrepo.NewStatementRepository(readDB)),
+		settlementapp.WithStatementClock(clk),
+	)
 	if err != nil {
-		logger.Fatalf("statement handler error: %v", err)
+		logger.Fatalf("statement service error: %v", err)
 	}
-
-	ingestHandler, err := thingsboard.NewIngestHandler(telemetryRepo, publisher, logger)
+	ingestOpts := []thingsboard.IngestOption{thingsboard.WithIngestClock(clk)}
+	if cfg.IngestErrorSampleRate > 0 {
+		errorSamples := telemetrypostgres.NewIngestErrorSampleRepository(db)
+		ingestOpts = append(ingestOpts, thingsboard.WithErrorSamples(errorSamples, cfg.IngestErrorSampleRate, cfg.IngestErrorSampleBytes))
+		if cfg.IngestErrorSampleTTL > 0 {
+			go func() {
+				ticker := time.NewTicker(time.Hour)
+				defer ticker.Stop()
+				for range ticker.C {
+					cutoff := clk.Now().UTC().Add(-cfg.IngestErrorSampleTTL)
+					if rows, err := errorSamples.PurgeBefore(context.Background(), cutoff); err != nil {
+						logger.Printf("ingest error sample purge error: %v", err)
+					} else if rows > 0 {
+						logger.Printf("ingest error sample purge: cutoff=%s deleted=%d", cutoff.Format(time.RFC3339), rows)
+					}
+				}
+			}()
+		}
+	}
+	ingestHandler, err := thingsboard.NewIngestHandler(telemetryRepo, publisher, logger, ingestOpts...)
 	if err != nil {
 		logger.Fatalf("ingest handler error: %v", err)
 	}
-	windowCloseHandler, err := analyticsinterfaces.NewWindowCloseHandler(publisher, logger)
+	windowCloseHandler, err := analyticsinterfaces.NewWindowCloseHandler(publisher, logger, analyticsinterfaces.WithBackfillTracker(backfillTracker))
 	if err != nil {
 		logger.Fatalf("window close handler error: %v", err)
 	}
 
-	tbClient, err := tbadapter.NewClient(cfg.TBBaseURL, cfg.TBToken)
+	tbClient, err := tbadapter.NewClient(cfg.TBBaseURL, cfg.TBToken, tbadapter.WithCircuitBreaker(cfg.TBBreakerThreshold, cfg.TBBreakerCooldown))
 	if err != nil {
 		logger.Fatalf("tb client error: %v", err)
 	}
-	provisionService, err := provisioning.NewService(db, tbClient)
+	provisionService, err := provisioning.NewService(
+		db,
+		tbClient,
+		provisioning.WithCompensation(cfg.ProvisionCompensation),
+		provisioning.WithBulkConcurrency(cfg.ProvisionBulkConcurrency),
+		provisioning.WithAlarmTemplates(alarmService),
+		provisioning.WithClock(clk),
+	)
 	if err != nil {
 		logger.Fatalf("provisioning service error: %v", err)
 	}
@@ -265,8 +540,17 @@ func main() {
 		logger.Fatalf("provisioning handler error: %v", err)
 	}
 
+	bulkProvisionHandler, err := provisioninghttp.NewBulkProvisioningHandler(provisionService, auditRepo)
+	if err != nil {
+		logger.Fatalf("bulk provisioning handler error: %v", err)
+	}
+	readinessHandler, err := provisioninghttp.NewStationReadinessHandler(provisionService, stationChecker)
+	if err != nil {
+		logger.Fatalf("readiness handler error: %v", err)
+	}
+
 	commandRepo := commandsrepo.NewCommandRepository(db)
-	commandService, err := commandsapp.NewService(commandRepo, publisher, cfg.TenantID)
+	commandService, err := commandsapp.NewService(commandRepo, publisher, cfg.TenantID, commandsapp.WithClock(clk))
 	if err != nil {
 		logger.Fatalf("command service error: %v", err)
 	}
@@ -285,12 +569,47 @@ func main() {
 	if err != nil {
 		logger.Fatalf("strategy service error: %v", err)
 	}
-	strategyHandler, err := strategyhttp.NewHandler(strategyService, stationChecker, auditRepo)
+	strategyValidator, err := strategyapp.NewValidator(pointMappingRepo)
+	if err != nil {
+		logger.Fatalf("strategy validator error: %v", err)
+	}
+	strategySimulator, err := strategyapp.NewSimulator(strategyRepo, strategytelemetry.NewHistoryReader(db), cfg.TenantID,
+		strategyapp.WithSimulatedCommandInterval(cfg.StrategyCommandInterval),
+	)
+	if err != nil {
+		logger.Fatalf("strategy simulator error: %v", err)
+	}
+	strategyHandler, err := strategyhttp.NewHandler(strategyService, stationChecker, auditRepo,
+		strategyhttp.WithValidator(strategyValidator),
+		strategyhttp.WithSimulator(strategySimulator),
+	)
 	if err != nil {
 		logger.Fatalf("strategy handler error: %v", err)
 	}
-	strategyTelemetry := strategytelemetry.NewLatestReader(db)
-	strategyEngine, err := strategyapp.NewEngine(strategyRepo, strategyTelemetry, commandService, cfg.TenantID)
+	var strategyTelemetry strategyapp.LatestTelemetryReader = strategytelemetry.NewLatestReader(db)
+	if cfg.StrategyTelemetryTTL > 0 {
+		telemetryCache, err := strategytelemetry.NewCachedReader(strategytelemetry.NewLatestReader(db),
+			strategytelemetry.WithCacheTTL(cfg.StrategyTelemetryTTL),
+			strategytelemetry.WithMappingCacheTTL(cfg.StrategyMappingTTL),
+			strategytelemetry.WithCacheClock(clk),
+		)
+		if err != nil {
+			logger.Fatalf("strategy telemetry cache error: %v", err)
+		}
+		// The cache is rebuilt from the database after a restart, so it skips the processed store.
+		eventing.Subscribe(baseBus, eventbus.EventTypeOf[telemetryevents.TelemetryReceived](), "strategy.telemetry_cache", func(ctx context.Context, event any) error {
+			evt, ok := event.(telemetryevents.TelemetryReceived)
+			if !ok {
+				return eventbus.ErrInvalidEventType
+			}
+			return telemetryCache.HandleTelemetryReceived(ctx, evt)
+		}, nil)
+		strategyTelemetry = telemetryCache
+	}
+	strategyEngine, err := strategyapp.NewEngine(strategyRepo, strategyTelemetry, commandService, cfg.TenantID,
+		strategyapp.WithMinCommandInterval(cfg.StrategyCommandInterval),
+		strategyapp.WithCommandAckWait(cfg.StrategyCommandAckWait),
+	)
 	if err != nil {
 		logger.Fatalf("strategy engine error: %v", err)
 	}
@@ -309,98 +628,292 @@ func main() {
 	if shadowCfg.WebhookURL != "" {
 		shadowNotifier = shadownotify.NewWebhookNotifier(shadowCfg.WebhookURL)
 	}
-	shadowRunner := shadowapp.NewRunner(shadowRepo, db, shadowCfg, shadowNotifier, shadowMetrics, logger)
-	shadowHandler, err := shadowhttp.NewHandler(shadowRunner, shadowRepo, cfg.TenantID, stationChecker)
+	windowRepublisher, err := analyticsinterfaces.NewWindowRepublisher(publisher, analyticsinterfaces.WithRepublisherClock(clk))
+	if err != nil {
+		logger.Fatalf("window republisher error: %v", err)
+	}
+	shadowRunner := shadowapp.NewRunner(shadowRepo, db, shadowCfg, shadowNotifier, shadowMetrics, logger, shadowapp.WithHealPublisher(windowRepublisher), shadowapp.WithRunnerClock(clk))
+	shadowHandler, err := shadowhttp.NewHandler(shadowRunner, shadowRepo, cfg.TenantID, stationChecker,
+		shadowhttp.WithReadRepository(shadowrepo.NewRepository(readDB)),
+		shadowhttp.WithReportRange(cfg.APIDefaultRange, cfg.APIMaxDayRange),
+		shadowhttp.WithHandlerClock(clk),
+	)
 	if err != nil {
 		logger.Fatalf("shadowrun handler error: %v", err)
 	}
-	shadowScheduler := shadowapp.NewScheduler(shadowRunner, cfg.TenantID, shadowCfg.Schedule.Stations, shadowCfg.Schedule.DailyAt, logger)
+	monthClose, err := settlementapp.NewMonthCloseService(statementService, stationRepo, shadowrunReconciler(shadowRunner, clk), cfg.TenantID)
+	if err != nil {
+		logger.Fatalf("month close service error: %v", err)
+	}
+	statementHandler, err := settlementinterfaces.NewStatementHandler(statementService, stationChecker, auditRepo,
+		settlementinterfaces.WithBrandingResolver(settlementrepo.NewBrandingRepository(db)),
+		settlementinterfaces.WithMonthClose(monthClose),
+		settlementinterfaces.WithLogger(logger),
+	)
+	if err != nil {
+		logger.Fatalf("statement handler error: %v", err)
+	}
+	shadowScheduler := shadowapp.NewScheduler(shadowRunner, cfg.TenantID, shadowCfg.Schedule.Stations, shadowCfg.Schedule.DailyAt, logger,
+		shadowapp.WithConcurrency(shadowCfg.Schedule.Concurrency),
+		shadowapp.WithStationTimeout(shadowCfg.Schedule.StationTimeout),
+		shadowapp.WithSchedulerMetrics(shadowMetrics),
+	)
 	go shadowScheduler.Start(context.Background())
 
-	policy := auth.NewDefaultPolicy([]string{"/healthz", "/metrics"}, []string{"/ingest/"})
+	policy := auth.NewDefaultPolicy([]string{"/healthz", "/metrics", "/version"}, []string{"/ingest/"})
 	authMiddleware := auth.NewMiddleware([]byte(cfg.JWTSecret), policy)
 	ingestAuth := auth.NewIngestAuthMiddleware([]byte(cfg.IngestSecret), time.Duration(cfg.IngestSkewSeconds)*time.Second)
 
 	mux := http.NewServeMux()
 	mux.Handle("/ingest/thingsboard/telemetry", ingestAuth.Wrap(ingestHandler))
 	mux.Handle("/analytics/window-close", windowCloseHandler)
+	if backfillHandler, err := analyticsinterfaces.NewBackfillStatusHandler(backfillTracker, logger); err == nil {
+		mux.Handle("/analytics/backfills/", backfillHandler)
+	}
+	if recalculateHandler, err := analyticsinterfaces.NewRecalculateHourHandler(hourlyService, stationChecker, logger); err == nil {
+		mux.Handle("/api/v1/analytics/recalculate-hour", recalculateHandler)
+	}
 	mux.Handle("/api/v1/provisioning/stations", provisionHandler)
+	mux.Handle("/api/v1/provisioning/stations/bulk", bulkProvisionHandler)
+	mux.Handle("/api/v1/provisioning/stations/", readinessHandler)
 	mux.Handle("/api/v1/commands", commandHandler)
 	mux.Handle("/api/v1/strategies/", strategyHandler)
 	mux.Handle("/api/v1/shadowrun/run", shadowHandler)
 	mux.Handle("/api/v1/shadowrun/reports", shadowHandler)
 	mux.Handle("/api/v1/shadowrun/reports/", shadowHandler)
-	mux.Handle("/api/v1/stats", apihttp.NewStatsHandler(db, stationChecker))
-	mux.Handle("/api/v1/settlements", apihttp.NewSettlementsHandler(db, cfg.TenantID, stationChecker))
+	queryOpts := []apihttp.QueryOption{
+		apihttp.WithQueryTimeout(cfg.APIQueryTimeout),
+		apihttp.WithDefaultRange(cfg.APIDefaultRange),
+		apihttp.WithMaxRange("hour", cfg.APIMaxHourRange),
+		apihttp.WithMaxRange("day", cfg.APIMaxDayRange),
+		apihttp.WithFloatPrecision(cfg.APIFloatPrecision),
+		apihttp.WithQueryClock(clk),
+	}
+	mux.Handle("/api/v1/stats", apihttp.NewStatsHandler(readDB, stationChecker, queryOpts...))
+	mux.Handle("/api/v1/admin/slo", apihttp.NewSLOHandler(nil, apihttp.WithSLOClock(clk)))
+	mux.Handle("/api/v1/stations/", apihttp.NewStationSummaryHandler(readDB, stationChecker, queryOpts...))
+	settlementsHandler := apihttp.NewSettlementsHandler(readDB, cfg.TenantID, stationChecker, queryOpts...)
+	mux.Handle("/api/v1/settlements", settlementsHandler)
+	mux.Handle("/api/v1/settlements/", settlementsHandler)
 	mux.Handle("/api/v1/statements", statementHandler)
 	mux.Handle("/api/v1/statements/", statementHandler)
 	mux.Handle("/api/v1/statements/generate", statementHandler)
-	mux.Handle("/api/v1/exports/settlements.csv", apihttp.NewExportSettlementsCSVHandler(db, cfg.TenantID, stationChecker))
-	mux.Handle("/api/v1/alarms/stream", alarmhttp.NewStreamHandler(alarmBroker))
+	mux.Handle("/api/v1/telemetry", apihttp.NewTelemetryHandler(readDB, cfg.TenantID, stationChecker,
+		apihttp.WithTelemetryQueryTimeout(cfg.APIQueryTimeout),
+		apihttp.WithTelemetryFloatPrecision(cfg.APIFloatPrecision),
+	))
+	mux.Handle("/api/v1/exports/settlements.csv", apihttp.NewExportSettlementsCSVHandler(readDB, cfg.TenantID, stationChecker, queryOpts...))
+	mux.Handle("/api/v1/alarms/stream", alarmhttp.NewStreamHandler(alarmBroker, stationChecker, alarmhttp.WithHeartbeat(cfg.AlarmStreamHeartbeat)))
 	if alarmHandler, err := alarmhttp.NewHandler(alarmService, stationChecker); err == nil {
 		mux.Handle("/api/v1/alarms", alarmHandler)
 		mux.Handle("/api/v1/alarms/", alarmHandler)
 	}
+	if ruleHandler, err := alarmhttp.NewRuleHandler(alarmService, stationChecker); err == nil {
+		mux.Handle("/api/v1/alarm-rules", ruleHandler)
+		mux.Handle("/api/v1/alarm-rules/", ruleHandler)
+		mux.Handle("/api/v1/alarms/rules/", ruleHandler)
+	}
+	if templateHandler, err := alarmhttp.NewTemplateHandler(alarmService, stationChecker); err == nil {
+		mux.Handle("/api/v1/alarm-rule-templates", templateHandler)
+		mux.Handle("/api/v1/alarm-rule-templates/", templateHandler)
+	}
 	mux.Handle("/metrics", promhttp.Handler())
+	mux.Handle("/version", buildinfo.Handler())
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("ok"))
 	})
 
-	server := &http.Server{Addr: cfg.HTTPAddr, Handler: corsMiddleware(loggingMiddleware(authMiddleware.Wrap(mux), logger))}
+	rateLimits, err := ratelimit.ParseLimits(cfg.HTTPRateLimits)
+	if err != nil {
+		logger.Fatalf("http rate limits error: %v", err)
+	}
+	limiterOpts := []ratelimit.Option{ratelimit.WithExemptPaths("/healthz", "/metrics", "/version")}
+	if cfg.HTTPRateLimitTrustProxy {
+		limiterOpts = append(limiterOpts, ratelimit.WithTrustedProxy())
+	}
+	limiter := ratelimit.NewLimiter(rateLimits, limiterOpts...)
+
+	server := &http.Server{Addr: cfg.HTTPAddr, Handler: corsMiddleware(loggingMiddleware(authMiddleware.Wrap(limiter.Wrap(mux)), logger))}
 	logger.Printf("http listening on %s", cfg.HTTPAddr)
 	logger.Fatal(server.ListenAndServe())
 }
 
 type config struct {
-	DatabaseURL             string
-	HTTPAddr                string
-	TenantID                string
-	StationID               string
-	PricePerKWh             float64
-	Currency                string
-	ExpectedHours           int
-	TBBaseURL               string
-	TBToken                 string
-	AlarmWebhookURL         string
-	AlarmNotifyTemplate     string
-	AlarmEscalationAfter    time.Duration
-	AlarmNotifyCooldown     time.Duration
-	AlarmNotifyDedupeWindow time.Duration
-	AlarmNotifyTimeout      time.Duration
-	AlarmReportLookbackDays int
-	AlarmReportBaseURL      string
-	JWTSecret               string
-	IngestSecret            string
-	IngestSkewSeconds       int
-	OutboxDispatchBatch     int
-	OutboxDispatchInterval  time.Duration
+	DatabaseURL              string
+	DatabaseReplicaURL       string
+	HTTPAddr                 string
+	TenantID                 string
+	StationID                string
+	PricePerKWh              float64
+	DayEnergyCapKWh          float64
+	StationEnergyCaps        string
+	PriceAnomalies           bool
+	NegativeEnergyPolicy     string
+	MinBillableKWh           float64
+	TenantMinBillableKWh     string
+	CategoryMinBillableKWh   string
+	SettleFullDaysOnly       bool
+	CategoryPrices           string
+	SnapshotAlgorithm        string
+	Currency                 string
+	ExpectedHours            int
+	RollupCatchUpInterval    time.Duration
+	RollupCatchUpLookback    time.Duration
+	SettleCatchUpInterval    time.Duration
+	SettleCatchUpLookback    time.Duration
+	AnalyticsWindowSkew      time.Duration
+	AnalyticsListBatchSize   int
+	TBBaseURL                string
+	TBToken                  string
+	TBBreakerThreshold       int
+	TBBreakerCooldown        time.Duration
+	ProvisionCompensation    bool
+	ProvisionBulkConcurrency int
+	StrategyCommandInterval  time.Duration
+	StrategyCommandAckWait   time.Duration
+	StrategyTelemetryTTL     time.Duration
+	StrategyMappingTTL       time.Duration
+	SettlementWebhookURL     string
+	SettlementWebhookSecret  string
+	SettlementHookAttempts   int
+	SettlementHookBackoff    time.Duration
+	AlarmWebhookURL          string
+	AlarmWebhookStructured   bool
+	AlarmNotifyTemplate      string
+	AlarmEscalationAfter     time.Duration
+	AlarmEscalationChain     string
+	AlarmNotifyChannels      string
+	AlarmRetryAttempts       int
+	AlarmRetryBackoff        time.Duration
+	AlarmNotifyCooldown      time.Duration
+	AlarmNotifyDedupeWindow  time.Duration
+	AlarmNotifyTimeout       time.Duration
+	AlarmDigestInterval      time.Duration
+	AlarmDigestTemplate      string
+	AlarmNotifyQuietHours    string
+	AlarmReportLookbackDays  int
+	AlarmReportBaseURL       string
+	AlarmStaleAfter          time.Duration
+	AlarmStaleSweepInterval  time.Duration
+	AlarmEvalInterval        time.Duration
+	AlarmFlapWindow          time.Duration
+	AlarmFlapThreshold       int
+	AlarmFlapSuppress        bool
+	AlarmStreamHeartbeat     time.Duration
+	AlarmStreamClientBuffer  int
+	JWTSecret                string
+	IngestSecret             string
+	IngestSkewSeconds        int
+	IngestErrorSampleRate    float64
+	IngestErrorSampleBytes   int
+	IngestErrorSampleTTL     time.Duration
+	TelemetryRetention       time.Duration
+	TelemetryPurgeInterval   time.Duration
+	TelemetryPurgeDryRun     bool
+	TelemetryDuplicatePolicy string
+	OutboxDispatchBatch      int
+	OutboxDispatchInterval   time.Duration
+	OutboxMaxAttempts        int
+	OutboxRetryBackoff       time.Duration
+	APIQueryTimeout          time.Duration
+	APIDefaultRange          time.Duration
+	APIMaxHourRange          time.Duration
+	APIMaxDayRange           time.Duration
+	APIFloatPrecision        int
+	MetricsBuckets           string
+	MetricsLabelRefresh      time.Duration
+	HTTPRateLimits           string
+	HTTPRateLimitTrustProxy  bool
 }
 
 func loadConfig() config {
 	cfg := config{
-		DatabaseURL:             getenvDefault("DATABASE_URL", getenvDefault("PG_DSN", "")),
-		HTTPAddr:                getenvDefault("HTTP_ADDR", ":8080"),
-		TenantID:                getenvDefault("TENANT_ID", "tenant-demo"),
-		StationID:               getenvDefault("STATION_ID", "station-demo-001"),
-		PricePerKWh:             getenvFloatDefault("PRICE_PER_KWH", 1.0),
-		Currency:                getenvDefault("CURRENCY", "CNY"),
-		ExpectedHours:           getenvIntDefault("EXPECTED_HOURS", 24),
-		TBBaseURL:               getenvDefault("TB_BASE_URL", ""),
-		TBToken:                 getenvDefault("TB_TOKEN", ""),
-		AlarmWebhookURL:         getenvDefault("ALARM_WEBHOOK_URL", ""),
-		AlarmNotifyTemplate:     getenvDefault("ALARM_NOTIFY_TEMPLATE", ""),
-		AlarmEscalationAfter:    getenvDuration("ALARM_ESCALATION_AFTER", 0),
-		AlarmNotifyCooldown:     getenvDuration("ALARM_NOTIFY_COOLDOWN", 0),
-		AlarmNotifyDedupeWindow: getenvDuration("ALARM_NOTIFY_DEDUP_WINDOW", 0),
-		AlarmNotifyTimeout:      getenvDuration("ALARM_NOTIFY_TIMEOUT", 5*time.Second),
-		AlarmReportLookbackDays: getenvIntDefault("ALARM_REPORT_LOOKBACK_DAYS", 0),
-		AlarmReportBaseURL:      getenvDefault("ALARM_REPORT_BASE_URL", getenvDefault("SHADOWRUN_PUBLIC_BASE_URL", "")),
-		JWTSecret:               getenvDefault("AUTH_JWT_SECRET", getenvDefault("JWT_SECRET", "")),
-		IngestSecret:            getenvDefault("INGEST_HMAC_SECRET", ""),
-		IngestSkewSeconds:       getenvIntDefault("INGEST_MAX_SKEW_SECONDS", 300),
-		OutboxDispatchBatch:     getenvIntDefault("OUTBOX_DISPATCH_BATCH", 200),
-		OutboxDispatchInterval:  getenvDuration("OUTBOX_DISPATCH_INTERVAL", 200*time.Millisecond),
+		DatabaseURL:              getenvDefault("DATABASE_URL", getenvDefault("PG_DSN", "")),
+		DatabaseReplicaURL:       getenvDefault("DATABASE_REPLICA_URL", ""),
+		HTTPAddr:                 getenvDefault("HTTP_ADDR", ":8080"),
+		TenantID:                 getenvDefault("TENANT_ID", "tenant-demo"),
+		StationID:                getenvDefault("STATION_ID", "station-demo-001"),
+		PricePerKWh:              getenvFloatDefault("PRICE_PER_KWH", 1.0),
+		DayEnergyCapKWh:          getenvFloatDefault("SETTLEMENT_DAY_ENERGY_CAP_KWH", 0),
+		StationEnergyCaps:        getenvDefault("SETTLEMENT_DAY_ENERGY_CAP_BY_STATION", ""),
+		PriceAnomalies:           getenvBoolDefault("SETTLEMENT_PRICE_ANOMALIES", false),
+		NegativeEnergyPolicy:     getenvDefault("ENERGY_NEGATIVE_POLICY", string(energy.NegativePassThrough)),
+		MinBillableKWh:           getenvFloatDefault("SETTLEMENT_MIN_BILLABLE_KWH", 0),
+		TenantMinBillableKWh:     getenvDefault("SETTLEMENT_MIN_BILLABLE_KWH_BY_TENANT", ""),
+		CategoryMinBillableKWh:   getenvDefault("SETTLEMENT_MIN_BILLABLE_KWH_BY_CATEGORY", ""),
+		SettleFullDaysOnly:       getenvBoolDefault("SETTLEMENT_REQUIRE_ALL_HOURS", false),
+		CategoryPrices:           getenvDefault("PRICE_PER_KWH_BY_CATEGORY", ""),
+		SnapshotAlgorithm:        getenvDefault("STATEMENT_SNAPSHOT_ALGORITHM", string(settlement.DefaultSnapshotAlgorithm)),
+		Currency:                 getenvDefault("CURRENCY", "CNY"),
+		ExpectedHours:            getenvIntDefault("EXPECTED_HOURS", 24),
+		RollupCatchUpInterval:    getenvDuration("ROLLUP_CATCHUP_INTERVAL", 15*time.Minute),
+		RollupCatchUpLookback:    getenvDuration("ROLLUP_CATCHUP_LOOKBACK", 72*time.Hour),
+		SettleCatchUpInterval:    getenvDuration("SETTLEMENT_CATCHUP_INTERVAL", 15*time.Minute),
+		SettleCatchUpLookback:    getenvDuration("SETTLEMENT_CATCHUP_LOOKBACK", 72*time.Hour),
+		AnalyticsWindowSkew:      getenvDuration("ANALYTICS_FUTURE_WINDOW_SKEW", application.DefaultFutureWindowSkew),
+		AnalyticsListBatchSize:   getenvIntDefault("ANALYTICS_LIST_BATCH_SIZE", domainstatistic.DefaultListBatchSize),
+		TBBaseURL:                getenvDefault("TB_BASE_URL", ""),
+		TBToken:                  getenvDefault("TB_TOKEN", ""),
+		TBBreakerThreshold:       getenvIntDefault("TB_BREAKER_THRESHOLD", tbadapter.DefaultBreakerThreshold),
+		TBBreakerCooldown:        getenvDuration("TB_BREAKER_COOLDOWN", tbadapter.DefaultBreakerCooldown),
+		ProvisionCompensation:    getenvBoolDefault("PROVISION_COMPENSATION", true),
+		ProvisionBulkConcurrency: getenvIntDefault("PROVISION_BULK_CONCURRENCY", 4),
+		StrategyCommandInterval:  getenvDuration("STRATEGY_MIN_COMMAND_INTERVAL", strategyapp.DefaultMinCommandInterval),
+		StrategyCommandAckWait:   getenvDuration("STRATEGY_COMMAND_ACK_WAIT", strategyapp.DefaultCommandAckWait),
+		StrategyTelemetryTTL:     getenvDuration("STRATEGY_TELEMETRY_CACHE_TTL", strategytelemetry.DefaultCacheTTL),
+		StrategyMappingTTL:       getenvDuration("STRATEGY_MAPPING_CACHE_TTL", strategytelemetry.DefaultMappingCacheTTL),
+		SettlementWebhookURL:     getenvDefault("SETTLEMENT_WEBHOOK_URL", ""),
+		SettlementWebhookSecret:  getenvDefault("SETTLEMENT_WEBHOOK_SECRET", ""),
+		SettlementHookAttempts:   getenvIntDefault("SETTLEMENT_WEBHOOK_ATTEMPTS", settlementinterfaces.DefaultWebhookAttempts),
+		SettlementHookBackoff:    getenvDuration("SETTLEMENT_WEBHOOK_BACKOFF", settlementinterfaces.DefaultWebhookBackoff),
+		AlarmWebhookURL:          getenvDefault("ALARM_WEBHOOK_URL", ""),
+		AlarmWebhookStructured:   getenvBoolDefault("ALARM_WEBHOOK_STRUCTURED", false),
+		AlarmNotifyTemplate:      getenvDefault("ALARM_NOTIFY_TEMPLATE", ""),
+		AlarmEscalationAfter:     getenvDuration("ALARM_ESCALATION_AFTER", 0),
+		AlarmEscalationChain:     getenvDefault("ALARM_ESCALATION_CHAIN", ""),
+		AlarmNotifyChannels:      getenvDefault("ALARM_NOTIFY_CHANNELS", ""),
+		AlarmRetryAttempts:       getenvIntDefault("ALARM_NOTIFY_RETRY_ATTEMPTS", alarmnotify.DefaultRetryAttempts),
+		AlarmRetryBackoff:        getenvDuration("ALARM_NOTIFY_RETRY_BACKOFF", alarmnotify.DefaultRetryBackoff),
+		AlarmNotifyCooldown:      getenvDuration("ALARM_NOTIFY_COOLDOWN", 0),
+		AlarmNotifyDedupeWindow:  getenvDuration("ALARM_NOTIFY_DEDUP_WINDOW", 0),
+		AlarmNotifyTimeout:       getenvDuration("ALARM_NOTIFY_TIMEOUT", 5*time.Second),
+		AlarmDigestInterval:      getenvDuration("ALARM_NOTIFY_DIGEST_INTERVAL", 0),
+		AlarmDigestTemplate:      getenvDefault("ALARM_NOTIFY_DIGEST_TEMPLATE", ""),
+		AlarmNotifyQuietHours:    getenvDefault("ALARM_NOTIFY_QUIET_HOURS", ""),
+		AlarmReportLookbackDays:  getenvIntDefault("ALARM_REPORT_LOOKBACK_DAYS", 0),
+		AlarmReportBaseURL:       getenvDefault("ALARM_REPORT_BASE_URL", getenvDefault("SHADOWRUN_PUBLIC_BASE_URL", "")),
+		AlarmStaleAfter:          getenvDuration("ALARM_STALE_AFTER", 0),
+		AlarmStaleSweepInterval:  getenvDuration("ALARM_STALE_SWEEP_INTERVAL", time.Minute),
+		AlarmEvalInterval:        getenvDuration("ALARM_EVALUATION_INTERVAL", 0),
+		AlarmFlapWindow:          getenvDuration("ALARM_FLAP_WINDOW", 0),
+		AlarmFlapThreshold:       getenvIntDefault("ALARM_FLAP_THRESHOLD", 5),
+		AlarmFlapSuppress:        getenvBoolDefault("ALARM_FLAP_SUPPRESS", false),
+		AlarmStreamHeartbeat:     getenvDuration("ALARM_STREAM_HEARTBEAT", 15*time.Second),
+		AlarmStreamClientBuffer:  getenvIntDefault("ALARM_STREAM_CLIENT_BUFFER", 16),
+		JWTSecret:                getenvDefault("AUTH_JWT_SECRET", getenvDefault("JWT_SECRET", "")),
+		IngestSecret:             getenvDefault("INGEST_HMAC_SECRET", ""),
+		IngestSkewSeconds:        getenvIntDefault("INGEST_MAX_SKEW_SECONDS", 300),
+		IngestErrorSampleRate:    getenvFloatDefault("INGEST_ERROR_SAMPLE_RATE", 0),
+		IngestErrorSampleBytes:   getenvIntDefault("INGEST_ERROR_SAMPLE_MAX_BYTES", thingsboard.DefaultErrorSampleMaxBytes),
+		IngestErrorSampleTTL:     getenvDuration("INGEST_ERROR_SAMPLE_RETENTION", 7*24*time.Hour),
+		TelemetryRetention:       getenvDuration("TELEMETRY_RETENTION", 0),
+		TelemetryPurgeInterval:   getenvDuration("TELEMETRY_PURGE_INTERVAL", time.Hour),
+		TelemetryPurgeDryRun:     getenvBoolDefault("TELEMETRY_PURGE_DRY_RUN", false),
+		TelemetryDuplicatePolicy: getenvDefault("TELEMETRY_DUPLICATE_POLICY", string(telemetrydomain.DuplicateLast)),
+		OutboxDispatchBatch:      getenvIntDefault("OUTBOX_DISPATCH_BATCH", 200),
+		OutboxDispatchInterval:   getenvDuration("OUTBOX_DISPATCH_INTERVAL", 200*time.Millisecond),
+		OutboxMaxAttempts:        getenvIntDefault("OUTBOX_MAX_ATTEMPTS", 5),
+		OutboxRetryBackoff:       getenvDuration("OUTBOX_RETRY_BACKOFF", 5*time.Second),
+		APIQueryTimeout:          getenvDuration("API_QUERY_TIMEOUT", apihttp.DefaultQueryTimeout),
+		APIDefaultRange:          getenvDuration("API_DEFAULT_RANGE", 24*time.Hour),
+		APIMaxHourRange:          getenvDuration("API_MAX_RANGE_HOUR", apihttp.DefaultMaxHourRange),
+		APIMaxDayRange:           getenvDuration("API_MAX_RANGE_DAY", apihttp.DefaultMaxDayRange),
+		APIFloatPrecision:        getenvIntDefault("API_FLOAT_PRECISION", apihttp.DefaultFloatPrecision),
+		MetricsBuckets:           getenvDefault("METRICS_HISTOGRAM_BUCKETS", ""),
+		MetricsLabelRefresh:      getenvDuration("METRICS_LABEL_REFRESH_INTERVAL", 5*time.Minute),
+		HTTPRateLimits:           getenvDefault("HTTP_RATE_LIMITS", ratelimit.DefaultLimits),
+		HTTPRateLimitTrustProxy:  getenvBoolDefault("HTTP_RATE_LIMIT_TRUST_PROXY", false),
 	}
 	if cfg.DatabaseURL == "" {
 		log.Fatal("DATABASE_URL or PG_DSN is required")
@@ -446,6 +959,18 @@ func getenvIntDefault(key string, fallback int) int {
 	return parsed
 }
 
+func getenvBoolDefault(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fallback
+	}
+	return parsed
+}
+
 func getenvDuration(key string, fallback time.Duration) time.Duration {
 	value := os.Getenv(key)
 	if value == "" {
@@ -458,7 +983,7 @@ func getenvDuration(key string, fallback time.Duration) time.Duration {
 	return parsed
 }
 
-func buildShadowrunReportResolver(repo *shadowrepo.Repository, baseURL string, lookbackDays int) alarmnotify.ReportURLResolver {
+func buildShadowrunReportResolver(repo *shadowrepo.Repository, baseURL string, lookbackDays int, clk clock.Clock) alarmnotify.ReportURLResolver {
 	if repo == nil || baseURL == "" || lookbackDays <= 0 {
 		return nil
 	}
@@ -467,9 +992,10 @@ func buildShadowrunReportResolver(repo *shadowrepo.Repository, baseURL string, l
 		if alarm.StationID == "" {
 			return ""
 		}
-		from := time.Now().UTC().AddDate(0, 0, -lookbackDays)
-		to := time.Now().UTC().AddDate(0, 0, 1)
-		reports, err := repo.ListReports(ctx, alarm.StationID, from, to)
+		now := clk.Now().UTC()
+		from := now.AddDate(0, 0, -lookbackDays)
+		to := now.AddDate(0, 0, 1)
+		reports, _, err := repo.ListReportsPage(ctx, alarm.StationID, from, to, 1, 0)
 		if err != nil || len(reports) == 0 {
 			return ""
 		}
@@ -477,6 +1003,25 @@ func buildShadowrunReportResolver(repo *shadowrepo.Repository, baseURL string, l
 	}
 }
 
+// shadowrunReconciler reconciles a month close station with a shadowrun job;
+// the month is within thresholds when the report recommends no action.
+func shadowrunReconciler(runner *shadowapp.Runner, clk clock.Clock) settlementapp.MonthReconcilerFunc {
+	return func(ctx context.Context, tenantID, stationID string, month time.Time) (settlementapp.ReconcileOutcome, error) {
+		report, err := runner.Rerun(ctx, tenantID, stationID, month, clk.Now().UTC(), nil)
+		if err != nil {
+			return settlementapp.ReconcileOutcome{}, err
+		}
+		if report == nil {
+			return settlementapp.ReconcileOutcome{}, errors.New("shadowrun report not found")
+		}
+		return settlementapp.ReconcileOutcome{
+			WithinThresholds: report.RecommendedAction == shadowapp.ActionNone,
+			ReportID:         report.ID,
+			Action:           report.RecommendedAction,
+		}, nil
+	}
+}
+
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		origin := r.Header.Get("Origin")
@@ -484,6 +1029,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Expose-Headers", pagination.ExposedHeaders)
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
 		if r.Method == "OPTIONS" {
@@ -515,10 +1061,6 @@ func (w *statusWriter) WriteHeader(status int) {
 
 // ---- Adapters ----
 
-type systemClock struct{}
-
-func (systemClock) Now() time.Time { return time.Now().UTC() }
-
 type hourStatisticIDFactory struct{}
 
 func (hourStatisticIDFactory) HourID(stationID string, hourStart time.Time) (domainstatistic.StatisticID, error) {
@@ -0,0 +1,10 @@
+-- 017_alarm_last_sample.sql
+
+ALTER TABLE alarms
+  ADD COLUMN IF NOT EXISTS last_sample_at TIMESTAMPTZ;
+
+UPDATE alarms SET last_sample_at = updated_at WHERE last_sample_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_alarms_open_last_sample
+  ON alarms (last_sample_at)
+  WHERE status IN ('active', 'acknowledged');
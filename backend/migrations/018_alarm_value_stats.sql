@@ -0,0 +1,11 @@
+-- 018_alarm_value_stats.sql
+
+ALTER TABLE alarms
+  ADD COLUMN IF NOT EXISTS min_value DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS max_value DOUBLE PRECISION,
+  ADD COLUMN IF NOT EXISTS value_sum DOUBLE PRECISION NOT NULL DEFAULT 0,
+  ADD COLUMN IF NOT EXISTS sample_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE alarms
+SET min_value = last_value, max_value = last_value, value_sum = COALESCE(last_value, 0), sample_count = 1
+WHERE sample_count = 0 AND last_value IS NOT NULL;
@@ -0,0 +1,10 @@
+-- 019_statistic_present_hours.sql
+
+-- Number of hour statistics summed into a DAY row. A DAY row with
+-- is_completed = FALSE is a partial day that is upgraded once all hours arrive.
+ALTER TABLE analytics_statistics
+  ADD COLUMN IF NOT EXISTS present_hours INTEGER;
+
+UPDATE analytics_statistics
+SET present_hours = 24
+WHERE time_type = 'DAY' AND is_completed AND present_hours IS NULL;
@@ -0,0 +1,24 @@
+-- 020_statistics_history.sql
+
+-- Append-only record of recalculated statistics: one row each time a
+-- completed analytics_statistics row is overwritten.
+CREATE TABLE IF NOT EXISTS analytics_statistics_history (
+	id BIGSERIAL PRIMARY KEY,
+	subject_id TEXT NOT NULL,
+	time_type TEXT NOT NULL,
+	time_key TEXT NOT NULL,
+	statistic_id TEXT NOT NULL,
+	before_charge_kwh DOUBLE PRECISION NOT NULL,
+	before_discharge_kwh DOUBLE PRECISION NOT NULL,
+	before_earnings DOUBLE PRECISION NOT NULL,
+	before_carbon_reduction DOUBLE PRECISION NOT NULL,
+	after_charge_kwh DOUBLE PRECISION NOT NULL,
+	after_discharge_kwh DOUBLE PRECISION NOT NULL,
+	after_earnings DOUBLE PRECISION NOT NULL,
+	after_carbon_reduction DOUBLE PRECISION NOT NULL,
+	trigger_event TEXT NOT NULL,
+	recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_statistics_history_key
+	ON analytics_statistics_history (subject_id, time_type, time_key, recorded_at);
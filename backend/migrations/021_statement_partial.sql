@@ -0,0 +1,7 @@
+-- 021_statement_partial.sql
+
+-- Statements generated while some days of the month had no settlement are
+-- flagged partial with the missing days (YYYY-MM-DD) listed.
+ALTER TABLE settlement_statements
+	ADD COLUMN IF NOT EXISTS partial BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS missing_days JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,11 @@
+-- 022_statement_branding.sql
+
+-- Per-tenant branding printed on statement exports. Tenants without a row get
+-- the default unbranded layout. logo_path is a PNG/JPEG file readable by the API.
+CREATE TABLE IF NOT EXISTS statement_branding (
+	tenant_id TEXT PRIMARY KEY,
+	company_name TEXT NOT NULL DEFAULT '',
+	logo_path TEXT NOT NULL DEFAULT '',
+	footer_text TEXT NOT NULL DEFAULT '',
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,6 @@
+-- 023_outbox_retry.sql
+
+-- Failed deliveries are re-queued as pending until next_attempt_at; NULL means
+-- the row can be claimed immediately.
+ALTER TABLE event_outbox
+	ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
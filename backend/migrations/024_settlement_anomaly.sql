@@ -0,0 +1,6 @@
+-- 024_settlement_anomaly.sql
+
+-- Day settlements with implausible energy (negative, or above the station cap)
+-- are kept and flagged with the reason; NULL means the day is plausible.
+ALTER TABLE settlements_day
+	ADD COLUMN IF NOT EXISTS anomaly TEXT;
@@ -0,0 +1,9 @@
+-- 025_tenant_settings.sql
+
+-- Per-tenant settings; a NULL currency falls back to the deployment CURRENCY.
+CREATE TABLE IF NOT EXISTS tenant_settings (
+	tenant_id TEXT PRIMARY KEY,
+	currency TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
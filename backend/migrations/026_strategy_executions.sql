@@ -0,0 +1,19 @@
+-- 026_strategy_executions.sql
+
+-- One row per engine evaluation, including skipped ones, with the telemetry
+-- the decision was based on.
+CREATE TABLE IF NOT EXISTS strategy_executions (
+	id BIGSERIAL PRIMARY KEY,
+	strategy_id TEXT NOT NULL REFERENCES strategies(station_id),
+	ts TIMESTAMPTZ NOT NULL,
+	template_id TEXT,
+	inputs JSONB,
+	decision JSONB,
+	outcome TEXT NOT NULL,
+	reason TEXT,
+	command_id TEXT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_strategy_executions_strategy
+	ON strategy_executions (strategy_id, id DESC);
@@ -0,0 +1,5 @@
+-- 027_commands_device_index.sql
+
+-- The strategy engine looks up the latest command per device before issuing.
+CREATE INDEX IF NOT EXISTS idx_commands_device_time
+	ON commands (tenant_id, device_id, created_at DESC);
@@ -0,0 +1,4 @@
+-- 028_alarm_rule_notify_channel.sql
+
+ALTER TABLE alarm_rules
+  ADD COLUMN IF NOT EXISTS notify_channel TEXT NOT NULL DEFAULT '';
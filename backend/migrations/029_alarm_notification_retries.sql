@@ -0,0 +1,19 @@
+-- 029_alarm_notification_retries.sql
+
+CREATE TABLE IF NOT EXISTS alarm_notification_retries (
+  id BIGSERIAL PRIMARY KEY,
+  alarm_id TEXT NOT NULL,
+  channel TEXT NOT NULL,
+  content TEXT NOT NULL,
+  fields JSONB,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  status TEXT NOT NULL DEFAULT 'pending',
+  next_attempt_at TIMESTAMPTZ NOT NULL,
+  last_error TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS alarm_notification_retries_due_idx
+  ON alarm_notification_retries (next_attempt_at)
+  WHERE status = 'pending';
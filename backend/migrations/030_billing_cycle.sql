@@ -0,0 +1,12 @@
+-- 030_billing_cycle.sql
+
+-- Tenants billing on a cycle (e.g. the 26th to the 25th) set the cycle start
+-- day; NULL bills calendar months. Statements record the period they cover,
+-- NULL for statements generated before cycles, which cover their calendar month.
+ALTER TABLE tenant_settings
+	ADD COLUMN IF NOT EXISTS billing_cycle_day SMALLINT
+		CHECK (billing_cycle_day BETWEEN 1 AND 28);
+
+ALTER TABLE settlement_statements
+	ADD COLUMN IF NOT EXISTS period_start DATE,
+	ADD COLUMN IF NOT EXISTS period_end DATE;
@@ -0,0 +1,19 @@
+-- 031_telemetry_compacted.sql
+
+-- Downsampled raw telemetry written by tools/telemetry_compact before the raw
+-- rows are deleted. One row per station point and bucket; resolution_seconds
+-- is the bucket width the row was compacted at.
+CREATE TABLE IF NOT EXISTS telemetry_points_compacted (
+	tenant_id TEXT NOT NULL,
+	station_id TEXT NOT NULL,
+	point_key TEXT NOT NULL,
+	bucket_start TIMESTAMPTZ NOT NULL,
+	resolution_seconds INTEGER NOT NULL,
+	avg_value DOUBLE PRECISION NOT NULL,
+	min_value DOUBLE PRECISION NOT NULL,
+	max_value DOUBLE PRECISION NOT NULL,
+	last_value DOUBLE PRECISION NOT NULL,
+	sample_count INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (tenant_id, station_id, point_key, bucket_start)
+);
@@ -0,0 +1,7 @@
+-- 032_point_mapping_aggregation.sql
+
+-- How the points of one telemetry batch that map to the same semantic are
+-- folded: sum (the previous behaviour), last, max or avg.
+ALTER TABLE point_mappings
+  ADD COLUMN IF NOT EXISTS aggregation TEXT NOT NULL DEFAULT 'sum'
+    CHECK (aggregation IN ('sum', 'last', 'max', 'avg'));
@@ -0,0 +1,23 @@
+-- 033_alarm_rule_templates.sql
+
+-- Alarm rules shared by the stations of one station type. Applying a template
+-- to a station inserts an ordinary alarm_rules row with a stable id derived
+-- from the template and station, so re-applying never duplicates rules.
+CREATE TABLE IF NOT EXISTS alarm_rule_templates (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL,
+	station_type TEXT NOT NULL,
+	name TEXT NOT NULL,
+	semantic TEXT NOT NULL,
+	operator TEXT NOT NULL,
+	threshold DOUBLE PRECISION NOT NULL,
+	hysteresis DOUBLE PRECISION NOT NULL DEFAULT 0,
+	duration_seconds INTEGER NOT NULL DEFAULT 0,
+	severity TEXT NOT NULL DEFAULT 'medium',
+	notify_channel TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_alarm_rule_templates_type
+	ON alarm_rule_templates (tenant_id, station_type);
@@ -0,0 +1,25 @@
+-- 034_backfill_jobs.sql
+
+-- One row per hour recalculated through /analytics/window-close. The status
+-- advances as the hour, day and settlement stages of the cascade report the
+-- recalculation, so operators can tell when a backfill has settled.
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+	id TEXT PRIMARY KEY,
+	tenant_id TEXT NOT NULL DEFAULT '',
+	station_id TEXT NOT NULL,
+	hour_start TIMESTAMPTZ NOT NULL,
+	day_start TIMESTAMPTZ NOT NULL,
+	status TEXT NOT NULL DEFAULT 'pending'
+		CHECK (status IN ('pending', 'hour_done', 'day_done', 'completed')),
+	hour_at TIMESTAMPTZ,
+	day_at TIMESTAMPTZ,
+	settlement_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_backfill_jobs_open_hour
+	ON backfill_jobs (station_id, hour_start) WHERE status <> 'completed';
+
+CREATE INDEX IF NOT EXISTS idx_backfill_jobs_open_day
+	ON backfill_jobs (station_id, day_start) WHERE status <> 'completed';
@@ -0,0 +1,18 @@
+-- 035_tariff_overrides.sql
+
+-- Flat price pinned by an analyst for one station and month when the tariff
+-- plan of that month is known to be wrong. Shadowrun reconciliation prices the
+-- month with it before looking at tariff_plans; production settlement does
+-- not read this table.
+CREATE TABLE IF NOT EXISTS tariff_overrides (
+	tenant_id TEXT NOT NULL,
+	station_id TEXT NOT NULL,
+	month DATE NOT NULL,
+	price_per_kwh DOUBLE PRECISION NOT NULL CHECK (price_per_kwh >= 0),
+	currency TEXT NOT NULL DEFAULT 'CNY',
+	reason TEXT NOT NULL DEFAULT '',
+	created_by TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (tenant_id, station_id, month)
+);
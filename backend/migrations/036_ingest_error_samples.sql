@@ -0,0 +1,20 @@
+-- 036_ingest_error_samples.sql
+
+-- A sample of failing ingest requests, kept so operators can inspect what was
+-- malformed. Payloads are stored redacted and cut to a configured size; rows
+-- are purged after the configured retention.
+CREATE TABLE IF NOT EXISTS ingest_error_samples (
+	id BIGSERIAL PRIMARY KEY,
+	reason TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	tenant_id TEXT NOT NULL DEFAULT '',
+	station_id TEXT NOT NULL DEFAULT '',
+	device_id TEXT NOT NULL DEFAULT '',
+	payload TEXT NOT NULL DEFAULT '',
+	payload_bytes INT NOT NULL DEFAULT 0,
+	truncated BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ingest_error_samples_created
+	ON ingest_error_samples (created_at);
@@ -0,0 +1,9 @@
+-- 037_telemetry_compacted_resolution_key.sql
+
+-- Compaction runs at different resolutions can produce buckets with the same
+-- start. Keying rows by resolution_seconds as well keeps each run's buckets
+-- instead of overwriting a bucket with one of a different width.
+ALTER TABLE telemetry_points_compacted DROP CONSTRAINT IF EXISTS telemetry_points_compacted_pkey;
+ALTER TABLE telemetry_points_compacted
+	ADD CONSTRAINT telemetry_points_compacted_pkey
+	PRIMARY KEY (tenant_id, station_id, point_key, resolution_seconds, bucket_start);
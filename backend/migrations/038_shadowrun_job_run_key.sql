@@ -0,0 +1,10 @@
+-- 038_shadowrun_job_run_key.sql
+
+-- Month close reconciles a station again even when a report for the same day
+-- already exists. Each forced run carries its own run_key so it gets a fresh
+-- job instead of the day's earlier one; scheduled runs keep run_key = ''.
+ALTER TABLE shadowrun_jobs ADD COLUMN IF NOT EXISTS run_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE shadowrun_jobs DROP CONSTRAINT IF EXISTS shadowrun_jobs_tenant_id_station_id_month_job_date_job_type_key;
+ALTER TABLE shadowrun_jobs
+	ADD CONSTRAINT shadowrun_jobs_run_key_unique
+	UNIQUE (tenant_id, station_id, month, job_date, job_type, run_key);
@@ -74,7 +74,9 @@ func main() {
 	mux.HandleFunc("/metrics", srv.handleMetrics)
 	mux.HandleFunc("/api/tenant", srv.handleTenant)
 	mux.HandleFunc("/api/asset", srv.handleAsset)
+	mux.HandleFunc("/api/asset/", srv.handleDeleteEntity)
 	mux.HandleFunc("/api/device", srv.handleDevice)
+	mux.HandleFunc("/api/device/", srv.handleDeleteEntity)
 	mux.HandleFunc("/api/entitiesQuery/find", srv.handleEntitiesQuery)
 	mux.HandleFunc("/api/plugins/telemetry/", srv.handleAttributes)
 	mux.HandleFunc("/api/relation", srv.handleRelation)
@@ -275,6 +277,35 @@ func (s *fakeTBServer) handleDevice(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *fakeTBServer) handleDeleteEntity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/api/asset/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/asset/")
+		if _, ok := s.assets[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(s.assets, id)
+	case strings.HasPrefix(r.URL.Path, "/api/device/"):
+		id := strings.TrimPrefix(r.URL.Path, "/api/device/")
+		if _, ok := s.devices[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		delete(s.devices, id)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 func (s *fakeTBServer) handleEntitiesQuery(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.NotFound(w, r)
@@ -331,6 +362,10 @@ func (s *fakeTBServer) handleEntitiesQuery(w http.ResponseWriter, r *http.Reques
 }
 
 func (s *fakeTBServer) handleAttributes(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.handleGetAttributes(w, r)
+		return
+	}
 	if r.Method != http.MethodPost {
 		http.NotFound(w, r)
 		return
@@ -377,6 +412,40 @@ func (s *fakeTBServer) handleAttributes(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 }
 
+func (s *fakeTBServer) handleGetAttributes(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/plugins/telemetry/")
+	parts := strings.Split(path, "/")
+	if len(parts) < 5 || parts[2] != "values" || parts[3] != "attributes" {
+		http.NotFound(w, r)
+		return
+	}
+	entity := s.lookupEntity(strings.ToUpper(parts[0]), parts[1])
+	if entity == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.mu.Lock()
+	items := make([]map[string]any, 0, len(entity.Attrs))
+	for key, value := range entity.Attrs {
+		items = append(items, map[string]any{"key": key, "value": value})
+	}
+	s.mu.Unlock()
+	writeJSON(w, items)
+}
+
+func (s *fakeTBServer) lookupEntity(entityType, entityID string) *tbEntity {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch entityType {
+	case "ASSET":
+		return s.assets[entityID]
+	case "DEVICE":
+		return s.devices[entityID]
+	default:
+		return nil
+	}
+}
+
 func (s *fakeTBServer) handleRelation(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.NotFound(w, r)
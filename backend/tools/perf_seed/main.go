@@ -11,10 +11,13 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"microgrid-cloud/internal/calendar"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -38,8 +41,50 @@ type config struct {
 	statementMonth     string
 	statementCategory  string
 	statementIDsOut    string
+	statementIDsFormat string
+	verify             bool
+	resume             bool
+	profile            string
+	seedTariff         string
+	tariffPrice        float64
+	tariffTOU          string
+}
+
+const (
+	tariffModeNone  = "none"
+	tariffModeFixed = "fixed"
+	tariffModeTOU   = "tou"
+
+	// defaultTOUSpec mirrors the valley/peak/flat split used by the m3 tariff tests.
+	defaultTOUSpec = "0-480:0.5,480-1080:1.5,1080-1440:0.8"
+)
+
+// skipFunc reports whether a station already holds all rows for the range.
+type skipFunc func(ctx context.Context, stationID string) (bool, error)
+
+// stationCounter returns per-key row counts of one station, keyed like the
+// expected map passed to newResumeSkip.
+type stationCounter func(ctx context.Context, stationID string) (map[string]int, error)
+
+// tariffRule is one price window of a tariff plan, in minutes of the day.
+type tariffRule struct {
+	ID          string
+	StartMinute int
+	EndMinute   int
+	Price       float64
 }
 
+// statementRef links a generated statement to its station.
+type statementRef struct {
+	StationID   string `json:"station_id"`
+	StatementID string `json:"statement_id"`
+}
+
+const (
+	statementIDsFormatLines = "lines"
+	statementIDsFormatJSON  = "json"
+)
+
 func main() {
 	cfg := parseConfig()
 	if cfg.dsn == "" {
@@ -51,6 +96,14 @@ func main() {
 	if cfg.days <= 0 {
 		log.Fatal("days must be > 0")
 	}
+	if cfg.statementIDsFormat != statementIDsFormatLines && cfg.statementIDsFormat != statementIDsFormatJSON {
+		log.Fatal("statement-ids-format must be lines or json")
+	}
+
+	profile, err := newValueProfile(cfg.profile)
+	if err != nil {
+		log.Fatalf("invalid profile: %v", err)
+	}
 
 	start, err := parseStartDate(cfg.startDate)
 	if err != nil {
@@ -59,6 +112,15 @@ func main() {
 	if cfg.statementMonth == "" {
 		cfg.statementMonth = start.Format("2006-01")
 	}
+	var tariffMonth time.Time
+	if cfg.seedTariff != tariffModeNone {
+		if tariffMonth, err = calendar.ParseMonth(cfg.statementMonth); err != nil {
+			log.Fatalf("invalid statement-month: %v", err)
+		}
+		if _, err := buildTariffRules("check", cfg.seedTariff, cfg.tariffPrice, cfg.tariffTOU); err != nil {
+			log.Fatalf("invalid tariff: %v", err)
+		}
+	}
 
 	stationIDs := buildStationIDs(cfg.stationPrefix, cfg.stationCount)
 
@@ -70,31 +132,63 @@ func main() {
 
 	ctx := context.Background()
 
+	end := start.AddDate(0, 0, cfg.days)
 	if cfg.seedHourly || cfg.seedDaily {
-		log.Printf("seeding analytics_statistics: stations=%d days=%d hourly=%v daily=%v", cfg.stationCount, cfg.days, cfg.seedHourly, cfg.seedDaily)
-		if err := seedAnalytics(ctx, db, stationIDs, start, cfg.days, cfg.seedHourly, cfg.seedDaily); err != nil {
+		log.Printf("seeding analytics_statistics: stations=%d days=%d hourly=%v daily=%v resume=%v profile=%s", cfg.stationCount, cfg.days, cfg.seedHourly, cfg.seedDaily, cfg.resume, cfg.profile)
+		var skip skipFunc
+		if cfg.resume {
+			skip = newResumeSkip(func(ctx context.Context, stationID string) (map[string]int, error) {
+				return countAnalytics(ctx, db, []string{stationID}, start, end)
+			}, func(stationID string) map[string]int {
+				return expectedAnalyticsCounts([]string{stationID}, cfg.days, cfg.seedHourly, cfg.seedDaily)
+			})
+		}
+		if err := seedAnalytics(ctx, db, stationIDs, start, cfg.days, cfg.seedHourly, cfg.seedDaily, profile, skip); err != nil {
 			log.Fatalf("seed analytics: %v", err)
 		}
 	}
 
 	if cfg.seedSettlements {
-		log.Printf("seeding settlements_day: stations=%d days=%d tenant=%s", cfg.stationCount, cfg.days, cfg.tenantID)
-		if err := seedSettlements(ctx, db, stationIDs, cfg.tenantID, start, cfg.days); err != nil {
+		log.Printf("seeding settlements_day: stations=%d days=%d tenant=%s resume=%v", cfg.stationCount, cfg.days, cfg.tenantID, cfg.resume)
+		var skip skipFunc
+		if cfg.resume {
+			skip = newResumeSkip(func(ctx context.Context, stationID string) (map[string]int, error) {
+				return countSettlements(ctx, db, cfg.tenantID, []string{stationID}, start, end)
+			}, func(stationID string) map[string]int {
+				return expectedSettlementCounts([]string{stationID}, cfg.days)
+			})
+		}
+		if err := seedSettlements(ctx, db, stationIDs, cfg.tenantID, start, cfg.days, skip); err != nil {
 			log.Fatalf("seed settlements: %v", err)
 		}
 	}
 
+	if cfg.seedTariff != tariffModeNone {
+		log.Printf("seeding tariff plans: stations=%d month=%s mode=%s", cfg.stationCount, cfg.statementMonth, cfg.seedTariff)
+		if err := seedTariffPlans(ctx, db, stationIDs, cfg.tenantID, tariffMonth, cfg.seedTariff, cfg.tariffPrice, cfg.tariffTOU); err != nil {
+			log.Fatalf("seed tariff plans: %v", err)
+		}
+	}
+
+	if cfg.verify {
+		log.Printf("verifying seeded row counts")
+		if err := verifySeed(ctx, db, cfg, stationIDs, start); err != nil {
+			log.Fatalf("verify: %v", err)
+		}
+		log.Printf("verify ok")
+	}
+
 	if cfg.generateStatements {
 		if cfg.baseURL == "" {
 			log.Fatal("base-url is required when generate-statements is enabled")
 		}
 		log.Printf("generating statements: month=%s category=%s stations=%d", cfg.statementMonth, cfg.statementCategory, cfg.stationCount)
-		ids, err := generateStatements(ctx, cfg.baseURL, stationIDs, cfg.statementMonth, cfg.statementCategory)
+		refs, err := generateStatements(ctx, cfg.baseURL, stationIDs, cfg.statementMonth, cfg.statementCategory)
 		if err != nil {
 			log.Fatalf("generate statements: %v", err)
 		}
 		if cfg.statementIDsOut != "" {
-			if err := writeLines(cfg.statementIDsOut, ids); err != nil {
+			if err := writeStatementIDs(cfg.statementIDsOut, cfg.statementIDsFormat, refs); err != nil {
 				log.Fatalf("write statement ids: %v", err)
 			}
 			log.Printf("statement ids written to %s", cfg.statementIDsOut)
@@ -120,6 +214,13 @@ func parseConfig() config {
 	flag.StringVar(&cfg.statementMonth, "statement-month", envOrDefault("STATEMENT_MONTH", ""), "statement month (YYYY-MM)")
 	flag.StringVar(&cfg.statementCategory, "statement-category", envOrDefault("STATEMENT_CATEGORY", "owner"), "statement category")
 	flag.StringVar(&cfg.statementIDsOut, "statement-ids-out", envOrDefault("STATEMENT_IDS_OUT", ""), "output file for statement IDs")
+	flag.StringVar(&cfg.statementIDsFormat, "statement-ids-format", envOrDefault("STATEMENT_IDS_FORMAT", statementIDsFormatLines), "statement IDs file format: lines|json")
+	flag.StringVar(&cfg.profile, "profile", envOrDefault("PROFILE", profileRamp), "charge/discharge value profile: ramp|sinusoidal|bursty")
+	flag.BoolVar(&cfg.resume, "resume", envOrBool("RESUME", false), "skip stations whose rows for the range are already complete")
+	flag.StringVar(&cfg.seedTariff, "seed-tariff", envOrDefault("SEED_TARIFF", tariffModeNone), "seed tariff plans for the statement month: none|fixed|tou")
+	flag.Float64Var(&cfg.tariffPrice, "tariff-price", envOrFloat("TARIFF_PRICE", 1.0), "price per kWh for fixed tariff plans")
+	flag.StringVar(&cfg.tariffTOU, "tariff-tou", envOrDefault("TARIFF_TOU", defaultTOUSpec), "TOU windows as start-end:price (minutes of day), covering 0-1440")
+	flag.BoolVar(&cfg.verify, "verify", envOrBool("VERIFY", false), "verify seeded row counts after seeding")
 	flag.Parse()
 	return cfg
 }
@@ -151,7 +252,8 @@ func buildStationIDs(prefix string, count int) []string {
 	return list
 }
 
-func seedAnalytics(ctx context.Context, db *sql.DB, stations []string, start time.Time, days int, hourly bool, daily bool) error {
+func seedAnalytics(ctx context.Context, db *sql.DB, stations []string, start time.Time, days int, hourly bool, daily bool, profile valueProfile, skip skipFunc) error {
+	const table = "analytics"
 	const insertSQL = `
 INSERT INTO analytics_statistics (
 	subject_id,
@@ -184,6 +286,14 @@ DO UPDATE SET
 
 	now := time.Now().UTC()
 	for idx, stationID := range stations {
+		skipped, err := shouldSkip(ctx, skip, stationID)
+		if err != nil {
+			return err
+		}
+		if skipped {
+			log.Printf("skipping %s station %s (%d/%d): already seeded", table, stationID, idx+1, len(stations))
+			continue
+		}
 		tx, err := db.BeginTx(ctx, nil)
 		if err != nil {
 			return err
@@ -194,12 +304,10 @@ DO UPDATE SET
 			return err
 		}
 
-		base := float64((idx % 10) + 1)
 		for day := 0; day < days; day++ {
 			dayStart := start.AddDate(0, 0, day)
 			if daily {
-				charge := base*10 + float64(day+1)
-				discharge := base*5 + float64(day%7)
+				charge, discharge := profile.daily(idx, day)
 				earnings := charge * 0.12
 				carbon := charge * 0.02
 				timeKey := dayStart.UTC().Format(timeKeyDayLayout)
@@ -229,8 +337,7 @@ DO UPDATE SET
 			if hourly {
 				for hour := 0; hour < 24; hour++ {
 					periodStart := dayStart.Add(time.Duration(hour) * time.Hour).UTC()
-					charge := base + float64(hour+1)
-					discharge := base/2 + float64(hour%6)
+					charge, discharge := profile.hourly(idx, day, hour)
 					earnings := charge * 0.08
 					carbon := charge * 0.01
 					timeKey := periodStart.Format(timeKeyHourLayout)
@@ -271,7 +378,8 @@ DO UPDATE SET
 	return nil
 }
 
-func seedSettlements(ctx context.Context, db *sql.DB, stations []string, tenantID string, start time.Time, days int) error {
+func seedSettlements(ctx context.Context, db *sql.DB, stations []string, tenantID string, start time.Time, days int, skip skipFunc) error {
+	const table = "settlements"
 	const insertSQL = `
 INSERT INTO settlements_day (
 	tenant_id,
@@ -298,6 +406,14 @@ DO UPDATE SET
 
 	now := time.Now().UTC()
 	for idx, stationID := range stations {
+		skipped, err := shouldSkip(ctx, skip, stationID)
+		if err != nil {
+			return err
+		}
+		if skipped {
+			log.Printf("skipping %s station %s (%d/%d): already seeded", table, stationID, idx+1, len(stations))
+			continue
+		}
 		tx, err := db.BeginTx(ctx, nil)
 		if err != nil {
 			return err
@@ -342,13 +458,230 @@ DO UPDATE SET
 	return nil
 }
 
-func generateStatements(ctx context.Context, baseURL string, stations []string, month string, category string) ([]string, error) {
+// newResumeSkip builds a skipFunc that treats a station as done when its
+// current counts match the expected counts exactly.
+func newResumeSkip(count stationCounter, expected func(stationID string) map[string]int) skipFunc {
+	return func(ctx context.Context, stationID string) (bool, error) {
+		actual, err := count(ctx, stationID)
+		if err != nil {
+			return false, err
+		}
+		return len(compareCounts("", expected(stationID), actual)) == 0, nil
+	}
+}
+
+func shouldSkip(ctx context.Context, skip skipFunc, stationID string) (bool, error) {
+	if skip == nil {
+		return false, nil
+	}
+	return skip(ctx, stationID)
+}
+
+// seedTariffPlans upserts one tariff plan per station for month and replaces
+// its rules, so re-running with another mode does not leave stale windows.
+func seedTariffPlans(ctx context.Context, db *sql.DB, stations []string, tenantID string, month time.Time, mode string, price float64, touSpec string) error {
+	for idx, stationID := range stations {
+		planID := fmt.Sprintf("%s-perf-%s", stationID, month.Format("200601"))
+		rules, err := buildTariffRules(planID, mode, price, touSpec)
+		if err != nil {
+			return err
+		}
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO tariff_plans (id, tenant_id, station_id, effective_month, currency, mode)
+VALUES ($1, $2, $3, $4, 'CNY', $5)
+ON CONFLICT (id)
+DO UPDATE SET mode = EXCLUDED.mode, updated_at = NOW()`, planID, tenantID, stationID, month.UTC(), mode); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM tariff_rules WHERE plan_id = $1", planID); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		for _, rule := range rules {
+			if _, err := tx.ExecContext(ctx, `
+INSERT INTO tariff_rules (id, plan_id, start_minute, end_minute, price_per_kwh)
+VALUES ($1, $2, $3, $4, $5)`, rule.ID, planID, rule.StartMinute, rule.EndMinute, rule.Price); err != nil {
+				_ = tx.Rollback()
+				return err
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Printf("seeded tariff station %s (%d/%d)", stationID, idx+1, len(stations))
+	}
+	return nil
+}
+
+// buildTariffRules returns the rules of a plan: a single all-day window for
+// fixed mode, or the windows of touSpec ("start-end:price,...") for TOU mode.
+// TOU windows must be contiguous and cover the whole day.
+func buildTariffRules(planID, mode string, price float64, touSpec string) ([]tariffRule, error) {
+	switch mode {
+	case tariffModeFixed:
+		if price < 0 {
+			return nil, fmt.Errorf("tariff price must be >= 0")
+		}
+		return []tariffRule{{ID: planID + "-rule", StartMinute: 0, EndMinute: 1440, Price: price}}, nil
+	case tariffModeTOU:
+	default:
+		return nil, fmt.Errorf("unknown tariff mode %q", mode)
+	}
+
+	var rules []tariffRule
+	next := 0
+	for _, entry := range strings.Split(touSpec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		window, rawPrice, ok := strings.Cut(entry, ":")
+		rawStart, rawEnd, okRange := strings.Cut(window, "-")
+		if !ok || !okRange {
+			return nil, fmt.Errorf("invalid tou window %q", entry)
+		}
+		start, errStart := strconv.Atoi(strings.TrimSpace(rawStart))
+		end, errEnd := strconv.Atoi(strings.TrimSpace(rawEnd))
+		value, errPrice := strconv.ParseFloat(strings.TrimSpace(rawPrice), 64)
+		if errStart != nil || errEnd != nil || errPrice != nil || value < 0 {
+			return nil, fmt.Errorf("invalid tou window %q", entry)
+		}
+		if start != next || end <= start || end > 1440 {
+			return nil, fmt.Errorf("tou window %q must start at minute %d and end after it, within 1440", entry, next)
+		}
+		rules = append(rules, tariffRule{ID: fmt.Sprintf("%s-r%d", planID, len(rules)+1), StartMinute: start, EndMinute: end, Price: value})
+		next = end
+	}
+	if next != 1440 {
+		return nil, fmt.Errorf("tou windows must cover minutes 0-1440, ended at %d", next)
+	}
+	return rules, nil
+}
+
+// verifySeed compares the rows present for the seeded stations and date range
+// against what this run should have produced.
+func verifySeed(ctx context.Context, db *sql.DB, cfg config, stations []string, start time.Time) error {
+	end := start.AddDate(0, 0, cfg.days)
+	var diffs []string
+	if cfg.seedHourly || cfg.seedDaily {
+		actual, err := countAnalytics(ctx, db, stations, start, end)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, compareCounts("analytics_statistics", expectedAnalyticsCounts(stations, cfg.days, cfg.seedHourly, cfg.seedDaily), actual)...)
+	}
+	if cfg.seedSettlements {
+		actual, err := countSettlements(ctx, db, cfg.tenantID, stations, start, end)
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, compareCounts("settlements_day", expectedSettlementCounts(stations, cfg.days), actual)...)
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("row count mismatch:\n  %s", strings.Join(diffs, "\n  "))
+	}
+	return nil
+}
+
+func expectedAnalyticsCounts(stations []string, days int, hourly, daily bool) map[string]int {
+	expected := make(map[string]int, len(stations)*2)
+	for _, stationID := range stations {
+		if hourly {
+			expected[stationID+"/HOUR"] = days * 24
+		}
+		if daily {
+			expected[stationID+"/DAY"] = days
+		}
+	}
+	return expected
+}
+
+func expectedSettlementCounts(stations []string, days int) map[string]int {
+	expected := make(map[string]int, len(stations))
+	for _, stationID := range stations {
+		expected[stationID] = days
+	}
+	return expected
+}
+
+func countAnalytics(ctx context.Context, db *sql.DB, stations []string, start, end time.Time) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT subject_id, time_type, COUNT(*)
+FROM analytics_statistics
+WHERE subject_id = ANY($1) AND time_type IN ('HOUR', 'DAY') AND period_start >= $2 AND period_start < $3
+GROUP BY subject_id, time_type`, stations, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var stationID, timeType string
+		var count int
+		if err := rows.Scan(&stationID, &timeType, &count); err != nil {
+			return nil, err
+		}
+		counts[stationID+"/"+timeType] = count
+	}
+	return counts, rows.Err()
+}
+
+func countSettlements(ctx context.Context, db *sql.DB, tenantID string, stations []string, start, end time.Time) (map[string]int, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT station_id, COUNT(*)
+FROM settlements_day
+WHERE tenant_id = $1 AND station_id = ANY($2) AND day_start >= $3 AND day_start < $4
+GROUP BY station_id`, tenantID, stations, start.UTC(), end.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var stationID string
+		var count int
+		if err := rows.Scan(&stationID, &count); err != nil {
+			return nil, err
+		}
+		counts[stationID] = count
+	}
+	return counts, rows.Err()
+}
+
+// compareCounts returns one "table key: expected=N actual=M" line per key whose
+// count differs, sorted by key.
+func compareCounts(table string, expected, actual map[string]int) []string {
+	keys := make([]string, 0, len(expected))
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	for key := range actual {
+		if _, ok := expected[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	var diffs []string
+	for _, key := range keys {
+		if expected[key] != actual[key] {
+			diffs = append(diffs, fmt.Sprintf("%s %s: expected=%d actual=%d", table, key, expected[key], actual[key]))
+		}
+	}
+	return diffs
+}
+
+func generateStatements(ctx context.Context, baseURL string, stations []string, month string, category string) ([]statementRef, error) {
 	if strings.TrimSpace(baseURL) == "" {
 		return nil, fmt.Errorf("base url required")
 	}
 	client := &http.Client{Timeout: 30 * time.Second}
 	baseURL = strings.TrimRight(baseURL, "/")
-	ids := make([]string, 0, len(stations))
+	refs := make([]statementRef, 0, len(stations))
 	for _, stationID := range stations {
 		body := map[string]any{
 			"station_id": stationID,
@@ -381,12 +714,36 @@ func generateStatements(ctx context.Context, baseURL string, stations []string,
 		if respBody.StatementID == "" {
 			return nil, fmt.Errorf("empty statement id for %s", stationID)
 		}
-		ids = append(ids, respBody.StatementID)
+		refs = append(refs, statementRef{StationID: stationID, StatementID: respBody.StatementID})
 	}
-	return ids, nil
+	return refs, nil
+}
+
+// writeStatementIDs writes statement ids either one per line or as a JSON
+// array of {station_id, statement_id} objects.
+func writeStatementIDs(path, format string, refs []statementRef) error {
+	if format == statementIDsFormatJSON {
+		if refs == nil {
+			refs = []statementRef{}
+		}
+		content, err := json.MarshalIndent(refs, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFile(path, content)
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.StatementID)
+	}
+	return writeLines(path, ids)
 }
 
 func writeLines(path string, lines []string) error {
+	return writeFile(path, []byte(strings.Join(lines, "\n")))
+}
+
+func writeFile(path string, content []byte) error {
 	if path == "" {
 		return nil
 	}
@@ -396,8 +753,7 @@ func writeLines(path string, lines []string) error {
 			return err
 		}
 	}
-	content := strings.Join(lines, "\n")
-	return os.WriteFile(path, []byte(content), 0o644)
+	return os.WriteFile(path, content, 0o644)
 }
 
 func envOrDefault(key, fallback string) string {
@@ -419,6 +775,18 @@ func envOrInt(key string, fallback int) int {
 	return value
 }
 
+func envOrFloat(key string, fallback float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
 func envOrBool(key string, fallback bool) bool {
 	raw := strings.TrimSpace(strings.ToLower(os.Getenv(key)))
 	if raw == "" {
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteStatementIDs_JSONKeepsStationMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out", "statement_ids.json")
+	refs := []statementRef{
+		{StationID: "station-perf-0001", StatementID: "stmt-a"},
+		{StationID: "station-perf-0002", StatementID: "stmt-b"},
+	}
+	if err := writeStatementIDs(path, statementIDsFormatJSON, refs); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var got []map[string]string
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("output is not a JSON array of objects: %v\n%s", err, raw)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(got))
+	}
+	for i, entry := range got {
+		if len(entry) != 2 || entry["station_id"] != refs[i].StationID || entry["statement_id"] != refs[i].StatementID {
+			t.Fatalf("entry %d mismatch: %v", i, entry)
+		}
+	}
+}
+
+func TestWriteStatementIDs_LinesIsDefaultShape(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "statement_ids.txt")
+	refs := []statementRef{{StationID: "s1", StatementID: "stmt-a"}, {StationID: "s2", StatementID: "stmt-b"}}
+	if err := writeStatementIDs(path, statementIDsFormatLines, refs); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if string(raw) != "stmt-a\nstmt-b" {
+		t.Fatalf("unexpected lines output: %q", raw)
+	}
+}
+
+func TestCompareCounts_ReportsMissingAndUnexpectedRows(t *testing.T) {
+	expected := expectedAnalyticsCounts([]string{"s1", "s2"}, 2, true, true)
+	actual := map[string]int{"s1/HOUR": 48, "s1/DAY": 2, "s2/HOUR": 47, "s3/DAY": 1}
+
+	diffs := compareCounts("analytics_statistics", expected, actual)
+	want := []string{
+		"analytics_statistics s2/DAY: expected=2 actual=0",
+		"analytics_statistics s2/HOUR: expected=48 actual=47",
+		"analytics_statistics s3/DAY: expected=0 actual=1",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("diffs=%v", diffs)
+	}
+	for i := range want {
+		if diffs[i] != want[i] {
+			t.Fatalf("diff %d = %q, want %q", i, diffs[i], want[i])
+		}
+	}
+
+	if diffs := compareCounts("settlements_day", expectedSettlementCounts([]string{"s1"}, 3), map[string]int{"s1": 3}); len(diffs) != 0 {
+		t.Fatalf("expected no diffs, got %v", diffs)
+	}
+}
+
+func TestBuildTariffRules(t *testing.T) {
+	fixed, err := buildTariffRules("plan-1", tariffModeFixed, 1.5, "")
+	if err != nil || len(fixed) != 1 || fixed[0].StartMinute != 0 || fixed[0].EndMinute != 1440 || fixed[0].Price != 1.5 {
+		t.Fatalf("fixed rules=%+v err=%v", fixed, err)
+	}
+
+	tou, err := buildTariffRules("plan-2", tariffModeTOU, 0, defaultTOUSpec)
+	if err != nil {
+		t.Fatalf("tou: %v", err)
+	}
+	want := []tariffRule{
+		{ID: "plan-2-r1", StartMinute: 0, EndMinute: 480, Price: 0.5},
+		{ID: "plan-2-r2", StartMinute: 480, EndMinute: 1080, Price: 1.5},
+		{ID: "plan-2-r3", StartMinute: 1080, EndMinute: 1440, Price: 0.8},
+	}
+	if len(tou) != len(want) {
+		t.Fatalf("tou rules=%+v", tou)
+	}
+	for i := range want {
+		if tou[i] != want[i] {
+			t.Fatalf("rule %d = %+v, want %+v", i, tou[i], want[i])
+		}
+	}
+
+	for _, spec := range []string{"0-480:0.5,600-1440:1", "0-480:0.5", "0-1440:abc", "480-0:1"} {
+		if _, err := buildTariffRules("plan-3", tariffModeTOU, 0, spec); err == nil {
+			t.Fatalf("expected error for spec %q", spec)
+		}
+	}
+	if _, err := buildTariffRules("plan-4", "dynamic", 1, ""); err == nil {
+		t.Fatalf("expected error for unknown mode")
+	}
+}
+
+func TestResumeSkip_SkipsFullySeededStations(t *testing.T) {
+	counts := map[string]map[string]int{
+		"station-done":    {"station-done/HOUR": 48, "station-done/DAY": 2},
+		"station-partial": {"station-partial/HOUR": 30, "station-partial/DAY": 2},
+	}
+	var queried []string
+	skip := newResumeSkip(func(_ context.Context, stationID string) (map[string]int, error) {
+		queried = append(queried, stationID)
+		return counts[stationID], nil
+	}, func(stationID string) map[string]int {
+		return expectedAnalyticsCounts([]string{stationID}, 2, true, true)
+	})
+
+	for stationID, want := range map[string]bool{"station-done": true, "station-partial": false, "station-new": false} {
+		got, err := shouldSkip(context.Background(), skip, stationID)
+		if err != nil {
+			t.Fatalf("%s: %v", stationID, err)
+		}
+		if got != want {
+			t.Fatalf("%s: skip=%v want=%v", stationID, got, want)
+		}
+	}
+	if len(queried) != 3 {
+		t.Fatalf("expected one count query per station, got %v", queried)
+	}
+	if skipped, err := shouldSkip(context.Background(), nil, "station-done"); err != nil || skipped {
+		t.Fatalf("nil skip must seed every station, skipped=%v err=%v", skipped, err)
+	}
+}
+
+func TestSinusoidalProfile_StaysWithinRange(t *testing.T) {
+	profile, err := newValueProfile(profileSinusoidal)
+	if err != nil {
+		t.Fatalf("profile: %v", err)
+	}
+	for station := 0; station < 10; station++ {
+		base := stationBase(station)
+		for day := 0; day < 14; day++ {
+			var peakHour int
+			var peak float64
+			for hour := 0; hour < 24; hour++ {
+				charge, discharge := profile.hourly(station, day, hour)
+				if charge < 0 || charge > 2*base*1.1+1e-9 {
+					t.Fatalf("station=%d day=%d hour=%d charge=%v out of [0, %v]", station, day, hour, charge, 2*base*1.1)
+				}
+				if discharge < 0 || discharge > base*1.1+1e-9 {
+					t.Fatalf("station=%d day=%d hour=%d discharge=%v out of [0, %v]", station, day, hour, discharge, base*1.1)
+				}
+				if charge > peak {
+					peak, peakHour = charge, hour
+				}
+			}
+			if peakHour != 12 {
+				t.Fatalf("station=%d day=%d charge peaks at %d, want 12", station, day, peakHour)
+			}
+			charge, _ := profile.daily(station, day)
+			if math.Abs(charge-24*base*weeklyFactor(day)) > 1e-9 {
+				t.Fatalf("station=%d day=%d daily charge=%v, want %v", station, day, charge, 24*base*weeklyFactor(day))
+			}
+		}
+	}
+	if _, err := newValueProfile("flat"); err == nil {
+		t.Fatalf("expected error for unknown profile")
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	profileRamp       = "ramp"
+	profileSinusoidal = "sinusoidal"
+	profileBursty     = "bursty"
+)
+
+// valueProfile deterministically fills charge/discharge kWh for a station.
+// station is the 0-based index of the station in the seeded set.
+type valueProfile interface {
+	hourly(station, day, hour int) (charge, discharge float64)
+	daily(station, day int) (charge, discharge float64)
+}
+
+func newValueProfile(name string) (valueProfile, error) {
+	switch name {
+	case profileRamp:
+		return rampProfile{}, nil
+	case profileSinusoidal:
+		return sinusoidalProfile{}, nil
+	case profileBursty:
+		return burstyProfile{}, nil
+	default:
+		return nil, fmt.Errorf("unknown profile %q (ramp|sinusoidal|bursty)", name)
+	}
+}
+
+// stationBase scales values per station so stations differ: 1..10.
+func stationBase(station int) float64 {
+	return float64((station % 10) + 1)
+}
+
+// rampProfile is the original linear pattern: values climb with the hour of
+// day and the day index.
+type rampProfile struct{}
+
+func (rampProfile) hourly(station, _, hour int) (float64, float64) {
+	base := stationBase(station)
+	return base + float64(hour+1), base/2 + float64(hour%6)
+}
+
+func (rampProfile) daily(station, day int) (float64, float64) {
+	base := stationBase(station)
+	return base*10 + float64(day+1), base*5 + float64(day%7)
+}
+
+// sinusoidalProfile models a daily load curve: charging peaks at 12:00 (solar)
+// and discharging at 19:00 (evening demand), scaled by a gentle weekly wave.
+type sinusoidalProfile struct{}
+
+func (sinusoidalProfile) hourly(station, day, hour int) (float64, float64) {
+	base := stationBase(station)
+	week := weeklyFactor(day)
+	charge := base * (1 + math.Cos(2*math.Pi*float64(hour-12)/24)) * week
+	discharge := base / 2 * (1 + math.Cos(2*math.Pi*float64(hour-19)/24)) * week
+	return charge, discharge
+}
+
+func (p sinusoidalProfile) daily(station, day int) (float64, float64) {
+	return sumHours(p, station, day)
+}
+
+// weeklyFactor varies between 0.9 and 1.1 over a 7-day period.
+func weeklyFactor(day int) float64 {
+	return 1 + 0.1*math.Sin(2*math.Pi*float64(day)/7)
+}
+
+// burstyProfile keeps a low, noisy baseline and spikes to 5x in about 5% of
+// hours, which stresses outlier handling and defeats delta compression.
+type burstyProfile struct{}
+
+func (burstyProfile) hourly(station, day, hour int) (float64, float64) {
+	base := stationBase(station)
+	noise := unitHash(station, day, hour, 0)
+	charge := base * (0.5 + 0.5*noise)
+	discharge := base / 2 * (0.5 + 0.5*unitHash(station, day, hour, 1))
+	if unitHash(station, day, hour, 2) < 0.05 {
+		charge *= 5
+	}
+	if unitHash(station, day, hour, 3) < 0.05 {
+		discharge *= 5
+	}
+	return charge, discharge
+}
+
+func (p burstyProfile) daily(station, day int) (float64, float64) {
+	return sumHours(p, station, day)
+}
+
+func sumHours(p valueProfile, station, day int) (float64, float64) {
+	var charge, discharge float64
+	for hour := 0; hour < 24; hour++ {
+		c, d := p.hourly(station, day, hour)
+		charge += c
+		discharge += d
+	}
+	return charge, discharge
+}
+
+// unitHash maps its inputs to a stable value in [0, 1) using the splitmix64
+// finalizer, so runs are reproducible without a shared random source.
+func unitHash(station, day, hour, stream int) float64 {
+	x := uint64(station)<<40 ^ uint64(day)<<16 ^ uint64(hour)<<8 ^ uint64(stream)
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / float64(1<<53)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultAmountTolerance = 0.01
+
+// amountCheck recomputes one settled day's amount from its priced hours.
+type amountCheck struct {
+	DayStart         time.Time
+	Hours            int
+	HourEnergyKWh    float64
+	SettleEnergyKWh  float64
+	HourAmount       float64
+	SettleAmount     float64
+	AmountDiff       float64
+	AmountMismatched bool
+}
+
+// buildAmountChecks compares every settlement row with the sum of that day's
+// hourly amounts under the station tariff. A day is flagged when the amounts
+// differ by more than tolerance, which points at the tariff applied by the
+// settlement rather than at missing or late energy.
+func buildAmountChecks(hours []hourStat, settlements []settlementRow, tolerance float64) []amountCheck {
+	hourByDay := make(map[time.Time][]hourStat)
+	for _, row := range hours {
+		day := time.Date(row.PeriodStart.Year(), row.PeriodStart.Month(), row.PeriodStart.Day(), 0, 0, 0, 0, time.UTC)
+		hourByDay[day] = append(hourByDay[day], row)
+	}
+
+	checks := make([]amountCheck, 0, len(settlements))
+	for _, settle := range settlements {
+		day := time.Date(settle.DayStart.Year(), settle.DayStart.Month(), settle.DayStart.Day(), 0, 0, 0, 0, time.UTC)
+		check := amountCheck{
+			DayStart:        day,
+			Hours:           len(hourByDay[day]),
+			SettleEnergyKWh: settle.EnergyKWh,
+			SettleAmount:    settle.Amount,
+		}
+		for _, hr := range hourByDay[day] {
+			check.HourEnergyKWh += hr.EnergyKWh
+			check.HourAmount += hr.Amount
+		}
+		check.AmountDiff = check.HourAmount - check.SettleAmount
+		check.AmountMismatched = math.Abs(check.AmountDiff) > tolerance
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func writeAmountChecks(outDir string, rows []amountCheck) error {
+	path := filepath.Join(outDir, "amount_check.csv")
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{
+		"day_start",
+		"hours",
+		"energy_kwh_hour",
+		"energy_kwh_settlement",
+		"amount_hour",
+		"amount_settlement",
+		"amount_diff",
+		"amount_mismatch",
+	}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.Write([]string{
+			formatTime(row.DayStart),
+			formatInt(row.Hours),
+			formatFloat(row.HourEnergyKWh),
+			formatFloat(row.SettleEnergyKWh),
+			formatFloat(row.HourAmount),
+			formatFloat(row.SettleAmount),
+			formatFloat(row.AmountDiff),
+			formatBool(row.AmountMismatched),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuildAmountChecks_FlagsMispricedSettlement(t *testing.T) {
+	day1 := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	var hours []hourStat
+	for h := 0; h < 24; h++ {
+		hours = append(hours,
+			hourStat{PeriodStart: day1.Add(time.Duration(h) * time.Hour), EnergyKWh: 10, Amount: 5},
+			hourStat{PeriodStart: day2.Add(time.Duration(h) * time.Hour), EnergyKWh: 10, Amount: 5},
+		)
+	}
+	settlements := []settlementRow{
+		{DayStart: day1, EnergyKWh: 240, Amount: 120.004},
+		// Same energy, but settled at a flat 0.6/kWh instead of the 0.5 tariff.
+		{DayStart: day2, EnergyKWh: 240, Amount: 144},
+	}
+
+	checks := buildAmountChecks(hours, settlements, defaultAmountTolerance)
+	if len(checks) != 2 {
+		t.Fatalf("expected 2 checks, got %d", len(checks))
+	}
+	if checks[0].AmountMismatched {
+		t.Fatalf("day 1 within tolerance flagged: %+v", checks[0])
+	}
+	got := checks[1]
+	if !got.AmountMismatched || got.AmountDiff != -24 || got.HourEnergyKWh != got.SettleEnergyKWh || got.Hours != 24 {
+		t.Fatalf("day 2 expected amount mismatch with matching energy, got %+v", got)
+	}
+
+	outDir := t.TempDir()
+	if err := writeAmountChecks(outDir, checks); err != nil {
+		t.Fatalf("write amount checks: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// dbSnapshot is the hour, day and settlement data of one station month as
+// loaded from one database.
+type dbSnapshot struct {
+	Hours       []hourStat
+	Days        []dayStat
+	Settlements []settlementRow
+}
+
+// dbDiffCounts counts the hours and days on which the two databases differ.
+type dbDiffCounts struct {
+	Hours int
+	Days  int
+}
+
+// loadSnapshot loads a station month from db the way reconcileStation does.
+// Hour amounts are priced with plan and rules, so both databases are priced
+// with the same tariff and amount diffs come from energy alone.
+func loadSnapshot(ctx context.Context, db *sql.DB, cfg config, stationID string, monthStart, monthEnd time.Time, plan *tariffPlan, rules []tariffRule) (dbSnapshot, error) {
+	var snapshot dbSnapshot
+	var err error
+	if snapshot.Hours, err = loadHourStats(ctx, db, stationID, monthStart, monthEnd, cfg.sinceUpdated, plan, rules); err != nil {
+		return snapshot, fmt.Errorf("load hour stats: %w", err)
+	}
+	if snapshot.Days, err = loadDayStats(ctx, db, stationID, monthStart, monthEnd, cfg.sinceUpdated); err != nil {
+		return snapshot, fmt.Errorf("load day stats: %w", err)
+	}
+	if snapshot.Settlements, err = loadSettlements(ctx, db, cfg.tenantID, stationID, monthStart, monthEnd, cfg.sinceUpdated); err != nil {
+		return snapshot, fmt.Errorf("load settlements: %w", err)
+	}
+	return snapshot, nil
+}
+
+// compareDatabases writes db_diff_report.csv (hours, in the diff_report.csv
+// layout) and db_diff_days.csv (day stats and settlements) comparing a,
+// loaded from --db, with b, loaded from --db-b. Differences up to tolerance
+// are ignored when counting.
+func compareDatabases(outDir string, a, b dbSnapshot, semantics []string, tolerance float64) (dbDiffCounts, error) {
+	var counts dbDiffCounts
+	other := make([]legacyHour, 0, len(b.Hours))
+	for _, row := range b.Hours {
+		other = append(other, legacyHour{HourStart: row.PeriodStart, EnergyKWh: row.EnergyKWh, Amount: row.Amount})
+	}
+	if err := writeHourDiff(filepath.Join(outDir, "db_diff_report.csv"), "a", "b", a.Hours, other, semantics); err != nil {
+		return counts, fmt.Errorf("write db diff report: %w", err)
+	}
+	hoursA := make(map[time.Time]hourStat, len(a.Hours))
+	for _, row := range a.Hours {
+		hoursA[row.PeriodStart] = row
+	}
+	seen := make(map[time.Time]bool, len(b.Hours))
+	for _, row := range b.Hours {
+		seen[row.PeriodStart] = true
+		rowA, ok := hoursA[row.PeriodStart]
+		if !ok || differs(rowA.EnergyKWh, row.EnergyKWh, tolerance) || differs(rowA.Amount, row.Amount, tolerance) {
+			counts.Hours++
+		}
+	}
+	for start := range hoursA {
+		if !seen[start] {
+			counts.Hours++
+		}
+	}
+
+	days := buildDayDiffs(a, b)
+	for _, day := range days {
+		if day.differs(tolerance) {
+			counts.Days++
+		}
+	}
+	if err := writeDayDiffs(filepath.Join(outDir, "db_diff_days.csv"), days); err != nil {
+		return counts, fmt.Errorf("write db diff days: %w", err)
+	}
+	return counts, nil
+}
+
+// dayDiff holds one day of both databases; a missing row leaves its side zero
+// with an empty status.
+type dayDiff struct {
+	DayStart               time.Time
+	DayEnergyA, DayEnergyB float64
+	SettledEnergyA         float64
+	SettledEnergyB         float64
+	AmountA, AmountB       float64
+	StatusA, StatusB       string
+	HasDayA, HasDayB       bool
+	HasSettlementA         bool
+	HasSettlementB         bool
+}
+
+func (d dayDiff) differs(tolerance float64) bool {
+	return d.HasDayA != d.HasDayB || d.HasSettlementA != d.HasSettlementB || d.StatusA != d.StatusB ||
+		differs(d.DayEnergyA, d.DayEnergyB, tolerance) ||
+		differs(d.SettledEnergyA, d.SettledEnergyB, tolerance) ||
+		differs(d.AmountA, d.AmountB, tolerance)
+}
+
+func buildDayDiffs(a, b dbSnapshot) []dayDiff {
+	byDay := make(map[time.Time]*dayDiff)
+	day := func(start time.Time) *dayDiff {
+		start = start.UTC()
+		if d, ok := byDay[start]; ok {
+			return d
+		}
+		d := &dayDiff{DayStart: start}
+		byDay[start] = d
+		return d
+	}
+	for _, row := range a.Days {
+		d := day(row.PeriodStart)
+		d.DayEnergyA, d.HasDayA = row.EnergyKWh, true
+	}
+	for _, row := range b.Days {
+		d := day(row.PeriodStart)
+		d.DayEnergyB, d.HasDayB = row.EnergyKWh, true
+	}
+	for _, row := range a.Settlements {
+		d := day(row.DayStart)
+		d.SettledEnergyA, d.AmountA, d.StatusA, d.HasSettlementA = row.EnergyKWh, row.Amount, row.Status, true
+	}
+	for _, row := range b.Settlements {
+		d := day(row.DayStart)
+		d.SettledEnergyB, d.AmountB, d.StatusB, d.HasSettlementB = row.EnergyKWh, row.Amount, row.Status, true
+	}
+
+	days := make([]dayDiff, 0, len(byDay))
+	for _, d := range byDay {
+		days = append(days, *d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].DayStart.Before(days[j].DayStart) })
+	return days
+}
+
+func writeDayDiffs(path string, days []dayDiff) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+	if err := writer.Write([]string{
+		"day_start",
+		"day_energy_kwh_a",
+		"day_energy_kwh_b",
+		"day_energy_diff",
+		"settled_energy_kwh_a",
+		"settled_energy_kwh_b",
+		"settled_energy_diff",
+		"amount_a",
+		"amount_b",
+		"amount_diff",
+		"status_a",
+		"status_b",
+	}); err != nil {
+		return err
+	}
+	for _, d := range days {
+		if err := writer.Write([]string{
+			formatDate(d.DayStart),
+			formatFloat(d.DayEnergyA),
+			formatFloat(d.DayEnergyB),
+			formatFloat(d.DayEnergyA - d.DayEnergyB),
+			formatFloat(d.SettledEnergyA),
+			formatFloat(d.SettledEnergyB),
+			formatFloat(d.SettledEnergyA - d.SettledEnergyB),
+			formatFloat(d.AmountA),
+			formatFloat(d.AmountB),
+			formatFloat(d.AmountA - d.AmountB),
+			d.StatusA,
+			d.StatusB,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func differs(a, b, tolerance float64) bool {
+	return math.Abs(a-b) > tolerance
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/csv"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	snapshotMonth = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	snapshotNow   = time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)
+)
+
+// snapshotData holds the rows each stub database serves, keyed by DSN.
+var snapshotData = map[string]struct {
+	hours, days [][]driver.Value
+	settlements [][]driver.Value
+	overrides   [][]driver.Value
+}{
+	"a": {
+		hours: [][]driver.Value{
+			statRow("HOUR", snapshotMonth, 4, 6),
+			statRow("HOUR", snapshotMonth.Add(time.Hour), 2, 3),
+		},
+		days:        [][]driver.Value{statRow("DAY", snapshotMonth, 6, 9)},
+		settlements: [][]driver.Value{settlementValues(snapshotMonth, 15, 15, "FINAL")},
+	},
+	"b": {
+		hours: [][]driver.Value{
+			statRow("HOUR", snapshotMonth, 4, 6),
+			statRow("HOUR", snapshotMonth.Add(time.Hour), 3, 4),
+			statRow("HOUR", snapshotMonth.Add(2*time.Hour), 1, 0),
+		},
+		days: [][]driver.Value{
+			statRow("DAY", snapshotMonth, 8, 10),
+			statRow("DAY", snapshotMonth.AddDate(0, 0, 1), 1, 1),
+		},
+		settlements: [][]driver.Value{settlementValues(snapshotMonth, 15, 15, "DRAFT")},
+	},
+	"override": {
+		overrides: [][]driver.Value{{0.8, "CNY"}},
+	},
+}
+
+func init() {
+	sql.Register("reconcile-snapshot", snapshotDriver{})
+}
+
+func TestCompareDatabases_WritesHourAndDayDiffs(t *testing.T) {
+	ctx := context.Background()
+	plan := &tariffPlan{ID: "fixed", Mode: "fixed", Currency: "CNY"}
+	rules := []tariffRule{{ID: "fixed", StartMinute: 0, EndMinute: 1440, PricePerKWh: 0.5}}
+	cfg := config{tenantID: "tenant-a"}
+	monthEnd := snapshotMonth.AddDate(0, 1, 0)
+
+	snapshots := make(map[string]dbSnapshot)
+	for _, dsn := range []string{"a", "b"} {
+		db, err := sql.Open("reconcile-snapshot", dsn)
+		if err != nil {
+			t.Fatalf("open %s: %v", dsn, err)
+		}
+		defer db.Close()
+		snapshot, err := loadSnapshot(ctx, db, cfg, "station-1", snapshotMonth, monthEnd, plan, rules)
+		if err != nil {
+			t.Fatalf("load %s: %v", dsn, err)
+		}
+		snapshots[dsn] = snapshot
+	}
+
+	outDir := t.TempDir()
+	counts, err := compareDatabases(outDir, snapshots["a"], snapshots["b"], []string{"charge_power_kw"}, 0.01)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	// Hour 01 differs in energy and hour 02 exists only in b; day 1 differs,
+	// day 2 exists only in b.
+	if counts.Hours != 2 || counts.Days != 2 {
+		t.Fatalf("counts = %+v, want 2 hours and 2 days", counts)
+	}
+
+	hours := readCSV(t, filepath.Join(outDir, "db_diff_report.csv"))
+	if got := strings.Join(hours[0][2:8], ","); got != "energy_kwh_a,energy_kwh_b,energy_diff,amount_a,amount_b,amount_diff" {
+		t.Fatalf("hour header = %s", got)
+	}
+	wantHours := []string{
+		"10,10,0,5,5,0",
+		"5,7,-2,2.5,3.5,-1",
+		"0,1,-1,0,0.5,-0.5",
+	}
+	if len(hours) != len(wantHours)+1 {
+		t.Fatalf("hour rows = %v", hours)
+	}
+	for i, want := range wantHours {
+		if got := strings.Join(hours[i+1][2:8], ","); got != want {
+			t.Fatalf("hour row %d = %s, want %s", i, got, want)
+		}
+	}
+
+	days := readCSV(t, filepath.Join(outDir, "db_diff_days.csv"))
+	wantDays := []string{
+		"2026-01-01,15,18,-3,15,15,0,15,15,0,FINAL,DRAFT",
+		"2026-01-02,0,2,-2,0,0,0,0,0,0,,",
+	}
+	if len(days) != len(wantDays)+1 {
+		t.Fatalf("day rows = %v", days)
+	}
+	for i, want := range wantDays {
+		if got := strings.Join(days[i+1], ","); got != want {
+			t.Fatalf("day row %d = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return records
+}
+
+func statRow(timeType string, start time.Time, charge, discharge float64) []driver.Value {
+	return []driver.Value{"station-1", timeType, start.Format("2006010215"), start, "stat-" + timeType, true, charge, discharge, 0.0, 0.0, snapshotNow, snapshotNow}
+}
+
+func settlementValues(day time.Time, energy, amount float64, status string) []driver.Value {
+	return []driver.Value{"tenant-a", "station-1", day, energy, amount, "CNY", status, int64(1), snapshotNow, snapshotNow}
+}
+
+type snapshotDriver struct{}
+
+func (snapshotDriver) Open(name string) (driver.Conn, error) {
+	return snapshotConn{name: name}, nil
+}
+
+type snapshotConn struct {
+	name string
+}
+
+func (c snapshotConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	data := snapshotData[c.name]
+	switch {
+	case strings.Contains(query, "FROM analytics_statistics") && args[1].Value == "HOUR":
+		return &snapshotRows{values: data.hours}, nil
+	case strings.Contains(query, "FROM analytics_statistics") && args[1].Value == "DAY":
+		return &snapshotRows{values: data.days}, nil
+	case strings.Contains(query, "FROM settlements_day"):
+		return &snapshotRows{values: data.settlements}, nil
+	case strings.Contains(query, "FROM tariff_overrides"):
+		return &snapshotRows{values: data.overrides}, nil
+	case strings.Contains(query, "FROM tariff_plans"):
+		return &snapshotRows{}, nil
+	}
+	return nil, errors.New("snapshot driver: unexpected query")
+}
+
+func (snapshotConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("snapshot driver: prepare not supported")
+}
+
+func (snapshotConn) Close() error { return nil }
+
+func (snapshotConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("snapshot driver: transactions not supported")
+}
+
+type snapshotRows struct {
+	values [][]driver.Value
+	next   int
+}
+
+func (r *snapshotRows) Columns() []string {
+	if len(r.values) == 0 {
+		return nil
+	}
+	return make([]string, len(r.values[0]))
+}
+
+func (r *snapshotRows) Close() error { return nil }
+
+func (r *snapshotRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
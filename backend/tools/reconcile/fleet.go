@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+
+	"microgrid-cloud/internal/reconcile"
+)
+
+// fleetTotalID labels the aggregate row of fleet_summary.csv.
+const fleetTotalID = "TOTAL"
+
+// stationResult is the outcome of reconciling one station in fleet mode.
+type stationResult struct {
+	StationID string
+	Summary   reconcile.Summary
+	Err       error
+}
+
+// fleetRow is one line of fleet_summary.csv.
+type fleetRow struct {
+	StationID         string
+	Stations          int
+	Failed            int
+	DiffEnergyMax     float64
+	DiffAmountMax     float64
+	MissingHoursTotal int
+	DaysWithDiff      int
+	Error             string
+}
+
+func loadTenantStations(ctx context.Context, db *sql.DB, tenantID string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+SELECT id
+FROM stations
+WHERE tenant_id = $1
+ORDER BY id ASC`, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// buildFleetRows returns one row per station followed by a total row that
+// takes the largest diffs and sums missing hours and diffing days. Failed
+// stations are listed but excluded from the totals.
+func buildFleetRows(results []stationResult) []fleetRow {
+	rows := make([]fleetRow, 0, len(results)+1)
+	total := fleetRow{StationID: fleetTotalID}
+	for _, result := range results {
+		total.Stations++
+		if result.Err != nil {
+			total.Failed++
+			rows = append(rows, fleetRow{StationID: result.StationID, Stations: 1, Failed: 1, Error: result.Err.Error()})
+			continue
+		}
+		row := fleetRow{
+			StationID:         result.StationID,
+			Stations:          1,
+			DiffEnergyMax:     result.Summary.DiffEnergyMax,
+			DiffAmountMax:     result.Summary.DiffAmountMax,
+			MissingHoursTotal: result.Summary.MissingHoursTotal,
+		}
+		for _, day := range result.Summary.DayDiffs {
+			if day.EnergyDiff != 0 || day.AmountDiff != 0 {
+				row.DaysWithDiff++
+			}
+		}
+		rows = append(rows, row)
+
+		if row.DiffEnergyMax > total.DiffEnergyMax {
+			total.DiffEnergyMax = row.DiffEnergyMax
+		}
+		if row.DiffAmountMax > total.DiffAmountMax {
+			total.DiffAmountMax = row.DiffAmountMax
+		}
+		total.MissingHoursTotal += row.MissingHoursTotal
+		total.DaysWithDiff += row.DaysWithDiff
+	}
+	return append(rows, total)
+}
+
+func writeFleetSummary(outDir, month string, results []stationResult) error {
+	path := filepath.Join(outDir, "fleet_summary.csv")
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{
+		"station_id",
+		"month",
+		"stations",
+		"failed",
+		"diff_energy_max",
+		"diff_amount_max",
+		"missing_hours_total",
+		"days_with_diff",
+		"error",
+	}); err != nil {
+		return err
+	}
+
+	for _, row := range buildFleetRows(results) {
+		if err := writer.Write([]string{
+			row.StationID,
+			month,
+			formatInt(row.Stations),
+			formatInt(row.Failed),
+			formatFloat(row.DiffEnergyMax),
+			formatFloat(row.DiffAmountMax),
+			formatInt(row.MissingHoursTotal),
+			formatInt(row.DaysWithDiff),
+			row.Error,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
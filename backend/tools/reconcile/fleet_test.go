@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"microgrid-cloud/internal/reconcile"
+)
+
+func TestWriteFleetSummary_AggregatesStations(t *testing.T) {
+	results := []stationResult{
+		{StationID: "station-a", Summary: reconcile.Summary{
+			DiffEnergyMax:     1.5,
+			DiffAmountMax:     4,
+			MissingHoursTotal: 3,
+			DayDiffs:          []reconcile.DayDiff{{EnergyDiff: 1.5}, {}, {AmountDiff: -4}},
+		}},
+		{StationID: "station-b", Summary: reconcile.Summary{
+			DiffEnergyMax:     2.25,
+			DiffAmountMax:     0.5,
+			MissingHoursTotal: 24,
+			DayDiffs:          []reconcile.DayDiff{{EnergyDiff: -2.25, AmountDiff: 0.5}},
+		}},
+		{StationID: "station-c", Err: errors.New("tariff: no rows")},
+	}
+
+	outDir := t.TempDir()
+	if err := writeFleetSummary(outDir, "2026-03", results); err != nil {
+		t.Fatalf("write fleet summary: %v", err)
+	}
+	file, err := os.Open(filepath.Join(outDir, "fleet_summary.csv"))
+	if err != nil {
+		t.Fatalf("open fleet summary: %v", err)
+	}
+	defer file.Close()
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("read fleet summary: %v", err)
+	}
+
+	want := [][]string{
+		{"station_id", "month", "stations", "failed", "diff_energy_max", "diff_amount_max", "missing_hours_total", "days_with_diff", "error"},
+		{"station-a", "2026-03", "1", "0", "1.5", "4", "3", "2", ""},
+		{"station-b", "2026-03", "1", "0", "2.25", "0.5", "24", "1", ""},
+		{"station-c", "2026-03", "1", "1", "0", "0", "0", "0", "tariff: no rows"},
+		{"TOTAL", "2026-03", "3", "1", "2.25", "4", "27", "3", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("expected %d records, got %d: %v", len(want), len(records), records)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if records[i][j] != want[i][j] {
+				t.Fatalf("record %d column %s = %q, want %q", i, want[0][j], records[i][j], want[i][j])
+			}
+		}
+	}
+}
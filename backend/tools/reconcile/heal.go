@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"microgrid-cloud/internal/reconcile"
+)
+
+const healResultFile = "auto_heal.json"
+
+// httpWindowPublisher republishes windows through the platform's
+// /analytics/window-close endpoint with recalculate=true.
+type httpWindowPublisher struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func newHTTPWindowPublisher(url, token string) *httpWindowPublisher {
+	return &httpWindowPublisher{url: url, token: token, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// PublishWindowClosed implements reconcile.WindowPublisher.
+func (p *httpWindowPublisher) PublishWindowClosed(ctx context.Context, stationID string, windowStart, windowEnd time.Time) error {
+	body, err := json.Marshal(map[string]any{
+		"stationId":   stationID,
+		"windowStart": windowStart.UTC().Format(time.RFC3339),
+		"windowEnd":   windowEnd.UTC().Format(time.RFC3339),
+		"recalculate": true,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("window close endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// autoHeal republishes the station's missing hours that have telemetry (or
+// only lists them in dry-run mode) and writes auto_heal.json into outDir.
+func autoHeal(ctx context.Context, cfg config, stationID, outDir string, hours []hourStat, monthStart, monthEnd, now time.Time) (reconcile.HealResult, error) {
+	missing := reconcile.MissingHourStarts(toReconcileHours(hours), monthStart, monthEnd, cfg.asOf, now)
+	result, err := reconcile.Heal(ctx, cfg.healMode, stationID, missing, cfg.healChecker, cfg.healPublisher)
+	for _, hour := range result.Hours {
+		verb := "would republish"
+		if cfg.healMode == reconcile.HealApply {
+			verb = "republishing"
+		}
+		fmt.Fprintf(progress, "station %s: auto-heal %s window %s\n", stationID, verb, formatTime(hour))
+	}
+	if err != nil {
+		return result, err
+	}
+	fmt.Fprintf(progress, "station %s: auto-heal %s: %d missing hours, %d with telemetry, %d published, %d without telemetry\n",
+		stationID, result.Mode, len(missing), len(result.Hours), result.Published, result.NoTelemetry)
+
+	file, err := os.Create(filepath.Join(outDir, healResultFile))
+	if err != nil {
+		return result, err
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return result, encoder.Encode(result)
+}
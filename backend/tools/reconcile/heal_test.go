@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHTTPWindowPublisher_PostsRecalculation(t *testing.T) {
+	var got map[string]any
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	start := time.Date(2026, time.March, 2, 7, 0, 0, 0, time.UTC)
+	publisher := newHTTPWindowPublisher(server.URL, "token-1")
+	if err := publisher.PublishWindowClosed(context.Background(), "station-1", start, start.Add(time.Hour)); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if auth != "Bearer token-1" {
+		t.Fatalf("authorization = %q", auth)
+	}
+	if got["stationId"] != "station-1" || got["windowStart"] != "2026-03-02T07:00:00Z" || got["windowEnd"] != "2026-03-02T08:00:00Z" || got["recalculate"] != true {
+		t.Fatalf("payload = %v", got)
+	}
+}
+
+func TestHTTPWindowPublisher_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	start := time.Date(2026, time.March, 2, 7, 0, 0, 0, time.UTC)
+	if err := newHTTPWindowPublisher(server.URL, "").PublishWindowClosed(context.Background(), "station-1", start, start.Add(time.Hour)); err == nil {
+		t.Fatalf("expected error for 401")
+	}
+}
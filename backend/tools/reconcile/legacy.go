@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	legacyFormatAuto = "auto"
+	legacyFormatCSV  = "csv"
+	legacyFormatJSON = "json"
+)
+
+// Field names accepted for legacy hour records, matched case-insensitively
+// against CSV headers and JSON object keys.
+var (
+	legacyTimeKeys   = []string{"hour_start", "period_start", "time", "datetime", "ts"}
+	legacyEnergyKeys = []string{"energy_kwh", "energy", "kwh"}
+	legacyAmountKeys = []string{"amount", "total_amount"}
+)
+
+// loadLegacyHours reads legacy hour records as CSV or JSON. The auto format
+// picks JSON for .json files and CSV otherwise.
+func loadLegacyHours(path, format string, loc *time.Location) ([]legacyHour, error) {
+	if format == "" || format == legacyFormatAuto {
+		format = legacyFormatCSV
+		if strings.EqualFold(filepath.Ext(path), ".json") {
+			format = legacyFormatJSON
+		}
+	}
+	switch format {
+	case legacyFormatCSV:
+		return loadLegacyCSV(path, loc)
+	case legacyFormatJSON:
+		return loadLegacyJSON(path, loc)
+	default:
+		return nil, fmt.Errorf("legacy: unsupported format %q", format)
+	}
+}
+
+// loadLegacyJSON reads either a top-level array of hour objects or an object
+// wrapping that array under "hours", "data" or "items". Times may be strings
+// or epoch seconds/milliseconds; numbers may be JSON numbers or strings.
+func loadLegacyJSON(path string, loc *time.Location) ([]legacyHour, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	records, err := decodeLegacyRecords(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("legacy json: empty")
+	}
+
+	result := make([]legacyHour, 0, len(records))
+	for i, record := range records {
+		fields := make(map[string]json.RawMessage, len(record))
+		for key, value := range record {
+			fields[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+		rawTime, okTime := legacyField(fields, legacyTimeKeys)
+		rawEnergy, okEnergy := legacyField(fields, legacyEnergyKeys)
+		rawAmount, okAmount := legacyField(fields, legacyAmountKeys)
+		if !okTime || !okEnergy || !okAmount {
+			return nil, fmt.Errorf("legacy json: record %d requires fields hour_start, energy_kwh, amount", i)
+		}
+		ts, err := parseLegacyTime(rawTime, loc)
+		if err != nil {
+			return nil, err
+		}
+		energy, err := parseFloat(rawEnergy)
+		if err != nil {
+			return nil, fmt.Errorf("legacy json: record %d energy: %w", i, err)
+		}
+		amount, err := parseFloat(rawAmount)
+		if err != nil {
+			return nil, fmt.Errorf("legacy json: record %d amount: %w", i, err)
+		}
+		result = append(result, legacyHour{
+			HourStart: ts.UTC(),
+			EnergyKWh: energy,
+			Amount:    amount,
+		})
+	}
+	return result, nil
+}
+
+func decodeLegacyRecords(data []byte) ([]map[string]json.RawMessage, error) {
+	var records []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &records); err == nil {
+		return records, nil
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, fmt.Errorf("legacy json: %w", err)
+	}
+	for _, key := range []string{"hours", "data", "items"} {
+		raw, ok := wrapper[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, &records); err != nil {
+			return nil, fmt.Errorf("legacy json: %s: %w", key, err)
+		}
+		return records, nil
+	}
+	return nil, errors.New("legacy json: expected an array or an object with hours, data or items")
+}
+
+// legacyField returns the first present key as text: JSON strings are
+// unquoted, numbers keep their literal form, and null reads as empty.
+func legacyField(fields map[string]json.RawMessage, keys []string) (string, bool) {
+	for _, key := range keys {
+		raw, ok := fields[key]
+		if !ok {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			return text, true
+		}
+		value := strings.TrimSpace(string(raw))
+		if value == "null" {
+			value = ""
+		}
+		return value, true
+	}
+	return "", false
+}
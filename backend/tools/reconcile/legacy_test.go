@@ -0,0 +1,67 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadLegacyHours_JSONEpochMillis(t *testing.T) {
+	hour0 := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	hour1 := hour0.Add(time.Hour)
+	body := `{"data": [
+		{"ts": ` + formatInt(int(hour0.UnixMilli())) + `, "Energy_KWh": 12.5, "amount": "6.25"},
+		{"ts": "` + formatInt(int(hour1.UnixMilli())) + `", "energy_kwh": "8", "amount": 4}
+	]}`
+	path := filepath.Join(t.TempDir(), "legacy.json")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatalf("write legacy file: %v", err)
+	}
+
+	rows, err := loadLegacyHours(path, legacyFormatAuto, time.UTC)
+	if err != nil {
+		t.Fatalf("load legacy json: %v", err)
+	}
+	want := []legacyHour{
+		{HourStart: hour0, EnergyKWh: 12.5, Amount: 6.25},
+		{HourStart: hour1, EnergyKWh: 8, Amount: 4},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(rows))
+	}
+	for i := range want {
+		if !rows[i].HourStart.Equal(want[i].HourStart) || rows[i].EnergyKWh != want[i].EnergyKWh || rows[i].Amount != want[i].Amount {
+			t.Fatalf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+
+	// An explicit format wins over the extension.
+	if _, err := loadLegacyHours(path, legacyFormatCSV, time.UTC); err == nil {
+		t.Fatalf("expected csv parse of a json file to fail")
+	}
+}
+
+func TestParseLegacyTime_AppliesLegacyZone(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+
+	got, err := parseLegacyTime("2026-03-02 08:00:00", shanghai)
+	if err != nil {
+		t.Fatalf("parse local time: %v", err)
+	}
+	want := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) || got.Location() != time.UTC {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+
+	// Explicit offsets and epoch values are absolute and ignore the legacy zone.
+	for _, value := range []string{"2026-03-02T00:00:00Z", "2026-03-02T08:00:00+08:00", formatInt(int(want.Unix()))} {
+		got, err := parseLegacyTime(value, shanghai)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("parse %q = %s, %v; want %s", value, got, err, want)
+		}
+	}
+}
@@ -14,6 +14,9 @@ import (
 	"strings"
 	"time"
 
+	"microgrid-cloud/internal/calendar"
+	"microgrid-cloud/internal/reconcile"
+
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
@@ -21,12 +24,33 @@ const timeLayout = time.RFC3339
 
 type config struct {
 	dbURL          string
+	dbURLB         string
 	tenantID       string
 	stationID      string
 	month          string
 	outDir         string
+	flat           bool
+	ndjson         bool
+	stationIDs     []string
+	allStations    bool
 	legacyHourPath string
+	legacyFormat   string
+	legacyTZ       *time.Location
 	pricePerKWh    float64
+	amountTol      float64
+	sinceUpdated   time.Time
+	asOf           time.Time
+	healMode       reconcile.HealMode
+	healURL        string
+	healToken      string
+	healChecker    reconcile.TelemetryChecker
+	healPublisher  reconcile.WindowPublisher
+	dbB            *sql.DB
+}
+
+// fleet reports whether more than the single --station is reconciled.
+func (c config) fleet() bool {
+	return c.allStations || len(c.stationIDs) > 0
 }
 
 type hourStat struct {
@@ -126,30 +150,94 @@ func main() {
 		os.Exit(2)
 	}
 
-	if err := os.MkdirAll(cfg.outDir, 0o755); err != nil {
-		fmt.Fprintln(os.Stderr, "create out dir:", err)
-		os.Exit(2)
-	}
-
 	db, err := sql.Open("pgx", cfg.dbURL)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "db open:", err)
 		os.Exit(2)
 	}
 	defer db.Close()
+	if cfg.dbURLB != "" {
+		cfg.dbB, err = sql.Open("pgx", cfg.dbURLB)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "db-b open:", err)
+			os.Exit(2)
+		}
+		defer cfg.dbB.Close()
+	}
 
 	ctx := context.Background()
-	monthStart, monthEnd, err := parseMonth(cfg.month)
+	monthStart, monthEnd, err := calendar.MonthRange(cfg.month)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(2)
 	}
 
-	plan, rules, err := loadTariff(ctx, db, cfg.tenantID, cfg.stationID, monthStart)
+	if cfg.ndjson {
+		progress = os.Stderr
+	}
+	if cfg.healMode != reconcile.HealOff {
+		cfg.healChecker = reconcile.NewSQLTelemetryChecker(db)
+		if cfg.healMode == reconcile.HealApply {
+			cfg.healPublisher = newHTTPWindowPublisher(cfg.healURL, cfg.healToken)
+		}
+	}
+
+	layout := newOutputLayout(cfg)
+	if !cfg.fleet() {
+		stationDir := layout.stationDir(cfg.stationID)
+		if _, err := reconcileStation(ctx, db, cfg, cfg.stationID, stationDir, monthStart, monthEnd); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		fmt.Fprintf(progress, "Reconciliation outputs written to %s\n", stationDir)
+		return
+	}
+
+	stations := cfg.stationIDs
+	if cfg.allStations {
+		stations, err = loadTenantStations(ctx, db, cfg.tenantID)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "load stations:", err)
+			os.Exit(2)
+		}
+	}
+
+	var results []stationResult
+	failed := 0
+	for _, stationID := range stations {
+		summary, err := reconcileStation(ctx, db, cfg, stationID, layout.stationDir(stationID), monthStart, monthEnd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "station %s: %v\n", stationID, err)
+			failed++
+		}
+		results = append(results, stationResult{StationID: stationID, Summary: summary, Err: err})
+	}
+	fleetDir := layout.fleetDir()
+	if err := os.MkdirAll(fleetDir, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, "create out dir:", err)
+		os.Exit(2)
+	}
+	if err := writeFleetSummary(fleetDir, cfg.month, results); err != nil {
+		fmt.Fprintln(os.Stderr, "write fleet summary:", err)
+		os.Exit(2)
+	}
+	fmt.Fprintf(progress, "Reconciliation outputs for %d stations written to %s\n", len(stations), cfg.outDir)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// reconcileStation loads one station's month and writes its CSVs and
+// diff_summary.json into outDir.
+func reconcileStation(ctx context.Context, db *sql.DB, cfg config, stationID, outDir string, monthStart, monthEnd time.Time) (reconcile.Summary, error) {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("create out dir: %w", err)
+	}
+
+	plan, rules, err := loadTariff(ctx, db, cfg.tenantID, stationID, monthStart)
 	if err != nil {
 		if cfg.pricePerKWh <= 0 {
-			fmt.Fprintln(os.Stderr, "tariff:", err)
-			os.Exit(2)
+			return reconcile.Summary{}, fmt.Errorf("tariff: %w", err)
 		}
 		plan = &tariffPlan{ID: "fixed", Mode: "fixed", Currency: "CNY"}
 		rules = []tariffRule{{
@@ -160,82 +248,172 @@ func main() {
 		}}
 	}
 
-	hours, err := loadHourStats(ctx, db, cfg.stationID, monthStart, monthEnd, plan, rules)
+	snapshot, err := loadSnapshot(ctx, db, cfg, stationID, monthStart, monthEnd, plan, rules)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "load hour stats:", err)
-		os.Exit(2)
+		return reconcile.Summary{}, err
 	}
-
-	days, err := loadDayStats(ctx, db, cfg.stationID, monthStart, monthEnd)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "load day stats:", err)
-		os.Exit(2)
+	hours, days, settlements := snapshot.Hours, snapshot.Days, snapshot.Settlements
+	if cfg.ndjson {
+		if err := writeNDJSON(os.Stdout, stationID, snapshot); err != nil {
+			return reconcile.Summary{}, fmt.Errorf("write ndjson: %w", err)
+		}
 	}
-
-	settlements, err := loadSettlements(ctx, db, cfg.tenantID, cfg.stationID, monthStart, monthEnd)
+	statements, err := loadStatements(ctx, db, cfg.tenantID, stationID, monthStart)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "load settlements:", err)
-		os.Exit(2)
+		return reconcile.Summary{}, fmt.Errorf("load statements: %w", err)
 	}
 
-	statements, err := loadStatements(ctx, db, cfg.tenantID, cfg.stationID, monthStart)
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "load statements:", err)
-		os.Exit(2)
+	if err := writeHourStats(outDir, hours); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write hour stats: %w", err)
+	}
+	if err := writeDayStats(outDir, days); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write day stats: %w", err)
+	}
+	if err := writeSettlements(outDir, settlements); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write settlements: %w", err)
+	}
+	if err := writeStatementSummary(outDir, statements); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write statement summary: %w", err)
 	}
 
-	if err := writeHourStats(cfg.outDir, hours); err != nil {
-		fmt.Fprintln(os.Stderr, "write hour stats:", err)
-		os.Exit(2)
+	amountChecks := buildAmountChecks(hours, settlements, cfg.amountTol)
+	if err := writeAmountChecks(outDir, amountChecks); err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write amount check: %w", err)
 	}
-	if err := writeDayStats(cfg.outDir, days); err != nil {
-		fmt.Fprintln(os.Stderr, "write day stats:", err)
-		os.Exit(2)
+	for _, check := range amountChecks {
+		if check.AmountMismatched {
+			fmt.Fprintf(os.Stderr, "station %s: settlement amount %s on %s differs from hourly amount %s\n",
+				stationID, formatFloat(check.SettleAmount), formatDate(check.DayStart), formatFloat(check.HourAmount))
+		}
 	}
-	if err := writeSettlements(cfg.outDir, settlements); err != nil {
-		fmt.Fprintln(os.Stderr, "write settlements:", err)
-		os.Exit(2)
+
+	summary, err := writeDiffSummary(outDir, stationID, hours, settlements, monthStart, monthEnd, cfg.asOf, time.Now())
+	if err != nil {
+		return reconcile.Summary{}, fmt.Errorf("write diff summary: %w", err)
 	}
-	if err := writeStatementSummary(cfg.outDir, statements); err != nil {
-		fmt.Fprintln(os.Stderr, "write statement summary:", err)
-		os.Exit(2)
+	if cfg.healMode != reconcile.HealOff && summary.MissingHoursTotal > 0 {
+		if _, err := autoHeal(ctx, cfg, stationID, outDir, hours, monthStart, monthEnd, time.Now()); err != nil {
+			return summary, fmt.Errorf("auto-heal: %w", err)
+		}
+	}
+	if !cfg.asOf.IsZero() {
+		fmt.Fprintf(progress, "station %s: diffed %d of %d days before %s, %d missing hours\n",
+			stationID, len(summary.DayDiffs), int(monthEnd.Sub(monthStart).Hours()/24), formatDate(cfg.asOf), summary.MissingHoursTotal)
 	}
 
 	if cfg.legacyHourPath != "" {
-		semantics, _ := loadSemantics(ctx, db, cfg.stationID)
-		legacyRows, err := loadLegacyHours(cfg.legacyHourPath)
+		semantics, _ := loadSemantics(ctx, db, stationID)
+		legacyRows, err := loadLegacyHours(cfg.legacyHourPath, cfg.legacyFormat, cfg.legacyTZ)
 		if err != nil {
-			fmt.Fprintln(os.Stderr, "load legacy hours:", err)
-			os.Exit(2)
+			return summary, fmt.Errorf("load legacy hours: %w", err)
 		}
-		if err := writeDiffReport(cfg.outDir, hours, legacyRows, semantics); err != nil {
-			fmt.Fprintln(os.Stderr, "write diff report:", err)
-			os.Exit(2)
+		if err := writeDiffReport(outDir, hours, legacyRows, semantics); err != nil {
+			return summary, fmt.Errorf("write diff report: %w", err)
 		}
 	}
 
-	fmt.Printf("Reconciliation outputs written to %s\n", cfg.outDir)
+	if cfg.dbB != nil {
+		snapshotB, err := loadSnapshot(ctx, cfg.dbB, cfg, stationID, monthStart, monthEnd, plan, rules)
+		if err != nil {
+			return summary, fmt.Errorf("db-b: %w", err)
+		}
+		semantics, _ := loadSemantics(ctx, db, stationID)
+		counts, err := compareDatabases(outDir, snapshot, snapshotB, semantics, cfg.amountTol)
+		if err != nil {
+			return summary, err
+		}
+		fmt.Fprintf(progress, "station %s: --db and --db-b differ on %d hours and %d days\n", stationID, counts.Hours, counts.Days)
+	}
+	return summary, nil
 }
 
 func parseFlags() (config, error) {
 	var cfg config
 	flag.StringVar(&cfg.dbURL, "db", getenvDefault("DATABASE_URL", getenvDefault("PG_DSN", "")), "Postgres DSN")
+	flag.StringVar(&cfg.dbURLB, "db-b", "", "second Postgres DSN; writes db_diff_report.csv and db_diff_days.csv comparing it with --db (optional)")
 	flag.StringVar(&cfg.tenantID, "tenant", getenvDefault("TENANT_ID", ""), "tenant id")
 	flag.StringVar(&cfg.stationID, "station", "", "station id")
+	stations := flag.String("stations", "", "comma-separated station ids, one output subdirectory each")
+	flag.BoolVar(&cfg.allStations, "all", false, "reconcile every station of the tenant")
 	flag.StringVar(&cfg.month, "month", "", "month in YYYY-MM")
-	flag.StringVar(&cfg.outDir, "out", "./out", "output directory")
-	flag.StringVar(&cfg.legacyHourPath, "legacy-hour-csv", "", "legacy hour CSV path (optional)")
+	flag.StringVar(&cfg.outDir, "out", "./out", "output directory; files go to {out}/{tenant}/{station}/{month}")
+	flag.BoolVar(&cfg.flat, "flat", false, "write into --out directly (one subdirectory per station in fleet mode) instead of {tenant}/{station}/{month}")
+	flag.BoolVar(&cfg.ndjson, "ndjson", false, "also stream hour, day and settlement records to stdout as one JSON object per line; status lines go to stderr")
+	flag.StringVar(&cfg.legacyHourPath, "legacy-hour-csv", "", "legacy hour CSV or JSON path (optional)")
+	legacyTZ := flag.String("legacy-tz", "UTC", "IANA time zone of legacy timestamps that carry no offset")
+	flag.StringVar(&cfg.legacyFormat, "legacy-format", legacyFormatAuto, "legacy file format: auto (by extension), csv or json")
 	flag.Float64Var(&cfg.pricePerKWh, "price-per-kwh", getenvFloatDefault("PRICE_PER_KWH", 0), "fallback fixed price per kWh when no tariff plan")
+	flag.Float64Var(&cfg.amountTol, "amount-tolerance", defaultAmountTolerance, "max |sum(hour amount) - settlement amount| per day before amount_check.csv flags it")
+	asOf := flag.String("as-of", "", "reconcile a partial month: only diff days before this date (YYYY-MM-DD or RFC3339)")
+	sinceUpdated := flag.String("since-updated", "", "only load rows with updated_at >= this RFC3339 time (incremental mode)")
+	autoHealMode := flag.String("auto-heal", string(reconcile.HealOff), "republish missing hours that have telemetry: off, dry-run (preview only) or apply")
+	flag.StringVar(&cfg.healURL, "heal-url", getenvDefault("RECONCILE_HEAL_URL", ""), "window close endpoint used by --auto-heal=apply, e.g. http://localhost:8080/analytics/window-close")
+	flag.StringVar(&cfg.healToken, "heal-token", getenvDefault("RECONCILE_HEAL_TOKEN", ""), "bearer token for --heal-url")
 	flag.Parse()
 
+	if *asOf != "" {
+		parsed, err := parseAsOf(*asOf)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.asOf = parsed
+	}
+	if *sinceUpdated != "" {
+		since, err := time.Parse(time.RFC3339, *sinceUpdated)
+		if err != nil {
+			return cfg, errors.New("--since-updated must be RFC3339")
+		}
+		cfg.sinceUpdated = since.UTC()
+	}
+	healMode, err := reconcile.ParseHealMode(*autoHealMode)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.healMode = healMode
+	if cfg.healMode != reconcile.HealOff && !cfg.sinceUpdated.IsZero() {
+		return cfg, errors.New("--auto-heal cannot be combined with --since-updated")
+	}
+	if cfg.healMode == reconcile.HealApply && cfg.healURL == "" {
+		return cfg, errors.New("--auto-heal=apply requires --heal-url or RECONCILE_HEAL_URL")
+	}
+	if cfg.amountTol < 0 {
+		return cfg, errors.New("--amount-tolerance must be >= 0")
+	}
 	if cfg.dbURL == "" {
 		return cfg, errors.New("missing --db or DATABASE_URL/PG_DSN")
 	}
 	if cfg.tenantID == "" {
 		return cfg, errors.New("missing --tenant or TENANT_ID")
 	}
-	if cfg.stationID == "" {
-		return cfg, errors.New("missing --station")
+	for _, id := range strings.Split(*stations, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			cfg.stationIDs = append(cfg.stationIDs, id)
+		}
+	}
+	modes := 0
+	for _, set := range []bool{cfg.stationID != "", len(cfg.stationIDs) > 0, cfg.allStations} {
+		if set {
+			modes++
+		}
+	}
+	if modes == 0 {
+		return cfg, errors.New("missing --station, --stations or --all")
+	}
+	if modes > 1 {
+		return cfg, errors.New("--station, --stations and --all are mutually exclusive")
+	}
+	loc, err := time.LoadLocation(*legacyTZ)
+	if err != nil {
+		return cfg, fmt.Errorf("--legacy-tz: %w", err)
+	}
+	cfg.legacyTZ = loc
+	switch cfg.legacyFormat {
+	case legacyFormatAuto, legacyFormatCSV, legacyFormatJSON:
+	default:
+		return cfg, errors.New("--legacy-format must be auto, csv or json")
+	}
+	if cfg.fleet() && cfg.legacyHourPath != "" {
+		return cfg, errors.New("--legacy-hour-csv requires a single --station")
 	}
 	if cfg.month == "" {
 		return cfg, errors.New("missing --month (YYYY-MM)")
@@ -263,17 +441,30 @@ func getenvFloatDefault(key string, fallback float64) float64 {
 	return parsed
 }
 
-func parseMonth(value string) (time.Time, time.Time, error) {
-	t, err := time.Parse("2006-01", value)
+// parseAsOf returns the UTC day of value, matching how the shadowrun runner
+// truncates its job date.
+func parseAsOf(value string) (time.Time, error) {
+	t, err := time.Parse("2006-01-02", value)
 	if err != nil {
-		return time.Time{}, time.Time{}, errors.New("month must be YYYY-MM")
+		t, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return time.Time{}, errors.New("--as-of must be YYYY-MM-DD or RFC3339")
+		}
 	}
-	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
-	end := start.AddDate(0, 1, 0)
-	return start, end, nil
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
 }
 
+// tariffOverrideID identifies the plan and rule built from a tariff override.
+const tariffOverrideID = "override"
+
+// loadTariff returns the tariff of the station's month. A row in
+// tariff_overrides takes precedence over tariff_plans, as in the shadow run.
 func loadTariff(ctx context.Context, db *sql.DB, tenantID, stationID string, month time.Time) (*tariffPlan, []tariffRule, error) {
+	if override, rules, err := loadTariffOverride(ctx, db, tenantID, stationID, month); err != nil || override != nil {
+		return override, rules, err
+	}
+
 	var plan tariffPlan
 	err := db.QueryRowContext(ctx, `
 SELECT id, mode, currency
@@ -308,6 +499,24 @@ ORDER BY start_minute ASC`, plan.ID)
 	return &plan, rules, nil
 }
 
+// loadTariffOverride returns the pinned price of the station's month as a
+// single all-day rule, or a nil plan when none is pinned.
+func loadTariffOverride(ctx context.Context, db *sql.DB, tenantID, stationID string, month time.Time) (*tariffPlan, []tariffRule, error) {
+	var price float64
+	plan := tariffPlan{ID: tariffOverrideID, Mode: tariffOverrideID}
+	err := db.QueryRowContext(ctx, `
+SELECT price_per_kwh, currency
+FROM tariff_overrides
+WHERE tenant_id = $1 AND station_id = $2 AND month = $3`, tenantID, stationID, month).Scan(&price, &plan.Currency)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return &plan, []tariffRule{{ID: tariffOverrideID, StartMinute: 0, EndMinute: 1440, PricePerKWh: price}}, nil
+}
+
 func matchRule(rules []tariffRule, minute int) (tariffRule, bool) {
 	for _, rule := range rules {
 		if rule.StartMinute <= minute && rule.EndMinute > minute {
@@ -317,27 +526,9 @@ func matchRule(rules []tariffRule, minute int) (tariffRule, bool) {
 	return tariffRule{}, false
 }
 
-func loadHourStats(ctx context.Context, db *sql.DB, stationID string, from, to time.Time, plan *tariffPlan, rules []tariffRule) ([]hourStat, error) {
-	rows, err := db.QueryContext(ctx, `
-SELECT
-	subject_id,
-	time_type,
-	time_key,
-	period_start,
-	statistic_id,
-	is_completed,
-	charge_kwh,
-	discharge_kwh,
-	earnings,
-	carbon_reduction,
-	created_at,
-	updated_at
-FROM analytics_statistics
-WHERE subject_id = $1
-	AND time_type = 'HOUR'
-	AND period_start >= $2
-	AND period_start < $3
-ORDER BY period_start ASC`, stationID, from.UTC(), to.UTC())
+func loadHourStats(ctx context.Context, db *sql.DB, stationID string, from, to, since time.Time, plan *tariffPlan, rules []tariffRule) ([]hourStat, error) {
+	query, args := statsQuery("HOUR", stationID, from, to, since)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -389,8 +580,10 @@ ORDER BY period_start ASC`, stationID, from.UTC(), to.UTC())
 	return result, nil
 }
 
-func loadDayStats(ctx context.Context, db *sql.DB, stationID string, from, to time.Time) ([]dayStat, error) {
-	rows, err := db.QueryContext(ctx, `
+// statsQuery selects analytics_statistics rows of one time type whose period
+// starts in [from, to). A non-zero since adds the incremental updated_at filter.
+func statsQuery(timeType, stationID string, from, to, since time.Time) (string, []any) {
+	query := `
 SELECT
 	subject_id,
 	time_type,
@@ -406,10 +599,27 @@ SELECT
 	updated_at
 FROM analytics_statistics
 WHERE subject_id = $1
-	AND time_type = 'DAY'
-	AND period_start >= $2
-	AND period_start < $3
-ORDER BY period_start ASC`, stationID, from.UTC(), to.UTC())
+	AND time_type = $2
+	AND period_start >= $3
+	AND period_start < $4`
+	args := []any{stationID, timeType, from.UTC(), to.UTC()}
+	query, args = withUpdatedSince(query, args, since)
+	return query + "\nORDER BY period_start ASC", args
+}
+
+// withUpdatedSince appends an updated_at >= since condition to a query whose
+// WHERE clause is last; the zero time leaves the query unchanged.
+func withUpdatedSince(query string, args []any, since time.Time) (string, []any) {
+	if since.IsZero() {
+		return query, args
+	}
+	args = append(args, since.UTC())
+	return query + fmt.Sprintf("\n\tAND updated_at >= $%d", len(args)), args
+}
+
+func loadDayStats(ctx context.Context, db *sql.DB, stationID string, from, to, since time.Time) ([]dayStat, error) {
+	query, args := statsQuery("DAY", stationID, from, to, since)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -446,25 +656,9 @@ ORDER BY period_start ASC`, stationID, from.UTC(), to.UTC())
 	return result, nil
 }
 
-func loadSettlements(ctx context.Context, db *sql.DB, tenantID, stationID string, from, to time.Time) ([]settlementRow, error) {
-	rows, err := db.QueryContext(ctx, `
-SELECT
-	tenant_id,
-	station_id,
-	day_start,
-	energy_kwh,
-	amount,
-	currency,
-	status,
-	version,
-	created_at,
-	updated_at
-FROM settlements_day
-WHERE tenant_id = $1
-	AND station_id = $2
-	AND day_start >= $3
-	AND day_start < $4
-ORDER BY day_start ASC`, tenantID, stationID, from.UTC(), to.UTC())
+func loadSettlements(ctx context.Context, db *sql.DB, tenantID, stationID string, from, to, since time.Time) ([]settlementRow, error) {
+	query, args := settlementsQuery(tenantID, stationID, from, to, since)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -498,6 +692,29 @@ ORDER BY day_start ASC`, tenantID, stationID, from.UTC(), to.UTC())
 	return result, nil
 }
 
+func settlementsQuery(tenantID, stationID string, from, to, since time.Time) (string, []any) {
+	query := `
+SELECT
+	tenant_id,
+	station_id,
+	day_start,
+	energy_kwh,
+	amount,
+	currency,
+	status,
+	version,
+	created_at,
+	updated_at
+FROM settlements_day
+WHERE tenant_id = $1
+	AND station_id = $2
+	AND day_start >= $3
+	AND day_start < $4`
+	args := []any{tenantID, stationID, from.UTC(), to.UTC()}
+	query, args = withUpdatedSince(query, args, since)
+	return query + "\nORDER BY day_start ASC", args
+}
+
 func loadStatements(ctx context.Context, db *sql.DB, tenantID, stationID string, month time.Time) ([]statementSummary, error) {
 	rows, err := db.QueryContext(ctx, `
 SELECT
@@ -822,7 +1039,7 @@ ORDER BY semantic ASC`, stationID)
 	return semantics, nil
 }
 
-func loadLegacyHours(path string) ([]legacyHour, error) {
+func loadLegacyCSV(path string, loc *time.Location) ([]legacyHour, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
@@ -842,9 +1059,9 @@ func loadLegacyHours(path string) ([]legacyHour, error) {
 	for i, name := range records[0] {
 		header[strings.ToLower(strings.TrimSpace(name))] = i
 	}
-	timeIdx := findHeader(header, "hour_start", "period_start", "time", "datetime", "ts")
-	energyIdx := findHeader(header, "energy_kwh", "energy", "kwh")
-	amountIdx := findHeader(header, "amount", "total_amount")
+	timeIdx := findHeader(header, legacyTimeKeys...)
+	energyIdx := findHeader(header, legacyEnergyKeys...)
+	amountIdx := findHeader(header, legacyAmountKeys...)
 	if timeIdx < 0 || energyIdx < 0 || amountIdx < 0 {
 		return nil, errors.New("legacy csv requires headers: hour_start, energy_kwh, amount")
 	}
@@ -854,7 +1071,7 @@ func loadLegacyHours(path string) ([]legacyHour, error) {
 		if timeIdx >= len(row) {
 			continue
 		}
-		ts, err := parseLegacyTime(row[timeIdx])
+		ts, err := parseLegacyTime(row[timeIdx], loc)
 		if err != nil {
 			return nil, err
 		}
@@ -875,8 +1092,35 @@ func loadLegacyHours(path string) ([]legacyHour, error) {
 	return result, nil
 }
 
+// writeDiffSummary writes diff_summary.json in the same shape the shadowrun
+// runner produces. It covers every day of the month, or only the days before
+// asOf when asOf falls inside the month.
+func writeDiffSummary(outDir, stationID string, hours []hourStat, settlements []settlementRow, monthStart, monthEnd, asOf, generatedAt time.Time) (reconcile.Summary, error) {
+	hourRows := toReconcileHours(hours)
+	settlementRows := make([]reconcile.Settlement, 0, len(settlements))
+	for _, row := range settlements {
+		settlementRows = append(settlementRows, reconcile.Settlement{DayStart: row.DayStart, EnergyKWh: row.EnergyKWh, Amount: row.Amount})
+	}
+	summary := reconcile.BuildSummary(stationID, hourRows, settlementRows, monthStart, monthEnd, asOf, generatedAt)
+	return summary, reconcile.WriteSummaryJSON(outDir, summary)
+}
+
+func toReconcileHours(hours []hourStat) []reconcile.Hour {
+	rows := make([]reconcile.Hour, 0, len(hours))
+	for _, row := range hours {
+		rows = append(rows, reconcile.Hour{Start: row.PeriodStart, EnergyKWh: row.EnergyKWh, Amount: row.Amount})
+	}
+	return rows
+}
+
 func writeDiffReport(outDir string, local []hourStat, legacy []legacyHour, semantics []string) error {
-	path := filepath.Join(outDir, "diff_report.csv")
+	return writeHourDiff(filepath.Join(outDir, "diff_report.csv"), "local", "legacy", local, legacy, semantics)
+}
+
+// writeHourDiff writes one row per hour present on either side, with the
+// values of both sides and local minus other. The column suffixes name the
+// sides; tariff columns describe the local hour.
+func writeHourDiff(path, localLabel, otherLabel string, local []hourStat, other []legacyHour, semantics []string) error {
 	file, err := os.Create(path)
 	if err != nil {
 		return err
@@ -889,11 +1133,11 @@ func writeDiffReport(outDir string, local []hourStat, legacy []legacyHour, seman
 	if err := writer.Write([]string{
 		"day_start",
 		"hour_start",
-		"energy_kwh_local",
-		"energy_kwh_legacy",
+		"energy_kwh_" + localLabel,
+		"energy_kwh_" + otherLabel,
 		"energy_diff",
-		"amount_local",
-		"amount_legacy",
+		"amount_" + localLabel,
+		"amount_" + otherLabel,
 		"amount_diff",
 		"tariff_rule_id",
 		"rule_start_minute",
@@ -909,7 +1153,7 @@ func writeDiffReport(outDir string, local []hourStat, legacy []legacyHour, seman
 		localMap[row.PeriodStart] = row
 	}
 	legacyMap := make(map[time.Time]legacyHour)
-	for _, row := range legacy {
+	for _, row := range other {
 		legacyMap[row.HourStart] = row
 	}
 
@@ -970,10 +1214,12 @@ func findHeader(headers map[string]int, names ...string) int {
 	return -1
 }
 
-func parseLegacyTime(value string) (time.Time, error) {
+// parseLegacyTime accepts epoch seconds/milliseconds or one of the supported
+// layouts. Timestamps without an offset are read as wall-clock time in loc.
+func parseLegacyTime(value string, loc *time.Location) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
-		return time.Time{}, errors.New("legacy csv: empty time")
+		return time.Time{}, errors.New("legacy: empty time")
 	}
 	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
 		if epoch > 1_000_000_000_000 {
@@ -989,11 +1235,11 @@ func parseLegacyTime(value string) (time.Time, error) {
 		"2006-01-02T15:04:05",
 	}
 	for _, layout := range layouts {
-		if t, err := time.Parse(layout, value); err == nil {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
 			return t.UTC(), nil
 		}
 	}
-	return time.Time{}, fmt.Errorf("legacy csv: unsupported time format %q", value)
+	return time.Time{}, fmt.Errorf("legacy: unsupported time format %q", value)
 }
 
 func parseFloat(value string) (float64, error) {
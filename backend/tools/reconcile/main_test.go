@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"microgrid-cloud/internal/reconcile"
+)
+
+func TestWriteDiffSummary_ReportsMaxima(t *testing.T) {
+	monthStart := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	day2 := monthStart.AddDate(0, 0, 1)
+
+	var hours []hourStat
+	for h := 0; h < 24; h++ {
+		hours = append(hours,
+			hourStat{PeriodStart: monthStart.Add(time.Duration(h) * time.Hour), EnergyKWh: 2, Amount: 1},
+			hourStat{PeriodStart: day2.Add(time.Duration(h) * time.Hour), EnergyKWh: 2, Amount: 1},
+		)
+	}
+	settlements := []settlementRow{
+		{DayStart: monthStart, EnergyKWh: 45.5, Amount: 24},
+		{DayStart: day2, EnergyKWh: 48, Amount: 27.25},
+	}
+
+	outDir := t.TempDir()
+	if _, err := writeDiffSummary(outDir, "station-1", hours, settlements, monthStart, monthEnd, time.Time{}, monthEnd); err != nil {
+		t.Fatalf("write diff summary: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, "diff_summary.json"))
+	if err != nil {
+		t.Fatalf("read summary: %v", err)
+	}
+	var summary reconcile.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatalf("decode summary: %v", err)
+	}
+	if summary.DiffEnergyMax != 2.5 {
+		t.Fatalf("diff_energy_max=%v, want 2.5", summary.DiffEnergyMax)
+	}
+	if summary.DiffAmountMax != 3.25 {
+		t.Fatalf("diff_amount_max=%v, want 3.25", summary.DiffAmountMax)
+	}
+	if summary.Month != "2026-03" || summary.StationID != "station-1" || len(summary.DayDiffs) != 31 {
+		t.Fatalf("unexpected summary header: month=%s station=%s days=%d", summary.Month, summary.StationID, len(summary.DayDiffs))
+	}
+}
+
+func TestQueries_SinceUpdatedFilter(t *testing.T) {
+	from := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	since := time.Date(2026, time.March, 20, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))
+
+	full, fullArgs := statsQuery("HOUR", "station-1", from, to, time.Time{})
+	if strings.Contains(full, "updated_at >=") || len(fullArgs) != 4 {
+		t.Fatalf("full query must not filter updated_at: %s %v", full, fullArgs)
+	}
+
+	query, args := statsQuery("HOUR", "station-1", from, to, since)
+	if !strings.Contains(query, "AND updated_at >= $5\nORDER BY period_start ASC") {
+		t.Fatalf("hour query missing since filter: %s", query)
+	}
+	if len(args) != 5 || !args[4].(time.Time).Equal(since) || args[4].(time.Time).Location() != time.UTC {
+		t.Fatalf("unexpected hour args %v", args)
+	}
+
+	query, args = settlementsQuery("tenant-1", "station-1", from, to, since)
+	if !strings.Contains(query, "AND updated_at >= $5\nORDER BY day_start ASC") || len(args) != 5 {
+		t.Fatalf("settlement query missing since filter: %s %v", query, args)
+	}
+}
+
+func TestWriteDiffSummary_AsOfMidMonthTrimsFutureDays(t *testing.T) {
+	monthStart := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, 0)
+	asOf, err := parseAsOf("2026-03-15T10:30:00+08:00")
+	if err != nil {
+		t.Fatalf("parse as-of: %v", err)
+	}
+
+	// Every day before the as-of date is complete except March 3, which lacks two hours.
+	var hours []hourStat
+	var settlements []settlementRow
+	for day := monthStart; day.Before(asOf); day = day.AddDate(0, 0, 1) {
+		count := 24
+		if day.Day() == 3 {
+			count = 22
+		}
+		for h := 0; h < count; h++ {
+			hours = append(hours, hourStat{PeriodStart: day.Add(time.Duration(h) * time.Hour), EnergyKWh: 1})
+		}
+		settlements = append(settlements, settlementRow{DayStart: day, EnergyKWh: float64(count)})
+	}
+
+	summary, err := writeDiffSummary(t.TempDir(), "station-1", hours, settlements, monthStart, monthEnd, asOf, monthEnd)
+	if err != nil {
+		t.Fatalf("write diff summary: %v", err)
+	}
+	if len(summary.DayDiffs) != 14 {
+		t.Fatalf("expected 14 diffed days before the as-of date, got %d", len(summary.DayDiffs))
+	}
+	if last := summary.DayDiffs[len(summary.DayDiffs)-1].DayStart; !last.Equal(asOf.AddDate(0, 0, -1)) {
+		t.Fatalf("last diffed day %s, want %s", last, asOf.AddDate(0, 0, -1))
+	}
+	if summary.MissingHoursTotal != 2 {
+		t.Fatalf("expected only the 2 missing hours before the as-of date, got %d", summary.MissingHoursTotal)
+	}
+}
+
+func TestLoadTariff_OverrideTakesPrecedence(t *testing.T) {
+	ctx := context.Background()
+	db, err := sql.Open("reconcile-snapshot", "override")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	plan, rules, err := loadTariff(ctx, db, "tenant-a", "station-1", snapshotMonth)
+	if err != nil {
+		t.Fatalf("load tariff: %v", err)
+	}
+	if plan.ID != tariffOverrideID || plan.Currency != "CNY" {
+		t.Fatalf("plan = %+v, want the override", plan)
+	}
+	if len(rules) != 1 || rules[0].StartMinute != 0 || rules[0].EndMinute != 1440 || rules[0].PricePerKWh != 0.8 {
+		t.Fatalf("rules = %+v, want one all-day rule at 0.8", rules)
+	}
+
+	// Without an override the plan lookup runs; none exists here either.
+	plain, err := sql.Open("reconcile-snapshot", "a")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer plain.Close()
+	if _, _, err := loadTariff(ctx, plain, "tenant-a", "station-1", snapshotMonth); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("load tariff without override err = %v, want sql.ErrNoRows", err)
+	}
+}
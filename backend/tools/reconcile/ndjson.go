@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// progress receives the tool's status lines. --ndjson moves them to stderr so
+// stdout only carries records.
+var progress io.Writer = os.Stdout
+
+type hourRecord struct {
+	Type         string    `json:"type"`
+	StationID    string    `json:"station_id"`
+	PeriodStart  time.Time `json:"period_start"`
+	IsCompleted  bool      `json:"is_completed"`
+	ChargeKWh    float64   `json:"charge_kwh"`
+	DischargeKWh float64   `json:"discharge_kwh"`
+	EnergyKWh    float64   `json:"energy_kwh"`
+	Amount       float64   `json:"amount"`
+	TariffRuleID string    `json:"tariff_rule_id,omitempty"`
+}
+
+type dayRecord struct {
+	Type        string    `json:"type"`
+	StationID   string    `json:"station_id"`
+	PeriodStart time.Time `json:"period_start"`
+	IsCompleted bool      `json:"is_completed"`
+	EnergyKWh   float64   `json:"energy_kwh"`
+}
+
+type settlementRecord struct {
+	Type      string    `json:"type"`
+	StationID string    `json:"station_id"`
+	DayStart  time.Time `json:"day_start"`
+	EnergyKWh float64   `json:"energy_kwh"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	Version   int       `json:"version"`
+}
+
+// writeNDJSON writes the hours, days and settlements of one station as one
+// JSON object per line, tagged by type, so a consumer can process a fleet run
+// station by station as it streams.
+func writeNDJSON(w io.Writer, stationID string, snapshot dbSnapshot) error {
+	encoder := json.NewEncoder(w)
+	for _, row := range snapshot.Hours {
+		if err := encoder.Encode(hourRecord{
+			Type:         "hour",
+			StationID:    stationID,
+			PeriodStart:  row.PeriodStart,
+			IsCompleted:  row.IsCompleted,
+			ChargeKWh:    row.ChargeKWh,
+			DischargeKWh: row.DischargeKWh,
+			EnergyKWh:    row.EnergyKWh,
+			Amount:       row.Amount,
+			TariffRuleID: row.TariffRuleID,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, row := range snapshot.Days {
+		if err := encoder.Encode(dayRecord{
+			Type:        "day",
+			StationID:   stationID,
+			PeriodStart: row.PeriodStart,
+			IsCompleted: row.IsCompleted,
+			EnergyKWh:   row.EnergyKWh,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, row := range snapshot.Settlements {
+		if err := encoder.Encode(settlementRecord{
+			Type:      "settlement",
+			StationID: stationID,
+			DayStart:  row.DayStart,
+			EnergyKWh: row.EnergyKWh,
+			Amount:    row.Amount,
+			Currency:  row.Currency,
+			Status:    row.Status,
+			Version:   row.Version,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
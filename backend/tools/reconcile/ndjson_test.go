@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWriteNDJSON_OneRecordPerLine(t *testing.T) {
+	day := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	snapshot := dbSnapshot{
+		Hours: []hourStat{
+			{PeriodStart: day, IsCompleted: true, ChargeKWh: 1, DischargeKWh: 2, EnergyKWh: 3, Amount: 1.5, TariffRuleID: "fixed"},
+			{PeriodStart: day.Add(time.Hour), EnergyKWh: 4, Amount: 2},
+		},
+		Days:        []dayStat{{PeriodStart: day, IsCompleted: true, EnergyKWh: 7}},
+		Settlements: []settlementRow{{DayStart: day, EnergyKWh: 7, Amount: 3.5, Currency: "CNY", Status: "FINAL", Version: 2}},
+	}
+
+	var out bytes.Buffer
+	if err := writeNDJSON(&out, "station-1", snapshot); err != nil {
+		t.Fatalf("write ndjson: %v", err)
+	}
+
+	var types []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		if record["station_id"] != "station-1" {
+			t.Fatalf("record without station: %v", record)
+		}
+		types = append(types, record["type"].(string))
+	}
+	want := []string{"hour", "hour", "day", "settlement"}
+	if len(types) != len(want) {
+		t.Fatalf("types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("types = %v, want %v", types, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// fleetDirName holds the fleet summaries of a tenant next to its stations.
+const fleetDirName = "_fleet"
+
+// outputLayout places the files of a run under --out. By default each run
+// writes to {out}/{tenant}/{station}/{month} and fleet runs put
+// fleet_summary.csv in {out}/{tenant}/_fleet/{month}, so runs for other
+// tenants, stations or months never overwrite each other. Flat keeps the old
+// layout: a single station writes into {out}, a fleet run into {out}/{station}
+// with the summary in {out}.
+type outputLayout struct {
+	root     string
+	tenantID string
+	month    string
+	flat     bool
+	fleet    bool
+}
+
+func newOutputLayout(cfg config) outputLayout {
+	return outputLayout{root: cfg.outDir, tenantID: cfg.tenantID, month: cfg.month, flat: cfg.flat, fleet: cfg.fleet()}
+}
+
+// stationDir is the directory of one station's CSVs and diff_summary.json.
+func (l outputLayout) stationDir(stationID string) string {
+	if l.flat {
+		if l.fleet {
+			return filepath.Join(l.root, stationID)
+		}
+		return l.root
+	}
+	return filepath.Join(l.root, pathSegment(l.tenantID), pathSegment(stationID), pathSegment(l.month))
+}
+
+// fleetDir is the directory of fleet_summary.csv.
+func (l outputLayout) fleetDir() string {
+	if l.flat {
+		return l.root
+	}
+	return filepath.Join(l.root, pathSegment(l.tenantID), fleetDirName, pathSegment(l.month))
+}
+
+// pathSegment keeps an id from adding or escaping directory levels.
+func pathSegment(id string) string {
+	id = strings.NewReplacer("/", "_", `\`, "_").Replace(id)
+	if id == "" || id == "." || id == ".." {
+		return "_"
+	}
+	return id
+}
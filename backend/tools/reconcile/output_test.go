@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOutputLayout_NestsByTenantStationMonth(t *testing.T) {
+	root := t.TempDir()
+	runs := []config{
+		{outDir: root, tenantID: "tenant-a", stationID: "station-1", month: "2026-03"},
+		{outDir: root, tenantID: "tenant-a", stationID: "station-1", month: "2026-04"},
+		{outDir: root, tenantID: "tenant-b", stationID: "station-1", month: "2026-03"},
+	}
+	for _, cfg := range runs {
+		dir := newOutputLayout(cfg).stationDir(cfg.stationID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatalf("create %s: %v", dir, err)
+		}
+		if err := writeHourStats(dir, nil); err != nil {
+			t.Fatalf("write hour stats: %v", err)
+		}
+	}
+	for _, rel := range []string{
+		"tenant-a/station-1/2026-03/hour_stats.csv",
+		"tenant-a/station-1/2026-04/hour_stats.csv",
+		"tenant-b/station-1/2026-03/hour_stats.csv",
+	} {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+			t.Fatalf("missing %s: %v", rel, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(root, "hour_stats.csv")); !os.IsNotExist(err) {
+		t.Fatalf("nested run wrote into --out directly: %v", err)
+	}
+
+	fleet := newOutputLayout(config{outDir: root, tenantID: "tenant-a", month: "2026-03", allStations: true})
+	if got, want := fleet.stationDir("station-2"), filepath.Join(root, "tenant-a", "station-2", "2026-03"); got != want {
+		t.Fatalf("fleet station dir = %s, want %s", got, want)
+	}
+	if got, want := fleet.fleetDir(), filepath.Join(root, "tenant-a", "_fleet", "2026-03"); got != want {
+		t.Fatalf("fleet dir = %s, want %s", got, want)
+	}
+	if got, want := fleet.stationDir("../escape"), filepath.Join(root, "tenant-a", ".._escape", "2026-03"); got != want {
+		t.Fatalf("station id with separators = %s, want %s", got, want)
+	}
+}
+
+func TestOutputLayout_FlatKeepsOldPaths(t *testing.T) {
+	single := newOutputLayout(config{outDir: "out", tenantID: "tenant-a", stationID: "station-1", month: "2026-03", flat: true})
+	if got := single.stationDir("station-1"); got != "out" {
+		t.Fatalf("flat single station dir = %s, want out", got)
+	}
+	fleet := newOutputLayout(config{outDir: "out", tenantID: "tenant-a", month: "2026-03", allStations: true, flat: true})
+	if got, want := fleet.stationDir("station-1"), filepath.Join("out", "station-1"); got != want {
+		t.Fatalf("flat fleet station dir = %s, want %s", got, want)
+	}
+	if got := fleet.fleetDir(); got != "out" {
+		t.Fatalf("flat fleet dir = %s, want out", got)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	telemetry "microgrid-cloud/internal/telemetry/domain"
+)
+
+// bucket is the downsampled value of one point over [Start, Start+resolution).
+type bucket struct {
+	PointKey string
+	Start    time.Time
+	Avg      float64
+	Min      float64
+	Max      float64
+	Last     float64
+	Count    int
+}
+
+// validateResolution accepts resolutions that split an hour into whole buckets,
+// so buckets never straddle the hours the tool compacts one at a time.
+func validateResolution(resolution time.Duration) error {
+	if resolution < time.Second || resolution > time.Hour || time.Hour%resolution != 0 {
+		return errors.New("resolution must divide an hour evenly, between 1s and 1h")
+	}
+	return nil
+}
+
+// bucketPoints downsamples points into resolution-wide buckets aligned to the
+// hour, per point key. Last is the value of the latest sample in the bucket.
+// The result is ordered by point key, then bucket start.
+func bucketPoints(points []telemetry.TelemetryPoint, resolution time.Duration) []bucket {
+	type key struct {
+		point string
+		start time.Time
+	}
+	type acc struct {
+		bucket
+		sum    float64
+		lastAt time.Time
+	}
+	accs := make(map[key]*acc)
+	for _, point := range points {
+		at := point.At.UTC()
+		start := at.Truncate(resolution)
+		for pointKey, value := range point.Values {
+			k := key{point: pointKey, start: start}
+			a := accs[k]
+			if a == nil {
+				a = &acc{bucket: bucket{PointKey: pointKey, Start: start, Min: value, Max: value, Last: value}, lastAt: at}
+				accs[k] = a
+			}
+			a.sum += value
+			a.Count++
+			a.Min = min(a.Min, value)
+			a.Max = max(a.Max, value)
+			if !at.Before(a.lastAt) {
+				a.Last, a.lastAt = value, at
+			}
+		}
+	}
+
+	buckets := make([]bucket, 0, len(accs))
+	for _, a := range accs {
+		a.Avg = a.sum / float64(a.Count)
+		buckets = append(buckets, a.bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].PointKey != buckets[j].PointKey {
+			return buckets[i].PointKey < buckets[j].PointKey
+		}
+		return buckets[i].Start.Before(buckets[j].Start)
+	})
+	return buckets
+}
@@ -0,0 +1,264 @@
+// Command telemetry_compact downsamples old raw telemetry into buckets of
+// telemetry_points_compacted and then deletes the raw rows, keeping charts of
+// old data at a coarser resolution. Only hours whose HOUR statistic is
+// completed are compacted, so no aggregate still needs the raw rows.
+//
+// Samples are read through the telemetry query layer, per station point as
+// analytics reads them: text values are left in telemetry_points, and
+// several devices reporting the same point key at the same instant count as
+// one sample.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	telemetrypostgres "microgrid-cloud/internal/telemetry/infrastructure/postgres"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type config struct {
+	dsn        string
+	tenantID   string
+	stationID  string
+	olderThan  time.Duration
+	resolution time.Duration
+	maxHours   int
+	dryRun     bool
+	keepRaw    bool
+}
+
+func main() {
+	cfg := parseConfig()
+	if cfg.dsn == "" {
+		log.Fatal("PG_DSN or DATABASE_URL is required")
+	}
+	if cfg.tenantID == "" {
+		log.Fatal("tenant-id is required")
+	}
+	if cfg.olderThan <= 0 {
+		log.Fatal("older-than must be > 0")
+	}
+	if err := validateResolution(cfg.resolution); err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := sql.Open("pgx", cfg.dsn)
+	if err != nil {
+		log.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	cutoff := time.Now().UTC().Add(-cfg.olderThan).Truncate(time.Hour)
+	compactor := &compactor{db: db, query: telemetrypostgres.NewTelemetryQuery(db), cfg: cfg}
+	stations := []string{cfg.stationID}
+	if cfg.stationID == "" {
+		if stations, err = compactor.stations(ctx, cutoff); err != nil {
+			log.Fatalf("list stations: %v", err)
+		}
+	}
+
+	var hours, buckets, samples int
+	for _, stationID := range stations {
+		h, b, s, err := compactor.compactStation(ctx, stationID, cutoff)
+		hours, buckets, samples = hours+h, buckets+b, samples+s
+		if err != nil {
+			log.Fatalf("station %s: %v", stationID, err)
+		}
+	}
+	verb := "compacted"
+	if cfg.dryRun {
+		verb = "would compact"
+	}
+	fmt.Printf("%s %d hours of %d stations before %s: %d samples into %d buckets of %s\n",
+		verb, hours, len(stations), cutoff.Format(time.RFC3339), samples, buckets, cfg.resolution)
+}
+
+func parseConfig() config {
+	cfg := config{}
+	flag.StringVar(&cfg.dsn, "pg-dsn", envOrDefault("PG_DSN", envOrDefault("DATABASE_URL", "")), "Postgres DSN")
+	flag.StringVar(&cfg.tenantID, "tenant-id", envOrDefault("TENANT_ID", "tenant-demo"), "tenant whose telemetry is compacted")
+	flag.StringVar(&cfg.stationID, "station-id", envOrDefault("STATION_ID", ""), "compact one station; empty compacts every station of the tenant")
+	flag.DurationVar(&cfg.olderThan, "older-than", envOrDuration("COMPACT_OLDER_THAN", 30*24*time.Hour), "compact hours that ended longer ago than this")
+	flag.DurationVar(&cfg.resolution, "resolution", envOrDuration("COMPACT_RESOLUTION", time.Minute), "bucket width; must divide an hour evenly")
+	flag.IntVar(&cfg.maxHours, "max-hours", envOrInt("COMPACT_MAX_HOURS", 0), "stop after this many hours per station (oldest first); 0 compacts all")
+	flag.BoolVar(&cfg.dryRun, "dry-run", envOrBool("COMPACT_DRY_RUN", false), "report what would be compacted without writing")
+	flag.BoolVar(&cfg.keepRaw, "keep-raw", envOrBool("COMPACT_KEEP_RAW", false), "write buckets but keep the raw rows")
+	flag.Parse()
+	return cfg
+}
+
+type compactor struct {
+	db    *sql.DB
+	query *telemetrypostgres.TelemetryQuery
+	cfg   config
+}
+
+// stations lists the tenant's stations with raw telemetry before cutoff.
+func (c *compactor) stations(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := c.db.QueryContext(ctx, `
+SELECT DISTINCT station_id
+FROM telemetry_points
+WHERE tenant_id = $1 AND ts < $2
+ORDER BY station_id`, c.cfg.tenantID, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stations []string
+	for rows.Next() {
+		var stationID string
+		if err := rows.Scan(&stationID); err != nil {
+			return nil, err
+		}
+		stations = append(stations, stationID)
+	}
+	return stations, rows.Err()
+}
+
+// compactableHours lists the hours before cutoff that still have raw
+// telemetry and a completed HOUR statistic, oldest first.
+func (c *compactor) compactableHours(ctx context.Context, stationID string, cutoff time.Time) ([]time.Time, error) {
+	query := `
+SELECT h.hour
+FROM (
+	SELECT DISTINCT date_bin('1 hour', ts, TIMESTAMPTZ '2000-01-01 00:00:00+00') AS hour
+	FROM telemetry_points
+	WHERE tenant_id = $1 AND station_id = $2 AND ts < $3 AND value_numeric IS NOT NULL
+) h
+WHERE EXISTS (
+	SELECT 1
+	FROM analytics_statistics s
+	WHERE s.subject_id = $2 AND s.time_type = 'HOUR' AND s.period_start = h.hour AND s.is_completed
+)
+ORDER BY h.hour`
+	args := []any{c.cfg.tenantID, stationID, cutoff}
+	if c.cfg.maxHours > 0 {
+		query += "\nLIMIT $4"
+		args = append(args, c.cfg.maxHours)
+	}
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var hours []time.Time
+	for rows.Next() {
+		var hour time.Time
+		if err := rows.Scan(&hour); err != nil {
+			return nil, err
+		}
+		hours = append(hours, hour.UTC())
+	}
+	return hours, rows.Err()
+}
+
+// compactStation compacts every compactable hour of a station and returns
+// the hours, buckets and samples it covered.
+func (c *compactor) compactStation(ctx context.Context, stationID string, cutoff time.Time) (int, int, int, error) {
+	hours, err := c.compactableHours(ctx, stationID, cutoff)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("list hours: %w", err)
+	}
+	var bucketCount, sampleCount int
+	for i, hour := range hours {
+		points, err := c.query.QueryHour(ctx, c.cfg.tenantID, stationID, hour, hour.Add(time.Hour))
+		if err != nil {
+			return i, bucketCount, sampleCount, fmt.Errorf("query %s: %w", hour.Format(time.RFC3339), err)
+		}
+		buckets := bucketPoints(points, c.cfg.resolution)
+		for _, b := range buckets {
+			sampleCount += b.Count
+		}
+		bucketCount += len(buckets)
+		if c.cfg.dryRun {
+			continue
+		}
+		if err := c.writeHour(ctx, stationID, hour, buckets); err != nil {
+			return i, bucketCount, sampleCount, fmt.Errorf("write %s: %w", hour.Format(time.RFC3339), err)
+		}
+	}
+	return len(hours), bucketCount, sampleCount, nil
+}
+
+// writeHour upserts the buckets of one hour and deletes its numeric raw rows
+// in the same transaction, so a failure leaves the raw rows in place.
+func (c *compactor) writeHour(ctx context.Context, stationID string, hour time.Time, buckets []bucket) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	resolution := int(c.cfg.resolution / time.Second)
+	for _, b := range buckets {
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO telemetry_points_compacted (
+	tenant_id, station_id, point_key, bucket_start, resolution_seconds,
+	avg_value, min_value, max_value, last_value, sample_count
+) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+ON CONFLICT (tenant_id, station_id, point_key, resolution_seconds, bucket_start)
+DO UPDATE SET
+	avg_value = EXCLUDED.avg_value,
+	min_value = EXCLUDED.min_value,
+	max_value = EXCLUDED.max_value,
+	last_value = EXCLUDED.last_value,
+	sample_count = EXCLUDED.sample_count`,
+			c.cfg.tenantID, stationID, b.PointKey, b.Start, resolution,
+			b.Avg, b.Min, b.Max, b.Last, b.Count); err != nil {
+			return err
+		}
+	}
+	if !c.cfg.keepRaw {
+		if _, err := tx.ExecContext(ctx, `
+DELETE FROM telemetry_points
+WHERE tenant_id = $1 AND station_id = $2 AND ts >= $3 AND ts < $4 AND value_numeric IS NOT NULL`,
+			c.cfg.tenantID, stationID, hour, hour.Add(time.Hour)); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envOrInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envOrDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envOrBool(key string, fallback bool) bool {
+	switch strings.TrimSpace(strings.ToLower(os.Getenv(key))) {
+	case "1", "true", "yes", "y", "on":
+		return true
+	case "0", "false", "no", "n", "off":
+		return false
+	default:
+		return fallback
+	}
+}
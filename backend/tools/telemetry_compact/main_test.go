@@ -0,0 +1,56 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	telemetry "microgrid-cloud/internal/telemetry/domain"
+)
+
+func TestBucketPoints_AggregatesPerPointAndMinute(t *testing.T) {
+	hour := time.Date(2026, time.January, 5, 9, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return hour.Add(d) }
+	points := []telemetry.TelemetryPoint{
+		// Out of order on purpose: Last follows the timestamp, not the input order.
+		{At: at(50 * time.Second), Values: map[string]float64{"charge_power_kw": 6, "soc": 40}},
+		{At: at(10 * time.Second), Values: map[string]float64{"charge_power_kw": 2}},
+		{At: at(30 * time.Second), Values: map[string]float64{"charge_power_kw": 4}},
+		{At: at(61 * time.Second), Values: map[string]float64{"charge_power_kw": -1}},
+		{At: at(59*time.Minute + 59*time.Second), Values: map[string]float64{"soc": 55}},
+	}
+
+	got := bucketPoints(points, time.Minute)
+	want := []bucket{
+		{PointKey: "charge_power_kw", Start: hour, Avg: 4, Min: 2, Max: 6, Last: 6, Count: 3},
+		{PointKey: "charge_power_kw", Start: at(time.Minute), Avg: -1, Min: -1, Max: -1, Last: -1, Count: 1},
+		{PointKey: "soc", Start: hour, Avg: 40, Min: 40, Max: 40, Last: 40, Count: 1},
+		{PointKey: "soc", Start: at(59 * time.Minute), Avg: 55, Min: 55, Max: 55, Last: 55, Count: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("buckets = %+v, want %d", got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("bucket %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	// A coarser resolution folds the same samples into fewer buckets.
+	coarse := bucketPoints(points, 15*time.Minute)
+	if len(coarse) != 3 || coarse[0].Count != 4 || coarse[0].Avg != 2.75 || coarse[0].Last != -1 {
+		t.Fatalf("15m buckets = %+v", coarse)
+	}
+}
+
+func TestValidateResolution(t *testing.T) {
+	for _, ok := range []time.Duration{time.Second, time.Minute, 5 * time.Minute, time.Hour} {
+		if err := validateResolution(ok); err != nil {
+			t.Fatalf("%s rejected: %v", ok, err)
+		}
+	}
+	for _, bad := range []time.Duration{0, 500 * time.Millisecond, 7 * time.Minute, 2 * time.Hour} {
+		if err := validateResolution(bad); err == nil {
+			t.Fatalf("%s accepted", bad)
+		}
+	}
+}
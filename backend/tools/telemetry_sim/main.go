@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type config struct {
+	baseURL      string
+	ingestSecret string
+	tenantID     string
+	stationID    string
+	deviceID     string
+	points       string
+	interval     time.Duration
+	duration     time.Duration
+	count        int
+	quality      string
+	seed         int64
+	selfTest     bool
+	ndjson       bool
+}
+
+// generator produces a value for the n-th sample at elapsed seconds since start.
+type generator interface {
+	Value(n int, elapsed float64) float64
+}
+
+// rampGenerator climbs from Start by Step per sample and wraps back at Max.
+type rampGenerator struct {
+	Start float64
+	Max   float64
+	Step  float64
+}
+
+func (g rampGenerator) Value(n int, _ float64) float64 {
+	span := g.Max - g.Start
+	if span <= 0 || g.Step == 0 {
+		return g.Start
+	}
+	return g.Start + math.Mod(float64(n)*g.Step, span)
+}
+
+// sineGenerator oscillates around Offset with Amplitude over Period seconds.
+type sineGenerator struct {
+	Offset    float64
+	Amplitude float64
+	Period    float64
+}
+
+func (g sineGenerator) Value(_ int, elapsed float64) float64 {
+	if g.Period <= 0 {
+		return g.Offset
+	}
+	return g.Offset + g.Amplitude*math.Sin(2*math.Pi*elapsed/g.Period)
+}
+
+// randomGenerator draws uniformly from [Min, Max).
+type randomGenerator struct {
+	Min float64
+	Max float64
+	rnd *rand.Rand
+}
+
+func (g randomGenerator) Value(_ int, _ float64) float64 {
+	return g.Min + g.rnd.Float64()*(g.Max-g.Min)
+}
+
+// constGenerator always returns the same value.
+type constGenerator float64
+
+func (g constGenerator) Value(_ int, _ float64) float64 {
+	return float64(g)
+}
+
+// sampleSink delivers one generated sample.
+type sampleSink func(ctx context.Context, payload ingestPayload, at time.Time) error
+
+type ingestPayload struct {
+	TenantID  string             `json:"tenantId"`
+	StationID string             `json:"stationId"`
+	DeviceID  string             `json:"deviceId"`
+	TS        int64              `json:"ts"`
+	Values    map[string]float64 `json:"values"`
+	Quality   string             `json:"quality,omitempty"`
+}
+
+func main() {
+	cfg := parseConfig()
+	if cfg.selfTest {
+		if err := selfTest(); err != nil {
+			log.Fatalf("self-test failed: %v", err)
+		}
+		log.Printf("self-test ok")
+		return
+	}
+	if !cfg.ndjson && cfg.baseURL == "" {
+		log.Fatal("base-url is required")
+	}
+	if !cfg.ndjson && cfg.ingestSecret == "" {
+		log.Fatal("ingest-secret or INGEST_HMAC_SECRET is required")
+	}
+	if cfg.stationID == "" || cfg.deviceID == "" || cfg.tenantID == "" {
+		log.Fatal("tenant-id, station-id and device-id are required")
+	}
+	if cfg.interval <= 0 {
+		log.Fatal("interval must be > 0")
+	}
+	if cfg.duration <= 0 && cfg.count <= 0 {
+		log.Fatal("one of duration or count must be > 0")
+	}
+
+	generators, err := parseGenerators(cfg.points, rand.New(rand.NewSource(cfg.seed)))
+	if err != nil {
+		log.Fatalf("invalid points: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	sink := httpSink(&http.Client{Timeout: 10 * time.Second}, cfg.baseURL, cfg.ingestSecret)
+	if cfg.ndjson {
+		sink = ndjsonSink(os.Stdout)
+	}
+	sent, failed, err := run(ctx, cfg, generators, sink, time.Now)
+	log.Printf("telemetry sim finished: sent=%d failed=%d", sent, failed)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Fatalf("telemetry sim: %v", err)
+	}
+}
+
+func parseConfig() config {
+	cfg := config{}
+	flag.StringVar(&cfg.baseURL, "base-url", envOrDefault("BASE_URL", "http://localhost:8080"), "API base URL")
+	flag.StringVar(&cfg.ingestSecret, "ingest-secret", envOrDefault("INGEST_HMAC_SECRET", ""), "ingest HMAC secret")
+	flag.StringVar(&cfg.tenantID, "tenant-id", envOrDefault("TENANT_ID", "tenant-demo"), "tenant id")
+	flag.StringVar(&cfg.stationID, "station-id", envOrDefault("STATION_ID", "station-demo-001"), "station id")
+	flag.StringVar(&cfg.deviceID, "device-id", envOrDefault("DEVICE_ID", "device-sim-001"), "device id")
+	flag.StringVar(&cfg.points, "points", envOrDefault("SIM_POINTS", "charge_power_kw=sine:5:5:3600,discharge_power_kw=random:0:2"),
+		"comma separated point specs: key=ramp:start:max:step | key=sine:offset:amplitude:period_seconds | key=random:min:max | key=const:value")
+	flag.DurationVar(&cfg.interval, "interval", envOrDuration("SIM_INTERVAL", time.Second), "send interval")
+	flag.DurationVar(&cfg.duration, "duration", envOrDuration("SIM_DURATION", 0), "total run duration (0 = use count)")
+	flag.IntVar(&cfg.count, "count", envOrInt("SIM_COUNT", 0), "number of samples to send (0 = use duration)")
+	flag.StringVar(&cfg.quality, "quality", envOrDefault("SIM_QUALITY", "good"), "quality flag attached to samples")
+	flag.Int64Var(&cfg.seed, "seed", int64(envOrInt("SIM_SEED", 1)), "random seed for reproducible runs")
+	flag.BoolVar(&cfg.selfTest, "self-test", false, "run an in-process self-test and exit")
+	flag.BoolVar(&cfg.ndjson, "ndjson", false, "print each sample to stdout as one JSON object per line instead of posting it")
+	flag.Parse()
+	return cfg
+}
+
+func parseGenerators(spec string, rnd *rand.Rand) (map[string]generator, error) {
+	result := make(map[string]generator)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, def, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("point %q: expected key=kind:args", item)
+		}
+		parts := strings.Split(def, ":")
+		args := make([]float64, 0, len(parts)-1)
+		for _, raw := range parts[1:] {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return nil, fmt.Errorf("point %q: invalid number %q", item, raw)
+			}
+			args = append(args, value)
+		}
+		var gen generator
+		switch parts[0] {
+		case "ramp":
+			if len(args) != 3 {
+				return nil, fmt.Errorf("point %q: ramp needs start:max:step", item)
+			}
+			gen = rampGenerator{Start: args[0], Max: args[1], Step: args[2]}
+		case "sine":
+			if len(args) != 3 {
+				return nil, fmt.Errorf("point %q: sine needs offset:amplitude:period_seconds", item)
+			}
+			gen = sineGenerator{Offset: args[0], Amplitude: args[1], Period: args[2]}
+		case "random":
+			if len(args) != 2 || args[1] < args[0] {
+				return nil, fmt.Errorf("point %q: random needs min:max with min <= max", item)
+			}
+			gen = randomGenerator{Min: args[0], Max: args[1], rnd: rnd}
+		case "const":
+			if len(args) != 1 {
+				return nil, fmt.Errorf("point %q: const needs value", item)
+			}
+			gen = constGenerator(args[0])
+		default:
+			return nil, fmt.Errorf("point %q: unknown kind %q", item, parts[0])
+		}
+		result[strings.TrimSpace(key)] = gen
+	}
+	if len(result) == 0 {
+		return nil, errors.New("no points configured")
+	}
+	return result, nil
+}
+
+func run(ctx context.Context, cfg config, generators map[string]generator, sink sampleSink, now func() time.Time) (int, int, error) {
+	start := now()
+	var deadline time.Time
+	if cfg.duration > 0 {
+		deadline = start.Add(cfg.duration)
+	}
+	keys := make([]string, 0, len(generators))
+	for key := range generators {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	ticker := time.NewTicker(cfg.interval)
+	defer ticker.Stop()
+
+	sent, failed := 0, 0
+	for n := 0; ; n++ {
+		if cfg.count > 0 && n >= cfg.count {
+			return sent, failed, nil
+		}
+		at := now()
+		if !deadline.IsZero() && !at.Before(deadline) {
+			return sent, failed, nil
+		}
+		payload := ingestPayload{
+			TenantID:  cfg.tenantID,
+			StationID: cfg.stationID,
+			DeviceID:  cfg.deviceID,
+			TS:        at.UnixMilli(),
+			Values:    make(map[string]float64, len(keys)),
+			Quality:   cfg.quality,
+		}
+		elapsed := at.Sub(start).Seconds()
+		for _, key := range keys {
+			payload.Values[key] = generators[key].Value(n, elapsed)
+		}
+		if err := sink(ctx, payload, at); err != nil {
+			if ctx.Err() != nil {
+				return sent, failed, ctx.Err()
+			}
+			failed++
+			log.Printf("send sample %d: %v", n, err)
+		} else {
+			sent++
+		}
+
+		if cfg.count > 0 && n+1 >= cfg.count {
+			return sent, failed, nil
+		}
+		select {
+		case <-ctx.Done():
+			return sent, failed, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// httpSink posts samples to the signed ingest endpoint.
+func httpSink(client *http.Client, baseURL, secret string) sampleSink {
+	return func(ctx context.Context, payload ingestPayload, at time.Time) error {
+		return send(ctx, client, baseURL, secret, payload, at)
+	}
+}
+
+// ndjsonSink writes each sample as a standalone JSON line, so the output can be
+// piped into other tools and processed as it streams.
+func ndjsonSink(w io.Writer) sampleSink {
+	encoder := json.NewEncoder(w)
+	return func(_ context.Context, payload ingestPayload, _ time.Time) error {
+		return encoder.Encode(payload)
+	}
+}
+
+func send(ctx context.Context, client *http.Client, baseURL, secret string, payload ingestPayload, at time.Time) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	url := strings.TrimRight(baseURL, "/") + "/ingest/thingsboard/telemetry"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Ingest-Timestamp", timestamp)
+	req.Header.Set("X-Ingest-Signature", sign(secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// sign mirrors the platform ingest signature: hex(HMAC-SHA256(secret, timestamp + "\n" + body)).
+func sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("\n"))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// selfTest sends a few samples to an in-process server that verifies signatures and payloads.
+func selfTest() error {
+	const secret = "self-test-secret"
+	received := make(chan ingestPayload, 8)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if sign(secret, r.Header.Get("X-Ingest-Timestamp"), body) != r.Header.Get("X-Ingest-Signature") {
+			http.Error(w, "invalid ingest signature", http.StatusUnauthorized)
+			return
+		}
+		var payload ingestPayload
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, "invalid json", http.StatusBadRequest)
+			return
+		}
+		received <- payload
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	generators, err := parseGenerators("p_ramp=ramp:0:10:4,p_const=const:7,p_rand=random:1:2,p_sine=sine:0:1:60", rand.New(rand.NewSource(1)))
+	if err != nil {
+		return err
+	}
+	cfg := config{
+		baseURL:      server.URL,
+		ingestSecret: secret,
+		tenantID:     "tenant-sim",
+		stationID:    "station-sim",
+		deviceID:     "device-sim",
+		interval:     time.Millisecond,
+		count:        3,
+	}
+	sent, failed, err := run(context.Background(), cfg, generators, httpSink(server.Client(), cfg.baseURL, cfg.ingestSecret), time.Now)
+	if err != nil {
+		return err
+	}
+	if sent != 3 || failed != 0 {
+		return fmt.Errorf("expected 3 sent and 0 failed, got sent=%d failed=%d", sent, failed)
+	}
+	close(received)
+	wantRamp := []float64{0, 4, 8}
+	i := 0
+	for payload := range received {
+		if payload.StationID != "station-sim" || len(payload.Values) != 4 {
+			return fmt.Errorf("unexpected payload: %+v", payload)
+		}
+		if payload.Values["p_ramp"] != wantRamp[i] {
+			return fmt.Errorf("ramp sample %d: want %v got %v", i, wantRamp[i], payload.Values["p_ramp"])
+		}
+		if payload.Values["p_const"] != 7 {
+			return fmt.Errorf("const sample %d: got %v", i, payload.Values["p_const"])
+		}
+		if v := payload.Values["p_rand"]; v < 1 || v >= 2 {
+			return fmt.Errorf("random sample %d out of range: %v", i, v)
+		}
+		i++
+	}
+	return nil
+}
+
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func envOrInt(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envOrDuration(key string, fallback time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
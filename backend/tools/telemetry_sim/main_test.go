@@ -0,0 +1,46 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestSelfTest(t *testing.T) {
+	if err := selfTest(); err != nil {
+		t.Fatalf("self-test: %v", err)
+	}
+}
+
+func TestRun_NDJSONWritesOneObjectPerLine(t *testing.T) {
+	generators, err := parseGenerators("p_ramp=ramp:0:10:4,p_const=const:7", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("generators: %v", err)
+	}
+	cfg := config{tenantID: "tenant-sim", stationID: "station-sim", deviceID: "device-sim", interval: time.Millisecond, count: 3}
+	var out bytes.Buffer
+	sent, failed, err := run(context.Background(), cfg, generators, ndjsonSink(&out), time.Now)
+	if err != nil || sent != 3 || failed != 0 {
+		t.Fatalf("run: sent=%d failed=%d err=%v", sent, failed, err)
+	}
+
+	scanner := bufio.NewScanner(&out)
+	lines := 0
+	for scanner.Scan() {
+		var payload ingestPayload
+		if err := json.Unmarshal(scanner.Bytes(), &payload); err != nil {
+			t.Fatalf("line %d %q: %v", lines, scanner.Text(), err)
+		}
+		if payload.StationID != "station-sim" || payload.Values["p_ramp"] != float64(lines*4) || payload.Values["p_const"] != 7 {
+			t.Fatalf("line %d payload = %+v", lines, payload)
+		}
+		lines++
+	}
+	if lines != 3 {
+		t.Fatalf("lines = %d, want 3", lines)
+	}
+}